@@ -24,6 +24,7 @@ import (
 	"github.com/echovault/sugardb/internal/clock"
 	"log"
 	"sync"
+	"sync/atomic"
 )
 
 type Engine struct {
@@ -43,6 +44,16 @@ type Engine struct {
 	getStateFunc      func() map[int]map[string]internal.KeyData
 	setKeyDataFunc    func(database int, key string, data internal.KeyData)
 	handleCommand     func(database int, command []byte)
+
+	lastRewriteTime   atomic.Int64 // Unix time in seconds of the last successful rewrite.
+	lastRewriteFailed atomic.Bool  // Whether the last rewrite failed.
+}
+
+// Stats holds the statistics of the AOF engine reported by the INFO command.
+type Stats struct {
+	SyncStrategy      string
+	LastRewriteTime   int64 // Unix time in seconds of the last successful rewrite, 0 if the log was never rewritten.
+	LastRewriteFailed bool
 }
 
 func WithClock(clock clock.Clock) func(engine *Engine) {
@@ -167,6 +178,15 @@ func (engine *Engine) RewriteLog() error {
 	engine.startRewriteFunc()
 	defer engine.finishRewriteFunc()
 
+	err := engine.rewriteLog()
+	engine.lastRewriteFailed.Store(err != nil)
+	if err == nil {
+		engine.lastRewriteTime.Store(engine.clock.Now().Unix())
+	}
+	return err
+}
+
+func (engine *Engine) rewriteLog() error {
 	// Create AOF preamble.
 	if err := engine.preambleStore.CreatePreamble(); err != nil {
 		return fmt.Errorf("rewrite log error: create preamble error: %+v", err)
@@ -180,6 +200,19 @@ func (engine *Engine) RewriteLog() error {
 	return nil
 }
 
+// SetStrategy changes the sync strategy of the append-only log.
+func (engine *Engine) SetStrategy(strategy string) {
+	engine.appendStore.SetStrategy(strategy)
+}
+
+func (engine *Engine) Stats() Stats {
+	return Stats{
+		SyncStrategy:      engine.appendStore.Strategy(),
+		LastRewriteTime:   engine.lastRewriteTime.Load(),
+		LastRewriteFailed: engine.lastRewriteFailed.Load(),
+	}
+}
+
 func (engine *Engine) Restore() error {
 	if err := engine.preambleStore.Restore(); err != nil {
 		return fmt.Errorf("restore aof error: restore preamble error: %+v", err)
@@ -112,25 +112,25 @@ func NewAppendStore(options ...func(store *Store)) (*Store, error) {
 	}
 
 	// Start another goroutine that takes handles syncing the content to the file system.
-	// No need to start this goroutine if sync strategy is anything other than 'everysec'.
-	if strings.EqualFold(store.strategy, "everysec") {
-		go func() {
-			ticker := time.NewTicker(1 * time.Second)
-			defer func() {
-				ticker.Stop()
-			}()
-			for {
-				store.mut.Lock()
+	// The content is only synced by this goroutine while the sync strategy is 'everysec'.
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer func() {
+			ticker.Stop()
+		}()
+		for {
+			store.mut.Lock()
+			if strings.EqualFold(store.strategy, "everysec") {
 				if err := store.Sync(); err != nil {
 					store.mut.Unlock()
 					log.Println(fmt.Errorf("new append store error: %+v", err))
 					break
 				}
-				store.mut.Unlock()
-				<-ticker.C
 			}
-		}()
-	}
+			store.mut.Unlock()
+			<-ticker.C
+		}
+	}()
 
 	return store, nil
 }
@@ -168,6 +168,20 @@ func (store *Store) Write(database int, command []byte) error {
 	return nil
 }
 
+// SetStrategy changes the sync strategy of the append file. It can only be "always", "everysec", or "no".
+func (store *Store) SetStrategy(strategy string) {
+	store.mut.Lock()
+	defer store.mut.Unlock()
+	store.strategy = strings.ToLower(strategy)
+}
+
+// Strategy returns the current sync strategy of the append file.
+func (store *Store) Strategy() string {
+	store.mut.Lock()
+	defer store.mut.Unlock()
+	return store.strategy
+}
+
 func (store *Store) Sync() error {
 	if store.rw != nil {
 		return store.rw.Sync()
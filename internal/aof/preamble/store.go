@@ -15,10 +15,10 @@
 package preamble
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/clock"
+	"github.com/echovault/sugardb/internal/codec"
 	"io"
 	"os"
 	"path"
@@ -110,7 +110,7 @@ func (store *Store) CreatePreamble() error {
 
 	// Get current state.
 	state := internal.FilterExpiredKeys(store.clock.Now(), store.getStateFunc())
-	o, err := json.Marshal(state)
+	o, err := codec.EncodeState(state)
 	if err != nil {
 		return err
 	}
@@ -155,8 +155,8 @@ func (store *Store) Restore() error {
 		return nil
 	}
 
-	state := make(map[int]map[string]internal.KeyData)
-	if err = json.Unmarshal(b, &state); err != nil {
+	state, err := codec.DecodeState(b)
+	if err != nil {
 		return err
 	}
 
@@ -0,0 +1,151 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrUnexpectedEOF is returned by BinaryReader when the data ends before the value being read.
+var ErrUnexpectedEOF = errors.New("unexpected end of binary data")
+
+// AppendBinaryString appends the length of the string followed by its bytes.
+func AppendBinaryString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// AppendBinaryFloat appends the IEEE 754 representation of the float, so that it round-trips exactly.
+func AppendBinaryFloat(b []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+// AppendBinaryTime appends the time with nanosecond precision. The zero time, used for "no expiry", is
+// encoded as a single byte.
+func AppendBinaryTime(b []byte, t time.Time) []byte {
+	if t.IsZero() {
+		return append(b, 0)
+	}
+	b = append(b, 1)
+	return binary.AppendVarint(b, t.UnixNano())
+}
+
+// BinaryReader reads the values written by the AppendBinary* functions and encoding/binary's varint functions.
+// The first error is kept and every subsequent read returns a zero value, so callers can check Err once
+// after reading a whole structure.
+type BinaryReader struct {
+	b   []byte
+	err error
+}
+
+func NewBinaryReader(b []byte) *BinaryReader {
+	return &BinaryReader{b: b}
+}
+
+// Err returns the first error encountered while reading.
+func (r *BinaryReader) Err() error {
+	return r.err
+}
+
+// Len returns the number of unread bytes.
+func (r *BinaryReader) Len() int {
+	return len(r.b)
+}
+
+func (r *BinaryReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+	r.b = nil
+}
+
+func (r *BinaryReader) Byte() byte {
+	if len(r.b) < 1 {
+		r.fail(ErrUnexpectedEOF)
+		return 0
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c
+}
+
+// Bytes reads the next n bytes. The returned slice aliases the reader's data.
+func (r *BinaryReader) Bytes(n int) []byte {
+	if n < 0 || len(r.b) < n {
+		r.fail(ErrUnexpectedEOF)
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *BinaryReader) Uvarint() uint64 {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.fail(ErrUnexpectedEOF)
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *BinaryReader) Varint() int64 {
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		r.fail(ErrUnexpectedEOF)
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+// Count reads a collection length. Every element takes at least one byte, so a length greater than the
+// number of unread bytes is rejected before the caller allocates for it.
+func (r *BinaryReader) Count() int {
+	n := r.Uvarint()
+	if n > uint64(len(r.b)) {
+		r.fail(ErrUnexpectedEOF)
+		return 0
+	}
+	return int(n)
+}
+
+func (r *BinaryReader) Float() float64 {
+	b := r.Bytes(8)
+	if b == nil {
+		return 0
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b))
+}
+
+func (r *BinaryReader) String() string {
+	n := r.Count()
+	return string(r.Bytes(n))
+}
+
+func (r *BinaryReader) Time() time.Time {
+	if r.Byte() == 0 {
+		return time.Time{}
+	}
+	nsec := r.Varint()
+	if r.err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nsec)
+}
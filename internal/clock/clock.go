@@ -39,3 +39,14 @@ func (MockClock) Now() time.Time {
 func (MockClock) After(d time.Duration) <-chan time.Time {
 	return time.After(d)
 }
+
+// FixedClock is a clock whose Now method always returns the same time.
+// Commands applied from the raft log use it so that every node executes them at the time they were proposed.
+type FixedClock struct {
+	Clock
+	Time time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
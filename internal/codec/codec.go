@@ -0,0 +1,412 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codec implements the binary format used to persist the keyspace in snapshots and the AOF preamble,
+// and to serialize single keys for DUMP and RESTORE.
+//
+// An encoded state or dump starts with the magic string "SUGARDB" followed by the format version, and ends with the
+// CRC-64 (ECMA) checksum of everything before it. Every value is written with a type tag so that it's restored
+// with exactly the type it was stored with. Data written in the older JSON format is still accepted on decode.
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc64"
+	"slices"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/hyperloglog"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+	"github.com/echovault/sugardb/internal/modules/list"
+	"github.com/echovault/sugardb/internal/modules/probabilistic"
+	"github.com/echovault/sugardb/internal/modules/set"
+	"github.com/echovault/sugardb/internal/modules/sorted_set"
+	"github.com/echovault/sugardb/internal/modules/stream"
+	"github.com/echovault/sugardb/internal/modules/timeseries"
+	"github.com/echovault/sugardb/internal/modules/vector_set"
+)
+
+// Version is the version of the binary format written by this package.
+const Version byte = 1
+
+var magic = []byte("SUGARDB")
+
+var crcTable = crc64.MakeTable(crc64.ECMA)
+
+var (
+	ErrChecksum           = errors.New("checksum mismatch")
+	ErrUnsupportedVersion = errors.New("unsupported format version")
+)
+
+// Type tags written before each value.
+const (
+	TypeString byte = iota
+	TypeInteger
+	TypeFloat
+	TypeInt64
+	TypeList
+	TypeHash
+	TypeSet
+	TypeSortedSet
+	TypeStream
+	TypeHyperLogLog
+	TypeBloomFilter
+	TypeCuckooFilter
+	TypeCountMinSketch
+	TypeTopK
+	TypeJSON
+	TypeTimeSeries
+	TypeVectorSet
+)
+
+// EncodeSnapshot encodes the snapshot object. Databases and keys are written in sorted order, so the same
+// state always produces the same bytes.
+func EncodeSnapshot(snapshot internal.SnapshotObject) ([]byte, error) {
+	b := append(slices.Clone(magic), Version)
+	b = binary.AppendVarint(b, snapshot.LatestSnapshotMilliseconds)
+
+	databases := make([]int, 0, len(snapshot.State))
+	for database := range snapshot.State {
+		databases = append(databases, database)
+	}
+	slices.Sort(databases)
+
+	b = binary.AppendUvarint(b, uint64(len(databases)))
+	for _, database := range databases {
+		data := snapshot.State[database]
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		b = binary.AppendVarint(b, int64(database))
+		b = binary.AppendUvarint(b, uint64(len(keys)))
+		for _, key := range keys {
+			var err error
+			b = internal.AppendBinaryString(b, key)
+			b = internal.AppendBinaryTime(b, data[key].ExpireAt)
+			if b, err = AppendValue(b, data[key].Value); err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+		}
+	}
+
+	return binary.BigEndian.AppendUint64(b, crc64.Checksum(b, crcTable)), nil
+}
+
+// DecodeSnapshot decodes a snapshot object written by EncodeSnapshot or marshalled as JSON by older versions.
+func DecodeSnapshot(b []byte) (internal.SnapshotObject, error) {
+	if !bytes.HasPrefix(b, magic) {
+		snapshot := internal.SnapshotObject{}
+		if err := json.Unmarshal(b, &snapshot); err != nil {
+			return internal.SnapshotObject{}, err
+		}
+		snapshot.State = normalizeLegacyState(snapshot.State)
+		return snapshot, nil
+	}
+
+	r, err := newReader(b)
+	if err != nil {
+		return internal.SnapshotObject{}, err
+	}
+
+	snapshot := internal.SnapshotObject{
+		LatestSnapshotMilliseconds: r.Varint(),
+		State:                      make(map[int]map[string]internal.KeyData),
+	}
+
+	databases := r.Count()
+	for i := 0; i < databases && r.Err() == nil; i++ {
+		database := int(r.Varint())
+		keys := r.Count()
+		data := make(map[string]internal.KeyData, keys)
+		for j := 0; j < keys && r.Err() == nil; j++ {
+			key := r.String()
+			expireAt := r.Time()
+			value, err := ReadValue(r)
+			if err != nil {
+				return internal.SnapshotObject{}, fmt.Errorf("key %s: %w", key, err)
+			}
+			data[key] = internal.KeyData{Value: value, ExpireAt: expireAt}
+		}
+		snapshot.State[database] = data
+	}
+
+	if r.Err() != nil {
+		return internal.SnapshotObject{}, r.Err()
+	}
+	if r.Len() != 0 {
+		return internal.SnapshotObject{}, fmt.Errorf("%d trailing bytes after state", r.Len())
+	}
+
+	return snapshot, nil
+}
+
+// EncodeState encodes the state of all the databases.
+func EncodeState(state map[int]map[string]internal.KeyData) ([]byte, error) {
+	return EncodeSnapshot(internal.SnapshotObject{State: state})
+}
+
+// DecodeState decodes a state written by EncodeState or marshalled as JSON by older versions.
+func DecodeState(b []byte) (map[int]map[string]internal.KeyData, error) {
+	if !bytes.HasPrefix(b, magic) {
+		state := make(map[int]map[string]internal.KeyData)
+		if err := json.Unmarshal(b, &state); err != nil {
+			return nil, err
+		}
+		return normalizeLegacyState(state), nil
+	}
+	snapshot, err := DecodeSnapshot(b)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.State, nil
+}
+
+// EncodeDump encodes the value and expiry time of a single key. The payload has the same header and checksum
+// as an encoded state, so a payload written by another version or corrupted in transit is rejected on decode.
+func EncodeDump(data internal.KeyData) ([]byte, error) {
+	b := append(slices.Clone(magic), Version)
+	b = internal.AppendBinaryTime(b, data.ExpireAt)
+	b, err := AppendValue(b, data.Value)
+	if err != nil {
+		return nil, err
+	}
+	return binary.BigEndian.AppendUint64(b, crc64.Checksum(b, crcTable)), nil
+}
+
+// DecodeDump decodes a payload written by EncodeDump.
+func DecodeDump(b []byte) (internal.KeyData, error) {
+	if !bytes.HasPrefix(b, magic) {
+		return internal.KeyData{}, ErrUnsupportedVersion
+	}
+	r, err := newReader(b)
+	if err != nil {
+		return internal.KeyData{}, err
+	}
+	expireAt := r.Time()
+	value, err := ReadValue(r)
+	if err != nil {
+		return internal.KeyData{}, err
+	}
+	if r.Len() != 0 {
+		return internal.KeyData{}, fmt.Errorf("%d trailing bytes after value", r.Len())
+	}
+	return internal.KeyData{Value: value, ExpireAt: expireAt}, nil
+}
+
+// newReader verifies the version and checksum of b and returns a reader positioned after the header.
+func newReader(b []byte) (*internal.BinaryReader, error) {
+	if len(b) < len(magic)+1+8 {
+		return nil, internal.ErrUnexpectedEOF
+	}
+	if version := b[len(magic)]; version != Version {
+		return nil, fmt.Errorf("%w %d", ErrUnsupportedVersion, version)
+	}
+	body, sum := b[:len(b)-8], binary.BigEndian.Uint64(b[len(b)-8:])
+	if crc64.Checksum(body, crcTable) != sum {
+		return nil, ErrChecksum
+	}
+	return internal.NewBinaryReader(body[len(magic)+1:]), nil
+}
+
+// AppendValue appends the type tag of the value followed by its encoding.
+func AppendValue(b []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string, int, float64, int64:
+		return appendScalar(b, v)
+
+	case *list.List:
+		b = append(b, TypeList)
+		b = binary.AppendUvarint(b, uint64(v.Len()))
+		for _, elem := range v.Elements() {
+			b = internal.AppendBinaryString(b, elem)
+		}
+		return b, nil
+
+	case *hash.Hash:
+		fields := make([]string, 0, v.Len())
+		for field := range v.All() {
+			fields = append(fields, field)
+		}
+		slices.Sort(fields)
+		b = append(b, TypeHash)
+		b = binary.AppendUvarint(b, uint64(len(fields)))
+		for _, field := range fields {
+			var err error
+			b = internal.AppendBinaryString(b, field)
+			value := v.Get(field)
+			b = internal.AppendBinaryTime(b, value.ExpireAt)
+			if b, err = appendScalar(b, value.Value); err != nil {
+				return nil, fmt.Errorf("field %s: %w", field, err)
+			}
+		}
+		return b, nil
+
+	case *set.Set:
+		return appendMarshaler(b, TypeSet, v)
+	case *sorted_set.SortedSet:
+		return appendMarshaler(b, TypeSortedSet, v)
+	case *stream.Stream:
+		return appendMarshaler(b, TypeStream, v)
+	case *hyperloglog.HyperLogLog:
+		return appendMarshaler(b, TypeHyperLogLog, v)
+	case *probabilistic.BloomFilter:
+		return appendMarshaler(b, TypeBloomFilter, v)
+	case *probabilistic.CuckooFilter:
+		return appendMarshaler(b, TypeCuckooFilter, v)
+	case *probabilistic.CountMinSketch:
+		return appendMarshaler(b, TypeCountMinSketch, v)
+	case *probabilistic.TopK:
+		return appendMarshaler(b, TypeTopK, v)
+	case *jsondoc.Document:
+		return appendMarshaler(b, TypeJSON, v)
+	case *timeseries.TimeSeries:
+		return appendMarshaler(b, TypeTimeSeries, v)
+	case *vector_set.VectorSet:
+		return appendMarshaler(b, TypeVectorSet, v)
+	}
+
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+func appendScalar(b []byte, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return internal.AppendBinaryString(append(b, TypeString), v), nil
+	case int:
+		return binary.AppendVarint(append(b, TypeInteger), int64(v)), nil
+	case float64:
+		return internal.AppendBinaryFloat(append(b, TypeFloat), v), nil
+	case int64:
+		return binary.AppendVarint(append(b, TypeInt64), v), nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+func appendMarshaler(b []byte, tag byte, value interface{ MarshalBinary() ([]byte, error) }) ([]byte, error) {
+	data, err := value.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, tag)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...), nil
+}
+
+// ReadValue reads a value written by AppendValue.
+func ReadValue(r *internal.BinaryReader) (interface{}, error) {
+	tag := r.Byte()
+	switch tag {
+	case TypeString, TypeInteger, TypeFloat, TypeInt64:
+		return readScalar(r, tag)
+
+	case TypeList:
+		elements := make([]string, r.Count())
+		for i := range elements {
+			elements[i] = r.String()
+		}
+		return list.NewList(elements...), r.Err()
+
+	case TypeHash:
+		n := r.Count()
+		h := hash.NewHash(nil)
+		for i := 0; i < n && r.Err() == nil; i++ {
+			field := r.String()
+			expireAt := r.Time()
+			value, err := readScalar(r, r.Byte())
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field, err)
+			}
+			h.Set(field, hash.HashValue{Value: value, ExpireAt: expireAt})
+		}
+		return h, r.Err()
+
+	case TypeSet:
+		s := new(set.Set)
+		return s, readUnmarshaler(r, s)
+	case TypeSortedSet:
+		s := new(sorted_set.SortedSet)
+		return s, readUnmarshaler(r, s)
+	case TypeStream:
+		s := new(stream.Stream)
+		return s, readUnmarshaler(r, s)
+	case TypeHyperLogLog:
+		hll := new(hyperloglog.HyperLogLog)
+		return hll, readUnmarshaler(r, hll)
+	case TypeBloomFilter:
+		bf := new(probabilistic.BloomFilter)
+		return bf, readUnmarshaler(r, bf)
+	case TypeCuckooFilter:
+		cf := new(probabilistic.CuckooFilter)
+		return cf, readUnmarshaler(r, cf)
+	case TypeCountMinSketch:
+		cms := new(probabilistic.CountMinSketch)
+		return cms, readUnmarshaler(r, cms)
+	case TypeTopK:
+		topK := new(probabilistic.TopK)
+		return topK, readUnmarshaler(r, topK)
+	case TypeJSON:
+		doc := new(jsondoc.Document)
+		return doc, readUnmarshaler(r, doc)
+	case TypeTimeSeries:
+		ts := new(timeseries.TimeSeries)
+		return ts, readUnmarshaler(r, ts)
+	case TypeVectorSet:
+		vs := new(vector_set.VectorSet)
+		return vs, readUnmarshaler(r, vs)
+	}
+
+	if r.Err() != nil {
+		return nil, r.Err()
+	}
+	return nil, fmt.Errorf("unknown type tag %d", tag)
+}
+
+func readScalar(r *internal.BinaryReader, tag byte) (interface{}, error) {
+	var value interface{}
+	switch tag {
+	case TypeString:
+		value = r.String()
+	case TypeInteger:
+		value = int(r.Varint())
+	case TypeFloat:
+		value = r.Float()
+	case TypeInt64:
+		value = r.Varint()
+	default:
+		if r.Err() != nil {
+			return nil, r.Err()
+		}
+		return nil, fmt.Errorf("unknown scalar type tag %d", tag)
+	}
+	if r.Err() != nil {
+		return nil, r.Err()
+	}
+	return value, nil
+}
+
+func readUnmarshaler(r *internal.BinaryReader, value interface{ UnmarshalBinary([]byte) error }) error {
+	data := r.Bytes(r.Count())
+	if r.Err() != nil {
+		return r.Err()
+	}
+	return value.UnmarshalBinary(data)
+}
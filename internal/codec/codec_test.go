@@ -0,0 +1,504 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/clock"
+	"github.com/echovault/sugardb/internal/codec"
+	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/hyperloglog"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+	"github.com/echovault/sugardb/internal/modules/list"
+	"github.com/echovault/sugardb/internal/modules/probabilistic"
+	"github.com/echovault/sugardb/internal/modules/set"
+	"github.com/echovault/sugardb/internal/modules/sorted_set"
+	"github.com/echovault/sugardb/internal/modules/stream"
+	"github.com/echovault/sugardb/internal/modules/timeseries"
+	"github.com/echovault/sugardb/internal/modules/vector_set"
+)
+
+func newStream(t *testing.T, now time.Time) *stream.Stream {
+	s := stream.NewStream()
+	s.Add(stream.ID{Ms: 1, Seq: 0}, []string{"field1", "value1"})
+	s.Add(stream.ID{Ms: 1, Seq: 1}, []string{"field1", "value2", "field2", "value3"})
+	s.Add(stream.ID{Ms: 5, Seq: 0}, []string{"field3", "value4"})
+	if err := s.CreateGroup("group1", stream.MinID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateGroup("group2", stream.ID{Ms: 1, Seq: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ReadGroup("group1", "consumer1", ">", 2, false, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ReadGroup("group1", "consumer2", ">", 0, false, now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func valuesEqual(want, got interface{}) bool {
+	switch w := want.(type) {
+	case *hash.Hash:
+		g, ok := got.(*hash.Hash)
+		if !ok || w.Len() != g.Len() {
+			return false
+		}
+		for field, value := range w.All() {
+			if value.Value != g.Get(field).Value || !value.ExpireAt.Equal(g.Get(field).ExpireAt) {
+				return false
+			}
+		}
+		return true
+	case *list.List:
+		g, ok := got.(*list.List)
+		return ok && slices.Equal(w.Elements(), g.Elements())
+	case *set.Set:
+		g, ok := got.(*set.Set)
+		if !ok {
+			return false
+		}
+		wm, gm := w.GetAll(), g.GetAll()
+		slices.Sort(wm)
+		slices.Sort(gm)
+		return slices.Equal(wm, gm) && w.Cardinality() == g.Cardinality()
+	case *sorted_set.SortedSet:
+		g, ok := got.(*sorted_set.SortedSet)
+		if !ok || w.Cardinality() != g.Cardinality() {
+			return false
+		}
+		for _, member := range w.GetAll() {
+			if !g.Contains(member.Value) || g.Get(member.Value).Score != member.Score {
+				return false
+			}
+		}
+		return true
+	case *stream.Stream:
+		g, ok := got.(*stream.Stream)
+		if !ok || w.LastID() != g.LastID() {
+			return false
+		}
+		if !reflect.DeepEqual(w.Range(stream.MinID, stream.MaxID, 0), g.Range(stream.MinID, stream.MaxID, 0)) {
+			return false
+		}
+		// The encoding is deterministic, so equal consumer groups encode to the same bytes.
+		wb, _ := w.MarshalBinary()
+		gb, _ := g.MarshalBinary()
+		return reflect.DeepEqual(wb, gb)
+	case *hyperloglog.HyperLogLog:
+		g, ok := got.(*hyperloglog.HyperLogLog)
+		if !ok || w.IsDense() != g.IsDense() || w.Count() != g.Count() {
+			return false
+		}
+		wb, _ := w.MarshalBinary()
+		gb, _ := g.MarshalBinary()
+		return reflect.DeepEqual(wb, gb)
+	case interface{ MarshalBinary() ([]byte, error) }:
+		g, ok := got.(interface{ MarshalBinary() ([]byte, error) })
+		if !ok || reflect.TypeOf(want) != reflect.TypeOf(got) {
+			return false
+		}
+		wb, _ := w.MarshalBinary()
+		gb, _ := g.MarshalBinary()
+		return reflect.DeepEqual(wb, gb)
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+func newHyperLogLog(n int) *hyperloglog.HyperLogLog {
+	hll := hyperloglog.NewHyperLogLog()
+	for i := 0; i < n; i++ {
+		hll.Add([]string{fmt.Sprintf("element%d", i)})
+	}
+	return hll
+}
+
+func newProbabilistic(n int) (*probabilistic.BloomFilter, *probabilistic.CuckooFilter,
+	*probabilistic.CountMinSketch, *probabilistic.TopK) {
+	bf := probabilistic.NewBloomFilter(0.01, 10, 2)
+	cf := probabilistic.NewCuckooFilter(16, 2, 20, 1)
+	cms := probabilistic.NewCountMinSketch(100, 5)
+	topK := probabilistic.NewTopK(3, 8, 7, 0.9)
+	for i := 0; i < n; i++ {
+		item := fmt.Sprintf("item%d", i)
+		_, _ = bf.Add(item)
+		_ = cf.Add(item)
+		cms.IncrBy(item, uint64(i+1))
+		topK.IncrBy(item, uint64(i+1))
+	}
+	return bf, cf, cms, topK
+}
+
+func newDocument(data string) *jsondoc.Document {
+	doc := new(jsondoc.Document)
+	if err := doc.UnmarshalBinary([]byte(data)); err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+func newTimeSeries(t *testing.T, now time.Time) *timeseries.TimeSeries {
+	ts := timeseries.NewTimeSeries(
+		int64(time.Hour/time.Millisecond),
+		timeseries.DuplicateSum,
+		[]timeseries.Label{{Name: "sensor", Value: "1"}, {Name: "area", Value: "north"}},
+	)
+	ts.AddRule("compacted", "avg", 1000)
+	for i := int64(0); i < 10; i++ {
+		sample := timeseries.Sample{Timestamp: now.UnixMilli() - 5000 + i*700, Value: float64(i) * 1.5}
+		if _, err := ts.Add(sample, "", now.UnixMilli()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ts
+}
+
+func newVectorSet(t *testing.T) *vector_set.VectorSet {
+	vs := vector_set.NewVectorSet(3, vector_set.MetricL2, vector_set.IndexHNSW, 4, 20)
+	for i := 0; i < 40; i++ {
+		attributes, err := vector_set.ParseAttributes(fmt.Sprintf(`{"n":%d,"even":%t}`, i, i%2 == 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		vector := []float32{float32(i), float32(i%7) * 0.5, -float32(i%3) * 1.25}
+		if _, err = vs.Add(fmt.Sprintf("element%d", i), vector, attributes, i%5 != 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vs.Remove("element10")
+	return vs
+}
+
+func Test_Codec(t *testing.T) {
+	now := clock.NewClock().Now()
+	bf, cf, cms, topK := newProbabilistic(50)
+
+	state := map[int]map[string]internal.KeyData{
+		0: {
+			"string":   {Value: "value1", ExpireAt: now.Add(10 * time.Second)},
+			"empty":    {Value: "", ExpireAt: time.Time{}},
+			"integer":  {Value: -42, ExpireAt: time.Time{}},
+			"float":    {Value: 3.14159, ExpireAt: now.Add(time.Hour)},
+			"int64":    {Value: int64(1 << 40), ExpireAt: time.Time{}},
+			"list":     {Value: list.NewList("a", "b", "", "c"), ExpireAt: now.Add(time.Minute)},
+			"set":      {Value: set.NewSet([]string{"one", "two", "three"}), ExpireAt: time.Time{}},
+			"sorted":   {Value: sorted_set.NewSortedSet([]sorted_set.MemberParam{{Value: "a", Score: 1.5}, {Value: "b", Score: -2}}), ExpireAt: time.Time{}},
+			"stream":   {Value: newStream(t, now), ExpireAt: now.Add(5 * time.Minute)},
+			"emptyset": {Value: set.NewSet([]string{}), ExpireAt: time.Time{}},
+			"hll":      {Value: newHyperLogLog(10), ExpireAt: time.Time{}},
+			"densehll": {Value: newHyperLogLog(5000), ExpireAt: now.Add(time.Hour)},
+			"bloom":    {Value: bf, ExpireAt: time.Time{}},
+			"cuckoo":   {Value: cf, ExpireAt: now.Add(time.Minute)},
+			"cms":      {Value: cms, ExpireAt: time.Time{}},
+			"topk":     {Value: topK, ExpireAt: time.Time{}},
+			"json":     {Value: newDocument(`{"a":[1,2.5,"x",null],"b":{"c":true}}`), ExpireAt: time.Time{}},
+			"series":   {Value: newTimeSeries(t, now), ExpireAt: time.Time{}},
+			"vectors":  {Value: newVectorSet(t), ExpireAt: time.Time{}},
+		},
+		3: {
+			"hash": {
+				Value: hash.NewHash(map[string]hash.HashValue{
+					"field1": {Value: "value1", ExpireAt: now.Add(30 * time.Second)},
+					"field2": {Value: 7, ExpireAt: time.Time{}},
+					"field3": {Value: 2.5, ExpireAt: now.Add(time.Millisecond)},
+				}),
+				ExpireAt: now.Add(2 * time.Hour),
+			},
+		},
+	}
+
+	t.Run("Test round-trip of every value type", func(t *testing.T) {
+		b, err := codec.EncodeSnapshot(internal.SnapshotObject{State: state, LatestSnapshotMilliseconds: now.UnixMilli()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshot, err := codec.DecodeSnapshot(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot.LatestSnapshotMilliseconds != now.UnixMilli() {
+			t.Errorf("expected latest snapshot milliseconds %d, got %d",
+				now.UnixMilli(), snapshot.LatestSnapshotMilliseconds)
+		}
+		if len(snapshot.State) != len(state) {
+			t.Fatalf("expected %d databases, got %d", len(state), len(snapshot.State))
+		}
+		for database, data := range state {
+			if len(snapshot.State[database]) != len(data) {
+				t.Errorf("expected %d keys in database %d, got %d", len(data), database, len(snapshot.State[database]))
+			}
+			for key, want := range data {
+				got, ok := snapshot.State[database][key]
+				if !ok {
+					t.Errorf("expected key %s in database %d to be restored", key, database)
+					continue
+				}
+				if !want.ExpireAt.Equal(got.ExpireAt) {
+					t.Errorf("expected key %s to expire at %v, got %v", key, want.ExpireAt, got.ExpireAt)
+				}
+				if !valuesEqual(want.Value, got.Value) {
+					t.Errorf("expected key %s to have value %+v (%T), got %+v (%T)", key, want.Value, want.Value, got.Value, got.Value)
+				}
+			}
+		}
+	})
+
+	t.Run("Test encoding is deterministic", func(t *testing.T) {
+		b1, err := codec.EncodeState(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b2, err := codec.EncodeState(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(b1, b2) {
+			t.Error("expected encoding the same state twice to produce the same bytes")
+		}
+	})
+
+	t.Run("Test corrupted data is rejected", func(t *testing.T) {
+		b, err := codec.EncodeState(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b[len(b)/2] ^= 0xff
+		if _, err = codec.DecodeState(b); !errors.Is(err, codec.ErrChecksum) {
+			t.Errorf("expected checksum error, got %v", err)
+		}
+		if _, err = codec.DecodeState(b[:len(b)/2]); err == nil {
+			t.Error("expected error decoding truncated data")
+		}
+	})
+
+	t.Run("Test round-trip of single key dumps", func(t *testing.T) {
+		for database, data := range state {
+			for key, want := range data {
+				b, err := codec.EncodeDump(want)
+				if err != nil {
+					t.Fatalf("key %s: %v", key, err)
+				}
+				got, err := codec.DecodeDump(b)
+				if err != nil {
+					t.Fatalf("key %s: %v", key, err)
+				}
+				if !want.ExpireAt.Equal(got.ExpireAt) {
+					t.Errorf("expected key %s in database %d to expire at %v, got %v", key, database, want.ExpireAt, got.ExpireAt)
+				}
+				if !valuesEqual(want.Value, got.Value) {
+					t.Errorf("expected key %s to have value %+v (%T), got %+v (%T)", key, want.Value, want.Value, got.Value, got.Value)
+				}
+
+				b[len(b)-1] ^= 0xff
+				if _, err = codec.DecodeDump(b); !errors.Is(err, codec.ErrChecksum) {
+					t.Errorf("expected checksum error for key %s, got %v", key, err)
+				}
+			}
+		}
+		if _, err := codec.DecodeDump([]byte("not a dump")); err == nil {
+			t.Error("expected error decoding a payload without the header")
+		}
+	})
+
+	t.Run("Test unsupported value type is rejected", func(t *testing.T) {
+		_, err := codec.EncodeState(map[int]map[string]internal.KeyData{
+			0: {"key": {Value: struct{}{}}},
+		})
+		if err == nil {
+			t.Error("expected error encoding unsupported value type")
+		}
+	})
+}
+
+func Test_LegacyJSON(t *testing.T) {
+	expireAt := clock.NewClock().Now().Add(time.Hour)
+	legacy := map[int]map[string]internal.KeyData{
+		0: {
+			"string":  {Value: "value1", ExpireAt: expireAt},
+			"integer": {Value: 42},
+			"float":   {Value: 3.5},
+			"list":    {Value: []string{"a", "b"}},
+			// Legacy snapshots were written when hashes were stored as plain maps.
+			"hash": {Value: map[string]hash.HashValue{
+				"field1": {Value: "value1", ExpireAt: expireAt},
+				"field2": {Value: 10},
+			}},
+			"set": {Value: set.NewSet([]string{"a"})},
+		},
+	}
+	want := map[string]interface{}{
+		"string":  "value1",
+		"integer": 42,
+		"float":   3.5,
+		"list":    list.NewList("a", "b"),
+		"hash": hash.NewHash(map[string]hash.HashValue{
+			"field1": {Value: "value1", ExpireAt: expireAt},
+			"field2": {Value: 10},
+		}),
+	}
+
+	check := func(t *testing.T, state map[int]map[string]internal.KeyData) {
+		if len(state[0]) != len(want) {
+			t.Errorf("expected %d keys, got %d", len(want), len(state[0]))
+		}
+		for key, value := range want {
+			if !valuesEqual(value, state[0][key].Value) {
+				t.Errorf("expected key %s to have value %+v (%T), got %+v (%T)",
+					key, value, value, state[0][key].Value, state[0][key].Value)
+			}
+		}
+		if !state[0]["string"].ExpireAt.Equal(expireAt) {
+			t.Errorf("expected key string to expire at %v, got %v", expireAt, state[0]["string"].ExpireAt)
+		}
+	}
+
+	t.Run("Test legacy preamble", func(t *testing.T) {
+		b, err := json.Marshal(legacy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state, err := codec.DecodeState(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, state)
+	})
+
+	t.Run("Test legacy snapshot", func(t *testing.T) {
+		b, err := json.Marshal(internal.SnapshotObject{State: legacy, LatestSnapshotMilliseconds: 1234})
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshot, err := codec.DecodeSnapshot(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot.LatestSnapshotMilliseconds != 1234 {
+			t.Errorf("expected latest snapshot milliseconds 1234, got %d", snapshot.LatestSnapshotMilliseconds)
+		}
+		check(t, snapshot.State)
+	})
+}
+
+// restore encodes the value in a state like the one written to snapshots and the AOF preamble, and decodes it.
+func restore(t *testing.T, value interface{}) interface{} {
+	b, err := codec.EncodeState(map[int]map[string]internal.KeyData{0: {"key": {Value: value}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := codec.DecodeState(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state[0]["key"].Value
+}
+
+func Test_RestoredValues(t *testing.T) {
+	t.Run("Test restored HyperLogLogs keep their counts", func(t *testing.T) {
+		for _, n := range []int{10, 10000} {
+			hll := newHyperLogLog(n)
+			restored := restore(t, hll).(*hyperloglog.HyperLogLog)
+			if restored.IsDense() != hll.IsDense() || restored.Count() != hll.Count() {
+				t.Errorf("expected restored HyperLogLog of %d elements to count %d, got %d", n, hll.Count(), restored.Count())
+			}
+			hll.Add([]string{"new"})
+			restored.Add([]string{"new"})
+			if restored.Count() != hll.Count() {
+				t.Errorf("expected restored HyperLogLog to count %d after an add, got %d", hll.Count(), restored.Count())
+			}
+		}
+	})
+
+	t.Run("Test restored probabilistic types answer the same queries", func(t *testing.T) {
+		bf, cf, cms, topK := newProbabilistic(5)
+		restoredBF := restore(t, bf).(*probabilistic.BloomFilter)
+		restoredCF := restore(t, cf).(*probabilistic.CuckooFilter)
+		restoredCMS := restore(t, cms).(*probabilistic.CountMinSketch)
+		restoredTopK := restore(t, topK).(*probabilistic.TopK)
+		for i := 0; i < 5; i++ {
+			item := fmt.Sprintf("item%d", i)
+			if !restoredBF.Exists(item) {
+				t.Errorf("expected restored Bloom filter to contain %s", item)
+			}
+			if !restoredCF.Exists(item) {
+				t.Errorf("expected restored cuckoo filter to contain %s", item)
+			}
+			if got, want := restoredCMS.Query(item), cms.Query(item); got != want {
+				t.Errorf("expected restored count-min sketch to count %d for %s, got %d", want, item, got)
+			}
+		}
+		if !reflect.DeepEqual(restoredTopK.List(), topK.List()) {
+			t.Errorf("expected restored top-k list %v, got %v", topK.List(), restoredTopK.List())
+		}
+	})
+
+	t.Run("Test restored JSON documents keep their key order and numbers", func(t *testing.T) {
+		document := `{"b":{"c":[1,2.5,"x",null,12345678901234567890]},"a":true}`
+		if got := restore(t, newDocument(document)).(*jsondoc.Document).String(); got != document {
+			t.Errorf("expected restored document %s, got %s", document, got)
+		}
+	})
+
+	t.Run("Test restored time series keep the open buckets of their rules", func(t *testing.T) {
+		ts := timeseries.NewTimeSeries(0, timeseries.DuplicateBlock, []timeseries.Label{{Name: "sensor", Value: "1"}})
+		ts.AddRule("dest", "max", 1000)
+		for _, sample := range []timeseries.Sample{{Timestamp: 100, Value: 1}, {Timestamp: 900, Value: 2}} {
+			if _, err := ts.Add(sample, "", 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+		restored := restore(t, ts).(*timeseries.TimeSeries)
+
+		// The next bucket closes the open bucket, which must compact the samples added before the restore.
+		next := timeseries.Sample{Timestamp: 1100, Value: 3}
+		want, err := ts.Add(next, "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := restored.Add(next, "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(want) != 1 || !reflect.DeepEqual(got, want) {
+			t.Errorf("expected restored series to produce compactions %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("Test restored vector sets return the same similar elements", func(t *testing.T) {
+		vs := newVectorSet(t)
+		restored := restore(t, vs).(*vector_set.VectorSet)
+		query := []float32{12, 1, -1.25}
+		want, err := vs.Search(query, vector_set.SearchOptions{Count: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := restored.Search(query, vector_set.SearchOptions{Count: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(want) != 5 || !reflect.DeepEqual(got, want) {
+			t.Errorf("expected restored vector set to return %+v, got %+v", want, got)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/list"
+)
+
+// normalizeLegacyState converts the values of a state decoded from JSON back into the types used by the
+// keyspace. JSON loses the distinction between integers and floats, turns lists into []interface{} and hashes
+// into maps. Sets, sorted sets and streams were marshalled as empty objects, so they can't be recovered and
+// are dropped.
+func normalizeLegacyState(state map[int]map[string]internal.KeyData) map[int]map[string]internal.KeyData {
+	if state == nil {
+		return make(map[int]map[string]internal.KeyData)
+	}
+	for database, data := range state {
+		for key, keyData := range data {
+			value, ok := normalizeLegacyValue(keyData.Value)
+			if !ok {
+				log.Printf("skipping key %s in database %d: value can't be restored from legacy snapshot\n", key, database)
+				delete(data, key)
+				continue
+			}
+			keyData.Value = value
+			data[key] = keyData
+		}
+	}
+	return state
+}
+
+func normalizeLegacyValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+
+	case float64:
+		return normalizeLegacyNumber(v), true
+
+	case []interface{}:
+		elements := make([]string, len(v))
+		for i, elem := range v {
+			s, ok := elem.(string)
+			if !ok {
+				return nil, false
+			}
+			elements[i] = s
+		}
+		return list.NewList(elements...), true
+
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil, false
+		}
+		h := hash.NewHash(nil)
+		for field, fieldValue := range v {
+			obj, ok := fieldValue.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			hashValue := hash.HashValue{}
+			switch fv := obj["Value"].(type) {
+			case string:
+				hashValue.Value = fv
+			case float64:
+				hashValue.Value = normalizeLegacyNumber(fv)
+			default:
+				return nil, false
+			}
+			if s, ok := obj["ExpireAt"].(string); ok {
+				expireAt, err := time.Parse(time.RFC3339Nano, s)
+				if err != nil {
+					return nil, false
+				}
+				hashValue.ExpireAt = expireAt
+			}
+			h.Set(field, hashValue)
+		}
+		return h, true
+	}
+
+	return nil, false
+}
+
+// normalizeLegacyNumber returns whole numbers as int, which is how they're stored by the keyspace.
+func normalizeLegacyNumber(f float64) interface{} {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int(f)
+	}
+	return f
+}
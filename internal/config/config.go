@@ -22,7 +22,6 @@ import (
 	"log"
 	"os"
 	"path"
-	"slices"
 	"strings"
 	"time"
 
@@ -33,36 +32,38 @@ import (
 )
 
 type Config struct {
-	TLS               bool          `json:"TLS" yaml:"TLS"`
-	MTLS              bool          `json:"MTLS" yaml:"MTLS"`
-	CertKeyPairs      [][]string    `json:"CertKeyPairs" yaml:"CertKeyPairs"`
-	ClientCAs         []string      `json:"ClientCAs" yaml:"ClientCAs"`
-	Port              uint16        `json:"Port" yaml:"Port"`
-	ServerID          string        `json:"ServerId" yaml:"ServerId"`
-	JoinAddr          string        `json:"JoinAddr" yaml:"JoinAddr"`
-	BindAddr          string        `json:"BindAddr" yaml:"BindAddr"`
-	DataDir           string        `json:"DataDir" yaml:"DataDir"`
-	BootstrapCluster  bool          `json:"BootstrapCluster" yaml:"BootstrapCluster"`
-	AclConfig         string        `json:"AclConfig" yaml:"AclConfig"`
-	ForwardCommand    bool          `json:"ForwardCommand" yaml:"ForwardCommand"`
-	RequirePass       bool          `json:"RequirePass" yaml:"RequirePass"`
-	Password          string        `json:"Password" yaml:"Password"`
-	SnapShotThreshold uint64        `json:"SnapshotThreshold" yaml:"SnapshotThreshold"`
-	SnapshotInterval  time.Duration `json:"SnapshotInterval" yaml:"SnapshotInterval"`
-	RestoreSnapshot   bool          `json:"RestoreSnapshot" yaml:"RestoreSnapshot"`
-	RestoreAOF        bool          `json:"RestoreAOF" yaml:"RestoreAOF"`
-	AOFSyncStrategy   string        `json:"AOFSyncStrategy" yaml:"AOFSyncStrategy"`
-	MaxMemory         uint64        `json:"MaxMemory" yaml:"MaxMemory"`
-	EvictionPolicy    string        `json:"EvictionPolicy" yaml:"EvictionPolicy"`
-	EvictionSample    uint          `json:"EvictionSample" yaml:"EvictionSample"`
-	EvictionInterval  time.Duration `json:"EvictionInterval" yaml:"EvictionInterval"`
-	ElectionTimeout   time.Duration `json:"ElectionTimeout" yaml:"ElectionTimeout"`
-	HeartbeatTimeout  time.Duration `json:"HeartbeatTimeout" yaml:"HeartbeatTimeout"`
-	CommitTimeout     time.Duration `json:"CommitTimeout" yaml:"CommitTimeout"`
-	Modules           []string      `json:"Plugins" yaml:"Plugins"`
-	DiscoveryPort     uint16        `json:"DiscoveryPort" yaml:"DiscoveryPort"`
-	RaftBindAddr      string
-	RaftBindPort      uint16
+	TLS                  bool          `json:"TLS" yaml:"TLS"`
+	MTLS                 bool          `json:"MTLS" yaml:"MTLS"`
+	CertKeyPairs         [][]string    `json:"CertKeyPairs" yaml:"CertKeyPairs"`
+	ClientCAs            []string      `json:"ClientCAs" yaml:"ClientCAs"`
+	Port                 uint16        `json:"Port" yaml:"Port"`
+	ServerID             string        `json:"ServerId" yaml:"ServerId"`
+	JoinAddr             string        `json:"JoinAddr" yaml:"JoinAddr"`
+	BindAddr             string        `json:"BindAddr" yaml:"BindAddr"`
+	DataDir              string        `json:"DataDir" yaml:"DataDir"`
+	BootstrapCluster     bool          `json:"BootstrapCluster" yaml:"BootstrapCluster"`
+	AclConfig            string        `json:"AclConfig" yaml:"AclConfig"`
+	ForwardCommand       bool          `json:"ForwardCommand" yaml:"ForwardCommand"`
+	RequirePass          bool          `json:"RequirePass" yaml:"RequirePass"`
+	Password             string        `json:"Password" yaml:"Password"`
+	SnapShotThreshold    uint64        `json:"SnapshotThreshold" yaml:"SnapshotThreshold"`
+	SnapshotInterval     time.Duration `json:"SnapshotInterval" yaml:"SnapshotInterval"`
+	RestoreSnapshot      bool          `json:"RestoreSnapshot" yaml:"RestoreSnapshot"`
+	RestoreAOF           bool          `json:"RestoreAOF" yaml:"RestoreAOF"`
+	AOFSyncStrategy      string        `json:"AOFSyncStrategy" yaml:"AOFSyncStrategy"`
+	MaxMemory            uint64        `json:"MaxMemory" yaml:"MaxMemory"`
+	EvictionPolicy       string        `json:"EvictionPolicy" yaml:"EvictionPolicy"`
+	EvictionSample       uint          `json:"EvictionSample" yaml:"EvictionSample"`
+	EvictionInterval     time.Duration `json:"EvictionInterval" yaml:"EvictionInterval"`
+	ElectionTimeout      time.Duration `json:"ElectionTimeout" yaml:"ElectionTimeout"`
+	HeartbeatTimeout     time.Duration `json:"HeartbeatTimeout" yaml:"HeartbeatTimeout"`
+	CommitTimeout        time.Duration `json:"CommitTimeout" yaml:"CommitTimeout"`
+	Modules              []string      `json:"Plugins" yaml:"Plugins"`
+	DiscoveryPort        uint16        `json:"DiscoveryPort" yaml:"DiscoveryPort"`
+	NotifyKeyspaceEvents string        `json:"NotifyKeyspaceEvents" yaml:"NotifyKeyspaceEvents"`
+	RaftBindAddr         string
+	RaftBindPort         uint16
+	ConfigFile           string `json:"-" yaml:"-"` // The JSON or YAML file the config was loaded from, used by CONFIG REWRITE.
 }
 
 func GetConfig() (Config, error) {
@@ -92,12 +93,11 @@ func GetConfig() (Config, error) {
 	flag.Func("aof-sync-strategy", `How often to flush the file contents written to append only file.
 The options are 'always' for syncing on each command, 'everysec' to sync every second, and 'no' to leave it up to the os.`,
 		func(option string) error {
-			if !slices.ContainsFunc([]string{"always", "everysec", "no"}, func(s string) bool {
-				return strings.EqualFold(s, option)
-			}) {
-				return errors.New("aofSyncStrategy must be 'always', 'everysec' or 'no'")
+			strategy, err := parseAOFSyncStrategy(option)
+			if err != nil {
+				return err
 			}
-			aofSyncStrategy = strings.ToLower(option)
+			aofSyncStrategy = strategy
 			return nil
 		})
 
@@ -123,19 +123,26 @@ There is no limit by default.`, func(memory string) error {
 5) volatile-lru - Evict the least recently used keys with an expiration.
 6) allkeys-random - Evict random keys until we get under the max-memory limit.
 7) volatile-random - Evict random keys with an expiration.`, func(policy string) error {
-			policies := []string{
-				constants.NoEviction,
-				constants.AllKeysLFU, constants.AllKeysLRU, constants.AllKeysRandom,
-				constants.VolatileLFU, constants.VolatileLRU, constants.VolatileRandom,
+			p, err := parseEvictionPolicy(policy)
+			if err != nil {
+				return err
 			}
-			policyIdx := slices.Index(policies, strings.ToLower(policy))
-			if policyIdx == -1 {
-				return fmt.Errorf("policy %s is not a valid policy", policy)
-			}
-			evictionPolicy = strings.ToLower(policy)
+			evictionPolicy = p
 			return nil
 		})
 
+	notifyKeyspaceEvents := ""
+	flag.Func("notify-keyspace-events", `The classes of keyspace events published over pub/sub. Empty by default, which disables notifications.
+Publishing requires K (keyspace channels) and/or E (keyevent channels), combined with any of:
+g (generic), $ (string), l (list), s (set), h (hash), z (sorted set), t (stream), x (expired), e (evicted),
+n (new keys) or A (alias for "g$lshztxe").`, func(flags string) error {
+		if _, err := internal.ParseKeyspaceEvents(flags); err != nil {
+			return err
+		}
+		notifyKeyspaceEvents = flags
+		return nil
+	})
+
 	var modules []string
 	flag.Func(
 		"loadmodule",
@@ -201,36 +208,38 @@ It is a plain text value by default but you can provide a SHA256 hash by adding
 	}
 
 	conf := Config{
-		CertKeyPairs:      certKeyPairs,
-		ClientCAs:         clientCAs,
-		TLS:               *tls,
-		MTLS:              *mtls,
-		Port:              uint16(*port),
-		ServerID:          *serverId,
-		JoinAddr:          *joinAddr,
-		BindAddr:          *bindAddr,
-		DataDir:           *dataDir,
-		BootstrapCluster:  *bootstrapCluster,
-		AclConfig:         *aclConfig,
-		ForwardCommand:    *forwardCommand,
-		RequirePass:       *requirePass,
-		Password:          *password,
-		SnapShotThreshold: *snapshotThreshold,
-		SnapshotInterval:  *snapshotInterval,
-		RestoreSnapshot:   *restoreSnapshot,
-		RestoreAOF:        *restoreAOF,
-		AOFSyncStrategy:   aofSyncStrategy,
-		MaxMemory:         maxMemory,
-		EvictionPolicy:    evictionPolicy,
-		EvictionSample:    *evictionSample,
-		EvictionInterval:  *evictionInterval,
-		ElectionTimeout:   *electionTimeout,
-		HeartbeatTimeout:  *heartbeatTimeout,
-		CommitTimeout:     *commitTimeout,
-		Modules:           modules,
-		DiscoveryPort:     uint16(*discoveryPort),
-		RaftBindAddr:      raftBindAddr,
-		RaftBindPort:      uint16(raftBindPort),
+		CertKeyPairs:         certKeyPairs,
+		ClientCAs:            clientCAs,
+		TLS:                  *tls,
+		MTLS:                 *mtls,
+		Port:                 uint16(*port),
+		ServerID:             *serverId,
+		JoinAddr:             *joinAddr,
+		BindAddr:             *bindAddr,
+		DataDir:              *dataDir,
+		BootstrapCluster:     *bootstrapCluster,
+		AclConfig:            *aclConfig,
+		ForwardCommand:       *forwardCommand,
+		RequirePass:          *requirePass,
+		Password:             *password,
+		SnapShotThreshold:    *snapshotThreshold,
+		SnapshotInterval:     *snapshotInterval,
+		RestoreSnapshot:      *restoreSnapshot,
+		RestoreAOF:           *restoreAOF,
+		AOFSyncStrategy:      aofSyncStrategy,
+		MaxMemory:            maxMemory,
+		EvictionPolicy:       evictionPolicy,
+		EvictionSample:       *evictionSample,
+		EvictionInterval:     *evictionInterval,
+		ElectionTimeout:      *electionTimeout,
+		HeartbeatTimeout:     *heartbeatTimeout,
+		CommitTimeout:        *commitTimeout,
+		Modules:              modules,
+		DiscoveryPort:        uint16(*discoveryPort),
+		NotifyKeyspaceEvents: notifyKeyspaceEvents,
+		RaftBindAddr:         raftBindAddr,
+		RaftBindPort:         uint16(raftBindPort),
+		ConfigFile:           *config,
 	}
 
 	if len(*config) > 0 {
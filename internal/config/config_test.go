@@ -13,3 +13,169 @@
 // limitations under the License.
 
 package config
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func Test_Parameters(t *testing.T) {
+	t.Run("Test GetParameters", func(t *testing.T) {
+		conf := DefaultConfig()
+		conf.MaxMemory = 1024
+		got, err := GetParameters(conf, "eviction-*", "MAX-MEMORY")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := map[string]string{
+			"eviction-interval": "100ms",
+			"eviction-policy":   "noeviction",
+			"eviction-sample":   "20",
+			"max-memory":        "1024",
+		}
+		if len(got) != len(want) {
+			t.Errorf("expected %d parameters, got %d: %v", len(want), len(got), got)
+		}
+		for name, value := range want {
+			if got[name] != value {
+				t.Errorf("expected parameter %s to be %s, got %s", name, value, got[name])
+			}
+		}
+	})
+
+	t.Run("Test SetParameter", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			param   string
+			value   string
+			check   func(conf Config) bool
+			wantErr string
+		}{
+			{
+				name:  "1. Set max memory with unit",
+				param: "max-memory",
+				value: "2mb",
+				check: func(conf Config) bool { return conf.MaxMemory == 2*1024*1024 },
+			},
+			{
+				name:  "2. Set max memory in bytes",
+				param: "max-memory",
+				value: "100",
+				check: func(conf Config) bool { return conf.MaxMemory == 100 },
+			},
+			{
+				name:  "3. Set eviction policy",
+				param: "eviction-policy",
+				value: "ALLKEYS-LRU",
+				check: func(conf Config) bool { return conf.EvictionPolicy == "allkeys-lru" },
+			},
+			{
+				name:  "4. Set snapshot interval",
+				param: "snapshot-interval",
+				value: "10s",
+				check: func(conf Config) bool { return conf.SnapshotInterval == 10*time.Second },
+			},
+			{
+				name:    "5. Return error for invalid value",
+				param:   "aof-sync-strategy",
+				value:   "sometimes",
+				wantErr: "invalid value for parameter aof-sync-strategy",
+			},
+			{
+				name:    "6. Return error for parameter that cannot be changed at runtime",
+				param:   "port",
+				value:   "7000",
+				wantErr: "parameter port cannot be changed at runtime",
+			},
+			{
+				name:    "7. Return error for unknown parameter",
+				param:   "unknown",
+				value:   "value",
+				wantErr: "unknown parameter unknown",
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				conf := DefaultConfig()
+				err := SetParameter(&conf, test.param, test.value)
+				if test.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+						t.Errorf("expected error \"%s\", got %v", test.wantErr, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !test.check(conf) {
+					t.Errorf("parameter %s was not set to %s", test.param, test.value)
+				}
+			})
+		}
+	})
+
+	t.Run("Test Rewrite", func(t *testing.T) {
+		dir := t.TempDir()
+
+		conf := DefaultConfig()
+		conf.MaxMemory = 4096
+		conf.EvictionInterval = 5 * time.Second
+
+		// JSON config file.
+		conf.ConfigFile = path.Join(dir, "config.json")
+		if err := os.WriteFile(conf.ConfigFile, []byte(`{"Port": 7000, "MaxMemory": 0}`), 0644); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := Rewrite(conf); err != nil {
+			t.Error(err)
+			return
+		}
+		var jsonConf Config
+		b, _ := os.ReadFile(conf.ConfigFile)
+		if err := json.Unmarshal(b, &jsonConf); err != nil {
+			t.Error(err)
+			return
+		}
+		if jsonConf.Port != 7000 || jsonConf.MaxMemory != 4096 || jsonConf.EvictionInterval != 5*time.Second {
+			t.Errorf("unexpected rewritten JSON config: %s", string(b))
+		}
+
+		// YAML config file.
+		conf.ConfigFile = path.Join(dir, "config.yaml")
+		if err := os.WriteFile(conf.ConfigFile, []byte("# The port.\nPort: 7000\nMaxMemory: 0\n"), 0644); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := Rewrite(conf); err != nil {
+			t.Error(err)
+			return
+		}
+		var yamlConf Config
+		b, _ = os.ReadFile(conf.ConfigFile)
+		if err := yaml.Unmarshal(b, &yamlConf); err != nil {
+			t.Error(err)
+			return
+		}
+		if yamlConf.Port != 7000 || yamlConf.MaxMemory != 4096 || yamlConf.EvictionInterval != 5*time.Second {
+			t.Errorf("unexpected rewritten YAML config: %s", string(b))
+		}
+		if !strings.HasPrefix(string(b), "# The port.\nPort: 7000\nMaxMemory: 4096\n") {
+			t.Errorf("expected comments and order to be preserved, got: %s", string(b))
+		}
+
+		// No config file.
+		conf.ConfigFile = ""
+		if err := Rewrite(conf); err == nil {
+			t.Error("expected error when there's no config file")
+		}
+	})
+}
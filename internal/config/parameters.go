@@ -0,0 +1,334 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/gobwas/glob"
+	"gopkg.in/yaml.v3"
+)
+
+// parameter is a configuration parameter that can be read with CONFIG GET.
+// Parameters with a set function can also be changed at runtime with CONFIG SET.
+type parameter struct {
+	name  string // The name of the parameter, which is the same as its command line flag.
+	field string // The name of the Config field that holds the parameter.
+	set   func(conf *Config, value string) error
+}
+
+var parameters = []parameter{
+	{name: "acl-config", field: "AclConfig"},
+	{
+		name: "aof-sync-strategy", field: "AOFSyncStrategy",
+		set: func(conf *Config, value string) error {
+			strategy, err := parseAOFSyncStrategy(value)
+			conf.AOFSyncStrategy = strategy
+			return err
+		},
+	},
+	{name: "bind-addr", field: "BindAddr"},
+	{name: "bootstrap-cluster", field: "BootstrapCluster"},
+	{name: "commit-timeout", field: "CommitTimeout"},
+	{name: "data-dir", field: "DataDir"},
+	{name: "discovery-port", field: "DiscoveryPort"},
+	{name: "election-timeout", field: "ElectionTimeout"},
+	{
+		name: "eviction-interval", field: "EvictionInterval",
+		set: func(conf *Config, value string) error {
+			interval, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			if interval <= 0 {
+				return errors.New("eviction-interval must be greater than 0")
+			}
+			conf.EvictionInterval = interval
+			return nil
+		},
+	},
+	{
+		name: "eviction-policy", field: "EvictionPolicy",
+		set: func(conf *Config, value string) error {
+			policy, err := parseEvictionPolicy(value)
+			conf.EvictionPolicy = policy
+			return err
+		},
+	},
+	{
+		name: "eviction-sample", field: "EvictionSample",
+		set: func(conf *Config, value string) error {
+			sample, err := strconv.ParseUint(value, 10, 32)
+			conf.EvictionSample = uint(sample)
+			return err
+		},
+	},
+	{name: "forward-commands", field: "ForwardCommand"},
+	{name: "heartbeat-timeout", field: "HeartbeatTimeout"},
+	{name: "join-addr", field: "JoinAddr"},
+	{
+		name: "max-memory", field: "MaxMemory",
+		set: func(conf *Config, value string) error {
+			// The memory limit can be given in bytes or with a unit (e.g. 100mb).
+			if b, err := strconv.ParseUint(value, 10, 64); err == nil {
+				conf.MaxMemory = b
+				return nil
+			}
+			if len(value) < 2 {
+				return fmt.Errorf("invalid memory value %s", value)
+			}
+			b, err := internal.ParseMemory(value)
+			conf.MaxMemory = b
+			return err
+		},
+	},
+	{name: "mtls", field: "MTLS"},
+	{
+		name: "notify-keyspace-events", field: "NotifyKeyspaceEvents",
+		set: func(conf *Config, value string) error {
+			if _, err := internal.ParseKeyspaceEvents(value); err != nil {
+				return err
+			}
+			conf.NotifyKeyspaceEvents = value
+			return nil
+		},
+	},
+	{
+		name: "password", field: "Password",
+		set: func(conf *Config, value string) error {
+			conf.Password = value
+			return nil
+		},
+	},
+	{name: "port", field: "Port"},
+	{
+		name: "require-pass", field: "RequirePass",
+		set: func(conf *Config, value string) error {
+			requirePass, err := strconv.ParseBool(value)
+			conf.RequirePass = requirePass
+			return err
+		},
+	},
+	{name: "restore-aof", field: "RestoreAOF"},
+	{name: "restore-snapshot", field: "RestoreSnapshot"},
+	{name: "server-id", field: "ServerID"},
+	{
+		name: "snapshot-interval", field: "SnapshotInterval",
+		set: func(conf *Config, value string) error {
+			interval, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			if interval < 0 {
+				return errors.New("snapshot-interval cannot be negative")
+			}
+			conf.SnapshotInterval = interval
+			return nil
+		},
+	},
+	{
+		name: "snapshot-threshold", field: "SnapShotThreshold",
+		set: func(conf *Config, value string) error {
+			threshold, err := strconv.ParseUint(value, 10, 64)
+			conf.SnapShotThreshold = threshold
+			return err
+		},
+	},
+	{name: "tls", field: "TLS"},
+}
+
+func parseAOFSyncStrategy(option string) (string, error) {
+	if !slices.ContainsFunc([]string{"always", "everysec", "no"}, func(s string) bool {
+		return strings.EqualFold(s, option)
+	}) {
+		return "", errors.New("aofSyncStrategy must be 'always', 'everysec' or 'no'")
+	}
+	return strings.ToLower(option), nil
+}
+
+func parseEvictionPolicy(policy string) (string, error) {
+	policies := []string{
+		constants.NoEviction,
+		constants.AllKeysLFU, constants.AllKeysLRU, constants.AllKeysRandom,
+		constants.VolatileLFU, constants.VolatileLRU, constants.VolatileRandom,
+	}
+	policyIdx := slices.Index(policies, strings.ToLower(policy))
+	if policyIdx == -1 {
+		return "", fmt.Errorf("policy %s is not a valid policy", policy)
+	}
+	return strings.ToLower(policy), nil
+}
+
+// GetParameters returns the values of the configuration parameters whose names match any of the glob patterns.
+func GetParameters(conf Config, patterns ...string) (map[string]string, error) {
+	globs := make([]glob.Glob, len(patterns))
+	for i, pattern := range patterns {
+		g, err := glob.Compile(strings.ToLower(pattern))
+		if err != nil {
+			return nil, err
+		}
+		globs[i] = g
+	}
+
+	values := make(map[string]string)
+	v := reflect.ValueOf(conf)
+	for _, p := range parameters {
+		if !slices.ContainsFunc(globs, func(g glob.Glob) bool { return g.Match(p.name) }) {
+			continue
+		}
+		switch field := v.FieldByName(p.field).Interface().(type) {
+		case time.Duration:
+			values[p.name] = field.String()
+		default:
+			values[p.name] = fmt.Sprintf("%v", field)
+		}
+	}
+	return values, nil
+}
+
+// SetParameter sets the value of the configuration parameter in conf.
+// Only parameters that can be safely changed at runtime can be set.
+func SetParameter(conf *Config, name string, value string) error {
+	idx := slices.IndexFunc(parameters, func(p parameter) bool {
+		return p.name == strings.ToLower(name)
+	})
+	if idx == -1 {
+		return fmt.Errorf("unknown parameter %s", name)
+	}
+	if parameters[idx].set == nil {
+		return fmt.Errorf("parameter %s cannot be changed at runtime", parameters[idx].name)
+	}
+	if err := parameters[idx].set(conf, value); err != nil {
+		return fmt.Errorf("invalid value for parameter %s: %v", parameters[idx].name, err)
+	}
+	return nil
+}
+
+// Rewrite persists the parameters that can be changed at runtime to the config file that the
+// configuration was loaded from. The other values in the file are preserved.
+func Rewrite(conf Config) error {
+	if conf.ConfigFile == "" {
+		return errors.New("the server is running without a config file")
+	}
+
+	b, err := os.ReadFile(conf.ConfigFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	switch strings.ToLower(path.Ext(conf.ConfigFile)) {
+	case ".json":
+		b, err = rewriteJSON(conf, b)
+	case ".yaml", ".yml":
+		b, err = rewriteYAML(conf, b)
+	default:
+		err = fmt.Errorf("config file %s must be a JSON or YAML file", conf.ConfigFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(conf.ConfigFile, b, 0644)
+}
+
+// mutableFields returns the struct field of each parameter that can be changed at runtime, along with
+// the key that holds it in the config file.
+func mutableFields(conf Config) map[string]interface{} {
+	fields := make(map[string]interface{})
+	v := reflect.ValueOf(conf)
+	for _, p := range parameters {
+		if p.set == nil {
+			continue
+		}
+		field, _ := v.Type().FieldByName(p.field)
+		fields[field.Tag.Get("json")] = v.FieldByName(p.field).Interface()
+	}
+	return fields
+}
+
+func rewriteJSON(conf Config, b []byte) ([]byte, error) {
+	values := make(map[string]json.RawMessage)
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &values); err != nil {
+			return nil, err
+		}
+	}
+	for key, value := range mutableFields(conf) {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = raw
+	}
+	b, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func rewriteYAML(conf Config, b []byte) ([]byte, error) {
+	// Edit the document node so that the order and comments of the existing entries are preserved.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, errors.New("config file must contain a YAML mapping")
+	}
+
+	fields := mutableFields(conf)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value := &yaml.Node{}
+		if err := value.Encode(fields[key]); err != nil {
+			return nil, err
+		}
+		// Keys and values alternate in the content of a mapping node.
+		found := false
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == key {
+				mapping.Content[i+1] = value
+				found = true
+				break
+			}
+		}
+		if !found {
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+		}
+	}
+
+	return yaml.Marshal(&doc)
+}
@@ -17,39 +17,57 @@ package constants
 const Version = "0.13.1" // Next SugarDB version. Update this before each release.
 
 const (
-	ACLModule        = "acl"
-	AdminModule      = "admin"
-	ConnectionModule = "connection"
-	GenericModule    = "generic"
-	HashModule       = "hash"
-	ListModule       = "list"
-	PubSubModule     = "pubsub"
-	SetModule        = "set"
-	SortedSetModule  = "sortedset"
-	StringModule     = "string"
+	ACLModule           = "acl"
+	AdminModule         = "admin"
+	ConnectionModule    = "connection"
+	GenericModule       = "generic"
+	GeoModule           = "geo"
+	HashModule          = "hash"
+	HyperLogLogModule   = "hyperloglog"
+	JSONModule          = "json"
+	ListModule          = "list"
+	ProbabilisticModule = "probabilistic"
+	PubSubModule        = "pubsub"
+	ScriptingModule     = "scripting"
+	SearchModule        = "search"
+	SetModule           = "set"
+	SortedSetModule     = "sortedset"
+	StreamModule        = "stream"
+	StringModule        = "string"
+	TimeSeriesModule    = "timeseries"
+	TransactionModule   = "transaction"
+	VectorSetModule     = "vectorset"
 )
 
 const (
 	AdminCategory       = "admin"
 	BitmapCategory      = "bitmap"
 	BlockingCategory    = "blocking"
+	BloomCategory       = "bloom"
+	CMSCategory         = "cms"
 	ConnectionCategory  = "connection"
+	CuckooCategory      = "cuckoo"
 	DangerousCategory   = "dangerous"
 	GeoCategory         = "geo"
 	HashCategory        = "hash"
 	HyperLogLogCategory = "hyperloglog"
 	FastCategory        = "fast"
+	JSONCategory        = "json"
 	KeyspaceCategory    = "keyspace"
 	ListCategory        = "list"
 	PubSubCategory      = "pubsub"
 	ReadCategory        = "read"
 	ScriptingCategory   = "scripting"
+	SearchCategory      = "search"
 	SetCategory         = "set"
 	SortedSetCategory   = "sortedset"
 	SlowCategory        = "slow"
 	StreamCategory      = "stream"
 	StringCategory      = "string"
+	TimeSeriesCategory  = "timeseries"
+	TopKCategory        = "topk"
 	TransactionCategory = "transaction"
+	VectorSetCategory   = "vectorset"
 	WriteCategory       = "write"
 )
 
@@ -64,3 +64,22 @@ func Test_CacheLFU(t *testing.T) {
 	}
 	mut.Unlock()
 }
+
+func Test_CacheLFU_SetCount(t *testing.T) {
+	cache := eviction.NewCacheLFU()
+	for _, key := range []string{"key1", "key2", "key2", "key3", "key3", "key3"} {
+		cache.Update(key)
+	}
+	cache.SetCount("key1", 10)
+	cache.SetCount("key4", 5)
+
+	if count, err := cache.GetCount("key4"); err != nil || count != 5 {
+		t.Errorf("expected key4 count 5, got %d, %v", count, err)
+	}
+	expectedKeys := []string{"key2", "key3", "key4", "key1"}
+	for _, expected := range expectedKeys {
+		if key := heap.Pop(cache).(string); key != expected {
+			t.Errorf("expected popped key %s, got %s", expected, key)
+		}
+	}
+}
@@ -112,6 +112,22 @@ func (cache *CacheLRU) Update(key string) {
 	heap.Fix(cache, entryIdx)
 }
 
+// SetTime sets the last access time of the key in unix milliseconds, adding the key to the cache if it's not
+// already in it.
+func (cache *CacheLRU) SetTime(key string, unixTime int64) {
+	entryIdx := slices.IndexFunc(cache.entries, func(e *EntryLRU) bool {
+		return e.key == key
+	})
+	if entryIdx == -1 {
+		heap.Push(cache, key)
+		entryIdx = slices.IndexFunc(cache.entries, func(e *EntryLRU) bool {
+			return e.key == key
+		})
+	}
+	cache.entries[entryIdx].unixTime = unixTime
+	heap.Fix(cache, entryIdx)
+}
+
 func (cache *CacheLRU) Delete(key string) {
 	entryIdx := slices.IndexFunc(cache.entries, func(entry *EntryLRU) bool {
 		return entry.key == key
@@ -46,3 +46,21 @@ func Test_CacheLRU(t *testing.T) {
 		}
 	}
 }
+
+func Test_CacheLRU_SetTime(t *testing.T) {
+	cache := eviction.NewCacheLRU()
+	now := time.Now().UnixMilli()
+	cache.SetTime("key1", now-1000)
+	cache.SetTime("key2", now-3000)
+	cache.SetTime("key3", now-2000)
+	cache.SetTime("key1", now)
+
+	if unixTime, err := cache.GetTime("key2"); err != nil || unixTime != now-3000 {
+		t.Errorf("expected key2 time %d, got %d, %v", now-3000, unixTime, err)
+	}
+	for _, expected := range []string{"key1", "key3", "key2"} {
+		if key := heap.Pop(cache).(string); key != expected {
+			t.Errorf("expected popped key %s, got %s", expected, key)
+		}
+	}
+}
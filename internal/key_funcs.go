@@ -0,0 +1,51 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"errors"
+
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// WriteKeyFunc returns the key extraction function of a command that only writes the key at cmd[1] and has at
+// least minLength arguments, including the command name.
+func WriteKeyFunc(minLength int) KeyExtractionFunc {
+	return func(cmd []string) (KeyExtractionFuncResult, error) {
+		if len(cmd) < minLength {
+			return KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+		}
+		return KeyExtractionFuncResult{
+			Channels:  make([]string, 0),
+			ReadKeys:  make([]string, 0),
+			WriteKeys: cmd[1:2],
+		}, nil
+	}
+}
+
+// ReadKeyFunc returns the key extraction function of a command that only reads the key at cmd[1] and has at
+// least minLength arguments, including the command name.
+func ReadKeyFunc(minLength int) KeyExtractionFunc {
+	return func(cmd []string) (KeyExtractionFuncResult, error) {
+		if len(cmd) < minLength {
+			return KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+		}
+		return KeyExtractionFuncResult{
+			Channels:  make([]string, 0),
+			ReadKeys:  cmd[1:2],
+			WriteKeys: make([]string, 0),
+		}, nil
+	}
+}
@@ -48,6 +48,14 @@ type ACL struct {
 	Connections  map[*net.Conn]Connection // Connections to the echovault that are currently registered with the ACL module
 	Config       config.Config            // SugarDB configuration that contains the relevant ACL config options
 	GlobPatterns map[string]glob.Glob
+	// The password settings of the default user before require-pass replaced them, or nil if they weren't replaced.
+	replacedDefault *passwordState
+}
+
+// passwordState holds the password settings of a user.
+type passwordState struct {
+	noPassword bool
+	passwords  []Password
 }
 
 func loadUsersFromConfigFile(filePath string) []*User {
@@ -118,8 +126,13 @@ func NewACL(config config.Config) *ACL {
 			break
 		}
 	}
+	var replacedDefault *passwordState
 	if !defaultLoaded {
 		users = append([]*User{defaultUser}, users...)
+		if config.RequirePass {
+			// The settings of a new user, which are restored if the password is no longer required.
+			replacedDefault = &passwordState{noPassword: false, passwords: []Password{}}
+		}
 	}
 
 	// 4. Normalise all users
@@ -133,6 +146,8 @@ func NewACL(config config.Config) *ACL {
 		Connections:  make(map[*net.Conn]Connection),
 		Config:       config,
 		GlobPatterns: make(map[string]glob.Glob),
+
+		replacedDefault: replacedDefault,
 	}
 
 	acl.CompileGlobs()
@@ -150,11 +165,59 @@ func (acl *ACL) RegisterConnection(conn *net.Conn) {
 	})
 	defaultUser := acl.Users[defaultUserIdx]
 	acl.Connections[conn] = Connection{
-		Authenticated: defaultUser.NoPassword,
+		// A connection established while no password is required remains authenticated if one is required later.
+		Authenticated: defaultUser.NoPassword || !acl.Config.RequirePass,
 		User:          defaultUser,
 	}
 }
 
+// UnregisterConnection removes the connection from the ACL once it's closed.
+func (acl *ACL) UnregisterConnection(conn *net.Conn) {
+	acl.LockUsers()
+	defer acl.UnlockUsers()
+	delete(acl.Connections, conn)
+}
+
+// SetRequirePass updates the require-pass and password configuration at runtime.
+// When a password is required, it becomes the password of the default user. When it's no longer required,
+// the default user's previous password settings are restored.
+// Connections that are already authenticated remain authenticated.
+func (acl *ACL) SetRequirePass(requirePass bool, password string) {
+	acl.LockUsers()
+	defer acl.UnlockUsers()
+
+	acl.Config.RequirePass = requirePass
+	acl.Config.Password = password
+
+	defaultUserIdx := slices.IndexFunc(acl.Users, func(user *User) bool {
+		return user.Username == "default"
+	})
+	if defaultUserIdx == -1 {
+		return
+	}
+	defaultUser := acl.Users[defaultUserIdx]
+
+	if !requirePass {
+		if acl.replacedDefault != nil {
+			defaultUser.NoPassword = acl.replacedDefault.noPassword
+			defaultUser.Passwords = acl.replacedDefault.passwords
+			acl.replacedDefault = nil
+		}
+		return
+	}
+
+	if acl.replacedDefault == nil {
+		acl.replacedDefault = &passwordState{noPassword: defaultUser.NoPassword, passwords: defaultUser.Passwords}
+	}
+	defaultUser.NoPassword = false
+	defaultUser.Passwords = []Password{
+		{
+			PasswordType:  GetPasswordType(password),
+			PasswordValue: password,
+		},
+	}
+}
+
 func (acl *ACL) SetUser(cmd []string) error {
 	acl.LockUsers()
 	defer acl.UnlockUsers()
@@ -420,7 +483,11 @@ func (acl *ACL) AuthorizeConnection(conn *net.Conn, cmd []string, command intern
 			return errors.New("not authorised to access any keys")
 		}
 
-		// 8. Check if readKeys are in IncludedReadKeys
+		// 8. Check if readKeys are in IncludedReadKeys.
+		// Commands that read keys only known at execution time need access to all keys.
+		if keys.AllReadKeys && !slices.Contains(connection.User.IncludedReadKeys, "*") {
+			return fmt.Errorf("not authorised to access the following read keys: %+v", []string{"%R~*"})
+		}
 		if len(readKeys) > 0 && !slices.ContainsFunc(readKeys, func(key string) bool {
 			return slices.ContainsFunc(connection.User.IncludedReadKeys, func(readKeyGlob string) bool {
 				if acl.GlobPatterns[readKeyGlob].Match(key) {
@@ -236,8 +236,9 @@ func Test_ACL(t *testing.T) {
 					constants.PubSubCategory,
 					constants.ConnectionCategory,
 					constants.ListCategory,
+					constants.KeyspaceCategory,
 				},
-				IncludeCommands:      []string{"set", "get", "subscribe", "lrange", "ltrim"},
+				IncludeCommands:      []string{"set", "get", "subscribe", "lrange", "ltrim", "sort_ro"},
 				IncludeChannels:      []string{"channel[12]"},
 				IncludeReadWriteKeys: []string{"key1", "key2"},
 			},
@@ -399,6 +400,21 @@ func Test_ACL(t *testing.T) {
 				},
 				wantErr: fmt.Sprintf("not authorised to access the following write keys: [%s~%s]", "%W", "key3"),
 			},
+			{
+				name: "11. Return error when SORT_RO reads keys through a pattern without access to all keys",
+				auth: []resp.Value{
+					resp.StringValue("AUTH"),
+					resp.StringValue("test_included"),
+					resp.StringValue("test_included_password"),
+				},
+				cmd: []resp.Value{
+					resp.StringValue("SORT_RO"),
+					resp.StringValue("key1"),
+					resp.StringValue("BY"),
+					resp.StringValue("key2*"),
+				},
+				wantErr: fmt.Sprintf("not authorised to access the following read keys: [%s~%s]", "%R", "*"),
+			},
 		}
 
 		for _, test := range tests {
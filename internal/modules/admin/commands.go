@@ -189,6 +189,101 @@ func handleCommandDocs(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte("*0\r\n"), nil
 }
 
+func handleConfigGet(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	values, err := params.GetConfig(params.Context, params.Command[2:]...)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	res := fmt.Sprintf("*%d\r\n", len(names)*2)
+	for _, name := range names {
+		res += fmt.Sprintf("$%d\r\n%s\r\n$%d\r\n%s\r\n", len(name), name, len(values[name]), values[name])
+	}
+	return []byte(res), nil
+}
+
+func handleConfigSet(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 4 || len(params.Command)%2 != 0 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	values := make(map[string]string)
+	for i := 2; i < len(params.Command); i += 2 {
+		name := strings.ToLower(params.Command[i])
+		if _, ok := values[name]; ok {
+			return nil, fmt.Errorf("duplicate parameter %s", name)
+		}
+		values[name] = params.Command[i+1]
+	}
+
+	if err := params.SetConfig(params.Context, values); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleConfigRewrite(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	if err := params.RewriteConfig(params.Context); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleInfo(params internal.HandlerFuncParams) ([]byte, error) {
+	sections := params.GetInfo(params.Context)
+
+	// Without arguments, the default sections (all sections except commandstats) are returned.
+	selected := make(map[string]bool)
+	if len(params.Command) == 1 {
+		for _, section := range sections {
+			selected[section.Name] = section.Name != "commandstats"
+		}
+	}
+	for _, arg := range params.Command[1:] {
+		switch strings.ToLower(arg) {
+		case "default":
+			for _, section := range sections {
+				selected[section.Name] = selected[section.Name] || section.Name != "commandstats"
+			}
+		case "all", "everything":
+			for _, section := range sections {
+				selected[section.Name] = true
+			}
+		default:
+			// Unknown sections are ignored.
+			selected[strings.ToLower(arg)] = true
+		}
+	}
+
+	var blocks []string
+	for _, section := range sections {
+		if !selected[section.Name] {
+			continue
+		}
+		block := fmt.Sprintf("# %s%s\r\n", strings.ToUpper(section.Name[:1]), section.Name[1:])
+		for _, field := range section.Fields {
+			block += fmt.Sprintf("%s:%s\r\n", field.Name, field.Value)
+		}
+		blocks = append(blocks, block)
+	}
+
+	res := strings.Join(blocks, "\r\n")
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(res), res)), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -318,6 +413,81 @@ Get the list of command names. Allows for filtering by ACL category or glob patt
 				return []byte(constants.OkResponse), nil
 			},
 		},
+		{
+			Command:     "config",
+			Module:      constants.AdminModule,
+			Categories:  []string{},
+			Description: "Commands pertaining to the server configuration",
+			Sync:        false,
+			Type:        "BUILT_IN",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "get",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CONFIG GET parameter [parameter ...]) Get the values of the configuration parameters.
+Each parameter can be a glob pattern. The parameter names are the same as the command line flags (e.g. max-memory).`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleConfigGet,
+				},
+				{
+					Command:    "set",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CONFIG SET parameter value [parameter value ...]) Change configuration parameters at runtime.
+Only max-memory, eviction-policy, eviction-sample, eviction-interval, aof-sync-strategy, snapshot-interval,
+snapshot-threshold, require-pass, password and notify-keyspace-events can be changed.
+No parameter is changed if any of the values is invalid.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleConfigSet,
+				},
+				{
+					Command:    "rewrite",
+					Module:     constants.AdminModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+					Description: `(CONFIG REWRITE) Persist the configuration parameters that can be changed at runtime
+to the JSON or YAML config file the server was started with.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleConfigRewrite,
+				},
+			},
+		},
+		{
+			Command:    "info",
+			Module:     constants.AdminModule,
+			Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: `(INFO [section [section ...]]) Get information and statistics about the server.
+The sections are server, clients, memory, persistence, stats, replication, keyspace and commandstats.
+Without arguments, all sections except commandstats are returned. Use "all" or "everything" to include every section.`,
+			Sync: false,
+			Type: "BUILT_IN",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels: make([]string, 0), ReadKeys: make([]string, 0), WriteKeys: make([]string, 0),
+				}, nil
+			},
+			HandlerFunc: handleInfo,
+		},
 		{
 			Command:     "module",
 			Module:      constants.AdminModule,
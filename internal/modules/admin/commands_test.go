@@ -24,12 +24,22 @@ import (
 	"github.com/echovault/sugardb/internal/modules/admin"
 	"github.com/echovault/sugardb/internal/modules/connection"
 	"github.com/echovault/sugardb/internal/modules/generic"
+	"github.com/echovault/sugardb/internal/modules/geo"
 	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/hyperloglog"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
 	"github.com/echovault/sugardb/internal/modules/list"
+	"github.com/echovault/sugardb/internal/modules/probabilistic"
 	"github.com/echovault/sugardb/internal/modules/pubsub"
+	"github.com/echovault/sugardb/internal/modules/scripting"
+	"github.com/echovault/sugardb/internal/modules/search"
 	"github.com/echovault/sugardb/internal/modules/set"
 	"github.com/echovault/sugardb/internal/modules/sorted_set"
+	"github.com/echovault/sugardb/internal/modules/stream"
 	str "github.com/echovault/sugardb/internal/modules/string"
+	"github.com/echovault/sugardb/internal/modules/timeseries"
+	"github.com/echovault/sugardb/internal/modules/transaction"
+	"github.com/echovault/sugardb/internal/modules/vector_set"
 	"github.com/echovault/sugardb/sugardb"
 	"github.com/tidwall/resp"
 	"os"
@@ -97,13 +107,23 @@ func Test_AdminCommands(t *testing.T) {
 		commands = append(commands, acl.Commands()...)
 		commands = append(commands, admin.Commands()...)
 		commands = append(commands, generic.Commands()...)
+		commands = append(commands, geo.Commands()...)
 		commands = append(commands, hash.Commands()...)
+		commands = append(commands, hyperloglog.Commands()...)
+		commands = append(commands, jsondoc.Commands()...)
 		commands = append(commands, list.Commands()...)
+		commands = append(commands, probabilistic.Commands()...)
 		commands = append(commands, connection.Commands()...)
 		commands = append(commands, pubsub.Commands()...)
+		commands = append(commands, scripting.Commands()...)
+		commands = append(commands, search.Commands()...)
 		commands = append(commands, set.Commands()...)
 		commands = append(commands, sorted_set.Commands()...)
+		commands = append(commands, stream.Commands()...)
 		commands = append(commands, str.Commands()...)
+		commands = append(commands, timeseries.Commands()...)
+		commands = append(commands, transaction.Commands()...)
+		commands = append(commands, vector_set.Commands()...)
 
 		// Flatten the commands and subcommands.
 		var allCommands []string
@@ -149,13 +169,23 @@ func Test_AdminCommands(t *testing.T) {
 		commands = append(commands, acl.Commands()...)
 		commands = append(commands, admin.Commands()...)
 		commands = append(commands, generic.Commands()...)
+		commands = append(commands, geo.Commands()...)
 		commands = append(commands, hash.Commands()...)
+		commands = append(commands, hyperloglog.Commands()...)
+		commands = append(commands, jsondoc.Commands()...)
 		commands = append(commands, list.Commands()...)
+		commands = append(commands, probabilistic.Commands()...)
 		commands = append(commands, connection.Commands()...)
 		commands = append(commands, pubsub.Commands()...)
+		commands = append(commands, scripting.Commands()...)
+		commands = append(commands, search.Commands()...)
 		commands = append(commands, set.Commands()...)
 		commands = append(commands, sorted_set.Commands()...)
+		commands = append(commands, stream.Commands()...)
 		commands = append(commands, str.Commands()...)
+		commands = append(commands, timeseries.Commands()...)
+		commands = append(commands, transaction.Commands()...)
+		commands = append(commands, vector_set.Commands()...)
 
 		// Flatten the commands and subcommands.
 		var allCommands []string
@@ -190,13 +220,23 @@ func Test_AdminCommands(t *testing.T) {
 		allCommands = append(allCommands, acl.Commands()...)
 		allCommands = append(allCommands, admin.Commands()...)
 		allCommands = append(allCommands, generic.Commands()...)
+		allCommands = append(allCommands, geo.Commands()...)
 		allCommands = append(allCommands, hash.Commands()...)
+		allCommands = append(allCommands, hyperloglog.Commands()...)
+		allCommands = append(allCommands, jsondoc.Commands()...)
 		allCommands = append(allCommands, list.Commands()...)
+		allCommands = append(allCommands, probabilistic.Commands()...)
 		allCommands = append(allCommands, connection.Commands()...)
 		allCommands = append(allCommands, pubsub.Commands()...)
+		allCommands = append(allCommands, scripting.Commands()...)
+		allCommands = append(allCommands, search.Commands()...)
 		allCommands = append(allCommands, set.Commands()...)
 		allCommands = append(allCommands, sorted_set.Commands()...)
+		allCommands = append(allCommands, stream.Commands()...)
 		allCommands = append(allCommands, str.Commands()...)
+		allCommands = append(allCommands, timeseries.Commands()...)
+		allCommands = append(allCommands, transaction.Commands()...)
+		allCommands = append(allCommands, vector_set.Commands()...)
 
 		tests := []struct {
 			name string
@@ -246,7 +286,9 @@ func Test_AdminCommands(t *testing.T) {
 				want: func() []string {
 					var commands []string
 					for _, command := range sorted_set.Commands() {
-						commands = append(commands, command.Command)
+						if strings.HasPrefix(command.Command, "z") {
+							commands = append(commands, command.Command)
+						}
 					}
 					return commands
 				}(),
@@ -905,6 +947,364 @@ func Test_AdminCommands(t *testing.T) {
 		}
 	})
 
+	t.Run("Test CONFIG GET/SET/REWRITE commands", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		mockServer, err := setupServer(uint16(port))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			mockServer.Start()
+		}()
+		t.Cleanup(func() {
+			mockServer.ShutDown()
+		})
+
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name        string
+			command     []string
+			expectedRes []string
+			expectedErr error
+		}{
+			{
+				name:        "1. Get parameters matching a glob pattern",
+				command:     []string{"CONFIG", "GET", "eviction-p*", "max-memory"},
+				expectedRes: []string{"eviction-policy", "noeviction", "max-memory", "0"},
+			},
+			{
+				name:        "2. Set multiple parameters",
+				command:     []string{"CONFIG", "SET", "eviction-policy", "allkeys-lru", "max-memory", "100mb"},
+				expectedRes: []string{"OK"},
+			},
+			{
+				name:        "3. Get the updated parameters",
+				command:     []string{"CONFIG", "GET", "eviction-policy", "max-memory"},
+				expectedRes: []string{"eviction-policy", "allkeys-lru", "max-memory", "104857600"},
+			},
+			{
+				name:        "4. Do not change any parameter if one of the values is invalid",
+				command:     []string{"CONFIG", "SET", "max-memory", "1gb", "eviction-policy", "invalid"},
+				expectedErr: errors.New("invalid value for parameter eviction-policy: policy invalid is not a valid policy"),
+			},
+			{
+				name:        "5. Get the parameters after the failed update",
+				command:     []string{"CONFIG", "GET", "max-memory"},
+				expectedRes: []string{"max-memory", "104857600"},
+			},
+			{
+				name:        "6. Return error when setting a parameter that cannot be changed at runtime",
+				command:     []string{"CONFIG", "SET", "port", "7000"},
+				expectedErr: errors.New("parameter port cannot be changed at runtime"),
+			},
+			{
+				name:        "7. Return error when a parameter is repeated",
+				command:     []string{"CONFIG", "SET", "max-memory", "1gb", "MAX-MEMORY", "2gb"},
+				expectedErr: errors.New("duplicate parameter max-memory"),
+			},
+			{
+				name:        "8. Return error when rewriting without a config file",
+				command:     []string{"CONFIG", "REWRITE"},
+				expectedErr: errors.New("the server is running without a config file"),
+			},
+			{
+				name:        "9. Return error when CONFIG SET is missing a value",
+				command:     []string{"CONFIG", "SET", "max-memory"},
+				expectedErr: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+					return
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if test.expectedErr != nil {
+					if !strings.Contains(res.Error().Error(), test.expectedErr.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedErr.Error(), res.Error().Error())
+					}
+					return
+				}
+
+				if res.Type() == resp.SimpleString {
+					if res.String() != test.expectedRes[0] {
+						t.Errorf("expected response \"%s\", got \"%s\"", test.expectedRes[0], res.String())
+					}
+					return
+				}
+
+				got := make(map[string]string)
+				for i := 0; i+1 < len(res.Array()); i += 2 {
+					got[res.Array()[i].String()] = res.Array()[i+1].String()
+				}
+				if len(got) != len(test.expectedRes)/2 {
+					t.Errorf("expected %d parameters, got %d", len(test.expectedRes)/2, len(got))
+				}
+				for i := 0; i+1 < len(test.expectedRes); i += 2 {
+					if got[test.expectedRes[i]] != test.expectedRes[i+1] {
+						t.Errorf("expected parameter %s to be \"%s\", got \"%s\"",
+							test.expectedRes[i], test.expectedRes[i+1], got[test.expectedRes[i]])
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Test CONFIG SET require-pass", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		mockServer, err := setupServer(uint16(port))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			mockServer.Start()
+		}()
+		t.Cleanup(func() {
+			mockServer.ShutDown()
+		})
+
+		// connect registers a new connection with the current require-pass setting.
+		connect := func() func(cmd ...string) resp.Value {
+			conn, err := internal.GetConnection("localhost", port)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				_ = conn.Close()
+			})
+			client := resp.NewConn(conn)
+			return func(cmd ...string) resp.Value {
+				command := make([]resp.Value, len(cmd))
+				for i, c := range cmd {
+					command[i] = resp.StringValue(c)
+				}
+				if err := client.WriteArray(command); err != nil {
+					t.Fatal(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Fatal(err)
+				}
+				return res
+			}
+		}
+		expect := func(res resp.Value, want string) {
+			t.Helper()
+			if res.Error() != nil {
+				if !strings.Contains(res.Error().Error(), want) {
+					t.Errorf("expected response \"%s\", got error \"%s\"", want, res.Error().Error())
+				}
+				return
+			}
+			if res.String() != want {
+				t.Errorf("expected response \"%s\", got \"%s\"", want, res.String())
+			}
+		}
+
+		admin := connect()
+
+		// 1. A new connection must authenticate once a password is required.
+		expect(admin("CONFIG", "SET", "password", "password1", "require-pass", "true"), "OK")
+		client1 := connect()
+		expect(client1("SET", "key", "value"), "user must be authenticated")
+		expect(client1("AUTH", "password1"), "OK")
+		expect(client1("SET", "key", "value"), "OK")
+
+		// 2. A new connection doesn't need to authenticate once the password is no longer required.
+		expect(admin("CONFIG", "SET", "require-pass", "false"), "OK")
+		client2 := connect()
+		expect(client2("SET", "key", "value"), "OK")
+
+		// 3. Only the new password is accepted when a password is required again. The connection established
+		// while no password was required remains authenticated.
+		expect(admin("CONFIG", "SET", "password", "password2", "require-pass", "true"), "OK")
+		expect(client2("SET", "key", "value"), "OK")
+		client3 := connect()
+		expect(client3("SET", "key", "value"), "user must be authenticated")
+		expect(client3("AUTH", "password1"), "could not authenticate user")
+		expect(client3("AUTH", "password2"), "OK")
+		expect(client3("SET", "key", "value"), "OK")
+	})
+
+	t.Run("Test INFO command", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		mockServer, err := setupServer(uint16(port))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			mockServer.Start()
+		}()
+		t.Cleanup(func() {
+			mockServer.ShutDown()
+		})
+
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		// Prepare data for testing.
+		for _, command := range [][]string{
+			{"SET", "key1", "value1"},
+			{"SET", "key2", "value2", "PX", "100000"},
+			{"GET", "key1"},
+			{"GET", "key3"},
+		} {
+			values := make([]resp.Value, len(command))
+			for i, c := range command {
+				values[i] = resp.StringValue(c)
+			}
+			if err = client.WriteArray(values); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, _, err = client.ReadValue(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		tests := []struct {
+			name           string
+			command        []string
+			wantSections   []string
+			wantFields     map[string]string
+			unwantSections []string
+		}{
+			{
+				name:           "1. Return the default sections without arguments",
+				command:        []string{"INFO"},
+				wantSections:   []string{"Server", "Clients", "Memory", "Persistence", "Stats", "Replication", "Keyspace"},
+				unwantSections: []string{"Commandstats"},
+				wantFields: map[string]string{
+					"sugardb_mode":      "standalone",
+					"connected_clients": "1",
+					"role":              "master",
+					"keyspace_hits":     "1",
+					"keyspace_misses":   "1",
+					"db0":               "keys=2,expires=1",
+				},
+			},
+			{
+				name:           "2. Return only the requested sections",
+				command:        []string{"INFO", "keyspace", "COMMANDSTATS"},
+				wantSections:   []string{"Keyspace", "Commandstats"},
+				unwantSections: []string{"Server", "Stats"},
+				wantFields: map[string]string{
+					"db0":         "keys=2,expires=1",
+					"cmdstat_set": "calls=2",
+					"cmdstat_get": "calls=2",
+				},
+			},
+			{
+				name:         "3. Return all the sections",
+				command:      []string{"INFO", "all"},
+				wantSections: []string{"Server", "Keyspace", "Commandstats"},
+			},
+			{
+				name:           "4. Ignore unknown sections",
+				command:        []string{"INFO", "unknown"},
+				unwantSections: []string{"Server", "Keyspace", "Commandstats"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+					return
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				sections := make(map[string]bool)
+				fields := make(map[string]string)
+				for _, line := range strings.Split(res.String(), "\r\n") {
+					if strings.HasPrefix(line, "# ") {
+						sections[strings.TrimPrefix(line, "# ")] = true
+						continue
+					}
+					if name, value, ok := strings.Cut(line, ":"); ok {
+						fields[name] = value
+					}
+				}
+
+				for _, section := range test.wantSections {
+					if !sections[section] {
+						t.Errorf("expected section \"%s\" in response, got %s", section, res.String())
+					}
+				}
+				for _, section := range test.unwantSections {
+					if sections[section] {
+						t.Errorf("did not expect section \"%s\" in response", section)
+					}
+				}
+				for name, value := range test.wantFields {
+					if !strings.HasPrefix(fields[name], value) {
+						t.Errorf("expected field \"%s\" to start with \"%s\", got \"%s\"", name, value, fields[name])
+					}
+				}
+			})
+		}
+	})
+
 	t.Run("Test REWRITEAOF command", func(t *testing.T) {
 		t.Parallel()
 
@@ -20,6 +20,8 @@ import (
 	"github.com/echovault/sugardb/internal/modules/acl"
 	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/constants"
@@ -171,6 +173,153 @@ func handleSwapDB(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(constants.OkResponse), nil
 }
 
+func handleClientList(params internal.HandlerFuncParams) ([]byte, error) {
+	var ids []uint64
+	if len(params.Command) > 2 {
+		if len(params.Command) < 4 || !strings.EqualFold(params.Command[2], "id") {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+		for _, arg := range params.Command[3:] {
+			id, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid client id %s", arg)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	now := params.GetClock().Now()
+	var list strings.Builder
+	for _, info := range params.ListClients() {
+		if len(ids) > 0 && !slices.Contains(ids, info.Id) {
+			continue
+		}
+		list.WriteString(buildClientInfo(info, now))
+	}
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", list.Len(), list.String())), nil
+}
+
+func handleClientInfo(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	connectionInfo := params.GetConnectionInfo(params.Connection)
+	for _, info := range params.ListClients() {
+		if info.Id == connectionInfo.Id {
+			connectionInfo = info
+			break
+		}
+	}
+	info := buildClientInfo(connectionInfo, params.GetClock().Now())
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(info), info)), nil
+}
+
+func handleClientID(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", params.GetConnectionInfo(params.Connection).Id)), nil
+}
+
+func handleClientGetName(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	name := params.GetConnectionInfo(params.Connection).Name
+	if name == "" {
+		return []byte("$-1\r\n"), nil
+	}
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(name), name)), nil
+}
+
+func handleClientSetName(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	name := params.Command[2]
+	if strings.ContainsAny(name, " \r\n") {
+		return nil, errors.New("client names cannot contain spaces or newlines")
+	}
+	params.SetClientName(params.Connection, name)
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientKill(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	// CLIENT KILL addr closes the client with the address and fails if there is no such client.
+	if len(params.Command) == 3 {
+		killed := params.KillClients(func(info internal.ConnectionInfo) bool {
+			return info.Addr == params.Command[2]
+		})
+		if killed == 0 {
+			return nil, errors.New("no such client")
+		}
+		return []byte(constants.OkResponse), nil
+	}
+
+	filter, err := getClientKillFilter(params.Command[2:], clientKillFilter{skipMe: true})
+	if err != nil {
+		return nil, err
+	}
+	currentId := params.GetConnectionInfo(params.Connection).Id
+	killed := params.KillClients(func(info internal.ConnectionInfo) bool {
+		return filter.match(info, currentId)
+	})
+	return []byte(fmt.Sprintf(":%d\r\n", killed)), nil
+}
+
+func handleClientPause(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) < 3 || len(params.Command) > 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	timeout, err := strconv.ParseInt(params.Command[2], 10, 64)
+	if err != nil || timeout < 0 {
+		return nil, errors.New("timeout is not an integer or out of range")
+	}
+
+	all := true
+	if len(params.Command) == 4 {
+		switch strings.ToLower(params.Command[3]) {
+		case "all":
+			all = true
+		case "write":
+			all = false
+		default:
+			return nil, errors.New("pause mode must be WRITE or ALL")
+		}
+	}
+
+	params.PauseClients(time.Duration(timeout)*time.Millisecond, all)
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientUnpause(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	params.UnpauseClients()
+	return []byte(constants.OkResponse), nil
+}
+
+func handleClientNoEvict(params internal.HandlerFuncParams) ([]byte, error) {
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	switch strings.ToLower(params.Command[2]) {
+	case "on":
+		params.SetClientNoEvict(params.Connection, true)
+	case "off":
+		params.SetClientNoEvict(params.Connection, false)
+	default:
+		return nil, errors.New("no-evict must be ON or OFF")
+	}
+	return []byte(constants.OkResponse), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -209,6 +358,169 @@ Otherwise, the server will return "PONG".`,
 			},
 			HandlerFunc: handlePing,
 		},
+		{
+			Command:     "client",
+			Module:      constants.ConnectionModule,
+			Categories:  []string{},
+			Description: "Commands pertaining to the client connections",
+			Sync:        false,
+			Type:        "BUILT_IN",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels:  make([]string, 0),
+					ReadKeys:  make([]string, 0),
+					WriteKeys: make([]string, 0),
+				}, nil
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:     "getname",
+					Module:      constants.ConnectionModule,
+					Categories:  []string{constants.FastCategory, constants.ConnectionCategory},
+					Description: `(CLIENT GETNAME) Returns the name of the current connection, or nil if no name is set.`,
+					Sync:        false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientGetName,
+				},
+				{
+					Command:     "id",
+					Module:      constants.ConnectionModule,
+					Categories:  []string{constants.FastCategory, constants.ConnectionCategory},
+					Description: `(CLIENT ID) Returns the id of the current connection.`,
+					Sync:        false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientID,
+				},
+				{
+					Command:    "info",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.SlowCategory, constants.ConnectionCategory},
+					Description: `(CLIENT INFO) Returns information about the current connection
+in the same format as a line of the CLIENT LIST response.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientInfo,
+				},
+				{
+					Command:    "kill",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory, constants.ConnectionCategory},
+					Description: `(CLIENT KILL addr | CLIENT KILL [ID client-id] [ADDR addr] [LADDR laddr] [USER username] [SKIPME yes/no])
+Closes the connections of the clients that match all the filters.
+The first form closes the client with the address and returns an error if there is no such client.
+The second form returns the number of closed connections. SKIPME defaults to yes,
+so the connection that executes the command is not closed unless SKIPME no is provided.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientKill,
+				},
+				{
+					Command:    "list",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory, constants.ConnectionCategory},
+					Description: `(CLIENT LIST [ID client-id [client-id ...]]) Returns information about the TCP clients connected to the server.
+Each client is described on its own line with its id, address, local address, name, age and idle time in seconds,
+flags, database, user, last command and RESP protocol.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientList,
+				},
+				{
+					Command:    "no-evict",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory, constants.ConnectionCategory},
+					Description: `(CLIENT NO-EVICT ON|OFF) Sets whether the current connection is excluded from client eviction.
+The setting is reported with the "e" flag in CLIENT LIST and CLIENT INFO.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientNoEvict,
+				},
+				{
+					Command:    "pause",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory, constants.ConnectionCategory},
+					Description: `(CLIENT PAUSE timeout [WRITE|ALL]) Suspends the commands of all the TCP clients for timeout milliseconds.
+In ALL mode, which is the default, every command is suspended. In WRITE mode, only write commands are suspended.
+CLIENT commands are never suspended. If the clients are already paused, the pause is only extended.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientPause,
+				},
+				{
+					Command:    "setname",
+					Module:     constants.ConnectionModule,
+					Categories: []string{constants.FastCategory, constants.ConnectionCategory},
+					Description: `(CLIENT SETNAME connection-name) Sets the name of the current connection.
+An empty name clears the connection's name. The name cannot contain spaces or newlines.`,
+					Sync: false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientSetName,
+				},
+				{
+					Command:     "unpause",
+					Module:      constants.ConnectionModule,
+					Categories:  []string{constants.AdminCategory, constants.SlowCategory, constants.DangerousCategory, constants.ConnectionCategory},
+					Description: `(CLIENT UNPAUSE) Resumes the commands of the clients paused with CLIENT PAUSE.`,
+					Sync:        false,
+					KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+						return internal.KeyExtractionFuncResult{
+							Channels:  make([]string, 0),
+							ReadKeys:  make([]string, 0),
+							WriteKeys: make([]string, 0),
+						}, nil
+					},
+					HandlerFunc: handleClientUnpause,
+				},
+			},
+		},
 		{
 			Command:     "echo",
 			Module:      constants.ConnectionModule,
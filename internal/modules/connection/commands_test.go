@@ -22,10 +22,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/echovault/sugardb/internal/modules/connection"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/config"
@@ -998,3 +1000,270 @@ func Test_Connection(t *testing.T) {
 		}
 	})
 }
+
+func Test_Client(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := setUpServer(port, false, "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	// newClient opens a new connection and returns the client with the id of the connection.
+	newClient := func(t *testing.T) (*resp.Conn, net.Conn, int) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := resp.NewConn(conn)
+		if err = client.WriteArray([]resp.Value{resp.StringValue("CLIENT"), resp.StringValue("ID")}); err != nil {
+			t.Fatal(err)
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client, conn, res.Integer()
+	}
+
+	do := func(t *testing.T, client *resp.Conn, command ...string) resp.Value {
+		cmd := make([]resp.Value, len(command))
+		for i, arg := range command {
+			cmd[i] = resp.StringValue(arg)
+		}
+		if err := client.WriteArray(cmd); err != nil {
+			t.Fatal(err)
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	t.Run("Test_HandleClientNameAndInfo", func(t *testing.T) {
+		client, conn, id := newClient(t)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		if res := do(t, client, "CLIENT", "GETNAME"); !res.IsNull() {
+			t.Errorf("expected nil name, got %s", res.String())
+		}
+		if res := do(t, client, "CLIENT", "SETNAME", "client-1"); res.String() != "OK" {
+			t.Errorf("expected OK, got %s", res.String())
+		}
+		if res := do(t, client, "CLIENT", "GETNAME"); res.String() != "client-1" {
+			t.Errorf("expected name client-1, got %s", res.String())
+		}
+		if res := do(t, client, "CLIENT", "SETNAME", "client 1"); res.Error() == nil ||
+			res.Error().Error() != "Error client names cannot contain spaces or newlines" {
+			t.Errorf("expected invalid name error, got %+v", res)
+		}
+		if res := do(t, client, "CLIENT", "NO-EVICT", "ON"); res.String() != "OK" {
+			t.Errorf("expected OK, got %s", res.String())
+		}
+		if res := do(t, client, "SELECT", "2"); res.String() != "OK" {
+			t.Errorf("expected OK, got %s", res.String())
+		}
+
+		info := do(t, client, "CLIENT", "INFO").String()
+		for _, field := range []string{
+			fmt.Sprintf("id=%d ", id),
+			fmt.Sprintf("addr=%s ", conn.LocalAddr().String()),
+			fmt.Sprintf("laddr=%s ", conn.RemoteAddr().String()),
+			"name=client-1 ",
+			"flags=e ",
+			"db=2 ",
+			"user=default ",
+			"cmd=client|info ",
+			"resp=2\n",
+		} {
+			if !strings.Contains(info, field) {
+				t.Errorf("expected client info %q to contain %q", info, field)
+			}
+		}
+		if list := do(t, client, "CLIENT", "LIST").String(); !strings.Contains(list, fmt.Sprintf("id=%d ", id)) ||
+			!strings.Contains(list, "name=client-1 age=") || !strings.Contains(list, "flags=e ") {
+			t.Errorf("expected client list %q to contain the e flag of the client", list)
+		}
+
+		if res := do(t, client, "CLIENT", "NO-EVICT", "OFF"); res.String() != "OK" {
+			t.Errorf("expected OK, got %s", res.String())
+		}
+		if info := do(t, client, "CLIENT", "INFO").String(); !strings.Contains(info, "flags=N ") {
+			t.Errorf("expected client info %q to contain %q", info, "flags=N ")
+		}
+		if res := do(t, client, "CLIENT", "NO-EVICT", "MAYBE"); res.Error() == nil ||
+			res.Error().Error() != "Error no-evict must be ON or OFF" {
+			t.Errorf("expected invalid argument error, got %+v", res)
+		}
+
+		if res := do(t, client, "CLIENT", "SETNAME", ""); res.String() != "OK" {
+			t.Errorf("expected OK, got %s", res.String())
+		}
+		if res := do(t, client, "CLIENT", "GETNAME"); !res.IsNull() {
+			t.Errorf("expected cleared name, got %s", res.String())
+		}
+	})
+
+	t.Run("Test_HandleClientList", func(t *testing.T) {
+		client1, conn1, id1 := newClient(t)
+		defer func() {
+			_ = conn1.Close()
+		}()
+		client2, conn2, id2 := newClient(t)
+		defer func() {
+			_ = conn2.Close()
+		}()
+
+		do(t, client2, "SET", "key", "value")
+
+		list := do(t, client1, "CLIENT", "LIST").String()
+		if !strings.Contains(list, fmt.Sprintf("id=%d addr=%s ", id1, conn1.LocalAddr().String())) {
+			t.Errorf("expected client list %q to contain client %d", list, id1)
+		}
+		if !strings.Contains(list, fmt.Sprintf("id=%d addr=%s ", id2, conn2.LocalAddr().String())) ||
+			!strings.Contains(list, "cmd=set ") {
+			t.Errorf("expected client list %q to contain client %d with last command set", list, id2)
+		}
+
+		list = do(t, client1, "CLIENT", "LIST", "ID", strconv.Itoa(id2)).String()
+		if !strings.HasPrefix(list, fmt.Sprintf("id=%d ", id2)) || strings.Count(list, "\n") != 1 {
+			t.Errorf("expected only client %d, got %q", id2, list)
+		}
+
+		res := do(t, client1, "CLIENT", "LIST", "ID", "abc")
+		if res.Error() == nil || res.Error().Error() != "Error invalid client id abc" {
+			t.Errorf("expected invalid client id error, got %+v", res)
+		}
+	})
+
+	t.Run("Test_HandleClientKill", func(t *testing.T) {
+		client1, conn1, id1 := newClient(t)
+		defer func() {
+			_ = conn1.Close()
+		}()
+		client2, conn2, id2 := newClient(t)
+		defer func() {
+			_ = conn2.Close()
+		}()
+		client3, conn3, _ := newClient(t)
+		defer func() {
+			_ = conn3.Close()
+		}()
+
+		// SKIPME defaults to yes, so the current connection is not closed.
+		if res := do(t, client1, "CLIENT", "KILL", "ID", strconv.Itoa(id1)); res.Integer() != 0 {
+			t.Errorf("expected 0 killed clients, got %d", res.Integer())
+		}
+		if res := do(t, client1, "CLIENT", "KILL", "USER", "non_existent_user"); res.Integer() != 0 {
+			t.Errorf("expected 0 killed clients, got %d", res.Integer())
+		}
+		if res := do(t, client1, "CLIENT", "KILL", "ID", strconv.Itoa(id2), "USER", "default"); res.Integer() != 1 {
+			t.Errorf("expected 1 killed client, got %d", res.Integer())
+		}
+		if err := client2.WriteArray([]resp.Value{resp.StringValue("PING")}); err == nil {
+			if _, _, err = client2.ReadValue(); err == nil {
+				t.Errorf("expected client %d connection to be closed", id2)
+			}
+		}
+
+		if res := do(t, client1, "CLIENT", "KILL", conn3.LocalAddr().String()); res.String() != "OK" {
+			t.Errorf("expected OK, got %+v", res)
+		}
+		if err := client3.WriteArray([]resp.Value{resp.StringValue("PING")}); err == nil {
+			if _, _, err = client3.ReadValue(); err == nil {
+				t.Error("expected third client connection to be closed")
+			}
+		}
+
+		res := do(t, client1, "CLIENT", "KILL", conn3.LocalAddr().String())
+		if res.Error() == nil || res.Error().Error() != "Error no such client" {
+			t.Errorf("expected no such client error, got %+v", res)
+		}
+
+		list := do(t, client1, "CLIENT", "LIST").String()
+		if strings.Contains(list, fmt.Sprintf("id=%d ", id2)) {
+			t.Errorf("expected killed client %d to be removed from the client list %q", id2, list)
+		}
+	})
+
+	t.Run("Test_HandleClientPause", func(t *testing.T) {
+		client1, conn1, _ := newClient(t)
+		defer func() {
+			_ = conn1.Close()
+		}()
+		client2, conn2, _ := newClient(t)
+		defer func() {
+			_ = conn2.Close()
+		}()
+
+		// In WRITE mode, read commands are not paused.
+		if res := do(t, client1, "CLIENT", "PAUSE", "300", "WRITE"); res.String() != "OK" {
+			t.Errorf("expected OK, got %+v", res)
+		}
+		start := time.Now()
+		do(t, client2, "GET", "key")
+		if elapsed := time.Since(start); elapsed >= 300*time.Millisecond {
+			t.Errorf("expected read command not to be paused, took %v", elapsed)
+		}
+		do(t, client2, "SET", "key", "value")
+		if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+			t.Errorf("expected write command to be paused, took %v", elapsed)
+		}
+
+		// In ALL mode, every command is paused until CLIENT UNPAUSE.
+		if res := do(t, client1, "CLIENT", "PAUSE", "10000", "ALL"); res.String() != "OK" {
+			t.Errorf("expected OK, got %+v", res)
+		}
+		done := make(chan resp.Value, 1)
+		start = time.Now()
+		go func() {
+			if err := client2.WriteArray([]resp.Value{resp.StringValue("GET"), resp.StringValue("key")}); err != nil {
+				return
+			}
+			res, _, _ := client2.ReadValue()
+			done <- res
+		}()
+		select {
+		case res := <-done:
+			t.Errorf("expected command to be paused, got %+v", res)
+		case <-time.After(200 * time.Millisecond):
+		}
+		// CLIENT commands are not paused.
+		if res := do(t, client1, "CLIENT", "UNPAUSE"); res.String() != "OK" {
+			t.Errorf("expected OK, got %+v", res)
+		}
+		select {
+		case res := <-done:
+			if res.String() != "value" {
+				t.Errorf("expected value, got %+v", res)
+			}
+		case <-time.After(2 * time.Second):
+			t.Error("expected command to resume after CLIENT UNPAUSE")
+		}
+
+		res := do(t, client1, "CLIENT", "PAUSE", "100", "READ")
+		if res.Error() == nil || res.Error().Error() != "Error pause mode must be WRITE or ALL" {
+			t.Errorf("expected pause mode error, got %+v", res)
+		}
+		res = do(t, client1, "CLIENT", "PAUSE", "-1")
+		if res.Error() == nil || res.Error().Error() != "Error timeout is not an integer or out of range" {
+			t.Errorf("expected timeout error, got %+v", res)
+		}
+	})
+}
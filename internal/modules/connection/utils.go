@@ -1,10 +1,14 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/constants"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type helloOptions struct {
@@ -64,3 +68,84 @@ func BuildHelloResponse(serverInfo internal.ServerInfo, connectionInfo internal.
 	}
 	return res
 }
+
+type clientKillFilter struct {
+	ids    []uint64
+	addr   string
+	laddr  string
+	user   string
+	skipMe bool
+}
+
+func getClientKillFilter(cmd []string, filter clientKillFilter) (clientKillFilter, error) {
+	if len(cmd) == 0 {
+		return filter, nil
+	}
+	if len(cmd) < 2 {
+		return filter, errors.New(constants.WrongArgsResponse)
+	}
+	switch strings.ToLower(cmd[0]) {
+	case "id":
+		id, err := strconv.ParseUint(cmd[1], 10, 64)
+		if err != nil {
+			return filter, errors.New("client-id should be greater than 0")
+		}
+		filter.ids = append(filter.ids, id)
+	case "addr":
+		filter.addr = cmd[1]
+	case "laddr":
+		filter.laddr = cmd[1]
+	case "user":
+		filter.user = cmd[1]
+	case "skipme":
+		switch strings.ToLower(cmd[1]) {
+		case "yes":
+			filter.skipMe = true
+		case "no":
+			filter.skipMe = false
+		default:
+			return filter, errors.New("skipme must be yes or no")
+		}
+	default:
+		return filter, fmt.Errorf("unknown filter %s", strings.ToUpper(cmd[0]))
+	}
+	return getClientKillFilter(cmd[2:], filter)
+}
+
+// match reports whether the client matches all the filters. currentId is the id of the connection executing the command.
+func (filter clientKillFilter) match(info internal.ConnectionInfo, currentId uint64) bool {
+	if filter.skipMe && info.Id == currentId {
+		return false
+	}
+	if len(filter.ids) > 0 && !slices.Contains(filter.ids, info.Id) {
+		return false
+	}
+	if filter.addr != "" && filter.addr != info.Addr {
+		return false
+	}
+	if filter.laddr != "" && filter.laddr != info.LocalAddr {
+		return false
+	}
+	if filter.user != "" && filter.user != info.User {
+		return false
+	}
+	return true
+}
+
+// buildClientInfo returns the line describing the client in the CLIENT LIST and CLIENT INFO responses.
+func buildClientInfo(info internal.ConnectionInfo, now time.Time) string {
+	flags := "N"
+	if info.NoEvict {
+		flags = "e"
+	}
+	cmd := info.LastCommand
+	if cmd == "" {
+		cmd = "NULL"
+	}
+	return fmt.Sprintf(
+		"id=%d addr=%s laddr=%s name=%s age=%d idle=%d flags=%s db=%d user=%s cmd=%s resp=%d\n",
+		info.Id, info.Addr, info.LocalAddr, info.Name,
+		int64(now.Sub(info.CreatedAt).Seconds()), int64(now.Sub(info.LastInteraction).Seconds()),
+		flags, info.Database, info.User, cmd, info.Protocol,
+	)
+}
@@ -19,15 +19,20 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/codec"
 	"github.com/echovault/sugardb/internal/constants"
 	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/list"
 	"github.com/echovault/sugardb/internal/modules/set"
 	"github.com/echovault/sugardb/internal/modules/sorted_set"
+	"github.com/tidwall/resp"
 )
 
 type KeyObject struct {
@@ -841,26 +846,7 @@ func handleType(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	value := params.GetValues(params.Context, []string{key})[key]
-	type_string := ""
-	switch value.(type) {
-	case string:
-		type_string = "string"
-	case int:
-		type_string = "integer"
-	case float64:
-		type_string = "float"
-	case []string:
-		type_string = "list"
-	case hash.Hash:
-		type_string = "hash"
-	case *set.Set:
-		type_string = "set"
-	case *sorted_set.SortedSet:
-		type_string = "zset"
-	default:
-		type_string = fmt.Sprintf("%T", value)
-	}
-	return []byte(fmt.Sprintf("+%v\r\n", type_string)), nil
+	return []byte(fmt.Sprintf("+%v\r\n", typeName(value))), nil
 }
 
 func handleTouch(params internal.HandlerFuncParams) ([]byte, error) {
@@ -933,6 +919,10 @@ func handleCopy(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	value := params.GetValues(params.Context, []string{sourceKey})[sourceKey]
+	if l, ok := value.(*list.List); ok {
+		// Lists are modified in place, so the copy must not share its storage with the source.
+		value = l.Clone()
+	}
 
 	ctx := context.WithoutCancel(params.Context)
 
@@ -1012,7 +1002,7 @@ func handleKeys(params internal.HandlerFuncParams) ([]byte, error) {
 	} else {
 		// Find all matching keys using direct pattern matching
 		for _, key := range storeKeys {
-			if matchPattern(pattern, key) {
+			if internal.MatchPattern(pattern, key) {
 				matchedKeys = append(matchedKeys, key)
 			}
 		}
@@ -1027,6 +1017,400 @@ func handleKeys(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(res), nil
 }
 
+func handleScan(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := scanKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+
+	options, err := getScanOptions(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, keys := params.ScanKeys(params.Context, options.cursor, options.count, func(key string, value interface{}) bool {
+		if options.match != "" && !internal.MatchPattern(options.match, key) {
+			return false
+		}
+		return options.keyType == "" || matchType(options.keyType, value)
+	})
+
+	return internal.EncodeScanResponse(cursor, keys), nil
+}
+
+// lookupSortPattern replaces the first "*" in the pattern with each element and returns the value of the resulting
+// key, or of the hash field when the pattern ends with "->field". Elements whose value does not exist map to nil.
+func lookupSortPattern(params internal.HandlerFuncParams, pattern string, elements []string) map[string]*string {
+	res := make(map[string]*string, len(elements))
+	if pattern == "" || pattern == "#" {
+		return res
+	}
+
+	keyPattern, field := splitSortPattern(pattern)
+	lookupKeys := make([]string, len(elements))
+	for i, element := range elements {
+		lookupKeys[i] = strings.Replace(keyPattern, "*", element, 1)
+	}
+	values := params.GetValues(params.Context, lookupKeys)
+
+	for i, element := range elements {
+		value := values[lookupKeys[i]]
+		if field != "" {
+			h, ok := value.(*hash.Hash)
+			if !ok {
+				continue
+			}
+			value = h.Get(field).Value
+		}
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case int:
+			s = strconv.Itoa(v)
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			continue
+		}
+		res[element] = &s
+	}
+	return res
+}
+
+func handleSORT(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := sortKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := getSortOptions(params.Command[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	var elements []string
+	switch value := params.GetValues(params.Context, []string{key})[key].(type) {
+	case nil:
+		elements = make([]string, 0)
+	case *list.List:
+		elements = value.Elements()
+	case *set.Set:
+		elements = value.GetAll()
+	case *sorted_set.SortedSet:
+		members := value.GetAll()
+		elements = make([]string, len(members))
+		for i, member := range members {
+			elements[i] = string(member.Value)
+		}
+	default:
+		return nil, fmt.Errorf("value at %s is not a list, set or sorted set", key)
+	}
+
+	// A BY pattern without "*" refers to the same key for every element, so the elements are left unsorted.
+	if options.by == "" || strings.Contains(options.by, "*") {
+		weights := make(map[string]string, len(elements))
+		for element, weight := range lookupSortPattern(params, options.by, elements) {
+			if weight != nil {
+				weights[element] = *weight
+			}
+		}
+		if options.by == "" {
+			for _, element := range elements {
+				weights[element] = element
+			}
+		}
+
+		scores := make(map[string]float64, len(elements))
+		if !options.alpha {
+			for _, element := range elements {
+				weight, ok := weights[element]
+				if !ok {
+					continue
+				}
+				score, err := strconv.ParseFloat(weight, 64)
+				if err != nil {
+					return nil, errors.New("one or more scores can't be converted into double")
+				}
+				scores[element] = score
+			}
+		}
+
+		slices.SortStableFunc(elements, func(a, b string) int {
+			var res int
+			if options.alpha {
+				res = strings.Compare(weights[a], weights[b])
+			} else if scores[a] < scores[b] {
+				res = -1
+			} else if scores[a] > scores[b] {
+				res = 1
+			}
+			if res == 0 {
+				res = strings.Compare(a, b)
+			}
+			if options.desc {
+				return -res
+			}
+			return res
+		})
+	}
+
+	start := min(options.offset, len(elements))
+	end := len(elements)
+	if options.count >= 0 {
+		end = min(start+options.count, end)
+	}
+	elements = elements[start:end]
+
+	// Each GET pattern projects one value per element, and a missing value is returned as nil.
+	var res []*string
+	if len(options.get) == 0 {
+		res = make([]*string, len(elements))
+		for i := range elements {
+			res[i] = &elements[i]
+		}
+	} else {
+		projections := make([]map[string]*string, len(options.get))
+		for i, pattern := range options.get {
+			projections[i] = lookupSortPattern(params, pattern, elements)
+		}
+		res = make([]*string, 0, len(elements)*len(options.get))
+		for i := range elements {
+			for j, pattern := range options.get {
+				if pattern == "#" {
+					res = append(res, &elements[i])
+					continue
+				}
+				res = append(res, projections[j][elements[i]])
+			}
+		}
+	}
+
+	if options.store != "" {
+		values := make([]string, len(res))
+		for i, value := range res {
+			if value != nil {
+				values[i] = *value
+			}
+		}
+		if len(values) == 0 {
+			if params.KeysExist(params.Context, []string{options.store})[options.store] {
+				if err = params.DeleteKey(params.Context, options.store); err != nil {
+					return nil, err
+				}
+			}
+			return []byte(":0\r\n"), nil
+		}
+		if err = params.SetValues(params.Context, map[string]interface{}{
+			options.store: list.NewList(values...),
+		}); err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf(":%d\r\n", len(values))), nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%d\r\n", len(res)))
+	for _, value := range res {
+		if value == nil {
+			b.WriteString("$-1\r\n")
+			continue
+		}
+		b.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(*value), *value))
+	}
+	return []byte(b.String()), nil
+}
+
+func handleDump(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := dumpKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	if !params.KeysExist(params.Context, []string{key})[key] {
+		return []byte("$-1\r\n"), nil
+	}
+
+	payload, err := codec.EncodeDump(internal.KeyData{
+		Value:    params.GetValues(params.Context, []string{key})[key],
+		ExpireAt: params.GetExpiry(params.Context, key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(payload), payload)), nil
+}
+
+func handleRestore(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := restoreKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := getRestoreOptions(params.Command[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := strconv.ParseInt(params.Command[2], 10, 64)
+	if err != nil || ttl < 0 {
+		return nil, errors.New("invalid TTL value, must be >= 0")
+	}
+
+	data, err := codec.DecodeDump([]byte(params.Command[3]))
+	if err != nil {
+		return nil, errors.New("DUMP payload version or checksum are wrong")
+	}
+
+	key := keys.WriteKeys[0]
+	keyExists := params.KeysExist(params.Context, []string{key})[key]
+	if keyExists && !options.replace {
+		return nil, errors.New("BUSYKEY Target key name already exists.")
+	}
+
+	// A TTL of 0 keeps the expiry time recorded in the payload.
+	now := params.GetClock().Now()
+	expireAt := data.ExpireAt
+	if ttl > 0 && options.absTTL {
+		expireAt = time.UnixMilli(ttl)
+	} else if ttl > 0 {
+		expireAt = now.Add(time.Duration(ttl) * time.Millisecond)
+	}
+
+	if keyExists {
+		if err = params.DeleteKey(params.Context, key); err != nil {
+			return nil, err
+		}
+	}
+	// A key that has already expired is not restored.
+	if expireAt != (time.Time{}) && !expireAt.After(now) {
+		return []byte(constants.OkResponse), nil
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: data.Value}); err != nil {
+		return nil, err
+	}
+	if expireAt != (time.Time{}) {
+		params.SetExpiry(params.Context, key, expireAt, false)
+	}
+	if h, ok := data.Value.(*hash.Hash); ok {
+		for field, value := range h.All() {
+			if value.ExpireAt != (time.Time{}) {
+				if err = params.SetHashExpiry(params.Context, key, field, value.ExpireAt); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if options.idleTime >= 0 {
+		params.SetObjectIdleTime(params.Context, key, options.idleTime)
+	}
+	if options.freq >= 0 {
+		params.SetObjectFrequency(params.Context, key, options.freq)
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleMigrate(params internal.HandlerFuncParams) ([]byte, error) {
+	options, err := getMigrateOptions(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for key, exists := range params.KeysExist(params.Context, options.keys) {
+		if exists {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return []byte("+NOKEY\r\n"), nil
+	}
+	slices.Sort(keys)
+
+	conn, err := net.DialTimeout("tcp", options.address, options.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("IOERR error or timeout connecting to the client: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	reader := resp.NewReader(conn)
+
+	// send writes the command to the target and waits for its reply, failing if the target replies with an error.
+	send := func(cmd ...string) error {
+		if err := conn.SetDeadline(time.Now().Add(options.timeout)); err != nil {
+			return err
+		}
+		if _, err := conn.Write(internal.EncodeCommand(cmd)); err != nil {
+			return fmt.Errorf("IOERR error or timeout writing to target instance: %w", err)
+		}
+		res, _, err := reader.ReadValue()
+		if err != nil {
+			return fmt.Errorf("IOERR error or timeout reading from target instance: %w", err)
+		}
+		if res.Error() != nil {
+			return fmt.Errorf("target instance replied with error: %w", res.Error())
+		}
+		return nil
+	}
+
+	if options.username != "" {
+		err = send("AUTH", options.username, options.password)
+	} else if options.password != "" {
+		err = send("AUTH", options.password)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = send("SELECT", strconv.Itoa(options.database)); err != nil {
+		return nil, err
+	}
+
+	// Log the deletion of the migrated keys to the AOF rather than the migration, which must not run again
+	// when the AOF is replayed. The keys deleted before a failure are logged too.
+	var deleted []string
+	defer func() {
+		if len(deleted) > 0 {
+			internal.PropagateCommand(params.Context, append([]string{"DEL"}, deleted...))
+		}
+	}()
+
+	values := params.GetValues(params.Context, keys)
+	for _, key := range keys {
+		payload, err := codec.EncodeDump(internal.KeyData{
+			Value:    values[key],
+			ExpireAt: params.GetExpiry(params.Context, key),
+		})
+		if err != nil {
+			return nil, err
+		}
+		// The expiry time is carried by the payload, so the key is restored with a TTL of 0.
+		cmd := []string{"RESTORE", key, "0", string(payload)}
+		if options.replace {
+			cmd = append(cmd, "REPLACE")
+		}
+		if err = send(cmd...); err != nil {
+			return nil, err
+		}
+		if !options.copy {
+			// The key is only deleted if it still holds the migrated value, so that a write made during the round
+			// trip to the target is not lost. It's deleted through raft in cluster mode so that it's deleted on
+			// every node.
+			if err = params.ReplicateDeleteKey(params.Context, key, payload); err != nil {
+				return nil, err
+			}
+			deleted = append(deleted, key)
+		}
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -1456,5 +1840,83 @@ The REPLACE option removes the destination key before copying the value to it.`,
 			KeyExtractionFunc: keysKeyFunc,
 			HandlerFunc:       handleKeys,
 		},
+		{
+			Command:    "scan",
+			Module:     constants.GenericModule,
+			Categories: []string{constants.KeyspaceCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(SCAN cursor [MATCH pattern] [COUNT count] [TYPE type])
+Incrementally iterates over the keys in the current database. Start the iteration with a cursor of 0 and
+continue with the cursor returned by each call until it returns 0.
+COUNT is the number of keys visited by each call, 10 by default. MATCH and TYPE filter the visited keys,
+so a call can return fewer keys than COUNT.
+Every key that exists for the whole iteration is returned exactly once, even while other keys are modified.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: scanKeyFunc,
+			HandlerFunc:       handleScan,
+		},
+		{
+			Command:    "sort",
+			Module:     constants.GenericModule,
+			Categories: []string{constants.KeyspaceCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(SORT key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC] [ALPHA]
+[STORE destination]) Sorts the elements of the list, set or sorted set at key. The elements are compared as numbers
+unless ALPHA is provided. BY sorts the elements by the values of external keys, where the first "*" in the pattern is
+replaced by the element and "->field" refers to a hash field. GET returns the values of external keys instead of the
+elements, "#" returns the element itself. STORE saves the result as a list at destination and returns its length.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: sortKeyFunc,
+			HandlerFunc:       handleSORT,
+		},
+		{
+			Command:    "sort_ro",
+			Module:     constants.GenericModule,
+			Categories: []string{constants.KeyspaceCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(SORT_RO key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC] [ALPHA])
+Read-only variant of SORT that does not accept the STORE option.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: sortKeyFunc,
+			HandlerFunc:       handleSORT,
+		},
+		{
+			Command:    "dump",
+			Module:     constants.GenericModule,
+			Categories: []string{constants.KeyspaceCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(DUMP key) Serializes the value at key, together with its expiry time and the expiry times of hash fields.
+The returned payload is versioned and checksummed, and can be passed to RESTORE on this or another SugarDB instance.
+Returns nil if the key does not exist.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: dumpKeyFunc,
+			HandlerFunc:       handleDump,
+		},
+		{
+			Command:    "restore",
+			Module:     constants.GenericModule,
+			Categories: []string{constants.KeyspaceCategory, constants.WriteCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: `(RESTORE key ttl serialized-value [REPLACE] [ABSTTL] [IDLETIME seconds] [FREQ frequency])
+Creates the key from a payload returned by DUMP. A ttl of 0 keeps the expiry time recorded in the payload, otherwise
+the key expires after ttl milliseconds, or at the ttl unix time in milliseconds when ABSTTL is provided.
+REPLACE overwrites an existing key. IDLETIME and FREQ set the eviction metadata of the key under LRU and LFU policies.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: restoreKeyFunc,
+			HandlerFunc:       handleRestore,
+		},
+		{
+			Command:    "migrate",
+			Module:     constants.GenericModule,
+			Categories: []string{constants.KeyspaceCategory, constants.WriteCategory, constants.SlowCategory, constants.DangerousCategory},
+			Description: `(MIGRATE host port key | "" destination-db timeout [COPY] [REPLACE] [AUTH password | AUTH2 username password]
+[KEYS key [key ...]]) Moves keys to another SugarDB instance by restoring them on the target with RESTORE.
+The keys are deleted locally once the target acknowledges them, unless COPY is provided.
+The timeout is in milliseconds. Returns NOKEY if none of the keys exist.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: migrateKeyFunc,
+			HandlerFunc:       handleMigrate,
+		},
 	}
 }
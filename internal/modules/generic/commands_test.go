@@ -17,12 +17,14 @@ package generic_test
 import (
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 	"sort"
 	"reflect"
+	"slices"
 
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/clock"
@@ -4043,6 +4045,773 @@ func Test_Generic(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_HandleSCAN", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mockServer, err := sugardb.NewSugarDB(
+			sugardb.WithConfig(config.Config{
+				BindAddr:       "localhost",
+				Port:           uint16(port),
+				DataDir:        "",
+				EvictionPolicy: constants.NoEviction,
+			}),
+		)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			mockServer.Start()
+		}()
+		t.Cleanup(func() {
+			mockServer.ShutDown()
+		})
+
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		do := func(cmd ...string) (resp.Value, error) {
+			command := make([]resp.Value, len(cmd))
+			for i, c := range cmd {
+				command[i] = resp.StringValue(c)
+			}
+			if err := client.WriteArray(command); err != nil {
+				return resp.Value{}, err
+			}
+			res, _, err := client.ReadValue()
+			return res, err
+		}
+
+		// scanAll iterates over the whole keyspace, calling between after every call to SCAN.
+		scanAll := func(args []string, between func()) ([]string, error) {
+			var keys []string
+			cursor := "0"
+			for {
+				res, err := do(append([]string{"SCAN", cursor}, args...)...)
+				if err != nil {
+					return nil, err
+				}
+				if res.Error() != nil {
+					return nil, res.Error()
+				}
+				cursor = res.Array()[0].String()
+				for _, key := range res.Array()[1].Array() {
+					keys = append(keys, key.String())
+				}
+				if cursor == "0" {
+					return keys, nil
+				}
+				if between != nil {
+					between()
+				}
+			}
+		}
+
+		var expected []string
+		for i := 0; i < 50; i++ {
+			key := fmt.Sprintf("ScanKey%d", i)
+			if _, err = do("SET", key, "value"); err != nil {
+				t.Error(err)
+				return
+			}
+			expected = append(expected, key)
+		}
+		if _, err = do("LPUSH", "ScanListKey1", "value"); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err = do("SET", "ScanIntegerKey1", "10"); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name     string
+			args     []string
+			expected []string
+		}{
+			{
+				name:     "1. Iterate over all the keys with the default count",
+				args:     []string{},
+				expected: append([]string{"ScanListKey1", "ScanIntegerKey1"}, expected...),
+			},
+			{
+				name:     "2. Iterate over all the keys with a small count",
+				args:     []string{"COUNT", "3"},
+				expected: append([]string{"ScanListKey1", "ScanIntegerKey1"}, expected...),
+			},
+			{
+				name: "3. Only return keys that match the pattern",
+				args: []string{"MATCH", "ScanKey1*", "COUNT", "7"},
+				expected: []string{"ScanKey1", "ScanKey10", "ScanKey11", "ScanKey12", "ScanKey13", "ScanKey14",
+					"ScanKey15", "ScanKey16", "ScanKey17", "ScanKey18", "ScanKey19"},
+			},
+			{
+				name:     "4. Only return keys of the given type",
+				args:     []string{"TYPE", "list"},
+				expected: []string{"ScanListKey1"},
+			},
+			{
+				name:     "5. Integers match the string type",
+				args:     []string{"TYPE", "string", "MATCH", "ScanInt*"},
+				expected: []string{"ScanIntegerKey1"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				keys, err := scanAll(test.args, nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				sort.Strings(keys)
+				want := slices.Clone(test.expected)
+				sort.Strings(want)
+				if !reflect.DeepEqual(keys, want) {
+					t.Errorf("expected keys %v, got %v", want, keys)
+				}
+			})
+		}
+
+		t.Run("6. Return every key that is present for the whole iteration exactly once", func(t *testing.T) {
+			added := 0
+			keys, err := scanAll([]string{"COUNT", "5", "MATCH", "ScanKey*"}, func() {
+				// Add and delete keys between calls.
+				_, _ = do("SET", fmt.Sprintf("ScanKeyNew%d", added), "value")
+				_, _ = do("DEL", fmt.Sprintf("ScanKeyNew%d", added-1))
+				added++
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			seen := make(map[string]int)
+			for _, key := range keys {
+				seen[key]++
+			}
+			for _, key := range expected {
+				if seen[key] != 1 {
+					t.Errorf("expected key %s to be returned once, got %d", key, seen[key])
+				}
+			}
+		})
+
+		t.Run("7. Return error when the cursor is invalid", func(t *testing.T) {
+			res, err := do("SCAN", "abc")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), "invalid cursor") {
+				t.Errorf("expected invalid cursor error, got %v", res)
+			}
+		})
+
+		t.Run("8. Return error when an option has no value", func(t *testing.T) {
+			res, err := do("SCAN", "0", "COUNT")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), constants.WrongArgsResponse) {
+				t.Errorf("expected error \"%s\", got %v", constants.WrongArgsResponse, res)
+			}
+		})
+	})
+
+	t.Run("Test_HandleSORT", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		do := func(cmd ...string) (resp.Value, error) {
+			command := make([]resp.Value, len(cmd))
+			for i, c := range cmd {
+				command[i] = resp.StringValue(c)
+			}
+			if err := client.WriteArray(command); err != nil {
+				return resp.Value{}, err
+			}
+			res, _, err := client.ReadValue()
+			return res, err
+		}
+
+		setup := [][]string{
+			{"RPUSH", "SortKeyList", "3", "1", "2", "10"},
+			{"SADD", "SortKeySet", "banana", "apple", "cherry"},
+			{"ZADD", "SortKeyZSet", "1", "c", "2", "b", "3", "a"},
+			{"SET", "SortKeyWeight_3", "1"},
+			{"SET", "SortKeyWeight_1", "30"},
+			{"SET", "SortKeyWeight_2", "20"},
+			{"SET", "SortKeyWeight_10", "10"},
+			{"SET", "SortKeyName_1", "one"},
+			{"SET", "SortKeyName_2", "two"},
+			{"SET", "SortKeyName_3", "three"},
+			{"HSET", "SortKeyHash_a", "rank", "2", "label", "A"},
+			{"HSET", "SortKeyHash_b", "rank", "3", "label", "B"},
+			{"HSET", "SortKeyHash_c", "rank", "1"},
+			{"SET", "SortKeyString", "value"},
+		}
+		for _, cmd := range setup {
+			if res, err := do(cmd...); err != nil || res.Error() != nil {
+				t.Errorf("setup command %v failed: %v %v", cmd, err, res.Error())
+				return
+			}
+		}
+
+		tests := []struct {
+			name        string
+			command     []string
+			expected    []string
+			expectedErr string
+		}{
+			{
+				name:     "1. Sort the elements of a list numerically",
+				command:  []string{"SORT", "SortKeyList"},
+				expected: []string{"1", "2", "3", "10"},
+			},
+			{
+				name:     "2. Sort the elements of a list in descending order",
+				command:  []string{"SORT", "SortKeyList", "DESC"},
+				expected: []string{"10", "3", "2", "1"},
+			},
+			{
+				name:     "3. Sort the elements lexicographically with ALPHA",
+				command:  []string{"SORT", "SortKeyList", "ALPHA"},
+				expected: []string{"1", "10", "2", "3"},
+			},
+			{
+				name:     "4. Sort the members of a set with ALPHA",
+				command:  []string{"SORT_RO", "SortKeySet", "ALPHA", "DESC"},
+				expected: []string{"cherry", "banana", "apple"},
+			},
+			{
+				name:     "5. Apply LIMIT to the sorted elements",
+				command:  []string{"SORT", "SortKeyList", "LIMIT", "1", "2"},
+				expected: []string{"2", "3"},
+			},
+			{
+				name:     "6. Sort by the values of external keys",
+				command:  []string{"SORT", "SortKeyList", "BY", "SortKeyWeight_*"},
+				expected: []string{"3", "10", "2", "1"},
+			},
+			{
+				name:     "7. Project the values of external keys with GET",
+				command:  []string{"SORT", "SortKeyList", "GET", "#", "GET", "SortKeyName_*"},
+				expected: []string{"1", "one", "2", "two", "3", "three", "10", ""},
+			},
+			{
+				name:     "8. Sort a sorted set by hash fields and project another field",
+				command:  []string{"SORT", "SortKeyZSet", "BY", "SortKeyHash_*->rank", "GET", "SortKeyHash_*->label"},
+				expected: []string{"", "A", "B"},
+			},
+			{
+				name:     "9. Keep the stored order when BY refers to a constant key",
+				command:  []string{"SORT", "SortKeyZSet", "BY", "nosort"},
+				expected: []string{"c", "b", "a"},
+			},
+			{
+				name:     "10. Return an empty array when the key does not exist",
+				command:  []string{"SORT", "SortKeyMissing"},
+				expected: []string{},
+			},
+			{
+				name:        "11. Return error when the elements are not numbers",
+				command:     []string{"SORT", "SortKeySet"},
+				expectedErr: "one or more scores can't be converted into double",
+			},
+			{
+				name:        "12. Return error when the key does not hold a list, set or sorted set",
+				command:     []string{"SORT", "SortKeyString"},
+				expectedErr: "value at SortKeyString is not a list, set or sorted set",
+			},
+			{
+				name:        "13. Return error when SORT_RO is called with STORE",
+				command:     []string{"SORT_RO", "SortKeyList", "STORE", "SortKeyDestination"},
+				expectedErr: "STORE option is not allowed with SORT_RO",
+			},
+			{
+				name:        "14. Return error when an option is unknown",
+				command:     []string{"SORT", "SortKeyList", "SHUFFLE"},
+				expectedErr: "unknown option SHUFFLE for sort command",
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				res, err := do(test.command...)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if test.expectedErr != "" {
+					if res.Error() == nil || !strings.Contains(res.Error().Error(), test.expectedErr) {
+						t.Errorf("expected error \"%s\", got %v", test.expectedErr, res)
+					}
+					return
+				}
+				elements := make([]string, len(res.Array()))
+				for i, value := range res.Array() {
+					elements[i] = value.String()
+				}
+				if !reflect.DeepEqual(elements, test.expected) {
+					t.Errorf("expected %v, got %v", test.expected, elements)
+				}
+			})
+		}
+
+		t.Run("15. Store the sorted elements as a list", func(t *testing.T) {
+			res, err := do("SORT", "SortKeyList", "DESC", "STORE", "SortKeyDestination")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Integer() != 4 {
+				t.Errorf("expected stored count 4, got %v", res)
+			}
+			res, err = do("LRANGE", "SortKeyDestination", "0", "-1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			elements := make([]string, len(res.Array()))
+			for i, value := range res.Array() {
+				elements[i] = value.String()
+			}
+			if !reflect.DeepEqual(elements, []string{"10", "3", "2", "1"}) {
+				t.Errorf("expected stored list [10 3 2 1], got %v", elements)
+			}
+
+			// An empty result deletes the destination.
+			res, err = do("SORT", "SortKeyMissing", "STORE", "SortKeyDestination")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Integer() != 0 {
+				t.Errorf("expected stored count 0, got %v", res)
+			}
+			res, err = do("EXISTS", "SortKeyDestination")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Integer() != 0 {
+				t.Errorf("expected destination to be deleted, got %v", res)
+			}
+		})
+	})
+
+	t.Run("Test_HandleDUMP_RESTORE", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		do := func(cmd ...string) (resp.Value, error) {
+			command := make([]resp.Value, len(cmd))
+			for i, c := range cmd {
+				command[i] = resp.StringValue(c)
+			}
+			if err := client.WriteArray(command); err != nil {
+				return resp.Value{}, err
+			}
+			res, _, err := client.ReadValue()
+			return res, err
+		}
+
+		setup := [][]string{
+			{"SET", "DumpKeyString", "value", "EX", "100"},
+			{"RPUSH", "DumpKeyList", "a", "b", "c"},
+			{"HSET", "DumpKeyHash", "field1", "value1", "field2", "value2"},
+			{"HEXPIRE", "DumpKeyHash", "50", "FIELDS", "1", "field1"},
+			{"ZADD", "DumpKeyZSet", "1", "one", "2", "two"},
+			{"SET", "DumpKeyExisting", "old"},
+		}
+		for _, cmd := range setup {
+			if res, err := do(cmd...); err != nil || res.Error() != nil {
+				t.Errorf("setup command %v failed: %v %v", cmd, err, res.Error())
+				return
+			}
+		}
+
+		dump := func(key string) string {
+			res, err := do("DUMP", key)
+			if err != nil {
+				t.Error(err)
+			}
+			return res.String()
+		}
+
+		tests := []struct {
+			name   string
+			key    string
+			verify [][]string // Commands run against the restored key, each followed by its expected reply
+		}{
+			{
+				name:   "1. Restore a string with its expiry time",
+				key:    "DumpKeyString",
+				verify: [][]string{{"GET", "value"}, {"TTL", "100"}},
+			},
+			{
+				name:   "2. Restore a list",
+				key:    "DumpKeyList",
+				verify: [][]string{{"LINDEX", "2", "c"}, {"LLEN", "3"}},
+			},
+			{
+				name:   "3. Restore a hash with its field expiry times",
+				key:    "DumpKeyHash",
+				verify: [][]string{{"HGET", "field2", "[value2]"}, {"HTTL", "FIELDS", "1", "field1", "[50]"}},
+			},
+			{
+				name:   "4. Restore a sorted set",
+				key:    "DumpKeyZSet",
+				verify: [][]string{{"ZSCORE", "two", "2"}},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				payload := dump(test.key)
+				restored := test.key + "RestoredKey"
+				res, err := do("RESTORE", restored, "0", payload)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if res.Error() != nil || res.String() != "OK" {
+					t.Errorf("expected RESTORE to reply OK, got %v", res)
+					return
+				}
+				for _, verify := range test.verify {
+					cmd := append([]string{verify[0], restored}, verify[1:len(verify)-1]...)
+					res, err = do(cmd...)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					got := res.String()
+					if res.Type() == resp.Array {
+						got = fmt.Sprint(res.Array())
+					}
+					if want := verify[len(verify)-1]; got != want {
+						t.Errorf("expected %v to reply %s, got %s", cmd, want, got)
+					}
+				}
+			})
+		}
+
+		t.Run("5. Return nil when dumping a key that does not exist", func(t *testing.T) {
+			res, err := do("DUMP", "DumpKeyMissing")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !res.IsNull() {
+				t.Errorf("expected nil, got %v", res)
+			}
+		})
+
+		t.Run("6. Only overwrite an existing key with REPLACE", func(t *testing.T) {
+			payload := dump("DumpKeyList")
+			res, err := do("RESTORE", "DumpKeyExisting", "0", payload)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), "BUSYKEY") {
+				t.Errorf("expected BUSYKEY error, got %v", res)
+			}
+			res, err = do("RESTORE", "DumpKeyExisting", "5000", payload, "REPLACE")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Error() != nil {
+				t.Error(res.Error())
+				return
+			}
+			res, err = do("PTTL", "DumpKeyExisting")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Integer() != 5000 {
+				t.Errorf("expected PTTL 5000, got %v", res)
+			}
+		})
+
+		t.Run("7. Do not restore a key whose absolute expiry time has passed", func(t *testing.T) {
+			res, err := do("RESTORE", "DumpKeyExpired", "1000", dump("DumpKeyList"), "ABSTTL")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.String() != "OK" {
+				t.Errorf("expected OK, got %v", res)
+			}
+			res, err = do("EXISTS", "DumpKeyExpired")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Integer() != 0 {
+				t.Errorf("expected key not to be restored, got %v", res)
+			}
+		})
+
+		t.Run("8. Reject a corrupted payload", func(t *testing.T) {
+			payload := []byte(dump("DumpKeyList"))
+			payload[len(payload)/2] ^= 0xff
+			res, err := do("RESTORE", "DumpKeyCorrupted", "0", string(payload))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), "DUMP payload version or checksum are wrong") {
+				t.Errorf("expected payload error, got %v", res)
+			}
+		})
+	})
+
+	t.Run("Test_HandleMIGRATE", func(t *testing.T) {
+		t.Parallel()
+
+		targetPort, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		target, err := sugardb.NewSugarDB(
+			sugardb.WithConfig(config.Config{
+				BindAddr:       "localhost",
+				Port:           uint16(targetPort),
+				DataDir:        "",
+				EvictionPolicy: constants.NoEviction,
+			}),
+		)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			target.Start()
+		}()
+		t.Cleanup(func() {
+			target.ShutDown()
+		})
+
+		connect := func(port int) (func(cmd ...string) (resp.Value, error), error) {
+			conn, err := internal.GetConnection("localhost", port)
+			if err != nil {
+				return nil, err
+			}
+			t.Cleanup(func() {
+				_ = conn.Close()
+			})
+			client := resp.NewConn(conn)
+			return func(cmd ...string) (resp.Value, error) {
+				command := make([]resp.Value, len(cmd))
+				for i, c := range cmd {
+					command[i] = resp.StringValue(c)
+				}
+				if err := client.WriteArray(command); err != nil {
+					return resp.Value{}, err
+				}
+				res, _, err := client.ReadValue()
+				return res, err
+			}, nil
+		}
+		do, err := connect(port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		doTarget, err := connect(targetPort)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		for _, cmd := range [][]string{
+			{"SET", "MigrateKey1", "value1"},
+			{"RPUSH", "MigrateKey2", "a", "b"},
+			{"SET", "MigrateKey3", "value3"},
+		} {
+			if res, err := do(cmd...); err != nil || res.Error() != nil {
+				t.Errorf("setup command %v failed: %v %v", cmd, err, res.Error())
+				return
+			}
+		}
+
+		targetPortString := strconv.Itoa(targetPort)
+		tests := []struct {
+			name       string
+			command    []string
+			expected   string
+			sourceKeys map[string]bool // Whether each key exists on the source after the migration
+			targetKeys map[string]bool // Whether each key exists in database 1 of the target after the migration
+		}{
+			{
+				name:       "1. Migrate a single key",
+				command:    []string{"MIGRATE", "localhost", targetPortString, "MigrateKey1", "1", "1000"},
+				expected:   "OK",
+				sourceKeys: map[string]bool{"MigrateKey1": false},
+				targetKeys: map[string]bool{"MigrateKey1": true},
+			},
+			{
+				name: "2. Copy several keys with the KEYS option",
+				command: []string{"MIGRATE", "localhost", targetPortString, "", "1", "1000", "COPY",
+					"KEYS", "MigrateKey2", "MigrateKey3", "MigrateKeyMissing"},
+				expected:   "OK",
+				sourceKeys: map[string]bool{"MigrateKey2": true, "MigrateKey3": true},
+				targetKeys: map[string]bool{"MigrateKey2": true, "MigrateKey3": true, "MigrateKeyMissing": false},
+			},
+			{
+				name:     "3. Return NOKEY when none of the keys exist",
+				command:  []string{"MIGRATE", "localhost", targetPortString, "MigrateKeyMissing", "1", "1000"},
+				expected: "NOKEY",
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				res, err := do(test.command...)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if res.Error() != nil || res.String() != test.expected {
+					t.Errorf("expected %s, got %v", test.expected, res)
+					return
+				}
+				for key, want := range test.sourceKeys {
+					res, _ = do("EXISTS", key)
+					if (res.Integer() == 1) != want {
+						t.Errorf("expected key %s to exist on the source: %v", key, want)
+					}
+				}
+				if _, err = doTarget("SELECT", "1"); err != nil {
+					t.Error(err)
+					return
+				}
+				for key, want := range test.targetKeys {
+					res, _ = doTarget("EXISTS", key)
+					if (res.Integer() == 1) != want {
+						t.Errorf("expected key %s to exist on the target: %v", key, want)
+					}
+				}
+			})
+		}
+
+		t.Run("4. Return the error of the target without deleting the key", func(t *testing.T) {
+			res, err := do("MIGRATE", "localhost", targetPortString, "MigrateKey3", "1", "1000")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), "BUSYKEY") {
+				t.Errorf("expected BUSYKEY error, got %v", res)
+			}
+			res, _ = do("EXISTS", "MigrateKey3")
+			if res.Integer() != 1 {
+				t.Error("expected MigrateKey3 to remain on the source")
+			}
+
+			res, err = do("MIGRATE", "localhost", targetPortString, "MigrateKey3", "1", "1000", "REPLACE")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.String() != "OK" {
+				t.Errorf("expected OK with REPLACE, got %v", res)
+			}
+		})
+
+		t.Run("5. Keep a key that is written while it's restored on the target", func(t *testing.T) {
+			if res, err := do("SET", "MigrateKey4", "value4"); err != nil || res.Error() != nil {
+				t.Errorf("setup command failed: %v %v", err, res.Error())
+				return
+			}
+			doWriter, err := connect(port)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			// The target writes to the key on the source before it acknowledges the RESTORE.
+			listener, err := net.Listen("tcp", "localhost:0")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			t.Cleanup(func() {
+				_ = listener.Close()
+			})
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer func() {
+					_ = conn.Close()
+				}()
+				reader := resp.NewReader(conn)
+				for {
+					cmd, _, err := reader.ReadValue()
+					if err != nil {
+						return
+					}
+					if strings.EqualFold(cmd.Array()[0].String(), "RESTORE") {
+						_, _ = doWriter("SET", "MigrateKey4", "value4-updated")
+					}
+					if _, err = conn.Write([]byte("+OK\r\n")); err != nil {
+						return
+					}
+				}
+			}()
+
+			fakePort := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+			res, err := do("MIGRATE", "localhost", fakePort, "MigrateKey4", "1", "1000")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), "was modified") {
+				t.Errorf("expected error for the modified key, got %v", res)
+			}
+			res, _ = do("GET", "MigrateKey4")
+			if res.String() != "value4-updated" {
+				t.Errorf("expected MigrateKey4 to keep the value written during the migration, got %v", res)
+			}
+		})
+	})
 }
 
 // Certain commands will need to be tested in a server with an eviction policy.
@@ -16,6 +16,7 @@ package generic
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/constants"
@@ -330,3 +331,95 @@ func keysKeyFunc(cmd []string) (internal.KeyExtractionFuncResult, error) {
 		ReadKeys: cmd[1:2],
 	}, nil
 }
+
+func scanKeyFunc(cmd []string) (internal.KeyExtractionFuncResult, error) {
+	if len(cmd) < 2 || len(cmd)%2 != 0 {
+		return internal.KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+	}
+	return internal.KeyExtractionFuncResult{
+		Channels:  make([]string, 0),
+		ReadKeys:  make([]string, 0),
+		WriteKeys: make([]string, 0),
+	}, nil
+}
+
+func sortKeyFunc(cmd []string) (internal.KeyExtractionFuncResult, error) {
+	if len(cmd) < 2 {
+		return internal.KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+	}
+	options, err := getSortOptions(cmd[2:])
+	if err != nil {
+		return internal.KeyExtractionFuncResult{}, err
+	}
+
+	// The keys referenced by a BY or GET pattern containing "*" are not known until the elements are sorted,
+	// so the command requires read access to all keys. A GET pattern without "*" always refers to the same key.
+	readKeys := []string{cmd[1]}
+	allReadKeys := strings.Contains(options.by, "*")
+	for _, pattern := range options.get {
+		switch {
+		case pattern == "#":
+		case strings.Contains(pattern, "*"):
+			allReadKeys = true
+		default:
+			readKeys = append(readKeys, pattern)
+		}
+	}
+
+	writeKeys := make([]string, 0)
+	if options.store != "" {
+		if strings.EqualFold(cmd[0], "sort_ro") {
+			return internal.KeyExtractionFuncResult{}, errors.New("STORE option is not allowed with SORT_RO")
+		}
+		writeKeys = append(writeKeys, options.store)
+	}
+
+	return internal.KeyExtractionFuncResult{
+		Channels:    make([]string, 0),
+		ReadKeys:    readKeys,
+		WriteKeys:   writeKeys,
+		AllReadKeys: allReadKeys,
+	}, nil
+}
+
+func dumpKeyFunc(cmd []string) (internal.KeyExtractionFuncResult, error) {
+	if len(cmd) != 2 {
+		return internal.KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+	}
+	return internal.KeyExtractionFuncResult{
+		Channels:  make([]string, 0),
+		ReadKeys:  cmd[1:],
+		WriteKeys: make([]string, 0),
+	}, nil
+}
+
+func restoreKeyFunc(cmd []string) (internal.KeyExtractionFuncResult, error) {
+	if len(cmd) < 4 {
+		return internal.KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+	}
+	return internal.KeyExtractionFuncResult{
+		Channels:  make([]string, 0),
+		ReadKeys:  make([]string, 0),
+		WriteKeys: cmd[1:2],
+	}, nil
+}
+
+func migrateKeyFunc(cmd []string) (internal.KeyExtractionFuncResult, error) {
+	options, err := getMigrateOptions(cmd)
+	if err != nil {
+		return internal.KeyExtractionFuncResult{}, err
+	}
+	// The keys are deleted after they're migrated, unless COPY is provided.
+	if options.copy {
+		return internal.KeyExtractionFuncResult{
+			Channels:  make([]string, 0),
+			ReadKeys:  options.keys,
+			WriteKeys: make([]string, 0),
+		}, nil
+	}
+	return internal.KeyExtractionFuncResult{
+		Channels:  make([]string, 0),
+		ReadKeys:  make([]string, 0),
+		WriteKeys: options.keys,
+	}, nil
+}
@@ -17,7 +17,20 @@ package generic
 import (
 	"errors"
 	"fmt"
+	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/clock"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/hyperloglog"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+	"github.com/echovault/sugardb/internal/modules/list"
+	"github.com/echovault/sugardb/internal/modules/probabilistic"
+	"github.com/echovault/sugardb/internal/modules/set"
+	"github.com/echovault/sugardb/internal/modules/sorted_set"
+	"github.com/echovault/sugardb/internal/modules/stream"
+	"github.com/echovault/sugardb/internal/modules/timeseries"
+	"github.com/echovault/sugardb/internal/modules/vector_set"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +42,41 @@ type SetOptions struct {
 	expireAt interface{} // Exact expireAt time un unix milliseconds
 }
 
+type ScanOptions struct {
+	cursor  uint64
+	match   string
+	count   int
+	keyType string
+}
+
+type SortOptions struct {
+	by     string   // Pattern of the keys whose values are used as the weights of the elements
+	get    []string // Patterns of the keys whose values are returned instead of the elements
+	offset int
+	count  int // Negative when all the elements after the offset are returned
+	desc   bool
+	alpha  bool
+	store  string // Destination key of the sorted elements
+}
+
+type RestoreOptions struct {
+	replace  bool
+	absTTL   bool
+	idleTime time.Duration // Negative when IDLETIME is not provided
+	freq     int           // Negative when FREQ is not provided
+}
+
+type MigrateOptions struct {
+	address  string
+	keys     []string
+	database int
+	timeout  time.Duration
+	copy     bool
+	replace  bool
+	username string // Empty when the password is sent with AUTH instead of AUTH2
+	password string
+}
+
 type CopyOptions struct {
 	database string
 	replace bool
@@ -151,96 +199,223 @@ func getCopyCommandOptions(cmd []string, options CopyOptions) (CopyOptions, erro
 	}
 }
 
-func matchPattern(pattern string, key string) bool {
-	/*
-		Implementation of Redis-style pattern matching
-		https://redis.io/docs/latest/commands/keys/
-	*/
-	patternLen := len(pattern)
-	keyLen := len(key) // length of the key to match
-	patternPos := 0 // position in the pattern
-	keyPos := 0 // position in the key
-
-	for patternPos < patternLen {
-		switch pattern[patternPos] {
-		case '\\': // Match characters verbatum after slash
-			if patternPos+1 < patternLen {
-				patternPos++
-				if keyPos >= keyLen || pattern[patternPos] != key[keyPos] {
-					return false
-				}
-				keyPos++
+func getRestoreOptions(cmd []string) (RestoreOptions, error) {
+	options := RestoreOptions{idleTime: -1, freq: -1}
+	for i := 0; i < len(cmd); i++ {
+		switch strings.ToLower(cmd[i]) {
+		case "replace":
+			options.replace = true
+		case "absttl":
+			options.absTTL = true
+		case "idletime", "freq":
+			if i+1 >= len(cmd) {
+				return RestoreOptions{}, errors.New("syntax error")
 			}
-		case '?': // Match any single character (skip key position)
-			// key position is at the end, return false
-			if keyPos >= keyLen {
-				return false
+			n, err := strconv.Atoi(cmd[i+1])
+			if err != nil || n < 0 {
+				return RestoreOptions{}, fmt.Errorf("invalid %s value, must be >= 0", strings.ToUpper(cmd[i]))
 			}
-			keyPos++
-		case '*': // Match any sequence of characters
-			// If pattern is at the end, return true
-			if patternPos+1 >= patternLen {
-				return true
+			if strings.EqualFold(cmd[i], "idletime") {
+				options.idleTime = time.Duration(n) * time.Second
+			} else {
+				options.freq = n
 			}
-			// Use recursion to match the rest of the pattern at each position
-			for i := keyPos; i <= keyLen; i++ {
-				if matchPattern(pattern[patternPos+1:], key[i:]) {
-					return true
-				}
+			i++
+		default:
+			return RestoreOptions{}, errors.New("syntax error")
+		}
+	}
+	if options.idleTime >= 0 && options.freq >= 0 {
+		return RestoreOptions{}, errors.New("IDLETIME and FREQ options are mutually exclusive")
+	}
+	return options, nil
+}
+
+// getMigrateOptions parses the whole MIGRATE command, including the target address, key, database and timeout.
+func getMigrateOptions(cmd []string) (MigrateOptions, error) {
+	if len(cmd) < 6 {
+		return MigrateOptions{}, errors.New(constants.WrongArgsResponse)
+	}
+	if _, err := strconv.ParseUint(cmd[2], 10, 16); err != nil {
+		return MigrateOptions{}, errors.New("port must be an integer between 0 and 65535")
+	}
+	database, err := strconv.Atoi(cmd[4])
+	if err != nil || database < 0 {
+		return MigrateOptions{}, errors.New("database must be a non-negative integer")
+	}
+	timeout, err := strconv.Atoi(cmd[5])
+	if err != nil {
+		return MigrateOptions{}, errors.New("timeout must be an integer")
+	}
+	if timeout <= 0 {
+		timeout = 1000
+	}
+
+	options := MigrateOptions{
+		address:  net.JoinHostPort(cmd[1], cmd[2]),
+		database: database,
+		timeout:  time.Duration(timeout) * time.Millisecond,
+	}
+	for i := 6; i < len(cmd); i++ {
+		switch strings.ToLower(cmd[i]) {
+		case "copy":
+			options.copy = true
+		case "replace":
+			options.replace = true
+		case "auth":
+			if i+1 >= len(cmd) {
+				return MigrateOptions{}, errors.New("syntax error")
 			}
-			return false
-		case '[': // Match any character in the character class brackets []
-			// key position is at the end, return false
-			if keyPos >= keyLen {
-				return false
+			options.password = cmd[i+1]
+			i++
+		case "auth2":
+			if i+2 >= len(cmd) {
+				return MigrateOptions{}, errors.New("syntax error")
 			}
-			patternPos++ // skip the [ character
-			// check if character class is negated (^)
-			negate := false
-			if patternPos < patternLen && pattern[patternPos] == '^' {
-				negate = true
-				patternPos++
+			options.username, options.password = cmd[i+1], cmd[i+2]
+			i += 2
+		case "keys":
+			if cmd[3] != "" {
+				return MigrateOptions{}, errors.New("when using MIGRATE KEYS option, the key argument must be set to the empty string")
 			}
+			options.keys = cmd[i+1:]
+			i = len(cmd)
+		default:
+			return MigrateOptions{}, errors.New("syntax error")
+		}
+	}
+	if cmd[3] != "" {
+		options.keys = []string{cmd[3]}
+	}
+	if len(options.keys) == 0 {
+		return MigrateOptions{}, errors.New(constants.WrongArgsResponse)
+	}
+	return options, nil
+}
 
-			// look through all characters in the character class
-			matched := false
-			for patternPos < patternLen && pattern[patternPos] != ']' {
-				// if character is escaped, check the next character
-				if pattern[patternPos] == '\\' && patternPos+1 < patternLen {
-					patternPos++
-					if pattern[patternPos] == key[keyPos] {
-						matched = true
-					}
-				// if character is a range, check if the key position is within the range
-				} else if patternPos+2 < patternLen && pattern[patternPos+1] == '-' {
-					// Handle range
-					if key[keyPos] >= pattern[patternPos] && key[keyPos] <= pattern[patternPos+2] {
-						matched = true
-					}
-					patternPos += 2
-				// if character is a match, set matched to true
-				} else if pattern[patternPos] == key[keyPos] {
-					matched = true
-				}
-				patternPos++
+func getSortOptions(cmd []string) (SortOptions, error) {
+	options := SortOptions{count: -1}
+	for i := 0; i < len(cmd); i++ {
+		option := strings.ToLower(cmd[i])
+		switch option {
+		case "asc":
+			options.desc = false
+		case "desc":
+			options.desc = true
+		case "alpha":
+			options.alpha = true
+		case "by", "get", "store":
+			if i+1 >= len(cmd) {
+				return SortOptions{}, errors.New("syntax error")
+			}
+			i++
+			switch option {
+			case "by":
+				options.by = cmd[i]
+			case "get":
+				options.get = append(options.get, cmd[i])
+			default:
+				options.store = cmd[i]
 			}
-			// if pattern position is at the end, return false
-			if patternPos >= patternLen {
-				return false
+		case "limit":
+			if i+2 >= len(cmd) {
+				return SortOptions{}, errors.New("syntax error")
 			}
-			// negate check: if matched is true and negate is true, return false
-			if matched == negate {
-				return false
+			offset, err := strconv.Atoi(cmd[i+1])
+			if err != nil {
+				return SortOptions{}, errors.New("value is not an integer or out of range")
 			}
-			keyPos++
-		default: // Match literal character (just like slash but on the current key position)
-			if keyPos >= keyLen || pattern[patternPos] != key[keyPos] {
-				return false
+			count, err := strconv.Atoi(cmd[i+2])
+			if err != nil {
+				return SortOptions{}, errors.New("value is not an integer or out of range")
+			}
+			options.offset, options.count = max(offset, 0), count
+			i += 2
+		default:
+			return SortOptions{}, fmt.Errorf("unknown option %s for sort command", strings.ToUpper(cmd[i]))
+		}
+	}
+	return options, nil
+}
+
+// splitSortPattern splits a BY or GET pattern into the key pattern and the hash field it refers to.
+// The field is empty when the pattern refers to the value of a key rather than a hash field.
+func splitSortPattern(pattern string) (string, string) {
+	star := strings.Index(pattern, "*")
+	arrow := strings.LastIndex(pattern, "->")
+	if star == -1 || arrow < star || arrow+2 == len(pattern) {
+		return pattern, ""
+	}
+	return pattern[:arrow], pattern[arrow+2:]
+}
+
+func getScanOptions(cmd []string) (ScanOptions, error) {
+	cursor, err := internal.ParseScanCursor(cmd[1])
+	if err != nil {
+		return ScanOptions{}, err
+	}
+	options := ScanOptions{cursor: cursor, count: internal.DefaultScanCount}
+	for i := 2; i < len(cmd); i += 2 {
+		switch strings.ToLower(cmd[i]) {
+		case "match":
+			options.match = cmd[i+1]
+		case "count":
+			if options.count, err = internal.ParseScanCount(cmd[i+1]); err != nil {
+				return ScanOptions{}, err
 			}
-			keyPos++
+		case "type":
+			options.keyType = strings.ToLower(cmd[i+1])
+		default:
+			return ScanOptions{}, fmt.Errorf("unknown option %s", cmd[i])
 		}
-		patternPos++
 	}
+	return options, nil
+}
+
+// typeName returns the name of the value's type as reported by the TYPE command.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case string, *hyperloglog.HyperLogLog:
+		return "string"
+	case int:
+		return "integer"
+	case float64:
+		return "float"
+	case *list.List:
+		return "list"
+	case *hash.Hash:
+		return "hash"
+	case *set.Set:
+		return "set"
+	case *sorted_set.SortedSet:
+		return "zset"
+	case *stream.Stream:
+		return "stream"
+	case *probabilistic.BloomFilter:
+		return "MBbloom--"
+	case *probabilistic.CuckooFilter:
+		return "MBbloomCF"
+	case *probabilistic.CountMinSketch:
+		return "CMSk-TYPE"
+	case *probabilistic.TopK:
+		return "TopK-TYPE"
+	case *jsondoc.Document:
+		return "ReJSON-RL"
+	case *timeseries.TimeSeries:
+		return "TSDB-TYPE"
+	case *vector_set.VectorSet:
+		return "vectorset"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
 
-	return keyPos == keyLen
+// matchType checks whether the value has the type given to the TYPE option of SCAN.
+// Integers and floats are stored as strings by the client, so they also match "string".
+func matchType(keyType string, value interface{}) bool {
+	name := typeName(value)
+	if name == keyType {
+		return true
+	}
+	return keyType == "string" && (name == "integer" || name == "float")
 }
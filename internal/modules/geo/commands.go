@@ -0,0 +1,393 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/internal/modules/sorted_set"
+)
+
+func handleGEOADD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := geoaddKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+
+	policy := ""
+	changed := false
+	i := 2
+	for ; i < len(params.Command); i++ {
+		option := strings.ToLower(params.Command[i])
+		if option == "nx" || option == "xx" {
+			if policy != "" && policy != option {
+				return nil, errors.New("XX and NX options at the same time are not compatible")
+			}
+			policy = option
+			continue
+		}
+		if option == "ch" {
+			changed = true
+			continue
+		}
+		break
+	}
+
+	args := params.Command[i:]
+	if len(args) == 0 || len(args)%3 != 0 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	members := make([]sorted_set.MemberParam, 0, len(args)/3)
+	for j := 0; j < len(args); j += 3 {
+		coords, err := parseCoordinates(args[j], args[j+1])
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, sorted_set.MemberParam{
+			Value: sorted_set.Value(args[j+2]),
+			Score: sorted_set.Score(encodeGeohash(coords)),
+		})
+	}
+
+	set, err := getSortedSet(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		if policy == "xx" {
+			return []byte(":0\r\n"), nil
+		}
+		set = sorted_set.NewSortedSet([]sorted_set.MemberParam{})
+	}
+
+	// Count the added members, and the updated members if CH is provided.
+	count := 0
+	for _, member := range members {
+		existing := set.Get(member.Value)
+		if !existing.Exists && policy != "xx" {
+			count++
+		} else if existing.Exists && changed && policy != "nx" && existing.Score != member.Score {
+			count++
+		}
+	}
+
+	var updatePolicy interface{}
+	if policy != "" {
+		updatePolicy = policy
+	}
+	if _, err = set.AddOrUpdate(members, updatePolicy, nil, nil, nil); err != nil {
+		return nil, err
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: set}); err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+}
+
+func handleGEOPOS(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := geoposKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := getSortedSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	members := params.Command[2:]
+
+	var res strings.Builder
+	res.WriteString(fmt.Sprintf("*%d\r\n", len(members)))
+	for _, member := range members {
+		coords, _, ok := memberCoordinates(set, member)
+		if !ok {
+			res.WriteString("*-1\r\n")
+			continue
+		}
+		res.WriteString("*2\r\n")
+		writeBulkString(&res, formatFloat(coords.Longitude))
+		writeBulkString(&res, formatFloat(coords.Latitude))
+	}
+
+	return []byte(res.String()), nil
+}
+
+func handleGEODIST(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := geodistKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	unit := "m"
+	if len(params.Command) == 5 {
+		unit = params.Command[4]
+	}
+	meters, ok := units[strings.ToLower(unit)]
+	if !ok {
+		return nil, errors.New("unsupported unit provided. please use M, KM, FT, MI")
+	}
+
+	set, err := getSortedSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	from, _, ok := memberCoordinates(set, params.Command[2])
+	if !ok {
+		return []byte("$-1\r\n"), nil
+	}
+	to, _, ok := memberCoordinates(set, params.Command[3])
+	if !ok {
+		return []byte("$-1\r\n"), nil
+	}
+
+	var res strings.Builder
+	writeBulkString(&res, formatDistance(distance(from, to)/meters))
+	return []byte(res.String()), nil
+}
+
+func handleGEOSEARCH(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := geosearchKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := parseSearchOptions(params.Command[2:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := getSortedSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return []byte("*0\r\n"), nil
+	}
+
+	center, err := searchCenter(set, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := search(set, center, opts)
+
+	var res strings.Builder
+	res.WriteString(fmt.Sprintf("*%d\r\n", len(results)))
+	for _, result := range results {
+		if !opts.withDist && !opts.withHash && !opts.withCoord {
+			writeBulkString(&res, result.member)
+			continue
+		}
+		length := 1
+		for _, with := range []bool{opts.withDist, opts.withHash, opts.withCoord} {
+			if with {
+				length++
+			}
+		}
+		res.WriteString(fmt.Sprintf("*%d\r\n", length))
+		writeBulkString(&res, result.member)
+		if opts.withDist {
+			writeBulkString(&res, formatDistance(result.dist))
+		}
+		if opts.withHash {
+			res.WriteString(fmt.Sprintf(":%d\r\n", result.hash))
+		}
+		if opts.withCoord {
+			res.WriteString("*2\r\n")
+			writeBulkString(&res, formatFloat(result.coords.Longitude))
+			writeBulkString(&res, formatFloat(result.coords.Latitude))
+		}
+	}
+
+	return []byte(res.String()), nil
+}
+
+func handleGEOSEARCHSTORE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := geosearchstoreKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	destination := keys.WriteKeys[0]
+
+	opts, err := parseSearchOptions(params.Command[3:], true)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := getSortedSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var results []searchResult
+	if set != nil {
+		center, err := searchCenter(set, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = search(set, center, opts)
+	}
+
+	if len(results) == 0 {
+		// An empty result deletes the destination key.
+		if params.KeysExist(params.Context, keys.WriteKeys)[destination] {
+			if err = params.DeleteKey(params.Context, destination); err != nil {
+				return nil, err
+			}
+		}
+		return []byte(":0\r\n"), nil
+	}
+
+	members := make([]sorted_set.MemberParam, len(results))
+	for i, result := range results {
+		members[i] = sorted_set.MemberParam{Value: sorted_set.Value(result.member), Score: sorted_set.Score(result.hash)}
+		if opts.storeDist {
+			members[i].Score = sorted_set.Score(result.dist)
+		}
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{
+		destination: sorted_set.NewSortedSet(members),
+	}); err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", len(members))), nil
+}
+
+// getSortedSet returns the sorted set at the key, or nil if the key does not exist.
+func getSortedSet(params internal.HandlerFuncParams, key string) (*sorted_set.SortedSet, error) {
+	if !params.KeysExist(params.Context, []string{key})[key] {
+		return nil, nil
+	}
+	set, ok := params.GetValues(params.Context, []string{key})[key].(*sorted_set.SortedSet)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a sorted set", key)
+	}
+	return set, nil
+}
+
+// memberCoordinates returns the coordinates and geohash of the member. It returns false if the member
+// does not exist or its score is not a geohash.
+func memberCoordinates(set *sorted_set.SortedSet, member string) (Coordinates, uint64, bool) {
+	if set == nil || !set.Contains(sorted_set.Value(member)) {
+		return Coordinates{}, 0, false
+	}
+	hash, ok := geohashFromScore(set.Get(sorted_set.Value(member)).Score)
+	if !ok {
+		return Coordinates{}, 0, false
+	}
+	return decodeGeohash(hash), hash, true
+}
+
+func searchCenter(set *sorted_set.SortedSet, opts searchOptions) (Coordinates, error) {
+	if opts.fromLonLat != nil {
+		return *opts.fromLonLat, nil
+	}
+	center, _, ok := memberCoordinates(set, opts.fromMember)
+	if !ok {
+		return Coordinates{}, fmt.Errorf("could not find member %s", opts.fromMember)
+	}
+	return center, nil
+}
+
+func writeBulkString(res *strings.Builder, s string) {
+	res.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(s), s))
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "geoadd",
+			Module:     constants.GeoModule,
+			Categories: []string{constants.GeoCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(GEOADD key [NX | XX] [CH] longitude latitude member [longitude latitude member ...])
+Adds the members with the specified coordinates to the geospatial index at key. The index is a sorted set
+where each member's score is the geohash of its coordinates, so it can also be read with the sorted set commands.
+"NX" only adds new members. "XX" only updates the coordinates of existing members.
+"CH" modifies the result to return the number of members added or updated, instead of only the members added.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: geoaddKeyFunc,
+			HandlerFunc:       handleGEOADD,
+		},
+		{
+			Command:    "geodist",
+			Module:     constants.GeoModule,
+			Categories: []string{constants.GeoCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(GEODIST key member1 member2 [M | KM | FT | MI])
+Returns the distance between the two members in the specified unit, which defaults to meters.
+Returns nil if one of the members does not exist.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: geodistKeyFunc,
+			HandlerFunc:       handleGEODIST,
+		},
+		{
+			Command:    "geopos",
+			Module:     constants.GeoModule,
+			Categories: []string{constants.GeoCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(GEOPOS key [member [member ...]])
+Returns the longitude and latitude of each member. Returns nil for the members that don't exist.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: geoposKeyFunc,
+			HandlerFunc:       handleGEOPOS,
+		},
+		{
+			Command:    "geosearch",
+			Module:     constants.GeoModule,
+			Categories: []string{constants.GeoCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(GEOSEARCH key <FROMMEMBER member | FROMLONLAT longitude latitude>
+<BYRADIUS radius <M | KM | FT | MI> | BYBOX width height <M | KM | FT | MI>> [ASC | DESC] [COUNT count [ANY]]
+[WITHCOORD] [WITHDIST] [WITHHASH])
+Returns the members within the circle or box centered on the given member or coordinates.
+"ASC" and "DESC" sort the members by their distance from the center.
+"COUNT" limits the result to the closest members, or to the first members found when "ANY" is provided.
+"WITHCOORD", "WITHDIST" and "WITHHASH" add the coordinates, the distance in the search unit and the geohash
+of each member to the result.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: geosearchKeyFunc,
+			HandlerFunc:       handleGEOSEARCH,
+		},
+		{
+			Command:    "geosearchstore",
+			Module:     constants.GeoModule,
+			Categories: []string{constants.GeoCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(GEOSEARCHSTORE destination source <FROMMEMBER member | FROMLONLAT longitude latitude>
+<BYRADIUS radius <M | KM | FT | MI> | BYBOX width height <M | KM | FT | MI>> [ASC | DESC] [COUNT count [ANY]]
+[STOREDIST])
+Works like GEOSEARCH, but stores the members found in the destination key and returns their count.
+The members are stored with their geohash, or with their distance from the center when "STOREDIST" is provided.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: geosearchstoreKeyFunc,
+			HandlerFunc:       handleGEOSEARCHSTORE,
+		},
+	}
+}
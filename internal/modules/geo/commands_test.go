@@ -0,0 +1,508 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo_test
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+// coord is a coordinate that's compared with a tolerance, as the stored coordinates are approximations.
+type coord float64
+
+// respToInterface converts the response to nested slices of strings, integers and nil values.
+func respToInterface(v resp.Value) interface{} {
+	switch v.Type() {
+	case resp.Array:
+		if v.IsNull() {
+			return nil
+		}
+		arr := make([]interface{}, len(v.Array()))
+		for i, item := range v.Array() {
+			arr[i] = respToInterface(item)
+		}
+		return arr
+	case resp.Integer:
+		return v.Integer()
+	default:
+		if v.IsNull() {
+			return nil
+		}
+		return v.String()
+	}
+}
+
+func responseEqual(want, got interface{}) bool {
+	switch w := want.(type) {
+	case coord:
+		s, ok := got.(string)
+		if !ok {
+			return false
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return err == nil && math.Abs(f-float64(w)) < 1e-5
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(w) != len(g) {
+			return false
+		}
+		for i := range w {
+			if !responseEqual(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+func Test_Geo(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	type command struct {
+		command          []string
+		expectedResponse interface{}
+		expectedError    error
+	}
+
+	runTests := func(t *testing.T, tests []struct {
+		name     string
+		commands []command
+	}) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for _, c := range test.commands {
+					cmd := make([]resp.Value, len(c.command))
+					for i, arg := range c.command {
+						cmd[i] = resp.StringValue(arg)
+					}
+					if err := client.WriteArray(cmd); err != nil {
+						t.Error(err)
+						return
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if c.expectedError != nil {
+						if res.Error() == nil || !strings.Contains(res.Error().Error(), c.expectedError.Error()) {
+							t.Errorf("%v: expected error \"%s\", got \"%v\"", c.command, c.expectedError.Error(), res)
+						}
+						continue
+					}
+					if got := respToInterface(res); !responseEqual(c.expectedResponse, got) {
+						t.Errorf("%v: expected response %v, got %v", c.command, c.expectedResponse, got)
+					}
+				}
+			})
+		}
+	}
+
+	sicily := func(key string) command {
+		return command{
+			command: []string{
+				"GEOADD", key,
+				"13.361389", "38.115556", "Palermo",
+				"15.087269", "37.502669", "Catania",
+				"12.758489", "38.788135", "edge1",
+				"17.241510", "38.788135", "edge2",
+			},
+			expectedResponse: 4,
+		}
+	}
+
+	t.Run("Test_HandleGEOADD", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Create geospatial index on a non-existent key",
+				commands: []command{
+					sicily("GeoaddKey1"),
+					{command: []string{"ZCARD", "GeoaddKey1"}, expectedResponse: 4},
+					{command: []string{"TYPE", "GeoaddKey1"}, expectedResponse: "zset"},
+					{command: []string{"ZSCORE", "GeoaddKey1", "Palermo"}, expectedResponse: "3479099956230698"},
+				},
+			},
+			{
+				name: "2. Only count updated members with CH",
+				commands: []command{
+					{command: []string{"GEOADD", "GeoaddKey2", "13.361389", "38.115556", "Palermo"}, expectedResponse: 1},
+					{
+						command:          []string{"GEOADD", "GeoaddKey2", "13.5", "38.1", "Palermo", "15.087269", "37.502669", "Catania"},
+						expectedResponse: 1,
+					},
+					{
+						command:          []string{"GEOADD", "GeoaddKey2", "CH", "13.361389", "38.115556", "Palermo"},
+						expectedResponse: 1,
+					},
+				},
+			},
+			{
+				name: "3. NX only adds new members and XX only updates existing members",
+				commands: []command{
+					{command: []string{"GEOADD", "GeoaddKey3", "13.361389", "38.115556", "Palermo"}, expectedResponse: 1},
+					{
+						command:          []string{"GEOADD", "GeoaddKey3", "NX", "CH", "10", "10", "Palermo", "15.087269", "37.502669", "Catania"},
+						expectedResponse: 1,
+					},
+					{
+						command:          []string{"GEOADD", "GeoaddKey3", "XX", "CH", "10", "10", "Palermo", "12", "12", "edge1"},
+						expectedResponse: 1,
+					},
+					{command: []string{"ZCARD", "GeoaddKey3"}, expectedResponse: 2},
+					{
+						command:          []string{"GEOPOS", "GeoaddKey3", "Palermo"},
+						expectedResponse: []interface{}{[]interface{}{coord(10), coord(10)}},
+					},
+					{command: []string{"GEOADD", "GeoaddKey4", "XX", "10", "10", "Palermo"}, expectedResponse: 0},
+					{command: []string{"EXISTS", "GeoaddKey4"}, expectedResponse: 0},
+				},
+			},
+			{
+				name: "4. Return error on invalid coordinates",
+				commands: []command{
+					{
+						command:       []string{"GEOADD", "GeoaddKey5", "13.361389", "86", "Palermo"},
+						expectedError: errors.New("invalid longitude,latitude pair 13.361389,86"),
+					},
+					{
+						command:       []string{"GEOADD", "GeoaddKey5", "lon", "38", "Palermo"},
+						expectedError: errors.New("longitude must be a float"),
+					},
+					{
+						command:       []string{"GEOADD", "GeoaddKey5", "NX", "XX", "13", "38", "Palermo"},
+						expectedError: errors.New("XX and NX options at the same time are not compatible"),
+					},
+					{
+						command:       []string{"GEOADD", "GeoaddKey5", "13", "38", "Palermo", "15"},
+						expectedError: errors.New(constants.WrongArgsResponse),
+					},
+				},
+			},
+			{
+				name: "5. Return error when the key does not hold a sorted set",
+				commands: []command{
+					{command: []string{"SET", "GeoaddKey6", "value"}, expectedResponse: "OK"},
+					{
+						command:       []string{"GEOADD", "GeoaddKey6", "13", "38", "Palermo"},
+						expectedError: errors.New("value at GeoaddKey6 is not a sorted set"),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleGEOPOS", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Return the positions of the members",
+				commands: []command{
+					sicily("GeoposKey1"),
+					{
+						command: []string{"GEOPOS", "GeoposKey1", "Palermo", "Catania", "NonExisting"},
+						expectedResponse: []interface{}{
+							[]interface{}{coord(13.36138933897018433), coord(38.11555639549629859)},
+							[]interface{}{coord(15.08726745843887329), coord(37.50266842333162032)},
+							nil,
+						},
+					},
+				},
+			},
+			{
+				name: "2. Return nil positions for a non-existent key",
+				commands: []command{
+					{command: []string{"GEOPOS", "GeoposKey2", "Palermo"}, expectedResponse: []interface{}{nil}},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleGEODIST", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Return the distance between members in each unit",
+				commands: []command{
+					sicily("GeodistKey1"),
+					{command: []string{"GEODIST", "GeodistKey1", "Palermo", "Catania"}, expectedResponse: "166274.1516"},
+					{command: []string{"GEODIST", "GeodistKey1", "Palermo", "Catania", "km"}, expectedResponse: "166.2742"},
+					{command: []string{"GEODIST", "GeodistKey1", "Palermo", "Catania", "MI"}, expectedResponse: "103.3182"},
+					{command: []string{"GEODIST", "GeodistKey1", "Palermo", "Catania", "ft"}, expectedResponse: "545518.8700"},
+				},
+			},
+			{
+				name: "2. Return nil when a member does not exist",
+				commands: []command{
+					sicily("GeodistKey2"),
+					{command: []string{"GEODIST", "GeodistKey2", "Palermo", "NonExisting"}, expectedResponse: nil},
+					{command: []string{"GEODIST", "GeodistKey3", "Palermo", "Catania"}, expectedResponse: nil},
+				},
+			},
+			{
+				name: "3. Return error on unsupported unit",
+				commands: []command{
+					{
+						command:       []string{"GEODIST", "GeodistKey2", "Palermo", "Catania", "yd"},
+						expectedError: errors.New("unsupported unit provided. please use M, KM, FT, MI"),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleGEOSEARCH", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Search by radius from coordinates",
+				commands: []command{
+					sicily("GeosearchKey1"),
+					{
+						command:          []string{"GEOSEARCH", "GeosearchKey1", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "ASC"},
+						expectedResponse: []interface{}{"Catania", "Palermo"},
+					},
+					{
+						command:          []string{"GEOSEARCH", "GeosearchKey1", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "DESC"},
+						expectedResponse: []interface{}{"Palermo", "Catania"},
+					},
+				},
+			},
+			{
+				name: "2. Search by box with the distance and coordinates of the members",
+				commands: []command{
+					sicily("GeosearchKey2"),
+					{
+						command: []string{
+							"GEOSEARCH", "GeosearchKey2", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km",
+							"ASC", "WITHCOORD", "WITHDIST",
+						},
+						expectedResponse: []interface{}{
+							[]interface{}{"Catania", "56.4413", []interface{}{coord(15.08726745843887329), coord(37.50266842333162032)}},
+							[]interface{}{"Palermo", "190.4424", []interface{}{coord(13.36138933897018433), coord(38.11555639549629859)}},
+							[]interface{}{"edge2", "279.7403", []interface{}{coord(17.24151045083999634), coord(38.78813451624225195)}},
+							[]interface{}{"edge1", "279.7405", []interface{}{coord(12.7584877610206604), coord(38.78813451624225195)}},
+						},
+					},
+				},
+			},
+			{
+				name: "3. Search from a member with the hash of the members",
+				commands: []command{
+					sicily("GeosearchKey3"),
+					{
+						command: []string{
+							"GEOSEARCH", "GeosearchKey3", "FROMMEMBER", "Palermo", "BYRADIUS", "170", "km", "WITHHASH",
+						},
+						expectedResponse: []interface{}{
+							[]interface{}{"Palermo", 3479099956230698},
+							[]interface{}{"edge1", 3479273021651468},
+							[]interface{}{"Catania", 3479447370796909},
+						},
+					},
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey3", "FROMMEMBER", "Rome", "BYRADIUS", "170", "km"},
+						expectedError: errors.New("could not find member Rome"),
+					},
+				},
+			},
+			{
+				name: "4. COUNT returns the closest members unless ANY is provided",
+				commands: []command{
+					sicily("GeosearchKey4"),
+					{
+						command:          []string{"GEOSEARCH", "GeosearchKey4", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "COUNT", "2"},
+						expectedResponse: []interface{}{"Catania", "Palermo"},
+					},
+					{
+						command:          []string{"GEOSEARCH", "GeosearchKey4", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "DESC", "COUNT", "1"},
+						expectedResponse: []interface{}{"edge1"},
+					},
+					{
+						command:          []string{"GEOSEARCH", "GeosearchKey4", "FROMLONLAT", "15", "37", "BYRADIUS", "100", "km", "COUNT", "3", "ANY"},
+						expectedResponse: []interface{}{"Catania"},
+					},
+				},
+			},
+			{
+				name: "5. Return an empty result for a non-existent key",
+				commands: []command{
+					{
+						command:          []string{"GEOSEARCH", "GeosearchKey5", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km"},
+						expectedResponse: []interface{}{},
+					},
+				},
+			},
+			{
+				name: "6. Return error on invalid options",
+				commands: []command{
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey6", "FROMLONLAT", "15", "37", "FROMMEMBER", "Palermo", "BYRADIUS", "1", "m"},
+						expectedError: errors.New("exactly one of FROMMEMBER or FROMLONLAT must be provided"),
+					},
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey6", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "BYBOX", "1", "1", "m"},
+						expectedError: errors.New("exactly one of BYRADIUS or BYBOX must be provided"),
+					},
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey6", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "ANY"},
+						expectedError: errors.New("the ANY argument requires COUNT argument"),
+					},
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey6", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "COUNT", "0"},
+						expectedError: errors.New("count must be a positive integer"),
+					},
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey6", "FROMLONLAT", "15", "37", "BYRADIUS", "-1", "m"},
+						expectedError: errors.New("length cannot be negative"),
+					},
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey6", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "STOREDIST"},
+						expectedError: errors.New("invalid option STOREDIST"),
+					},
+					{
+						command:       []string{"GEOSEARCH", "GeosearchKey6", "FROMLONLAT", "15", "37", "BYRADIUS", "1"},
+						expectedError: errors.New("missing argument radius unit"),
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleGEOSEARCHSTORE", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Store the members found with their geohash",
+				commands: []command{
+					sicily("GeosearchstoreKey1"),
+					{
+						command: []string{
+							"GEOSEARCHSTORE", "GeosearchstoreDest1", "GeosearchstoreKey1",
+							"FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "ASC", "COUNT", "3",
+						},
+						expectedResponse: 3,
+					},
+					{
+						command:          []string{"GEOSEARCH", "GeosearchstoreDest1", "FROMMEMBER", "Catania", "BYRADIUS", "200", "km", "ASC"},
+						expectedResponse: []interface{}{"Catania", "Palermo"},
+					},
+				},
+			},
+			{
+				name: "2. Store the members found with their distance",
+				commands: []command{
+					sicily("GeosearchstoreKey2"),
+					{
+						command: []string{
+							"GEOSEARCHSTORE", "GeosearchstoreDest2", "GeosearchstoreKey2",
+							"FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "STOREDIST",
+						},
+						expectedResponse: 2,
+					},
+					{command: []string{"ZSCORE", "GeosearchstoreDest2", "Catania"}, expectedResponse: coord(56.4412578701582)},
+				},
+			},
+			{
+				name: "3. Delete the destination when no members are found",
+				commands: []command{
+					sicily("GeosearchstoreKey3"),
+					{command: []string{"SET", "GeosearchstoreDest3", "value"}, expectedResponse: "OK"},
+					{
+						command: []string{
+							"GEOSEARCHSTORE", "GeosearchstoreDest3", "GeosearchstoreKey3",
+							"FROMLONLAT", "0", "0", "BYRADIUS", "10", "km",
+						},
+						expectedResponse: 0,
+					},
+					{command: []string{"EXISTS", "GeosearchstoreDest3"}, expectedResponse: 0},
+				},
+			},
+			{
+				name: "4. Return error on reply options",
+				commands: []command{
+					{
+						command: []string{
+							"GEOSEARCHSTORE", "GeosearchstoreDest4", "GeosearchstoreKey4",
+							"FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "WITHDIST",
+						},
+						expectedError: errors.New("invalid option WITHDIST"),
+					},
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"math"
+)
+
+const (
+	// geoStep is the number of bits used for each of the longitude and latitude in a geohash.
+	// The 52-bit geohash fits in the mantissa of a float64, so it can be stored as a sorted set score.
+	geoStep = 26
+
+	minLongitude = -180.0
+	maxLongitude = 180.0
+	// The latitude is limited to the range of the Web Mercator projection.
+	minLatitude = -85.05112878
+	maxLatitude = 85.05112878
+
+	earthRadiusInMeters = 6372797.560856
+)
+
+// units maps the distance units accepted by the geo commands to their length in meters.
+var units = map[string]float64{
+	"m":  1,
+	"km": 1000,
+	"ft": 0.3048,
+	"mi": 1609.34,
+}
+
+type Coordinates struct {
+	Longitude float64
+	Latitude  float64
+}
+
+func validCoordinates(coords Coordinates) bool {
+	return coords.Longitude >= minLongitude && coords.Longitude <= maxLongitude &&
+		coords.Latitude >= minLatitude && coords.Latitude <= maxLatitude
+}
+
+// encodeGeohash interleaves the latitude and longitude bits into a 52-bit geohash, with the latitude
+// in the even bits and the longitude in the odd bits.
+func encodeGeohash(coords Coordinates) uint64 {
+	latBits := quantize(coords.Latitude, minLatitude, maxLatitude)
+	lonBits := quantize(coords.Longitude, minLongitude, maxLongitude)
+	return spreadBits(latBits) | spreadBits(lonBits)<<1
+}
+
+// decodeGeohash returns the coordinates of the center of the area represented by the geohash.
+func decodeGeohash(hash uint64) Coordinates {
+	return Coordinates{
+		Longitude: dequantize(squashBits(hash>>1), minLongitude, maxLongitude),
+		Latitude:  dequantize(squashBits(hash), minLatitude, maxLatitude),
+	}
+}
+
+func quantize(value, rangeMin, rangeMax float64) uint32 {
+	bits := uint64((value - rangeMin) / (rangeMax - rangeMin) * (1 << geoStep))
+	// The maximum value falls at the end of the range, so it's kept in the last area.
+	return uint32(min(bits, 1<<geoStep-1))
+}
+
+func dequantize(bits uint32, rangeMin, rangeMax float64) float64 {
+	scale := (rangeMax - rangeMin) / (1 << geoStep)
+	lower := rangeMin + float64(bits)*scale
+	upper := rangeMin + float64(bits+1)*scale
+	return max(rangeMin, min(rangeMax, (lower+upper)/2))
+}
+
+// spreadBits moves each bit of v to the even bit at twice its position.
+func spreadBits(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+// squashBits is the inverse of spreadBits, it collects the even bits of x.
+func squashBits(x uint64) uint32 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x>>4) & 0x00FF00FF00FF00FF
+	x = (x | x>>8) & 0x0000FFFF0000FFFF
+	x = (x | x>>16) & 0x00000000FFFFFFFF
+	return uint32(x)
+}
+
+// distance returns the distance in meters between the two points using the haversine formula.
+func distance(from, to Coordinates) float64 {
+	v := math.Sin(degreesToRadians(to.Longitude-from.Longitude) / 2)
+	if v == 0 {
+		return latitudeDistance(from.Latitude, to.Latitude)
+	}
+	lat1 := degreesToRadians(from.Latitude)
+	lat2 := degreesToRadians(to.Latitude)
+	u := math.Sin((lat2 - lat1) / 2)
+	a := u*u + math.Cos(lat1)*math.Cos(lat2)*v*v
+	return 2 * earthRadiusInMeters * math.Asin(math.Sqrt(a))
+}
+
+func latitudeDistance(lat1, lat2 float64) float64 {
+	return earthRadiusInMeters * math.Abs(degreesToRadians(lat2)-degreesToRadians(lat1))
+}
+
+func degreesToRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,275 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geo
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/internal/modules/sorted_set"
+)
+
+type searchOptions struct {
+	fromMember    string
+	fromLonLat    *Coordinates
+	byRadius      bool
+	byBox         bool
+	radius        float64 // Radius in meters when searching by radius.
+	width, height float64 // Box dimensions in meters when searching by box.
+	unit          float64 // Length of the unit used for the reply distances, in meters.
+	order         string  // "asc", "desc" or "" to leave the results unsorted.
+	count         int     // Maximum number of results, 0 for no limit.
+	any           bool
+	withCoord     bool
+	withDist      bool
+	withHash      bool
+	storeDist     bool
+}
+
+type searchResult struct {
+	member string
+	hash   uint64
+	coords Coordinates
+	dist   float64 // Distance from the search center, in the search unit.
+}
+
+// parseSearchOptions parses the arguments of GEOSEARCH and GEOSEARCHSTORE that follow the key(s).
+// The WITH* options are only accepted by GEOSEARCH, while STOREDIST is only accepted by GEOSEARCHSTORE.
+func parseSearchOptions(args []string, store bool) (searchOptions, error) {
+	opts := searchOptions{}
+
+	var fromMember bool
+	for i := 0; i < len(args); i++ {
+		option := strings.ToLower(args[i])
+
+		// next returns the n arguments of the option.
+		next := func(n int, name string) ([]string, error) {
+			if i+n >= len(args) {
+				return nil, fmt.Errorf(constants.MissingArgResponse, name)
+			}
+			values := args[i+1 : i+1+n]
+			i += n
+			return values, nil
+		}
+
+		switch {
+		case option == "frommember":
+			values, err := next(1, "member")
+			if err != nil {
+				return opts, err
+			}
+			fromMember = true
+			opts.fromMember = values[0]
+
+		case option == "fromlonlat":
+			values, err := next(2, "longitude latitude")
+			if err != nil {
+				return opts, err
+			}
+			coords, err := parseCoordinates(values[0], values[1])
+			if err != nil {
+				return opts, err
+			}
+			opts.fromLonLat = &coords
+
+		case option == "byradius":
+			values, err := next(2, "radius unit")
+			if err != nil {
+				return opts, err
+			}
+			radius, err := parseLength(values[0], values[1])
+			if err != nil {
+				return opts, err
+			}
+			opts.byRadius, opts.radius, opts.unit = true, radius, units[strings.ToLower(values[1])]
+
+		case option == "bybox":
+			values, err := next(3, "width height unit")
+			if err != nil {
+				return opts, err
+			}
+			width, err := parseLength(values[0], values[2])
+			if err != nil {
+				return opts, err
+			}
+			height, err := parseLength(values[1], values[2])
+			if err != nil {
+				return opts, err
+			}
+			opts.byBox, opts.width, opts.height, opts.unit = true, width, height, units[strings.ToLower(values[2])]
+
+		case option == "asc" || option == "desc":
+			opts.order = option
+
+		case option == "count":
+			values, err := next(1, "count")
+			if err != nil {
+				return opts, err
+			}
+			count, err := strconv.Atoi(values[0])
+			if err != nil || count <= 0 {
+				return opts, errors.New("count must be a positive integer")
+			}
+			opts.count = count
+			if i+1 < len(args) && strings.EqualFold(args[i+1], "any") {
+				opts.any = true
+				i++
+			}
+
+		case option == "any":
+			return opts, errors.New("the ANY argument requires COUNT argument")
+
+		case option == "withcoord" && !store:
+			opts.withCoord = true
+		case option == "withdist" && !store:
+			opts.withDist = true
+		case option == "withhash" && !store:
+			opts.withHash = true
+		case option == "storedist" && store:
+			opts.storeDist = true
+
+		default:
+			return opts, fmt.Errorf("invalid option %s", args[i])
+		}
+	}
+
+	if fromMember == (opts.fromLonLat != nil) {
+		return opts, errors.New("exactly one of FROMMEMBER or FROMLONLAT must be provided")
+	}
+	if opts.byRadius == opts.byBox {
+		return opts, errors.New("exactly one of BYRADIUS or BYBOX must be provided")
+	}
+
+	return opts, nil
+}
+
+func parseCoordinates(longitude, latitude string) (Coordinates, error) {
+	lon, err := strconv.ParseFloat(longitude, 64)
+	if err != nil {
+		return Coordinates{}, errors.New("longitude must be a float")
+	}
+	lat, err := strconv.ParseFloat(latitude, 64)
+	if err != nil {
+		return Coordinates{}, errors.New("latitude must be a float")
+	}
+	coords := Coordinates{Longitude: lon, Latitude: lat}
+	if !validCoordinates(coords) {
+		return Coordinates{}, fmt.Errorf("invalid longitude,latitude pair %s,%s", longitude, latitude)
+	}
+	return coords, nil
+}
+
+// parseLength parses a non-negative length in the given unit and returns it in meters.
+func parseLength(length string, unit string) (float64, error) {
+	meters, ok := units[strings.ToLower(unit)]
+	if !ok {
+		return 0, errors.New("unsupported unit provided. please use M, KM, FT, MI")
+	}
+	l, err := strconv.ParseFloat(length, 64)
+	if err != nil {
+		return 0, fmt.Errorf("length %s must be a float", length)
+	}
+	if l < 0 {
+		return 0, errors.New("length cannot be negative")
+	}
+	return l * meters, nil
+}
+
+// contains checks whether the point is within the search area around the center.
+// It returns the distance between the center and the point in meters.
+func (opts searchOptions) contains(center, point Coordinates) (float64, bool) {
+	if opts.byRadius {
+		dist := distance(center, point)
+		return dist, dist <= opts.radius
+	}
+	// The box is aligned with the meridians, so each side is compared along its own axis.
+	if latitudeDistance(center.Latitude, point.Latitude) > opts.height/2 {
+		return 0, false
+	}
+	if distance(center, Coordinates{Longitude: point.Longitude, Latitude: center.Latitude}) > opts.width/2 {
+		return 0, false
+	}
+	return distance(center, point), true
+}
+
+// search returns the members of the set that are within the search area around the center.
+func search(set *sorted_set.SortedSet, center Coordinates, opts searchOptions) []searchResult {
+	var results []searchResult
+	for _, member := range set.GetAll() {
+		hash, ok := geohashFromScore(member.Score)
+		if !ok {
+			continue
+		}
+		coords := decodeGeohash(hash)
+		dist, ok := opts.contains(center, coords)
+		if !ok {
+			continue
+		}
+		results = append(results, searchResult{
+			member: string(member.Value),
+			hash:   hash,
+			coords: coords,
+			dist:   dist / opts.unit,
+		})
+		// With ANY, the search stops as soon as enough matches are found, so they may not be the closest ones.
+		if opts.any && len(results) == opts.count {
+			break
+		}
+	}
+
+	order := opts.order
+	if order == "" && opts.count > 0 && !opts.any {
+		// The closest matches are returned when the results are limited.
+		order = "asc"
+	}
+	slices.SortFunc(results, func(a, b searchResult) int {
+		switch order {
+		case "asc":
+			return cmp.Or(cmp.Compare(a.dist, b.dist), cmp.Compare(a.member, b.member))
+		case "desc":
+			return cmp.Or(cmp.Compare(b.dist, a.dist), cmp.Compare(a.member, b.member))
+		default:
+			return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.member, b.member))
+		}
+	})
+
+	if opts.count > 0 && len(results) > opts.count {
+		results = results[:opts.count]
+	}
+	return results
+}
+
+// geohashFromScore returns the geohash stored as the score of a sorted set member. It returns false when
+// the score is not a valid geohash, e.g. when the member was added with ZADD.
+func geohashFromScore(score sorted_set.Score) (uint64, bool) {
+	if score < 0 || score >= 1<<(2*geoStep) || score != sorted_set.Score(math.Trunc(float64(score))) {
+		return 0, false
+	}
+	return uint64(score), true
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatDistance(dist float64) string {
+	return strconv.FormatFloat(dist, 'f', 4, 64)
+}
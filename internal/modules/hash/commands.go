@@ -35,7 +35,7 @@ func handleHSET(params internal.HandlerFuncParams) ([]byte, error) {
 
 	key := keys.WriteKeys[0]
 	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
-	entries := Hash{}
+	entries := NewHash(nil)
 
 	if len(params.Command[2:])%2 != 0 {
 		return nil, errors.New("each field must have a corresponding value")
@@ -43,46 +43,46 @@ func handleHSET(params internal.HandlerFuncParams) ([]byte, error) {
 
 	for i := 2; i <= len(params.Command)-2; i += 2 {
 		k := params.Command[i]
-		entries[k] = HashValue{Value: internal.AdaptType(params.Command[i+1])}
+		entries.Set(k, HashValue{Value: internal.AdaptType(params.Command[i+1])})
 	}
 
 	if !keyExists {
 		if err = params.SetValues(params.Context, map[string]interface{}{key: entries}); err != nil {
 			return nil, err
 		}
-		return []byte(fmt.Sprintf(":%d\r\n", len(entries))), nil
+		return []byte(fmt.Sprintf(":%d\r\n", entries.Len())), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		// Not hash, save the entries map directly.
 		if err = params.SetValues(params.Context, map[string]interface{}{key: entries}); err != nil {
 			return nil, err
 		}
-		return []byte(fmt.Sprintf(":%d\r\n", len(entries))), nil
+		return []byte(fmt.Sprintf(":%d\r\n", entries.Len())), nil
 	}
 
 	count := 0
 	switch strings.ToLower(params.Command[0]) {
 	case "hsetnx":
 		// Handle HSETNX
-		for field, _ := range entries {
-			if _, ok := hash[field]; !ok {
+		for field, _ := range entries.All() {
+			if !hash.Contains(field) {
 				count += 1
 			}
 		}
 
-		for field, value := range hash {
-			entries[field] = value
+		for field, value := range hash.All() {
+			entries.Set(field, value)
 		}
 	default:
 		// Handle HSET
-		for field, value := range hash {
-			if entries[field].Value == nil {
-				entries[field] = value
+		for field, value := range hash.All() {
+			if !entries.Contains(field) {
+				entries.Set(field, value)
 			}
 		}
-		count = len(entries)
+		count = entries.Len()
 	}
 
 	if err = params.SetValues(params.Context, map[string]interface{}{key: entries}); err != nil {
@@ -106,7 +106,7 @@ func handleHGET(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("$-1\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
@@ -115,7 +115,7 @@ func handleHGET(params internal.HandlerFuncParams) ([]byte, error) {
 
 	res := fmt.Sprintf("*%d\r\n", len(fields))
 	for _, field := range fields {
-		value = hash[field]
+		value = hash.Get(field)
 		if value.Value == nil {
 			res += "$-1\r\n"
 			continue
@@ -151,7 +151,7 @@ func handleHMGET(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("$-1\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
@@ -162,11 +162,11 @@ func handleHMGET(params internal.HandlerFuncParams) ([]byte, error) {
 
 	res := fmt.Sprintf("*%d\r\n", len(fields))
 	for _, field := range fields {
-		value, ok = hash[field]
-		if !ok {
+		if !hash.Contains(field) {
 			res += "$-1\r\n"
 			continue
 		}
+		value = hash.Get(field)
 
 		if s, ok := value.Value.(string); ok {
 			res += fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
@@ -201,7 +201,7 @@ func handleHSTRLEN(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("$-1\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
@@ -210,7 +210,7 @@ func handleHSTRLEN(params internal.HandlerFuncParams) ([]byte, error) {
 
 	res := fmt.Sprintf("*%d\r\n", len(fields))
 	for _, field := range fields {
-		value = hash[field]
+		value = hash.Get(field)
 		if value.Value == nil {
 			res += ":0\r\n"
 			continue
@@ -247,13 +247,13 @@ func handleHVALS(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("*0\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
-	res := fmt.Sprintf("*%d\r\n", len(hash))
-	for _, val := range hash {
+	res := fmt.Sprintf("*%d\r\n", hash.Len())
+	for _, val := range hash.All() {
 		if s, ok := val.Value.(string); ok {
 			res += fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
 			continue
@@ -305,18 +305,18 @@ func handleHRANDFIELD(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("*0\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
 	// If count is the >= hash length, then return the entire hash
-	if count >= len(hash) {
-		res := fmt.Sprintf("*%d\r\n", len(hash))
+	if count >= hash.Len() {
+		res := fmt.Sprintf("*%d\r\n", hash.Len())
 		if withvalues {
-			res = fmt.Sprintf("*%d\r\n", len(hash)*2)
+			res = fmt.Sprintf("*%d\r\n", hash.Len()*2)
 		}
-		for field, value := range hash {
+		for field, value := range hash.All() {
 			res += fmt.Sprintf("$%d\r\n%s\r\n", len(field), field)
 			if withvalues {
 				if s, ok := value.Value.(string); ok {
@@ -339,7 +339,7 @@ func handleHRANDFIELD(params internal.HandlerFuncParams) ([]byte, error) {
 
 	// Get all the fields
 	var fields []string
-	for field, _ := range hash {
+	for field, _ := range hash.All() {
 		fields = append(fields, field)
 	}
 
@@ -364,16 +364,16 @@ func handleHRANDFIELD(params internal.HandlerFuncParams) ([]byte, error) {
 	for _, field := range pluckedFields {
 		res += fmt.Sprintf("$%d\r\n%s\r\n", len(field), field)
 		if withvalues {
-			if s, ok := hash[field].Value.(string); ok {
+			if s, ok := hash.Get(field).Value.(string); ok {
 				res += fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
 				continue
 			}
-			if f, ok := hash[field].Value.(float64); ok {
+			if f, ok := hash.Get(field).Value.(float64); ok {
 				fs := strconv.FormatFloat(f, 'f', -1, 64)
 				res += fmt.Sprintf("$%d\r\n%s\r\n", len(fs), fs)
 				continue
 			}
-			if d, ok := hash[field].Value.(int); ok {
+			if d, ok := hash.Get(field).Value.(int); ok {
 				res += fmt.Sprintf(":%d\r\n", d)
 				continue
 			}
@@ -396,12 +396,12 @@ func handleHLEN(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte(":0\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
-	return []byte(fmt.Sprintf(":%d\r\n", len(hash))), nil
+	return []byte(fmt.Sprintf(":%d\r\n", hash.Len())), nil
 }
 
 func handleHKEYS(params internal.HandlerFuncParams) ([]byte, error) {
@@ -417,19 +417,89 @@ func handleHKEYS(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("*0\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
-	res := fmt.Sprintf("*%d\r\n", len(hash))
-	for field, _ := range hash {
+	res := fmt.Sprintf("*%d\r\n", hash.Len())
+	for field, _ := range hash.All() {
 		res += fmt.Sprintf("$%d\r\n%s\r\n", len(field), field)
 	}
 
 	return []byte(res), nil
 }
 
+func handleHSCAN(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := hscanKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := internal.ParseScanCursor(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+
+	var match string
+	count := internal.DefaultScanCount
+	noValues := false
+	for i := 3; i < len(params.Command); i++ {
+		option := strings.ToLower(params.Command[i])
+		if option == "novalues" {
+			noValues = true
+			continue
+		}
+		if i+1 >= len(params.Command) {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+		switch option {
+		case "match":
+			match = params.Command[i+1]
+		case "count":
+			if count, err = internal.ParseScanCount(params.Command[i+1]); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown option %s", params.Command[i])
+		}
+		i++
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	if !keyExists {
+		return internal.EncodeScanResponse(0, []string{}), nil
+	}
+
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a hash", key)
+	}
+
+	next, fields := hash.Scan(cursor, count)
+
+	var elements []string
+	for _, field := range fields {
+		if match != "" && !internal.MatchPattern(match, field) {
+			continue
+		}
+		elements = append(elements, field)
+		if noValues {
+			continue
+		}
+		switch value := hash.Get(field).Value.(type) {
+		case float64:
+			elements = append(elements, strconv.FormatFloat(value, 'f', -1, 64))
+		default:
+			elements = append(elements, fmt.Sprintf("%v", value))
+		}
+	}
+
+	return internal.EncodeScanResponse(next, elements), nil
+}
+
 func handleHINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
 	keys, err := hincrbyKeyFunc(params.Command)
 	if err != nil {
@@ -458,15 +528,15 @@ func handleHINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	if !keyExists {
-		hash := make(Hash)
+		hash := NewHash(nil)
 		if strings.EqualFold(params.Command[0], "hincrbyfloat") {
-			hash[field] = HashValue{Value: floatIncrement}
+			hash.Set(field, HashValue{Value: floatIncrement})
 			if err = params.SetValues(params.Context, map[string]interface{}{key: hash}); err != nil {
 				return nil, err
 			}
 			return []byte(fmt.Sprintf("+%s\r\n", strconv.FormatFloat(floatIncrement, 'f', -1, 64))), nil
 		} else {
-			hash[field] = HashValue{Value: intIncrement}
+			hash.Set(field, HashValue{Value: intIncrement})
 			if err = params.SetValues(params.Context, map[string]interface{}{key: hash}); err != nil {
 				return nil, err
 			}
@@ -474,31 +544,31 @@ func handleHINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
-	if hash[field].Value == nil {
-		hash[field] = HashValue{Value: 0}
+	if hash.Get(field).Value == nil {
+		hash.Set(field, HashValue{Value: 0})
 	}
 
-	switch hash[field].Value.(type) {
+	switch hash.Get(field).Value.(type) {
 	default:
 		return nil, fmt.Errorf("value at field %s is not a number", field)
 	case int:
-		i, _ := hash[field].Value.(int)
+		i, _ := hash.Get(field).Value.(int)
 		if strings.EqualFold(params.Command[0], "hincrbyfloat") {
-			hash[field] = HashValue{Value: float64(i) + floatIncrement}
+			hash.Set(field, HashValue{Value: float64(i) + floatIncrement})
 		} else {
-			hash[field] = HashValue{Value: i + intIncrement}
+			hash.Set(field, HashValue{Value: i + intIncrement})
 		}
 	case float64:
-		f, _ := hash[field].Value.(float64)
+		f, _ := hash.Get(field).Value.(float64)
 		if strings.EqualFold(params.Command[0], "hincrbyfloat") {
-			hash[field] = HashValue{Value: f + floatIncrement}
+			hash.Set(field, HashValue{Value: f + floatIncrement})
 		} else {
-			hash[field] = HashValue{Value: f + float64(intIncrement)}
+			hash.Set(field, HashValue{Value: f + float64(intIncrement)})
 		}
 	}
 
@@ -506,11 +576,11 @@ func handleHINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, err
 	}
 
-	if f, ok := hash[field].Value.(float64); ok {
+	if f, ok := hash.Get(field).Value.(float64); ok {
 		return []byte(fmt.Sprintf("+%s\r\n", strconv.FormatFloat(f, 'f', -1, 64))), nil
 	}
 
-	i, _ := hash[field].Value.(int)
+	i, _ := hash.Get(field).Value.(int)
 	return []byte(fmt.Sprintf(":%d\r\n", i)), nil
 }
 
@@ -527,13 +597,13 @@ func handleHGETALL(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("*0\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
-	res := fmt.Sprintf("*%d\r\n", len(hash)*2)
-	for field, value := range hash {
+	res := fmt.Sprintf("*%d\r\n", hash.Len()*2)
+	for field, value := range hash.All() {
 		res += fmt.Sprintf("$%d\r\n%s\r\n", len(field), field)
 		if s, ok := value.Value.(string); ok {
 			res += fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
@@ -566,12 +636,12 @@ func handleHEXISTS(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte(":0\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
-	if hash[field].Value != nil {
+	if hash.Get(field).Value != nil {
 		return []byte(":1\r\n"), nil
 	}
 
@@ -592,7 +662,7 @@ func handleHDEL(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte(":0\r\n"), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
@@ -600,8 +670,8 @@ func handleHDEL(params internal.HandlerFuncParams) ([]byte, error) {
 	count := 0
 
 	for _, field := range fields {
-		if hash[field].Value != nil {
-			delete(hash, field)
+		if hash.Get(field).Value != nil {
+			hash.Delete(field)
 			count += 1
 		}
 	}
@@ -645,7 +715,12 @@ func handleHEXPIRE(params internal.HandlerFuncParams) ([]byte, error) {
 	endIdx := fieldsIdx + 2 + int(numfields)
 	fields := cmdargs[fieldsIdx+2 : endIdx]
 
-	expireAt := params.GetClock().Now().Add(time.Duration(seconds) * time.Second)
+	// HPEXPIRE and HPEXPIREAT express the expire time in milliseconds.
+	unit := time.Second
+	if strings.HasPrefix(strings.ToLower(params.Command[0]), "hpexpire") {
+		unit = time.Millisecond
+	}
+	expireAt := params.GetClock().Now().Add(time.Duration(seconds) * unit)
 
 	// build out response
 	resp := "*" + fmt.Sprintf("%v", len(fields)) + "\r\n"
@@ -659,7 +734,7 @@ func handleHEXPIRE(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte(resp), nil
 	}
 
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value of key %s is not a hash", key)
 	}
@@ -677,12 +752,11 @@ func handleHEXPIRE(params internal.HandlerFuncParams) ([]byte, error) {
 		switch strings.ToLower(cmdargs[1]) {
 		case "nx":
 			for _, f := range fields {
-				_, ok := hash[f]
-				if !ok {
+				if !hash.Contains(f) {
 					resp = resp + ":-2\r\n"
 					continue
 				}
-				currentExpireAt := hash[f].ExpireAt
+				currentExpireAt := hash.Get(f).ExpireAt
 				if currentExpireAt != (time.Time{}) {
 					resp = resp + ":0\r\n"
 					continue
@@ -697,12 +771,11 @@ func handleHEXPIRE(params internal.HandlerFuncParams) ([]byte, error) {
 			}
 		case "xx":
 			for _, f := range fields {
-				_, ok := hash[f]
-				if !ok {
+				if !hash.Contains(f) {
 					resp = resp + ":-2\r\n"
 					continue
 				}
-				currentExpireAt := hash[f].ExpireAt
+				currentExpireAt := hash.Get(f).ExpireAt
 				if currentExpireAt == (time.Time{}) {
 					resp = resp + ":0\r\n"
 					continue
@@ -717,12 +790,11 @@ func handleHEXPIRE(params internal.HandlerFuncParams) ([]byte, error) {
 			}
 		case "gt":
 			for _, f := range fields {
-				_, ok := hash[f]
-				if !ok {
+				if !hash.Contains(f) {
 					resp = resp + ":-2\r\n"
 					continue
 				}
-				currentExpireAt := hash[f].ExpireAt
+				currentExpireAt := hash.Get(f).ExpireAt
 				//TODO
 				if currentExpireAt == (time.Time{}) || expireAt.Before(currentExpireAt) {
 					resp = resp + ":0\r\n"
@@ -738,12 +810,11 @@ func handleHEXPIRE(params internal.HandlerFuncParams) ([]byte, error) {
 			}
 		case "lt":
 			for _, f := range fields {
-				_, ok := hash[f]
-				if !ok {
+				if !hash.Contains(f) {
 					resp = resp + ":-2\r\n"
 					continue
 				}
-				currentExpireAt := hash[f].ExpireAt
+				currentExpireAt := hash.Get(f).ExpireAt
 				if currentExpireAt != (time.Time{}) && currentExpireAt.Before(expireAt) {
 					resp = resp + ":0\r\n"
 					continue
@@ -761,8 +832,7 @@ func handleHEXPIRE(params internal.HandlerFuncParams) ([]byte, error) {
 		}
 	} else {
 		for _, f := range fields {
-			_, ok := hash[f]
-			if !ok {
+			if !hash.Contains(f) {
 				resp = resp + ":-2\r\n"
 				continue
 			}
@@ -801,6 +871,27 @@ func handleHEXPIREAT(params internal.HandlerFuncParams) ([]byte, error) {
 	return handleHEXPIRE(params)
 }
 
+func handleHPEXPIREAT(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := hpexpireatKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	cmdargs := keys.WriteKeys[1:]
+	epoch, err := strconv.ParseInt(cmdargs[0], 10, 64)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("milliseconds must be integer, was provided %q", cmdargs[0]))
+	}
+
+	if params.GetClock().Now().UnixMilli() > epoch {
+		params.Command[2] = "0"
+		return handleHEXPIRE(params)
+	}
+
+	expireAt := epoch - params.GetClock().Now().UnixMilli()
+	params.Command[2] = strconv.FormatInt(expireAt, 10)
+	return handleHEXPIRE(params)
+}
+
 func handleHTTL(params internal.HandlerFuncParams) ([]byte, error) {
 	keys, err := httlKeyFunc(params.Command)
 	if err != nil {
@@ -821,28 +912,35 @@ func handleHTTL(params internal.HandlerFuncParams) ([]byte, error) {
 	key := keys.ReadKeys[0]
 	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
 	if !keyExists {
-		resp = resp + ":-2\r\n"
+		for range fields {
+			resp = resp + ":-2\r\n"
+		}
 		return []byte(resp), nil
 	}
 
 	// handle not a hash
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
 	// build out response
 	for _, field := range fields {
-		f, ok := hash[field]
-		if !ok {
+		if !hash.Contains(field) {
 			resp = resp + ":-2\r\n"
 			continue
 		}
+		f := hash.Get(field)
 		if f.ExpireAt == (time.Time{}) {
 			resp = resp + ":-1\r\n"
 			continue
 		}
-		resp = resp + fmt.Sprintf(":%d\r\n", int(f.ExpireAt.Sub(params.GetClock().Now()).Round(time.Second).Seconds()))
+		ttl := f.ExpireAt.Sub(params.GetClock().Now())
+		if strings.EqualFold(params.Command[0], "hpttl") {
+			resp = resp + fmt.Sprintf(":%d\r\n", ttl.Milliseconds())
+			continue
+		}
+		resp = resp + fmt.Sprintf(":%d\r\n", int(ttl.Round(time.Second).Seconds()))
 
 	}
 
@@ -874,18 +972,18 @@ func handleHPEXPIRETIME(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	// handle not a hash
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
 	for _, field := range fields {
-		f, ok := hash[field]
-		if !ok {
+		if !hash.Contains(field) {
 			// Field doesn't exist
 			resp += ":-2\r\n"
 			continue
 		}
+		f := hash.Get(field)
 
 		if f.ExpireAt == (time.Time{}) {
 			// No expiration set
@@ -924,18 +1022,18 @@ func handleHEXPIRETIME(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 		// handle not a hash
-	hash, ok := params.GetValues(params.Context, []string{key})[key].(Hash)
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
 	if !ok {
 		return nil, fmt.Errorf("value at %s is not a hash", key)
 	}
 
 	for _, field := range fields {
-		f, ok := hash[field]
-		if !ok {
+		if !hash.Contains(field) {
 			// Field doesn't exist
 			resp += ":-2\r\n"
 			continue
 		}
+		f := hash.Get(field)
 
 		if f.ExpireAt == (time.Time{}) {
 			// No expiration set
@@ -948,6 +1046,338 @@ func handleHEXPIRETIME(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(resp), nil
 }
 
+func handleHPERSIST(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := hpersistKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	fields, err := parseFields(params.Command[2:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := fmt.Sprintf("*%d\r\n", len(fields))
+
+	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
+	if !keyExists {
+		for range fields {
+			resp += ":-2\r\n"
+		}
+		return []byte(resp), nil
+	}
+
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a hash", key)
+	}
+
+	for _, field := range fields {
+		if !hash.Contains(field) {
+			resp += ":-2\r\n"
+			continue
+		}
+		f := hash.Get(field)
+		if f.ExpireAt == (time.Time{}) {
+			resp += ":-1\r\n"
+			continue
+		}
+		if err = params.SetHashExpiry(params.Context, key, field, time.Time{}); err != nil {
+			return nil, err
+		}
+		resp += ":1\r\n"
+	}
+
+	return []byte(resp), nil
+}
+
+func handleHGETDEL(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := hgetdelKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	fields, err := parseFields(params.Command[2:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(fields))
+
+	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
+	if !keyExists {
+		for range fields {
+			res += "$-1\r\n"
+		}
+		return []byte(res), nil
+	}
+
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a hash", key)
+	}
+
+	for _, field := range fields {
+		if !hash.Contains(field) {
+			res += "$-1\r\n"
+			continue
+		}
+		value := hash.Get(field)
+		res += encodeHashValue(value.Value)
+		hash.Delete(field)
+	}
+
+	if err = storeHash(params, key, hash); err != nil {
+		return nil, err
+	}
+
+	return []byte(res), nil
+}
+
+func handleHGETEX(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := hgetexKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	now := params.GetClock().Now()
+
+	// HGETEX key [EX seconds | PX milliseconds | EXAT unix-time-seconds | PXAT unix-time-milliseconds | PERSIST]
+	// FIELDS numfields field [field ...]
+	var expireAt time.Time
+	var expire, persist bool
+	i := 2
+	for ; i < len(params.Command) && !strings.EqualFold(params.Command[i], "fields"); i++ {
+		switch strings.ToLower(params.Command[i]) {
+		case "ex", "px", "exat", "pxat":
+			if expire || persist || i+1 >= len(params.Command) {
+				return nil, errors.New(constants.InvalidCmdResponse)
+			}
+			if expireAt, err = parseExpireAt(now, params.Command[i], params.Command[i+1]); err != nil {
+				return nil, err
+			}
+			expire = true
+			i++
+		case "persist":
+			if expire || persist {
+				return nil, errors.New(constants.InvalidCmdResponse)
+			}
+			persist = true
+		default:
+			return nil, fmt.Errorf("unknown option %s", strings.ToUpper(params.Command[i]))
+		}
+	}
+
+	fields, err := parseFields(params.Command[i:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(fields))
+
+	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
+	if !keyExists {
+		for range fields {
+			res += "$-1\r\n"
+		}
+		return []byte(res), nil
+	}
+
+	hash, ok := params.GetValues(params.Context, []string{key})[key].(*Hash)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a hash", key)
+	}
+
+	deleted := false
+	for _, field := range fields {
+		if !hash.Contains(field) {
+			res += "$-1\r\n"
+			continue
+		}
+		value := hash.Get(field)
+		res += encodeHashValue(value.Value)
+
+		switch {
+		case persist:
+			err = params.SetHashExpiry(params.Context, key, field, time.Time{})
+		case expire && !expireAt.After(now):
+			// An expire time in the past deletes the field.
+			hash.Delete(field)
+			deleted = true
+		case expire:
+			err = params.SetHashExpiry(params.Context, key, field, expireAt)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if deleted {
+		if err = storeHash(params, key, hash); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(res), nil
+}
+
+func handleHSETEX(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := hsetexKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	now := params.GetClock().Now()
+
+	// HSETEX key [FNX | FXX] [EX seconds | PX milliseconds | EXAT unix-time-seconds | PXAT unix-time-milliseconds |
+	// KEEPTTL] FIELDS numfields field value [field value ...]
+	var condition string
+	var expireAt time.Time
+	var expire, keepTTL bool
+	i := 2
+	for ; i < len(params.Command) && !strings.EqualFold(params.Command[i], "fields"); i++ {
+		switch option := strings.ToLower(params.Command[i]); option {
+		case "fnx", "fxx":
+			if condition != "" {
+				return nil, errors.New(constants.InvalidCmdResponse)
+			}
+			condition = option
+		case "ex", "px", "exat", "pxat":
+			if expire || keepTTL || i+1 >= len(params.Command) {
+				return nil, errors.New(constants.InvalidCmdResponse)
+			}
+			if expireAt, err = parseExpireAt(now, params.Command[i], params.Command[i+1]); err != nil {
+				return nil, err
+			}
+			expire = true
+			i++
+		case "keepttl":
+			if expire || keepTTL {
+				return nil, errors.New(constants.InvalidCmdResponse)
+			}
+			keepTTL = true
+		default:
+			return nil, fmt.Errorf("unknown option %s", strings.ToUpper(params.Command[i]))
+		}
+	}
+
+	entries, err := parseFields(params.Command[i:], true)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := NewHash(nil)
+	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
+	if keyExists {
+		var ok bool
+		if hash, ok = params.GetValues(params.Context, []string{key})[key].(*Hash); !ok {
+			return nil, fmt.Errorf("value at %s is not a hash", key)
+		}
+	}
+
+	for i := 0; i < len(entries); i += 2 {
+		exists := hash.Contains(entries[i])
+		if (condition == "fnx" && exists) || (condition == "fxx" && !exists) {
+			return []byte(":0\r\n"), nil
+		}
+	}
+
+	for i := 0; i < len(entries); i += 2 {
+		field := entries[i]
+		if expire && !expireAt.After(now) {
+			// An expire time in the past deletes the field.
+			hash.Delete(field)
+			continue
+		}
+		value := HashValue{Value: internal.AdaptType(entries[i+1])}
+		if keepTTL {
+			value.ExpireAt = hash.Get(field).ExpireAt
+		}
+		hash.Set(field, value)
+	}
+
+	if hash.Len() == 0 && !keyExists {
+		return []byte(":1\r\n"), nil
+	}
+	if err = storeHash(params, key, hash); err != nil {
+		return nil, err
+	}
+
+	if expire && expireAt.After(now) {
+		for i := 0; i < len(entries); i += 2 {
+			if err = params.SetHashExpiry(params.Context, key, entries[i], expireAt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return []byte(":1\r\n"), nil
+}
+
+// parseFields parses the FIELDS numfields field [field ...] arguments of the field expiry commands and returns
+// the fields. When withValues is true, each field is followed by its value and both are returned.
+func parseFields(args []string, withValues bool) ([]string, error) {
+	if len(args) < 2 || !strings.EqualFold(args[0], "fields") {
+		return nil, errors.New(fmt.Sprintf(constants.MissingArgResponse, "FIELDS"))
+	}
+	numfields, err := strconv.Atoi(args[1])
+	if err != nil || numfields <= 0 {
+		return nil, errors.New("numfields must be a positive integer")
+	}
+	if withValues {
+		numfields *= 2
+	}
+	if len(args[2:]) != numfields {
+		return nil, errors.New("numfields must match the number of fields")
+	}
+	return args[2:], nil
+}
+
+// parseExpireAt returns the expire time described by an EX, PX, EXAT or PXAT option and its argument.
+func parseExpireAt(now time.Time, option string, arg string) (time.Time, error) {
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("invalid expire time for %s option", strings.ToUpper(option))
+	}
+	switch strings.ToLower(option) {
+	case "ex":
+		return now.Add(time.Duration(n) * time.Second), nil
+	case "px":
+		return now.Add(time.Duration(n) * time.Millisecond), nil
+	case "exat":
+		return time.Unix(n, 0), nil
+	default:
+		return time.UnixMilli(n), nil
+	}
+}
+
+// storeHash writes the hash back to the keyspace, deleting the key when the hash has no fields left.
+func storeHash(params internal.HandlerFuncParams, key string, hash *Hash) error {
+	if hash.Len() == 0 {
+		return params.DeleteKey(params.Context, key)
+	}
+	return params.SetValues(params.Context, map[string]interface{}{key: hash})
+}
+
+// encodeHashValue returns the RESP encoding of the value of a hash field.
+func encodeHashValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case int:
+		return fmt.Sprintf(":%d\r\n", v)
+	case float64:
+		fs := strconv.FormatFloat(v, 'f', -1, 64)
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(fs), fs)
+	default:
+		return "$-1\r\n"
+	}
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -1045,6 +1475,18 @@ Return the string length of the values stored at the specified fields. 0 if the
 			KeyExtractionFunc: hkeysKeyFunc,
 			HandlerFunc:       handleHKEYS,
 		},
+		{
+			Command:    "hscan",
+			Module:     constants.HashModule,
+			Categories: []string{constants.HashCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(HSCAN key cursor [MATCH pattern] [COUNT count] [NOVALUES])
+Incrementally iterates over the fields of a hash and their values. Start the iteration with a cursor of 0 and
+continue with the cursor returned by each call until it returns 0. NOVALUES returns only the fields.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: hscanKeyFunc,
+			HandlerFunc:       handleHSCAN,
+		},
 		{
 			Command:           "hincrbyfloat",
 			Module:            constants.HashModule,
@@ -1113,6 +1555,63 @@ Return the string length of the values stored at the specified fields. 0 if the
 			KeyExtractionFunc: hexpireatKeyFunc,
 			HandlerFunc:       handleHEXPIREAT,
 		},
+		{
+			Command:           "hpexpire",
+			Module:            constants.HashModule,
+			Categories:        []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
+			Description:       `(HPEXPIRE key milliseconds [NX | XX | GT | LT] FIELDS numfields field [field ...]) Sets the expiration, in milliseconds, of a field in a hash.`,
+			Sync:              true,
+			KeyExtractionFunc: hpexpireKeyFunc,
+			HandlerFunc:       handleHEXPIRE,
+		},
+		{
+			Command:           "hpexpireat",
+			Module:            constants.HashModule,
+			Categories:        []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
+			Description:       `(HPEXPIREAT key unix-time-milliseconds [NX | XX | GT | LT] FIELDS numfields field [field ...]) Sets the exact expiration time, in unix milliseconds, of a field in a hash.`,
+			Sync:              true,
+			KeyExtractionFunc: hpexpireatKeyFunc,
+			HandlerFunc:       handleHPEXPIREAT,
+		},
+		{
+			Command:           "hpersist",
+			Module:            constants.HashModule,
+			Categories:        []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
+			Description:       `(HPERSIST key FIELDS numfields field [field ...]) Removes the expiration of the given fields in a hash.`,
+			Sync:              true,
+			KeyExtractionFunc: hpersistKeyFunc,
+			HandlerFunc:       handleHPERSIST,
+		},
+		{
+			Command:           "hgetdel",
+			Module:            constants.HashModule,
+			Categories:        []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
+			Description:       `(HGETDEL key FIELDS numfields field [field ...]) Returns the values of the given fields and deletes them from the hash.`,
+			Sync:              true,
+			KeyExtractionFunc: hgetdelKeyFunc,
+			HandlerFunc:       handleHGETDEL,
+		},
+		{
+			Command:    "hgetex",
+			Module:     constants.HashModule,
+			Categories: []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(HGETEX key [EX seconds | PX milliseconds | EXAT unix-time-seconds | PXAT unix-time-milliseconds | PERSIST]
+FIELDS numfields field [field ...]) Returns the values of the given fields and optionally sets or removes their expiration.`,
+			Sync:              true,
+			KeyExtractionFunc: hgetexKeyFunc,
+			HandlerFunc:       handleHGETEX,
+		},
+		{
+			Command:    "hsetex",
+			Module:     constants.HashModule,
+			Categories: []string{constants.HashCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(HSETEX key [FNX | FXX] [EX seconds | PX milliseconds | EXAT unix-time-seconds | PXAT unix-time-milliseconds | KEEPTTL]
+FIELDS numfields field value [field value ...]) Sets the values of the given fields and optionally their expiration.
+FNX only sets the fields if none of them exist, FXX only sets them if all of them exist.`,
+			Sync:              true,
+			KeyExtractionFunc: hsetexKeyFunc,
+			HandlerFunc:       handleHSETEX,
+		},
 		{
 			Command:           "httl",
 			Module:            constants.HashModule,
@@ -1122,6 +1621,15 @@ Return the string length of the values stored at the specified fields. 0 if the
 			KeyExtractionFunc: httlKeyFunc,
 			HandlerFunc:       handleHTTL,
 		},
+		{
+			Command:           "hpttl",
+			Module:            constants.HashModule,
+			Categories:        []string{constants.HashCategory, constants.ReadCategory, constants.FastCategory},
+			Description:       `(HPTTL key FIELDS numfields field [field ...]) Returns the remaining TTL, in milliseconds, of a hash key's field(s) that have a set expiration.`,
+			Sync:              false,
+			KeyExtractionFunc: hpttlKeyFunc,
+			HandlerFunc:       handleHTTL,
+		},
 		{
 			Command:           "hpexpiretime",
 			Module:            constants.HashModule,
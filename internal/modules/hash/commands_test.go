@@ -1523,6 +1523,182 @@ func Test_Hash(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_HandleHSCAN", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		largeHash := make(map[string]string)
+		for i := 0; i < 30; i++ {
+			largeHash[fmt.Sprintf("field%d", i)] = strconv.Itoa(i)
+		}
+
+		tests := []struct {
+			name             string
+			key              string
+			presetValue      interface{}
+			args             []string
+			expectedResponse map[string]string
+			expectedError    error
+		}{
+			{
+				name:             "1. Return all the fields and values of the hash",
+				key:              "HscanKey1",
+				presetValue:      map[string]string{"field1": "value1", "field2": "123456789", "field3": "3.142"},
+				args:             []string{},
+				expectedResponse: map[string]string{"field1": "value1", "field2": "123456789", "field3": "3.142"},
+				expectedError:    nil,
+			},
+			{
+				name:             "2. Iterate over a large hash with a small count",
+				key:              "HscanKey2",
+				presetValue:      largeHash,
+				args:             []string{"COUNT", "4"},
+				expectedResponse: largeHash,
+				expectedError:    nil,
+			},
+			{
+				name:        "3. Only return the fields that match the pattern",
+				key:         "HscanKey3",
+				presetValue: largeHash,
+				args:        []string{"MATCH", "field2?", "COUNT", "5"},
+				expectedResponse: map[string]string{
+					"field20": "20", "field21": "21", "field22": "22", "field23": "23", "field24": "24",
+					"field25": "25", "field26": "26", "field27": "27", "field28": "28", "field29": "29",
+				},
+				expectedError: nil,
+			},
+			{
+				name:             "4. Only return the fields with NOVALUES",
+				key:              "HscanKey4",
+				presetValue:      map[string]string{"field1": "value1", "field2": "value2"},
+				args:             []string{"NOVALUES"},
+				expectedResponse: map[string]string{"field1": "", "field2": ""},
+				expectedError:    nil,
+			},
+			{
+				name:             "5. Empty response when the key does not exist",
+				key:              "HscanKey5",
+				presetValue:      nil,
+				args:             []string{},
+				expectedResponse: map[string]string{},
+				expectedError:    nil,
+			},
+			{
+				name:          "6. Return error when the key is not a hash",
+				key:           "HscanKey6",
+				presetValue:   "Default value",
+				args:          []string{},
+				expectedError: errors.New("value at HscanKey6 is not a hash"),
+			},
+			{
+				name:          "7. Return error when count is not a positive integer",
+				key:           "HscanKey7",
+				presetValue:   nil,
+				args:          []string{"COUNT", "0"},
+				expectedError: errors.New("count must be a positive integer"),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if test.presetValue != nil {
+					var command []resp.Value
+					var expected string
+
+					switch test.presetValue.(type) {
+					case string:
+						command = []resp.Value{
+							resp.StringValue("SET"),
+							resp.StringValue(test.key),
+							resp.StringValue(test.presetValue.(string)),
+						}
+						expected = "ok"
+					case map[string]string:
+						command = []resp.Value{resp.StringValue("HSET"), resp.StringValue(test.key)}
+						for key, value := range test.presetValue.(map[string]string) {
+							command = append(command, []resp.Value{
+								resp.StringValue(key),
+								resp.StringValue(value)}...,
+							)
+						}
+						expected = strconv.Itoa(len(test.presetValue.(map[string]string)))
+					}
+
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if !strings.EqualFold(res.String(), expected) {
+						t.Errorf("expected preset response to be \"%s\", got %s", expected, res.String())
+					}
+				}
+
+				noValues := slices.Contains(test.args, "NOVALUES")
+				got := make(map[string]string)
+				cursor := "0"
+				for {
+					command := []resp.Value{resp.StringValue("HSCAN"), resp.StringValue(test.key), resp.StringValue(cursor)}
+					for _, arg := range test.args {
+						command = append(command, resp.StringValue(arg))
+					}
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if test.expectedError != nil {
+						if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+							t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error())
+						}
+						return
+					}
+
+					elements := res.Array()[1].Array()
+					for i := 0; i < len(elements); i++ {
+						field := elements[i].String()
+						if _, ok := got[field]; ok {
+							t.Errorf("field \"%s\" returned more than once", field)
+						}
+						if noValues {
+							got[field] = ""
+							continue
+						}
+						got[field] = elements[i+1].String()
+						i++
+					}
+
+					if cursor = res.Array()[0].String(); cursor == "0" {
+						break
+					}
+				}
+
+				if len(got) != len(test.expectedResponse) {
+					t.Errorf("expected response of length %d, got %d", len(test.expectedResponse), len(got))
+				}
+				for field, value := range test.expectedResponse {
+					if got[field] != value {
+						t.Errorf("expected field \"%s\" to have value \"%s\", got \"%s\"", field, value, got[field])
+					}
+				}
+			})
+		}
+	})
+
 	t.Run("Test_HandleHGETALL", func(t *testing.T) {
 		t.Parallel()
 		conn, err := internal.GetConnection("localhost", port)
@@ -1540,15 +1716,15 @@ func Test_Hash(t *testing.T) {
 			key              string
 			presetValue      interface{}
 			command          []string
-			expectedResponse hash.Hash
+			expectedResponse *hash.Hash
 			expectedError    error
 		}{
 			{
 				name:             "1. Return an array containing all the fields and values of the hash",
 				key:              "HGetAllKey1",
-				presetValue:      hash.Hash{"field1": hash.HashValue{Value: "value1"}, "field2": hash.HashValue{Value: "123456789"}, "field3": hash.HashValue{Value: "3.142"}},
+				presetValue:      hash.NewHash(map[string]hash.HashValue{"field1": hash.HashValue{Value: "value1"}, "field2": hash.HashValue{Value: "123456789"}, "field3": hash.HashValue{Value: "3.142"}}),
 				command:          []string{"HGETALL", "HGetAllKey1"},
-				expectedResponse: hash.Hash{"field1": hash.HashValue{Value: "value1"}, "field2": hash.HashValue{Value: "123456789"}, "field3": hash.HashValue{Value: "3.142"}},
+				expectedResponse: hash.NewHash(map[string]hash.HashValue{"field1": hash.HashValue{Value: "value1"}, "field2": hash.HashValue{Value: "123456789"}, "field3": hash.HashValue{Value: "3.142"}}),
 				expectedError:    nil,
 			},
 			{
@@ -1599,15 +1775,15 @@ func Test_Hash(t *testing.T) {
 							resp.StringValue(test.presetValue.(string)),
 						}
 						expected = "ok"
-					case hash.Hash:
+					case *hash.Hash:
 						command = []resp.Value{resp.StringValue("HSET"), resp.StringValue(test.key)}
-						for key, value := range test.presetValue.(hash.Hash) {
+						for key, value := range test.presetValue.(*hash.Hash).All() {
 							command = append(command, []resp.Value{
 								resp.StringValue(key),
 								resp.StringValue(value.Value.(string))}...,
 							)
 						}
-						expected = strconv.Itoa(len(test.presetValue.(hash.Hash)))
+						expected = strconv.Itoa(test.presetValue.(*hash.Hash).Len())
 					}
 
 					if err = client.WriteArray(command); err != nil {
@@ -1655,8 +1831,8 @@ func Test_Hash(t *testing.T) {
 						field := item.String()
 						value := hash.HashValue{Value: res.Array()[i+1].String()}
 
-						if test.expectedResponse[field] != value {
-							t.Errorf("expected value at field \"%s\" to be \"%s\", got \"%s\"", field, test.expectedResponse[field], value)
+						if test.expectedResponse.Get(field) != value {
+							t.Errorf("expected value at field \"%s\" to be \"%s\", got \"%s\"", field, test.expectedResponse.Get(field), value)
 						}
 					}
 				}
@@ -1951,7 +2127,7 @@ func Test_Hash(t *testing.T) {
 		tests := []struct {
 			name          string
 			key           string
-			presetValue   hash.Hash
+			presetValue   *hash.Hash
 			command       []string
 			expectedValue string
 			expectedError error
@@ -1960,7 +2136,7 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "1. Set expiration for all keys in hash, no options.",
 				key:  "HexpireKey1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK1Field1": hash.HashValue{
 						Value: "default1",
 					},
@@ -1970,7 +2146,7 @@ func Test_Hash(t *testing.T) {
 					"HexpireK1Field3": hash.HashValue{
 						Value: "default3",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey1", "5", "FIELDS", "3", "HexpireK1Field1", "HexpireK1Field2", "HexpireK1Field3"},
 				expectedValue: "[1 1 1]",
 				expectedError: nil,
@@ -1978,11 +2154,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "2. Set expiration for one key in hash, no options.",
 				key:  "HexpireKey2",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK2Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey2", "5", "FIELDS", "1", "HexpireK2Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -1990,12 +2166,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "3. Set expiration, expireTime already populated, no options.",
 				key:  "HexpireKey3",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK3Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey3", "100", "FIELDS", "1", "HexpireK3Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2003,11 +2179,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "4. Set expiration, option NX with no expire time currently set.",
 				key:  "HexpireKey4",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK4Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey4", "5", "NX", "FIELDS", "1", "HexpireK4Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2015,12 +2191,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "5. Set expiration, option NX with an expire time already set.",
 				key:  "HexpireKey5",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK5Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey5", "100", "NX", "FIELDS", "1", "HexpireK5Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2028,11 +2204,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "6. Set expiration, option XX with no expire time currently set.",
 				key:  "HexpireKey6",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK6Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey6", "5", "XX", "FIELDS", "1", "HexpireK6Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2040,12 +2216,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "7. Set expiration, option XX with expire time already set.",
 				key:  "HexpireKey7",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK7Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey7", "100", "XX", "FIELDS", "1", "HexpireK7Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2053,12 +2229,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "8. Set expiration, option GT with expire time less than one provided.",
 				key:  "HexpireKey8",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK8Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey8", "1000", "GT", "FIELDS", "1", "HexpireK8Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2066,12 +2242,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "9. Set expiration, option GT with expire time greater than one provided.",
 				key:  "HexpireKey9",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK9Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey9", "100", "GT", "FIELDS", "1", "HexpireK9Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2079,12 +2255,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "10. Set expiration, option LT with expire time less than one provided.",
 				key:  "HexpireKey10",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK10Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey10", "1000", "LT", "FIELDS", "1", "HexpireK10Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2092,12 +2268,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "11. Set expiration, option LT with expire time greater than one provided.",
 				key:  "HexpireKey11",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK11Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey11", "100", "LT", "FIELDS", "1", "HexpireK11Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2105,11 +2281,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "12. Set expiration, provide 0 seconds.",
 				key:  "HexpireKey12",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK12Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey12", "0", "FIELDS", "1", "HexpireK12Field1"},
 				expectedValue: "[2]",
 				expectedError: nil,
@@ -2125,11 +2301,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "14. Attempt to set expiration for field that doesn't exist.",
 				key:  "HexpireKey14",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK14Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey14", "100", "FIELDS", "2", "HexpireK14BadField1", "HexpireK14Field1"},
 				expectedValue: "[-2 1]",
 				expectedError: nil,
@@ -2137,22 +2313,22 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "15. Set expiration, command wrong length.",
 				key:  "HexpireKey15",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK15Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey15", "100", "1", "HexpireK15Field1"},
 				expectedError: errors.New("Error wrong number of arguments"),
 			},
 			{
 				name: "16. Set expiration, command filed numfields is not a number.",
 				key:  "HexpireKey16",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK16Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIRE", "HexpireKey16", "100", "FIELDS", "one", "HexpireK16Field1"},
 				expectedError: errors.New("Error numberfields must be integer, was provided \"one\""),
 			},
@@ -2167,13 +2343,13 @@ func Test_Hash(t *testing.T) {
 					var expected string
 
 					command = []resp.Value{resp.StringValue("HSET"), resp.StringValue(test.key)}
-					for key, value := range test.presetValue {
+					for key, value := range test.presetValue.All() {
 						command = append(command, []resp.Value{
 							resp.StringValue(key),
 							resp.StringValue(value.Value.(string))}...,
 						)
 					}
-					expected = strconv.Itoa(len(test.presetValue))
+					expected = strconv.Itoa(test.presetValue.Len())
 
 					if err = client.WriteArray(command); err != nil {
 						t.Error(err)
@@ -2190,26 +2366,28 @@ func Test_Hash(t *testing.T) {
 				}
 
 				// preset Expire Time
-				for field, value := range test.presetValue {
-					if value.ExpireAt != (time.Time{}) {
-						cmd := []resp.Value{
-							resp.StringValue("HEXPIRE"),
-							resp.StringValue(test.key),
-							resp.StringValue("500"),
-							resp.StringValue("FIELDS"),
-							resp.StringValue("1"),
-							resp.StringValue(field),
-						}
+				if test.presetValue != nil {
+					for field, value := range test.presetValue.All() {
+						if value.ExpireAt != (time.Time{}) {
+							cmd := []resp.Value{
+								resp.StringValue("HEXPIRE"),
+								resp.StringValue(test.key),
+								resp.StringValue("500"),
+								resp.StringValue("FIELDS"),
+								resp.StringValue("1"),
+								resp.StringValue(field),
+							}
 
-						if err = client.WriteArray(cmd); err != nil {
-							t.Error(err)
-						}
-						res, _, err := client.ReadValue()
-						if err != nil {
-							t.Error(err)
-						}
-						if res.String() != "[1]" {
-							t.Errorf("Error presetting expire time - Key: %s, Field: %s,  response: %s", test.key, field, res.String())
+							if err = client.WriteArray(cmd); err != nil {
+								t.Error(err)
+							}
+							res, _, err := client.ReadValue()
+							if err != nil {
+								t.Error(err)
+							}
+							if res.String() != "[1]" {
+								t.Errorf("Error presetting expire time - Key: %s, Field: %s,  response: %s", test.key, field, res.String())
+							}
 						}
 					}
 				}
@@ -2259,7 +2437,7 @@ func Test_Hash(t *testing.T) {
 		tests := []struct {
 			name          string
 			key           string
-			presetValue   hash.Hash
+			presetValue   *hash.Hash
 			command       []string
 			expectedValue string
 			expectedError error
@@ -2268,7 +2446,7 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "1. Set expiration for all keys in hash, no options.",
 				key:  "HexpireAtKey1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK1Field1": hash.HashValue{
 						Value: "default1",
 					},
@@ -2278,7 +2456,7 @@ func Test_Hash(t *testing.T) {
 					"HexpireK1Field3": hash.HashValue{
 						Value: "default3",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey1", strconv.FormatInt(mockClock.Now().Unix()+5, 10), "FIELDS", "3", "HexpireK1Field1", "HexpireK1Field2", "HexpireK1Field3"},
 				expectedValue: "[1 1 1]",
 				expectedError: nil,
@@ -2286,11 +2464,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "2. Set expiration for one key in hash, no options.",
 				key:  "HexpireAtKey2",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK2Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey2", strconv.FormatInt(mockClock.Now().Unix()+5, 10), "FIELDS", "1", "HexpireK2Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2298,12 +2476,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "3. Set expiration, expireTime already populated, no options.",
 				key:  "HexpireAtKey3",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK3Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey3", strconv.FormatInt(mockClock.Now().Unix()+100, 10), "FIELDS", "1", "HexpireK3Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2311,11 +2489,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "4. Set expiration, option NX with no expire time currently set.",
 				key:  "HexpireAtKey4",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK4Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey4", strconv.FormatInt(mockClock.Now().Unix()+5, 10), "NX", "FIELDS", "1", "HexpireK4Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2323,12 +2501,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "5. Set expiration, option NX with an expire time already set.",
 				key:  "HexpireAtKey5",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK5Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey5", strconv.FormatInt(mockClock.Now().Unix()+100, 10), "NX", "FIELDS", "1", "HexpireK5Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2336,11 +2514,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "6. Set expiration, option XX with no expire time currently set.",
 				key:  "HexpireAtKey6",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK6Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey6", strconv.FormatInt(mockClock.Now().Unix()+5, 10), "XX", "FIELDS", "1", "HexpireK6Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2348,12 +2526,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "7. Set expiration, option XX with expire time already set.",
 				key:  "HexpireAtKey7",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK7Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey7", strconv.FormatInt(mockClock.Now().Unix()+100, 10), "XX", "FIELDS", "1", "HexpireK7Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2361,12 +2539,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "8. Set expiration, option GT with expire time less than one provided.",
 				key:  "HexpireAtKey8",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK8Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey8", strconv.FormatInt(mockClock.Now().Unix()+1000, 10), "GT", "FIELDS", "1", "HexpireK8Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2374,12 +2552,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "9. Set expiration, option GT with expire time greater than one provided.",
 				key:  "HexpireAtKey9",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK9Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey9", strconv.FormatInt(mockClock.Now().Unix()+100, 10), "GT", "FIELDS", "1", "HexpireK9Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2387,12 +2565,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "10. Set expiration, option LT with expire time less than one provided.",
 				key:  "HexpireAtKey10",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK10Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey10", strconv.FormatInt(mockClock.Now().Unix()+1000, 10), "LT", "FIELDS", "1", "HexpireK10Field1"},
 				expectedValue: "[0]",
 				expectedError: nil,
@@ -2400,12 +2578,12 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "11. Set expiration, option LT with expire time greater than one provided.",
 				key:  "HexpireAtKey11",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK11Field1": hash.HashValue{
 						Value:    "default1",
 						ExpireAt: mockClock.Now().Add(500 * time.Second),
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey11", strconv.FormatInt(mockClock.Now().Unix()+100, 10), "LT", "FIELDS", "1", "HexpireK11Field1"},
 				expectedValue: "[1]",
 				expectedError: nil,
@@ -2413,11 +2591,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "12. Set expiration, provide 0 seconds.",
 				key:  "HexpireAtKey12",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK12Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey12", strconv.FormatInt(mockClock.Now().Unix()-10, 10), "FIELDS", "1", "HexpireK12Field1"},
 				expectedValue: "[2]",
 				expectedError: nil,
@@ -2433,11 +2611,11 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "14. Attempt to set expiration for field that doesn't exist.",
 				key:  "HexpireAtKey14",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK14Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey14", strconv.FormatInt(mockClock.Now().Unix()+10, 10), "FIELDS", "2", "HexpireK14BadField1", "HexpireK14Field1"},
 				expectedValue: "[-2 1]",
 				expectedError: nil,
@@ -2445,22 +2623,22 @@ func Test_Hash(t *testing.T) {
 			{
 				name: "15. Set expiration, command wrong length.",
 				key:  "HexpireAtKey15",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK15Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey15", strconv.FormatInt(mockClock.Now().Unix()+10, 10), "1", "HexpireK15Field1"},
 				expectedError: errors.New("Error wrong number of arguments"),
 			},
 			{
 				name: "16. Set expiration, command filed numfields is not a number.",
 				key:  "HexpireAtKey16",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HexpireK16Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				command:       []string{"HEXPIREAT", "HexpireAtKey16", strconv.FormatInt(mockClock.Now().Unix()+10, 10), "FIELDS", "one", "HexpireK16Field1"},
 				expectedError: errors.New("Error numberfields must be integer, was provided \"one\""),
 			},
@@ -2475,13 +2653,13 @@ func Test_Hash(t *testing.T) {
 					var expected string
 
 					command = []resp.Value{resp.StringValue("HSET"), resp.StringValue(test.key)}
-					for key, value := range test.presetValue {
+					for key, value := range test.presetValue.All() {
 						command = append(command, []resp.Value{
 							resp.StringValue(key),
 							resp.StringValue(value.Value.(string))}...,
 						)
 					}
-					expected = strconv.Itoa(len(test.presetValue))
+					expected = strconv.Itoa(test.presetValue.Len())
 
 					if err = client.WriteArray(command); err != nil {
 						t.Error(err)
@@ -2498,26 +2676,28 @@ func Test_Hash(t *testing.T) {
 				}
 
 				// preset Expire Time
-				for field, value := range test.presetValue {
-					if value.ExpireAt != (time.Time{}) {
-						cmd := []resp.Value{
-							resp.StringValue("HEXPIRE"),
-							resp.StringValue(test.key),
-							resp.StringValue("500"),
-							resp.StringValue("FIELDS"),
-							resp.StringValue("1"),
-							resp.StringValue(field),
-						}
+				if test.presetValue != nil {
+					for field, value := range test.presetValue.All() {
+						if value.ExpireAt != (time.Time{}) {
+							cmd := []resp.Value{
+								resp.StringValue("HEXPIRE"),
+								resp.StringValue(test.key),
+								resp.StringValue("500"),
+								resp.StringValue("FIELDS"),
+								resp.StringValue("1"),
+								resp.StringValue(field),
+							}
 
-						if err = client.WriteArray(cmd); err != nil {
-							t.Error(err)
-						}
-						res, _, err := client.ReadValue()
-						if err != nil {
-							t.Error(err)
-						}
-						if res.String() != "[1]" {
-							t.Errorf("Error presetting expire time - Key: %s, Field: %s,  response: %s", test.key, field, res.String())
+							if err = client.WriteArray(cmd); err != nil {
+								t.Error(err)
+							}
+							res, _, err := client.ReadValue()
+							if err != nil {
+								t.Error(err)
+							}
+							if res.String() != "[1]" {
+								t.Errorf("Error presetting expire time - Key: %s, Field: %s,  response: %s", test.key, field, res.String())
+							}
 						}
 					}
 				}
@@ -2578,11 +2758,11 @@ func Test_Hash(t *testing.T) {
 				name:    "1. Get TTL for one field when expireTime is set.",
 				key:     "HTTLKey1",
 				command: []string{"HTTL", "HTTLKey1", "FIELDS", "1", "HTTLK1Field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK1Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     true,
 				expectedValue: "[5]",
 				expectedError: nil,
@@ -2591,7 +2771,7 @@ func Test_Hash(t *testing.T) {
 				name:    "2. Get TTL for multiple fields when expireTime is set.",
 				key:     "HTTLKey2",
 				command: []string{"HTTL", "HTTLKey2", "FIELDS", "3", "HTTLK2Field1", "HTTLK2Field2", "HTTLK2Field3"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK2Field1": hash.HashValue{
 						Value: "default1",
 					},
@@ -2601,7 +2781,7 @@ func Test_Hash(t *testing.T) {
 					"HTTLK2Field3": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     true,
 				expectedValue: "[5 5 5]",
 				expectedError: nil,
@@ -2610,11 +2790,11 @@ func Test_Hash(t *testing.T) {
 				name:    "3. Get TTL for one field when expireTime is not set.",
 				key:     "HTTLKey3",
 				command: []string{"HTTL", "HTTLKey3", "FIELDS", "1", "HTTLK3Field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK3Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedValue: "[-1]",
 				expectedError: nil,
@@ -2623,7 +2803,7 @@ func Test_Hash(t *testing.T) {
 				name:    "4. Get TTL for multiple fields when expireTime is not set.",
 				key:     "HTTLKey4",
 				command: []string{"HTTL", "HTTLKey4", "FIELDS", "3", "HTTLK4Field1", "HTTLK4Field2", "HTTLK4Field3"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK4Field1": hash.HashValue{
 						Value: "default1",
 					},
@@ -2633,7 +2813,7 @@ func Test_Hash(t *testing.T) {
 					"HTTLK4Field3": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedValue: "[-1 -1 -1]",
 				expectedError: nil,
@@ -2659,11 +2839,11 @@ func Test_Hash(t *testing.T) {
 				name:    "7. Command missing 'FIELDS'.",
 				key:     "HTTLKey7",
 				command: []string{"HTTL", "HTTLKey7", "1", "HTTLK7Field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK7Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedError: errors.New("Error wrong number of arguments"),
 			},
@@ -2671,11 +2851,11 @@ func Test_Hash(t *testing.T) {
 				name:    "8. Command numfields provided isn't a number.",
 				key:     "HTTLKey8",
 				command: []string{"HTTL", "HTTLKey8", "FIELDS", "one", "HTTLK8Field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK8Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedError: errors.New("Error expire time must be integer, was provided \"one\""),
 			},
@@ -2683,11 +2863,11 @@ func Test_Hash(t *testing.T) {
 				name:    "9. Command missing numfields.",
 				key:     "HTTLKey9",
 				command: []string{"HTTL", "HTTLKey9", "FIELDS", "HTTLK9Field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK9Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedError: errors.New("Error wrong number of arguments"),
 			},
@@ -2695,11 +2875,11 @@ func Test_Hash(t *testing.T) {
 				name:    "10. Command FIELDS index contains something else.",
 				key:     "HTTLKey10",
 				command: []string{"HTTL", "HTTLKey10", "NOTFIELDS", "1", "HTTLK10Field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"HTTLK10Field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedError: errors.New("Error invalid command provided"),
 			},
@@ -2720,15 +2900,15 @@ func Test_Hash(t *testing.T) {
 							resp.StringValue(test.presetValue.(string)),
 						}
 						expected = "ok"
-					case hash.Hash:
+					case *hash.Hash:
 						command = []resp.Value{resp.StringValue("HSET"), resp.StringValue(test.key)}
-						for key, value := range test.presetValue.(hash.Hash) {
+						for key, value := range test.presetValue.(*hash.Hash).All() {
 							command = append(command, []resp.Value{
 								resp.StringValue(key),
 								resp.StringValue(value.Value.(string))}...,
 							)
 						}
-						expected = strconv.Itoa(len(test.presetValue.(hash.Hash)))
+						expected = strconv.Itoa(test.presetValue.(*hash.Hash).Len())
 					}
 
 					if err = client.WriteArray(command); err != nil {
@@ -2748,15 +2928,15 @@ func Test_Hash(t *testing.T) {
 
 				if test.setExpire {
 					// set expire times
-					command := make([]resp.Value, len(test.presetValue.(hash.Hash))+5)
+					command := make([]resp.Value, test.presetValue.(*hash.Hash).Len()+5)
 					command[0] = resp.StringValue("HEXPIRE")
 					command[1] = resp.StringValue(test.key)
 					command[2] = resp.StringValue("5")
 					command[3] = resp.StringValue("FIELDS")
-					command[4] = resp.StringValue(fmt.Sprintf("%v", (len(test.presetValue.(hash.Hash)))))
+					command[4] = resp.StringValue(fmt.Sprintf("%v", (test.presetValue.(*hash.Hash).Len())))
 
 					i := 0
-					for k, _ := range test.presetValue.(hash.Hash) {
+					for k, _ := range test.presetValue.(*hash.Hash).All() {
 						command[5+i] = resp.StringValue(k)
 						i++
 					}
@@ -2829,11 +3009,11 @@ func Test_Hash(t *testing.T) {
 				name:    "1. Single field with expiration",
 				key:     "HPExpireTimeKey1",
 				command: []string{"HPEXPIRETIME", "HPExpireTimeKey1", "FIELDS", "1", "field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     true,
 				expireSeconds: 500,
 				expectedValue: fmt.Sprintf("[%d]", fixedTimestamp),
@@ -2842,11 +3022,11 @@ func Test_Hash(t *testing.T) {
 				name:    "2. Single field with no expiration",
 				key:     "HPExpireTimeKey2",
 				command: []string{"HPEXPIRETIME", "HPExpireTimeKey2", "FIELDS", "1", "field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedValue: "[-1]",
 			},
@@ -2854,14 +3034,14 @@ func Test_Hash(t *testing.T) {
 				name:    "3. Multiple fields mixed",
 				key:     "HPExpireTimeKey3",
 				command: []string{"HPEXPIRETIME", "HPExpireTimeKey3", "FIELDS", "3", "field1", "field2", "nonexist"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
 					"field2": hash.HashValue{
 						Value: "default2",
 					},
-				},
+				}),
 				setExpire:     true,
 				expireSeconds: 500,
 				expectedValue: fmt.Sprintf("[%d %d -2]", fixedTimestamp, fixedTimestamp),
@@ -2887,11 +3067,11 @@ func Test_Hash(t *testing.T) {
 				name:    "6. Invalid numfields format",
 				key:     "HPExpireTimeKey6",
 				command: []string{"HPEXPIRETIME", "HPExpireTimeKey6", "FIELDS", "notanumber", "field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedValue: "",
 				expectedError: errors.New("expire time must be integer, was provided \"notanumber\""),
@@ -2910,9 +3090,9 @@ func Test_Hash(t *testing.T) {
 							resp.StringValue(test.key),
 							resp.StringValue(v),
 						}
-					case hash.Hash:
+					case *hash.Hash:
 						command = []resp.Value{resp.StringValue("HSET"), resp.StringValue(test.key)}
-						for key, value := range v {
+						for key, value := range v.All() {
 							command = append(command, resp.StringValue(key), resp.StringValue(value.Value.(string)))
 						}
 					}
@@ -2925,8 +3105,8 @@ func Test_Hash(t *testing.T) {
 					}
 
 					if test.setExpire {
-						if hash, ok := test.presetValue.(hash.Hash); ok {
-							for field := range hash {
+						if hash, ok := test.presetValue.(*hash.Hash); ok {
+							for field := range hash.All() {
 								expireCmd := []resp.Value{
 									resp.StringValue("HEXPIRE"),
 									resp.StringValue(test.key),
@@ -3002,11 +3182,11 @@ func Test_Hash(t *testing.T) {
 				name:    "1. Single field with expiration",
 				key:     "HExpireTimeKey1",
 				command: []string{"HEXPIRETIME", "HExpireTimeKey1", "FIELDS", "1", "field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     true,
 				expireSeconds: 500,
 				expectedValue: fmt.Sprintf("[%d]", fixedTimestamp),
@@ -3015,11 +3195,11 @@ func Test_Hash(t *testing.T) {
 				name:    "2. Single field with no expiration",
 				key:     "HExpireTimeKey2",
 				command: []string{"HEXPIRETIME", "HExpireTimeKey2", "FIELDS", "1", "field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedValue: "[-1]",
 			},
@@ -3027,14 +3207,14 @@ func Test_Hash(t *testing.T) {
 				name:    "3. Multiple fields mixed",
 				key:     "HExpireTimeKey3",
 				command: []string{"HEXPIRETIME", "HExpireTimeKey3", "FIELDS", "3", "field1", "field2", "nonexist"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
 					"field2": hash.HashValue{
 						Value: "default2",
 					},
-				},
+				}),
 				setExpire:     true,
 				expireSeconds: 500,
 				expectedValue: fmt.Sprintf("[%d %d -2]", fixedTimestamp, fixedTimestamp),
@@ -3060,11 +3240,11 @@ func Test_Hash(t *testing.T) {
 				name:    "6. Invalid numfields format",
 				key:     "HExpireTimeKey6",
 				command: []string{"HEXPIRETIME", "HExpireTimeKey6", "FIELDS", "notanumber", "field1"},
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "default1",
 					},
-				},
+				}),
 				setExpire:     false,
 				expectedValue: "",
 				expectedError: errors.New("expire time must be integer, was provided \"notanumber\""),
@@ -3083,9 +3263,9 @@ func Test_Hash(t *testing.T) {
 							resp.StringValue(test.key),
 							resp.StringValue(v),
 						}
-					case hash.Hash:
+					case *hash.Hash:
 						command = []resp.Value{resp.StringValue("HSET"), resp.StringValue(test.key)}
-						for key, value := range v {
+						for key, value := range v.All() {
 							command = append(command, resp.StringValue(key), resp.StringValue(value.Value.(string)))
 						}
 					}
@@ -3098,8 +3278,8 @@ func Test_Hash(t *testing.T) {
 					}
 
 					if test.setExpire {
-						if hash, ok := test.presetValue.(hash.Hash); ok {
-							for field := range hash {
+						if hash, ok := test.presetValue.(*hash.Hash); ok {
+							for field := range hash.All() {
 								expireCmd := []resp.Value{
 									resp.StringValue("HEXPIRE"),
 									resp.StringValue(test.key),
@@ -3145,4 +3325,144 @@ func Test_Hash(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Test_HandleHashFieldExpiry", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		nowMilli := mockClock.Now().UnixMilli()
+
+		// Each test runs its commands in order against a fresh key and checks the response of every command.
+		tests := []struct {
+			name     string
+			commands [][]string
+			expected []string
+		}{
+			{
+				name: "1. HPEXPIRE and HPTTL work in milliseconds",
+				commands: [][]string{
+					{"HSET", "HashExpiryKey1", "field1", "value1", "field2", "value2"},
+					{"HPEXPIRE", "HashExpiryKey1", "5000", "FIELDS", "2", "field1", "field3"},
+					{"HPTTL", "HashExpiryKey1", "FIELDS", "3", "field1", "field2", "field3"},
+					{"HTTL", "HashExpiryKey1", "FIELDS", "1", "field1"},
+					{"HPEXPIRE", "HashExpiryKey1", "9000", "NX", "FIELDS", "1", "field1"},
+					{"HPTTL", "HashExpiryKeyMissing", "FIELDS", "2", "field1", "field2"},
+				},
+				expected: []string{"2", "[1 -2]", "[5000 -1 -2]", "[5]", "[0]", "[-2 -2]"},
+			},
+			{
+				name: "2. HPEXPIREAT sets the expiry to a unix time in milliseconds",
+				commands: [][]string{
+					{"HSET", "HashExpiryKey2", "field1", "value1"},
+					{"HPEXPIREAT", "HashExpiryKey2", strconv.FormatInt(nowMilli+2500, 10), "FIELDS", "1", "field1"},
+					{"HPTTL", "HashExpiryKey2", "FIELDS", "1", "field1"},
+					{"HPEXPIRETIME", "HashExpiryKey2", "FIELDS", "1", "field1"},
+				},
+				expected: []string{"1", "[1]", "[2500]", fmt.Sprintf("[%d]", nowMilli+2500)},
+			},
+			{
+				name: "3. HPERSIST removes the expiry of fields",
+				commands: [][]string{
+					{"HSET", "HashExpiryKey3", "field1", "value1", "field2", "value2"},
+					{"HEXPIRE", "HashExpiryKey3", "10", "FIELDS", "1", "field1"},
+					{"HPERSIST", "HashExpiryKey3", "FIELDS", "3", "field1", "field2", "field3"},
+					{"HTTL", "HashExpiryKey3", "FIELDS", "1", "field1"},
+					{"HPERSIST", "HashExpiryKeyMissing", "FIELDS", "1", "field1"},
+				},
+				expected: []string{"2", "[1]", "[1 -1 -2]", "[-1]", "[-2]"},
+			},
+			{
+				name: "4. HGETDEL returns and deletes fields, and deletes the key when it is empty",
+				commands: [][]string{
+					{"HSET", "HashExpiryKey4", "field1", "value1", "field2", "2"},
+					{"HGETDEL", "HashExpiryKey4", "FIELDS", "2", "field1", "field3"},
+					{"HLEN", "HashExpiryKey4"},
+					{"HGETDEL", "HashExpiryKey4", "FIELDS", "1", "field2"},
+					{"EXISTS", "HashExpiryKey4"},
+				},
+				expected: []string{"2", "[value1 ]", "1", "[2]", "0"},
+			},
+			{
+				name: "5. HGETEX sets and removes the expiry of the fields it returns",
+				commands: [][]string{
+					{"HSET", "HashExpiryKey5", "field1", "value1", "field2", "value2"},
+					{"HGETEX", "HashExpiryKey5", "EX", "100", "FIELDS", "2", "field1", "field3"},
+					{"HTTL", "HashExpiryKey5", "FIELDS", "2", "field1", "field2"},
+					{"HGETEX", "HashExpiryKey5", "PERSIST", "FIELDS", "1", "field1"},
+					{"HTTL", "HashExpiryKey5", "FIELDS", "1", "field1"},
+					{"HGETEX", "HashExpiryKey5", "PXAT", "1", "FIELDS", "1", "field2"},
+					{"HGETALL", "HashExpiryKey5"},
+				},
+				expected: []string{"2", "[value1 ]", "[100 -1]", "[value1]", "[-1]", "[value2]", "[field1 value1]"},
+			},
+			{
+				name: "6. HSETEX sets fields with an expiry and honours FNX, FXX and KEEPTTL",
+				commands: [][]string{
+					{"HSETEX", "HashExpiryKey6", "PX", "1500", "FIELDS", "2", "field1", "value1", "field2", "value2"},
+					{"HPTTL", "HashExpiryKey6", "FIELDS", "2", "field1", "field2"},
+					{"HSETEX", "HashExpiryKey6", "FNX", "FIELDS", "2", "field2", "new", "field3", "value3"},
+					{"HSETEX", "HashExpiryKey6", "FXX", "KEEPTTL", "FIELDS", "1", "field1", "updated"},
+					{"HPTTL", "HashExpiryKey6", "FIELDS", "1", "field1"},
+					{"HSETEX", "HashExpiryKey6", "FIELDS", "1", "field1", "value1"},
+					{"HPTTL", "HashExpiryKey6", "FIELDS", "1", "field1"},
+					{"HGET", "HashExpiryKey6", "field1", "field3"},
+				},
+				expected: []string{"1", "[1500 1500]", "0", "1", "[1500]", "1", "[-1]", "[value1 ]"},
+			},
+			{
+				name: "7. Return errors on invalid arguments",
+				commands: [][]string{
+					{"HSETEX", "HashExpiryKey7", "FIELDS", "2", "field1", "value1"},
+					{"HGETEX", "HashExpiryKey7", "EX", "0", "FIELDS", "1", "field1"},
+					{"HGETEX", "HashExpiryKey7", "EX", "10", "PERSIST", "FIELDS", "1", "field1"},
+					{"HGETDEL", "HashExpiryKey7", "FIELDS", "0", "field1"},
+					{"HPERSIST", "HashExpiryKey7", "field1", "field2", "field3"},
+				},
+				expected: []string{
+					"numfields must match the number of fields",
+					"invalid expire time for EX option",
+					constants.InvalidCmdResponse,
+					"numfields must be a positive integer",
+					fmt.Sprintf(constants.MissingArgResponse, "FIELDS"),
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for i, command := range test.commands {
+					values := make([]resp.Value, len(command))
+					for j, v := range command {
+						values[j] = resp.StringValue(v)
+					}
+					if err = client.WriteArray(values); err != nil {
+						t.Error(err)
+						return
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if res.Error() != nil {
+						if !strings.Contains(res.Error().Error(), test.expected[i]) {
+							t.Errorf("command %v: expected error \"%s\", got \"%s\"", command, test.expected[i], res.Error())
+						}
+						continue
+					}
+					if res.String() != test.expected[i] {
+						t.Errorf("command %v: expected response %s, got %s", command, test.expected[i], res.String())
+					}
+				}
+			})
+		}
+	})
 }
@@ -1,9 +1,11 @@
 package hash
 
 import (
+	"iter"
 	"time"
 	"unsafe"
 
+	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/constants"
 )
 
@@ -12,15 +14,83 @@ type HashValue struct {
 	ExpireAt time.Time
 }
 
-type Hash map[string]HashValue
+// Hash is the value of a hash key. It maps the fields of the hash to their values.
+type Hash struct {
+	fields map[string]HashValue
+	// scanIndex keeps the fields ordered by their scan position for HSCAN.
+	scanIndex *internal.ScanIndex[string]
+}
+
+// NewHash returns a hash that holds the provided fields.
+func NewHash(fields map[string]HashValue) *Hash {
+	h := &Hash{
+		fields:    make(map[string]HashValue, len(fields)),
+		scanIndex: internal.NewScanIndex[string](),
+	}
+	for field, value := range fields {
+		h.Set(field, value)
+	}
+	return h
+}
+
+// Len returns the number of fields in the hash.
+func (h *Hash) Len() int {
+	return len(h.fields)
+}
+
+// Contains reports whether the field exists in the hash.
+func (h *Hash) Contains(field string) bool {
+	_, ok := h.fields[field]
+	return ok
+}
+
+// Get returns the value of the field, or the zero HashValue if the field does not exist.
+func (h *Hash) Get(field string) HashValue {
+	return h.fields[field]
+}
+
+// Set adds the field to the hash or replaces its value.
+func (h *Hash) Set(field string, value HashValue) {
+	if _, ok := h.fields[field]; !ok {
+		h.scanIndex.Insert(field)
+	}
+	h.fields[field] = value
+}
+
+// Delete removes the field from the hash. It returns false if the field does not exist.
+func (h *Hash) Delete(field string) bool {
+	if _, ok := h.fields[field]; !ok {
+		return false
+	}
+	delete(h.fields, field)
+	h.scanIndex.Delete(field)
+	return true
+}
+
+// All returns an iterator over the fields of the hash and their values.
+// Fields can be deleted from the hash while iterating.
+func (h *Hash) All() iter.Seq2[string, HashValue] {
+	return func(yield func(string, HashValue) bool) {
+		for field, value := range h.fields {
+			if !yield(field, value) {
+				return
+			}
+		}
+	}
+}
+
+// Scan returns up to count fields starting from cursor, and the cursor to continue the iteration from.
+func (h *Hash) Scan(cursor uint64, count int) (uint64, []string) {
+	return h.scanIndex.Scan(cursor, count)
+}
 
-func (h Hash) GetMem() int64 {
+func (h *Hash) GetMem() int64 {
 
 	var size int64
 	// Map headers
 	size += int64(unsafe.Sizeof(h))
 
-	for key, val := range h {
+	for key, val := range h.fields {
 
 		size += int64(unsafe.Sizeof(key))
 		size += int64(len(key))
@@ -42,6 +112,7 @@ func (h Hash) GetMem() int64 {
 			size += int64(len(vt))
 		}
 	}
+	size += h.scanIndex.GetMem()
 	return size
 }
 
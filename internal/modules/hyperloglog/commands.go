@@ -0,0 +1,161 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hyperloglog
+
+import (
+	"fmt"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+func handlePFADD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := pfaddKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
+
+	if !keyExists {
+		hll := NewHyperLogLog()
+		hll.Add(params.Command[2:])
+		if err = params.SetValues(params.Context, map[string]interface{}{key: hll}); err != nil {
+			return nil, err
+		}
+		return []byte(":1\r\n"), nil
+	}
+
+	hll, ok := params.GetValues(params.Context, []string{key})[key].(*HyperLogLog)
+	if !ok {
+		return nil, fmt.Errorf("value at key %s is not a hyperloglog", key)
+	}
+
+	if !hll.Add(params.Command[2:]) {
+		return []byte(":0\r\n"), nil
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: hll}); err != nil {
+		return nil, err
+	}
+	return []byte(":1\r\n"), nil
+}
+
+func handlePFCOUNT(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := pfcountKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	hlls, err := getHyperLogLogs(params, keys.ReadKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hlls) == 1 {
+		return []byte(fmt.Sprintf(":%d\r\n", hlls[0].Count())), nil
+	}
+
+	// The cardinality of multiple keys is the cardinality of their union.
+	union := NewHyperLogLog()
+	for _, hll := range hlls {
+		union.Merge(hll)
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", union.Count())), nil
+}
+
+func handlePFMERGE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := pfmergeKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	// The destination key is merged with the source keys if it exists.
+	hlls, err := getHyperLogLogs(params, append(keys.WriteKeys, keys.ReadKeys...))
+	if err != nil {
+		return nil, err
+	}
+
+	merged := NewHyperLogLog()
+	for _, hll := range hlls {
+		merged.Merge(hll)
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{keys.WriteKeys[0]: merged}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+// getHyperLogLogs returns the HyperLogLogs at the keys. Keys that don't exist are treated as empty HyperLogLogs.
+func getHyperLogLogs(params internal.HandlerFuncParams, keys []string) ([]*HyperLogLog, error) {
+	values := params.GetValues(params.Context, keys)
+	hlls := make([]*HyperLogLog, 0, len(keys))
+	for _, key := range keys {
+		if values[key] == nil {
+			hlls = append(hlls, NewHyperLogLog())
+			continue
+		}
+		hll, ok := values[key].(*HyperLogLog)
+		if !ok {
+			return nil, fmt.Errorf("value at key %s is not a hyperloglog", key)
+		}
+		hlls = append(hlls, hll)
+	}
+	return hlls, nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "pfadd",
+			Module:     constants.HyperLogLogModule,
+			Categories: []string{constants.HyperLogLogCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(PFADD key [element [element ...]])
+Adds the elements to the HyperLogLog at key. Creates the key if it doesn't exist.
+Returns 1 if the estimated cardinality changed or the key was created, otherwise 0.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: pfaddKeyFunc,
+			HandlerFunc:       handlePFADD,
+		},
+		{
+			Command:    "pfcount",
+			Module:     constants.HyperLogLogModule,
+			Categories: []string{constants.HyperLogLogCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(PFCOUNT key [key ...])
+Returns the estimated number of distinct elements added to the HyperLogLog at key.
+With multiple keys, returns the estimated cardinality of the union of the HyperLogLogs.
+The estimate has a standard error of 0.81%.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: pfcountKeyFunc,
+			HandlerFunc:       handlePFCOUNT,
+		},
+		{
+			Command:    "pfmerge",
+			Module:     constants.HyperLogLogModule,
+			Categories: []string{constants.HyperLogLogCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(PFMERGE destkey [sourcekey [sourcekey ...]])
+Merges the HyperLogLogs at the source keys into destkey, so that destkey estimates the cardinality of their union.
+If destkey exists, it's merged with the source keys.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: pfmergeKeyFunc,
+			HandlerFunc:       handlePFMERGE,
+		},
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hyperloglog_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+func Test_HyperLogLog(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	type command struct {
+		command          []string
+		expectedResponse interface{}
+		expectedError    error
+	}
+
+	runCommands := func(t *testing.T, client *resp.Conn, commands []command) {
+		for _, c := range commands {
+			cmd := make([]resp.Value, len(c.command))
+			for i, arg := range c.command {
+				cmd[i] = resp.StringValue(arg)
+			}
+			if err := client.WriteArray(cmd); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if c.expectedError != nil {
+				if !strings.Contains(res.Error().Error(), c.expectedError.Error()) {
+					t.Errorf("%v: expected error \"%s\", got \"%v\"", c.command, c.expectedError.Error(), res.Error())
+				}
+				continue
+			}
+			switch expected := c.expectedResponse.(type) {
+			case int:
+				if res.Integer() != expected {
+					t.Errorf("%v: expected response %d, got %d", c.command, expected, res.Integer())
+				}
+			case string:
+				if !strings.EqualFold(res.String(), expected) {
+					t.Errorf("%v: expected response \"%s\", got \"%s\"", c.command, expected, res.String())
+				}
+			}
+		}
+	}
+
+	// addElements adds count distinct elements starting at offset to the HyperLogLog at key.
+	addElements := func(t *testing.T, client *resp.Conn, key string, offset, count int) {
+		for i := 0; i < count; i += 1000 {
+			cmd := []resp.Value{resp.StringValue("PFADD"), resp.StringValue(key)}
+			for j := i; j < min(i+1000, count); j++ {
+				cmd = append(cmd, resp.StringValue(fmt.Sprintf("element%d", offset+j)))
+			}
+			if err := client.WriteArray(cmd); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, _, err := client.ReadValue(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}
+
+	t.Run("Test_HandlePFADD", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Create HyperLogLog on a non-existent key",
+				commands: []command{
+					{command: []string{"PFADD", "PfaddKey1", "a", "b", "c"}, expectedResponse: 1},
+					{command: []string{"PFCOUNT", "PfaddKey1"}, expectedResponse: 3},
+				},
+			},
+			{
+				name: "2. Create empty HyperLogLog when no elements are given",
+				commands: []command{
+					{command: []string{"PFADD", "PfaddKey2"}, expectedResponse: 1},
+					{command: []string{"PFADD", "PfaddKey2"}, expectedResponse: 0},
+					{command: []string{"PFCOUNT", "PfaddKey2"}, expectedResponse: 0},
+				},
+			},
+			{
+				name: "3. Return 0 when the elements don't change the HyperLogLog",
+				commands: []command{
+					{command: []string{"PFADD", "PfaddKey3", "a", "b"}, expectedResponse: 1},
+					{command: []string{"PFADD", "PfaddKey3", "b", "a"}, expectedResponse: 0},
+					{command: []string{"PFADD", "PfaddKey3", "c"}, expectedResponse: 1},
+					{command: []string{"PFCOUNT", "PfaddKey3"}, expectedResponse: 3},
+				},
+			},
+			{
+				name: "4. Report the HyperLogLog as a string",
+				commands: []command{
+					{command: []string{"PFADD", "PfaddKey4", "a"}, expectedResponse: 1},
+					{command: []string{"TYPE", "PfaddKey4"}, expectedResponse: "string"},
+				},
+			},
+			{
+				name: "5. Return error when the key does not hold a HyperLogLog",
+				commands: []command{
+					{command: []string{"SET", "PfaddKey5", "value"}, expectedResponse: "OK"},
+					{
+						command:       []string{"PFADD", "PfaddKey5", "a"},
+						expectedError: errors.New("value at key PfaddKey5 is not a hyperloglog"),
+					},
+				},
+			},
+			{
+				name: "6. Command too short",
+				commands: []command{
+					{command: []string{"PFADD"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				runCommands(t, client, test.commands)
+			})
+		}
+	})
+
+	t.Run("Test_HandlePFCOUNT", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Return 0 for a non-existent key",
+				commands: []command{
+					{command: []string{"PFCOUNT", "PfcountKey1"}, expectedResponse: 0},
+				},
+			},
+			{
+				name: "2. Return the cardinality of the union of multiple keys",
+				commands: []command{
+					{command: []string{"PFADD", "PfcountKey2", "a", "b", "c"}, expectedResponse: 1},
+					{command: []string{"PFADD", "PfcountKey3", "c", "d"}, expectedResponse: 1},
+					{command: []string{"PFCOUNT", "PfcountKey2", "PfcountKey3", "PfcountKey4"}, expectedResponse: 4},
+					{command: []string{"PFCOUNT", "PfcountKey3"}, expectedResponse: 2},
+				},
+			},
+			{
+				name: "3. Return error when one of the keys does not hold a HyperLogLog",
+				commands: []command{
+					{command: []string{"PFADD", "PfcountKey5", "a"}, expectedResponse: 1},
+					{command: []string{"SADD", "PfcountKey6", "a"}, expectedResponse: 1},
+					{
+						command:       []string{"PFCOUNT", "PfcountKey5", "PfcountKey6"},
+						expectedError: errors.New("value at key PfcountKey6 is not a hyperloglog"),
+					},
+				},
+			},
+			{
+				name: "4. Command too short",
+				commands: []command{
+					{command: []string{"PFCOUNT"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				runCommands(t, client, test.commands)
+			})
+		}
+
+		t.Run("5. Estimate large cardinalities within the error bounds", func(t *testing.T) {
+			// 3 standard errors of a 2^14 register HyperLogLog.
+			const maxError = 3 * 0.0081
+			for _, count := range []int{100, 1000, 10000, 100000} {
+				key := fmt.Sprintf("PfcountLargeKey%d", count)
+				addElements(t, client, key, 0, count)
+				// Adding the same elements again does not change the estimate.
+				addElements(t, client, key, 0, count)
+
+				if err := client.WriteArray([]resp.Value{resp.StringValue("PFCOUNT"), resp.StringValue(key)}); err != nil {
+					t.Error(err)
+					return
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if e := math.Abs(float64(res.Integer()-count)) / float64(count); e > maxError {
+					t.Errorf("expected count of %d elements to be within %.2f%%, got %d", count, maxError*100, res.Integer())
+				}
+			}
+		})
+	})
+
+	t.Run("Test_HandlePFMERGE", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Merge source keys into a non-existent destination",
+				commands: []command{
+					{command: []string{"PFADD", "PfmergeKey1", "a", "b", "c"}, expectedResponse: 1},
+					{command: []string{"PFADD", "PfmergeKey2", "c", "d", "e"}, expectedResponse: 1},
+					{command: []string{"PFMERGE", "PfmergeDest1", "PfmergeKey1", "PfmergeKey2"}, expectedResponse: "OK"},
+					{command: []string{"PFCOUNT", "PfmergeDest1"}, expectedResponse: 5},
+				},
+			},
+			{
+				name: "2. Merge source keys into an existing destination",
+				commands: []command{
+					{command: []string{"PFADD", "PfmergeDest2", "x", "y"}, expectedResponse: 1},
+					{command: []string{"PFADD", "PfmergeKey3", "a", "x"}, expectedResponse: 1},
+					{command: []string{"PFMERGE", "PfmergeDest2", "PfmergeKey3", "PfmergeKey4"}, expectedResponse: "OK"},
+					{command: []string{"PFCOUNT", "PfmergeDest2"}, expectedResponse: 3},
+				},
+			},
+			{
+				name: "3. Create an empty destination when there are no source keys",
+				commands: []command{
+					{command: []string{"PFMERGE", "PfmergeDest3"}, expectedResponse: "OK"},
+					{command: []string{"EXISTS", "PfmergeDest3"}, expectedResponse: 1},
+					{command: []string{"PFCOUNT", "PfmergeDest3"}, expectedResponse: 0},
+				},
+			},
+			{
+				name: "4. Return error when a source key does not hold a HyperLogLog",
+				commands: []command{
+					{command: []string{"LPUSH", "PfmergeKey5", "a"}, expectedResponse: 1},
+					{
+						command:       []string{"PFMERGE", "PfmergeDest4", "PfmergeKey5"},
+						expectedError: errors.New("value at key PfmergeKey5 is not a hyperloglog"),
+					},
+				},
+			},
+			{
+				name: "5. Command too short",
+				commands: []command{
+					{command: []string{"PFMERGE"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				runCommands(t, client, test.commands)
+			})
+		}
+
+		t.Run("6. Merge dense HyperLogLogs", func(t *testing.T) {
+			// The two HyperLogLogs share half of their elements.
+			addElements(t, client, "PfmergeDenseKey1", 0, 20000)
+			addElements(t, client, "PfmergeDenseKey2", 10000, 20000)
+			runCommands(t, client, []command{
+				{command: []string{"PFMERGE", "PfmergeDenseDest", "PfmergeDenseKey1", "PfmergeDenseKey2"}, expectedResponse: "OK"},
+			})
+
+			for _, cmd := range [][]string{
+				{"PFCOUNT", "PfmergeDenseDest"},
+				{"PFCOUNT", "PfmergeDenseKey1", "PfmergeDenseKey2"},
+			} {
+				command := make([]resp.Value, len(cmd))
+				for i, arg := range cmd {
+					command[i] = resp.StringValue(arg)
+				}
+				if err := client.WriteArray(command); err != nil {
+					t.Error(err)
+					return
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if e := math.Abs(float64(res.Integer()-30000)) / 30000; e > 3*0.0081 {
+					t.Errorf("%v: expected count to be close to 30000, got %d", cmd, res.Integer())
+				}
+			}
+		})
+	})
+}
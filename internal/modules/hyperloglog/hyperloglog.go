@@ -0,0 +1,332 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hyperloglog
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+const (
+	// precision is the number of hash bits used to select a register.
+	precision = 14
+	// registerCount is the number of registers of a HyperLogLog, which gives a standard error of 0.81%.
+	registerCount = 1 << precision
+	// registerBits is the width of a dense register. It's large enough to hold the longest run of zeros of a hash.
+	registerBits = 6
+	registerMax  = 1<<registerBits - 1
+	// denseSize is the number of bytes that hold the dense registers.
+	denseSize = (registerCount*registerBits + 7) / 8
+	// maxSparseRegisters is the number of non-zero registers above which the sparse representation
+	// is converted to the dense representation, as it would no longer be smaller.
+	maxSparseRegisters = 750
+	// hashSeed is the seed of the MurmurHash64A hash of the elements.
+	hashSeed = 0xadc83b19
+	alphaInf = 0.721347520444481703680
+)
+
+const (
+	encodingSparse byte = iota
+	encodingDense
+)
+
+// HyperLogLog estimates the number of distinct elements added to it.
+//
+// A HyperLogLog with few elements uses the sparse representation, a sorted list of its non-zero registers.
+// Once it has more than maxSparseRegisters non-zero registers, it's converted to the dense representation,
+// which packs all the 6-bit registers in 12KB.
+type HyperLogLog struct {
+	sparse []uint32 // Non-zero registers sorted by index. Each entry holds the index << 8 | value.
+	dense  []byte   // Packed 6-bit registers. Nil while the sparse representation is used.
+}
+
+func (hll *HyperLogLog) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*hll))
+	size += int64(cap(hll.sparse)) * int64(unsafe.Sizeof(uint32(0)))
+	size += int64(cap(hll.dense))
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*HyperLogLog)(nil)
+
+// MarshalBinary encodes the representation of the HyperLogLog followed by its registers.
+func (hll *HyperLogLog) MarshalBinary() ([]byte, error) {
+	if hll.IsDense() {
+		return append([]byte{encodingDense}, hll.dense...), nil
+	}
+	b := binary.AppendUvarint([]byte{encodingSparse}, uint64(len(hll.sparse)))
+	for _, entry := range hll.sparse {
+		b = binary.AppendUvarint(b, uint64(entry))
+	}
+	return b, nil
+}
+
+func (hll *HyperLogLog) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	switch r.Byte() {
+	case encodingDense:
+		dense := r.Bytes(denseSize)
+		if r.Err() != nil {
+			return r.Err()
+		}
+		*hll = HyperLogLog{dense: slices.Clone(dense)}
+		return nil
+	case encodingSparse:
+		n := r.Count()
+		sparse := make([]uint32, 0, n)
+		for i := 0; i < n; i++ {
+			entry := uint32(r.Uvarint())
+			if entry>>8 >= registerCount || (len(sparse) > 0 && entry>>8 <= sparse[len(sparse)-1]>>8) {
+				return errors.New("invalid hyperloglog register")
+			}
+			sparse = append(sparse, entry)
+		}
+		if r.Err() != nil {
+			return r.Err()
+		}
+		*hll = HyperLogLog{sparse: sparse}
+		return nil
+	}
+	if r.Err() != nil {
+		return r.Err()
+	}
+	return errors.New("invalid hyperloglog encoding")
+}
+
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{sparse: make([]uint32, 0)}
+}
+
+// IsDense returns true if the HyperLogLog uses the dense representation.
+func (hll *HyperLogLog) IsDense() bool {
+	return hll.dense != nil
+}
+
+// Add adds the elements and returns true if any of the registers was updated,
+// which means the estimated cardinality may have changed.
+func (hll *HyperLogLog) Add(elems []string) bool {
+	updated := false
+	for _, elem := range elems {
+		index, count := registerOf(elem)
+		if hll.setRegister(index, count) {
+			updated = true
+		}
+	}
+	return updated
+}
+
+// Merge sets each register to the maximum of its value and the value of the register of the other HyperLogLog,
+// so that the HyperLogLog estimates the cardinality of the union of both.
+func (hll *HyperLogLog) Merge(other *HyperLogLog) {
+	other.forEachRegister(func(index int, value uint8) {
+		hll.setRegister(index, value)
+	})
+}
+
+// Count returns the estimated number of distinct elements added to the HyperLogLog.
+// It uses the improved estimator described by Otmar Ertl in "New cardinality estimation algorithms
+// for HyperLogLog sketches", which doesn't need bias correction for small cardinalities.
+func (hll *HyperLogLog) Count() int {
+	var histogram [64]int
+	nonZero := 0
+	hll.forEachRegister(func(_ int, value uint8) {
+		histogram[value]++
+		nonZero++
+	})
+	histogram[0] = registerCount - nonZero
+
+	q := 64 - precision
+	m := float64(registerCount)
+	z := m * tau((m-float64(histogram[q+1]))/m)
+	for j := q; j >= 1; j-- {
+		z += float64(histogram[j])
+		z *= 0.5
+	}
+	z += m * sigma(float64(histogram[0])/m)
+	return int(math.Round(alphaInf * m * m / z))
+}
+
+// forEachRegister calls f with the index and value of every non-zero register.
+func (hll *HyperLogLog) forEachRegister(f func(index int, value uint8)) {
+	if !hll.IsDense() {
+		for _, entry := range hll.sparse {
+			f(int(entry>>8), uint8(entry))
+		}
+		return
+	}
+	for index := 0; index < registerCount; index++ {
+		if value := getDenseRegister(hll.dense, index); value != 0 {
+			f(index, value)
+		}
+	}
+}
+
+// setRegister sets the register to the value if the value is greater than the register's current value.
+// It returns true if the register was updated.
+func (hll *HyperLogLog) setRegister(index int, value uint8) bool {
+	if hll.IsDense() {
+		if getDenseRegister(hll.dense, index) >= value {
+			return false
+		}
+		setDenseRegister(hll.dense, index, value)
+		return true
+	}
+
+	i, found := slices.BinarySearchFunc(hll.sparse, uint32(index), func(entry uint32, index uint32) int {
+		return int(entry>>8) - int(index)
+	})
+	if found {
+		if uint8(hll.sparse[i]) >= value {
+			return false
+		}
+		hll.sparse[i] = uint32(index)<<8 | uint32(value)
+		return true
+	}
+	hll.sparse = slices.Insert(hll.sparse, i, uint32(index)<<8|uint32(value))
+	if len(hll.sparse) > maxSparseRegisters {
+		hll.toDense()
+	}
+	return true
+}
+
+// toDense converts the sparse representation to the dense representation.
+func (hll *HyperLogLog) toDense() {
+	dense := make([]byte, denseSize)
+	for _, entry := range hll.sparse {
+		setDenseRegister(dense, int(entry>>8), uint8(entry))
+	}
+	hll.sparse = nil
+	hll.dense = dense
+}
+
+func getDenseRegister(dense []byte, index int) uint8 {
+	bit := index * registerBits
+	b0 := uint16(dense[bit/8])
+	var b1 uint16
+	if bit/8+1 < len(dense) {
+		b1 = uint16(dense[bit/8+1])
+	}
+	return uint8((b0|b1<<8)>>(bit%8)) & registerMax
+}
+
+func setDenseRegister(dense []byte, index int, value uint8) {
+	bit := index * registerBits
+	word := uint16(value&registerMax) << (bit % 8)
+	mask := uint16(registerMax) << (bit % 8)
+	dense[bit/8] = dense[bit/8]&^byte(mask) | byte(word)
+	if bit/8+1 < len(dense) {
+		dense[bit/8+1] = dense[bit/8+1]&^byte(mask>>8) | byte(word>>8)
+	}
+}
+
+// registerOf returns the register selected by the hash of the element and the length of the run of zeros
+// in the rest of the hash, plus one.
+func registerOf(elem string) (int, uint8) {
+	hash := murmurHash64A([]byte(elem), hashSeed)
+	index := int(hash & (registerCount - 1))
+	hash >>= precision
+	// Make sure the count is at most 64-precision+1 so it fits in a register.
+	hash |= 1 << (64 - precision)
+	return index, uint8(bits.TrailingZeros64(hash) + 1)
+}
+
+// murmurHash64A is the 64-bit MurmurHash2 by Austin Appleby.
+func murmurHash64A(data []byte, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+
+	h := seed ^ uint64(len(data))*m
+	for len(data) >= 8 {
+		k := binary.LittleEndian.Uint64(data)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h ^= k
+		h *= m
+		data = data[8:]
+	}
+
+	switch len(data) {
+	case 7:
+		h ^= uint64(data[6]) << 48
+		fallthrough
+	case 6:
+		h ^= uint64(data[5]) << 40
+		fallthrough
+	case 5:
+		h ^= uint64(data[4]) << 32
+		fallthrough
+	case 4:
+		h ^= uint64(data[3]) << 24
+		fallthrough
+	case 3:
+		h ^= uint64(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint64(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint64(data[0])
+		h *= m
+	}
+
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+	return h
+}
+
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y := 1.0
+	z := x
+	for {
+		x *= x
+		zPrime := z
+		z += x * y
+		y += y
+		if zPrime == z {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y := 1.0
+	z := 1 - x
+	for {
+		x = math.Sqrt(x)
+		zPrime := z
+		y *= 0.5
+		z -= math.Pow(1-x, 2) * y
+		if zPrime == z {
+			return z / 3
+		}
+	}
+}
@@ -0,0 +1,1094 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// getDocument returns the document at key. It returns false if the key doesn't exist, and an error if the value
+// is not a document.
+func getDocument(params internal.HandlerFuncParams, key string) (*Document, bool, error) {
+	if !params.KeysExist(params.Context, []string{key})[key] {
+		return nil, false, nil
+	}
+	doc, ok := params.GetValues(params.Context, []string{key})[key].(*Document)
+	if !ok {
+		return nil, false, fmt.Errorf("value at key %s is not a JSON document", key)
+	}
+	return doc, true, nil
+}
+
+// getExistingDocument returns the document at key, or an error if the key doesn't exist.
+func getExistingDocument(params internal.HandlerFuncParams, key string) (*Document, error) {
+	doc, exists, err := getDocument(params, key)
+	if err == nil && !exists {
+		err = fmt.Errorf("key %s does not exist", key)
+	}
+	return doc, err
+}
+
+// parseArg parses a JSON value passed as a command argument.
+func parseArg(arg string) (interface{}, error) {
+	value, err := Parse([]byte(arg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON value %s: %w", arg, err)
+	}
+	return value, nil
+}
+
+// pathArg returns the path at cmd[i], or the legacy root path if the command is too short to include it.
+func pathArg(cmd []string, i int) (Path, error) {
+	if i >= len(cmd) {
+		return ParsePath(".")
+	}
+	return ParsePath(cmd[i])
+}
+
+// isType returns true if the value is of the JSON type. "number" matches both integers and floats.
+func isType(value interface{}, typeName string) bool {
+	name := TypeName(value)
+	return name == typeName || (typeName == "number" && name == "integer")
+}
+
+// reply encodes the result of a command applied to each node matched by the path. JSONPath queries reply with an
+// array that holds the result for each match, where nil marks a match that isn't of the expected type. Legacy
+// paths reply with the result for the first match, and fail if nothing matched or the first match isn't of the
+// expected type.
+func reply(path Path, nodes []node, results []interface{}, expected string) ([]byte, error) {
+	if !path.IsLegacy() {
+		res := fmt.Sprintf("*%d\r\n", len(results))
+		for _, result := range results {
+			res += encodeResult(result)
+		}
+		return []byte(res), nil
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("path %s does not exist", path)
+	}
+	if !isType(nodes[0].value, expected) {
+		return nil, fmt.Errorf("expected %s but found %s at path %s", expected, TypeName(nodes[0].value), path)
+	}
+	return []byte(encodeResult(results[0])), nil
+}
+
+// encodeResult encodes nil as a nil reply, integers as integer replies, strings as bulk strings and string slices
+// as arrays of bulk strings.
+func encodeResult(result interface{}) string {
+	switch r := result.(type) {
+	case int:
+		return fmt.Sprintf(":%d\r\n", r)
+	case string:
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(r), r)
+	case []string:
+		res := fmt.Sprintf("*%d\r\n", len(r))
+		for _, s := range r {
+			res += fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+		}
+		return res
+	default:
+		return "$-1\r\n"
+	}
+}
+
+// update applies fn to each node matched by the path at key, and stores the document if any node was changed.
+// fn returns the result for the node and whether it changed the document. fn is applied to a copy of the
+// document, so that an error part way through leaves the stored document unchanged.
+func update(
+	params internal.HandlerFuncParams,
+	key string,
+	path Path,
+	fn func(doc *Document, n node) (interface{}, bool, error),
+) ([]node, []interface{}, error) {
+	doc, err := getExistingDocument(params, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc = NewDocument(clone(doc.root))
+	nodes := path.find(doc.root)
+	results := make([]interface{}, len(nodes))
+	changed := false
+	for i, n := range nodes {
+		result, ok, err := fn(doc, n)
+		if err != nil {
+			return nil, nil, err
+		}
+		results[i], changed = result, changed || ok
+	}
+	if changed {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: doc}); err != nil {
+			return nil, nil, err
+		}
+	}
+	return nodes, results, nil
+}
+
+func handleSET(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 5 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	path, err := ParsePath(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseArg(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+	var nx, xx bool
+	if len(params.Command) == 5 {
+		switch strings.ToLower(params.Command[4]) {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		default:
+			return nil, errors.New("syntax error")
+		}
+	}
+
+	key := keys.WriteKeys[0]
+	doc, exists, err := getDocument(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if xx {
+			return []byte("$-1\r\n"), nil
+		}
+		if !path.IsRoot() {
+			return nil, errors.New("new objects must be created at the root")
+		}
+		doc = NewDocument(value)
+	} else {
+		matched := len(path.find(doc.root)) > 0
+		if (nx && matched) || (xx && !matched) || doc.Set(path, value) == 0 {
+			return []byte("$-1\r\n"), nil
+		}
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: doc}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleGET(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	var format Format
+	args := params.Command[2:]
+	for len(args) > 1 {
+		switch strings.ToLower(args[0]) {
+		case "indent":
+			format.Indent = args[1]
+		case "newline":
+			format.Newline = args[1]
+		case "space":
+			format.Space = args[1]
+		default:
+			goto paths
+		}
+		args = args[2:]
+	}
+paths:
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	paths := make([]Path, len(args))
+	for i, arg := range args {
+		if paths[i], err = ParsePath(arg); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, exists, err := getDocument(params, keys.ReadKeys[0])
+	if err != nil || !exists {
+		return []byte("$-1\r\n"), err
+	}
+
+	// Each path is replaced by the first value it matches for legacy paths, and by an array of the values it
+	// matches for JSONPath queries.
+	results := make([]interface{}, len(paths))
+	for i, path := range paths {
+		values := doc.Get(path)
+		if !path.IsLegacy() {
+			results[i] = NewArray(values...)
+			continue
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("path %s does not exist", path)
+		}
+		results[i] = values[0]
+	}
+
+	var res []byte
+	if len(paths) == 1 {
+		res = Marshal(results[0], format)
+	} else {
+		object := NewObject()
+		for i, path := range paths {
+			object.Set(path.String(), results[i])
+		}
+		res = Marshal(object, format)
+	}
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(res), res)), nil
+}
+
+func handleMGET(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := mgetKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	path, err := ParsePath(params.Command[len(params.Command)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(keys.ReadKeys))
+	for _, key := range keys.ReadKeys {
+		// Keys that don't exist or don't hold a document reply with nil.
+		doc, exists, err := getDocument(params, key)
+		if err != nil || !exists {
+			res += "$-1\r\n"
+			continue
+		}
+		values := doc.Get(path)
+		switch {
+		case !path.IsLegacy():
+			res += encodeResult(string(Marshal(NewArray(values...), Format{})))
+		case len(values) == 0:
+			res += "$-1\r\n"
+		default:
+			res += encodeResult(string(Marshal(values[0], Format{})))
+		}
+	}
+	return []byte(res), nil
+}
+
+// handleDEL handles JSON.DEL and JSON.FORGET.
+func handleDEL(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := pathArg(params.Command, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	doc, exists, err := getDocument(params, key)
+	if err != nil || !exists {
+		return []byte(":0\r\n"), err
+	}
+
+	if path.IsRoot() {
+		if err = params.DeleteKey(params.Context, key); err != nil {
+			return nil, err
+		}
+		return []byte(":1\r\n"), nil
+	}
+	count := doc.Delete(path)
+	if count > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: doc}); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+}
+
+func handleTYPE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := pathArg(params.Command, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, exists, err := getDocument(params, keys.ReadKeys[0])
+	if err != nil || !exists {
+		return []byte("$-1\r\n"), err
+	}
+
+	values := doc.Get(path)
+	types := make([]string, len(values))
+	for i, value := range values {
+		types[i] = TypeName(value)
+	}
+	if !path.IsLegacy() {
+		return []byte(encodeResult(types)), nil
+	}
+	if len(types) == 0 {
+		return []byte("$-1\r\n"), nil
+	}
+	return []byte(encodeResult(types[0])), nil
+}
+
+// handleNUMINCRBY handles JSON.NUMINCRBY and JSON.NUMMULTBY. The result is an integer if both numbers are
+// integers and the result doesn't overflow, and a float otherwise.
+func handleNUMINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := ParsePath(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	operand, err := parseArg(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+	if !isType(operand, "number") {
+		return nil, errors.New("expected a number")
+	}
+	multiply := strings.EqualFold(params.Command[0], "json.nummultby")
+
+	nodes, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		if !isType(n.value, "number") {
+			return nil, false, nil
+		}
+		result := applyNumber(n.value, operand, multiply)
+		if f, ok := result.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			return nil, false, errors.New("result is not a finite number")
+		}
+		doc.replace(n, result)
+		return result, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !path.IsLegacy() {
+		res := Marshal(NewArray(results...), Format{})
+		return []byte(encodeResult(string(res))), nil
+	}
+	if len(nodes) == 0 || !isType(nodes[0].value, "number") {
+		return reply(path, nodes, results, "number")
+	}
+	return []byte(encodeResult(string(Marshal(results[0], Format{})))), nil
+}
+
+func applyNumber(value interface{}, operand interface{}, multiply bool) interface{} {
+	a, aInt := value.(int64)
+	b, bInt := operand.(int64)
+	if aInt && bInt {
+		if !multiply && !((b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b)) {
+			return a + b
+		}
+		if multiply && (a == 0 || (a*b)/a == b && !(a == -1 && b == math.MinInt64) && !(b == -1 && a == math.MinInt64)) {
+			return a * b
+		}
+	}
+	x, y := toFloat(value), toFloat(operand)
+	if multiply {
+		return x * y
+	}
+	return x + y
+}
+
+func toFloat(value interface{}) float64 {
+	switch n := value.(type) {
+	case int64:
+		return float64(n)
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	}
+	return value.(float64)
+}
+
+func handleSTRAPPEND(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := pathArg(params.Command[:len(params.Command)-1], 2)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseArg(params.Command[len(params.Command)-1])
+	if err != nil {
+		return nil, err
+	}
+	suffix, ok := value.(string)
+	if !ok {
+		return nil, errors.New("expected a JSON string")
+	}
+
+	nodes, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		s, ok := n.value.(string)
+		if !ok {
+			return nil, false, nil
+		}
+		doc.replace(n, s+suffix)
+		return len(s) + len(suffix), true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply(path, nodes, results, "string")
+}
+
+func handleSTRLEN(params internal.HandlerFuncParams) ([]byte, error) {
+	return handleLength(params, "string", func(value interface{}) int {
+		return len(value.(string))
+	})
+}
+
+func handleARRLEN(params internal.HandlerFuncParams) ([]byte, error) {
+	return handleLength(params, "array", func(value interface{}) int {
+		return value.(*Array).Len()
+	})
+}
+
+func handleOBJLEN(params internal.HandlerFuncParams) ([]byte, error) {
+	return handleLength(params, "object", func(value interface{}) int {
+		return value.(*Object).Len()
+	})
+}
+
+// handleLength handles the commands that reply with the length of each value of a type matched by the path.
+// A key that doesn't exist replies with nil.
+func handleLength(params internal.HandlerFuncParams, typeName string, length func(value interface{}) int) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := pathArg(params.Command, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, exists, err := getDocument(params, keys.ReadKeys[0])
+	if err != nil || !exists {
+		return []byte("$-1\r\n"), err
+	}
+
+	nodes := path.find(doc.root)
+	results := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		if isType(n.value, typeName) {
+			results[i] = length(n.value)
+		}
+	}
+	return reply(path, nodes, results, typeName)
+}
+
+func handleARRAPPEND(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	path, err := ParsePath(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	values, err := parseValues(params.Command[3:])
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		array, ok := n.value.(*Array)
+		if !ok {
+			return nil, false, nil
+		}
+		for _, value := range values {
+			array.elements = append(array.elements, clone(value))
+		}
+		return array.Len(), true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply(path, nodes, results, "array")
+}
+
+func parseValues(args []string) ([]interface{}, error) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		value, err := parseArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func handleARRINSERT(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(5)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	path, err := ParsePath(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.Atoi(params.Command[3])
+	if err != nil {
+		return nil, errors.New("index must be an integer")
+	}
+	values, err := parseValues(params.Command[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		array, ok := n.value.(*Array)
+		if !ok {
+			return nil, false, nil
+		}
+		i := index
+		if i < 0 {
+			i += array.Len()
+		}
+		if i < 0 || i > array.Len() {
+			return nil, false, errors.New("index out of bounds")
+		}
+		inserted := make([]interface{}, len(values))
+		for j, value := range values {
+			inserted[j] = clone(value)
+		}
+		array.elements = slices.Insert(array.elements, i, inserted...)
+		return array.Len(), true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply(path, nodes, results, "array")
+}
+
+// handleARRPOP removes and replies with the element at the index of each array matched by the path. The index
+// defaults to -1, the last element, and is clamped to the bounds of the array. Empty arrays reply with nil.
+func handleARRPOP(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := pathArg(params.Command, 2)
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	if len(params.Command) == 4 {
+		if index, err = strconv.Atoi(params.Command[3]); err != nil {
+			return nil, errors.New("index must be an integer")
+		}
+	}
+
+	nodes, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		array, ok := n.value.(*Array)
+		if !ok || array.Len() == 0 {
+			return nil, false, nil
+		}
+		i := index
+		if i < 0 {
+			i += array.Len()
+		}
+		i = max(0, min(i, array.Len()-1))
+		popped := array.elements[i]
+		array.elements = slices.Delete(array.elements, i, i+1)
+		return string(Marshal(popped, Format{})), true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply(path, nodes, results, "array")
+}
+
+// handleARRINDEX replies with the index of the first occurrence of the value in each array matched by the path,
+// or -1 if it doesn't occur. The search is limited to the elements from start up to, but not including, stop.
+// A stop of 0 searches to the end of the array.
+func handleARRINDEX(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 6 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := ParsePath(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseArg(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+	bounds := []int{0, 0}
+	for i, arg := range params.Command[4:] {
+		if bounds[i], err = strconv.Atoi(arg); err != nil {
+			return nil, errors.New("start and stop must be integers")
+		}
+	}
+
+	doc, err := getExistingDocument(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	nodes := path.find(doc.root)
+	results := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		array, ok := n.value.(*Array)
+		if !ok {
+			continue
+		}
+		start, stop := normalizeIndex(bounds[0], array.Len()), array.Len()
+		if bounds[1] != 0 {
+			stop = normalizeIndex(bounds[1], array.Len())
+		}
+		results[i] = -1
+		for j := start; j < stop; j++ {
+			if Equal(array.elements[j], value) {
+				results[i] = j
+				break
+			}
+		}
+	}
+	return reply(path, nodes, results, "array")
+}
+
+// handleARRTRIM trims each array matched by the path to the elements from start to stop, inclusive.
+func handleARRTRIM(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(5)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 5 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := ParsePath(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	start, err := strconv.Atoi(params.Command[3])
+	if err != nil {
+		return nil, errors.New("start and stop must be integers")
+	}
+	stop, err := strconv.Atoi(params.Command[4])
+	if err != nil {
+		return nil, errors.New("start and stop must be integers")
+	}
+
+	nodes, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		array, ok := n.value.(*Array)
+		if !ok {
+			return nil, false, nil
+		}
+		from := normalizeIndex(start, array.Len())
+		to := normalizeIndex(stop, array.Len()) + 1
+		if stop >= array.Len() {
+			to = array.Len()
+		}
+		if from >= to {
+			array.elements = nil
+		} else {
+			array.elements = slices.Clone(array.elements[from:to])
+		}
+		return array.Len(), true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply(path, nodes, results, "array")
+}
+
+func handleOBJKEYS(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := pathArg(params.Command, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, exists, err := getDocument(params, keys.ReadKeys[0])
+	if err != nil || !exists {
+		return []byte("$-1\r\n"), err
+	}
+
+	nodes := path.find(doc.root)
+	results := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		if object, ok := n.value.(*Object); ok {
+			results[i] = slices.Clone(object.keys)
+		}
+	}
+	return reply(path, nodes, results, "object")
+}
+
+// handleCLEAR empties the arrays and objects and sets the numbers matched by the path to 0. It replies with the
+// number of values cleared.
+func handleCLEAR(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := pathArg(params.Command, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	_, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		switch v := n.value.(type) {
+		case *Array:
+			v.elements = nil
+		case *Object:
+			v.keys, v.values = nil, make(map[string]interface{})
+		case int64, json.Number:
+			doc.replace(n, int64(0))
+		case float64:
+			doc.replace(n, float64(0))
+		default:
+			return nil, false, nil
+		}
+		return 1, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+	for _, result := range results {
+		if result != nil {
+			count++
+		}
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+}
+
+// handleTOGGLE negates the booleans matched by the path. JSONPath queries reply with the new value of each match
+// as 1 or 0, and legacy paths reply with "true" or "false".
+func handleTOGGLE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	path, err := ParsePath(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, results, err := update(params, keys.WriteKeys[0], path, func(doc *Document, n node) (interface{}, bool, error) {
+		b, ok := n.value.(bool)
+		if !ok {
+			return nil, false, nil
+		}
+		doc.replace(n, !b)
+		if path.IsLegacy() {
+			return strconv.FormatBool(!b), true, nil
+		}
+		if !b {
+			return 1, true, nil
+		}
+		return 0, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply(path, nodes, results, "boolean")
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "json.set",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.SET key path value [NX | XX])
+Sets the JSON value at the path in the document at key. A new key must be set at the root path.
+If the path doesn't exist and its last element is an object key, the key is added to the parent object.
+NX only sets the value if the path doesn't exist, and XX only sets it if the path exists.
+Returns OK if the value was set, otherwise nil.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleSET,
+		},
+		{
+			Command:    "json.get",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.GET key [INDENT indent] [NEWLINE newline] [SPACE space] [path [path ...]])
+Returns the JSON values at the paths in the document at key. The default path is the root.
+JSONPath queries return an array of all matches, and legacy paths return the first match.
+With multiple paths, returns an object keyed by path. INDENT, NEWLINE and SPACE format the reply.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleGET,
+		},
+		{
+			Command:    "json.mget",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.MGET key [key ...] path)
+Returns the JSON values at the path in the document at each key.
+Keys that don't exist or don't hold a document return nil.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: mgetKeyFunc,
+			HandlerFunc:       handleMGET,
+		},
+		{
+			Command:    "json.del",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.DEL key [path])
+Deletes the values at the path in the document at key. Deleting the root deletes the key.
+Returns the number of values deleted.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(2),
+			HandlerFunc:       handleDEL,
+		},
+		{
+			Command:    "json.forget",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.FORGET key [path])
+An alias for JSON.DEL.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(2),
+			HandlerFunc:       handleDEL,
+		},
+		{
+			Command:    "json.type",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.TYPE key [path])
+Returns the types of the values at the path in the document at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleTYPE,
+		},
+		{
+			Command:    "json.numincrby",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.NUMINCRBY key path number)
+Increments the numbers at the path in the document at key by number.
+Returns the new values as JSON. Values that aren't numbers are nil in JSONPath replies.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleNUMINCRBY,
+		},
+		{
+			Command:    "json.nummultby",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.NUMMULTBY key path number)
+Multiplies the numbers at the path in the document at key by number.
+Returns the new values as JSON. Values that aren't numbers are nil in JSONPath replies.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleNUMINCRBY,
+		},
+		{
+			Command:    "json.strappend",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.STRAPPEND key [path] value)
+Appends the JSON string value to the strings at the path in the document at key.
+Returns the new length of each string.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleSTRAPPEND,
+		},
+		{
+			Command:    "json.strlen",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.STRLEN key [path])
+Returns the length of the strings at the path in the document at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleSTRLEN,
+		},
+		{
+			Command:    "json.arrappend",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.ARRAPPEND key path value [value ...])
+Appends the JSON values to the arrays at the path in the document at key.
+Returns the new length of each array.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleARRAPPEND,
+		},
+		{
+			Command:    "json.arrinsert",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.ARRINSERT key path index value [value ...])
+Inserts the JSON values before the index of the arrays at the path in the document at key.
+A negative index counts from the end of the array. Returns the new length of each array.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(5),
+			HandlerFunc:       handleARRINSERT,
+		},
+		{
+			Command:    "json.arrlen",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.ARRLEN key [path])
+Returns the length of the arrays at the path in the document at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleARRLEN,
+		},
+		{
+			Command:    "json.arrpop",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.ARRPOP key [path [index]])
+Removes and returns the element at the index of the arrays at the path in the document at key.
+The index defaults to -1, the last element. Empty arrays return nil.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(2),
+			HandlerFunc:       handleARRPOP,
+		},
+		{
+			Command:    "json.arrindex",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.ARRINDEX key path value [start [stop]])
+Returns the index of the first occurrence of the JSON value in the arrays at the path in the document at key,
+or -1 if it doesn't occur. start and stop limit the search, and a stop of 0 searches to the end of the array.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(4),
+			HandlerFunc:       handleARRINDEX,
+		},
+		{
+			Command:    "json.arrtrim",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.ARRTRIM key path start stop)
+Trims the arrays at the path in the document at key to the elements from start to stop, inclusive.
+Returns the new length of each array.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(5),
+			HandlerFunc:       handleARRTRIM,
+		},
+		{
+			Command:    "json.objkeys",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.OBJKEYS key [path])
+Returns the keys of the objects at the path in the document at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleOBJKEYS,
+		},
+		{
+			Command:    "json.objlen",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(JSON.OBJLEN key [path])
+Returns the number of keys in the objects at the path in the document at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleOBJLEN,
+		},
+		{
+			Command:    "json.clear",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.CLEAR key [path])
+Empties the arrays and objects and sets the numbers at the path in the document at key to 0.
+Returns the number of values cleared.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(2),
+			HandlerFunc:       handleCLEAR,
+		},
+		{
+			Command:    "json.toggle",
+			Module:     constants.JSONModule,
+			Categories: []string{constants.JSONCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(JSON.TOGGLE key path)
+Toggles the booleans at the path in the document at key.
+Returns the new values.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleTOGGLE,
+		},
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondoc_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+func Test_JSON(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	// command is a command and its expected response. Array responses are compared element by element as
+	// strings, with nil elements compared as empty strings.
+	type command struct {
+		command          []string
+		expectedResponse interface{}
+		expectedError    error
+	}
+
+	runCommands := func(t *testing.T, client *resp.Conn, commands []command) {
+		for _, c := range commands {
+			cmd := make([]resp.Value, len(c.command))
+			for i, arg := range c.command {
+				cmd[i] = resp.StringValue(arg)
+			}
+			if err := client.WriteArray(cmd); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if c.expectedError != nil {
+				if res.Error() == nil || !strings.Contains(res.Error().Error(), c.expectedError.Error()) {
+					t.Errorf("%v: expected error \"%s\", got \"%v\"", c.command, c.expectedError.Error(), res)
+				}
+				continue
+			}
+			if res.Error() != nil {
+				t.Errorf("%v: unexpected error \"%v\"", c.command, res.Error())
+				continue
+			}
+			switch expected := c.expectedResponse.(type) {
+			case int:
+				if res.Integer() != expected {
+					t.Errorf("%v: expected response %d, got \"%v\"", c.command, expected, res)
+				}
+			case string:
+				if res.String() != expected {
+					t.Errorf("%v: expected response \"%s\", got \"%s\"", c.command, expected, res.String())
+				}
+			case []string:
+				got := make([]string, len(res.Array()))
+				for i, value := range res.Array() {
+					got[i] = value.String()
+				}
+				if strings.Join(got, "|") != strings.Join(expected, "|") {
+					t.Errorf("%v: expected response %v, got %v", c.command, expected, got)
+				}
+			}
+		}
+	}
+
+	runTests := func(t *testing.T, tests []struct {
+		name     string
+		commands []command
+	}) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		for _, test := range tests {
+			t.Log(test.name)
+			runCommands(t, client, test.commands)
+		}
+	}
+
+	t.Run("Test_HandleSETAndGET", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. JSON.SET creates a document at the root and JSON.GET returns it in order",
+				commands: []command{
+					{command: []string{"JSON.SET", "SetKey1", "$", `{"b":1,"a":[1,2.5,"x"],"c":{"d":null}}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.GET", "SetKey1"}, expectedResponse: `{"b":1,"a":[1,2.5,"x"],"c":{"d":null}}`},
+					{command: []string{"JSON.GET", "SetKey1", "$.a[1]"}, expectedResponse: `[2.5]`},
+					{command: []string{"JSON.GET", "SetKey1", ".c.d"}, expectedResponse: `null`},
+					{command: []string{"JSON.GET", "SetKey1", "$..d", "$.b"}, expectedResponse: `{"$..d":[null],"$.b":[1]}`},
+					{command: []string{"JSON.GET", "SetKey1", "$.missing"}, expectedResponse: `[]`},
+					{command: []string{"JSON.GET", "SetKey1", ".missing"}, expectedError: errors.New("path .missing does not exist")},
+					{command: []string{"JSON.GET", "SetKey2"}, expectedResponse: ""},
+				},
+			},
+			{
+				name: "2. JSON.SET updates values and adds object keys",
+				commands: []command{
+					{command: []string{"JSON.SET", "SetKey3", ".", `{"a":{"b":1},"c":[{"b":2},{"b":3}]}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.SET", "SetKey3", "$..b", `"x"`}, expectedResponse: "OK"},
+					{command: []string{"JSON.SET", "SetKey3", "$.a.e", `[true]`}, expectedResponse: "OK"},
+					{command: []string{"JSON.GET", "SetKey3"}, expectedResponse: `{"a":{"b":"x","e":[true]},"c":[{"b":"x"},{"b":"x"}]}`},
+					{command: []string{"JSON.SET", "SetKey3", "$.x.y", `1`}, expectedResponse: ""},
+				},
+			},
+			{
+				name: "3. JSON.SET respects NX and XX",
+				commands: []command{
+					{command: []string{"JSON.SET", "SetKey4", "$", `{"a":1}`, "XX"}, expectedResponse: ""},
+					{command: []string{"JSON.SET", "SetKey4", "$", `{"a":1}`, "NX"}, expectedResponse: "OK"},
+					{command: []string{"JSON.SET", "SetKey4", "$.a", `2`, "NX"}, expectedResponse: ""},
+					{command: []string{"JSON.SET", "SetKey4", "$.b", `2`, "XX"}, expectedResponse: ""},
+					{command: []string{"JSON.SET", "SetKey4", "$.b", `2`, "NX"}, expectedResponse: "OK"},
+					{command: []string{"JSON.SET", "SetKey4", "$.a", `3`, "XX"}, expectedResponse: "OK"},
+					{command: []string{"JSON.GET", "SetKey4"}, expectedResponse: `{"a":3,"b":2}`},
+				},
+			},
+			{
+				name: "4. JSON.SET rejects invalid input",
+				commands: []command{
+					{command: []string{"JSON.SET", "SetKey5", "$.a", `1`}, expectedError: errors.New("new objects must be created at the root")},
+					{command: []string{"JSON.SET", "SetKey5", "$", `{"a":}`}, expectedError: errors.New("invalid JSON value")},
+					{command: []string{"JSON.SET", "SetKey5", "$", `{"a":`}, expectedError: errors.New("unexpected end of JSON input")},
+					{command: []string{"JSON.SET", "SetKey5", "$", `[1, 2`}, expectedError: errors.New("unexpected end of JSON input")},
+					{command: []string{"JSON.SET", "SetKey5", "$[?(@.a)]", `1`}, expectedError: errors.New("filter expressions are not supported")},
+					{command: []string{"JSON.SET", "SetKey5", "$"}, expectedError: errors.New(constants.WrongArgsResponse)},
+					{command: []string{"SET", "SetKey6", "value"}, expectedResponse: "OK"},
+					{command: []string{"JSON.GET", "SetKey6"}, expectedError: errors.New("value at key SetKey6 is not a JSON document")},
+				},
+			},
+			{
+				name: "5. JSON.GET formats the reply",
+				commands: []command{
+					{command: []string{"JSON.SET", "SetKey7", "$", `{"a":[1,2],"b":{}}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.GET", "SetKey7", "INDENT", "  ", "NEWLINE", "\n", "SPACE", " "}, expectedResponse: "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": {}\n}"},
+				},
+			},
+			{
+				name: "6. JSON.MGET returns the path of each key",
+				commands: []command{
+					{command: []string{"JSON.SET", "MGetKey1", "$", `{"a":1}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.SET", "MGetKey2", "$", `{"a":"two"}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.MGET", "MGetKey1", "MGetKey2", "MGetKey3", "$.a"}, expectedResponse: []string{`[1]`, `["two"]`, ""}},
+					{command: []string{"JSON.MGET", "MGetKey1", "MGetKey2", ".a"}, expectedResponse: []string{`1`, `"two"`}},
+				},
+			},
+			{
+				name: "7. JSON.SET keeps integers outside the int64 range",
+				commands: []command{
+					{command: []string{"JSON.SET", "SetKey8", "$", `{"a":12345678901234567890,"b":[-98765432109876543210]}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.GET", "SetKey8"}, expectedResponse: `{"a":12345678901234567890,"b":[-98765432109876543210]}`},
+					{command: []string{"JSON.TYPE", "SetKey8", "$.a"}, expectedResponse: []string{"integer"}},
+					{command: []string{"JSON.ARRINDEX", "SetKey8", "$.b", "-98765432109876543210"}, expectedResponse: []string{"0"}},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleDELAndTYPE", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. JSON.DEL deletes matched values",
+				commands: []command{
+					{command: []string{"JSON.SET", "DelKey1", "$", `{"a":[1,2,3,4],"b":{"a":1},"c":2}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.DEL", "DelKey1", "$.a[0,2,0]"}, expectedResponse: 2},
+					{command: []string{"JSON.DEL", "DelKey1", "$..a"}, expectedResponse: 2},
+					{command: []string{"JSON.FORGET", "DelKey1", "$.missing"}, expectedResponse: 0},
+					{command: []string{"JSON.GET", "DelKey1"}, expectedResponse: `{"b":{},"c":2}`},
+					{command: []string{"JSON.DEL", "DelKey1"}, expectedResponse: 1},
+					{command: []string{"EXISTS", "DelKey1"}, expectedResponse: 0},
+					{command: []string{"JSON.DEL", "DelKey1"}, expectedResponse: 0},
+				},
+			},
+			{
+				name: "2. JSON.TYPE returns the type of matched values",
+				commands: []command{
+					{command: []string{"JSON.SET", "TypeKey1", "$", `{"a":1,"b":1.5,"c":"s","d":true,"e":null,"f":[],"g":{}}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.TYPE", "TypeKey1"}, expectedResponse: "object"},
+					{command: []string{"JSON.TYPE", "TypeKey1", "$.*"}, expectedResponse: []string{"integer", "number", "string", "boolean", "null", "array", "object"}},
+					{command: []string{"JSON.TYPE", "TypeKey1", ".missing"}, expectedResponse: ""},
+					{command: []string{"TYPE", "TypeKey1"}, expectedResponse: "ReJSON-RL"},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleNumbersAndStrings", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. JSON.NUMINCRBY and JSON.NUMMULTBY update numbers",
+				commands: []command{
+					{command: []string{"JSON.SET", "NumKey1", "$", `{"a":1,"b":{"a":2.5},"c":"x"}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.NUMINCRBY", "NumKey1", "$..a", "2"}, expectedResponse: `[3,4.5]`},
+					{command: []string{"JSON.NUMINCRBY", "NumKey1", ".a", "0.5"}, expectedResponse: `3.5`},
+					{command: []string{"JSON.NUMMULTBY", "NumKey1", "$.*", "2"}, expectedResponse: `[7.0,null,null]`},
+					{command: []string{"JSON.NUMINCRBY", "NumKey1", ".c", "1"}, expectedError: errors.New("expected number but found string")},
+					{command: []string{"JSON.NUMINCRBY", "NumKey1", ".a", "x"}, expectedError: errors.New("invalid JSON value")},
+					{command: []string{"JSON.NUMMULTBY", "NumKey1", ".a", "1e300"}, expectedResponse: `7e+300`},
+					{command: []string{"JSON.NUMMULTBY", "NumKey1", ".a", "1e308"}, expectedError: errors.New("result is not a finite number")},
+					{command: []string{"JSON.NUMINCRBY", "NumKey2", ".a", "1"}, expectedError: errors.New("key NumKey2 does not exist")},
+				},
+			},
+			{
+				name: "2. JSON.NUMINCRBY falls back to floats on integer overflow",
+				commands: []command{
+					{command: []string{"JSON.SET", "NumKey3", "$", `9223372036854775807`}, expectedResponse: "OK"},
+					{command: []string{"JSON.NUMINCRBY", "NumKey3", "$", "1"}, expectedResponse: `[9223372036854776000.0]`},
+				},
+			},
+			{
+				name: "3. JSON.STRAPPEND and JSON.STRLEN",
+				commands: []command{
+					{command: []string{"JSON.SET", "StrKey1", "$", `{"a":"foo","b":{"a":"ba"},"c":1}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.STRAPPEND", "StrKey1", "$..a", `"r"`}, expectedResponse: []string{"4", "3"}},
+					{command: []string{"JSON.STRLEN", "StrKey1", "$.*"}, expectedResponse: []string{"4", "", ""}},
+					{command: []string{"JSON.STRLEN", "StrKey1", ".b.a"}, expectedResponse: 3},
+					{command: []string{"JSON.STRAPPEND", "StrKey1", ".c", `"x"`}, expectedError: errors.New("expected string but found integer")},
+					{command: []string{"JSON.STRAPPEND", "StrKey1", ".a", `1`}, expectedError: errors.New("expected a JSON string")},
+					{command: []string{"JSON.GET", "StrKey1"}, expectedResponse: `{"a":"foor","b":{"a":"bar"},"c":1}`},
+				},
+			},
+			{
+				name: "4. JSON.TOGGLE negates booleans",
+				commands: []command{
+					{command: []string{"JSON.SET", "ToggleKey1", "$", `{"a":true,"b":{"a":false},"c":1}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.TOGGLE", "ToggleKey1", "$..a"}, expectedResponse: []string{"0", "1"}},
+					{command: []string{"JSON.TOGGLE", "ToggleKey1", ".a"}, expectedResponse: "true"},
+					{command: []string{"JSON.TOGGLE", "ToggleKey1", "$.c"}, expectedResponse: []string{""}},
+					{command: []string{"JSON.GET", "ToggleKey1"}, expectedResponse: `{"a":true,"b":{"a":true},"c":1}`},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleArrays", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. JSON.ARRAPPEND, JSON.ARRINSERT and JSON.ARRLEN",
+				commands: []command{
+					{command: []string{"JSON.SET", "ArrKey1", "$", `{"a":[1],"b":{"a":[]},"c":"x"}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.ARRAPPEND", "ArrKey1", "$..a", `2`, `{"x":1}`}, expectedResponse: []string{"3", "2"}},
+					{command: []string{"JSON.ARRINSERT", "ArrKey1", ".a", "0", `0`}, expectedResponse: 4},
+					{command: []string{"JSON.ARRINSERT", "ArrKey1", "$.a", "-1", `"y"`}, expectedResponse: []string{"5"}},
+					{command: []string{"JSON.ARRINSERT", "ArrKey1", "$.a", "10", `1`}, expectedError: errors.New("index out of bounds")},
+					{command: []string{"JSON.ARRLEN", "ArrKey1", "$.*"}, expectedResponse: []string{"5", "", ""}},
+					{command: []string{"JSON.ARRLEN", "ArrKey1", ".c"}, expectedError: errors.New("expected array but found string")},
+					{command: []string{"JSON.ARRLEN", "ArrKey2"}, expectedResponse: ""},
+					{command: []string{"JSON.GET", "ArrKey1", ".a"}, expectedResponse: `[0,1,2,"y",{"x":1}]`},
+				},
+			},
+			{
+				name: "2. Appended values are copied into each array",
+				commands: []command{
+					{command: []string{"JSON.SET", "ArrKey3", "$", `{"a":[],"b":[]}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.ARRAPPEND", "ArrKey3", "$.*", `{"x":1}`}, expectedResponse: []string{"1", "1"}},
+					{command: []string{"JSON.SET", "ArrKey3", "$.a[0].x", `2`}, expectedResponse: "OK"},
+					{command: []string{"JSON.GET", "ArrKey3"}, expectedResponse: `{"a":[{"x":2}],"b":[{"x":1}]}`},
+				},
+			},
+			{
+				name: "3. JSON.ARRPOP removes elements",
+				commands: []command{
+					{command: []string{"JSON.SET", "ArrKey4", "$", `{"a":[1,"two",[3]],"b":[]}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.ARRPOP", "ArrKey4", ".a"}, expectedResponse: `[3]`},
+					{command: []string{"JSON.ARRPOP", "ArrKey4", "$.*", "0"}, expectedResponse: []string{"1", ""}},
+					{command: []string{"JSON.ARRPOP", "ArrKey4", ".a", "100"}, expectedResponse: `"two"`},
+					{command: []string{"JSON.ARRPOP", "ArrKey4", ".a"}, expectedResponse: ""},
+				},
+			},
+			{
+				name: "4. JSON.ARRINDEX finds values",
+				commands: []command{
+					{command: []string{"JSON.SET", "ArrKey5", "$", `{"a":[1,"x",{"b":2},1.0,1],"b":3}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.ARRINDEX", "ArrKey5", ".a", `{"b":2}`}, expectedResponse: 2},
+					{command: []string{"JSON.ARRINDEX", "ArrKey5", ".a", `1`, "1"}, expectedResponse: 3},
+					{command: []string{"JSON.ARRINDEX", "ArrKey5", ".a", `1`, "1", "3"}, expectedResponse: -1},
+					{command: []string{"JSON.ARRINDEX", "ArrKey5", ".a", `1`, "-1"}, expectedResponse: 4},
+					{command: []string{"JSON.ARRINDEX", "ArrKey5", "$.*", `"x"`}, expectedResponse: []string{"1", ""}},
+				},
+			},
+			{
+				name: "5. JSON.ARRTRIM trims arrays",
+				commands: []command{
+					{command: []string{"JSON.SET", "ArrKey6", "$", `{"a":[0,1,2,3,4],"b":[0,1]}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.ARRTRIM", "ArrKey6", ".a", "1", "-2"}, expectedResponse: 3},
+					{command: []string{"JSON.ARRTRIM", "ArrKey6", "$.*", "1", "100"}, expectedResponse: []string{"2", "1"}},
+					{command: []string{"JSON.ARRTRIM", "ArrKey6", ".a", "1", "0"}, expectedResponse: 0},
+					{command: []string{"JSON.GET", "ArrKey6"}, expectedResponse: `{"a":[],"b":[1]}`},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleObjects", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. JSON.OBJKEYS and JSON.OBJLEN",
+				commands: []command{
+					{command: []string{"JSON.SET", "ObjKey1", "$", `{"b":{"y":1,"x":2},"a":1}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.OBJKEYS", "ObjKey1"}, expectedResponse: []string{"b", "a"}},
+					{command: []string{"JSON.OBJKEYS", "ObjKey1", ".b"}, expectedResponse: []string{"y", "x"}},
+					{command: []string{"JSON.OBJLEN", "ObjKey1", "$.*"}, expectedResponse: []string{"2", ""}},
+					{command: []string{"JSON.OBJLEN", "ObjKey1", ".a"}, expectedError: errors.New("expected object but found integer")},
+				},
+			},
+			{
+				name: "2. JSON.CLEAR empties containers and zeroes numbers",
+				commands: []command{
+					{command: []string{"JSON.SET", "ClearKey1", "$", `{"a":[1,2],"b":{"c":1},"d":2.5,"e":"s"}`}, expectedResponse: "OK"},
+					{command: []string{"JSON.CLEAR", "ClearKey1", "$.*"}, expectedResponse: 3},
+					{command: []string{"JSON.GET", "ClearKey1"}, expectedResponse: `{"a":[],"b":{},"d":0.0,"e":"s"}`},
+					{command: []string{"JSON.CLEAR", "ClearKey1"}, expectedResponse: 1},
+					{command: []string{"JSON.GET", "ClearKey1"}, expectedResponse: `{}`},
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,414 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsondoc implements the JSON document type. Documents keep the order of object keys and the
+// distinction between integers and floats, so a document reads back exactly as it was written.
+package jsondoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// Array is a JSON array.
+type Array struct {
+	elements []interface{}
+}
+
+func NewArray(elements ...interface{}) *Array {
+	return &Array{elements: elements}
+}
+
+func (a *Array) Len() int {
+	return len(a.elements)
+}
+
+// Elements returns the elements of the array. The returned slice must not be modified.
+func (a *Array) Elements() []interface{} {
+	return a.elements
+}
+
+// Object is a JSON object. Keys are kept in the order they were added.
+type Object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func NewObject() *Object {
+	return &Object{values: make(map[string]interface{})}
+}
+
+func (o *Object) Len() int {
+	return len(o.keys)
+}
+
+// Keys returns the keys of the object in the order they were added. The returned slice must not be modified.
+func (o *Object) Keys() []string {
+	return o.keys
+}
+
+func (o *Object) Get(key string) (interface{}, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// Set sets the value of the key. A new key is added after the existing keys.
+func (o *Object) Set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Delete removes the key and returns true if it existed.
+func (o *Object) Delete(key string) bool {
+	if _, ok := o.values[key]; !ok {
+		return false
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Document is a JSON value stored at a key. The values of a document are nil, bool, int64, float64, string,
+// *Array and *Object. Integers that don't fit in an int64 are held as their json.Number literal.
+type Document struct {
+	root interface{}
+}
+
+func NewDocument(root interface{}) *Document {
+	return &Document{root: root}
+}
+
+func (doc *Document) Root() interface{} {
+	return doc.root
+}
+
+func (doc *Document) GetMem() int64 {
+	return int64(unsafe.Sizeof(*doc)) + valueMem(doc.root)
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*Document)(nil)
+
+func valueMem(value interface{}) int64 {
+	switch v := value.(type) {
+	case string:
+		return int64(unsafe.Sizeof(v)) + int64(len(v))
+	case json.Number:
+		return int64(unsafe.Sizeof(v)) + int64(len(v))
+	case *Array:
+		size := int64(unsafe.Sizeof(*v)) + int64(cap(v.elements))*int64(unsafe.Sizeof(value))
+		for _, element := range v.elements {
+			size += valueMem(element)
+		}
+		return size
+	case *Object:
+		size := int64(unsafe.Sizeof(*v)) + int64(cap(v.keys))*int64(unsafe.Sizeof(""))
+		for _, key := range v.keys {
+			// The key is held by both the key slice and the map.
+			size += int64(len(key)) + int64(unsafe.Sizeof(key)) + int64(unsafe.Sizeof(value))
+			size += valueMem(v.values[key])
+		}
+		return size
+	default:
+		// nil, bool and numbers are held in the interface value itself.
+		return 8
+	}
+}
+
+// MarshalBinary encodes the document as compact JSON.
+func (doc *Document) MarshalBinary() ([]byte, error) {
+	return Marshal(doc.root, Format{}), nil
+}
+
+func (doc *Document) UnmarshalBinary(data []byte) error {
+	root, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	doc.root = root
+	return nil
+}
+
+func (doc *Document) String() string {
+	return string(Marshal(doc.root, Format{}))
+}
+
+// TypeName returns the JSON type of the value: "object", "array", "string", "integer", "number", "boolean"
+// or "null".
+func TypeName(value interface{}) string {
+	switch value.(type) {
+	case *Object:
+		return "object"
+	case *Array:
+		return "array"
+	case string:
+		return "string"
+	case int64, json.Number:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// Parse parses JSON text into a document value. Numbers without a fraction or exponent are parsed as int64
+// when they fit, and kept as their json.Number literal otherwise, so that they're written back unchanged.
+// Other numbers are parsed as float64.
+func Parse(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := parseValue(decoder)
+	if errors.Is(err, io.EOF) {
+		// Truncated input is reported as a syntax error rather than io.EOF, which callers take to mean that
+		// the connection was closed.
+		return nil, errors.New("unexpected end of JSON input")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+func parseValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			array := NewArray()
+			for decoder.More() {
+				element, err := parseValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				array.elements = append(array.elements, element)
+			}
+			_, err = decoder.Token()
+			return array, err
+		}
+		object := NewObject()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.Set(key.(string), value)
+		}
+		_, err = decoder.Token()
+		return object, err
+	case json.Number:
+		return parseNumber(t.String())
+	default:
+		// string, bool or nil
+		return t, nil
+	}
+}
+
+func parseNumber(s string) (interface{}, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, nil
+		}
+		if _, ok := new(big.Int).SetString(s, 10); ok {
+			return json.Number(s), nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s", s)
+	}
+	return f, nil
+}
+
+// Format controls the whitespace written by Marshal. Indent is written once per nesting level at the start of
+// each line, Newline after each element of an array or object, and Space after each object key's colon.
+// The zero value writes compact JSON.
+type Format struct {
+	Indent  string
+	Newline string
+	Space   string
+}
+
+// Marshal encodes the value as JSON text. Floats are always written with a fraction or exponent, so they're
+// parsed back as floats.
+func Marshal(value interface{}, format Format) []byte {
+	var b bytes.Buffer
+	marshalValue(&b, value, format, 0)
+	return b.Bytes()
+}
+
+func marshalValue(b *bytes.Buffer, value interface{}, format Format, depth int) {
+	newline := func(depth int) {
+		b.WriteString(format.Newline)
+		b.WriteString(strings.Repeat(format.Indent, depth))
+	}
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case json.Number:
+		b.WriteString(v.String())
+	case float64:
+		b.WriteString(FormatFloat(v))
+	case string:
+		b.Write(quote(v))
+	case *Array:
+		if len(v.elements) == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteByte('[')
+		for i, element := range v.elements {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			newline(depth + 1)
+			marshalValue(b, element, format, depth+1)
+		}
+		newline(depth)
+		b.WriteByte(']')
+	case *Object:
+		if len(v.keys) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		b.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			newline(depth + 1)
+			b.Write(quote(key))
+			b.WriteByte(':')
+			b.WriteString(format.Space)
+			marshalValue(b, v.values[key], format, depth+1)
+		}
+		newline(depth)
+		b.WriteByte('}')
+	}
+}
+
+func quote(s string) []byte {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+// FormatFloat formats the float as a JSON number that always has a fraction or exponent.
+func FormatFloat(f float64) string {
+	var s string
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		s = strconv.FormatFloat(f, 'e', -1, 64)
+	} else {
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// Equal reports whether the values are equal. Integers and floats are equal if they hold the same number.
+func Equal(a, b interface{}) bool {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return x == y
+		case float64:
+			return float64(x) == y
+		}
+		return false
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return x == float64(y)
+		case json.Number:
+			f, _ := y.Float64()
+			return x == f
+		case float64:
+			return x == y
+		}
+		return false
+	case json.Number:
+		// The literal is outside the range of int64, so it can only equal another literal or a float.
+		switch y := b.(type) {
+		case json.Number:
+			return x == y
+		case float64:
+			f, _ := x.Float64()
+			return f == y
+		}
+		return false
+	case *Array:
+		y, ok := b.(*Array)
+		if !ok || len(x.elements) != len(y.elements) {
+			return false
+		}
+		for i := range x.elements {
+			if !Equal(x.elements[i], y.elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *Object:
+		y, ok := b.(*Object)
+		if !ok || len(x.keys) != len(y.keys) {
+			return false
+		}
+		for key, value := range x.values {
+			other, ok := y.values[key]
+			if !ok || !Equal(value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
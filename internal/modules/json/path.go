@@ -0,0 +1,457 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondoc
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// selector selects children of a node. Names select object keys and indices select array elements.
+// When recursive is true, the selector is applied to the node and all its descendants.
+type selector struct {
+	recursive bool
+	wildcard  bool
+	names     []string
+	indices   []int
+	slice     *slice
+}
+
+// slice selects the array elements from start up to, but not including, end, taking every step-th element.
+type slice struct {
+	start, end, step int
+	hasStart, hasEnd bool
+}
+
+// Path is a parsed path into a document.
+//
+// Paths that start with $ are JSONPath queries, which can match any number of values. Commands reply to them
+// with the result for each match. Other paths use the legacy syntax, e.g. ".a.b[0]", which is read as the
+// JSONPath query "$.a.b[0]". Commands reply to them with the result for the first match only.
+//
+// Child names, bracketed names, array indices, negative indices, slices, unions, wildcards and recursive
+// descent are supported. Filter expressions are not.
+type Path struct {
+	raw       string
+	legacy    bool
+	selectors []selector
+}
+
+func ParsePath(path string) (Path, error) {
+	p := Path{raw: path}
+	rest := path
+	switch {
+	case strings.HasPrefix(path, "$"):
+		rest = path[1:]
+	case path == "" || path == ".":
+		p.legacy, rest = true, ""
+	case strings.HasPrefix(path, ".") || strings.HasPrefix(path, "["):
+		p.legacy = true
+	default:
+		p.legacy, rest = true, "."+path
+	}
+
+	for len(rest) > 0 {
+		var s selector
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			s.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				continue
+			}
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "["):
+			end, err := parseBracket(rest, &s)
+			if err != nil {
+				return Path{}, fmt.Errorf("invalid path %s: %w", path, err)
+			}
+			p.selectors = append(p.selectors, s)
+			rest = rest[end:]
+			continue
+		default:
+			return Path{}, fmt.Errorf("invalid path %s", path)
+		}
+
+		// A dot is followed by a wildcard or a name.
+		end := strings.IndexAny(rest, ".[")
+		if end == -1 {
+			end = len(rest)
+		}
+		name := rest[:end]
+		switch {
+		case name == "*":
+			s.wildcard = true
+		case name == "":
+			return Path{}, fmt.Errorf("invalid path %s", path)
+		default:
+			s.names = []string{name}
+		}
+		p.selectors = append(p.selectors, s)
+		rest = rest[end:]
+	}
+
+	// A recursive descent followed by a bracket applies to the bracket's selector.
+	for i := 0; i < len(p.selectors); i++ {
+		s := p.selectors[i]
+		if s.recursive && !s.wildcard && s.names == nil && s.indices == nil && s.slice == nil {
+			if i+1 == len(p.selectors) {
+				return Path{}, fmt.Errorf("invalid path %s", path)
+			}
+			p.selectors[i+1].recursive = true
+			p.selectors = slices.Delete(p.selectors, i, i+1)
+		}
+	}
+	return p, nil
+}
+
+// parseBracket parses the bracketed selector at the start of s into sel, and returns the length of the bracket.
+func parseBracket(s string, sel *selector) (int, error) {
+	i := 1
+	for {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) {
+			return 0, errors.New("missing ]")
+		}
+		switch c := s[i]; {
+		case c == '\'' || c == '"':
+			name, n, err := parseQuoted(s[i:])
+			if err != nil {
+				return 0, err
+			}
+			sel.names = append(sel.names, name)
+			i += n
+		case c == '*':
+			sel.wildcard = true
+			i++
+		case c == '?':
+			return 0, errors.New("filter expressions are not supported")
+		default:
+			end := i
+			for end < len(s) && strings.IndexByte("-0123456789: ", s[end]) != -1 {
+				end++
+			}
+			token := strings.TrimSpace(s[i:end])
+			if strings.Contains(token, ":") {
+				sl, err := parseSlice(token)
+				if err != nil {
+					return 0, err
+				}
+				sel.slice = sl
+			} else {
+				index, err := strconv.Atoi(token)
+				if err != nil {
+					return 0, fmt.Errorf("invalid index %q", token)
+				}
+				sel.indices = append(sel.indices, index)
+			}
+			i = end
+		}
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) {
+			return 0, errors.New("missing ]")
+		}
+		if s[i] == ']' {
+			return i + 1, nil
+		}
+		if s[i] != ',' {
+			return 0, fmt.Errorf("unexpected %q", s[i])
+		}
+		i++
+	}
+}
+
+// parseQuoted parses the quoted string at the start of s, and returns it unquoted with the length of the quoted form.
+func parseQuoted(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+func parseSlice(token string) (*slice, error) {
+	parts := strings.Split(token, ":")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid slice %q", token)
+	}
+	sl := &slice{step: 1}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slice %q", token)
+		}
+		switch i {
+		case 0:
+			sl.start, sl.hasStart = n, true
+		case 1:
+			sl.end, sl.hasEnd = n, true
+		case 2:
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid slice step %d", n)
+			}
+			sl.step = n
+		}
+	}
+	return sl, nil
+}
+
+func (p Path) String() string {
+	return p.raw
+}
+
+// IsLegacy returns true if the path doesn't use the JSONPath syntax.
+func (p Path) IsLegacy() bool {
+	return p.legacy
+}
+
+// IsRoot returns true if the path selects the root of the document.
+func (p Path) IsRoot() bool {
+	return len(p.selectors) == 0
+}
+
+// node is a value matched by a path, along with its position in its parent so that it can be replaced or deleted.
+// The parent of the root is nil.
+type node struct {
+	value  interface{}
+	parent interface{}
+	key    string
+	index  int
+}
+
+// find returns the nodes matched by the path, in document order.
+func (p Path) find(root interface{}) []node {
+	return p.findFrom([]node{{value: root}}, p.selectors)
+}
+
+func (p Path) findFrom(nodes []node, selectors []selector) []node {
+	for _, s := range selectors {
+		var next []node
+		for _, n := range nodes {
+			if !s.recursive {
+				next = s.apply(n, next)
+				continue
+			}
+			for _, d := range descendants(n, nil) {
+				next = s.apply(d, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descendants appends the node and all the nodes below it to nodes.
+func descendants(n node, nodes []node) []node {
+	nodes = append(nodes, n)
+	for _, child := range children(n) {
+		nodes = descendants(child, nodes)
+	}
+	return nodes
+}
+
+func children(n node) []node {
+	var nodes []node
+	switch v := n.value.(type) {
+	case *Object:
+		for _, key := range v.keys {
+			nodes = append(nodes, node{value: v.values[key], parent: v, key: key})
+		}
+	case *Array:
+		for i, element := range v.elements {
+			nodes = append(nodes, node{value: element, parent: v, index: i})
+		}
+	}
+	return nodes
+}
+
+// apply appends the children of n selected by s to nodes.
+func (s selector) apply(n node, nodes []node) []node {
+	if s.wildcard {
+		return append(nodes, children(n)...)
+	}
+	switch v := n.value.(type) {
+	case *Object:
+		for _, name := range s.names {
+			if value, ok := v.values[name]; ok {
+				nodes = append(nodes, node{value: value, parent: v, key: name})
+			}
+		}
+	case *Array:
+		length := len(v.elements)
+		for _, index := range s.indices {
+			if index < 0 {
+				index += length
+			}
+			if index >= 0 && index < length {
+				nodes = append(nodes, node{value: v.elements[index], parent: v, index: index})
+			}
+		}
+		if s.slice != nil {
+			start, end := 0, length
+			if s.slice.hasStart {
+				start = normalizeIndex(s.slice.start, length)
+			}
+			if s.slice.hasEnd {
+				end = normalizeIndex(s.slice.end, length)
+			}
+			for i := start; i < end; i += s.slice.step {
+				nodes = append(nodes, node{value: v.elements[i], parent: v, index: i})
+			}
+		}
+	}
+	return nodes
+}
+
+// normalizeIndex converts a negative index to an index from the start, and clamps the index to [0, length].
+func normalizeIndex(index int, length int) int {
+	if index < 0 {
+		index += length
+	}
+	return max(0, min(index, length))
+}
+
+// Get returns the values matched by the path.
+func (doc *Document) Get(path Path) []interface{} {
+	nodes := path.find(doc.root)
+	values := make([]interface{}, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.value
+	}
+	return values
+}
+
+// Set replaces the values matched by the path with the value. If nothing matches and the last selector of the
+// path is a single name, the key is added to each object matched by the rest of the path. Returns the number of
+// values that were replaced or added.
+func (doc *Document) Set(path Path, value interface{}) int {
+	nodes := path.find(doc.root)
+	if len(nodes) > 0 {
+		for i, n := range nodes {
+			// Each match gets its own copy, so that later updates of one don't change the others.
+			v := value
+			if i > 0 {
+				v = clone(value)
+			}
+			doc.replace(n, v)
+		}
+		return len(nodes)
+	}
+
+	last := path.selectors[len(path.selectors)-1]
+	if last.recursive || last.wildcard || len(last.names) != 1 || last.indices != nil || last.slice != nil {
+		return 0
+	}
+	count := 0
+	for _, parent := range path.findFrom([]node{{value: doc.root}}, path.selectors[:len(path.selectors)-1]) {
+		if object, ok := parent.value.(*Object); ok {
+			v := value
+			if count > 0 {
+				v = clone(value)
+			}
+			object.Set(last.names[0], v)
+			count++
+		}
+	}
+	return count
+}
+
+func (doc *Document) replace(n node, value interface{}) {
+	switch parent := n.parent.(type) {
+	case nil:
+		doc.root = value
+	case *Object:
+		parent.values[n.key] = value
+	case *Array:
+		parent.elements[n.index] = value
+	}
+}
+
+// Delete deletes the values matched by the path and returns the number of values deleted. The root can't be
+// deleted this way, as deleting the root deletes the key.
+func (doc *Document) Delete(path Path) int {
+	if path.IsRoot() {
+		return 0
+	}
+	// The same value can be matched more than once, e.g. by [0,0].
+	type position struct {
+		parent interface{}
+		key    string
+		index  int
+	}
+	seen := make(map[position]bool)
+	nodes := slices.DeleteFunc(path.find(doc.root), func(n node) bool {
+		p := position{parent: n.parent, key: n.key, index: n.index}
+		if seen[p] {
+			return true
+		}
+		seen[p] = true
+		return false
+	})
+
+	// Delete array elements from the highest index down, so that deleting an element doesn't move the others.
+	slices.SortStableFunc(nodes, func(a, b node) int {
+		return b.index - a.index
+	})
+	for _, n := range nodes {
+		switch parent := n.parent.(type) {
+		case *Object:
+			parent.Delete(n.key)
+		case *Array:
+			parent.elements = slices.Delete(parent.elements, n.index, n.index+1)
+		}
+	}
+	return len(nodes)
+}
+
+func clone(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *Array:
+		array := &Array{elements: make([]interface{}, len(v.elements))}
+		for i, element := range v.elements {
+			array.elements[i] = clone(element)
+		}
+		return array
+	case *Object:
+		object := NewObject()
+		for _, key := range v.keys {
+			object.Set(key, clone(v.values[key]))
+		}
+		return object
+	default:
+		return value
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func handleLLen(params internal.HandlerFuncParams) ([]byte, error) {
@@ -38,8 +39,8 @@ func handleLLen(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte(":0\r\n"), nil
 	}
 
-	if list, ok := params.GetValues(params.Context, []string{key})[key].([]string); ok {
-		return []byte(fmt.Sprintf(":%d\r\n", len(list))), nil
+	if list, ok := params.GetValues(params.Context, []string{key})[key].(*List); ok {
+		return []byte(fmt.Sprintf(":%d\r\n", list.Len())), nil
 	}
 
 	return nil, errors.New("LLEN command on non-list item")
@@ -57,7 +58,7 @@ func handleLIndex(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte(fmt.Sprintf("$-1\r\n")), nil
 	}
 
-	list, ok := params.GetValues(params.Context, []string{key})[key].([]string)
+	list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
 	if !ok {
 		return nil, errors.New("LINDEX command on non-list item")
 	}
@@ -68,14 +69,15 @@ func handleLIndex(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 	// If index is less than 0, calculate index from the end of the list
 	if index < 0 {
-		index = len(list) + index
+		index = list.Len() + index
 	}
 
-	if index >= len(list) || index < 0 {
+	if index >= list.Len() || index < 0 {
 		return []byte(fmt.Sprintf("$-1\r\n")), nil
 	}
 
-	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(list[index]), list[index])), nil
+	element := list.Index(index)
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(element), element)), nil
 }
 
 func handleLRange(params internal.HandlerFuncParams) ([]byte, error) {
@@ -90,7 +92,7 @@ func handleLRange(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("*0\r\n"), nil
 	}
 
-	list, ok := params.GetValues(params.Context, []string{key})[key].([]string)
+	list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
 	if !ok {
 		return nil, errors.New("LRANGE command on non-list item")
 	}
@@ -101,7 +103,7 @@ func handleLRange(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 	// If start is < 0, calculate it from the end of the list
 	if start < 0 {
-		start = len(list) + start
+		start = list.Len() + start
 	}
 
 	end, err := strconv.Atoi(params.Command[3])
@@ -110,20 +112,21 @@ func handleLRange(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 	// If end is < 0, calculate it from the end of the list
 	if end < 0 {
-		end = len(list) - end
+		end = list.Len() - end
 	}
 	// If end is greater than list length, set it to the last element of the list
-	if end > len(list) {
-		end = len(list) - 1
+	if end > list.Len() {
+		end = list.Len() - 1
 	}
 
-	if start > end || start > len(list) {
+	if start > end || start > list.Len() {
 		return []byte("*0\r\n"), nil
 	}
 
 	res := fmt.Sprintf("*%d\r\n", end-start+1)
 	for i := start; i <= end; i++ {
-		res += fmt.Sprintf("$%d\r\n%s\r\n", len(list[i]), list[i])
+		element := list.Index(i)
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(element), element)
 	}
 
 	return []byte(res), nil
@@ -146,21 +149,21 @@ func handleLSet(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, errors.New("index must be an integer")
 	}
 
-	list, ok := params.GetValues(params.Context, []string{key})[key].([]string)
+	list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
 	if !ok {
 		return nil, errors.New("LSET command on non-list item")
 	}
 
 	// If index is negative set index to length - index
 	if index < 0 {
-		index = len(list) + index
+		index = list.Len() + index
 	}
 
-	if !(index >= 0 && index < len(list)) {
+	if !(index >= 0 && index < list.Len()) {
 		return nil, errors.New("index must be within list range")
 	}
 
-	list[index] = params.Command[3]
+	list.Set(index, params.Command[3])
 	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
 		return nil, err
 	}
@@ -189,21 +192,21 @@ func handleLTrim(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, fmt.Errorf("end index must be an integer")
 	}
 
-	list, ok := params.GetValues(params.Context, []string{key})[key].([]string)
+	list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
 	if !ok {
 		return nil, errors.New("LTRIM command on non-list item")
 	}
 
 	// If start and end indices are negative, calculate them from the end of the list
 	if start < 0 {
-		start = len(list) + start
+		start = list.Len() + start
 	}
 	if end < 0 {
-		end = len(list) + end
+		end = list.Len() + end
 	}
 
 	// If start index is greater than end index or greater than the index of the last element, delete the key.
-	if start > end || start > len(list)-1 {
+	if start > end || start > list.Len()-1 {
 		if err = params.DeleteKey(params.Context, key); err != nil {
 			return nil, err
 		}
@@ -211,15 +214,16 @@ func handleLTrim(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	// If end is greater than the length of the list, set it to the length of the list
-	if end > len(list) {
-		end = len(list)
+	if end > list.Len() {
+		end = list.Len()
 	}
 	// In order to include end element, if the end index is within range, add 1
-	if end <= len(list)-1 {
+	if end <= list.Len()-1 {
 		end += 1
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: list[start:end]}); err != nil {
+	list.Trim(max(start, 0), end)
+	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
 		return nil, err
 	}
 
@@ -246,52 +250,50 @@ func handleLRem(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte(":0\r\n"), nil
 	}
 
-	list, ok := params.GetValues(params.Context, []string{key})[key].([]string)
+	list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
 	if !ok {
 		return nil, errors.New("LREM command on non-list item")
 	}
 
-	removedCount := len(list)
+	elements := list.Elements()
+	removedCount := len(elements)
 
 	switch {
 	default:
 		// Count is zero, remove all instances of the element from the list.
-		for i := 0; i < len(list); i++ {
-			if list[i] == value {
-				list = append(list[:i], list[i+1:]...)
-				absoluteCount += 1
-			}
-		}
+		elements = slices.DeleteFunc(elements, func(element string) bool {
+			return element == value
+		})
 	case count > 0:
 		// Start from the head
-		for i := 0; i < len(list); i++ {
+		for i := 0; i < len(elements); i++ {
 			if absoluteCount == 0 {
 				break
 			}
-			if list[i] == value {
-				list = append(list[:i], list[i+1:]...)
+			if elements[i] == value {
+				elements = slices.Delete(elements, i, i+1)
 				absoluteCount -= 1
+				i--
 			}
 		}
 	case count < 0:
 		// Start from the tail
-		for i := len(list) - 1; i >= 0; i-- {
+		for i := len(elements) - 1; i >= 0; i-- {
 			if absoluteCount == 0 {
 				break
 			}
-			if list[i] == value {
-				list = append(list[:i], list[i+1:]...)
+			if elements[i] == value {
+				elements = slices.Delete(elements, i, i+1)
 				absoluteCount -= 1
-				removedCount += 0
 			}
 		}
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
+	if err = params.SetValues(params.Context, map[string]interface{}{key: NewList(elements...)}); err != nil {
 		return nil, err
 	}
 
-	removedCount = removedCount - len(list)
+	removedCount = removedCount - len(elements)
 	return []byte(fmt.Sprintf(":%d\r\n", removedCount)), nil
 }
 
@@ -315,39 +317,21 @@ func handleLMove(params internal.HandlerFuncParams) ([]byte, error) {
 	}
 
 	lists := params.GetValues(params.Context, keys.WriteKeys)
-	sourceList, sourceOk := lists[source].([]string)
-	destinationList, destinationOk := lists[destination].([]string)
+	sourceList, sourceOk := lists[source].(*List)
+	destinationList, destinationOk := lists[destination].(*List)
 
 	if !sourceOk || !destinationOk {
 		return nil, errors.New("both source and destination must be lists")
 	}
 
-	switch whereFrom {
-	case "left":
-		err = params.SetValues(params.Context, map[string]interface{}{
-			source: append([]string{}, sourceList[1:]...),
-			destination: func() []string {
-				if whereTo == "left" {
-					return append(sourceList[0:1], destinationList...)
-				}
-				// whereTo == "right"
-				return append(destinationList, sourceList[0])
-			}(),
-		})
-	case "right":
-		err = params.SetValues(params.Context, map[string]interface{}{
-			source: append([]string{}, sourceList[:len(sourceList)-1]...),
-			destination: func() []string {
-				if whereTo == "left" {
-					return append(sourceList[len(sourceList)-1:], destinationList...)
-				}
-				// whereTo == "right"
-				return append(destinationList, sourceList[len(sourceList)-1])
-			}(),
-		})
+	if sourceList.Len() > 0 {
+		moveElement(sourceList, destinationList, whereFrom, whereTo)
 	}
 
-	if err != nil {
+	if err = params.SetValues(params.Context, map[string]interface{}{
+		source:      sourceList,
+		destination: destinationList,
+	}); err != nil {
 		return nil, err
 	}
 
@@ -360,37 +344,38 @@ func handleLPush(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, err
 	}
 
-	var newElems []string
-
-	for _, elem := range params.Command[2:] {
-		newElems = append(newElems, elem)
+	newElems, maxLen, err := parsePushArgs(params.Command)
+	if err != nil {
+		return nil, err
 	}
 
 	key := keys.WriteKeys[0]
 	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
 
+	l := NewList()
 	if !keyExists {
-		switch strings.ToLower(params.Command[0]) {
-		case "lpushx":
+		if strings.EqualFold(params.Command[0], "lpushx") {
 			return nil, errors.New("LPUSHX command on non-existent key")
-		default:
-			if err = params.SetValues(params.Context, map[string]interface{}{key: []string{}}); err != nil {
-				return nil, err
-			}
+		}
+	} else {
+		currentList := params.GetValues(params.Context, []string{key})[key]
+		var ok bool
+		if l, ok = currentList.(*List); !ok {
+			return nil, errors.New("LPUSH command on non-list item")
 		}
 	}
 
-	currentList := params.GetValues(params.Context, []string{key})[key]
-	l, ok := currentList.([]string)
-	if !ok {
-		return nil, errors.New("LPUSH command on non-list item")
+	l.PushFront(newElems...)
+	if maxLen > 0 && l.Len() > maxLen {
+		// The list is capped, so the elements at the end of the list are dropped.
+		l.Trim(0, maxLen)
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: append(newElems, l...)}); err != nil {
+	if err = params.SetValues(params.Context, map[string]interface{}{key: l}); err != nil {
 		return nil, err
 	}
 
-	return []byte(fmt.Sprintf(":%d\r\n", len(l)+len(newElems))), nil
+	return []byte(fmt.Sprintf(":%d\r\n", l.Len())), nil
 }
 
 func handleRPush(params internal.HandlerFuncParams) ([]byte, error) {
@@ -402,33 +387,49 @@ func handleRPush(params internal.HandlerFuncParams) ([]byte, error) {
 	key := keys.WriteKeys[0]
 	keyExists := params.KeysExist(params.Context, keys.WriteKeys)[key]
 
-	var newElems []string
-
-	for _, elem := range params.Command[2:] {
-		newElems = append(newElems, elem)
+	newElems, maxLen, err := parsePushArgs(params.Command)
+	if err != nil {
+		return nil, err
 	}
 
+	l := NewList()
 	if !keyExists {
-		switch strings.ToLower(params.Command[0]) {
-		case "rpushx":
+		if strings.EqualFold(params.Command[0], "rpushx") {
 			return nil, errors.New("RPUSHX command on non-existent key")
-		default:
-			if err = params.SetValues(params.Context, map[string]interface{}{key: []string{}}); err != nil {
-				return nil, err
-			}
+		}
+	} else {
+		currentList := params.GetValues(params.Context, []string{key})[key]
+		var ok bool
+		if l, ok = currentList.(*List); !ok {
+			return nil, errors.New("RPUSH command on non-list item")
 		}
 	}
 
-	currentList := params.GetValues(params.Context, []string{key})[key]
-	l, ok := currentList.([]string)
-	if !ok {
-		return nil, errors.New("RPUSH command on non-list item")
+	l.PushBack(newElems...)
+	if maxLen > 0 && l.Len() > maxLen {
+		// The list is capped, so the elements at the beginning of the list are dropped.
+		l.Trim(l.Len()-maxLen, l.Len())
 	}
 
-	if err = params.SetValues(params.Context, map[string]interface{}{key: append(l, newElems...)}); err != nil {
+	if err = params.SetValues(params.Context, map[string]interface{}{key: l}); err != nil {
 		return nil, err
 	}
-	return []byte(fmt.Sprintf(":%d\r\n", len(l)+len(newElems))), nil
+	return []byte(fmt.Sprintf(":%d\r\n", l.Len())), nil
+}
+
+// parsePushArgs returns the elements of a push command and the length the list is capped at, or 0 if the list
+// is not capped. Only LPUSHCAP and RPUSHCAP cap the list, and they take the length right after the key.
+func parsePushArgs(cmd []string) ([]string, int, error) {
+	switch strings.ToLower(cmd[0]) {
+	case "lpushcap", "rpushcap":
+		maxLen, err := strconv.Atoi(cmd[2])
+		if err != nil || maxLen <= 0 {
+			return nil, 0, errors.New("maxlen must be a positive integer")
+		}
+		return cmd[3:], maxLen, nil
+	default:
+		return cmd[2:], 0, nil
+	}
 }
 
 func handlePop(params internal.HandlerFuncParams) ([]byte, error) {
@@ -443,7 +444,7 @@ func handlePop(params internal.HandlerFuncParams) ([]byte, error) {
 		return []byte("$-1\r\n"), nil
 	}
 
-	list, ok := params.GetValues(params.Context, []string{key})[key].([]string)
+	list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
 	if !ok {
 		return nil, fmt.Errorf("%s command on non-list item", strings.ToUpper(params.Command[0]))
 	}
@@ -460,13 +461,13 @@ func handlePop(params internal.HandlerFuncParams) ([]byte, error) {
 		// Set absolute value for count
 		count = internal.AbsInt(count)
 		// If count is greater than the length of the list, set count to the length of the list.
-		if count > len(list) {
-			count = len(list)
+		if count > list.Len() {
+			count = list.Len()
 		}
 	}
 
 	// Return nil if list is empty
-	if len(list) == 0 {
+	if list.Len() == 0 {
 		return []byte("$-1\r\n"), nil
 	}
 
@@ -474,12 +475,10 @@ func handlePop(params internal.HandlerFuncParams) ([]byte, error) {
 	for i := 0; i < count; i++ {
 		if strings.EqualFold(params.Command[0], "lpop") {
 			// Pop from the left
-			popped = append(popped, list[0])
-			list = list[1:]
+			popped = append(popped, list.PopFront())
 		} else {
 			// Pop from the right
-			popped = append(popped, list[len(list)-1])
-			list = list[:len(list)-1]
+			popped = append(popped, list.PopBack())
 		}
 	}
 	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
@@ -498,6 +497,361 @@ func handlePop(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(res), nil
 }
 
+// blockTimeout returns the channel that receives when a blocking command times out.
+// A nil channel is returned when the command blocks indefinitely.
+func blockTimeout(params internal.HandlerFuncParams, timeout time.Duration) <-chan time.Time {
+	if timeout == 0 {
+		return nil
+	}
+	return params.GetClock().After(timeout)
+}
+
+func handleBlockingPop(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := blpopKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := internal.ParseBlockingTimeout(params.Command[len(params.Command)-1])
+	if err != nil {
+		return nil, err
+	}
+	timeout := blockTimeout(params, duration)
+
+	for {
+		// Register the wait before checking the lists so that elements pushed after the check wake the client.
+		wait, cancel := params.AwaitKeys(params.Context, keys.WriteKeys)
+
+		for _, key := range keys.WriteKeys {
+			if !params.KeysExist(params.Context, []string{key})[key] {
+				continue
+			}
+			list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
+			if !ok {
+				cancel()
+				return nil, fmt.Errorf("%s command on non-list item", strings.ToUpper(params.Command[0]))
+			}
+			if list.Len() == 0 {
+				continue
+			}
+
+			// Pop the element from the first non-empty list.
+			var popped string
+			if strings.EqualFold(params.Command[0], "blpop") {
+				popped = list.PopFront()
+			} else {
+				popped = list.PopBack()
+			}
+			cancel()
+			if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
+				return nil, err
+			}
+
+			return []byte(fmt.Sprintf("*2\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(key), key, len(popped), popped)), nil
+		}
+
+		if !wait(timeout) {
+			return []byte("*-1\r\n"), nil
+		}
+	}
+}
+
+func handleBLMove(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := blmoveKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	source, destination := keys.WriteKeys[0], keys.WriteKeys[1]
+	whereFrom := strings.ToLower(params.Command[3])
+	whereTo := strings.ToLower(params.Command[4])
+	if !slices.Contains([]string{"left", "right"}, whereFrom) || !slices.Contains([]string{"left", "right"}, whereTo) {
+		return nil, errors.New("wherefrom and whereto arguments must be either LEFT or RIGHT")
+	}
+
+	duration, err := internal.ParseBlockingTimeout(params.Command[5])
+	if err != nil {
+		return nil, err
+	}
+	timeout := blockTimeout(params, duration)
+
+	for {
+		wait, cancel := params.AwaitKeys(params.Context, []string{source})
+
+		keysExist := params.KeysExist(params.Context, keys.WriteKeys)
+		lists := params.GetValues(params.Context, keys.WriteKeys)
+		sourceList, sourceOk := lists[source].(*List)
+		destinationList, destinationOk := lists[destination].(*List)
+		if (keysExist[source] && !sourceOk) || (keysExist[destination] && !destinationOk) {
+			cancel()
+			return nil, errors.New("both source and destination must be lists")
+		}
+
+		if sourceOk && sourceList.Len() > 0 {
+			cancel()
+
+			if !destinationOk {
+				destinationList = NewList()
+			}
+			element := moveElement(sourceList, destinationList, whereFrom, whereTo)
+
+			values := map[string]interface{}{source: sourceList, destination: destinationList}
+			if err = params.SetValues(params.Context, values); err != nil {
+				return nil, err
+			}
+
+			return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(element), element)), nil
+		}
+
+		if !wait(timeout) {
+			return []byte("$-1\r\n"), nil
+		}
+	}
+}
+
+// moveElement removes an element from one side of the source list and adds it to one side of the destination
+// list, and returns the element. The source list must not be empty. The source and destination can be the
+// same list, in which case the element is moved within the list.
+func moveElement(sourceList, destinationList *List, whereFrom, whereTo string) string {
+	var element string
+	if whereFrom == "left" {
+		element = sourceList.PopFront()
+	} else {
+		element = sourceList.PopBack()
+	}
+	if whereTo == "left" {
+		destinationList.PushFront(element)
+	} else {
+		destinationList.PushBack(element)
+	}
+	return element
+}
+
+func handleRPopLPush(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := rpoplpushKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	source, destination := keys.WriteKeys[0], keys.WriteKeys[1]
+
+	keysExist := params.KeysExist(params.Context, keys.WriteKeys)
+	lists := params.GetValues(params.Context, keys.WriteKeys)
+	sourceList, sourceOk := lists[source].(*List)
+	destinationList, destinationOk := lists[destination].(*List)
+	if (keysExist[source] && !sourceOk) || (keysExist[destination] && !destinationOk) {
+		return nil, errors.New("both source and destination must be lists")
+	}
+
+	if !sourceOk || sourceList.Len() == 0 {
+		return []byte("$-1\r\n"), nil
+	}
+
+	if !destinationOk {
+		destinationList = NewList()
+	}
+	element := moveElement(sourceList, destinationList, "right", "left")
+
+	values := map[string]interface{}{source: sourceList, destination: destinationList}
+	if err = params.SetValues(params.Context, values); err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(element), element)), nil
+}
+
+func handleLInsert(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := linsertKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	where := strings.ToLower(params.Command[2])
+	pivot, element := params.Command[3], params.Command[4]
+
+	if !slices.Contains([]string{"before", "after"}, where) {
+		return nil, errors.New("where argument must be either BEFORE or AFTER")
+	}
+
+	if !params.KeysExist(params.Context, keys.WriteKeys)[key] {
+		return []byte(":0\r\n"), nil
+	}
+
+	list, ok := params.GetValues(params.Context, []string{key})[key].(*List)
+	if !ok {
+		return nil, errors.New("LINSERT command on non-list item")
+	}
+
+	index := -1
+	for i := 0; i < list.Len(); i++ {
+		if list.Index(i) == pivot {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return []byte(":-1\r\n"), nil
+	}
+	if where == "after" {
+		index++
+	}
+
+	list.Insert(index, element)
+	if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", list.Len())), nil
+}
+
+func handleLPos(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := lposKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	element := params.Command[2]
+
+	rank, count, maxLen := 1, 0, 0
+	withCount := false
+	for i := 3; i < len(params.Command); i += 2 {
+		option := strings.ToLower(params.Command[i])
+		if !slices.Contains([]string{"rank", "count", "maxlen"}, option) {
+			return nil, fmt.Errorf("invalid option %s", params.Command[i])
+		}
+		if i+1 >= len(params.Command) {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+		n, err := strconv.Atoi(params.Command[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", strings.ToUpper(option))
+		}
+		switch option {
+		case "rank":
+			if n == 0 {
+				return nil, errors.New("RANK can't be zero: use 1 to start from the first match, 2 from the second ... " +
+					"or use negative to start from the end of the list")
+			}
+			rank = n
+		case "count":
+			if n < 0 {
+				return nil, errors.New("COUNT can't be negative")
+			}
+			count, withCount = n, true
+		case "maxlen":
+			if n < 0 {
+				return nil, errors.New("MAXLEN can't be negative")
+			}
+			maxLen = n
+		}
+	}
+
+	list := NewList()
+	if params.KeysExist(params.Context, keys.ReadKeys)[key] {
+		var ok bool
+		if list, ok = params.GetValues(params.Context, []string{key})[key].(*List); !ok {
+			return nil, errors.New("LPOS command on non-list item")
+		}
+	}
+
+	// A negative rank searches from the end of the list, skipping the first |rank|-1 matches.
+	skip := internal.AbsInt(rank) - 1
+	var matches []int
+	for i := 0; i < list.Len() && (maxLen == 0 || i < maxLen); i++ {
+		index := i
+		if rank < 0 {
+			index = list.Len() - 1 - i
+		}
+		if list.Index(index) != element {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		matches = append(matches, index)
+		// Without COUNT only the first match is returned. A COUNT of 0 returns all the matches.
+		if !withCount || (count > 0 && len(matches) == count) {
+			break
+		}
+	}
+
+	if !withCount {
+		if len(matches) == 0 {
+			return []byte("$-1\r\n"), nil
+		}
+		return []byte(fmt.Sprintf(":%d\r\n", matches[0])), nil
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(matches))
+	for _, index := range matches {
+		res += fmt.Sprintf(":%d\r\n", index)
+	}
+	return []byte(res), nil
+}
+
+func handleLMPop(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := lmpopKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	args := params.Command[2+len(keys.WriteKeys):]
+	where := strings.ToLower(args[0])
+	if !slices.Contains([]string{"left", "right"}, where) {
+		return nil, errors.New("where argument must be either LEFT or RIGHT")
+	}
+
+	count := 1
+	if len(args) > 1 {
+		if len(args) != 3 || !strings.EqualFold(args[1], "count") {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+		if count, err = strconv.Atoi(args[2]); err != nil || count <= 0 {
+			return nil, errors.New("count must be a positive integer")
+		}
+	}
+
+	keysExist := params.KeysExist(params.Context, keys.WriteKeys)
+	lists := params.GetValues(params.Context, keys.WriteKeys)
+	for _, key := range keys.WriteKeys {
+		if !keysExist[key] {
+			continue
+		}
+		list, ok := lists[key].(*List)
+		if !ok {
+			return nil, errors.New("LMPOP command on non-list item")
+		}
+		if list.Len() == 0 {
+			continue
+		}
+
+		// Pop the elements from the first non-empty list. Elements popped from the end of the list are
+		// returned starting with the last element.
+		popped := make([]string, 0, min(count, list.Len()))
+		for list.Len() > 0 && len(popped) < count {
+			if where == "left" {
+				popped = append(popped, list.PopFront())
+			} else {
+				popped = append(popped, list.PopBack())
+			}
+		}
+		if err = params.SetValues(params.Context, map[string]interface{}{key: list}); err != nil {
+			return nil, err
+		}
+
+		res := fmt.Sprintf("*2\r\n$%d\r\n%s\r\n*%d\r\n", len(key), key, len(popped))
+		for _, element := range popped {
+			res += fmt.Sprintf("$%d\r\n%s\r\n", len(element), element)
+		}
+		return []byte(res), nil
+	}
+
+	return []byte("*-1\r\n"), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -522,6 +876,19 @@ Prepends a value to the beginning of a list only if the list exists.`,
 			KeyExtractionFunc: lpushKeyFunc,
 			HandlerFunc:       handleLPush,
 		},
+		{
+			Command:    "lpushcap",
+			Module:     constants.ListModule,
+			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(LPUSHCAP key maxlen element [element ...])
+Prepends one or more values to the beginning of a list, creates the list if it does not exist.
+The length of the list is then capped at maxlen by dropping elements from the end of the list.
+The cap is a separate command because a MAXLEN option on LPUSH would be ambiguous with list elements.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: pushCapKeyFunc,
+			HandlerFunc:       handleLPush,
+		},
 		{
 			Command:    "lpop",
 			Module:     constants.ListModule,
@@ -595,6 +962,44 @@ Returns an array of n elements from the beginning of the list when called with a
 			KeyExtractionFunc: lremKeyFunc,
 			HandlerFunc:       handleLRem,
 		},
+		{
+			Command:    "linsert",
+			Module:     constants.ListModule,
+			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(LINSERT key <BEFORE | AFTER> pivot element)
+Inserts the element before or after the first occurrence of pivot in the list.
+Returns the length of the list after the insert, -1 if the pivot was not found, or 0 if the key does not exist.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: linsertKeyFunc,
+			HandlerFunc:       handleLInsert,
+		},
+		{
+			Command:    "lpos",
+			Module:     constants.ListModule,
+			Categories: []string{constants.ListCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(LPOS key element [RANK rank] [COUNT num-matches] [MAXLEN len])
+Returns the index of the first element of the list that matches element, or nil if there is no match.
+RANK skips the first rank-1 matches. A negative rank searches from the end of the list.
+COUNT returns an array of up to num-matches indexes. A COUNT of 0 returns all the matches.
+MAXLEN only compares the first len elements of the list, or the last len elements when searching from the end.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: lposKeyFunc,
+			HandlerFunc:       handleLPos,
+		},
+		{
+			Command:    "lmpop",
+			Module:     constants.ListModule,
+			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(LMPOP numkeys key [key ...] <LEFT | RIGHT> [COUNT count])
+Pops up to count elements, 1 by default, from the beginning or end of the first non-empty list.
+Returns the key of the list and the popped elements, or nil if all the lists are empty.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: lmpopKeyFunc,
+			HandlerFunc:       handleLMPop,
+		},
 		{
 			Command:    "lmove",
 			Module:     constants.ListModule,
@@ -606,6 +1011,51 @@ Move element from one list to the other specifying left/right for both lists.`,
 			KeyExtractionFunc: lmoveKeyFunc,
 			HandlerFunc:       handleLMove,
 		},
+		{
+			Command: "blpop",
+			Module:  constants.ListModule,
+			Categories: []string{
+				constants.ListCategory, constants.WriteCategory, constants.SlowCategory, constants.BlockingCategory,
+			},
+			Description: `(BLPOP key [key ...] timeout)
+Removes and returns the first element of the first non-empty list, along with the key of the list.
+If all the lists are empty, blocks until an element is pushed to one of them or until the timeout in seconds elapses.
+A timeout of 0 blocks indefinitely. Returns a null array when the command times out.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: blpopKeyFunc,
+			HandlerFunc:       handleBlockingPop,
+		},
+		{
+			Command: "brpop",
+			Module:  constants.ListModule,
+			Categories: []string{
+				constants.ListCategory, constants.WriteCategory, constants.SlowCategory, constants.BlockingCategory,
+			},
+			Description: `(BRPOP key [key ...] timeout)
+Removes and returns the last element of the first non-empty list, along with the key of the list.
+If all the lists are empty, blocks until an element is pushed to one of them or until the timeout in seconds elapses.
+A timeout of 0 blocks indefinitely. Returns a null array when the command times out.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: brpopKeyFunc,
+			HandlerFunc:       handleBlockingPop,
+		},
+		{
+			Command: "blmove",
+			Module:  constants.ListModule,
+			Categories: []string{
+				constants.ListCategory, constants.WriteCategory, constants.SlowCategory, constants.BlockingCategory,
+			},
+			Description: `(BLMOVE source destination <LEFT | RIGHT> <LEFT | RIGHT> timeout)
+Moves an element from the source list to the destination list and returns the element.
+The destination list is created if it does not exist. If the source list is empty, blocks until an element
+is pushed to it or until the timeout in seconds elapses. A timeout of 0 blocks indefinitely.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: blmoveKeyFunc,
+			HandlerFunc:       handleBLMove,
+		},
 		{
 			Command:    "rpop",
 			Module:     constants.ListModule,
@@ -619,6 +1069,18 @@ Returns an array of n elements from the end of the list when called with a count
 			KeyExtractionFunc: popKeyFunc,
 			HandlerFunc:       handlePop,
 		},
+		{
+			Command:    "rpoplpush",
+			Module:     constants.ListModule,
+			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(RPOPLPUSH source destination)
+Removes the last element of the source list and prepends it to the destination list, creating the destination
+list if it does not exist. Returns the element, or nil if the source list is empty.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: rpoplpushKeyFunc,
+			HandlerFunc:       handleRPopLPush,
+		},
 		{
 			Command:           "rpush",
 			Module:            constants.ListModule,
@@ -639,5 +1101,17 @@ Returns an array of n elements from the end of the list when called with a count
 			KeyExtractionFunc: rpushKeyFunc,
 			HandlerFunc:       handleRPush,
 		},
+		{
+			Command:    "rpushcap",
+			Module:     constants.ListModule,
+			Categories: []string{constants.ListCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(RPUSHCAP key maxlen element [element ...]) Appends one or multiple elements to the end of a list.
+The length of the list is then capped at maxlen by dropping elements from the beginning of the list.
+The cap is a separate command because a MAXLEN option on RPUSH would be ambiguous with list elements.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: pushCapKeyFunc,
+			HandlerFunc:       handleRPush,
+		},
 	}
 }
@@ -19,13 +19,16 @@ import (
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/config"
 	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/internal/modules/list"
 	"github.com/echovault/sugardb/sugardb"
 	"github.com/tidwall/resp"
 	"go/types"
+	"math/rand"
 	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func Test_List(t *testing.T) {
@@ -1412,6 +1415,51 @@ func Test_List(t *testing.T) {
 				expectedValue:    nil,
 				expectedError:    errors.New("LPUSHX command on non-existent key"),
 			},
+			{
+				name:             "6. LPUSHCAP drops the elements at the end of the list",
+				key:              "LpushKey8",
+				presetValue:      []string{"1", "2", "3"},
+				command:          []string{"LPUSHCAP", "LpushKey8", "4", "value1", "value2"},
+				expectedResponse: 4,
+				expectedValue:    []string{"value1", "value2", "1", "2"},
+				expectedError:    nil,
+			},
+			{
+				name:             "7. LPUSH pushes MAXLEN and the following integer as elements",
+				key:              "LpushKey9",
+				presetValue:      nil,
+				command:          []string{"LPUSH", "LpushKey9", "MAXLEN", "0", "value1"},
+				expectedResponse: 3,
+				expectedValue:    []string{"value1", "0", "MAXLEN"},
+				expectedError:    nil,
+			},
+			{
+				name:             "8. LPUSHCAP returns error when maxlen is not positive",
+				key:              "LpushKey10",
+				presetValue:      nil,
+				command:          []string{"LPUSHCAP", "LpushKey10", "0", "value1"},
+				expectedResponse: 0,
+				expectedValue:    nil,
+				expectedError:    errors.New("maxlen must be a positive integer"),
+			},
+			{
+				name:             "9. LPUSHCAP returns error when maxlen is not an integer",
+				key:              "LpushKey11",
+				presetValue:      nil,
+				command:          []string{"LPUSHCAP", "LpushKey11", "value1", "value2"},
+				expectedResponse: 0,
+				expectedValue:    nil,
+				expectedError:    errors.New("maxlen must be a positive integer"),
+			},
+			{
+				name:             "10. LPUSHCAP returns error when no elements are provided",
+				key:              "LpushKey12",
+				presetValue:      nil,
+				command:          []string{"LPUSHCAP", "LpushKey12", "4"},
+				expectedResponse: 0,
+				expectedValue:    nil,
+				expectedError:    errors.New(constants.WrongArgsResponse),
+			},
 		}
 
 		for _, test := range tests {
@@ -1567,6 +1615,24 @@ func Test_List(t *testing.T) {
 				expectedValue:    nil,
 				expectedError:    errors.New("RPUSHX command on non-existent key"),
 			},
+			{
+				name:             "6. RPUSHCAP drops the elements at the beginning of the list",
+				key:              "RpushKey8",
+				presetValue:      []string{"1", "2", "3"},
+				command:          []string{"RPUSHCAP", "RpushKey8", "4", "value1", "value2"},
+				expectedResponse: 4,
+				expectedValue:    []string{"2", "3", "value1", "value2"},
+				expectedError:    nil,
+			},
+			{
+				name:             "7. RPUSH pushes MAXLEN and the following integer as elements",
+				key:              "RpushKey9",
+				presetValue:      []string{"1"},
+				command:          []string{"RPUSH", "RpushKey9", "MAXLEN", "1", "value1"},
+				expectedResponse: 4,
+				expectedValue:    []string{"1", "MAXLEN", "1", "value1"},
+				expectedError:    nil,
+			},
 		}
 
 		for _, test := range tests {
@@ -1926,4 +1992,1010 @@ func Test_List(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Test_HandleBlockingPop", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		// The pusher pushes elements from a separate connection while the client is blocked.
+		pusherConn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = pusherConn.Close()
+		}()
+		pusher := resp.NewConn(pusherConn)
+
+		tests := []struct {
+			name             string
+			presetValues     map[string][]string
+			push             []string // Command executed from the pusher connection while the client is blocked.
+			command          []string
+			expectedResponse []string // Nil when the response is expected to be a null value.
+			expectedValues   map[string][]string
+			expectedError    error
+		}{
+			{
+				name:             "1. BLPOP pops from the first non-empty list without blocking",
+				presetValues:     map[string][]string{"BlockingPopKey2": {"one", "two"}},
+				command:          []string{"BLPOP", "BlockingPopKey1", "BlockingPopKey2", "0"},
+				expectedResponse: []string{"BlockingPopKey2", "one"},
+				expectedValues:   map[string][]string{"BlockingPopKey2": {"two"}},
+			},
+			{
+				name:             "2. BRPOP pops from the end of the list",
+				presetValues:     map[string][]string{"BlockingPopKey3": {"one", "two"}},
+				command:          []string{"BRPOP", "BlockingPopKey3", "0"},
+				expectedResponse: []string{"BlockingPopKey3", "two"},
+				expectedValues:   map[string][]string{"BlockingPopKey3": {"one"}},
+			},
+			{
+				name:             "3. BLPOP blocks until an element is pushed",
+				push:             []string{"RPUSH", "BlockingPopKey4", "one", "two"},
+				command:          []string{"BLPOP", "BlockingPopKey4", "5"},
+				expectedResponse: []string{"BlockingPopKey4", "one"},
+				expectedValues:   map[string][]string{"BlockingPopKey4": {"two"}},
+			},
+			{
+				name:             "4. BRPOP returns null when the timeout elapses",
+				command:          []string{"BRPOP", "BlockingPopKey5", "0.05"},
+				expectedResponse: nil,
+			},
+			{
+				name:             "5. BLMOVE moves the element and creates the destination list",
+				presetValues:     map[string][]string{"BlockingPopKey6": {"one", "two"}},
+				command:          []string{"BLMOVE", "BlockingPopKey6", "BlockingPopKey7", "RIGHT", "LEFT", "0"},
+				expectedResponse: []string{"two"},
+				expectedValues:   map[string][]string{"BlockingPopKey6": {"one"}, "BlockingPopKey7": {"two"}},
+			},
+			{
+				name:             "6. BLMOVE blocks until an element is pushed to the source list",
+				presetValues:     map[string][]string{"BlockingPopKey9": {"one"}},
+				push:             []string{"LPUSH", "BlockingPopKey8", "two"},
+				command:          []string{"BLMOVE", "BlockingPopKey8", "BlockingPopKey9", "LEFT", "RIGHT", "5"},
+				expectedResponse: []string{"two"},
+				expectedValues:   map[string][]string{"BlockingPopKey8": {}, "BlockingPopKey9": {"one", "two"}},
+			},
+			{
+				name:             "7. BLMOVE returns null when the timeout elapses",
+				command:          []string{"BLMOVE", "BlockingPopKey10", "BlockingPopKey11", "LEFT", "LEFT", "0.05"},
+				expectedResponse: nil,
+			},
+			{
+				name:          "8. Return error when the timeout is negative",
+				command:       []string{"BLPOP", "BlockingPopKey12", "-1"},
+				expectedError: errors.New("timeout is negative"),
+			},
+			{
+				name:          "9. Return error when the timeout is not a number",
+				command:       []string{"BLPOP", "BlockingPopKey12", "timeout"},
+				expectedError: errors.New("timeout is not a float or out of range"),
+			},
+			{
+				name:          "10. Command too short",
+				command:       []string{"BLPOP", "BlockingPopKey12"},
+				expectedError: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for key, values := range test.presetValues {
+					command := []resp.Value{resp.StringValue("RPUSH"), resp.StringValue(key)}
+					for _, value := range values {
+						command = append(command, resp.StringValue(value))
+					}
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					if _, _, err = client.ReadValue(); err != nil {
+						t.Error(err)
+					}
+				}
+
+				pushed := make(chan error, 1)
+				if test.push != nil {
+					go func() {
+						time.Sleep(100 * time.Millisecond)
+						command := make([]resp.Value, len(test.push))
+						for i, c := range test.push {
+							command[i] = resp.StringValue(c)
+						}
+						if err := pusher.WriteArray(command); err != nil {
+							pushed <- err
+							return
+						}
+						_, _, err := pusher.ReadValue()
+						pushed <- err
+					}()
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.push != nil {
+					if err = <-pushed; err != nil {
+						t.Error(err)
+					}
+				}
+
+				if test.expectedError != nil {
+					if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error().Error())
+					}
+					return
+				}
+
+				if test.expectedResponse == nil {
+					if !res.IsNull() {
+						t.Errorf("expected null response, got %+v", res)
+					}
+					return
+				}
+
+				var got []string
+				if res.Type() == resp.Array {
+					for _, item := range res.Array() {
+						got = append(got, item.String())
+					}
+				} else {
+					got = []string{res.String()}
+				}
+				if !slices.Equal(got, test.expectedResponse) {
+					t.Errorf("expected response %v, got %v", test.expectedResponse, got)
+				}
+
+				for key, expected := range test.expectedValues {
+					if err = client.WriteArray([]resp.Value{
+						resp.StringValue("LRANGE"),
+						resp.StringValue(key),
+						resp.StringValue("0"),
+						resp.StringValue("-1"),
+					}); err != nil {
+						t.Error(err)
+					}
+					res, _, err = client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+					got = []string{}
+					for _, item := range res.Array() {
+						got = append(got, item.String())
+					}
+					if !slices.Equal(got, expected) {
+						t.Errorf("expected list at key \"%s\" to be %v, got %v", key, expected, got)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Test_HandleLINSERT", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name             string
+			key              string
+			presetValue      interface{}
+			command          []string
+			expectedResponse int
+			expectedValue    []string
+			expectedError    error
+		}{
+			{
+				name:             "1. Insert element before the pivot",
+				key:              "LinsertKey1",
+				presetValue:      []string{"1", "2", "3"},
+				command:          []string{"LINSERT", "LinsertKey1", "BEFORE", "2", "value1"},
+				expectedResponse: 4,
+				expectedValue:    []string{"1", "value1", "2", "3"},
+				expectedError:    nil,
+			},
+			{
+				name:             "2. Insert element after the pivot",
+				key:              "LinsertKey2",
+				presetValue:      []string{"1", "2", "3"},
+				command:          []string{"LINSERT", "LinsertKey2", "AFTER", "3", "value1"},
+				expectedResponse: 4,
+				expectedValue:    []string{"1", "2", "3", "value1"},
+				expectedError:    nil,
+			},
+			{
+				name:             "3. Return -1 when the pivot is not in the list",
+				key:              "LinsertKey3",
+				presetValue:      []string{"1", "2", "3"},
+				command:          []string{"LINSERT", "LinsertKey3", "AFTER", "4", "value1"},
+				expectedResponse: -1,
+				expectedValue:    []string{"1", "2", "3"},
+				expectedError:    nil,
+			},
+			{
+				name:             "4. Return 0 when the list does not exist",
+				key:              "LinsertKey4",
+				presetValue:      nil,
+				command:          []string{"LINSERT", "LinsertKey4", "AFTER", "1", "value1"},
+				expectedResponse: 0,
+				expectedValue:    []string{},
+				expectedError:    nil,
+			},
+			{
+				name:             "5. Return error when where argument is not BEFORE or AFTER",
+				key:              "LinsertKey5",
+				presetValue:      []string{"1", "2", "3"},
+				command:          []string{"LINSERT", "LinsertKey5", "MIDDLE", "1", "value1"},
+				expectedResponse: 0,
+				expectedValue:    nil,
+				expectedError:    errors.New("where argument must be either BEFORE or AFTER"),
+			},
+			{
+				name:             "6. Return error when the value is not a list",
+				key:              "LinsertKey6",
+				presetValue:      "Default value",
+				command:          []string{"LINSERT", "LinsertKey6", "BEFORE", "1", "value1"},
+				expectedResponse: 0,
+				expectedValue:    nil,
+				expectedError:    errors.New("LINSERT command on non-list item"),
+			},
+			{
+				name:             "7. Command too short",
+				key:              "LinsertKey7",
+				presetValue:      nil,
+				command:          []string{"LINSERT", "LinsertKey7", "BEFORE", "1"},
+				expectedResponse: 0,
+				expectedValue:    nil,
+				expectedError:    errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if test.presetValue != nil {
+					var command []resp.Value
+					var expected string
+
+					switch test.presetValue.(type) {
+					case string:
+						command = []resp.Value{
+							resp.StringValue("SET"),
+							resp.StringValue(test.key),
+							resp.StringValue(test.presetValue.(string)),
+						}
+						expected = "ok"
+					case []string:
+						command = []resp.Value{resp.StringValue("RPUSH"), resp.StringValue(test.key)}
+						for _, element := range test.presetValue.([]string) {
+							command = append(command, []resp.Value{resp.StringValue(element)}...)
+						}
+						expected = strconv.Itoa(len(test.presetValue.([]string)))
+					}
+
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if !strings.EqualFold(res.String(), expected) {
+						t.Errorf("expected preset response to be \"%s\", got %s", expected, res.String())
+					}
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.expectedError != nil {
+					if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error().Error())
+					}
+					return
+				}
+
+				if res.Integer() != test.expectedResponse {
+					t.Errorf("expected response %d, got %d", test.expectedResponse, res.Integer())
+				}
+
+				if err = client.WriteArray([]resp.Value{
+					resp.StringValue("LRANGE"),
+					resp.StringValue(test.key),
+					resp.StringValue("0"),
+					resp.StringValue("-1"),
+				}); err != nil {
+					t.Error(err)
+				}
+
+				res, _, err = client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if len(res.Array()) != len(test.expectedValue) {
+					t.Errorf("expected list at key \"%s\" to be length %d, got %d",
+						test.key, len(test.expectedValue), len(res.Array()))
+				}
+
+				for i, item := range res.Array() {
+					if i < len(test.expectedValue) && item.String() != test.expectedValue[i] {
+						t.Errorf("expected element at index %d to be \"%s\", got \"%s\"",
+							i, test.expectedValue[i], item.String())
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Test_HandleLPOS", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		list := []string{"a", "b", "c", "1", "2", "3", "c", "c"}
+
+		tests := []struct {
+			name             string
+			key              string
+			presetValue      interface{}
+			command          []string
+			expectedResponse []int // nil when a nil response is expected.
+			expectArray      bool
+			expectedError    error
+		}{
+			{
+				name:             "1. Return the index of the first match",
+				key:              "LposKey1",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey1", "c"},
+				expectedResponse: []int{2},
+			},
+			{
+				name:             "2. Return the index of the match with the given rank",
+				key:              "LposKey2",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey2", "c", "RANK", "2"},
+				expectedResponse: []int{6},
+			},
+			{
+				name:             "3. Negative rank searches from the end of the list",
+				key:              "LposKey3",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey3", "c", "RANK", "-1"},
+				expectedResponse: []int{7},
+			},
+			{
+				name:             "4. Return the requested number of matches",
+				key:              "LposKey4",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey4", "c", "COUNT", "2"},
+				expectedResponse: []int{2, 6},
+				expectArray:      true,
+			},
+			{
+				name:             "5. COUNT 0 returns all the matches",
+				key:              "LposKey5",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey5", "c", "COUNT", "0"},
+				expectedResponse: []int{2, 6, 7},
+				expectArray:      true,
+			},
+			{
+				name:             "6. Combine RANK and COUNT",
+				key:              "LposKey6",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey6", "c", "RANK", "-2", "COUNT", "0"},
+				expectedResponse: []int{6, 2},
+				expectArray:      true,
+			},
+			{
+				name:             "7. MAXLEN limits the number of compared elements",
+				key:              "LposKey7",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey7", "c", "COUNT", "0", "MAXLEN", "7"},
+				expectedResponse: []int{2, 6},
+				expectArray:      true,
+			},
+			{
+				name:             "8. Return nil when there is no match",
+				key:              "LposKey8",
+				presetValue:      list,
+				command:          []string{"LPOS", "LposKey8", "d"},
+				expectedResponse: nil,
+			},
+			{
+				name:             "9. Return an empty array with COUNT when the key does not exist",
+				key:              "LposKey9",
+				presetValue:      nil,
+				command:          []string{"LPOS", "LposKey9", "d", "COUNT", "1"},
+				expectedResponse: []int{},
+				expectArray:      true,
+			},
+			{
+				name:          "10. Return error when RANK is zero",
+				key:           "LposKey10",
+				presetValue:   list,
+				command:       []string{"LPOS", "LposKey10", "c", "RANK", "0"},
+				expectedError: errors.New("RANK can't be zero"),
+			},
+			{
+				name:          "11. Return error when COUNT is negative",
+				key:           "LposKey11",
+				presetValue:   list,
+				command:       []string{"LPOS", "LposKey11", "c", "COUNT", "-1"},
+				expectedError: errors.New("COUNT can't be negative"),
+			},
+			{
+				name:          "12. Return error when the value is not a list",
+				key:           "LposKey12",
+				presetValue:   "Default value",
+				command:       []string{"LPOS", "LposKey12", "c"},
+				expectedError: errors.New("LPOS command on non-list item"),
+			},
+			{
+				name:          "13. Command too short",
+				key:           "LposKey13",
+				presetValue:   nil,
+				command:       []string{"LPOS", "LposKey13"},
+				expectedError: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if test.presetValue != nil {
+					var command []resp.Value
+					var expected string
+
+					switch test.presetValue.(type) {
+					case string:
+						command = []resp.Value{
+							resp.StringValue("SET"),
+							resp.StringValue(test.key),
+							resp.StringValue(test.presetValue.(string)),
+						}
+						expected = "ok"
+					case []string:
+						command = []resp.Value{resp.StringValue("RPUSH"), resp.StringValue(test.key)}
+						for _, element := range test.presetValue.([]string) {
+							command = append(command, []resp.Value{resp.StringValue(element)}...)
+						}
+						expected = strconv.Itoa(len(test.presetValue.([]string)))
+					}
+
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if !strings.EqualFold(res.String(), expected) {
+						t.Errorf("expected preset response to be \"%s\", got %s", expected, res.String())
+					}
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.expectedError != nil {
+					if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error().Error())
+					}
+					return
+				}
+
+				if test.expectedResponse == nil {
+					if !res.IsNull() {
+						t.Errorf("expected nil response, got %+v", res)
+					}
+					return
+				}
+
+				if !test.expectArray {
+					if res.Integer() != test.expectedResponse[0] {
+						t.Errorf("expected response %d, got %d", test.expectedResponse[0], res.Integer())
+					}
+					return
+				}
+
+				if len(res.Array()) != len(test.expectedResponse) {
+					t.Errorf("expected response array of length %d, got %d", len(test.expectedResponse), len(res.Array()))
+					return
+				}
+				for i, item := range res.Array() {
+					if item.Integer() != test.expectedResponse[i] {
+						t.Errorf("expected index %d at position %d, got %d", test.expectedResponse[i], i, item.Integer())
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Test_HandleLMPOP", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name             string
+			presetValue      map[string]interface{}
+			command          []string
+			expectedKey      string
+			expectedResponse []string // nil when a nil response is expected.
+			expectedValue    map[string][]string
+			expectedError    error
+		}{
+			{
+				name: "1. Pop from the left of the first non-empty list",
+				presetValue: map[string]interface{}{
+					"LmpopKey2": []string{"1", "2", "3"},
+				},
+				command:          []string{"LMPOP", "2", "LmpopKey1", "LmpopKey2", "LEFT"},
+				expectedKey:      "LmpopKey2",
+				expectedResponse: []string{"1"},
+				expectedValue:    map[string][]string{"LmpopKey2": {"2", "3"}},
+			},
+			{
+				name: "2. Pop multiple elements from the right of the list",
+				presetValue: map[string]interface{}{
+					"LmpopKey3": []string{"1", "2", "3", "4"},
+				},
+				command:          []string{"LMPOP", "1", "LmpopKey3", "RIGHT", "COUNT", "3"},
+				expectedKey:      "LmpopKey3",
+				expectedResponse: []string{"4", "3", "2"},
+				expectedValue:    map[string][]string{"LmpopKey3": {"1"}},
+			},
+			{
+				name: "3. COUNT larger than the list pops all the elements",
+				presetValue: map[string]interface{}{
+					"LmpopKey4": []string{"1", "2"},
+				},
+				command:          []string{"LMPOP", "1", "LmpopKey4", "LEFT", "COUNT", "5"},
+				expectedKey:      "LmpopKey4",
+				expectedResponse: []string{"1", "2"},
+				expectedValue:    map[string][]string{"LmpopKey4": {}},
+			},
+			{
+				name:             "4. Return nil when none of the lists exist",
+				presetValue:      nil,
+				command:          []string{"LMPOP", "2", "LmpopKey5", "LmpopKey6", "LEFT"},
+				expectedResponse: nil,
+			},
+			{
+				name:          "5. Return error when numkeys is not a positive integer",
+				presetValue:   nil,
+				command:       []string{"LMPOP", "0", "LmpopKey7", "LEFT"},
+				expectedError: errors.New("numkeys must be a positive integer"),
+			},
+			{
+				name:          "6. Return error when where argument is not LEFT or RIGHT",
+				presetValue:   nil,
+				command:       []string{"LMPOP", "1", "LmpopKey8", "UP"},
+				expectedError: errors.New("where argument must be either LEFT or RIGHT"),
+			},
+			{
+				name:          "7. Return error when the value is not a list",
+				presetValue:   map[string]interface{}{"LmpopKey9": "Default value"},
+				command:       []string{"LMPOP", "1", "LmpopKey9", "LEFT"},
+				expectedError: errors.New("LMPOP command on non-list item"),
+			},
+			{
+				name:          "8. Command too short",
+				presetValue:   nil,
+				command:       []string{"LMPOP", "1", "LmpopKey10"},
+				expectedError: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for key, value := range test.presetValue {
+					var command []resp.Value
+					var expected string
+
+					switch value.(type) {
+					case string:
+						command = []resp.Value{
+							resp.StringValue("SET"),
+							resp.StringValue(key),
+							resp.StringValue(value.(string)),
+						}
+						expected = "ok"
+					case []string:
+						command = []resp.Value{resp.StringValue("RPUSH"), resp.StringValue(key)}
+						for _, element := range value.([]string) {
+							command = append(command, []resp.Value{resp.StringValue(element)}...)
+						}
+						expected = strconv.Itoa(len(value.([]string)))
+					}
+
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if !strings.EqualFold(res.String(), expected) {
+						t.Errorf("expected preset response to be \"%s\", got %s", expected, res.String())
+					}
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.expectedError != nil {
+					if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error().Error())
+					}
+					return
+				}
+
+				if test.expectedResponse == nil {
+					if !res.IsNull() {
+						t.Errorf("expected nil response, got %+v", res)
+					}
+					return
+				}
+
+				if len(res.Array()) != 2 {
+					t.Errorf("expected response array of length 2, got %d", len(res.Array()))
+					return
+				}
+				if res.Array()[0].String() != test.expectedKey {
+					t.Errorf("expected key \"%s\", got \"%s\"", test.expectedKey, res.Array()[0].String())
+				}
+				popped := res.Array()[1].Array()
+				if len(popped) != len(test.expectedResponse) {
+					t.Errorf("expected %d popped elements, got %d", len(test.expectedResponse), len(popped))
+					return
+				}
+				for i, item := range popped {
+					if item.String() != test.expectedResponse[i] {
+						t.Errorf("expected popped element \"%s\" at index %d, got \"%s\"",
+							test.expectedResponse[i], i, item.String())
+					}
+				}
+
+				for key, expectedList := range test.expectedValue {
+					if err = client.WriteArray([]resp.Value{
+						resp.StringValue("LRANGE"),
+						resp.StringValue(key),
+						resp.StringValue("0"),
+						resp.StringValue("-1"),
+					}); err != nil {
+						t.Error(err)
+					}
+					res, _, err = client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+					if len(res.Array()) != len(expectedList) {
+						t.Errorf("expected list at key \"%s\" to be length %d, got %d",
+							key, len(expectedList), len(res.Array()))
+						continue
+					}
+					for i, item := range res.Array() {
+						if item.String() != expectedList[i] {
+							t.Errorf("expected element \"%s\" at index %d, got \"%s\"", expectedList[i], i, item.String())
+						}
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("Test_HandleRPOPLPUSH", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name             string
+			presetValue      map[string]interface{}
+			command          []string
+			expectedResponse string // empty when a nil response is expected.
+			expectedValue    map[string][]string
+			expectedError    error
+		}{
+			{
+				name: "1. Move the last element of the source to the start of the destination",
+				presetValue: map[string]interface{}{
+					"RpoplpushKey1": []string{"1", "2", "3"},
+					"RpoplpushKey2": []string{"a", "b"},
+				},
+				command:          []string{"RPOPLPUSH", "RpoplpushKey1", "RpoplpushKey2"},
+				expectedResponse: "3",
+				expectedValue: map[string][]string{
+					"RpoplpushKey1": {"1", "2"},
+					"RpoplpushKey2": {"3", "a", "b"},
+				},
+			},
+			{
+				name: "2. Create the destination list if it does not exist",
+				presetValue: map[string]interface{}{
+					"RpoplpushKey3": []string{"1", "2", "3"},
+				},
+				command:          []string{"RPOPLPUSH", "RpoplpushKey3", "RpoplpushKey4"},
+				expectedResponse: "3",
+				expectedValue: map[string][]string{
+					"RpoplpushKey3": {"1", "2"},
+					"RpoplpushKey4": {"3"},
+				},
+			},
+			{
+				name: "3. Rotate the list when the source and destination are the same",
+				presetValue: map[string]interface{}{
+					"RpoplpushKey5": []string{"1", "2", "3"},
+				},
+				command:          []string{"RPOPLPUSH", "RpoplpushKey5", "RpoplpushKey5"},
+				expectedResponse: "3",
+				expectedValue: map[string][]string{
+					"RpoplpushKey5": {"3", "1", "2"},
+				},
+			},
+			{
+				name:             "4. Return nil when the source list does not exist",
+				presetValue:      nil,
+				command:          []string{"RPOPLPUSH", "RpoplpushKey6", "RpoplpushKey7"},
+				expectedResponse: "",
+			},
+			{
+				name: "5. Return error when the destination is not a list",
+				presetValue: map[string]interface{}{
+					"RpoplpushKey8": []string{"1", "2", "3"},
+					"RpoplpushKey9": "Default value",
+				},
+				command:       []string{"RPOPLPUSH", "RpoplpushKey8", "RpoplpushKey9"},
+				expectedError: errors.New("both source and destination must be lists"),
+			},
+			{
+				name:          "6. Command too short",
+				presetValue:   nil,
+				command:       []string{"RPOPLPUSH", "RpoplpushKey10"},
+				expectedError: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for key, value := range test.presetValue {
+					var command []resp.Value
+					var expected string
+
+					switch value.(type) {
+					case string:
+						command = []resp.Value{
+							resp.StringValue("SET"),
+							resp.StringValue(key),
+							resp.StringValue(value.(string)),
+						}
+						expected = "ok"
+					case []string:
+						command = []resp.Value{resp.StringValue("RPUSH"), resp.StringValue(key)}
+						for _, element := range value.([]string) {
+							command = append(command, []resp.Value{resp.StringValue(element)}...)
+						}
+						expected = strconv.Itoa(len(value.([]string)))
+					}
+
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if !strings.EqualFold(res.String(), expected) {
+						t.Errorf("expected preset response to be \"%s\", got %s", expected, res.String())
+					}
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.expectedError != nil {
+					if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error().Error())
+					}
+					return
+				}
+
+				if test.expectedResponse == "" {
+					if !res.IsNull() {
+						t.Errorf("expected nil response, got %+v", res)
+					}
+					return
+				}
+
+				if res.String() != test.expectedResponse {
+					t.Errorf("expected response \"%s\", got \"%s\"", test.expectedResponse, res.String())
+				}
+
+				for key, expectedList := range test.expectedValue {
+					if err = client.WriteArray([]resp.Value{
+						resp.StringValue("LRANGE"),
+						resp.StringValue(key),
+						resp.StringValue("0"),
+						resp.StringValue("-1"),
+					}); err != nil {
+						t.Error(err)
+					}
+					res, _, err = client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+					if len(res.Array()) != len(expectedList) {
+						t.Errorf("expected list at key \"%s\" to be length %d, got %d",
+							key, len(expectedList), len(res.Array()))
+						continue
+					}
+					for i, item := range res.Array() {
+						if item.String() != expectedList[i] {
+							t.Errorf("expected element \"%s\" at index %d, got \"%s\"", expectedList[i], i, item.String())
+						}
+					}
+				}
+			})
+		}
+	})
+}
+
+func Test_ListDeque(t *testing.T) {
+	// Apply random operations to both ends of the deque and check it against a plain slice after each step.
+	rng := rand.New(rand.NewSource(1))
+	l := list.NewList()
+	var expected []string
+
+	for i := 0; i < 5000; i++ {
+		element := strconv.Itoa(i)
+		switch rng.Intn(6) {
+		case 0:
+			l.PushFront(element, element+"a")
+			expected = append([]string{element, element + "a"}, expected...)
+		case 1:
+			l.PushBack(element)
+			expected = append(expected, element)
+		case 2:
+			if len(expected) > 0 {
+				if popped := l.PopFront(); popped != expected[0] {
+					t.Fatalf("step %d: expected front %s, got %s", i, expected[0], popped)
+				}
+				expected = expected[1:]
+			}
+		case 3:
+			if len(expected) > 0 {
+				if popped := l.PopBack(); popped != expected[len(expected)-1] {
+					t.Fatalf("step %d: expected back %s, got %s", i, expected[len(expected)-1], popped)
+				}
+				expected = expected[:len(expected)-1]
+			}
+		case 4:
+			index := rng.Intn(len(expected) + 1)
+			l.Insert(index, element)
+			expected = slices.Insert(expected, index, element)
+		case 5:
+			if len(expected) > 0 {
+				index := rng.Intn(len(expected))
+				l.Set(index, element)
+				expected[index] = element
+			}
+		}
+
+		if l.Len() != len(expected) {
+			t.Fatalf("step %d: expected length %d, got %d", i, len(expected), l.Len())
+		}
+	}
+
+	if !slices.Equal(l.Elements(), expected) {
+		t.Fatalf("expected elements %v, got %v", expected, l.Elements())
+	}
+	for i := range expected {
+		if l.Index(i) != expected[i] {
+			t.Fatalf("expected element %s at index %d, got %s", expected[i], i, l.Index(i))
+		}
+	}
+
+	clone := l.Clone()
+	l.Trim(1, l.Len()-1)
+	if !slices.Equal(l.Elements(), expected[1:len(expected)-1]) {
+		t.Errorf("expected trimmed elements %v, got %v", expected[1:len(expected)-1], l.Elements())
+	}
+	if !slices.Equal(clone.Elements(), expected) {
+		t.Errorf("expected clone to be unaffected by trim")
+	}
+	if l.GetMem() <= 0 {
+		t.Errorf("expected positive memory usage, got %d", l.GetMem())
+	}
 }
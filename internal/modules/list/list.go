@@ -0,0 +1,193 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package list
+
+import (
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+const minCapacity = 8
+
+// List is a double-ended queue backed by a ring buffer. Pushing and popping at both ends take amortised
+// constant time, and any element can be accessed by its index in constant time.
+type List struct {
+	elements []string
+	head     int
+	length   int
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*List)(nil)
+
+func NewList(elements ...string) *List {
+	list := &List{}
+	list.PushBack(elements...)
+	return list
+}
+
+func (list *List) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*list))
+	// The ring buffer holds a string header for every slot, including the empty ones.
+	size += int64(cap(list.elements)) * int64(unsafe.Sizeof(""))
+	for i := 0; i < list.length; i++ {
+		size += int64(len(list.Index(i)))
+	}
+	return size
+}
+
+func (list *List) Len() int {
+	return list.length
+}
+
+// position returns the position in the ring buffer of the element at the given index of the list.
+func (list *List) position(index int) int {
+	return (list.head + index) % len(list.elements)
+}
+
+// Index returns the element at the given index. The index must be within the bounds of the list.
+func (list *List) Index(index int) string {
+	return list.elements[list.position(index)]
+}
+
+// Set replaces the element at the given index. The index must be within the bounds of the list.
+func (list *List) Set(index int, element string) {
+	list.elements[list.position(index)] = element
+}
+
+// resize moves the elements into a new ring buffer with the given capacity, starting at position 0.
+func (list *List) resize(capacity int) {
+	elements := make([]string, capacity)
+	if list.length > 0 {
+		n := copy(elements, list.elements[list.head:min(list.head+list.length, len(list.elements))])
+		copy(elements[n:], list.elements[:list.length-n])
+	}
+	list.elements = elements
+	list.head = 0
+}
+
+// grow makes room for n more elements.
+func (list *List) grow(n int) {
+	if list.length+n <= len(list.elements) {
+		return
+	}
+	capacity := max(len(list.elements), minCapacity)
+	for capacity < list.length+n {
+		capacity *= 2
+	}
+	list.resize(capacity)
+}
+
+// shrink releases memory when the list only uses a quarter of its ring buffer.
+func (list *List) shrink() {
+	if len(list.elements) > minCapacity && list.length <= len(list.elements)/4 {
+		list.resize(max(len(list.elements)/2, minCapacity))
+	}
+}
+
+// PushFront adds the elements to the beginning of the list. The elements keep the order they are provided in.
+func (list *List) PushFront(elements ...string) {
+	list.grow(len(elements))
+	for i := len(elements) - 1; i >= 0; i-- {
+		list.head = (list.head - 1 + len(list.elements)) % len(list.elements)
+		list.elements[list.head] = elements[i]
+		list.length++
+	}
+}
+
+// PushBack adds the elements to the end of the list.
+func (list *List) PushBack(elements ...string) {
+	list.grow(len(elements))
+	for _, element := range elements {
+		list.elements[list.position(list.length)] = element
+		list.length++
+	}
+}
+
+// PopFront removes and returns the first element of the list. The list must not be empty.
+func (list *List) PopFront() string {
+	element := list.elements[list.head]
+	list.elements[list.head] = ""
+	list.head = (list.head + 1) % len(list.elements)
+	list.length--
+	list.shrink()
+	return element
+}
+
+// PopBack removes and returns the last element of the list. The list must not be empty.
+func (list *List) PopBack() string {
+	position := list.position(list.length - 1)
+	element := list.elements[position]
+	list.elements[position] = ""
+	list.length--
+	list.shrink()
+	return element
+}
+
+// Insert adds the element at the given index, shifting the elements on the shorter side of the index.
+// The index must be between 0 and the length of the list.
+func (list *List) Insert(index int, element string) {
+	if index < list.length/2 {
+		list.PushFront(element)
+		for i := 0; i < index; i++ {
+			list.Set(i, list.Index(i+1))
+		}
+	} else {
+		list.PushBack(element)
+		for i := list.length - 1; i > index; i-- {
+			list.Set(i, list.Index(i-1))
+		}
+	}
+	list.Set(index, element)
+}
+
+// Trim keeps the elements from start up to, but not including, end. The indices must be within the bounds
+// of the list.
+func (list *List) Trim(start, end int) {
+	if start >= end {
+		*list = List{}
+		return
+	}
+	for i := end; i < list.length; i++ {
+		list.elements[list.position(i)] = ""
+	}
+	for i := 0; i < start; i++ {
+		list.elements[list.position(i)] = ""
+	}
+	list.head = list.position(start)
+	list.length = end - start
+	list.shrink()
+}
+
+// Range returns a copy of the elements from start up to, but not including, end. The indices must be within
+// the bounds of the list.
+func (list *List) Range(start, end int) []string {
+	elements := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		elements = append(elements, list.Index(i))
+	}
+	return elements
+}
+
+// Elements returns a copy of all the elements of the list.
+func (list *List) Elements() []string {
+	return list.Range(0, list.length)
+}
+
+func (list *List) Clone() *List {
+	return NewList(list.Elements()...)
+}
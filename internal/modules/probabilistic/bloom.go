@@ -0,0 +1,211 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilistic
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+const (
+	DefaultBloomErrorRate = 0.01
+	DefaultBloomCapacity  = 100
+	DefaultBloomExpansion = 2
+	// bloomTighteningRatio is the factor by which the error rate of each new layer is reduced, so that the
+	// error rate of the whole filter stays within twice the requested rate however many layers are added.
+	bloomTighteningRatio = 0.5
+)
+
+var ErrBloomFull = errors.New("non scaling filter is full")
+
+// bloomLayer is a classic Bloom filter sized for a fixed number of items.
+type bloomLayer struct {
+	bits     []uint64
+	size     uint64 // Number of bits
+	hashes   int
+	capacity int
+	count    int
+}
+
+func newBloomLayer(errorRate float64, capacity int) *bloomLayer {
+	size := uint64(math.Ceil(-float64(capacity) * math.Log(errorRate) / (math.Ln2 * math.Ln2)))
+	size = max(size, 64)
+	return &bloomLayer{
+		bits:     make([]uint64, (size+63)/64),
+		size:     size,
+		hashes:   max(int(math.Ceil(-math.Log2(errorRate))), 1),
+		capacity: capacity,
+	}
+}
+
+func (layer *bloomLayer) contains(h1, h2 uint64) bool {
+	for i := 0; i < layer.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % layer.size
+		if layer.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (layer *bloomLayer) add(h1, h2 uint64) {
+	for i := 0; i < layer.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % layer.size
+		layer.bits[bit/64] |= 1 << (bit % 64)
+	}
+	layer.count++
+}
+
+// BloomFilter tells whether an item may have been added to it. It has no false negatives, and false positives
+// occur at most at the configured error rate.
+//
+// A scaling filter adds a new layer, expansion times larger than the last one, whenever the last layer holds as
+// many items as it was sized for. A non-scaling filter rejects items once it's full.
+type BloomFilter struct {
+	errorRate float64
+	expansion int // 0 when the filter doesn't scale
+	layers    []*bloomLayer
+}
+
+func NewBloomFilter(errorRate float64, capacity int, expansion int) *BloomFilter {
+	return &BloomFilter{
+		errorRate: errorRate,
+		expansion: expansion,
+		layers:    []*bloomLayer{newBloomLayer(errorRate, capacity)},
+	}
+}
+
+func (bf *BloomFilter) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*bf))
+	for _, layer := range bf.layers {
+		size += int64(unsafe.Sizeof(*layer))
+		size += int64(cap(layer.bits)) * 8
+	}
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*BloomFilter)(nil)
+
+// Add adds the item and returns false if the item may already have been added.
+func (bf *BloomFilter) Add(item string) (bool, error) {
+	h1, h2 := hashItem(item)
+	if bf.contains(h1, h2) {
+		return false, nil
+	}
+	last := bf.layers[len(bf.layers)-1]
+	if last.count >= last.capacity {
+		if bf.expansion == 0 {
+			return false, ErrBloomFull
+		}
+		errorRate := bf.errorRate * math.Pow(bloomTighteningRatio, float64(len(bf.layers)))
+		last = newBloomLayer(errorRate, last.capacity*bf.expansion)
+		bf.layers = append(bf.layers, last)
+	}
+	last.add(h1, h2)
+	return true, nil
+}
+
+// Exists returns true if the item may have been added, and false if it definitely wasn't.
+func (bf *BloomFilter) Exists(item string) bool {
+	return bf.contains(hashItem(item))
+}
+
+func (bf *BloomFilter) contains(h1, h2 uint64) bool {
+	for _, layer := range bf.layers {
+		if layer.contains(h1, h2) {
+			return true
+		}
+	}
+	return false
+}
+
+// Card returns the number of items added to the filter.
+func (bf *BloomFilter) Card() int {
+	count := 0
+	for _, layer := range bf.layers {
+		count += layer.count
+	}
+	return count
+}
+
+// Capacity returns the number of items the filter can hold before it scales or becomes full.
+func (bf *BloomFilter) Capacity() int {
+	capacity := 0
+	for _, layer := range bf.layers {
+		capacity += layer.capacity
+	}
+	return capacity
+}
+
+// Layers returns the number of layers of the filter.
+func (bf *BloomFilter) Layers() int {
+	return len(bf.layers)
+}
+
+// Expansion returns the growth factor of new layers, or 0 if the filter doesn't scale.
+func (bf *BloomFilter) Expansion() int {
+	return bf.expansion
+}
+
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	b := internal.AppendBinaryFloat(nil, bf.errorRate)
+	b = binary.AppendUvarint(b, uint64(bf.expansion))
+	b = binary.AppendUvarint(b, uint64(len(bf.layers)))
+	for _, layer := range bf.layers {
+		b = binary.AppendUvarint(b, layer.size)
+		b = binary.AppendUvarint(b, uint64(layer.hashes))
+		b = binary.AppendUvarint(b, uint64(layer.capacity))
+		b = binary.AppendUvarint(b, uint64(layer.count))
+		b = appendWords(b, layer.bits)
+	}
+	return b, nil
+}
+
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	filter := BloomFilter{
+		errorRate: r.Float(),
+		expansion: int(r.Uvarint()),
+	}
+	n := r.Count()
+	for i := 0; i < n && r.Err() == nil; i++ {
+		layer := &bloomLayer{
+			size:     r.Uvarint(),
+			hashes:   int(r.Uvarint()),
+			capacity: int(r.Uvarint()),
+			count:    int(r.Uvarint()),
+			bits:     readWords(r),
+		}
+		if r.Err() == nil && uint64(len(layer.bits)) != (layer.size+63)/64 {
+			return errors.New("invalid bloom filter layer")
+		}
+		filter.layers = append(filter.layers, layer)
+	}
+	if r.Err() != nil {
+		return r.Err()
+	}
+	if len(filter.layers) == 0 {
+		return errors.New("invalid bloom filter")
+	}
+	*bf = filter
+	return nil
+}
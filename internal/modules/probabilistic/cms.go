@@ -0,0 +1,134 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilistic
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// CountMinSketch estimates how many times each item was counted. It keeps depth rows of width counters, and an
+// item increments one counter in every row. The estimate of an item is its smallest counter, which overestimates
+// the true count by at most error * total count with the configured probability.
+type CountMinSketch struct {
+	width    int
+	depth    int
+	count    uint64 // Sum of all the increments
+	counters []uint64
+}
+
+func NewCountMinSketch(width int, depth int) *CountMinSketch {
+	return &CountMinSketch{
+		width:    width,
+		depth:    depth,
+		counters: make([]uint64, width*depth),
+	}
+}
+
+// NewCountMinSketchByProb returns a sketch whose estimates exceed the true count by more than errorRate * total count
+// with at most the given probability.
+func NewCountMinSketchByProb(errorRate float64, probability float64) *CountMinSketch {
+	width := int(math.Ceil(2 / errorRate))
+	depth := int(math.Ceil(math.Log10(probability) / math.Log10(0.5)))
+	return NewCountMinSketch(width, max(depth, 1))
+}
+
+func (cms *CountMinSketch) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*cms))
+	size += int64(cap(cms.counters)) * 8
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*CountMinSketch)(nil)
+
+// IncrBy increments the count of the item and returns its new estimated count.
+func (cms *CountMinSketch) IncrBy(item string, increment uint64) uint64 {
+	h1, h2 := hashItem(item)
+	estimate := uint64(math.MaxUint64)
+	for i := 0; i < cms.depth; i++ {
+		index := i*cms.width + int((h1+uint64(i)*h2)%uint64(cms.width))
+		cms.counters[index] += increment
+		estimate = min(estimate, cms.counters[index])
+	}
+	cms.count += increment
+	return estimate
+}
+
+// Query returns the estimated count of the item.
+func (cms *CountMinSketch) Query(item string) uint64 {
+	h1, h2 := hashItem(item)
+	estimate := uint64(math.MaxUint64)
+	for i := 0; i < cms.depth; i++ {
+		estimate = min(estimate, cms.counters[i*cms.width+int((h1+uint64(i)*h2)%uint64(cms.width))])
+	}
+	return estimate
+}
+
+// Merge sets the counters to the weighted sum of the counters of the sketches.
+// All the sketches must have the same width and depth.
+func (cms *CountMinSketch) Merge(sketches []*CountMinSketch, weights []uint64) error {
+	for _, sketch := range sketches {
+		if sketch.width != cms.width || sketch.depth != cms.depth {
+			return errors.New("width/depth is not equal")
+		}
+	}
+	counters := make([]uint64, len(cms.counters))
+	var count uint64
+	for i, sketch := range sketches {
+		for j, counter := range sketch.counters {
+			counters[j] += counter * weights[i]
+		}
+		count += sketch.count * weights[i]
+	}
+	cms.counters, cms.count = counters, count
+	return nil
+}
+
+// Info returns the width, depth and total count of the sketch.
+func (cms *CountMinSketch) Info() (width int, depth int, count uint64) {
+	return cms.width, cms.depth, cms.count
+}
+
+func (cms *CountMinSketch) MarshalBinary() ([]byte, error) {
+	b := binary.AppendUvarint(nil, uint64(cms.width))
+	b = binary.AppendUvarint(b, uint64(cms.depth))
+	b = binary.AppendUvarint(b, cms.count)
+	return appendWords(b, cms.counters), nil
+}
+
+func (cms *CountMinSketch) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	sketch := CountMinSketch{
+		width: int(r.Uvarint()),
+		depth: int(r.Uvarint()),
+		count: r.Uvarint(),
+	}
+	sketch.counters = readWords(r)
+	if r.Err() != nil {
+		return r.Err()
+	}
+	if sketch.width == 0 || len(sketch.counters) != sketch.width*sketch.depth {
+		return errors.New("invalid count-min sketch")
+	}
+	*cms = sketch
+	return nil
+}
@@ -0,0 +1,1081 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilistic
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// getValue returns the value at key. It returns false if the key doesn't exist, and an error if the value
+// is not of type T.
+func getValue[T any](params internal.HandlerFuncParams, key string, typeName string) (T, bool, error) {
+	var value T
+	if !params.KeysExist(params.Context, []string{key})[key] {
+		return value, false, nil
+	}
+	value, ok := params.GetValues(params.Context, []string{key})[key].(T)
+	if !ok {
+		return value, false, fmt.Errorf("value at key %s is not a %s", key, typeName)
+	}
+	return value, true, nil
+}
+
+func handleBFRESERVE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := getBloomOptions(append([]string{"ERROR", params.Command[2], "CAPACITY", params.Command[3]},
+		params.Command[4:]...))
+	if err != nil {
+		return nil, err
+	}
+	if options.noCreate || options.items != nil {
+		return nil, errors.New("syntax error")
+	}
+
+	key := keys.WriteKeys[0]
+	if params.KeysExist(params.Context, []string{key})[key] {
+		return nil, fmt.Errorf("key %s already exists", key)
+	}
+
+	bf := NewBloomFilter(options.errorRate, options.capacity, options.expansion)
+	if err = params.SetValues(params.Context, map[string]interface{}{key: bf}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+// handleBFADD handles BF.ADD, BF.MADD and BF.INSERT. BF.ADD replies with an integer and the others with an array
+// that holds the reply of each item.
+func handleBFADD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	command := strings.ToLower(params.Command[0])
+	options, err := getBloomOptions(nil)
+	switch command {
+	case "bf.add":
+		if len(params.Command) != 3 {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+		options.items = params.Command[2:]
+	case "bf.madd":
+		options.items = params.Command[2:]
+	default:
+		if options, err = getBloomOptions(params.Command[2:]); err != nil {
+			return nil, err
+		}
+		if options.items == nil {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+	}
+
+	key := keys.WriteKeys[0]
+	bf, exists, err := getValue[*BloomFilter](params, key, "bloom filter")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if options.noCreate {
+			return nil, fmt.Errorf("key %s does not exist", key)
+		}
+		bf = NewBloomFilter(options.errorRate, options.capacity, options.expansion)
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(options.items))
+	for _, item := range options.items {
+		added, err := bf.Add(item)
+		switch {
+		case err != nil && command == "bf.add":
+			return nil, err
+		case err != nil:
+			res += fmt.Sprintf("-Error %s\r\n", err.Error())
+		case added:
+			res += ":1\r\n"
+		default:
+			res += ":0\r\n"
+		}
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: bf}); err != nil {
+		return nil, err
+	}
+	if command == "bf.add" {
+		return []byte(strings.TrimPrefix(res, "*1\r\n")), nil
+	}
+	return []byte(res), nil
+}
+
+// handleBFEXISTS handles BF.EXISTS and BF.MEXISTS.
+func handleBFEXISTS(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(params.Command[0], "bf.exists") && len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	key := keys.ReadKeys[0]
+	bf, exists, err := getValue[*BloomFilter](params, key, "bloom filter")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]int, len(params.Command[2:]))
+	for i, item := range params.Command[2:] {
+		if exists && bf.Exists(item) {
+			res[i] = 1
+		}
+	}
+	if strings.EqualFold(params.Command[0], "bf.exists") {
+		return []byte(fmt.Sprintf(":%d\r\n", res[0])), nil
+	}
+	return encodeIntegers(res), nil
+}
+
+func handleBFCARD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	bf, exists, err := getValue[*BloomFilter](params, keys.ReadKeys[0], "bloom filter")
+	if err != nil || !exists {
+		return []byte(":0\r\n"), err
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", bf.Card())), nil
+}
+
+func handleBFINFO(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) > 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	key := keys.ReadKeys[0]
+	bf, exists, err := getValue[*BloomFilter](params, key, "bloom filter")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	var expansion interface{}
+	if bf.Expansion() != 0 {
+		expansion = bf.Expansion()
+	}
+	fields := []string{"Capacity", "Size", "Number of filters", "Number of items inserted", "Expansion rate"}
+	values := []interface{}{bf.Capacity(), bf.GetMem(), bf.Layers(), bf.Card(), expansion}
+	if len(params.Command) == 2 {
+		return encodeInfo(fields, values), nil
+	}
+
+	// A single field is returned as an array that only holds its value.
+	i := slices.Index([]string{"capacity", "size", "filters", "items", "expansion"}, strings.ToLower(params.Command[2]))
+	if i == -1 {
+		return nil, errors.New("invalid information value")
+	}
+	if values[i] == nil {
+		return []byte("*1\r\n$-1\r\n"), nil
+	}
+	return []byte(fmt.Sprintf("*1\r\n:%d\r\n", values[i])), nil
+}
+
+func handleCFRESERVE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := getCuckooOptions(append([]string{"CAPACITY", params.Command[2]}, params.Command[3:]...))
+	if err != nil {
+		return nil, err
+	}
+	if options.noCreate || options.items != nil {
+		return nil, errors.New("syntax error")
+	}
+
+	key := keys.WriteKeys[0]
+	if params.KeysExist(params.Context, []string{key})[key] {
+		return nil, fmt.Errorf("key %s already exists", key)
+	}
+
+	cf := NewCuckooFilter(options.capacity, options.bucketSize, options.maxIterations, options.expansion)
+	if err = params.SetValues(params.Context, map[string]interface{}{key: cf}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+// handleCFADD handles CF.ADD, CF.ADDNX, CF.INSERT and CF.INSERTNX. The NX variants only add items that
+// don't already exist in the filter. CF.ADD and CF.ADDNX reply with an integer, and the INSERT variants reply
+// with an array that holds 1 for each item added, 0 for each existing item and -1 when the filter is full.
+func handleCFADD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	command := strings.ToLower(params.Command[0])
+	insert := strings.HasPrefix(command, "cf.insert")
+	options, err := getCuckooOptions(nil)
+	if insert {
+		if options, err = getCuckooOptions(params.Command[2:]); err != nil {
+			return nil, err
+		}
+		if options.items == nil {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+	} else {
+		if len(params.Command) != 3 {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+		options.items = params.Command[2:]
+	}
+
+	key := keys.WriteKeys[0]
+	cf, exists, err := getValue[*CuckooFilter](params, key, "cuckoo filter")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if options.noCreate {
+			return nil, fmt.Errorf("key %s does not exist", key)
+		}
+		cf = NewCuckooFilter(options.capacity, options.bucketSize, options.maxIterations, options.expansion)
+	}
+
+	res := make([]int, len(options.items))
+	for i, item := range options.items {
+		if strings.HasSuffix(command, "nx") && cf.Exists(item) {
+			continue
+		}
+		if err = cf.Add(item); err != nil {
+			if !insert {
+				return nil, err
+			}
+			res[i] = -1
+			continue
+		}
+		res[i] = 1
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: cf}); err != nil {
+		return nil, err
+	}
+	if !insert {
+		return []byte(fmt.Sprintf(":%d\r\n", res[0])), nil
+	}
+	return encodeIntegers(res), nil
+}
+
+// handleCFEXISTS handles CF.EXISTS, CF.MEXISTS and CF.COUNT.
+func handleCFEXISTS(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	command := strings.ToLower(params.Command[0])
+	if command != "cf.mexists" && len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	cf, exists, err := getValue[*CuckooFilter](params, keys.ReadKeys[0], "cuckoo filter")
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]int, len(params.Command[2:]))
+	for i, item := range params.Command[2:] {
+		switch {
+		case !exists:
+		case command == "cf.count":
+			res[i] = cf.Count(item)
+		case cf.Exists(item):
+			res[i] = 1
+		}
+	}
+	if command != "cf.mexists" {
+		return []byte(fmt.Sprintf(":%d\r\n", res[0])), nil
+	}
+	return encodeIntegers(res), nil
+}
+
+func handleCFDEL(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	key := keys.WriteKeys[0]
+	cf, exists, err := getValue[*CuckooFilter](params, key, "cuckoo filter")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	if !cf.Delete(params.Command[2]) {
+		return []byte(":0\r\n"), nil
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{key: cf}); err != nil {
+		return nil, err
+	}
+	return []byte(":1\r\n"), nil
+}
+
+func handleCFINFO(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	key := keys.ReadKeys[0]
+	cf, exists, err := getValue[*CuckooFilter](params, key, "cuckoo filter")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	buckets, layers, inserted, deleted := cf.Info()
+	bucketSize, maxIterations, expansion := cf.Options()
+	return encodeInfo(
+		[]string{"Size", "Number of buckets", "Number of filters", "Number of items inserted",
+			"Number of items deleted", "Bucket size", "Expansion rate", "Max iterations"},
+		[]interface{}{cf.GetMem(), buckets, layers, inserted, deleted, bucketSize, expansion, maxIterations},
+	), nil
+}
+
+// handleCMSINIT handles CMS.INITBYDIM and CMS.INITBYPROB.
+func handleCMSINIT(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	var cms *CountMinSketch
+	if strings.EqualFold(params.Command[0], "cms.initbydim") {
+		width, err := strconv.Atoi(params.Command[2])
+		if err != nil || width < 1 {
+			return nil, errors.New("invalid width")
+		}
+		depth, err := strconv.Atoi(params.Command[3])
+		if err != nil || depth < 1 {
+			return nil, errors.New("invalid depth")
+		}
+		cms = NewCountMinSketch(width, depth)
+	} else {
+		errorRate, err := strconv.ParseFloat(params.Command[2], 64)
+		if err != nil || errorRate <= 0 || errorRate >= 1 {
+			return nil, errors.New("invalid overestimation value")
+		}
+		probability, err := strconv.ParseFloat(params.Command[3], 64)
+		if err != nil || probability <= 0 || probability >= 1 {
+			return nil, errors.New("invalid prob value")
+		}
+		cms = NewCountMinSketchByProb(errorRate, probability)
+	}
+
+	key := keys.WriteKeys[0]
+	if params.KeysExist(params.Context, []string{key})[key] {
+		return nil, fmt.Errorf("key %s already exists", key)
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{key: cms}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleCMSINCRBY(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	items, increments, err := parseItemIncrements(params.Command[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	cms, exists, err := getValue[*CountMinSketch](params, key, "count-min sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	res := make([]uint64, len(items))
+	for i, item := range items {
+		res[i] = cms.IncrBy(item, increments[i])
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{key: cms}); err != nil {
+		return nil, err
+	}
+	return encodeIntegers(res), nil
+}
+
+func handleCMSQUERY(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	cms, exists, err := getValue[*CountMinSketch](params, key, "count-min sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	res := make([]uint64, len(params.Command[2:]))
+	for i, item := range params.Command[2:] {
+		res[i] = cms.Query(item)
+	}
+	return encodeIntegers(res), nil
+}
+
+func handleCMSMERGE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := cmsMergeKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := make([]uint64, len(keys.ReadKeys))
+	for i := range weights {
+		weights[i] = 1
+	}
+	if rest := params.Command[3+len(keys.ReadKeys):]; len(rest) > 0 {
+		if !strings.EqualFold(rest[0], "weights") || len(rest) != len(weights)+1 {
+			return nil, errors.New("syntax error")
+		}
+		for i, weight := range rest[1:] {
+			if weights[i], err = strconv.ParseUint(weight, 10, 64); err != nil {
+				return nil, errors.New("invalid weight")
+			}
+		}
+	}
+
+	destination := keys.WriteKeys[0]
+	cms, exists, err := getValue[*CountMinSketch](params, destination, "count-min sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", destination)
+	}
+
+	sketches := make([]*CountMinSketch, len(keys.ReadKeys))
+	for i, key := range keys.ReadKeys {
+		sketch, exists, err := getValue[*CountMinSketch](params, key, "count-min sketch")
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("key %s does not exist", key)
+		}
+		sketches[i] = sketch
+	}
+
+	if err = cms.Merge(sketches, weights); err != nil {
+		return nil, err
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{destination: cms}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleCMSINFO(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	key := keys.ReadKeys[0]
+	cms, exists, err := getValue[*CountMinSketch](params, key, "count-min sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	width, depth, count := cms.Info()
+	return encodeInfo([]string{"width", "depth", "count"}, []interface{}{width, depth, count}), nil
+}
+
+func handleTOPKRESERVE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 && len(params.Command) != 6 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	k, err := strconv.Atoi(params.Command[2])
+	if err != nil || k < 1 {
+		return nil, errors.New("invalid k")
+	}
+	width, depth, decay := DefaultTopKWidth, DefaultTopKDepth, DefaultTopKDecay
+	if len(params.Command) == 6 {
+		if width, err = strconv.Atoi(params.Command[3]); err != nil || width < 1 {
+			return nil, errors.New("invalid width")
+		}
+		if depth, err = strconv.Atoi(params.Command[4]); err != nil || depth < 1 {
+			return nil, errors.New("invalid depth")
+		}
+		if decay, err = strconv.ParseFloat(params.Command[5], 64); err != nil || decay <= 0 || decay > 1 {
+			return nil, errors.New("invalid decay value. must be '<= 1' & '> 0'")
+		}
+	}
+
+	key := keys.WriteKeys[0]
+	if params.KeysExist(params.Context, []string{key})[key] {
+		return nil, fmt.Errorf("key %s already exists", key)
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{key: NewTopK(k, width, depth, decay)}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+// handleTOPKADD handles TOPK.ADD and TOPK.INCRBY. The reply holds the item that dropped out of the top k
+// because of each increment, or nil if no item dropped out.
+func handleTOPKADD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	items := params.Command[2:]
+	increments := make([]uint64, len(items))
+	if strings.EqualFold(params.Command[0], "topk.incrby") {
+		if items, increments, err = parseItemIncrements(params.Command[2:]); err != nil {
+			return nil, err
+		}
+	} else {
+		for i := range increments {
+			increments[i] = 1
+		}
+	}
+
+	key := keys.WriteKeys[0]
+	topK, exists, err := getValue[*TopK](params, key, "top-k sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(items))
+	for i, item := range items {
+		if expelled, ok := topK.IncrBy(item, increments[i]); ok {
+			res += fmt.Sprintf("$%d\r\n%s\r\n", len(expelled), expelled)
+			continue
+		}
+		res += "$-1\r\n"
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: topK}); err != nil {
+		return nil, err
+	}
+	return []byte(res), nil
+}
+
+// handleTOPKQUERY handles TOPK.QUERY and TOPK.COUNT.
+func handleTOPKQUERY(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	topK, exists, err := getValue[*TopK](params, key, "top-k sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	res := make([]uint64, len(params.Command[2:]))
+	for i, item := range params.Command[2:] {
+		if strings.EqualFold(params.Command[0], "topk.count") {
+			res[i] = topK.Count(item)
+		} else if topK.Query(item) {
+			res[i] = 1
+		}
+	}
+	return encodeIntegers(res), nil
+}
+
+func handleTOPKLIST(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	withCount := len(params.Command) == 3 && strings.EqualFold(params.Command[2], "withcount")
+	if len(params.Command) > 3 || (len(params.Command) == 3 && !withCount) {
+		return nil, errors.New("syntax error")
+	}
+
+	key := keys.ReadKeys[0]
+	topK, exists, err := getValue[*TopK](params, key, "top-k sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	items := topK.List()
+	if !withCount {
+		res := fmt.Sprintf("*%d\r\n", len(items))
+		for _, item := range items {
+			res += fmt.Sprintf("$%d\r\n%s\r\n", len(item.Item), item.Item)
+		}
+		return []byte(res), nil
+	}
+	res := fmt.Sprintf("*%d\r\n", len(items)*2)
+	for _, item := range items {
+		res += fmt.Sprintf("$%d\r\n%s\r\n:%d\r\n", len(item.Item), item.Item, item.Count)
+	}
+	return []byte(res), nil
+}
+
+func handleTOPKINFO(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	key := keys.ReadKeys[0]
+	topK, exists, err := getValue[*TopK](params, key, "top-k sketch")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	k, width, depth, decay := topK.Info()
+	return encodeInfo(
+		[]string{"k", "width", "depth", "decay"},
+		[]interface{}{k, width, depth, strconv.FormatFloat(decay, 'f', -1, 64)},
+	), nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "bf.reserve",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(BF.RESERVE key error_rate capacity [EXPANSION expansion] [NONSCALING])
+Creates an empty Bloom filter at key with the given false positive rate and initial capacity.
+When the filter fills up, a new sub-filter expansion times larger is stacked on top of it,
+unless NONSCALING is passed.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleBFRESERVE,
+		},
+		{
+			Command:    "bf.add",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(BF.ADD key item)
+Adds the item to the Bloom filter at key, creating the filter with default options if it doesn't exist.
+Returns 1 if the item was added, or 0 if it may already exist.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleBFADD,
+		},
+		{
+			Command:    "bf.madd",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(BF.MADD key item [item ...])
+Adds the items to the Bloom filter at key, creating the filter with default options if it doesn't exist.
+Returns an array with 1 for each item added, or 0 for each item that may already exist.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleBFADD,
+		},
+		{
+			Command:    "bf.insert",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(BF.INSERT key [CAPACITY capacity] [ERROR error] [EXPANSION expansion] [NOCREATE] [NONSCALING] ITEMS item [item ...])
+Adds the items to the Bloom filter at key. If the key doesn't exist, the filter is created with the given options
+unless NOCREATE is passed. Returns an array with 1 for each item added, or 0 for each item that may already exist.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleBFADD,
+		},
+		{
+			Command:    "bf.exists",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(BF.EXISTS key item)
+Returns 1 if the item may exist in the Bloom filter at key, or 0 if it definitely doesn't.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleBFEXISTS,
+		},
+		{
+			Command:    "bf.mexists",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(BF.MEXISTS key item [item ...])
+Returns an array with 1 for each item that may exist in the Bloom filter at key, and 0 for each item that doesn't.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleBFEXISTS,
+		},
+		{
+			Command:    "bf.card",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(BF.CARD key)
+Returns the number of items added to the Bloom filter at key, or 0 if the key doesn't exist.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleBFCARD,
+		},
+		{
+			Command:    "bf.info",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.BloomCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(BF.INFO key [CAPACITY | SIZE | FILTERS | ITEMS | EXPANSION])
+Returns information about the Bloom filter at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleBFINFO,
+		},
+		{
+			Command:    "cf.reserve",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CF.RESERVE key capacity [BUCKETSIZE bucketsize] [MAXITERATIONS maxiterations] [EXPANSION expansion])
+Creates an empty cuckoo filter at key with the given initial capacity.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleCFRESERVE,
+		},
+		{
+			Command:    "cf.add",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CF.ADD key item)
+Adds the item to the cuckoo filter at key, creating the filter with default options if it doesn't exist.
+Returns 1 when the item is added.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleCFADD,
+		},
+		{
+			Command:    "cf.addnx",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CF.ADDNX key item)
+Adds the item to the cuckoo filter at key only if it doesn't already exist in the filter.
+Returns 1 if the item was added, or 0 if it may already exist.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleCFADD,
+		},
+		{
+			Command:    "cf.insert",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CF.INSERT key [CAPACITY capacity] [NOCREATE] ITEMS item [item ...])
+Adds the items to the cuckoo filter at key. If the key doesn't exist, the filter is created with the given capacity
+unless NOCREATE is passed. Returns an array with 1 for each item added, or -1 if the filter is full.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleCFADD,
+		},
+		{
+			Command:    "cf.insertnx",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CF.INSERTNX key [CAPACITY capacity] [NOCREATE] ITEMS item [item ...])
+Adds the items that don't already exist to the cuckoo filter at key. Returns an array with 1 for each item added,
+0 for each item that may already exist, or -1 if the filter is full.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleCFADD,
+		},
+		{
+			Command:    "cf.exists",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(CF.EXISTS key item)
+Returns 1 if the item may exist in the cuckoo filter at key, or 0 if it definitely doesn't.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleCFEXISTS,
+		},
+		{
+			Command:    "cf.mexists",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(CF.MEXISTS key item [item ...])
+Returns an array with 1 for each item that may exist in the cuckoo filter at key, and 0 for each item that doesn't.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleCFEXISTS,
+		},
+		{
+			Command:    "cf.count",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(CF.COUNT key item)
+Returns an estimate of the number of times the item was added to the cuckoo filter at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleCFEXISTS,
+		},
+		{
+			Command:    "cf.del",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CF.DEL key item)
+Deletes one occurrence of the item from the cuckoo filter at key. Returns 1 if the item was deleted, otherwise 0.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleCFDEL,
+		},
+		{
+			Command:    "cf.info",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CuckooCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(CF.INFO key)
+Returns information about the cuckoo filter at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleCFINFO,
+		},
+		{
+			Command:    "cms.initbydim",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CMSCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CMS.INITBYDIM key width depth)
+Creates a count-min sketch at key with the given number of counters per row and number of rows.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleCMSINIT,
+		},
+		{
+			Command:    "cms.initbyprob",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CMSCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CMS.INITBYPROB key error probability)
+Creates a count-min sketch at key sized so that counts are overestimated by at most error times the total count,
+with the given probability of exceeding that bound.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleCMSINIT,
+		},
+		{
+			Command:    "cms.incrby",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CMSCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(CMS.INCRBY key item increment [item increment ...])
+Increases the count of each item in the count-min sketch at key. Returns an array of the updated counts.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleCMSINCRBY,
+		},
+		{
+			Command:    "cms.query",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CMSCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(CMS.QUERY key item [item ...])
+Returns an array of the estimated counts of the items in the count-min sketch at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleCMSQUERY,
+		},
+		{
+			Command:    "cms.merge",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CMSCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(CMS.MERGE destination numkeys source [source ...] [WEIGHTS weight [weight ...]])
+Merges the count-min sketches at the source keys into the sketch at destination, multiplying each source by its weight.
+All the sketches must have the same width and depth.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: cmsMergeKeyFunc,
+			HandlerFunc:       handleCMSMERGE,
+		},
+		{
+			Command:    "cms.info",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.CMSCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(CMS.INFO key)
+Returns the width, depth and total count of the count-min sketch at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleCMSINFO,
+		},
+		{
+			Command:    "topk.reserve",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.TopKCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(TOPK.RESERVE key topk [width depth decay])
+Creates a top-k sketch at key that tracks the k most frequent items.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleTOPKRESERVE,
+		},
+		{
+			Command:    "topk.add",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.TopKCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(TOPK.ADD key item [item ...])
+Adds the items to the top-k sketch at key. Returns an array with the item that dropped out of the top k
+because of each addition, or nil if no item dropped out.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleTOPKADD,
+		},
+		{
+			Command:    "topk.incrby",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.TopKCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(TOPK.INCRBY key item increment [item increment ...])
+Increases the count of each item in the top-k sketch at key. Returns an array with the item that dropped out
+of the top k because of each increment, or nil if no item dropped out.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleTOPKADD,
+		},
+		{
+			Command:    "topk.query",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.TopKCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(TOPK.QUERY key item [item ...])
+Returns an array with 1 for each item that is in the top k of the sketch at key, otherwise 0.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleTOPKQUERY,
+		},
+		{
+			Command:    "topk.count",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.TopKCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(TOPK.COUNT key item [item ...])
+Returns an array of the estimated counts of the items in the top-k sketch at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleTOPKQUERY,
+		},
+		{
+			Command:    "topk.list",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.TopKCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TOPK.LIST key [WITHCOUNT])
+Returns the items in the top-k sketch at key, ordered by count from highest to lowest.
+WITHCOUNT includes the estimated count after each item.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleTOPKLIST,
+		},
+		{
+			Command:    "topk.info",
+			Module:     constants.ProbabilisticModule,
+			Categories: []string{constants.TopKCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(TOPK.INFO key)
+Returns the k, width, depth and decay of the top-k sketch at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleTOPKINFO,
+		},
+	}
+}
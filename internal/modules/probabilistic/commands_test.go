@@ -0,0 +1,396 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilistic_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+func Test_Probabilistic(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	// command is a command and its expected response. Array responses are compared element by element as
+	// strings, with nil elements compared as empty strings.
+	type command struct {
+		command          []string
+		expectedResponse interface{}
+		expectedError    error
+	}
+
+	runCommands := func(t *testing.T, client *resp.Conn, commands []command) {
+		for _, c := range commands {
+			cmd := make([]resp.Value, len(c.command))
+			for i, arg := range c.command {
+				cmd[i] = resp.StringValue(arg)
+			}
+			if err := client.WriteArray(cmd); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if c.expectedError != nil {
+				if res.Error() == nil || !strings.Contains(res.Error().Error(), c.expectedError.Error()) {
+					t.Errorf("%v: expected error \"%s\", got \"%v\"", c.command, c.expectedError.Error(), res)
+				}
+				continue
+			}
+			switch expected := c.expectedResponse.(type) {
+			case int:
+				if res.Integer() != expected {
+					t.Errorf("%v: expected response %d, got \"%v\"", c.command, expected, res)
+				}
+			case string:
+				if !strings.EqualFold(res.String(), expected) {
+					t.Errorf("%v: expected response \"%s\", got \"%s\"", c.command, expected, res.String())
+				}
+			case []string:
+				got := make([]string, len(res.Array()))
+				for i, value := range res.Array() {
+					got[i] = value.String()
+				}
+				if strings.Join(got, ",") != strings.Join(expected, ",") {
+					t.Errorf("%v: expected response %v, got %v", c.command, expected, got)
+				}
+			}
+		}
+	}
+
+	runTests := func(t *testing.T, tests []struct {
+		name     string
+		commands []command
+	}) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		for _, test := range tests {
+			t.Log(test.name)
+			runCommands(t, client, test.commands)
+		}
+	}
+
+	t.Run("Test_HandleBloomFilter", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. BF.ADD creates a filter with the default options",
+				commands: []command{
+					{command: []string{"BF.ADD", "BfKey1", "a"}, expectedResponse: 1},
+					{command: []string{"BF.ADD", "BfKey1", "a"}, expectedResponse: 0},
+					{command: []string{"BF.EXISTS", "BfKey1", "a"}, expectedResponse: 1},
+					{command: []string{"BF.EXISTS", "BfKey1", "b"}, expectedResponse: 0},
+					{command: []string{"BF.CARD", "BfKey1"}, expectedResponse: 1},
+					{command: []string{"BF.INFO", "BfKey1", "CAPACITY"}, expectedResponse: []string{"100"}},
+					{command: []string{"BF.INFO", "BfKey1", "EXPANSION"}, expectedResponse: []string{"2"}},
+				},
+			},
+			{
+				name: "2. BF.MADD and BF.MEXISTS handle multiple items",
+				commands: []command{
+					{command: []string{"BF.MADD", "BfKey2", "a", "b", "a"}, expectedResponse: []string{"1", "1", "0"}},
+					{command: []string{"BF.MEXISTS", "BfKey2", "a", "b", "c"}, expectedResponse: []string{"1", "1", "0"}},
+					{command: []string{"BF.MEXISTS", "BfKey2NonExistent", "a"}, expectedResponse: []string{"0"}},
+					{command: []string{"BF.CARD", "BfKey2NonExistent"}, expectedResponse: 0},
+				},
+			},
+			{
+				name: "3. BF.RESERVE creates an empty filter and fails on an existing key",
+				commands: []command{
+					{command: []string{"BF.RESERVE", "BfKey3", "0.001", "1000", "EXPANSION", "4"}, expectedResponse: "OK"},
+					{command: []string{"BF.INFO", "BfKey3", "CAPACITY"}, expectedResponse: []string{"1000"}},
+					{command: []string{"BF.INFO", "BfKey3", "ITEMS"}, expectedResponse: []string{"0"}},
+					{command: []string{"BF.INFO", "BfKey3", "EXPANSION"}, expectedResponse: []string{"4"}},
+					{command: []string{"BF.RESERVE", "BfKey3", "0.01", "100"}, expectedError: errors.New("key BfKey3 already exists")},
+					{command: []string{"BF.RESERVE", "BfKey3a", "1.5", "100"}, expectedError: errors.New("(0 < error rate range < 1)")},
+					{command: []string{"BF.RESERVE", "BfKey3a", "0.01", "0"}, expectedError: errors.New("bad capacity")},
+					{command: []string{"BF.RESERVE", "BfKey3a", "0.01", "100", "NONSCALING", "EXPANSION", "2"},
+						expectedError: errors.New("nonscaling filters cannot expand")},
+				},
+			},
+			{
+				name: "4. A scaling filter stacks sub-filters when it fills up",
+				commands: []command{
+					{command: []string{"BF.RESERVE", "BfKey4", "0.01", "2"}, expectedResponse: "OK"},
+					{command: []string{"BF.MADD", "BfKey4", "a", "b", "c", "d", "e"}, expectedResponse: []string{"1", "1", "1", "1", "1"}},
+					{command: []string{"BF.INFO", "BfKey4", "FILTERS"}, expectedResponse: []string{"2"}},
+					{command: []string{"BF.INFO", "BfKey4", "CAPACITY"}, expectedResponse: []string{"6"}},
+					{command: []string{"BF.CARD", "BfKey4"}, expectedResponse: 5},
+				},
+			},
+			{
+				name: "5. A non-scaling filter rejects items once it's full",
+				commands: []command{
+					{command: []string{"BF.RESERVE", "BfKey5", "0.01", "2", "NONSCALING"}, expectedResponse: "OK"},
+					{command: []string{"BF.ADD", "BfKey5", "a"}, expectedResponse: 1},
+					{command: []string{"BF.ADD", "BfKey5", "b"}, expectedResponse: 1},
+					{command: []string{"BF.ADD", "BfKey5", "c"}, expectedError: errors.New("non scaling filter is full")},
+					{command: []string{"BF.INFO", "BfKey5", "EXPANSION"}, expectedResponse: []string{""}},
+				},
+			},
+			{
+				name: "6. BF.INSERT applies the options when creating the filter",
+				commands: []command{
+					{command: []string{"BF.INSERT", "BfKey6", "NOCREATE", "ITEMS", "a"}, expectedError: errors.New("key BfKey6 does not exist")},
+					{command: []string{"BF.INSERT", "BfKey6", "CAPACITY", "50", "ERROR", "0.001", "ITEMS", "a", "b"},
+						expectedResponse: []string{"1", "1"}},
+					{command: []string{"BF.INFO", "BfKey6", "CAPACITY"}, expectedResponse: []string{"50"}},
+					{command: []string{"BF.INSERT", "BfKey6", "NOCREATE", "ITEMS", "b", "c"}, expectedResponse: []string{"0", "1"}},
+					{command: []string{"BF.INSERT", "BfKey6", "CAPACITY", "50"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+			{
+				name: "7. Bloom commands fail on a value of another type",
+				commands: []command{
+					{command: []string{"SET", "BfKey7", "value"}, expectedResponse: "OK"},
+					{command: []string{"BF.ADD", "BfKey7", "a"}, expectedError: errors.New("value at key BfKey7 is not a bloom filter")},
+					{command: []string{"BF.INFO", "BfKey7NonExistent"}, expectedError: errors.New("key BfKey7NonExistent does not exist")},
+					{command: []string{"BF.ADD", "BfKey7"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleCuckooFilter", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. CF.ADD creates a filter and allows duplicate items",
+				commands: []command{
+					{command: []string{"CF.ADD", "CfKey1", "a"}, expectedResponse: 1},
+					{command: []string{"CF.ADD", "CfKey1", "a"}, expectedResponse: 1},
+					{command: []string{"CF.COUNT", "CfKey1", "a"}, expectedResponse: 2},
+					{command: []string{"CF.EXISTS", "CfKey1", "a"}, expectedResponse: 1},
+					{command: []string{"CF.EXISTS", "CfKey1", "b"}, expectedResponse: 0},
+					{command: []string{"CF.MEXISTS", "CfKey1", "a", "b"}, expectedResponse: []string{"1", "0"}},
+				},
+			},
+			{
+				name: "2. CF.ADDNX and CF.INSERTNX only add items that don't exist",
+				commands: []command{
+					{command: []string{"CF.ADDNX", "CfKey2", "a"}, expectedResponse: 1},
+					{command: []string{"CF.ADDNX", "CfKey2", "a"}, expectedResponse: 0},
+					{command: []string{"CF.INSERTNX", "CfKey2", "ITEMS", "a", "b"}, expectedResponse: []string{"0", "1"}},
+					{command: []string{"CF.COUNT", "CfKey2", "a"}, expectedResponse: 1},
+				},
+			},
+			{
+				name: "3. CF.DEL removes one occurrence of an item",
+				commands: []command{
+					{command: []string{"CF.INSERT", "CfKey3", "ITEMS", "a", "a", "b"}, expectedResponse: []string{"1", "1", "1"}},
+					{command: []string{"CF.DEL", "CfKey3", "a"}, expectedResponse: 1},
+					{command: []string{"CF.COUNT", "CfKey3", "a"}, expectedResponse: 1},
+					{command: []string{"CF.DEL", "CfKey3", "a"}, expectedResponse: 1},
+					{command: []string{"CF.DEL", "CfKey3", "a"}, expectedResponse: 0},
+					{command: []string{"CF.EXISTS", "CfKey3", "a"}, expectedResponse: 0},
+					{command: []string{"CF.EXISTS", "CfKey3", "b"}, expectedResponse: 1},
+					{command: []string{"CF.DEL", "CfKey3NonExistent", "a"}, expectedError: errors.New("key CfKey3NonExistent does not exist")},
+				},
+			},
+			{
+				name: "4. CF.RESERVE creates an empty filter with the given options",
+				commands: []command{
+					{command: []string{"CF.RESERVE", "CfKey4", "64", "BUCKETSIZE", "4", "MAXITERATIONS", "10", "EXPANSION", "2"},
+						expectedResponse: "OK"},
+					{command: []string{"CF.RESERVE", "CfKey4", "64"}, expectedError: errors.New("key CfKey4 already exists")},
+					{command: []string{"CF.RESERVE", "CfKey4a", "64", "BUCKETSIZE", "0"}, expectedError: errors.New("bad bucketsize")},
+					{command: []string{"CF.INSERT", "CfKey4a", "NOCREATE", "ITEMS", "a"}, expectedError: errors.New("key CfKey4a does not exist")},
+				},
+			},
+			{
+				name: "5. A non-expanding filter reports full inserts",
+				commands: []command{
+					{command: []string{"CF.RESERVE", "CfKey5", "2", "BUCKETSIZE", "2", "EXPANSION", "0"}, expectedResponse: "OK"},
+					{command: []string{"CF.INSERT", "CfKey5", "ITEMS", "a", "b", "c"}, expectedResponse: []string{"1", "1", "-1"}},
+					{command: []string{"CF.ADD", "CfKey5", "a"}, expectedError: errors.New("filter is full")},
+				},
+			},
+			{
+				name: "6. Cuckoo commands fail on a value of another type",
+				commands: []command{
+					{command: []string{"SET", "CfKey6", "value"}, expectedResponse: "OK"},
+					{command: []string{"CF.ADD", "CfKey6", "a"}, expectedError: errors.New("value at key CfKey6 is not a cuckoo filter")},
+					{command: []string{"CF.INFO", "CfKey6NonExistent"}, expectedError: errors.New("key CfKey6NonExistent does not exist")},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleCountMinSketch", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. CMS.INCRBY and CMS.QUERY count items",
+				commands: []command{
+					{command: []string{"CMS.INITBYDIM", "CmsKey1", "2000", "5"}, expectedResponse: "OK"},
+					{command: []string{"CMS.INCRBY", "CmsKey1", "a", "5", "b", "3"}, expectedResponse: []string{"5", "3"}},
+					{command: []string{"CMS.INCRBY", "CmsKey1", "a", "2"}, expectedResponse: []string{"7"}},
+					{command: []string{"CMS.QUERY", "CmsKey1", "a", "b", "c"}, expectedResponse: []string{"7", "3", "0"}},
+					{command: []string{"CMS.INFO", "CmsKey1"}, expectedResponse: []string{"width", "2000", "depth", "5", "count", "10"}},
+				},
+			},
+			{
+				name: "2. CMS.INITBYPROB sizes the sketch from the error rate and probability",
+				commands: []command{
+					{command: []string{"CMS.INITBYPROB", "CmsKey2", "0.001", "0.01"}, expectedResponse: "OK"},
+					{command: []string{"CMS.INFO", "CmsKey2"}, expectedResponse: []string{"width", "2000", "depth", "7", "count", "0"}},
+					{command: []string{"CMS.INITBYPROB", "CmsKey2", "0.001", "0.01"}, expectedError: errors.New("key CmsKey2 already exists")},
+					{command: []string{"CMS.INITBYPROB", "CmsKey2a", "2", "0.01"}, expectedError: errors.New("invalid overestimation value")},
+					{command: []string{"CMS.INITBYDIM", "CmsKey2a", "0", "5"}, expectedError: errors.New("invalid width")},
+				},
+			},
+			{
+				name: "3. CMS.MERGE merges the weighted sources into the destination",
+				commands: []command{
+					{command: []string{"CMS.INITBYDIM", "CmsKey3a", "1000", "5"}, expectedResponse: "OK"},
+					{command: []string{"CMS.INITBYDIM", "CmsKey3b", "1000", "5"}, expectedResponse: "OK"},
+					{command: []string{"CMS.INITBYDIM", "CmsKey3c", "1000", "5"}, expectedResponse: "OK"},
+					{command: []string{"CMS.INCRBY", "CmsKey3a", "a", "2"}, expectedResponse: []string{"2"}},
+					{command: []string{"CMS.INCRBY", "CmsKey3b", "a", "1", "b", "4"}, expectedResponse: []string{"1", "4"}},
+					{command: []string{"CMS.MERGE", "CmsKey3c", "2", "CmsKey3a", "CmsKey3b", "WEIGHTS", "3", "1"}, expectedResponse: "OK"},
+					{command: []string{"CMS.QUERY", "CmsKey3c", "a", "b"}, expectedResponse: []string{"7", "4"}},
+					{command: []string{"CMS.MERGE", "CmsKey3c", "2", "CmsKey3a", "CmsKey3b", "WEIGHTS", "3"},
+						expectedError: errors.New("syntax error")},
+					{command: []string{"CMS.MERGE", "CmsKey3d", "1", "CmsKey3a"}, expectedError: errors.New("key CmsKey3d does not exist")},
+					{command: []string{"CMS.INITBYDIM", "CmsKey3e", "10", "5"}, expectedResponse: "OK"},
+					{command: []string{"CMS.MERGE", "CmsKey3e", "1", "CmsKey3a"}, expectedError: errors.New("width/depth is not equal")},
+				},
+			},
+			{
+				name: "4. CMS commands fail on a missing key or a value of another type",
+				commands: []command{
+					{command: []string{"CMS.INCRBY", "CmsKey4", "a", "1"}, expectedError: errors.New("key CmsKey4 does not exist")},
+					{command: []string{"SET", "CmsKey4", "value"}, expectedResponse: "OK"},
+					{command: []string{"CMS.QUERY", "CmsKey4", "a"}, expectedError: errors.New("value at key CmsKey4 is not a count-min sketch")},
+					{command: []string{"CMS.INCRBY", "CmsKey4", "a"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleTopK", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. TOPK.ADD tracks the most frequent items",
+				commands: []command{
+					{command: []string{"TOPK.RESERVE", "TopkKey1", "2", "50", "7", "0.9"}, expectedResponse: "OK"},
+					{command: []string{"TOPK.ADD", "TopkKey1", "a", "b", "a"}, expectedResponse: []string{"", "", ""}},
+					{command: []string{"TOPK.QUERY", "TopkKey1", "a", "b", "c"}, expectedResponse: []string{"1", "1", "0"}},
+					{command: []string{"TOPK.INCRBY", "TopkKey1", "c", "5"}, expectedResponse: []string{"b"}},
+					{command: []string{"TOPK.LIST", "TopkKey1"}, expectedResponse: []string{"c", "a"}},
+					{command: []string{"TOPK.LIST", "TopkKey1", "WITHCOUNT"}, expectedResponse: []string{"c", "5", "a", "2"}},
+					{command: []string{"TOPK.COUNT", "TopkKey1", "a", "b", "c"}, expectedResponse: []string{"2", "1", "5"}},
+				},
+			},
+			{
+				name: "2. TOPK.RESERVE uses the default width, depth and decay",
+				commands: []command{
+					{command: []string{"TOPK.RESERVE", "TopkKey2", "10"}, expectedResponse: "OK"},
+					{command: []string{"TOPK.INFO", "TopkKey2"}, expectedResponse: []string{"k", "10", "width", "8", "depth", "7", "decay", "0.9"}},
+					{command: []string{"TOPK.RESERVE", "TopkKey2", "10"}, expectedError: errors.New("key TopkKey2 already exists")},
+					{command: []string{"TOPK.RESERVE", "TopkKey2a", "0"}, expectedError: errors.New("invalid k")},
+					{command: []string{"TOPK.RESERVE", "TopkKey2a", "10", "8", "7", "2"},
+						expectedError: errors.New("invalid decay value. must be '<= 1' & '> 0'")},
+					{command: []string{"TOPK.RESERVE", "TopkKey2a", "10", "8"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+			{
+				name: "3. Top-k commands fail on a missing key or a value of another type",
+				commands: []command{
+					{command: []string{"TOPK.ADD", "TopkKey3", "a"}, expectedError: errors.New("key TopkKey3 does not exist")},
+					{command: []string{"SET", "TopkKey3", "value"}, expectedResponse: "OK"},
+					{command: []string{"TOPK.LIST", "TopkKey3"}, expectedError: errors.New("value at key TopkKey3 is not a top-k sketch")},
+					{command: []string{"TOPK.INCRBY", "TopkKey3", "a", "b"}, expectedError: errors.New("cannot parse increment")},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_Type", func(t *testing.T) {
+		t.Parallel()
+		commands := []command{
+			{command: []string{"BF.ADD", "TypeKey1", "a"}, expectedResponse: 1},
+			{command: []string{"CF.ADD", "TypeKey2", "a"}, expectedResponse: 1},
+			{command: []string{"CMS.INITBYDIM", "TypeKey3", "10", "2"}, expectedResponse: "OK"},
+			{command: []string{"TOPK.RESERVE", "TypeKey4", "2"}, expectedResponse: "OK"},
+		}
+		for i, name := range []string{"MBbloom--", "MBbloomCF", "CMSk-TYPE", "TopK-TYPE"} {
+			commands = append(commands, command{
+				command:          []string{"TYPE", fmt.Sprintf("TypeKey%d", i+1)},
+				expectedResponse: name,
+			})
+		}
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{{name: "1. TYPE returns the name of each probabilistic type", commands: commands}})
+	})
+}
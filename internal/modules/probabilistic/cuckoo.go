@@ -0,0 +1,281 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilistic
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"slices"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+const (
+	DefaultCuckooCapacity      = 1024
+	DefaultCuckooBucketSize    = 2
+	DefaultCuckooMaxIterations = 20
+	DefaultCuckooExpansion     = 1
+)
+
+var ErrCuckooFull = errors.New("filter is full")
+
+// cuckooLayer is a table of buckets that each hold a fixed number of 8-bit fingerprints. A fingerprint of 0
+// marks an empty slot. The number of buckets is a power of 2, so that the alternate bucket of a fingerprint can be
+// found from either of its buckets.
+type cuckooLayer struct {
+	slots      []uint8
+	numBuckets uint64
+}
+
+func newCuckooLayer(capacity int, bucketSize int) *cuckooLayer {
+	numBuckets := uint64(1)
+	if n := (capacity + bucketSize - 1) / bucketSize; n > 1 {
+		numBuckets = 1 << bits.Len64(uint64(n-1))
+	}
+	return &cuckooLayer{
+		slots:      make([]uint8, numBuckets*uint64(bucketSize)),
+		numBuckets: numBuckets,
+	}
+}
+
+func (layer *cuckooLayer) altIndex(index uint64, fp uint8) uint64 {
+	return (index ^ (uint64(fp) * 0x5bd1e995)) & (layer.numBuckets - 1)
+}
+
+// CuckooFilter tells whether an item may have been added to it, like a Bloom filter, but also supports deleting
+// items and counting how many times an item was added.
+//
+// When the buckets of a new item are full, a fingerprint is moved to its alternate bucket to make room, up to
+// maxIterations times. If that fails, a scaling filter adds a new layer, expansion times larger than the last one.
+type CuckooFilter struct {
+	bucketSize    int
+	maxIterations int
+	expansion     int // 0 when the filter doesn't scale
+	capacity      int // Capacity of the first layer
+	count         int
+	deleted       int
+	layers        []*cuckooLayer
+	kicks         uint64 // Chooses the slot a fingerprint is evicted from, so that evictions don't cycle
+}
+
+func NewCuckooFilter(capacity int, bucketSize int, maxIterations int, expansion int) *CuckooFilter {
+	return &CuckooFilter{
+		bucketSize:    bucketSize,
+		maxIterations: maxIterations,
+		expansion:     expansion,
+		capacity:      capacity,
+		layers:        []*cuckooLayer{newCuckooLayer(capacity, bucketSize)},
+	}
+}
+
+func (cf *CuckooFilter) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*cf))
+	for _, layer := range cf.layers {
+		size += int64(unsafe.Sizeof(*layer))
+		size += int64(cap(layer.slots))
+	}
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*CuckooFilter)(nil)
+
+// fingerprint returns the fingerprint of the item and its first bucket index, before it's reduced to the number of
+// buckets of a layer.
+func fingerprint(item string) (uint8, uint64) {
+	h, _ := hashItem(item)
+	return uint8(h>>56%255 + 1), h
+}
+
+func (cf *CuckooFilter) bucket(layer *cuckooLayer, index uint64) []uint8 {
+	start := index * uint64(cf.bucketSize)
+	return layer.slots[start : start+uint64(cf.bucketSize)]
+}
+
+// Add adds the item, even if it was already added.
+func (cf *CuckooFilter) Add(item string) error {
+	fp, h := fingerprint(item)
+
+	// Use a free slot in any layer before evicting fingerprints.
+	for _, layer := range cf.layers {
+		i1 := h & (layer.numBuckets - 1)
+		for _, index := range []uint64{i1, layer.altIndex(i1, fp)} {
+			if slot := slices.Index(cf.bucket(layer, index), 0); slot != -1 {
+				cf.bucket(layer, index)[slot] = fp
+				cf.count++
+				return nil
+			}
+		}
+	}
+
+	if cf.evict(cf.layers[len(cf.layers)-1], h&(cf.layers[len(cf.layers)-1].numBuckets-1), fp) {
+		cf.count++
+		return nil
+	}
+
+	if cf.expansion == 0 {
+		return ErrCuckooFull
+	}
+	capacity := cf.capacity
+	for range cf.layers {
+		capacity *= cf.expansion
+	}
+	layer := newCuckooLayer(capacity, cf.bucketSize)
+	cf.layers = append(cf.layers, layer)
+	bucket := cf.bucket(layer, h&(layer.numBuckets-1))
+	bucket[0] = fp
+	cf.count++
+	return nil
+}
+
+// evict inserts the fingerprint by repeatedly moving a fingerprint out of a full bucket to its alternate bucket.
+// If no free slot is found within maxIterations moves, the moves are undone and false is returned.
+func (cf *CuckooFilter) evict(layer *cuckooLayer, index uint64, fp uint8) bool {
+	type move struct {
+		index uint64
+		slot  int
+	}
+	moves := make([]move, 0, cf.maxIterations)
+	for i := 0; i < cf.maxIterations; i++ {
+		bucket := cf.bucket(layer, index)
+		if slot := slices.Index(bucket, 0); slot != -1 {
+			bucket[slot] = fp
+			return true
+		}
+		cf.kicks++
+		slot := int(cf.kicks % uint64(cf.bucketSize))
+		fp, bucket[slot] = bucket[slot], fp
+		moves = append(moves, move{index: index, slot: slot})
+		index = layer.altIndex(index, fp)
+	}
+	for i := len(moves) - 1; i >= 0; i-- {
+		bucket := cf.bucket(layer, moves[i].index)
+		fp, bucket[moves[i].slot] = bucket[moves[i].slot], fp
+	}
+	return false
+}
+
+// Exists returns true if the item may have been added, and false if it definitely wasn't.
+func (cf *CuckooFilter) Exists(item string) bool {
+	return cf.Count(item) > 0
+}
+
+// Count returns the number of times the item may have been added. It can overestimate, but never underestimates.
+func (cf *CuckooFilter) Count(item string) int {
+	fp, h := fingerprint(item)
+	count := 0
+	for _, layer := range cf.layers {
+		i1 := h & (layer.numBuckets - 1)
+		i2 := layer.altIndex(i1, fp)
+		for _, slot := range cf.bucket(layer, i1) {
+			if slot == fp {
+				count++
+			}
+		}
+		if i2 == i1 {
+			continue
+		}
+		for _, slot := range cf.bucket(layer, i2) {
+			if slot == fp {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Delete deletes one occurrence of the item and returns false if the item wasn't found.
+// Only items that were added should be deleted, as deleting another item with the same fingerprint
+// causes a false negative for the item that was added.
+func (cf *CuckooFilter) Delete(item string) bool {
+	fp, h := fingerprint(item)
+	for i := len(cf.layers) - 1; i >= 0; i-- {
+		layer := cf.layers[i]
+		i1 := h & (layer.numBuckets - 1)
+		for _, index := range []uint64{i1, layer.altIndex(i1, fp)} {
+			bucket := cf.bucket(layer, index)
+			if slot := slices.Index(bucket, fp); slot != -1 {
+				bucket[slot] = 0
+				cf.count--
+				cf.deleted++
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Info returns the number of buckets, layers, inserted items and deleted items of the filter.
+func (cf *CuckooFilter) Info() (buckets int, layers int, inserted int, deleted int) {
+	for _, layer := range cf.layers {
+		buckets += int(layer.numBuckets)
+	}
+	return buckets, len(cf.layers), cf.count, cf.deleted
+}
+
+// Options returns the bucket size, maximum number of evictions and expansion of the filter.
+func (cf *CuckooFilter) Options() (bucketSize int, maxIterations int, expansion int) {
+	return cf.bucketSize, cf.maxIterations, cf.expansion
+}
+
+func (cf *CuckooFilter) MarshalBinary() ([]byte, error) {
+	b := binary.AppendUvarint(nil, uint64(cf.bucketSize))
+	b = binary.AppendUvarint(b, uint64(cf.maxIterations))
+	b = binary.AppendUvarint(b, uint64(cf.expansion))
+	b = binary.AppendUvarint(b, uint64(cf.capacity))
+	b = binary.AppendUvarint(b, uint64(cf.count))
+	b = binary.AppendUvarint(b, uint64(cf.deleted))
+	b = binary.AppendUvarint(b, cf.kicks)
+	b = binary.AppendUvarint(b, uint64(len(cf.layers)))
+	for _, layer := range cf.layers {
+		b = binary.AppendUvarint(b, layer.numBuckets)
+		b = append(b, layer.slots...)
+	}
+	return b, nil
+}
+
+func (cf *CuckooFilter) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	filter := CuckooFilter{
+		bucketSize:    int(r.Uvarint()),
+		maxIterations: int(r.Uvarint()),
+		expansion:     int(r.Uvarint()),
+		capacity:      int(r.Uvarint()),
+		count:         int(r.Uvarint()),
+		deleted:       int(r.Uvarint()),
+		kicks:         r.Uvarint(),
+	}
+	n := r.Count()
+	for i := 0; i < n && r.Err() == nil; i++ {
+		numBuckets := r.Uvarint()
+		if numBuckets == 0 || numBuckets&(numBuckets-1) != 0 || numBuckets > uint64(r.Len()) {
+			return errors.New("invalid cuckoo filter layer")
+		}
+		slots := r.Bytes(int(numBuckets) * filter.bucketSize)
+		filter.layers = append(filter.layers, &cuckooLayer{slots: slices.Clone(slots), numBuckets: numBuckets})
+	}
+	if r.Err() != nil {
+		return r.Err()
+	}
+	if len(filter.layers) == 0 || filter.bucketSize == 0 {
+		return errors.New("invalid cuckoo filter")
+	}
+	*cf = filter
+	return nil
+}
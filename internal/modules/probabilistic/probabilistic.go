@@ -0,0 +1,68 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probabilistic implements Bloom filters, cuckoo filters, count-min sketches and top-k sketches.
+// The structures answer membership, frequency and ranking queries about a stream of items in a fraction of the
+// memory an exact answer would need, at the cost of a bounded error.
+package probabilistic
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// hashItem returns two independent 64-bit hashes of the item. The i-th hash function of a structure is derived
+// from them as h1 + i*h2, which is as accurate as using i independent hash functions.
+//
+// The high bits of FNV-1a barely change between short items, so the hash is mixed before its bits are used
+// for fingerprints.
+func hashItem(item string) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(item))
+	h1 := mix64(h.Sum64())
+	return h1, mix64(h1) | 1
+}
+
+// mix64 is the finalizer of SplitMix64. It spreads the bits of x over the whole word.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func appendWords(b []byte, words []uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(len(words)))
+	for _, word := range words {
+		b = binary.BigEndian.AppendUint64(b, word)
+	}
+	return b
+}
+
+func readWords(r *internal.BinaryReader) []uint64 {
+	n := r.Count()
+	data := r.Bytes(n * 8)
+	if r.Err() != nil {
+		return nil
+	}
+	words := make([]uint64, n)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(data[i*8:])
+	}
+	return words
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilistic
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+const (
+	DefaultTopKWidth = 8
+	DefaultTopKDepth = 7
+	DefaultTopKDecay = 0.9
+)
+
+type topKBucket struct {
+	fingerprint uint32
+	count       uint64
+}
+
+type TopKItem struct {
+	Item  string
+	Count uint64
+}
+
+// TopK keeps track of the k items counted the most times, using the HeavyKeeper algorithm.
+//
+// The sketch has depth rows of width buckets, and each bucket holds the fingerprint and count of one item.
+// When an item hashes to a bucket held by another item, the count of the bucket decays with a probability that
+// falls exponentially with the count, so the buckets end up held by the frequent items. The k items with the
+// largest counts are kept in a list along with their estimated counts.
+type TopK struct {
+	k       int
+	width   int
+	depth   int
+	decay   float64
+	buckets []topKBucket
+	items   []TopKItem
+}
+
+func NewTopK(k int, width int, depth int, decay float64) *TopK {
+	return &TopK{
+		k:       k,
+		width:   width,
+		depth:   depth,
+		decay:   decay,
+		buckets: make([]topKBucket, width*depth),
+		items:   make([]TopKItem, 0, k),
+	}
+}
+
+func (topK *TopK) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*topK))
+	size += int64(cap(topK.buckets)) * int64(unsafe.Sizeof(topKBucket{}))
+	size += int64(cap(topK.items)) * int64(unsafe.Sizeof(TopKItem{}))
+	for _, item := range topK.items {
+		size += int64(len(item.Item))
+	}
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*TopK)(nil)
+
+// IncrBy increments the count of the item. If the item enters the top k and another item drops out of it,
+// the item that dropped out is returned along with true.
+func (topK *TopK) IncrBy(item string, increment uint64) (string, bool) {
+	h1, h2 := hashItem(item)
+	fingerprint := uint32(h1 >> 32)
+
+	var count uint64
+	for i := 0; i < topK.depth; i++ {
+		bucket := &topK.buckets[i*topK.width+int((h1+uint64(i)*h2)%uint64(topK.width))]
+		switch {
+		case bucket.count == 0:
+			bucket.fingerprint, bucket.count = fingerprint, increment
+		case bucket.fingerprint == fingerprint:
+			bucket.count += increment
+		default:
+			for n := increment; n > 0; n-- {
+				if rand.Float64() >= math.Pow(topK.decay, float64(bucket.count)) {
+					continue
+				}
+				if bucket.count--; bucket.count == 0 {
+					bucket.fingerprint, bucket.count = fingerprint, n
+					break
+				}
+			}
+		}
+		if bucket.fingerprint == fingerprint {
+			count = max(count, bucket.count)
+		}
+	}
+
+	if i := slices.IndexFunc(topK.items, func(entry TopKItem) bool { return entry.Item == item }); i != -1 {
+		topK.items[i].Count = max(topK.items[i].Count, count)
+		return "", false
+	}
+	if count == 0 {
+		return "", false
+	}
+	if len(topK.items) < topK.k {
+		topK.items = append(topK.items, TopKItem{Item: item, Count: count})
+		return "", false
+	}
+	i := topK.minItem()
+	if count <= topK.items[i].Count {
+		return "", false
+	}
+	expelled := topK.items[i].Item
+	topK.items[i] = TopKItem{Item: item, Count: count}
+	return expelled, true
+}
+
+func (topK *TopK) minItem() int {
+	index := 0
+	for i, item := range topK.items {
+		if item.Count < topK.items[index].Count {
+			index = i
+		}
+	}
+	return index
+}
+
+// Query returns true if the item is in the top k.
+func (topK *TopK) Query(item string) bool {
+	return slices.ContainsFunc(topK.items, func(entry TopKItem) bool { return entry.Item == item })
+}
+
+// Count returns the estimated count of the item in the sketch.
+func (topK *TopK) Count(item string) uint64 {
+	h1, h2 := hashItem(item)
+	fingerprint := uint32(h1 >> 32)
+	var count uint64
+	for i := 0; i < topK.depth; i++ {
+		bucket := topK.buckets[i*topK.width+int((h1+uint64(i)*h2)%uint64(topK.width))]
+		if bucket.fingerprint == fingerprint {
+			count = max(count, bucket.count)
+		}
+	}
+	return count
+}
+
+// List returns the top k items ordered from the largest to the smallest count.
+func (topK *TopK) List() []TopKItem {
+	items := slices.Clone(topK.items)
+	slices.SortFunc(items, func(a, b TopKItem) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Item, b.Item)
+	})
+	return items
+}
+
+// Info returns the number of items kept, and the width, depth and decay of the sketch.
+func (topK *TopK) Info() (k int, width int, depth int, decay float64) {
+	return topK.k, topK.width, topK.depth, topK.decay
+}
+
+func (topK *TopK) MarshalBinary() ([]byte, error) {
+	b := binary.AppendUvarint(nil, uint64(topK.k))
+	b = binary.AppendUvarint(b, uint64(topK.width))
+	b = binary.AppendUvarint(b, uint64(topK.depth))
+	b = internal.AppendBinaryFloat(b, topK.decay)
+	for _, bucket := range topK.buckets {
+		b = binary.AppendUvarint(b, uint64(bucket.fingerprint))
+		b = binary.AppendUvarint(b, bucket.count)
+	}
+	b = binary.AppendUvarint(b, uint64(len(topK.items)))
+	for _, item := range topK.items {
+		b = internal.AppendBinaryString(b, item.Item)
+		b = binary.AppendUvarint(b, item.Count)
+	}
+	return b, nil
+}
+
+func (topK *TopK) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	k, width, depth := int(r.Uvarint()), int(r.Uvarint()), int(r.Uvarint())
+	if r.Err() == nil && (k == 0 || width == 0 || depth == 0 || width*depth > 2*r.Len()) {
+		return errors.New("invalid top-k sketch")
+	}
+	sketch := NewTopK(k, width, depth, r.Float())
+	for i := range sketch.buckets {
+		sketch.buckets[i] = topKBucket{fingerprint: uint32(r.Uvarint()), count: r.Uvarint()}
+	}
+	n := r.Count()
+	for i := 0; i < n && r.Err() == nil; i++ {
+		sketch.items = append(sketch.items, TopKItem{Item: r.String(), Count: r.Uvarint()})
+	}
+	if r.Err() != nil {
+		return r.Err()
+	}
+	*topK = *sketch
+	return nil
+}
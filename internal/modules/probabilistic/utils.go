@@ -0,0 +1,190 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilistic
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type BloomOptions struct {
+	errorRate float64
+	capacity  int
+	expansion int // 0 when NONSCALING is provided
+	noCreate  bool
+	items     []string
+}
+
+type CuckooOptions struct {
+	capacity      int
+	bucketSize    int
+	maxIterations int
+	expansion     int
+	noCreate      bool
+	items         []string
+}
+
+// getBloomOptions parses the options of BF.RESERVE and BF.INSERT. For BF.RESERVE, the error rate and capacity
+// are passed as the ERROR and CAPACITY options so that both commands share the same validation.
+func getBloomOptions(cmd []string) (BloomOptions, error) {
+	options := BloomOptions{
+		errorRate: DefaultBloomErrorRate,
+		capacity:  DefaultBloomCapacity,
+		expansion: DefaultBloomExpansion,
+	}
+	nonScaling, expansion := false, false
+	for i := 0; i < len(cmd); i++ {
+		option := strings.ToLower(cmd[i])
+		switch option {
+		case "nocreate":
+			options.noCreate = true
+		case "nonscaling":
+			nonScaling = true
+		case "items":
+			options.items = cmd[i+1:]
+			if len(options.items) == 0 {
+				return BloomOptions{}, errors.New("wrong number of arguments")
+			}
+			i = len(cmd)
+		case "error", "capacity", "expansion":
+			if i+1 >= len(cmd) {
+				return BloomOptions{}, errors.New("syntax error")
+			}
+			i++
+			if option == "error" {
+				errorRate, err := strconv.ParseFloat(cmd[i], 64)
+				if err != nil || errorRate <= 0 || errorRate >= 1 {
+					return BloomOptions{}, errors.New("(0 < error rate range < 1)")
+				}
+				options.errorRate = errorRate
+				continue
+			}
+			n, err := strconv.Atoi(cmd[i])
+			if err != nil || n < 1 {
+				return BloomOptions{}, fmt.Errorf("bad %s", option)
+			}
+			if option == "capacity" {
+				options.capacity = n
+			} else {
+				options.expansion, expansion = n, true
+			}
+		default:
+			return BloomOptions{}, errors.New("syntax error")
+		}
+	}
+	if nonScaling {
+		if expansion {
+			return BloomOptions{}, errors.New("nonscaling filters cannot expand")
+		}
+		options.expansion = 0
+	}
+	return options, nil
+}
+
+// getCuckooOptions parses the options of CF.RESERVE and the CF.INSERT family. As with getBloomOptions,
+// the capacity of CF.RESERVE is passed as the CAPACITY option.
+func getCuckooOptions(cmd []string) (CuckooOptions, error) {
+	options := CuckooOptions{
+		capacity:      DefaultCuckooCapacity,
+		bucketSize:    DefaultCuckooBucketSize,
+		maxIterations: DefaultCuckooMaxIterations,
+		expansion:     DefaultCuckooExpansion,
+	}
+	for i := 0; i < len(cmd); i++ {
+		option := strings.ToLower(cmd[i])
+		switch option {
+		case "nocreate":
+			options.noCreate = true
+		case "items":
+			options.items = cmd[i+1:]
+			if len(options.items) == 0 {
+				return CuckooOptions{}, errors.New("wrong number of arguments")
+			}
+			i = len(cmd)
+		case "capacity", "bucketsize", "maxiterations", "expansion":
+			if i+1 >= len(cmd) {
+				return CuckooOptions{}, errors.New("syntax error")
+			}
+			i++
+			n, err := strconv.Atoi(cmd[i])
+			switch {
+			case err != nil:
+				return CuckooOptions{}, fmt.Errorf("bad %s", option)
+			case option == "capacity" && n >= 1:
+				options.capacity = n
+			case option == "bucketsize" && n >= 1 && n <= 255:
+				options.bucketSize = n
+			case option == "maxiterations" && n >= 1 && n <= 65535:
+				options.maxIterations = n
+			case option == "expansion" && n >= 0 && n <= 32768:
+				options.expansion = n
+			default:
+				return CuckooOptions{}, fmt.Errorf("bad %s", option)
+			}
+		default:
+			return CuckooOptions{}, errors.New("syntax error")
+		}
+	}
+	return options, nil
+}
+
+// parseItemIncrements parses a list of item and increment pairs, as passed to CMS.INCRBY and TOPK.INCRBY.
+func parseItemIncrements(cmd []string) ([]string, []uint64, error) {
+	if len(cmd) == 0 || len(cmd)%2 != 0 {
+		return nil, nil, errors.New("wrong number of arguments")
+	}
+	items := make([]string, 0, len(cmd)/2)
+	increments := make([]uint64, 0, len(cmd)/2)
+	for i := 0; i < len(cmd); i += 2 {
+		increment, err := strconv.ParseUint(cmd[i+1], 10, 64)
+		if err != nil {
+			return nil, nil, errors.New("cannot parse increment")
+		}
+		items = append(items, cmd[i])
+		increments = append(increments, increment)
+	}
+	return items, increments, nil
+}
+
+// encodeIntegers encodes the values as a RESP array of integers.
+func encodeIntegers[T int | uint64](values []T) []byte {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%d\r\n", len(values)))
+	for _, value := range values {
+		b.WriteString(fmt.Sprintf(":%d\r\n", value))
+	}
+	return []byte(b.String())
+}
+
+// encodeInfo encodes the names and values of the fields returned by the INFO commands as a flat RESP array.
+// Values are integers, strings or nil.
+func encodeInfo(fields []string, values []interface{}) []byte {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%d\r\n", len(fields)*2))
+	for i, field := range fields {
+		b.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(field), field))
+		switch value := values[i].(type) {
+		case nil:
+			b.WriteString("$-1\r\n")
+		case string:
+			b.WriteString(fmt.Sprintf("$%d\r\n%s\r\n", len(value), value))
+		default:
+			b.WriteString(fmt.Sprintf(":%d\r\n", value))
+		}
+	}
+	return []byte(b.String())
+}
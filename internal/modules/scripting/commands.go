@@ -0,0 +1,189 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scripting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+func handleEval(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := evalKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	script := params.Command[1]
+	engine, err := getScriptEngine(script)
+	if err != nil {
+		return nil, err
+	}
+
+	sha := getScriptSHA(script)
+	if !params.ScriptExists(sha) {
+		if err = params.AddScript(engine, "RAW", script, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	args := params.Command[3+len(keys.WriteKeys):]
+	return params.RunScript(params.Context, sha, keys.WriteKeys, args)
+}
+
+func handleEvalSHA(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := evalKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	sha := params.Command[1]
+	if !params.ScriptExists(sha) {
+		return nil, errors.New("NOSCRIPT No matching script. Please use EVAL")
+	}
+
+	args := params.Command[3+len(keys.WriteKeys):]
+	return params.RunScript(params.Context, sha, keys.WriteKeys, args)
+}
+
+func handleScriptLoad(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := scriptLoadKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+
+	script := params.Command[2]
+	engine, err := getScriptEngine(script)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = params.AddScript(engine, "RAW", script, nil); err != nil {
+		return nil, err
+	}
+
+	sha := getScriptSHA(script)
+	return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(sha), sha)), nil
+}
+
+func handleScriptExists(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := scriptExistsKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(params.Command[2:]))
+	for _, sha := range params.Command[2:] {
+		if params.ScriptExists(sha) {
+			res += ":1\r\n"
+			continue
+		}
+		res += ":0\r\n"
+	}
+
+	return []byte(res), nil
+}
+
+func handleScriptFlush(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := scriptFlushKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+
+	if len(params.Command) == 3 && !strings.EqualFold(params.Command[2], "ASYNC") &&
+		!strings.EqualFold(params.Command[2], "SYNC") {
+		return nil, fmt.Errorf("unknown flush mode %s, expected ASYNC or SYNC", strings.ToUpper(params.Command[2]))
+	}
+
+	params.FlushScripts()
+
+	return []byte(constants.OkResponse), nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "eval",
+			Module:     constants.ScriptingModule,
+			Categories: []string{constants.ScriptingCategory, constants.SlowCategory, constants.WriteCategory},
+			Description: `(EVAL script numkeys [key [key ...]] [arg [arg ...]])
+Execute a Lua or JavaScript script atomically. The keys are available to the script in the KEYS global
+and the rest of the arguments in the ARGV global. Scripts are executed with Lua unless the first line of the script
+is the "#!js" shebang. The script is cached and can be executed again using EVALSHA.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: evalKeyFunc,
+			HandlerFunc:       handleEval,
+		},
+		{
+			Command:    "evalsha",
+			Module:     constants.ScriptingModule,
+			Categories: []string{constants.ScriptingCategory, constants.SlowCategory, constants.WriteCategory},
+			Description: `(EVALSHA sha1 numkeys [key [key ...]] [arg [arg ...]])
+Execute a cached script by its SHA1 digest. The script must have been cached by EVAL or SCRIPT LOAD.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: evalKeyFunc,
+			HandlerFunc:       handleEvalSHA,
+		},
+		{
+			Command:     "script",
+			Module:      constants.ScriptingModule,
+			Categories:  []string{},
+			Description: "Script cache commands",
+			Sync:        false,
+			Type:        "BUILT_IN",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels:  make([]string, 0),
+					ReadKeys:  make([]string, 0),
+					WriteKeys: make([]string, 0),
+				}, nil
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "load",
+					Module:     constants.ScriptingModule,
+					Categories: []string{constants.ScriptingCategory, constants.SlowCategory, constants.WriteCategory},
+					Description: `(SCRIPT LOAD script) Load a script into the script cache without executing it. 
+Returns the SHA1 digest of the script.`,
+					Sync:              true,
+					KeyExtractionFunc: scriptLoadKeyFunc,
+					HandlerFunc:       handleScriptLoad,
+				},
+				{
+					Command:    "exists",
+					Module:     constants.ScriptingModule,
+					Categories: []string{constants.ScriptingCategory, constants.SlowCategory},
+					Description: `(SCRIPT EXISTS sha1 [sha1 ...]) 
+Returns an array of integers specifying whether each of the scripts is in the script cache.`,
+					Sync:              false,
+					KeyExtractionFunc: scriptExistsKeyFunc,
+					HandlerFunc:       handleScriptExists,
+				},
+				{
+					Command:    "flush",
+					Module:     constants.ScriptingModule,
+					Categories: []string{constants.ScriptingCategory, constants.SlowCategory, constants.WriteCategory},
+					Description: `(SCRIPT FLUSH [ASYNC | SYNC]) Remove all the scripts from the script cache.
+The flush is always synchronous, the ASYNC and SYNC modifiers are accepted for compatibility.`,
+					Sync:              true,
+					KeyExtractionFunc: scriptFlushKeyFunc,
+					HandlerFunc:       handleScriptFlush,
+				},
+			},
+		},
+	}
+}
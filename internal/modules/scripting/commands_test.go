@@ -0,0 +1,349 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scripting_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+func Test_Scripting(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	incrScript := `
+local values = getValues(KEYS)
+local current = values[KEYS[1]] or 0
+local entries = {}
+entries[KEYS[1]] = current + tonumber(ARGV[1])
+setValues(entries)
+return entries[KEYS[1]]`
+
+	t.Run("Test_HandleEval", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		// A HyperLogLog has no Lua or JS representation.
+		if err = client.WriteArray([]resp.Value{
+			resp.StringValue("PFADD"), resp.StringValue("EvalHLLKey"), resp.StringValue("a"),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, _, err = client.ReadValue(); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name             string
+			command          []string
+			expectedResponse string
+			expectedError    error
+		}{
+			{
+				name:             "1. Return bulk string from Lua script",
+				command:          []string{"EVAL", `return "hello"`, "0"},
+				expectedResponse: "hello",
+			},
+			{
+				name:             "2. Return KEYS and ARGV from Lua script",
+				command:          []string{"EVAL", `return KEYS[1] .. ARGV[1] .. ARGV[2]`, "1", "key", "arg1", "arg2"},
+				expectedResponse: "keyarg1arg2",
+			},
+			{
+				name:             "3. Return KEYS and ARGV from JS script",
+				command:          []string{"EVAL", "#!js\nreturn KEYS[0] + ARGV[0] + ARGV[1];", "1", "key", "arg1", "arg2"},
+				expectedResponse: "keyarg1arg2",
+			},
+			{
+				name:             "4. Return simple string from ok table",
+				command:          []string{"EVAL", `return {ok = "DONE"}`, "0"},
+				expectedResponse: "DONE",
+			},
+			{
+				name:          "5. Return error from err table",
+				command:       []string{"EVAL", `return {err = "script failed"}`, "0"},
+				expectedError: errors.New("script failed"),
+			},
+			{
+				name:          "6. Return error when numkeys is not an integer",
+				command:       []string{"EVAL", `return 1`, "one"},
+				expectedError: errors.New("numkeys must be an integer"),
+			},
+			{
+				name:          "7. Return error when numkeys is greater than the number of args",
+				command:       []string{"EVAL", `return 1`, "2", "key1"},
+				expectedError: errors.New("numkeys cannot be greater than the number of args"),
+			},
+			{
+				name:          "8. Command too short",
+				command:       []string{"EVAL", `return 1`},
+				expectedError: errors.New(constants.WrongArgsResponse),
+			},
+			{
+				name:          "9. Return error when Lua script reads a key that is not in KEYS",
+				command:       []string{"EVAL", `return getValues({"EvalOtherKey"})["EvalOtherKey"]`, "1", "EvalKey"},
+				expectedError: errors.New("script accessed key EvalOtherKey that was not declared in KEYS"),
+			},
+			{
+				name:          "10. Return error when Lua script writes a key that is not in KEYS",
+				command:       []string{"EVAL", `setValues({EvalOtherKey = "value"})`, "0"},
+				expectedError: errors.New("script accessed key EvalOtherKey that was not declared in KEYS"),
+			},
+			{
+				name:          "11. Return error when JS script writes a key that is not in KEYS",
+				command:       []string{"EVAL", "#!js\nsetValues({EvalOtherKey: \"value\"});", "1", "EvalKey"},
+				expectedError: errors.New("script accessed key EvalOtherKey that was not declared in KEYS"),
+			},
+			{
+				name:             "12. Return nil for a value that has no Lua representation",
+				command:          []string{"EVAL", `local v = getValues({KEYS[1]}); return v[KEYS[1]]`, "1", "EvalHLLKey"},
+				expectedResponse: "",
+			},
+			{
+				name:             "13. Return nil for a value that has no JS representation",
+				command:          []string{"EVAL", "#!js\nreturn getValues(KEYS)[KEYS[0]];", "1", "EvalHLLKey"},
+				expectedResponse: "",
+			},
+			{
+				name:          "14. Return the first line of a Lua runtime error",
+				command:       []string{"EVAL", `local t = nil; return t.field`, "0"},
+				expectedError: errors.New("attempt to index a non-table object(nil) with key 'field'"),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+				if test.expectedError != nil {
+					if res.Error() == nil {
+						t.Errorf("expected error \"%s\", got response \"%s\"", test.expectedError.Error(), res.String())
+						return
+					}
+					if strings.Contains(res.Error().Error(), "stack traceback") {
+						t.Errorf("expected a single line error, got \"%s\"", res.Error().Error())
+					}
+					if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error().Error())
+					}
+					return
+				}
+				if res.String() != test.expectedResponse {
+					t.Errorf("expected response \"%s\", got \"%s\"", test.expectedResponse, res.String())
+				}
+			})
+		}
+	})
+
+	t.Run("Test_HandleEvalIsAtomic", func(t *testing.T) {
+		t.Parallel()
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				conn, err := internal.GetConnection("localhost", port)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer func() {
+					_ = conn.Close()
+				}()
+				client := resp.NewConn(conn)
+				if err = client.WriteArray([]resp.Value{
+					resp.StringValue("EVAL"),
+					resp.StringValue(incrScript),
+					resp.StringValue("1"),
+					resp.StringValue("EvalAtomicKey"),
+					resp.StringValue("1"),
+				}); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, _, err = client.ReadValue(); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+		if err = client.WriteArray([]resp.Value{resp.StringValue("GET"), resp.StringValue("EvalAtomicKey")}); err != nil {
+			t.Error(err)
+			return
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if res.Integer() != 20 {
+			t.Errorf("expected value 20 after concurrent increments, got %s", res.String())
+		}
+	})
+
+	t.Run("Test_HandleScriptLoadEvalShaExistsFlush", func(t *testing.T) {
+		// Not parallel as SCRIPT FLUSH would remove the scripts cached by the other tests.
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		// Load the script.
+		if err = client.WriteArray([]resp.Value{
+			resp.StringValue("SCRIPT"), resp.StringValue("LOAD"), resp.StringValue(incrScript),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sha := res.String()
+		if len(sha) != 40 {
+			t.Errorf("expected 40 character SHA1 digest, got \"%s\"", sha)
+			return
+		}
+
+		// Execute the script using its digest.
+		if err = client.WriteArray([]resp.Value{
+			resp.StringValue("EVALSHA"), resp.StringValue(sha), resp.StringValue("1"),
+			resp.StringValue("EvalShaKey"), resp.StringValue("3"),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+		if res, _, err = client.ReadValue(); err != nil {
+			t.Error(err)
+			return
+		}
+		if res.Integer() != 3 {
+			t.Errorf("expected EVALSHA response 3, got %s", res.String())
+		}
+
+		// Check that the script exists.
+		if err = client.WriteArray([]resp.Value{
+			resp.StringValue("SCRIPT"), resp.StringValue("EXISTS"), resp.StringValue(sha),
+			resp.StringValue("0000000000000000000000000000000000000000"),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+		if res, _, err = client.ReadValue(); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(res.Array()) != 2 || res.Array()[0].Integer() != 1 || res.Array()[1].Integer() != 0 {
+			t.Errorf("expected SCRIPT EXISTS response [1 0], got %v", res.Array())
+		}
+
+		// Flush the script cache.
+		if err = client.WriteArray([]resp.Value{resp.StringValue("SCRIPT"), resp.StringValue("FLUSH")}); err != nil {
+			t.Error(err)
+			return
+		}
+		if res, _, err = client.ReadValue(); err != nil {
+			t.Error(err)
+			return
+		}
+		if !strings.EqualFold(res.String(), "ok") {
+			t.Errorf("expected SCRIPT FLUSH response OK, got %s", res.String())
+		}
+
+		// EVALSHA should fail after the flush.
+		if err = client.WriteArray([]resp.Value{
+			resp.StringValue("EVALSHA"), resp.StringValue(sha), resp.StringValue("1"),
+			resp.StringValue("EvalShaKey"), resp.StringValue("3"),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+		if res, _, err = client.ReadValue(); err != nil {
+			t.Error(err)
+			return
+		}
+		if res.Error() == nil || !strings.Contains(res.Error().Error(), "NOSCRIPT") {
+			t.Errorf("expected NOSCRIPT error after flush, got %s", res.String())
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scripting
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+)
+
+// getScriptEngine returns the engine that should execute the script based on its shebang line.
+// Scripts without a shebang are executed with Lua.
+func getScriptEngine(script string) (string, error) {
+	if !strings.HasPrefix(script, "#!") {
+		return "LUA", nil
+	}
+	line, _, _ := strings.Cut(script[2:], "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("missing engine in script shebang")
+	}
+	switch strings.ToLower(fields[0]) {
+	case "lua":
+		return "LUA", nil
+	case "js":
+		return "JS", nil
+	default:
+		return "", fmt.Errorf("unsupported engine %s in script shebang, expected lua or js", fields[0])
+	}
+}
+
+// getScriptSHA returns the hex encoded SHA1 digest of the script.
+func getScriptSHA(script string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(script)))
+}
@@ -0,0 +1,237 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The reducers of the GROUPBY step of an aggregation.
+const (
+	ReduceCount         = "COUNT"          // The number of rows in the group.
+	ReduceCountDistinct = "COUNT_DISTINCT" // The number of distinct values of the property.
+	ReduceSum           = "SUM"            // The sum of the numeric values of the property.
+	ReduceMin           = "MIN"            // The lowest numeric value of the property.
+	ReduceMax           = "MAX"            // The highest numeric value of the property.
+	ReduceAvg           = "AVG"            // The average of the numeric values of the property.
+)
+
+// Reducers are the reducers supported by the GROUPBY step.
+var Reducers = []string{ReduceCount, ReduceCountDistinct, ReduceSum, ReduceMin, ReduceMax, ReduceAvg}
+
+// Row is a result of an aggregation. Fields are the properties returned for the row, in order, while Values
+// holds every property of the row that the steps can refer to.
+type Row struct {
+	Fields []string
+	Values map[string]string
+}
+
+// Step is a step of an aggregation pipeline. Each step transforms the rows returned by the previous step.
+type Step interface {
+	apply(rows []Row) []Row
+}
+
+// Reducer computes a property of each group from the rows of the group. Property is empty for COUNT.
+type Reducer struct {
+	Function string
+	Property string
+	Alias    string
+}
+
+// DefaultAlias returns the name of the property of the reducer when the aggregation doesn't name it.
+func (reducer Reducer) DefaultAlias() string {
+	return "__generated_alias" + strings.ToLower(reducer.Function) + strings.ToLower(reducer.Property)
+}
+
+// GroupBy groups the rows by the values of the properties and reduces each group to a row.
+// The rows returned contain the properties and the reducers.
+type GroupBy struct {
+	Properties []string
+	Reducers   []Reducer
+}
+
+func (step GroupBy) apply(rows []Row) []Row {
+	var groups [][]Row
+	positions := make(map[string]int)
+	for _, row := range rows {
+		values := make([]string, len(step.Properties))
+		for i, property := range step.Properties {
+			values[i] = row.Values[property]
+		}
+		key := strings.Join(values, "\x00")
+		i, ok := positions[key]
+		if !ok {
+			i = len(groups)
+			positions[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], row)
+	}
+
+	res := make([]Row, len(groups))
+	for i, group := range groups {
+		row := Row{Values: make(map[string]string)}
+		for _, property := range step.Properties {
+			row.Fields = append(row.Fields, property)
+			if value, ok := group[0].Values[property]; ok {
+				row.Values[property] = value
+			}
+		}
+		for _, reducer := range step.Reducers {
+			row.Fields = append(row.Fields, reducer.Alias)
+			row.Values[reducer.Alias] = reducer.reduce(group)
+		}
+		res[i] = row
+	}
+	return res
+}
+
+func (reducer Reducer) reduce(rows []Row) string {
+	if reducer.Function == ReduceCount {
+		return strconv.Itoa(len(rows))
+	}
+	if reducer.Function == ReduceCountDistinct {
+		distinct := make(map[string]struct{})
+		for _, row := range rows {
+			if value, ok := row.Values[reducer.Property]; ok {
+				distinct[value] = struct{}{}
+			}
+		}
+		return strconv.Itoa(len(distinct))
+	}
+
+	var numbers []float64
+	for _, row := range rows {
+		if n, err := strconv.ParseFloat(row.Values[reducer.Property], 64); err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	var res float64
+	switch reducer.Function {
+	case ReduceSum, ReduceAvg:
+		for _, n := range numbers {
+			res += n
+		}
+		if reducer.Function == ReduceAvg && len(numbers) > 0 {
+			res /= float64(len(numbers))
+		}
+	case ReduceMin:
+		res = math.Inf(1)
+		for _, n := range numbers {
+			res = min(res, n)
+		}
+	case ReduceMax:
+		res = math.Inf(-1)
+		for _, n := range numbers {
+			res = max(res, n)
+		}
+	}
+	return strconv.FormatFloat(res, 'f', -1, 64)
+}
+
+// SortKey is a property the rows are sorted by.
+type SortKey struct {
+	Property   string
+	Descending bool
+}
+
+// SortBy sorts the rows by the properties. Values are compared as numbers when both are numbers, otherwise as
+// strings. Rows without the property are sorted last.
+type SortBy struct {
+	Keys []SortKey
+}
+
+func (step SortBy) apply(rows []Row) []Row {
+	slices.SortStableFunc(rows, func(a, b Row) int {
+		for _, key := range step.Keys {
+			x, xok := a.Values[key.Property]
+			y, yok := b.Values[key.Property]
+			if !xok || !yok {
+				if c := compareMissing(xok, yok); c != 0 {
+					return c
+				}
+				continue
+			}
+			c := compareValues(x, y)
+			if key.Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return rows
+}
+
+func compareValues(a, b string) int {
+	x, xerr := strconv.ParseFloat(a, 64)
+	y, yerr := strconv.ParseFloat(b, 64)
+	if xerr != nil || yerr != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// Aggregate applies the steps to the rows in order.
+func Aggregate(rows []Row, steps []Step) []Row {
+	for _, step := range steps {
+		rows = step.apply(rows)
+	}
+	return rows
+}
+
+// ParseReducer parses the function and arguments of a REDUCE clause. Properties are referred to with @.
+func ParseReducer(function string, args []string) (Reducer, error) {
+	reducer := Reducer{Function: strings.ToUpper(function)}
+	if !slices.Contains(Reducers, reducer.Function) {
+		return Reducer{}, fmt.Errorf("unknown reducer %s", function)
+	}
+	if reducer.Function == ReduceCount {
+		if len(args) != 0 {
+			return Reducer{}, errors.New("COUNT takes no arguments")
+		}
+		return reducer, nil
+	}
+	if len(args) != 1 {
+		return Reducer{}, fmt.Errorf("%s takes exactly one argument", reducer.Function)
+	}
+	property, err := ParseProperty(args[0])
+	if err != nil {
+		return Reducer{}, err
+	}
+	reducer.Property = property
+	return reducer, nil
+}
+
+// ParseProperty returns the name of a property referred to with @.
+func ParseProperty(arg string) (string, error) {
+	if len(arg) < 2 || arg[0] != '@' {
+		return "", fmt.Errorf("property %s must start with @", arg)
+	}
+	return arg[1:], nil
+}
@@ -0,0 +1,663 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/internal/modules/hash"
+)
+
+func getEngine(params internal.HandlerFuncParams) (*Engine, int, error) {
+	engine, ok := params.GetSearch().(*Engine)
+	if !ok {
+		return nil, 0, errors.New("could not load search module")
+	}
+	return engine, params.Context.Value("Database").(int), nil
+}
+
+func parseCount(arg string, name string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+func encodeBulkString(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func encodeFields(names []string, values map[string]string) string {
+	var res string
+	var n int
+	for _, name := range names {
+		if value, ok := values[name]; ok {
+			res += encodeBulkString(name) + encodeBulkString(value)
+			n++
+		}
+	}
+	return fmt.Sprintf("*%d\r\n", n*2) + res
+}
+
+// parseSchema parses the fields that follow SCHEMA in FT.CREATE.
+func parseSchema(args []string) ([]Field, error) {
+	var fields []Field
+	for i := 0; i < len(args); {
+		field := Field{Name: args[i]}
+		i++
+		if i+1 < len(args) && strings.EqualFold(args[i], "as") {
+			field.Alias = args[i+1]
+			i += 2
+		}
+		if i >= len(args) {
+			return nil, fmt.Errorf("missing type of field %s", field.Name)
+		}
+		field.Type = strings.ToUpper(args[i])
+		if !slices.Contains([]string{FieldText, FieldTag, FieldNumeric}, field.Type) {
+			return nil, fmt.Errorf("unknown field type %s", args[i])
+		}
+		i++
+	options:
+		for i < len(args) {
+			switch strings.ToLower(args[i]) {
+			case "sortable":
+				field.Sortable = true
+				i++
+			case "casesensitive":
+				if field.Type != FieldTag {
+					return nil, errors.New("CASESENSITIVE is only supported by TAG fields")
+				}
+				field.CaseSensitive = true
+				i++
+			case "separator":
+				if field.Type != FieldTag {
+					return nil, errors.New("SEPARATOR is only supported by TAG fields")
+				}
+				if i+1 >= len(args) || len(args[i+1]) != 1 {
+					return nil, errors.New("the separator must be a single character")
+				}
+				field.Separator = args[i+1]
+				i += 2
+			default:
+				break options
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func handleFTCreate(params internal.HandlerFuncParams) ([]byte, error) {
+	engine, database, err := getEngine(params)
+	if err != nil {
+		return nil, err
+	}
+
+	definition := Definition{Name: params.Command[1]}
+	args := params.Command[2:]
+	for len(args) > 0 && !strings.EqualFold(args[0], "schema") {
+		switch strings.ToLower(args[0]) {
+		case "on":
+			if len(args) < 2 || !strings.EqualFold(args[1], "hash") {
+				return nil, errors.New("only HASH indexes are supported")
+			}
+			args = args[2:]
+		case "prefix":
+			if len(args) < 2 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			n, err := parseCount(args[1], "PREFIX count")
+			if err != nil {
+				return nil, err
+			}
+			if len(args) < 2+n {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			definition.Prefixes = append(definition.Prefixes, args[2:2+n]...)
+			args = args[2+n:]
+		default:
+			return nil, fmt.Errorf("unknown option %s", args[0])
+		}
+	}
+	if len(args) == 0 {
+		return nil, errors.New("missing SCHEMA")
+	}
+	if definition.Fields, err = parseSchema(args[1:]); err != nil {
+		return nil, err
+	}
+	if err = engine.CreateIndex(database, definition); err != nil {
+		return nil, err
+	}
+
+	// Add the hashes that already exist to the index.
+	var cursor uint64
+	for {
+		cursor, _ = params.ScanKeys(params.Context, cursor, 0, func(key string, value interface{}) bool {
+			engine.AddKey(database, definition.Name, key, value)
+			return false
+		})
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleFTDropIndex(params internal.HandlerFuncParams) ([]byte, error) {
+	engine, database, err := getEngine(params)
+	if err != nil {
+		return nil, err
+	}
+	deleteDocuments := false
+	switch {
+	case len(params.Command) == 3 && strings.EqualFold(params.Command[2], "dd"):
+		deleteDocuments = true
+	case len(params.Command) != 2:
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	keys, err := engine.DropIndex(database, params.Command[1])
+	if err != nil {
+		return nil, err
+	}
+	if deleteDocuments {
+		for _, key := range keys {
+			if err = params.DeleteKey(params.Context, key); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleFTList(params internal.HandlerFuncParams) ([]byte, error) {
+	engine, database, err := getEngine(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 1 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	names := engine.Indexes(database)
+	res := fmt.Sprintf("*%d\r\n", len(names))
+	for _, name := range names {
+		res += encodeBulkString(name)
+	}
+	return []byte(res), nil
+}
+
+func handleFTInfo(params internal.HandlerFuncParams) ([]byte, error) {
+	engine, database, err := getEngine(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	info, err := engine.Info(database, params.Command[1])
+	if err != nil {
+		return nil, err
+	}
+
+	res := "*12\r\n"
+	res += encodeBulkString("index_name") + encodeBulkString(info.Definition.Name)
+
+	res += encodeBulkString("index_definition") + "*4\r\n"
+	res += encodeBulkString("key_type") + encodeBulkString("HASH") + encodeBulkString("prefixes")
+	res += fmt.Sprintf("*%d\r\n", len(info.Definition.Prefixes))
+	for _, prefix := range info.Definition.Prefixes {
+		res += encodeBulkString(prefix)
+	}
+
+	res += encodeBulkString("attributes") + fmt.Sprintf("*%d\r\n", len(info.Definition.Fields))
+	for _, field := range info.Definition.Fields {
+		attribute := []string{"identifier", field.Name, "attribute", field.Alias, "type", field.Type}
+		if field.Type == FieldTag {
+			attribute = append(attribute, "SEPARATOR", field.Separator)
+			if field.CaseSensitive {
+				attribute = append(attribute, "CASESENSITIVE")
+			}
+		}
+		if field.Sortable {
+			attribute = append(attribute, "SORTABLE")
+		}
+		res += fmt.Sprintf("*%d\r\n", len(attribute))
+		for _, s := range attribute {
+			res += encodeBulkString(s)
+		}
+	}
+
+	res += encodeBulkString("num_docs") + fmt.Sprintf(":%d\r\n", info.NumDocs)
+	res += encodeBulkString("num_terms") + fmt.Sprintf(":%d\r\n", info.NumTerms)
+	res += encodeBulkString("num_records") + fmt.Sprintf(":%d\r\n", info.NumRecords)
+	return []byte(res), nil
+}
+
+// hashFields returns the fields of the hashes at the keys, formatted as strings.
+func hashFields(params internal.HandlerFuncParams, keys []string) map[string]map[string]string {
+	res := make(map[string]map[string]string, len(keys))
+	for key, value := range params.GetValues(params.Context, keys) {
+		h, ok := value.(*hash.Hash)
+		if !ok {
+			continue
+		}
+		fields := make(map[string]string, h.Len())
+		for field, v := range h.All() {
+			fields[field] = formatValue(v.Value)
+		}
+		res[key] = fields
+	}
+	return res
+}
+
+// fieldName returns the name of the hash field of the index field with the alias. Names that are not aliases
+// are hash fields themselves.
+func fieldName(definition Definition, alias string) string {
+	for _, field := range definition.Fields {
+		if field.Alias == alias {
+			return field.Name
+		}
+	}
+	return alias
+}
+
+func handleFTSearch(params internal.HandlerFuncParams) ([]byte, error) {
+	engine, database, err := getEngine(params)
+	if err != nil {
+		return nil, err
+	}
+
+	name, query := params.Command[1], params.Command[2]
+	options := SearchOptions{Limit: 10}
+	var noContent, withScores bool
+	var returnFields []string
+	for args := params.Command[3:]; len(args) > 0; {
+		switch strings.ToLower(args[0]) {
+		case "nocontent":
+			noContent = true
+			args = args[1:]
+		case "withscores":
+			withScores = true
+			args = args[1:]
+		case "return":
+			if len(args) < 2 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			n, err := parseCount(args[1], "RETURN count")
+			if err != nil {
+				return nil, err
+			}
+			if len(args) < 2+n {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			// RETURN 0 returns no fields, like NOCONTENT.
+			noContent = noContent || n == 0
+			returnFields = args[2 : 2+n]
+			args = args[2+n:]
+		case "sortby":
+			if len(args) < 2 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			options.SortBy = strings.TrimPrefix(args[1], "@")
+			args = args[2:]
+			if len(args) > 0 && (strings.EqualFold(args[0], "asc") || strings.EqualFold(args[0], "desc")) {
+				options.Descending = strings.EqualFold(args[0], "desc")
+				args = args[1:]
+			}
+		case "limit":
+			if len(args) < 3 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			if options.Offset, err = parseCount(args[1], "LIMIT offset"); err != nil {
+				return nil, err
+			}
+			if options.Limit, err = parseCount(args[2], "LIMIT num"); err != nil {
+				return nil, err
+			}
+			args = args[3:]
+		default:
+			return nil, fmt.Errorf("unknown option %s", args[0])
+		}
+	}
+
+	total, hits, err := engine.Search(database, name, query, options)
+	if err != nil {
+		return nil, err
+	}
+	var definition Definition
+	if !noContent {
+		info, err := engine.Info(database, name)
+		if err != nil {
+			return nil, err
+		}
+		definition = info.Definition
+	}
+
+	keys := make([]string, len(hits))
+	for i, hit := range hits {
+		keys[i] = hit.Key
+	}
+	var contents map[string]map[string]string
+	if !noContent {
+		contents = hashFields(params, keys)
+	}
+
+	width := 1
+	if withScores {
+		width++
+	}
+	if !noContent {
+		width++
+	}
+	res := fmt.Sprintf("*%d\r\n:%d\r\n", 1+len(hits)*width, total)
+	for _, hit := range hits {
+		res += encodeBulkString(hit.Key)
+		if withScores {
+			res += encodeBulkString(strconv.FormatFloat(hit.Score, 'f', -1, 64))
+		}
+		if noContent {
+			continue
+		}
+		fields := contents[hit.Key]
+		if returnFields == nil {
+			names := make([]string, 0, len(fields))
+			for field := range fields {
+				names = append(names, field)
+			}
+			sort.Strings(names)
+			res += encodeFields(names, fields)
+			continue
+		}
+		// Returned fields may be referred to by their alias.
+		values := make(map[string]string, len(returnFields))
+		for _, field := range returnFields {
+			if value, ok := fields[fieldName(definition, field)]; ok {
+				values[field] = value
+			}
+		}
+		res += encodeFields(returnFields, values)
+	}
+	return []byte(res), nil
+}
+
+func handleFTAggregate(params internal.HandlerFuncParams) ([]byte, error) {
+	engine, database, err := getEngine(params)
+	if err != nil {
+		return nil, err
+	}
+
+	name, query := params.Command[1], params.Command[2]
+	var load []string
+	var loadAll bool
+	var steps []Step
+	offset, limit := 0, -1
+	for args := params.Command[3:]; len(args) > 0; {
+		switch strings.ToLower(args[0]) {
+		case "load":
+			if len(args) < 2 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			if args[1] == "*" {
+				loadAll = true
+				args = args[2:]
+				continue
+			}
+			n, err := parseCount(args[1], "LOAD count")
+			if err != nil {
+				return nil, err
+			}
+			if len(args) < 2+n {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			for _, arg := range args[2 : 2+n] {
+				load = append(load, strings.TrimPrefix(arg, "@"))
+			}
+			args = args[2+n:]
+		case "groupby":
+			if len(args) < 2 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			n, err := parseCount(args[1], "GROUPBY nargs")
+			if err != nil {
+				return nil, err
+			}
+			if len(args) < 2+n {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			step := GroupBy{}
+			for _, arg := range args[2 : 2+n] {
+				property, err := ParseProperty(arg)
+				if err != nil {
+					return nil, err
+				}
+				step.Properties = append(step.Properties, property)
+			}
+			args = args[2+n:]
+			for len(args) > 0 && strings.EqualFold(args[0], "reduce") {
+				if len(args) < 3 {
+					return nil, errors.New(constants.WrongArgsResponse)
+				}
+				n, err := parseCount(args[2], "REDUCE nargs")
+				if err != nil {
+					return nil, err
+				}
+				if len(args) < 3+n {
+					return nil, errors.New(constants.WrongArgsResponse)
+				}
+				reducer, err := ParseReducer(args[1], args[3:3+n])
+				if err != nil {
+					return nil, err
+				}
+				args = args[3+n:]
+				reducer.Alias = reducer.DefaultAlias()
+				if len(args) >= 2 && strings.EqualFold(args[0], "as") {
+					reducer.Alias = args[1]
+					args = args[2:]
+				}
+				step.Reducers = append(step.Reducers, reducer)
+			}
+			steps = append(steps, step)
+		case "sortby":
+			if len(args) < 2 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			n, err := parseCount(args[1], "SORTBY nargs")
+			if err != nil {
+				return nil, err
+			}
+			if len(args) < 2+n {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			step := SortBy{}
+			for _, arg := range args[2 : 2+n] {
+				if (strings.EqualFold(arg, "asc") || strings.EqualFold(arg, "desc")) && len(step.Keys) > 0 {
+					step.Keys[len(step.Keys)-1].Descending = strings.EqualFold(arg, "desc")
+					continue
+				}
+				property, err := ParseProperty(arg)
+				if err != nil {
+					return nil, err
+				}
+				step.Keys = append(step.Keys, SortKey{Property: property})
+			}
+			args = args[2+n:]
+			steps = append(steps, step)
+		case "limit":
+			if len(args) < 3 {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			if offset, err = parseCount(args[1], "LIMIT offset"); err != nil {
+				return nil, err
+			}
+			if limit, err = parseCount(args[2], "LIMIT num"); err != nil {
+				return nil, err
+			}
+			args = args[3:]
+		default:
+			return nil, fmt.Errorf("unknown option %s", args[0])
+		}
+	}
+
+	_, hits, err := engine.Search(database, name, query, SearchOptions{Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+	info, err := engine.Info(database, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents map[string]map[string]string
+	if loadAll || len(load) > 0 {
+		keys := make([]string, len(hits))
+		for i, hit := range hits {
+			keys[i] = hit.Key
+		}
+		contents = hashFields(params, keys)
+	}
+	rows := make([]Row, len(hits))
+	for i, hit := range hits {
+		// The indexed fields can be referred to by every step, but are only returned when they're loaded.
+		row := Row{Values: hit.Values}
+		row.Values["__key"] = hit.Key
+		fields := contents[hit.Key]
+		if loadAll {
+			for field, value := range fields {
+				row.Fields = append(row.Fields, field)
+				row.Values[field] = value
+			}
+			sort.Strings(row.Fields)
+		}
+		for _, property := range load {
+			if property == "__key" {
+				row.Fields = append(row.Fields, property)
+				continue
+			}
+			if value, ok := fields[fieldName(info.Definition, property)]; ok {
+				row.Fields = append(row.Fields, property)
+				row.Values[property] = value
+			}
+		}
+		rows[i] = row
+	}
+
+	rows = Aggregate(rows, steps)
+	total := len(rows)
+	start := min(offset, total)
+	end := total
+	if limit >= 0 {
+		end = min(start+limit, total)
+	}
+	rows = rows[start:end]
+
+	res := fmt.Sprintf("*%d\r\n:%d\r\n", 1+len(rows), total)
+	for _, row := range rows {
+		res += encodeFields(row.Fields, row.Values)
+	}
+	return []byte(res), nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "ft.create",
+			Module:     constants.SearchModule,
+			Categories: []string{constants.SearchCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(FT.CREATE index [ON HASH] [PREFIX count prefix [prefix ...]]
+SCHEMA field [AS alias] TEXT | TAG [SEPARATOR separator] [CASESENSITIVE] | NUMERIC [SORTABLE] [field ...])
+Creates an index of the hashes whose keys start with one of the prefixes, or of every hash when there are
+no prefixes. The hashes that already exist are added to the index, and the index is kept up to date as hashes
+are written, deleted and expired.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: indexKeyFunc(5),
+			HandlerFunc:       handleFTCreate,
+		},
+		{
+			Command:    "ft.dropindex",
+			Module:     constants.SearchModule,
+			Categories: []string{constants.SearchCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(FT.DROPINDEX index [DD])
+Deletes the index. DD also deletes the hashes in the index.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: indexKeyFunc(2),
+			HandlerFunc:       handleFTDropIndex,
+		},
+		{
+			Command:    "ft._list",
+			Module:     constants.SearchModule,
+			Categories: []string{constants.SearchCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(FT._LIST)
+Returns the names of the indexes of the database.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: indexKeyFunc(1),
+			HandlerFunc:       handleFTList,
+		},
+		{
+			Command:    "ft.info",
+			Module:     constants.SearchModule,
+			Categories: []string{constants.SearchCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(FT.INFO index)
+Returns the definition of the index and the number of documents, terms and records it holds.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: indexKeyFunc(2),
+			HandlerFunc:       handleFTInfo,
+		},
+		{
+			Command:    "ft.search",
+			Module:     constants.SearchModule,
+			Categories: []string{constants.SearchCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(FT.SEARCH index query [NOCONTENT] [WITHSCORES] [RETURN count field [field ...]]
+[SORTBY field [ASC | DESC]] [LIMIT offset num])
+Returns the number of hashes in the index that match the query, followed by the key and fields of each hash in
+the page selected by LIMIT, which defaults to the first 10. Terms separated by spaces must all match,
+| separates alternatives, - negates an expression and parentheses group expressions. Fields are queried with
+@field:term for text fields, @field:{tag | tag} for tag fields and @field:[min max] for numeric fields.
+Terms that are not preceded by a field are searched in all the text fields. Results are sorted by score,
+unless SORTBY is set.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: indexKeyFunc(3),
+			HandlerFunc:       handleFTSearch,
+		},
+		{
+			Command:    "ft.aggregate",
+			Module:     constants.SearchModule,
+			Categories: []string{constants.SearchCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(FT.AGGREGATE index query [LOAD count field [field ...] | LOAD *]
+[GROUPBY nargs property [property ...] [REDUCE function nargs [arg ...] [AS name] ...] ...]
+[SORTBY nargs property [ASC | DESC] [property [ASC | DESC] ...]] [LIMIT offset num])
+Runs the hashes in the index that match the query through a pipeline of GROUPBY and SORTBY steps, in order.
+Properties are referred to with @. The reducers are COUNT, COUNT_DISTINCT, SUM, MIN, MAX and AVG.
+Returns the number of rows, followed by the rows in the page selected by LIMIT.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: indexKeyFunc(3),
+			HandlerFunc:       handleFTAggregate,
+		},
+	}
+}
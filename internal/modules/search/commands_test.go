@@ -0,0 +1,460 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+func Test_Search(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	// command is a command and its expected response. Array responses are flattened and compared element by
+	// element as strings, where nil elements are empty strings and "*" matches any element.
+	type command struct {
+		command          []string
+		expectedResponse interface{}
+		expectedError    error
+	}
+
+	var flatten func(value resp.Value) []string
+	flatten = func(value resp.Value) []string {
+		if value.Type() != resp.Array {
+			return []string{value.String()}
+		}
+		var values []string
+		for _, v := range value.Array() {
+			values = append(values, flatten(v)...)
+		}
+		return values
+	}
+
+	runCommands := func(t *testing.T, client *resp.Conn, commands []command) {
+		for _, c := range commands {
+			cmd := make([]resp.Value, len(c.command))
+			for i, arg := range c.command {
+				cmd[i] = resp.StringValue(arg)
+			}
+			if err := client.WriteArray(cmd); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if c.expectedError != nil {
+				if res.Error() == nil || !strings.Contains(res.Error().Error(), c.expectedError.Error()) {
+					t.Errorf("%v: expected error \"%s\", got \"%v\"", c.command, c.expectedError.Error(), res)
+				}
+				continue
+			}
+			if res.Error() != nil {
+				t.Errorf("%v: unexpected error \"%v\"", c.command, res.Error())
+				continue
+			}
+			switch expected := c.expectedResponse.(type) {
+			case int:
+				if res.Integer() != expected {
+					t.Errorf("%v: expected response %d, got \"%v\"", c.command, expected, res)
+				}
+			case string:
+				if res.String() != expected {
+					t.Errorf("%v: expected response \"%s\", got \"%s\"", c.command, expected, res.String())
+				}
+			case []string:
+				got := flatten(res)
+				matches := len(got) == len(expected)
+				for i := 0; matches && i < len(got); i++ {
+					matches = expected[i] == "*" || got[i] == expected[i]
+				}
+				if !matches {
+					t.Errorf("%v: expected response %v, got %v", c.command, expected, got)
+				}
+			}
+		}
+	}
+
+	runTests := func(t *testing.T, tests []struct {
+		name     string
+		commands []command
+	}) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		for _, test := range tests {
+			t.Log(test.name)
+			runCommands(t, client, test.commands)
+		}
+	}
+
+	t.Run("Test_HandleFTList", func(t *testing.T) {
+		// Not parallel, so that the indexes of the other tests don't exist yet.
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. FT._LIST returns the names of the indexes in order",
+				commands: []command{
+					{command: []string{"FT._LIST"}, expectedResponse: []string{}},
+					{command: []string{"FT.CREATE", "ListIdx2", "SCHEMA", "title", "TEXT"}, expectedResponse: "OK"},
+					{command: []string{"FT.CREATE", "ListIdx1", "SCHEMA", "title", "TEXT"}, expectedResponse: "OK"},
+					{command: []string{"FT._LIST"}, expectedResponse: []string{"ListIdx1", "ListIdx2"}},
+					{command: []string{"FT.DROPINDEX", "ListIdx1"}, expectedResponse: "OK"},
+					{command: []string{"FT.DROPINDEX", "ListIdx2"}, expectedResponse: "OK"},
+					{command: []string{"FT._LIST"}, expectedResponse: []string{}},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleFTCreate", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. FT.CREATE indexes the hashes that already exist",
+				commands: []command{
+					{command: []string{"HSET", "create:1", "title", "Hello world", "tags", "a, B", "price", "10"}, expectedResponse: 3},
+					{command: []string{"HSET", "create:2", "title", "Goodbye"}, expectedResponse: 1},
+					{command: []string{"HSET", "other:1", "title", "Hello"}, expectedResponse: 1},
+					{
+						command: []string{
+							"FT.CREATE", "CreateIdx1", "ON", "HASH", "PREFIX", "1", "create:", "SCHEMA",
+							"title", "TEXT", "tags", "AS", "labels", "TAG", "SEPARATOR", ",", "price", "NUMERIC", "SORTABLE",
+						},
+						expectedResponse: "OK",
+					},
+					{command: []string{"FT.SEARCH", "CreateIdx1", "hello", "NOCONTENT"}, expectedResponse: []string{"1", "create:1"}},
+					{command: []string{"FT.SEARCH", "CreateIdx1", "@labels:{b}", "NOCONTENT"}, expectedResponse: []string{"1", "create:1"}},
+					{
+						command: []string{"FT.INFO", "CreateIdx1"},
+						expectedResponse: []string{
+							"index_name", "CreateIdx1",
+							"index_definition", "key_type", "HASH", "prefixes", "create:",
+							"attributes",
+							"identifier", "title", "attribute", "title", "type", "TEXT",
+							"identifier", "tags", "attribute", "labels", "type", "TAG", "SEPARATOR", ",",
+							"identifier", "price", "attribute", "price", "type", "NUMERIC", "SORTABLE",
+							"num_docs", "2", "num_terms", "3", "num_records", "6",
+						},
+					},
+					{
+						command:       []string{"FT.CREATE", "CreateIdx1", "SCHEMA", "title", "TEXT"},
+						expectedError: errors.New("index CreateIdx1 already exists"),
+					},
+				},
+			},
+			{
+				name: "2. FT.CREATE rejects invalid definitions",
+				commands: []command{
+					{command: []string{"FT.CREATE", "CreateIdx2", "SCHEMA"}, expectedError: errors.New(constants.WrongArgsResponse)},
+					{
+						command:       []string{"FT.CREATE", "CreateIdx2", "SCHEMA", "location", "GEO"},
+						expectedError: errors.New("unknown field type GEO"),
+					},
+					{
+						command:       []string{"FT.CREATE", "CreateIdx2", "ON", "JSON", "SCHEMA", "title", "TEXT"},
+						expectedError: errors.New("only HASH indexes are supported"),
+					},
+					{
+						command:       []string{"FT.CREATE", "CreateIdx2", "SCHEMA", "title", "TEXT", "name", "AS", "title", "TAG"},
+						expectedError: errors.New("duplicate field title"),
+					},
+					{
+						command:       []string{"FT.CREATE", "CreateIdx2", "SCHEMA", "title", "TEXT", "SEPARATOR", ";"},
+						expectedError: errors.New("SEPARATOR is only supported by TAG fields"),
+					},
+					{
+						command:       []string{"FT.CREATE", "CreateIdx2", "PREFIX", "1", "create:", "title", "TEXT"},
+						expectedError: errors.New("unknown option title"),
+					},
+					{command: []string{"FT.INFO", "CreateIdx2"}, expectedError: errors.New("unknown index CreateIdx2")},
+				},
+			},
+			{
+				name: "3. FT.DROPINDEX deletes the index, and the hashes with DD",
+				commands: []command{
+					{command: []string{"HSET", "drop:1", "title", "Hello"}, expectedResponse: 1},
+					{command: []string{"FT.CREATE", "DropIdx1", "PREFIX", "1", "drop:", "SCHEMA", "title", "TEXT"}, expectedResponse: "OK"},
+					{command: []string{"FT.CREATE", "DropIdx2", "PREFIX", "1", "drop:", "SCHEMA", "title", "TEXT"}, expectedResponse: "OK"},
+					{command: []string{"FT.DROPINDEX", "DropIdx1"}, expectedResponse: "OK"},
+					{command: []string{"FT.SEARCH", "DropIdx1", "*"}, expectedError: errors.New("unknown index DropIdx1")},
+					{command: []string{"EXISTS", "drop:1"}, expectedResponse: 1},
+					{command: []string{"FT.DROPINDEX", "DropIdx2", "DD"}, expectedResponse: "OK"},
+					{command: []string{"EXISTS", "drop:1"}, expectedResponse: 0},
+					{command: []string{"FT.DROPINDEX", "DropIdx2"}, expectedError: errors.New("unknown index DropIdx2")},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleFTSearch", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. FT.SEARCH matches numeric ranges, tags and text terms",
+				commands: []command{
+					{
+						command:          []string{"FT.CREATE", "SearchIdx", "PREFIX", "1", "product:", "SCHEMA", "name", "TEXT", "category", "TAG", "price", "NUMERIC"},
+						expectedResponse: "OK",
+					},
+					{command: []string{"HSET", "product:1", "name", "Red running shoes", "category", "shoes,sport", "price", "80"}, expectedResponse: 3},
+					{command: []string{"HSET", "product:2", "name", "Blue running shorts", "category", "clothing,sport", "price", "30"}, expectedResponse: 3},
+					{command: []string{"HSET", "product:3", "name", "Red dress shoes", "category", "shoes,formal", "price", "120"}, expectedResponse: 3},
+					{command: []string{"HSET", "product:4", "name", "Green socks", "category", "clothing", "price", "5"}, expectedResponse: 3},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "@price:[30 100]", "NOCONTENT", "SORTBY", "price"},
+						expectedResponse: []string{"2", "product:2", "product:1"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "@price:[(30 +inf]", "NOCONTENT", "SORTBY", "price", "ASC"},
+						expectedResponse: []string{"2", "product:1", "product:3"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "@category:{shoes}", "NOCONTENT", "SORTBY", "price"},
+						expectedResponse: []string{"2", "product:1", "product:3"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "@category:{formal | clothing}", "NOCONTENT", "SORTBY", "price", "DESC"},
+						expectedResponse: []string{"3", "product:3", "product:2", "product:4"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "red", "NOCONTENT", "SORTBY", "@price"},
+						expectedResponse: []string{"2", "product:1", "product:3"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "run*", "NOCONTENT", "SORTBY", "price"},
+						expectedResponse: []string{"2", "product:2", "product:1"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "red -@category:{formal}", "NOCONTENT"},
+						expectedResponse: []string{"1", "product:1"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "@name:(red | green) @price:[0 100]", "NOCONTENT", "SORTBY", "price"},
+						expectedResponse: []string{"2", "product:4", "product:1"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "-red", "NOCONTENT", "SORTBY", "price"},
+						expectedResponse: []string{"2", "product:4", "product:2"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "*", "NOCONTENT", "SORTBY", "price", "LIMIT", "1", "2"},
+						expectedResponse: []string{"4", "product:2", "product:1"},
+					},
+					{command: []string{"FT.SEARCH", "SearchIdx", "*", "LIMIT", "0", "0"}, expectedResponse: []string{"4"}},
+					{command: []string{"FT.SEARCH", "SearchIdx", "purple"}, expectedResponse: []string{"0"}},
+				},
+			},
+			{
+				name: "2. FT.SEARCH returns the fields and scores of the hashes",
+				commands: []command{
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "@price:[5 5]"},
+						expectedResponse: []string{"1", "product:4", "category", "clothing", "name", "Green socks", "price", "5"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "@price:[5 5]", "RETURN", "2", "name", "missing"},
+						expectedResponse: []string{"1", "product:4", "name", "Green socks"},
+					},
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "shoes", "WITHSCORES", "NOCONTENT"},
+						expectedResponse: []string{"2", "product:1", "*", "product:3", "*"},
+					},
+					// The rarer term scores higher.
+					{
+						command:          []string{"FT.SEARCH", "SearchIdx", "running | dress", "NOCONTENT", "LIMIT", "0", "1"},
+						expectedResponse: []string{"3", "product:3"},
+					},
+				},
+			},
+			{
+				name: "3. FT.SEARCH keeps the index up to date with the keyspace",
+				commands: []command{
+					{command: []string{"HSET", "product:5", "name", "Yellow hat", "price", "50"}, expectedResponse: 2},
+					{command: []string{"HSET", "other:5", "name", "Yellow hat", "price", "50"}, expectedResponse: 2},
+					{command: []string{"FT.SEARCH", "SearchIdx", "yellow", "NOCONTENT"}, expectedResponse: []string{"1", "product:5"}},
+					{command: []string{"HSET", "product:5", "name", "Orange hat"}, expectedResponse: []string{"*"}},
+					{command: []string{"FT.SEARCH", "SearchIdx", "yellow", "NOCONTENT"}, expectedResponse: []string{"0"}},
+					{command: []string{"FT.SEARCH", "SearchIdx", "orange", "NOCONTENT"}, expectedResponse: []string{"1", "product:5"}},
+					{command: []string{"HDEL", "product:5", "price"}, expectedResponse: 1},
+					{command: []string{"FT.SEARCH", "SearchIdx", "@price:[50 50]", "NOCONTENT"}, expectedResponse: []string{"0"}},
+					{command: []string{"DEL", "product:5"}, expectedResponse: 1},
+					{command: []string{"FT.SEARCH", "SearchIdx", "orange", "NOCONTENT"}, expectedResponse: []string{"0"}},
+					{command: []string{"HSET", "product:6", "name", "Black hat"}, expectedResponse: 1},
+					{command: []string{"PEXPIREAT", "product:6", "1"}, expectedResponse: 1},
+					// Reading the key deletes it, as its TTL has expired.
+					{command: []string{"GET", "product:6"}, expectedResponse: []string{"*"}},
+					{command: []string{"FT.SEARCH", "SearchIdx", "black", "NOCONTENT"}, expectedResponse: []string{"0"}},
+					{command: []string{"SET", "product:7", "hat"}, expectedResponse: "OK"},
+					{command: []string{"FT.SEARCH", "SearchIdx", "@price:[-inf +inf]", "NOCONTENT"}, expectedResponse: []string{"4", "*", "*", "*", "*"}},
+				},
+			},
+			{
+				name: "4. FT.SEARCH rejects invalid queries",
+				commands: []command{
+					{command: []string{"FT.SEARCH", "SearchMissingIdx", "*"}, expectedError: errors.New("unknown index SearchMissingIdx")},
+					{command: []string{"FT.SEARCH", "SearchIdx", ""}, expectedError: errors.New("empty query")},
+					{command: []string{"FT.SEARCH", "SearchIdx", "@missing:red"}, expectedError: errors.New("unknown field missing")},
+					{
+						command:       []string{"FT.SEARCH", "SearchIdx", "@price:red"},
+						expectedError: errors.New("numeric field price must be queried with [min max]"),
+					},
+					{
+						command:       []string{"FT.SEARCH", "SearchIdx", "@name:{red}"},
+						expectedError: errors.New("field name is not a tag field"),
+					},
+					{command: []string{"FT.SEARCH", "SearchIdx", "(red | blue"}, expectedError: errors.New("missing ) in query")},
+					{command: []string{"FT.SEARCH", "SearchIdx", "@price:[1 2"}, expectedError: errors.New("missing ] in query")},
+					{command: []string{"FT.SEARCH", "SearchIdx", "@price:[1 x]"}, expectedError: errors.New("invalid range bound x in query")},
+					{command: []string{"FT.SEARCH", "SearchIdx", "@category:{shoes"}, expectedError: errors.New("missing } in query")},
+					{command: []string{"FT.SEARCH", "SearchIdx", "red)"}, expectedError: errors.New("syntax error at offset 3 near )")},
+					{
+						command:       []string{"FT.SEARCH", "SearchIdx", "*", "SORTBY", "missing"},
+						expectedError: errors.New("unknown field missing"),
+					},
+					{command: []string{"FT.SEARCH", "SearchIdx", "*", "LIMIT", "0"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleFTAggregate", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. FT.AGGREGATE groups and sorts the matching hashes",
+				commands: []command{
+					{
+						command:          []string{"FT.CREATE", "AggregateIdx", "PREFIX", "1", "sale:", "SCHEMA", "city", "TAG", "amount", "NUMERIC"},
+						expectedResponse: "OK",
+					},
+					{command: []string{"HSET", "sale:1", "city", "London", "amount", "10", "item", "tea"}, expectedResponse: 3},
+					{command: []string{"HSET", "sale:2", "city", "Paris", "amount", "25", "item", "coffee"}, expectedResponse: 3},
+					{command: []string{"HSET", "sale:3", "city", "London", "amount", "30", "item", "coffee"}, expectedResponse: 3},
+					{command: []string{"HSET", "sale:4", "city", "Berlin", "amount", "5", "item", "tea"}, expectedResponse: 3},
+					{
+						command: []string{
+							"FT.AGGREGATE", "AggregateIdx", "*", "GROUPBY", "1", "@city",
+							"REDUCE", "COUNT", "0", "AS", "sales", "REDUCE", "SUM", "1", "@amount", "AS", "total",
+							"SORTBY", "2", "@total", "DESC",
+						},
+						expectedResponse: []string{
+							"3",
+							"city", "London", "sales", "2", "total", "40",
+							"city", "Paris", "sales", "1", "total", "25",
+							"city", "Berlin", "sales", "1", "total", "5",
+						},
+					},
+					{
+						command: []string{
+							"FT.AGGREGATE", "AggregateIdx", "@amount:[10 +inf]", "LOAD", "1", "@item", "GROUPBY", "1", "@item",
+							"REDUCE", "AVG", "1", "@amount", "REDUCE", "MAX", "1", "@amount", "AS", "max",
+							"REDUCE", "COUNT_DISTINCT", "1", "@city", "AS", "cities", "SORTBY", "1", "@item",
+						},
+						expectedResponse: []string{
+							"2",
+							"item", "coffee", "__generated_aliasavgamount", "27.5", "max", "30", "cities", "2",
+							"item", "tea", "__generated_aliasavgamount", "10", "max", "10", "cities", "1",
+						},
+					},
+					{
+						command: []string{
+							"FT.AGGREGATE", "AggregateIdx", "@city:{london}", "LOAD", "2", "@__key", "@amount",
+							"SORTBY", "2", "@amount", "DESC", "LIMIT", "0", "1",
+						},
+						expectedResponse: []string{"2", "__key", "sale:3", "amount", "30"},
+					},
+					{
+						command:          []string{"FT.AGGREGATE", "AggregateIdx", "@amount:[0 5]", "LOAD", "*"},
+						expectedResponse: []string{"1", "amount", "5", "city", "Berlin", "item", "tea"},
+					},
+				},
+			},
+			{
+				name: "2. FT.AGGREGATE rejects invalid pipelines",
+				commands: []command{
+					{
+						command:       []string{"FT.AGGREGATE", "AggregateIdx", "*", "GROUPBY", "1", "city"},
+						expectedError: errors.New("property city must start with @"),
+					},
+					{
+						command:       []string{"FT.AGGREGATE", "AggregateIdx", "*", "GROUPBY", "1", "@city", "REDUCE", "MEDIAN", "1", "@amount"},
+						expectedError: errors.New("unknown reducer MEDIAN"),
+					},
+					{
+						command:       []string{"FT.AGGREGATE", "AggregateIdx", "*", "GROUPBY", "1", "@city", "REDUCE", "SUM", "0"},
+						expectedError: errors.New("SUM takes exactly one argument"),
+					},
+					{
+						command:       []string{"FT.AGGREGATE", "AggregateIdx", "*", "APPLY", "@amount * 2"},
+						expectedError: errors.New("unknown option APPLY"),
+					},
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,248 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Engine holds the indexes of each database. The server keeps the indexes up to date by passing every write and
+// deletion in the keyspace to Update and Remove.
+type Engine struct {
+	mut     sync.RWMutex
+	indexes map[int]map[string]*Index
+}
+
+func NewEngine() *Engine {
+	return &Engine{
+		mut:     sync.RWMutex{},
+		indexes: make(map[int]map[string]*Index),
+	}
+}
+
+// CreateIndex adds an empty index to the database. The hashes that already exist are added with AddKey.
+func (engine *Engine) CreateIndex(database int, definition Definition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+	engine.mut.Lock()
+	defer engine.mut.Unlock()
+	if engine.indexes[database] == nil {
+		engine.indexes[database] = make(map[string]*Index)
+	}
+	if _, exists := engine.indexes[database][definition.Name]; exists {
+		return fmt.Errorf("index %s already exists", definition.Name)
+	}
+	engine.indexes[database][definition.Name] = newIndex(definition)
+	return nil
+}
+
+// DropIndex removes the index from the database and returns the keys of the documents it held.
+func (engine *Engine) DropIndex(database int, name string) ([]string, error) {
+	engine.mut.Lock()
+	defer engine.mut.Unlock()
+	index, ok := engine.indexes[database][name]
+	if !ok {
+		return nil, fmt.Errorf("unknown index %s", name)
+	}
+	delete(engine.indexes[database], name)
+	return index.sortedKeys(), nil
+}
+
+// Indexes returns the names of the indexes of the database in order.
+func (engine *Engine) Indexes(database int) []string {
+	engine.mut.RLock()
+	defer engine.mut.RUnlock()
+	names := make([]string, 0, len(engine.indexes[database]))
+	for name := range engine.indexes[database] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Info describes the index.
+func (engine *Engine) Info(database int, name string) (Info, error) {
+	engine.mut.RLock()
+	defer engine.mut.RUnlock()
+	index, ok := engine.indexes[database][name]
+	if !ok {
+		return Info{}, fmt.Errorf("unknown index %s", name)
+	}
+	return index.info(), nil
+}
+
+// AddKey indexes the value at the key in a single index. It's used to add the existing hashes to a new index.
+func (engine *Engine) AddKey(database int, name string, key string, value interface{}) {
+	engine.mut.Lock()
+	defer engine.mut.Unlock()
+	if index, ok := engine.indexes[database][name]; ok {
+		index.update(key, value)
+	}
+}
+
+// Update indexes the value written at the key in every index of the database.
+func (engine *Engine) Update(database int, key string, value interface{}) {
+	engine.mut.Lock()
+	defer engine.mut.Unlock()
+	for _, index := range engine.indexes[database] {
+		index.update(key, value)
+	}
+}
+
+// Remove removes the deleted key from every index of the database.
+func (engine *Engine) Remove(database int, key string) {
+	engine.mut.Lock()
+	defer engine.mut.Unlock()
+	for _, index := range engine.indexes[database] {
+		index.remove(key)
+	}
+}
+
+// Flush removes all the documents from the indexes of the database, or of all the databases when it's -1.
+// The indexes themselves are kept.
+func (engine *Engine) Flush(database int) {
+	engine.mut.Lock()
+	defer engine.mut.Unlock()
+	for db, indexes := range engine.indexes {
+		if database != -1 && db != database {
+			continue
+		}
+		for _, index := range indexes {
+			index.clear()
+		}
+	}
+}
+
+// SearchOptions modifies the results of Search.
+//
+// SortBy is the field the results are sorted by. Documents without the field are sorted last. By default,
+// the results are sorted by their score, from the highest to the lowest.
+//
+// Offset and Limit select the page of results. A negative Limit returns all the results after Offset.
+type SearchOptions struct {
+	SortBy     string
+	Descending bool
+	Offset     int
+	Limit      int
+}
+
+// Hit is a document matched by a query. Values holds the indexed values of the document by field alias.
+type Hit struct {
+	Key    string
+	Score  float64
+	Values map[string]string
+}
+
+// Search returns the total number of documents of the index that match the query, and the page of them
+// selected by the options.
+func (engine *Engine) Search(database int, name string, query string, options SearchOptions) (int, []Hit, error) {
+	engine.mut.RLock()
+	defer engine.mut.RUnlock()
+	index, ok := engine.indexes[database][name]
+	if !ok {
+		return 0, nil, fmt.Errorf("unknown index %s", name)
+	}
+	root, err := parseQuery(index, query)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var field *Field
+	if options.SortBy != "" {
+		if field, ok = index.fields[options.SortBy]; !ok {
+			return 0, nil, fmt.Errorf("unknown field %s", options.SortBy)
+		}
+	}
+
+	res := root.eval(index)
+	hits := make([]Hit, 0, len(res))
+	for key, score := range res {
+		hits = append(hits, Hit{Key: key, Score: score})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if field != nil {
+			if c := index.compareField(field, a.Key, b.Key, options.Descending); c != 0 {
+				return c
+			}
+		} else if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	total := len(hits)
+	start := min(max(options.Offset, 0), total)
+	end := total
+	if options.Limit >= 0 {
+		end = min(start+options.Limit, total)
+	}
+	hits = hits[start:end]
+	for i := range hits {
+		values := make(map[string]string, len(index.docs[hits[i].Key].values))
+		for alias, value := range index.docs[hits[i].Key].values {
+			values[alias] = value
+		}
+		hits[i].Values = values
+	}
+	return total, hits, nil
+}
+
+// compareField compares the values of the field in the documents at the keys. Numeric fields are compared by
+// value and the other fields by their lowercase values. Documents without the field are sorted last.
+func (index *Index) compareField(field *Field, a, b string, descending bool) int {
+	var c int
+	if field.Type == FieldNumeric {
+		x, xok := index.docs[a].numbers[field.Alias]
+		y, yok := index.docs[b].numbers[field.Alias]
+		switch {
+		case !xok || !yok:
+			return compareMissing(xok, yok)
+		case x < y:
+			c = -1
+		case x > y:
+			c = 1
+		}
+	} else {
+		x, xok := index.docs[a].values[field.Alias]
+		y, yok := index.docs[b].values[field.Alias]
+		if !xok || !yok {
+			return compareMissing(xok, yok)
+		}
+		c = strings.Compare(strings.ToLower(x), strings.ToLower(y))
+	}
+	if descending {
+		return -c
+	}
+	return c
+}
+
+func compareMissing(aok, bok bool) int {
+	switch {
+	case aok == bok:
+		return 0
+	case aok:
+		return -1
+	default:
+		return 1
+	}
+}
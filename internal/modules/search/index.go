@@ -0,0 +1,327 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/echovault/sugardb/internal/modules/hash"
+)
+
+// The types of the fields of an index.
+const (
+	FieldText    = "TEXT"    // The words of the value can be searched.
+	FieldTag     = "TAG"     // The value is a list of tags that are matched exactly.
+	FieldNumeric = "NUMERIC" // The value is a number that is matched by ranges.
+)
+
+// DefaultSeparator separates the tags of a TAG field when the field doesn't set its own separator.
+const DefaultSeparator = ","
+
+// Field is a hash field included in an index. Alias is the name of the field in queries, which defaults to Name.
+// Sortable is accepted for compatibility, every field of an index can be sorted by.
+type Field struct {
+	Name          string
+	Alias         string
+	Type          string
+	Sortable      bool
+	Separator     string
+	CaseSensitive bool
+}
+
+// Definition describes an index of the hashes whose keys start with one of the prefixes.
+// An index without prefixes includes every hash in the database.
+type Definition struct {
+	Name     string
+	Prefixes []string
+	Fields   []Field
+}
+
+// Validate checks the fields of the definition and fills in the default aliases and separators.
+func (definition *Definition) Validate() error {
+	if len(definition.Fields) == 0 {
+		return errors.New("the schema must have at least one field")
+	}
+	aliases := make(map[string]bool, len(definition.Fields))
+	for i := range definition.Fields {
+		field := &definition.Fields[i]
+		if field.Alias == "" {
+			field.Alias = field.Name
+		}
+		field.Type = strings.ToUpper(field.Type)
+		if !slices.Contains([]string{FieldText, FieldTag, FieldNumeric}, field.Type) {
+			return fmt.Errorf("unknown field type %s", field.Type)
+		}
+		if field.Type == FieldTag && field.Separator == "" {
+			field.Separator = DefaultSeparator
+		}
+		if aliases[field.Alias] {
+			return fmt.Errorf("duplicate field %s", field.Alias)
+		}
+		aliases[field.Alias] = true
+	}
+	return nil
+}
+
+type numericEntry struct {
+	value float64
+	key   string
+}
+
+func compareNumericEntries(a, b numericEntry) int {
+	if a.value != b.value {
+		if a.value < b.value {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a.key, b.key)
+}
+
+// document holds the indexed values of a hash.
+type document struct {
+	values  map[string]string         // The values of the fields by alias.
+	numbers map[string]float64        // The values of the numeric fields.
+	terms   map[string]map[string]int // The frequency of each term of the text fields.
+	tags    map[string][]string       // The tags of the tag fields.
+}
+
+// Index holds the inverted indexes of the hashes that match its definition.
+type Index struct {
+	definition Definition
+	fields     map[string]*Field
+	docs       map[string]*document
+	terms      map[string]map[string]map[string]int      // The keys and frequencies of each term of a text field.
+	tags       map[string]map[string]map[string]struct{} // The keys with each tag of a tag field.
+	numbers    map[string][]numericEntry                 // The values of a numeric field, sorted.
+}
+
+func newIndex(definition Definition) *Index {
+	index := &Index{
+		definition: definition,
+		fields:     make(map[string]*Field, len(definition.Fields)),
+		docs:       make(map[string]*document),
+		terms:      make(map[string]map[string]map[string]int),
+		tags:       make(map[string]map[string]map[string]struct{}),
+		numbers:    make(map[string][]numericEntry),
+	}
+	for i := range index.definition.Fields {
+		field := &index.definition.Fields[i]
+		index.fields[field.Alias] = field
+		switch field.Type {
+		case FieldText:
+			index.terms[field.Alias] = make(map[string]map[string]int)
+		case FieldTag:
+			index.tags[field.Alias] = make(map[string]map[string]struct{})
+		}
+	}
+	return index
+}
+
+// matches checks whether the key starts with one of the prefixes of the index.
+func (index *Index) matches(key string) bool {
+	if len(index.definition.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range index.definition.Prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// update indexes the value at the key. Values that are not hashes are removed from the index,
+// as the key no longer holds a document.
+func (index *Index) update(key string, value interface{}) {
+	index.remove(key)
+	h, ok := value.(*hash.Hash)
+	if !ok || !index.matches(key) {
+		return
+	}
+
+	doc := &document{
+		values:  make(map[string]string),
+		numbers: make(map[string]float64),
+		terms:   make(map[string]map[string]int),
+		tags:    make(map[string][]string),
+	}
+	for _, field := range index.definition.Fields {
+		v := h.Get(field.Name)
+		if v.Value == nil {
+			continue
+		}
+		s := formatValue(v.Value)
+		switch field.Type {
+		case FieldText:
+			frequencies := make(map[string]int)
+			for _, term := range Tokenize(s) {
+				frequencies[term]++
+			}
+			for term, n := range frequencies {
+				if index.terms[field.Alias][term] == nil {
+					index.terms[field.Alias][term] = make(map[string]int)
+				}
+				index.terms[field.Alias][term][key] = n
+			}
+			doc.terms[field.Alias] = frequencies
+		case FieldTag:
+			tags := splitTags(s, field.Separator, field.CaseSensitive)
+			for _, tag := range tags {
+				if index.tags[field.Alias][tag] == nil {
+					index.tags[field.Alias][tag] = make(map[string]struct{})
+				}
+				index.tags[field.Alias][tag][key] = struct{}{}
+			}
+			doc.tags[field.Alias] = tags
+		case FieldNumeric:
+			n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err != nil || math.IsNaN(n) {
+				// Values that are not numbers are left out of the numeric index.
+				continue
+			}
+			entry := numericEntry{value: n, key: key}
+			entries := index.numbers[field.Alias]
+			i, _ := slices.BinarySearchFunc(entries, entry, compareNumericEntries)
+			index.numbers[field.Alias] = slices.Insert(entries, i, entry)
+			doc.numbers[field.Alias] = n
+		}
+		doc.values[field.Alias] = s
+	}
+	index.docs[key] = doc
+}
+
+// remove deletes the key from the index.
+func (index *Index) remove(key string) {
+	doc, ok := index.docs[key]
+	if !ok {
+		return
+	}
+	for alias, frequencies := range doc.terms {
+		for term := range frequencies {
+			delete(index.terms[alias][term], key)
+			if len(index.terms[alias][term]) == 0 {
+				delete(index.terms[alias], term)
+			}
+		}
+	}
+	for alias, tags := range doc.tags {
+		for _, tag := range tags {
+			delete(index.tags[alias][tag], key)
+			if len(index.tags[alias][tag]) == 0 {
+				delete(index.tags[alias], tag)
+			}
+		}
+	}
+	for alias, n := range doc.numbers {
+		entries := index.numbers[alias]
+		if i, found := slices.BinarySearchFunc(entries, numericEntry{value: n, key: key}, compareNumericEntries); found {
+			index.numbers[alias] = slices.Delete(entries, i, i+1)
+		}
+	}
+	delete(index.docs, key)
+}
+
+// clear removes all the documents from the index.
+func (index *Index) clear() {
+	fresh := newIndex(index.definition)
+	index.docs, index.terms, index.tags, index.numbers = fresh.docs, fresh.terms, fresh.tags, fresh.numbers
+}
+
+// Info describes an index.
+//
+// NumTerms is the number of distinct terms of the text fields, and NumRecords is the number of entries of the
+// inverted indexes.
+type Info struct {
+	Definition Definition
+	NumDocs    int
+	NumTerms   int
+	NumRecords int
+}
+
+func (index *Index) info() Info {
+	info := Info{Definition: index.definition, NumDocs: len(index.docs)}
+	for _, terms := range index.terms {
+		info.NumTerms += len(terms)
+		for _, keys := range terms {
+			info.NumRecords += len(keys)
+		}
+	}
+	for _, tags := range index.tags {
+		for _, keys := range tags {
+			info.NumRecords += len(keys)
+		}
+	}
+	for _, entries := range index.numbers {
+		info.NumRecords += len(entries)
+	}
+	return info
+}
+
+// Tokenize splits the text into lowercase terms. Terms are made of letters, digits and underscores.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isTermRune(r)
+	})
+}
+
+func isTermRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+func splitTags(value, separator string, caseSensitive bool) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, separator) {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if !caseSensitive {
+			tag = strings.ToLower(tag)
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// sortedKeys returns the keys of the documents in order.
+func (index *Index) sortedKeys() []string {
+	keys := make([]string, 0, len(index.docs))
+	for key := range index.docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,37 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"errors"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// indexKeyFunc returns the key extraction function of a command that has at least minLength arguments, including
+// the command name. The commands access indexes rather than keys, so no keys are returned.
+func indexKeyFunc(minLength int) internal.KeyExtractionFunc {
+	return func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+		if len(cmd) < minLength {
+			return internal.KeyExtractionFuncResult{}, errors.New(constants.WrongArgsResponse)
+		}
+		return internal.KeyExtractionFuncResult{
+			Channels:  make([]string, 0),
+			ReadKeys:  make([]string, 0),
+			WriteKeys: make([]string, 0),
+		}, nil
+	}
+}
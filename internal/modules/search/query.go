@@ -0,0 +1,486 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// matches maps the keys of the documents matched by a query to their scores.
+type matches map[string]float64
+
+// node is a node of a parsed query.
+type node interface {
+	eval(index *Index) matches
+}
+
+// allNode matches every document of the index.
+type allNode struct{}
+
+func (allNode) eval(index *Index) matches {
+	res := make(matches, len(index.docs))
+	for key := range index.docs {
+		res[key] = 0
+	}
+	return res
+}
+
+// termNode matches the documents with the term in any of the text fields.
+// The score of a document is the TF-IDF of the term.
+type termNode struct {
+	fields []string
+	term   string
+	prefix bool
+}
+
+func (n termNode) eval(index *Index) matches {
+	res := make(matches)
+	for _, field := range n.fields {
+		for term, keys := range index.terms[field] {
+			if term != n.term && !(n.prefix && strings.HasPrefix(term, n.term)) {
+				continue
+			}
+			idf := math.Log(1 + float64(len(index.docs))/float64(len(keys)))
+			for key, frequency := range keys {
+				res[key] += float64(frequency) * idf
+			}
+		}
+	}
+	return res
+}
+
+type tagValue struct {
+	value  string
+	prefix bool
+}
+
+// tagNode matches the documents with any of the tags.
+type tagNode struct {
+	field string
+	tags  []tagValue
+}
+
+func (n tagNode) eval(index *Index) matches {
+	res := make(matches)
+	for tag, keys := range index.tags[n.field] {
+		for _, t := range n.tags {
+			if tag == t.value || (t.prefix && strings.HasPrefix(tag, t.value)) {
+				for key := range keys {
+					res[key] = 0
+				}
+				break
+			}
+		}
+	}
+	return res
+}
+
+// rangeNode matches the documents whose numeric field is in the range.
+type rangeNode struct {
+	field                      string
+	min, max                   float64
+	minExclusive, maxExclusive bool
+}
+
+func (n rangeNode) eval(index *Index) matches {
+	res := make(matches)
+	entries := index.numbers[n.field]
+	// Find the first entry that's not below the minimum, then scan until the maximum.
+	lo, hi := 0, len(entries)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if entries[mid].value < n.min {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	for _, entry := range entries[lo:] {
+		if entry.value > n.max || (n.maxExclusive && entry.value == n.max) {
+			break
+		}
+		if n.minExclusive && entry.value == n.min {
+			continue
+		}
+		res[entry.key] = 0
+	}
+	return res
+}
+
+// intersectNode matches the documents matched by all of its nodes. The scores of the nodes are added up.
+type intersectNode struct {
+	nodes []node
+}
+
+func (n intersectNode) eval(index *Index) matches {
+	var res matches
+	var excluded []matches
+	for _, child := range n.nodes {
+		if not, ok := child.(notNode); ok {
+			excluded = append(excluded, not.node.eval(index))
+			continue
+		}
+		m := child.eval(index)
+		if res == nil {
+			res = m
+			continue
+		}
+		for key, score := range res {
+			if s, ok := m[key]; ok {
+				res[key] = score + s
+			} else {
+				delete(res, key)
+			}
+		}
+	}
+	// A query made only of negations matches every document that's not excluded.
+	if res == nil {
+		res = allNode{}.eval(index)
+	}
+	for _, m := range excluded {
+		for key := range m {
+			delete(res, key)
+		}
+	}
+	return res
+}
+
+// unionNode matches the documents matched by any of its nodes.
+type unionNode struct {
+	nodes []node
+}
+
+func (n unionNode) eval(index *Index) matches {
+	res := make(matches)
+	for _, child := range n.nodes {
+		for key, score := range child.eval(index) {
+			res[key] += score
+		}
+	}
+	return res
+}
+
+// notNode matches the documents that are not matched by its node.
+type notNode struct {
+	node node
+}
+
+func (n notNode) eval(index *Index) matches {
+	return intersectNode{nodes: []node{n}}.eval(index)
+}
+
+// parser parses queries against the fields of an index.
+//
+// Terms separated by spaces must all match, while | separates alternatives and - negates the expression after it.
+// Parentheses group expressions. A field is queried with @field: followed by a term or a group of terms for text
+// fields, {tag | tag} for tag fields and [min max] for numeric fields. The bounds of a range are inclusive unless
+// they're preceded by (, and can be -inf or +inf. A term or tag that ends with * matches by prefix. A query of *
+// matches every document.
+type parser struct {
+	index *Index
+	query string
+	pos   int
+}
+
+func parseQuery(index *Index, query string) (node, error) {
+	p := &parser{index: index, query: query}
+	p.skipSpaces()
+	if p.done() {
+		return nil, errors.New("empty query")
+	}
+	n, err := p.parseUnion(nil)
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.syntaxError()
+	}
+	return n, nil
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.query)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.query[p.pos]
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && (p.query[p.pos] == ' ' || p.query[p.pos] == '\t' || p.query[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *parser) syntaxError() error {
+	if p.done() {
+		return errors.New("unexpected end of query")
+	}
+	return fmt.Errorf("syntax error at offset %d near %s", p.pos, p.query[p.pos:])
+}
+
+func (p *parser) parseUnion(field *Field) (node, error) {
+	var nodes []node
+	for {
+		n, err := p.parseIntersect(field)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+		p.skipSpaces()
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return unionNode{nodes: nodes}, nil
+}
+
+func (p *parser) parseIntersect(field *Field) (node, error) {
+	var nodes []node
+	for {
+		p.skipSpaces()
+		if p.done() || p.peek() == '|' || p.peek() == ')' {
+			break
+		}
+		n, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, p.syntaxError()
+	case 1:
+		if _, ok := nodes[0].(notNode); !ok {
+			return nodes[0], nil
+		}
+	}
+	return intersectNode{nodes: nodes}, nil
+}
+
+func (p *parser) parseUnary(field *Field) (node, error) {
+	if p.peek() == '-' {
+		p.pos++
+		n, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node: n}, nil
+	}
+	return p.parseAtom(field)
+}
+
+func (p *parser) parseAtom(field *Field) (node, error) {
+	switch p.peek() {
+	case '(':
+		p.pos++
+		n, err := p.parseUnion(field)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, errors.New("missing ) in query")
+		}
+		p.pos++
+		return n, nil
+	case '@':
+		p.pos++
+		return p.parseField()
+	case '*':
+		if p.pos+1 == len(p.query) || strings.IndexByte(" \t\n|)", p.query[p.pos+1]) >= 0 {
+			p.pos++
+			return allNode{}, nil
+		}
+	}
+
+	term, prefix := p.readTerm()
+	if term == "" {
+		return nil, p.syntaxError()
+	}
+	n := termNode{term: strings.ToLower(term), prefix: prefix}
+	if field != nil {
+		n.fields = []string{field.Alias}
+	} else {
+		for _, f := range p.index.definition.Fields {
+			if f.Type == FieldText {
+				n.fields = append(n.fields, f.Alias)
+			}
+		}
+	}
+	return n, nil
+}
+
+// readTerm reads the characters of a term, which are letters, digits, underscores and characters escaped with \.
+// It returns whether the term ends with *.
+func (p *parser) readTerm() (string, bool) {
+	var term strings.Builder
+	for !p.done() {
+		c := p.query[p.pos]
+		if c == '\\' && p.pos+1 < len(p.query) {
+			r, size := utf8.DecodeRuneInString(p.query[p.pos+1:])
+			term.WriteRune(r)
+			p.pos += 1 + size
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(p.query[p.pos:])
+		if !isTermRune(r) {
+			break
+		}
+		term.WriteRune(r)
+		p.pos += size
+	}
+	if term.Len() > 0 && p.peek() == '*' {
+		p.pos++
+		return term.String(), true
+	}
+	return term.String(), false
+}
+
+func (p *parser) parseField() (node, error) {
+	start := p.pos
+	name, prefix := p.readTerm()
+	if name == "" || prefix {
+		p.pos = start
+		return nil, p.syntaxError()
+	}
+	if p.peek() != ':' {
+		return nil, fmt.Errorf("missing : after field %s", name)
+	}
+	p.pos++
+	field, ok := p.index.fields[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %s", name)
+	}
+
+	switch c := p.peek(); {
+	case c == '[':
+		if field.Type != FieldNumeric {
+			return nil, fmt.Errorf("field %s is not a numeric field", name)
+		}
+		p.pos++
+		return p.parseRange(field)
+	case c == '{':
+		if field.Type != FieldTag {
+			return nil, fmt.Errorf("field %s is not a tag field", name)
+		}
+		p.pos++
+		return p.parseTags(field)
+	case field.Type == FieldNumeric:
+		return nil, fmt.Errorf("numeric field %s must be queried with [min max]", name)
+	case field.Type == FieldTag:
+		return nil, fmt.Errorf("tag field %s must be queried with {tag}", name)
+	}
+	return p.parseUnary(field)
+}
+
+func (p *parser) parseRange(field *Field) (node, error) {
+	n := rangeNode{field: field.Alias}
+	var err error
+	p.skipSpaces()
+	if n.min, n.minExclusive, err = p.parseBound(); err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if n.max, n.maxExclusive, err = p.parseBound(); err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.peek() != ']' {
+		return nil, errors.New("missing ] in query")
+	}
+	p.pos++
+	return n, nil
+}
+
+func (p *parser) parseBound() (float64, bool, error) {
+	start := p.pos
+	for !p.done() && strings.IndexByte(" \t\n]", p.query[p.pos]) < 0 {
+		p.pos++
+	}
+	bound := p.query[start:p.pos]
+	if bound == "" {
+		return 0, false, errors.New("missing ] in query")
+	}
+	exclusive := strings.HasPrefix(bound, "(")
+	s := strings.TrimPrefix(bound, "(")
+	switch strings.ToLower(s) {
+	case "-inf":
+		return math.Inf(-1), exclusive, nil
+	case "+inf", "inf":
+		return math.Inf(1), exclusive, nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(n) {
+		return 0, false, fmt.Errorf("invalid range bound %s in query", bound)
+	}
+	return n, exclusive, nil
+}
+
+func (p *parser) parseTags(field *Field) (node, error) {
+	n := tagNode{field: field.Alias}
+	var tag strings.Builder
+	var escaped bool
+	for {
+		if p.done() {
+			return nil, errors.New("missing } in query")
+		}
+		c := p.query[p.pos]
+		p.pos++
+		if c == '\\' && !p.done() {
+			tag.WriteByte(p.query[p.pos])
+			p.pos++
+			escaped = true
+			continue
+		}
+		if c != '|' && c != '}' {
+			tag.WriteByte(c)
+			if c != ' ' {
+				escaped = false
+			}
+			continue
+		}
+
+		value := strings.TrimSpace(tag.String())
+		prefix := !escaped && strings.HasSuffix(value, "*")
+		if prefix {
+			value = strings.TrimSuffix(value, "*")
+		}
+		if value == "" {
+			return nil, errors.New("empty tag in query")
+		}
+		if !field.CaseSensitive {
+			value = strings.ToLower(value)
+		}
+		n.tags = append(n.tags, tagValue{value: value, prefix: prefix})
+		tag.Reset()
+		escaped = false
+		if c == '}' {
+			return n, nil
+		}
+	}
+}
@@ -567,6 +567,45 @@ func handleSUNIONSTORE(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(fmt.Sprintf(":%d\r\n", union.Cardinality())), nil
 }
 
+func handleSSCAN(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := sscanKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := internal.ParseScanCursor(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	match, count, err := internal.ParseScanOptions(params.Command[3:])
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	if !keyExists {
+		return internal.EncodeScanResponse(0, []string{}), nil
+	}
+
+	set, ok := params.GetValues(params.Context, []string{key})[key].(*Set)
+	if !ok {
+		return nil, fmt.Errorf("value at key %s is not a set", key)
+	}
+
+	next, members := set.Scan(cursor, count)
+
+	elements := make([]string, 0, len(members))
+	for _, member := range members {
+		if match == "" || internal.MatchPattern(match, member) {
+			elements = append(elements, member)
+		}
+	}
+
+	return internal.EncodeScanResponse(next, elements), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -664,6 +703,18 @@ Returns the cardinality of the intersection between multiple sets.`,
 			KeyExtractionFunc: smembersKeyFunc,
 			HandlerFunc:       handleSMEMBERS,
 		},
+		{
+			Command:    "sscan",
+			Module:     constants.SetModule,
+			Categories: []string{constants.SetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(SSCAN key cursor [MATCH pattern] [COUNT count])
+Incrementally iterates over the members of a set. Start the iteration with a cursor of 0 and
+continue with the cursor returned by each call until it returns 0.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: sscanKeyFunc,
+			HandlerFunc:       handleSSCAN,
+		},
 		{
 			Command:           "smismember",
 			Module:            constants.SetModule,
@@ -16,6 +16,7 @@ package set_test
 
 import (
 	"errors"
+	"fmt"
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/config"
 	"github.com/echovault/sugardb/internal/constants"
@@ -1399,6 +1400,193 @@ func Test_Set(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_HandleSSCAN", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		var members []string
+		for i := 0; i < 40; i++ {
+			members = append(members, fmt.Sprintf("member%d", i))
+		}
+
+		tests := []struct {
+			name             string
+			key              string
+			presetValue      interface{}
+			cursor           string
+			args             []string
+			mutate           []string // Members added and removed between calls to SSCAN.
+			expectedResponse []string
+			expectedError    error
+		}{
+			{
+				name:             "1. Return all the members of the set",
+				key:              "SscanKey1",
+				presetValue:      set.NewSet([]string{"one", "two", "three", "four", "five"}),
+				args:             []string{},
+				expectedResponse: []string{"one", "two", "three", "four", "five"},
+				expectedError:    nil,
+			},
+			{
+				name:             "2. Iterate over a large set with a small count",
+				key:              "SscanKey2",
+				presetValue:      set.NewSet(members),
+				args:             []string{"COUNT", "3"},
+				expectedResponse: members,
+				expectedError:    nil,
+			},
+			{
+				name:        "3. Only return the members that match the pattern",
+				key:         "SscanKey3",
+				presetValue: set.NewSet(members),
+				args:        []string{"MATCH", "member3*", "COUNT", "6"},
+				expectedResponse: []string{"member3", "member30", "member31", "member32", "member33", "member34",
+					"member35", "member36", "member37", "member38", "member39"},
+				expectedError: nil,
+			},
+			{
+				name:             "4. Return every member exactly once while the set is modified",
+				key:              "SscanKey4",
+				presetValue:      set.NewSet(members),
+				args:             []string{"COUNT", "4", "MATCH", "member*"},
+				mutate:           []string{"added1", "added2", "added3", "added4", "added5", "added6"},
+				expectedResponse: members,
+				expectedError:    nil,
+			},
+			{
+				name:             "5. Empty response when the key does not exist",
+				key:              "SscanKey5",
+				presetValue:      nil,
+				args:             []string{},
+				expectedResponse: []string{},
+				expectedError:    nil,
+			},
+			{
+				name:          "6. Return error when the key is not a set",
+				key:           "SscanKey6",
+				presetValue:   "Default value",
+				args:          []string{},
+				expectedError: errors.New("value at key SscanKey6 is not a set"),
+			},
+			{
+				name:          "7. Return error when the cursor is invalid",
+				key:           "SscanKey7",
+				presetValue:   nil,
+				cursor:        "-1",
+				args:          []string{},
+				expectedError: errors.New("invalid cursor"),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if test.presetValue != nil {
+					var command []resp.Value
+					var expected string
+
+					switch test.presetValue.(type) {
+					case string:
+						command = []resp.Value{
+							resp.StringValue("SET"),
+							resp.StringValue(test.key),
+							resp.StringValue(test.presetValue.(string)),
+						}
+						expected = "ok"
+					case *set.Set:
+						command = []resp.Value{resp.StringValue("SADD"), resp.StringValue(test.key)}
+						for _, element := range test.presetValue.(*set.Set).GetAll() {
+							command = append(command, []resp.Value{resp.StringValue(element)}...)
+						}
+						expected = strconv.Itoa(test.presetValue.(*set.Set).Cardinality())
+					}
+
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if !strings.EqualFold(res.String(), expected) {
+						t.Errorf("expected preset response to be \"%s\", got %s", expected, res.String())
+					}
+				}
+
+				cursor := "0"
+				if test.cursor != "" {
+					cursor = test.cursor
+				}
+				var got []string
+				for i := 0; ; i++ {
+					command := []resp.Value{resp.StringValue("SSCAN"), resp.StringValue(test.key), resp.StringValue(cursor)}
+					for _, arg := range test.args {
+						command = append(command, resp.StringValue(arg))
+					}
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if test.expectedError != nil {
+						if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+							t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error())
+						}
+						return
+					}
+
+					for _, item := range res.Array()[1].Array() {
+						got = append(got, item.String())
+					}
+
+					if cursor = res.Array()[0].String(); cursor == "0" {
+						break
+					}
+
+					if i < len(test.mutate) {
+						// Add a new member and remove the one added in the previous iteration.
+						commands := [][]resp.Value{
+							{resp.StringValue("SADD"), resp.StringValue(test.key), resp.StringValue(test.mutate[i])},
+						}
+						if i > 0 {
+							commands = append(commands, []resp.Value{
+								resp.StringValue("SREM"), resp.StringValue(test.key), resp.StringValue(test.mutate[i-1]),
+							})
+						}
+						for _, command := range commands {
+							if err = client.WriteArray(command); err != nil {
+								t.Error(err)
+							}
+							if _, _, err = client.ReadValue(); err != nil {
+								t.Error(err)
+							}
+						}
+					}
+				}
+
+				if len(got) != len(test.expectedResponse) {
+					t.Errorf("expected response array of length \"%d\", got \"%d\"", len(test.expectedResponse), len(got))
+				}
+				for _, member := range test.expectedResponse {
+					if !slices.Contains(got, member) {
+						t.Errorf("expected member \"%s\" in response", member)
+					}
+				}
+			})
+		}
+	})
+
 	t.Run("Test_HandleSMISMEMBER", func(t *testing.T) {
 		t.Parallel()
 		conn, err := internal.GetConnection("localhost", port)
@@ -15,6 +15,7 @@
 package set
 
 import (
+	"encoding/binary"
 	"math/rand"
 	"slices"
 	"unsafe"
@@ -26,6 +27,8 @@ import (
 type Set struct {
 	members map[string]interface{}
 	length  int
+	// scanIndex keeps the members ordered by their scan position for SSCAN.
+	scanIndex *internal.ScanIndex[string]
 }
 
 func (set *Set) GetMem() int64 {
@@ -38,16 +41,43 @@ func (set *Set) GetMem() int64 {
 		size += int64(len(k))
 		size += int64(unsafe.Sizeof(v))
 	}
+	size += set.scanIndex.GetMem()
 	return size
 }
 
 // compile time interface check
 var _ constants.CompositeType = (*Set)(nil)
 
+// MarshalBinary encodes the members of the set in sorted order, so equal sets always encode to the same bytes.
+func (set *Set) MarshalBinary() ([]byte, error) {
+	members := set.GetAll()
+	slices.Sort(members)
+	b := binary.AppendUvarint(nil, uint64(len(members)))
+	for _, member := range members {
+		b = internal.AppendBinaryString(b, member)
+	}
+	return b, nil
+}
+
+func (set *Set) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	n := r.Count()
+	members := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		members = append(members, r.String())
+	}
+	if r.Err() != nil {
+		return r.Err()
+	}
+	*set = *NewSet(members)
+	return nil
+}
+
 func NewSet(elems []string) *Set {
 	set := &Set{
-		members: make(map[string]interface{}),
-		length:  0,
+		members:   make(map[string]interface{}),
+		length:    0,
+		scanIndex: internal.NewScanIndex[string](),
 	}
 	set.Add(elems)
 	return set
@@ -58,6 +88,7 @@ func (set *Set) Add(elems []string) int {
 	for _, e := range elems {
 		if !set.Contains(e) {
 			set.members[e] = struct{}{}
+			set.scanIndex.Insert(e)
 			count += 1
 		}
 	}
@@ -77,6 +108,11 @@ func (set *Set) GetAll() []string {
 	return res
 }
 
+// Scan returns up to count members starting from cursor, and the cursor to continue the iteration from.
+func (set *Set) Scan(cursor uint64, count int) (uint64, []string) {
+	return set.scanIndex.Scan(cursor, count)
+}
+
 func (set *Set) Cardinality() int {
 	return set.length
 }
@@ -124,6 +160,7 @@ func (set *Set) Remove(elems []string) int {
 	for _, e := range elems {
 		if set.get(e) != nil {
 			delete(set.members, e)
+			set.scanIndex.Delete(e)
 			count += 1
 		}
 	}
@@ -15,7 +15,6 @@
 package sorted_set
 
 import (
-	"cmp"
 	"errors"
 	"fmt"
 	"github.com/echovault/sugardb/internal"
@@ -24,6 +23,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func handleZADD(params internal.HandlerFuncParams) ([]byte, error) {
@@ -243,14 +243,9 @@ func handleZCOUNT(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, fmt.Errorf("value at %s is not a sorted set", key)
 	}
 
-	var members []MemberParam
-	for _, m := range set.GetAll() {
-		if m.Score >= minimum && m.Score <= maximum {
-			members = append(members, m)
-		}
-	}
+	start, end := set.ScoreRange(minimum, maximum)
 
-	return []byte(fmt.Sprintf(":%d\r\n", len(members))), nil
+	return []byte(fmt.Sprintf(":%d\r\n", end-start)), nil
 }
 
 func handleZLEXCOUNT(params internal.HandlerFuncParams) ([]byte, error) {
@@ -273,25 +268,14 @@ func handleZLEXCOUNT(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, fmt.Errorf("value at %s is not a sorted set", key)
 	}
 
-	members := set.GetAll()
-
 	// Check if all members has the same score
-	for i := 0; i < len(members)-2; i++ {
-		if members[i].Score != members[i+1].Score {
-			return []byte(":0\r\n"), nil
-		}
+	if !set.SingleScore() {
+		return []byte(":0\r\n"), nil
 	}
 
-	count := 0
+	start, end := set.LexRange(minimum, maximum)
 
-	for _, m := range members {
-		if slices.Contains([]int{1, 0}, internal.CompareLex(string(m.Value), minimum)) &&
-			slices.Contains([]int{-1, 0}, internal.CompareLex(string(m.Value), maximum)) {
-			count += 1
-		}
-	}
-
-	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+	return []byte(fmt.Sprintf(":%d\r\n", end-start)), nil
 }
 
 func handleZDIFF(params internal.HandlerFuncParams) ([]byte, error) {
@@ -674,6 +658,60 @@ func handleZPOP(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(res), nil
 }
 
+func handleBZPOP(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := bzpopKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := "min"
+	if strings.EqualFold(params.Command[0], "bzpopmax") {
+		policy = "max"
+	}
+
+	duration, err := internal.ParseBlockingTimeout(params.Command[len(params.Command)-1])
+	if err != nil {
+		return nil, err
+	}
+	var timeout <-chan time.Time
+	if duration > 0 {
+		timeout = params.GetClock().After(duration)
+	}
+
+	for {
+		// Register the wait before checking the sorted sets so that members added after the check wake the client.
+		wait, cancel := params.AwaitKeys(params.Context, keys.WriteKeys)
+
+		for _, key := range keys.WriteKeys {
+			if !params.KeysExist(params.Context, []string{key})[key] {
+				continue
+			}
+			set, ok := params.GetValues(params.Context, []string{key})[key].(*SortedSet)
+			if !ok {
+				cancel()
+				return nil, fmt.Errorf("value at key %s is not a sorted set", key)
+			}
+			if set.Cardinality() == 0 {
+				continue
+			}
+
+			cancel()
+			popped, err := set.Pop(1, policy)
+			if err != nil {
+				return nil, err
+			}
+			m := popped.GetAll()[0]
+			score := strconv.FormatFloat(float64(m.Score), 'f', -1, 64)
+			return []byte(fmt.Sprintf("*3\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n",
+				len(key), key, len(m.Value), m.Value, len(score), score)), nil
+		}
+
+		if !wait(timeout) {
+			return []byte("*-1\r\n"), nil
+		}
+	}
+}
+
 func handleZMSCORE(params internal.HandlerFuncParams) ([]byte, error) {
 	keys, err := zmscoreKeyFunc(params.Command)
 	if err != nil {
@@ -790,26 +828,19 @@ func handleZRANK(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, fmt.Errorf("value at %s is not a sorted set", key)
 	}
 
-	members := set.GetAll()
-	slices.SortFunc(members, func(a, b MemberParam) int {
-		if strings.EqualFold(params.Command[0], "zrevrank") {
-			return cmp.Compare(b.Score, a.Score)
-		}
-		return cmp.Compare(a.Score, b.Score)
-	})
-
-	for i := 0; i < len(members); i++ {
-		if members[i].Value == Value(member) {
-			if withscores {
-				score := strconv.FormatFloat(float64(members[i].Score), 'f', -1, 64)
-				return []byte(fmt.Sprintf("*2\r\n:%d\r\n$%d\r\n%s\r\n", i, len(score), score)), nil
-			} else {
-				return []byte(fmt.Sprintf("*1\r\n:%d\r\n", i)), nil
-			}
-		}
+	rank, ok := set.Rank(Value(member))
+	if !ok {
+		return []byte("$-1\r\n"), nil
+	}
+	if strings.EqualFold(params.Command[0], "zrevrank") {
+		rank = set.Cardinality() - 1 - rank
 	}
 
-	return []byte("$-1\r\n"), nil
+	if withscores {
+		score := strconv.FormatFloat(float64(set.Get(Value(member)).Score), 'f', -1, 64)
+		return []byte(fmt.Sprintf("*2\r\n:%d\r\n$%d\r\n%s\r\n", rank, len(score), score)), nil
+	}
+	return []byte(fmt.Sprintf("*1\r\n:%d\r\n", rank)), nil
 }
 
 func handleZREM(params internal.HandlerFuncParams) ([]byte, error) {
@@ -897,11 +928,10 @@ func handleZREMRANGEBYSCORE(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, fmt.Errorf("value at %s is not a sorted set", key)
 	}
 
-	for _, m := range set.GetAll() {
-		if m.Score >= Score(minimum) && m.Score <= Score(maximum) {
-			set.Remove(m.Value)
-			deletedCount += 1
-		}
+	start, end := set.ScoreRange(Score(minimum), Score(maximum))
+	for _, m := range set.RangeByRank(start, end-1, false) {
+		set.Remove(m.Value)
+		deletedCount += 1
 	}
 
 	return []byte(fmt.Sprintf(":%d\r\n", deletedCount)), nil
@@ -946,23 +976,11 @@ func handleZREMRANGEBYRANK(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, errors.New("indices out of bounds")
 	}
 
-	members := set.GetAll()
-	slices.SortFunc(members, func(a, b MemberParam) int {
-		return cmp.Compare(a.Score, b.Score)
-	})
-
 	deletedCount := 0
 
-	if start < stop {
-		for i := start; i <= stop; i++ {
-			set.Remove(members[i].Value)
-			deletedCount += 1
-		}
-	} else {
-		for i := stop; i <= start; i++ {
-			set.Remove(members[i].Value)
-			deletedCount += 1
-		}
+	for _, m := range set.RangeByRank(min(start, stop), max(start, stop), false) {
+		set.Remove(m.Value)
+		deletedCount += 1
 	}
 
 	return []byte(fmt.Sprintf(":%d\r\n", deletedCount)), nil
@@ -988,24 +1006,18 @@ func handleZREMRANGEBYLEX(params internal.HandlerFuncParams) ([]byte, error) {
 		return nil, fmt.Errorf("value at %s is not a sorted set", key)
 	}
 
-	members := set.GetAll()
-
 	// Check if all the members have the same score. If not, return 0
-	for i := 0; i < len(members)-1; i++ {
-		if members[i].Score != members[i+1].Score {
-			return []byte(":0\r\n"), nil
-		}
+	if !set.SingleScore() {
+		return []byte(":0\r\n"), nil
 	}
 
 	deletedCount := 0
 
 	// All the members have the same score
-	for _, m := range members {
-		if slices.Contains([]int{1, 0}, internal.CompareLex(string(m.Value), minimum)) &&
-			slices.Contains([]int{-1, 0}, internal.CompareLex(string(m.Value), maximum)) {
-			set.Remove(m.Value)
-			deletedCount += 1
-		}
+	start, end := set.LexRange(minimum, maximum)
+	for _, m := range set.RangeByRank(start, end-1, false) {
+		set.Remove(m.Value)
+		deletedCount += 1
 	}
 
 	return []byte(fmt.Sprintf(":%d\r\n", deletedCount)), nil
@@ -1090,48 +1102,19 @@ func handleZRANGE(params internal.HandlerFuncParams) ([]byte, error) {
 		count = set.Cardinality() - offset
 	}
 
-	members := set.GetAll()
+	var start, end int
 	if strings.EqualFold(policy, "byscore") {
-		slices.SortFunc(members, func(a, b MemberParam) int {
-			// Do a score sort
-			if reverse {
-				return cmp.Compare(b.Score, a.Score)
-			}
-			return cmp.Compare(a.Score, b.Score)
-		})
+		start, end = set.ScoreRange(Score(scoreStart), Score(scoreStop))
 	}
 	if strings.EqualFold(policy, "bylex") {
 		// If policy is BYLEX, all the elements must have the same score
-		for i := 0; i < len(members)-1; i++ {
-			if members[i].Score != members[i+1].Score {
-				return []byte("*0\r\n"), nil
-			}
+		if !set.SingleScore() {
+			return []byte("*0\r\n"), nil
 		}
-		slices.SortFunc(members, func(a, b MemberParam) int {
-			if reverse {
-				return internal.CompareLex(string(b.Value), string(a.Value))
-			}
-			return internal.CompareLex(string(a.Value), string(b.Value))
-		})
+		start, end = set.LexRange(lexStart, lexStop)
 	}
 
-	var resultMembers []MemberParam
-
-	for i := offset; i <= count; i++ {
-		if i >= len(members) {
-			break
-		}
-		if strings.EqualFold(policy, "byscore") {
-			if members[i].Score >= Score(scoreStart) && members[i].Score <= Score(scoreStop) {
-				resultMembers = append(resultMembers, members[i])
-			}
-			continue
-		}
-		if slices.Contains([]int{1, 0}, internal.CompareLex(string(members[i].Value), lexStart)) &&
-			slices.Contains([]int{-1, 0}, internal.CompareLex(string(members[i].Value), lexStop)) {
-			resultMembers = append(resultMembers, members[i])
-		}
-	}
+	resultMembers := rangeMembers(set, start, end, offset, count, reverse)
 
 	res := fmt.Sprintf("*%d", len(resultMembers))
 
@@ -1148,6 +1131,17 @@ func handleZRANGE(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(res), nil
 }
 
+// rangeMembers returns the members of the set with ranks between start and end that are also within the
+// positions offset to count of the ordered set. When reverse is true, the positions are counted from the end
+// of the set and the members are returned in descending order.
+func rangeMembers(set *SortedSet, start, end, offset, count int, reverse bool) []MemberParam {
+	if reverse {
+		// Convert the positions in the reversed set to ranks in the ascending set.
+		offset, count = set.Cardinality()-1-count, set.Cardinality()-1-offset
+	}
+	return set.RangeByRank(max(start, offset), min(end-1, count), reverse)
+}
+
 func handleZRANGESTORE(params internal.HandlerFuncParams) ([]byte, error) {
 	keys, err := zrangeStoreKeyFunc(params.Command)
 	if err != nil {
@@ -1223,48 +1217,19 @@ func handleZRANGESTORE(params internal.HandlerFuncParams) ([]byte, error) {
 		count = set.Cardinality() - offset
 	}
 
-	members := set.GetAll()
+	var start, end int
 	if strings.EqualFold(policy, "byscore") {
-		slices.SortFunc(members, func(a, b MemberParam) int {
-			// Do a score sort
-			if reverse {
-				return cmp.Compare(b.Score, a.Score)
-			}
-			return cmp.Compare(a.Score, b.Score)
-		})
+		start, end = set.ScoreRange(Score(scoreStart), Score(scoreStop))
 	}
 	if strings.EqualFold(policy, "bylex") {
 		// If policy is BYLEX, all the elements must have the same score
-		for i := 0; i < len(members)-1; i++ {
-			if members[i].Score != members[i+1].Score {
-				return []byte(":0\r\n"), nil
-			}
+		if !set.SingleScore() {
+			return []byte(":0\r\n"), nil
 		}
-		slices.SortFunc(members, func(a, b MemberParam) int {
-			if reverse {
-				return internal.CompareLex(string(b.Value), string(a.Value))
-			}
-			return internal.CompareLex(string(a.Value), string(b.Value))
-		})
+		start, end = set.LexRange(lexStart, lexStop)
 	}
 
-	var resultMembers []MemberParam
-
-	for i := offset; i <= count; i++ {
-		if i >= len(members) {
-			break
-		}
-		if strings.EqualFold(policy, "byscore") {
-			if members[i].Score >= Score(scoreStart) && members[i].Score <= Score(scoreStop) {
-				resultMembers = append(resultMembers, members[i])
-			}
-			continue
-		}
-		if slices.Contains([]int{1, 0}, internal.CompareLex(string(members[i].Value), lexStart)) &&
-			slices.Contains([]int{-1, 0}, internal.CompareLex(string(members[i].Value), lexStop)) {
-			resultMembers = append(resultMembers, members[i])
-		}
-	}
+	resultMembers := rangeMembers(set, start, end, offset, count, reverse)
 
 	newSortedSet := NewSortedSet(resultMembers)
 	if err = params.SetValues(params.Context, map[string]interface{}{
@@ -1276,6 +1241,189 @@ func handleZRANGESTORE(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(fmt.Sprintf(":%d\r\n", newSortedSet.Cardinality())), nil
 }
 
+func handleZRANGEBYSCORE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := zrangebyscoreKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	// ZREVRANGEBYSCORE takes the max bound before the min bound.
+	reverse := strings.EqualFold(params.Command[0], "zrevrangebyscore")
+	minArg, maxArg := params.Command[2], params.Command[3]
+	if reverse {
+		minArg, maxArg = maxArg, minArg
+	}
+
+	minimum, err := parseScoreBound(minArg, true)
+	if err != nil {
+		return nil, err
+	}
+	maximum, err := parseScoreBound(maxArg, false)
+	if err != nil {
+		return nil, err
+	}
+
+	withscores, offset, count, err := parseRangeByOptions(params.Command[4:], true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !keyExists {
+		return []byte("*0\r\n"), nil
+	}
+
+	set, ok := params.GetValues(params.Context, []string{key})[key].(*SortedSet)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a sorted set", key)
+	}
+
+	start, end := set.ScoreRange(minimum, maximum)
+
+	return encodeMemberArray(limitRange(set, start, end, offset, count, reverse), withscores), nil
+}
+
+func handleZRANGEBYLEX(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := zrangebylexKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	_, offset, count, err := parseRangeByOptions(params.Command[4:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !keyExists {
+		return []byte("*0\r\n"), nil
+	}
+
+	set, ok := params.GetValues(params.Context, []string{key})[key].(*SortedSet)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a sorted set", key)
+	}
+
+	start, end, err := lexRange(set, params.Command[2], params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+
+	// All the members must have the same score for a lexicographical range.
+	if !set.SingleScore() {
+		return []byte("*0\r\n"), nil
+	}
+
+	return encodeMemberArray(limitRange(set, start, end, offset, count, false), false), nil
+}
+
+func handleZREVRANGE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := zrevrangeKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	start, err := strconv.Atoi(params.Command[2])
+	if err != nil {
+		return nil, errors.New("start index must be an integer")
+	}
+	stop, err := strconv.Atoi(params.Command[3])
+	if err != nil {
+		return nil, errors.New("stop index must be an integer")
+	}
+
+	withscores := false
+	if len(params.Command) == 5 {
+		if !strings.EqualFold(params.Command[4], "withscores") {
+			return nil, fmt.Errorf("unknown option %s", strings.ToUpper(params.Command[4]))
+		}
+		withscores = true
+	}
+
+	if !keyExists {
+		return []byte("*0\r\n"), nil
+	}
+
+	set, ok := params.GetValues(params.Context, []string{key})[key].(*SortedSet)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a sorted set", key)
+	}
+
+	// Indices count from the member with the highest score. Negative indices count from the lowest score.
+	cardinality := set.Cardinality()
+	if start < 0 {
+		start += cardinality
+	}
+	if stop < 0 {
+		stop += cardinality
+	}
+	start = max(start, 0)
+	if start > stop || start >= cardinality {
+		return []byte("*0\r\n"), nil
+	}
+
+	members := set.RangeByRank(cardinality-1-stop, cardinality-1-start, true)
+
+	return encodeMemberArray(members, withscores), nil
+}
+
+func handleZINTERCARD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := zintercardKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 0
+	if args := params.Command[2+len(keys.ReadKeys):]; len(args) > 0 {
+		if len(args) != 2 || !strings.EqualFold(args[0], "limit") {
+			return nil, errors.New(constants.InvalidCmdResponse)
+		}
+		if limit, err = strconv.Atoi(args[1]); err != nil || limit < 0 {
+			return nil, errors.New("LIMIT can't be negative")
+		}
+	}
+
+	var sets []*SortedSet
+	keysExist := params.KeysExist(params.Context, keys.ReadKeys)
+	values := params.GetValues(params.Context, keys.ReadKeys)
+	for _, key := range keys.ReadKeys {
+		if !keysExist[key] {
+			// The intersection with an empty set is empty.
+			return []byte(":0\r\n"), nil
+		}
+		set, ok := values[key].(*SortedSet)
+		if !ok {
+			return nil, fmt.Errorf("value at %s is not a sorted set", key)
+		}
+		sets = append(sets, set)
+	}
+
+	// Check the members of the smallest set against the other sets.
+	slices.SortFunc(sets, func(a, b *SortedSet) int {
+		return a.Cardinality() - b.Cardinality()
+	})
+	count := 0
+	for _, member := range sets[0].GetAll() {
+		if !slices.ContainsFunc(sets[1:], func(set *SortedSet) bool {
+			return !set.Contains(member.Value)
+		}) {
+			count++
+			if count == limit {
+				break
+			}
+		}
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+}
+
 func handleZUNION(params internal.HandlerFuncParams) ([]byte, error) {
 	if _, err := zunionKeyFunc(params.Command); err != nil {
 		return nil, err
@@ -1366,6 +1514,45 @@ func handleZUNIONSTORE(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(fmt.Sprintf(":%d\r\n", union.Cardinality())), nil
 }
 
+func handleZSCAN(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := zscanKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := internal.ParseScanCursor(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	match, count, err := internal.ParseScanOptions(params.Command[3:])
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	if !keyExists {
+		return internal.EncodeScanResponse(0, []string{}), nil
+	}
+
+	set, ok := params.GetValues(params.Context, []string{key})[key].(*SortedSet)
+	if !ok {
+		return nil, fmt.Errorf("value at %s is not a sorted set", key)
+	}
+
+	next, members := set.Scan(cursor, count)
+
+	elements := make([]string, 0, len(members)*2)
+	for _, member := range members {
+		if match == "" || internal.MatchPattern(match, string(member.Value)) {
+			elements = append(elements, string(member.Value), strconv.FormatFloat(float64(member.Score), 'f', -1, 64))
+		}
+	}
+
+	return internal.EncodeScanResponse(next, elements), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -1456,6 +1643,16 @@ Computes the intersection of the sets in the keys, with weights, aggregate and s
 			KeyExtractionFunc: zinterKeyFunc,
 			HandlerFunc:       handleZINTER,
 		},
+		{
+			Command:           "zintercard",
+			Module:            constants.SortedSetModule,
+			Categories:        []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description:       `(ZINTERCARD numkeys key [key ...] [LIMIT limit]) Returns the cardinality of the intersection of the sorted sets.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: zintercardKeyFunc,
+			HandlerFunc:       handleZINTERCARD,
+		},
 		{
 			Command:    "zinterstore",
 			Module:     constants.SortedSetModule,
@@ -1492,6 +1689,36 @@ Returns nil for members that do not exist in the set`,
 			KeyExtractionFunc: zmscoreKeyFunc,
 			HandlerFunc:       handleZMSCORE,
 		},
+		{
+			Command: "bzpopmax",
+			Module:  constants.SortedSetModule,
+			Categories: []string{
+				constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory, constants.BlockingCategory,
+			},
+			Description: `(BZPOPMAX key [key ...] timeout)
+Removes and returns the member with the highest score from the first non-empty sorted set, along with the key and
+the member's score. If all the sorted sets are empty, blocks until a member is added to one of them or until the
+timeout in seconds elapses. A timeout of 0 blocks indefinitely. Returns a null array when the command times out.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: bzpopKeyFunc,
+			HandlerFunc:       handleBZPOP,
+		},
+		{
+			Command: "bzpopmin",
+			Module:  constants.SortedSetModule,
+			Categories: []string{
+				constants.SortedSetCategory, constants.WriteCategory, constants.SlowCategory, constants.BlockingCategory,
+			},
+			Description: `(BZPOPMIN key [key ...] timeout)
+Removes and returns the member with the lowest score from the first non-empty sorted set, along with the key and
+the member's score. If all the sorted sets are empty, blocks until a member is added to one of them or until the
+timeout in seconds elapses. A timeout of 0 blocks indefinitely. Returns a null array when the command times out.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: bzpopKeyFunc,
+			HandlerFunc:       handleBZPOP,
+		},
 		{
 			Command:    "zpopmax",
 			Module:     constants.SortedSetModule,
@@ -1571,6 +1798,18 @@ Returns the number of elements removed.`,
 			KeyExtractionFunc: zscoreKeyFunc,
 			HandlerFunc:       handleZSCORE,
 		},
+		{
+			Command:    "zscan",
+			Module:     constants.SortedSetModule,
+			Categories: []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(ZSCAN key cursor [MATCH pattern] [COUNT count])
+Incrementally iterates over the members of a sorted set and their scores. Start the iteration with a cursor of 0 and
+continue with the cursor returned by each call until it returns 0.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: zscanKeyFunc,
+			HandlerFunc:       handleZSCAN,
+		},
 		{
 			Command:           "zremrangebylex",
 			Module:            constants.SortedSetModule,
@@ -1636,6 +1875,49 @@ the same score. If the value held at key is not a sorted set, an error is return
 			KeyExtractionFunc: zrangeStoreKeyFunc,
 			HandlerFunc:       handleZRANGESTORE,
 		},
+		{
+			Command:    "zrangebyscore",
+			Module:     constants.SortedSetModule,
+			Categories: []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(ZRANGEBYSCORE key min max [WITHSCORES] [LIMIT offset count])
+Returns the members of the sorted set with scores between min and max, ordered from the lowest score.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: zrangebyscoreKeyFunc,
+			HandlerFunc:       handleZRANGEBYSCORE,
+		},
+		{
+			Command:    "zrevrangebyscore",
+			Module:     constants.SortedSetModule,
+			Categories: []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(ZREVRANGEBYSCORE key max min [WITHSCORES] [LIMIT offset count])
+Returns the members of the sorted set with scores between max and min, ordered from the highest score.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: zrangebyscoreKeyFunc,
+			HandlerFunc:       handleZRANGEBYSCORE,
+		},
+		{
+			Command:    "zrangebylex",
+			Module:     constants.SortedSetModule,
+			Categories: []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(ZRANGEBYLEX key min max [LIMIT offset count])
+Returns the members of the sorted set between min and max when all the members have the same score.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: zrangebylexKeyFunc,
+			HandlerFunc:       handleZRANGEBYLEX,
+		},
+		{
+			Command:           "zrevrange",
+			Module:            constants.SortedSetModule,
+			Categories:        []string{constants.SortedSetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description:       `(ZREVRANGE key start stop [WITHSCORES]) Returns the members of the sorted set in the index range, ordered from the highest score.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: zrevrangeKeyFunc,
+			HandlerFunc:       handleZREVRANGE,
+		},
 		{
 			Command:    "zunion",
 			Module:     constants.SortedSetModule,
@@ -15,12 +15,15 @@
 package sorted_set_test
 
 import (
+	"cmp"
 	"errors"
 	"math"
+	"math/rand"
 	"slices"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/config"
@@ -2181,6 +2184,176 @@ func Test_SortedSet(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_HandleZSCAN", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		var members []sorted_set.MemberParam
+		for i := 0; i < 25; i++ {
+			members = append(members, sorted_set.MemberParam{
+				Value: sorted_set.Value("member" + strconv.Itoa(i)),
+				Score: sorted_set.Score(float64(i) + 0.5),
+			})
+		}
+		largeExpected := make(map[string]string)
+		for _, member := range members {
+			largeExpected[string(member.Value)] = strconv.FormatFloat(float64(member.Score), 'f', -1, 64)
+		}
+
+		tests := []struct {
+			name             string
+			key              string
+			presetValue      interface{}
+			args             []string
+			expectedResponse map[string]string
+			expectedError    error
+		}{
+			{
+				name: "1. Return all the members of the sorted set with their scores",
+				key:  "ZscanKey1",
+				presetValue: sorted_set.NewSortedSet([]sorted_set.MemberParam{
+					{Value: "one", Score: 1}, {Value: "two", Score: 2}, {Value: "three", Score: 3.5},
+				}),
+				args:             []string{},
+				expectedResponse: map[string]string{"one": "1", "two": "2", "three": "3.5"},
+				expectedError:    nil,
+			},
+			{
+				name:             "2. Iterate over a large sorted set with a small count",
+				key:              "ZscanKey2",
+				presetValue:      sorted_set.NewSortedSet(members),
+				args:             []string{"COUNT", "4"},
+				expectedResponse: largeExpected,
+				expectedError:    nil,
+			},
+			{
+				name:        "3. Only return the members that match the pattern",
+				key:         "ZscanKey3",
+				presetValue: sorted_set.NewSortedSet(members),
+				args:        []string{"MATCH", "member2?"},
+				expectedResponse: map[string]string{
+					"member20": "20.5", "member21": "21.5", "member22": "22.5", "member23": "23.5", "member24": "24.5",
+				},
+				expectedError: nil,
+			},
+			{
+				name:             "4. Empty response when the key does not exist",
+				key:              "ZscanKey4",
+				presetValue:      nil,
+				args:             []string{},
+				expectedResponse: map[string]string{},
+				expectedError:    nil,
+			},
+			{
+				name:          "5. Return error when the key is not a sorted set",
+				key:           "ZscanKey5",
+				presetValue:   "Default value",
+				args:          []string{},
+				expectedError: errors.New("value at ZscanKey5 is not a sorted set"),
+			},
+			{
+				name:          "6. Return error when an option is unknown",
+				key:           "ZscanKey6",
+				presetValue:   nil,
+				args:          []string{"WITHSCORES", "1"},
+				expectedError: errors.New("unknown option WITHSCORES"),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				if test.presetValue != nil {
+					var command []resp.Value
+					var expected string
+
+					switch test.presetValue.(type) {
+					case string:
+						command = []resp.Value{
+							resp.StringValue("SET"),
+							resp.StringValue(test.key),
+							resp.StringValue(test.presetValue.(string)),
+						}
+						expected = "ok"
+					case *sorted_set.SortedSet:
+						command = []resp.Value{resp.StringValue("ZADD"), resp.StringValue(test.key)}
+						for _, member := range test.presetValue.(*sorted_set.SortedSet).GetAll() {
+							command = append(command, []resp.Value{
+								resp.StringValue(strconv.FormatFloat(float64(member.Score), 'f', -1, 64)),
+								resp.StringValue(string(member.Value)),
+							}...)
+						}
+						expected = strconv.Itoa(test.presetValue.(*sorted_set.SortedSet).Cardinality())
+					}
+
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if !strings.EqualFold(res.String(), expected) {
+						t.Errorf("expected preset response to be \"%s\", got %s", expected, res.String())
+					}
+				}
+
+				got := make(map[string]string)
+				cursor := "0"
+				for {
+					command := []resp.Value{resp.StringValue("ZSCAN"), resp.StringValue(test.key), resp.StringValue(cursor)}
+					for _, arg := range test.args {
+						command = append(command, resp.StringValue(arg))
+					}
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+					}
+
+					if test.expectedError != nil {
+						if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+							t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error())
+						}
+						return
+					}
+
+					elements := res.Array()[1].Array()
+					for i := 0; i < len(elements); i += 2 {
+						member := elements[i].String()
+						if _, ok := got[member]; ok {
+							t.Errorf("member \"%s\" returned more than once", member)
+						}
+						got[member] = elements[i+1].String()
+					}
+
+					if cursor = res.Array()[0].String(); cursor == "0" {
+						break
+					}
+				}
+
+				if len(got) != len(test.expectedResponse) {
+					t.Errorf("expected response of length %d, got %d", len(test.expectedResponse), len(got))
+				}
+				for member, score := range test.expectedResponse {
+					if got[member] != score {
+						t.Errorf("expected member \"%s\" to have score \"%s\", got \"%s\"", member, score, got[member])
+					}
+				}
+			})
+		}
+	})
+
 	t.Run("Test_HandleZSCORE", func(t *testing.T) {
 		t.Parallel()
 		conn, err := internal.GetConnection("localhost", port)
@@ -5745,4 +5918,368 @@ func Test_SortedSet(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Test_HandleBZPOP", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		// The writer adds members from a separate connection while the client is blocked.
+		writerConn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = writerConn.Close()
+		}()
+		writer := resp.NewConn(writerConn)
+
+		tests := []struct {
+			name             string
+			preset           map[string][]string // ZADD arguments for each key.
+			write            []string            // Command executed from the writer connection while the client is blocked.
+			command          []string
+			expectedResponse []string // Nil when the response is expected to be a null array.
+			expectedError    error
+		}{
+			{
+				name:             "1. BZPOPMIN pops the member with the lowest score from the first non-empty sorted set",
+				preset:           map[string][]string{"BZPopKey2": {"2", "two", "1", "one"}},
+				command:          []string{"BZPOPMIN", "BZPopKey1", "BZPopKey2", "0"},
+				expectedResponse: []string{"BZPopKey2", "one", "1"},
+			},
+			{
+				name:             "2. BZPOPMAX pops the member with the highest score",
+				preset:           map[string][]string{"BZPopKey3": {"2", "two", "1.5", "one"}},
+				command:          []string{"BZPOPMAX", "BZPopKey3", "0"},
+				expectedResponse: []string{"BZPopKey3", "two", "2"},
+			},
+			{
+				name:             "3. BZPOPMIN blocks until a member is added",
+				write:            []string{"ZADD", "BZPopKey4", "3", "three"},
+				command:          []string{"BZPOPMIN", "BZPopKey4", "5"},
+				expectedResponse: []string{"BZPopKey4", "three", "3"},
+			},
+			{
+				name:             "4. BZPOPMAX returns null when the timeout elapses",
+				command:          []string{"BZPOPMAX", "BZPopKey5", "0.05"},
+				expectedResponse: nil,
+			},
+			{
+				name:          "5. Return error when the key is not a sorted set",
+				command:       []string{"BZPOPMIN", "BZPopKey6", "0"},
+				expectedError: errors.New("value at key BZPopKey6 is not a sorted set"),
+			},
+			{
+				name:          "6. Command too short",
+				command:       []string{"BZPOPMIN", "BZPopKey7"},
+				expectedError: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		// Preset a non-sorted set value for the wrong type test.
+		if err = client.WriteArray([]resp.Value{
+			resp.StringValue("SET"), resp.StringValue("BZPopKey6"), resp.StringValue("value"),
+		}); err != nil {
+			t.Error(err)
+		}
+		if _, _, err = client.ReadValue(); err != nil {
+			t.Error(err)
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for key, args := range test.preset {
+					command := []resp.Value{resp.StringValue("ZADD"), resp.StringValue(key)}
+					for _, arg := range args {
+						command = append(command, resp.StringValue(arg))
+					}
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					if _, _, err = client.ReadValue(); err != nil {
+						t.Error(err)
+					}
+				}
+
+				written := make(chan error, 1)
+				if test.write != nil {
+					go func() {
+						time.Sleep(100 * time.Millisecond)
+						command := make([]resp.Value, len(test.write))
+						for i, c := range test.write {
+							command[i] = resp.StringValue(c)
+						}
+						if err := writer.WriteArray(command); err != nil {
+							written <- err
+							return
+						}
+						_, _, err := writer.ReadValue()
+						written <- err
+					}()
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.write != nil {
+					if err = <-written; err != nil {
+						t.Error(err)
+					}
+				}
+
+				if test.expectedError != nil {
+					if !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%s\"", test.expectedError.Error(), res.Error().Error())
+					}
+					return
+				}
+
+				if test.expectedResponse == nil {
+					if !res.IsNull() {
+						t.Errorf("expected null response, got %+v", res)
+					}
+					return
+				}
+
+				got := make([]string, len(res.Array()))
+				for i, item := range res.Array() {
+					got[i] = item.String()
+				}
+				if !slices.Equal(got, test.expectedResponse) {
+					t.Errorf("expected response %v, got %v", test.expectedResponse, got)
+				}
+			})
+		}
+	})
+
+	t.Run("Test_HandleRangeByCommands", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		// Each test runs its commands in order and checks the response of every command.
+		tests := []struct {
+			name     string
+			commands [][]string
+			expected []string
+		}{
+			{
+				name: "1. ZRANGEBYSCORE with inclusive, exclusive and infinite bounds",
+				commands: [][]string{
+					{"ZADD", "RangeByKey1", "1", "one", "2", "two", "3", "three", "4", "four", "5", "five"},
+					{"ZRANGEBYSCORE", "RangeByKey1", "2", "4"},
+					{"ZRANGEBYSCORE", "RangeByKey1", "(2", "(4"},
+					{"ZRANGEBYSCORE", "RangeByKey1", "-inf", "+inf", "WITHSCORES", "LIMIT", "1", "2"},
+					{"ZRANGEBYSCORE", "RangeByKey1", "3", "+inf", "LIMIT", "1", "-1"},
+					{"ZRANGEBYSCORE", "RangeByKey1", "4", "2"},
+					{"ZRANGEBYSCORE", "RangeByKeyMissing", "-inf", "+inf"},
+				},
+				expected: []string{
+					"5", "[two three four]", "[three]", "[two 2 three 3]", "[four five]", "[]", "[]",
+				},
+			},
+			{
+				name: "2. ZREVRANGEBYSCORE takes max before min and returns members from the highest score",
+				commands: [][]string{
+					{"ZADD", "RangeByKey2", "1", "one", "2", "two", "3", "three", "4", "four", "5", "five"},
+					{"ZREVRANGEBYSCORE", "RangeByKey2", "4", "2"},
+					{"ZREVRANGEBYSCORE", "RangeByKey2", "+inf", "(3", "WITHSCORES"},
+					{"ZREVRANGEBYSCORE", "RangeByKey2", "+inf", "-inf", "LIMIT", "1", "2"},
+				},
+				expected: []string{"5", "[four three two]", "[five 5 four 4]", "[four three]"},
+			},
+			{
+				name: "3. ZRANGEBYLEX with inclusive, exclusive and open bounds",
+				commands: [][]string{
+					{"ZADD", "RangeByKey3", "0", "a", "0", "b", "0", "c", "0", "d", "0", "e"},
+					{"ZRANGEBYLEX", "RangeByKey3", "-", "+"},
+					{"ZRANGEBYLEX", "RangeByKey3", "[b", "(d"},
+					{"ZRANGEBYLEX", "RangeByKey3", "(a", "[c", "LIMIT", "1", "5"},
+					{"ZRANGEBYLEX", "RangeByKey3", "b", "d"},
+				},
+				expected: []string{"5", "[a b c d e]", "[b c]", "[c]", "min or max not valid string range item"},
+			},
+			{
+				name: "4. ZREVRANGE returns members by index from the highest score",
+				commands: [][]string{
+					{"ZADD", "RangeByKey4", "1", "one", "2", "two", "3", "three"},
+					{"ZREVRANGE", "RangeByKey4", "0", "-1"},
+					{"ZREVRANGE", "RangeByKey4", "1", "1", "WITHSCORES"},
+					{"ZREVRANGE", "RangeByKey4", "-2", "10"},
+					{"ZREVRANGE", "RangeByKey4", "5", "10"},
+				},
+				expected: []string{"3", "[three two one]", "[two 2]", "[two one]", "[]"},
+			},
+			{
+				name: "5. ZINTERCARD counts the intersection up to the limit",
+				commands: [][]string{
+					{"ZADD", "RangeByKey5a", "1", "a", "2", "b", "3", "c", "4", "d"},
+					{"ZADD", "RangeByKey5b", "1", "b", "2", "c", "3", "d", "4", "e"},
+					{"ZINTERCARD", "2", "RangeByKey5a", "RangeByKey5b"},
+					{"ZINTERCARD", "2", "RangeByKey5a", "RangeByKey5b", "LIMIT", "2"},
+					{"ZINTERCARD", "2", "RangeByKey5a", "RangeByKeyMissing"},
+					{"ZINTERCARD", "0", "RangeByKey5a"},
+				},
+				expected: []string{"4", "4", "3", "2", "0", "numkeys should be greater than 0"},
+			},
+			{
+				name: "6. Return errors on invalid arguments",
+				commands: [][]string{
+					{"SET", "RangeByKey6", "value"},
+					{"ZRANGEBYSCORE", "RangeByKey6", "-inf", "+inf"},
+					{"ZRANGEBYSCORE", "RangeByKey1", "a", "+inf"},
+					{"ZRANGEBYSCORE", "RangeByKey1", "-inf", "+inf", "LIMIT", "1"},
+					{"ZREVRANGE", "RangeByKey1", "0", "-1", "WITHVALUES"},
+				},
+				expected: []string{
+					"OK",
+					"value at RangeByKey6 is not a sorted set",
+					"min or max is not a float",
+					"limit should contain offset and count as integers",
+					"unknown option WITHVALUES",
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for i, command := range test.commands {
+					values := make([]resp.Value, len(command))
+					for j, v := range command {
+						values[j] = resp.StringValue(v)
+					}
+					if err = client.WriteArray(values); err != nil {
+						t.Error(err)
+						return
+					}
+					res, _, err := client.ReadValue()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if res.Error() != nil {
+						if !strings.Contains(res.Error().Error(), test.expected[i]) {
+							t.Errorf("command %v: expected error \"%s\", got \"%s\"", command, test.expected[i], res.Error())
+						}
+						continue
+					}
+					if res.String() != test.expected[i] {
+						t.Errorf("command %v: expected response %s, got %s", command, test.expected[i], res.String())
+					}
+				}
+			})
+		}
+	})
+}
+
+func Test_SortedSetIndex(t *testing.T) {
+	// Build a large set with duplicate scores and random updates and removals, then check that the ordered
+	// index agrees with a plain sort of the members.
+	rng := rand.New(rand.NewSource(1))
+	set := sorted_set.NewSortedSet([]sorted_set.MemberParam{})
+	expected := make(map[sorted_set.Value]sorted_set.Score)
+
+	for i := 0; i < 5000; i++ {
+		value := sorted_set.Value(strconv.Itoa(rng.Intn(2000)))
+		switch rng.Intn(4) {
+		case 0:
+			set.Remove(value)
+			delete(expected, value)
+		default:
+			score := sorted_set.Score(rng.Intn(100))
+			if _, err := set.AddOrUpdate(
+				[]sorted_set.MemberParam{{Value: value, Score: score}}, nil, nil, nil, nil,
+			); err != nil {
+				t.Error(err)
+				return
+			}
+			expected[value] = score
+		}
+	}
+
+	want := make([]sorted_set.MemberParam, 0, len(expected))
+	for value, score := range expected {
+		want = append(want, sorted_set.MemberParam{Value: value, Score: score})
+	}
+	slices.SortFunc(want, func(a, b sorted_set.MemberParam) int {
+		if c := cmp.Compare(a.Score, b.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+
+	if !slices.Equal(set.GetAll(), want) {
+		t.Errorf("expected GetAll to return the members ordered by score and value")
+		return
+	}
+	if set.Cardinality() != len(want) {
+		t.Errorf("expected cardinality %d, got %d", len(want), set.Cardinality())
+	}
+
+	for i, member := range want {
+		rank, ok := set.Rank(member.Value)
+		if !ok || rank != i {
+			t.Errorf("expected rank of %s to be %d, got %d", member.Value, i, rank)
+			return
+		}
+	}
+
+	if got := set.RangeByRank(10, 19, false); !slices.Equal(got, want[10:20]) {
+		t.Errorf("expected RangeByRank(10, 19) to return %v, got %v", want[10:20], got)
+	}
+	reversed := slices.Clone(want[len(want)-5:])
+	slices.Reverse(reversed)
+	if got := set.RangeByRank(len(want)-5, len(want)+5, true); !slices.Equal(got, reversed) {
+		t.Errorf("expected reversed range to return %v, got %v", reversed, got)
+	}
+
+	start, end := set.ScoreRange(20, 30)
+	for i, member := range want {
+		inRange := member.Score >= 20 && member.Score <= 30
+		if inRange != (i >= start && i < end) {
+			t.Errorf("expected member %s with score %v to be in range %v", member.Value, member.Score, inRange)
+			return
+		}
+	}
+
+	// Memory usage must follow the members that are added and removed.
+	before := set.GetMem()
+	if _, err := set.AddOrUpdate(
+		[]sorted_set.MemberParam{{Value: "new-member", Score: 1000}}, nil, nil, nil, nil,
+	); err != nil {
+		t.Error(err)
+		return
+	}
+	if set.GetMem() <= before {
+		t.Errorf("expected memory usage to grow after adding a member")
+	}
+	set.Remove("new-member")
+	if set.GetMem() != before {
+		t.Errorf("expected memory usage %d after removing the member, got %d", before, set.GetMem())
+	}
 }
@@ -0,0 +1,225 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sorted_set
+
+import (
+	"math/rand"
+	"unsafe"
+)
+
+const (
+	skipListMaxLevel    = 32
+	skipListProbability = 0.25
+)
+
+type skipListLevel struct {
+	forward *skipListNode
+	// span is the number of nodes between this node and the forward node on this level.
+	// It's used to calculate the rank of a node while traversing the list.
+	span int
+}
+
+type skipListNode struct {
+	value    Value
+	score    Score
+	backward *skipListNode
+	levels   []skipListLevel
+}
+
+func (node *skipListNode) next() *skipListNode {
+	return node.levels[0].forward
+}
+
+// less reports whether the node is ordered before the provided score and value.
+// Nodes are ordered by score, and then by value for nodes with the same score.
+func (node *skipListNode) less(score Score, value Value) bool {
+	return node.score < score || (node.score == score && node.value < value)
+}
+
+// skipList is the ordered index of a SortedSet. It keeps the members ordered by score and value, and records
+// the span of each link so that both rank and range lookups take O(log n) time.
+type skipList struct {
+	header *skipListNode
+	tail   *skipListNode
+	length int
+	level  int
+}
+
+func newSkipList() *skipList {
+	return &skipList{
+		header: &skipListNode{levels: make([]skipListLevel, skipListMaxLevel)},
+		level:  1,
+	}
+}
+
+func randomSkipListLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.Float64() < skipListProbability {
+		level++
+	}
+	return level
+}
+
+// insert adds a new node to the list. The caller must make sure the value is not already in the list.
+func (list *skipList) insert(value Value, score Score) {
+	var update [skipListMaxLevel]*skipListNode
+	var rank [skipListMaxLevel]int
+
+	node := list.header
+	for i := list.level - 1; i >= 0; i-- {
+		if i < list.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for node.levels[i].forward != nil && node.levels[i].forward.less(score, value) {
+			rank[i] += node.levels[i].span
+			node = node.levels[i].forward
+		}
+		update[i] = node
+	}
+
+	level := randomSkipListLevel()
+	if level > list.level {
+		for i := list.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = list.header
+			update[i].levels[i].span = list.length
+		}
+		list.level = level
+	}
+
+	node = &skipListNode{value: value, score: score, levels: make([]skipListLevel, level)}
+	for i := 0; i < level; i++ {
+		node.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = node
+		node.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = (rank[0] - rank[i]) + 1
+	}
+	// The levels above the new node now span one more node.
+	for i := level; i < list.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != list.header {
+		node.backward = update[0]
+	}
+	if node.next() != nil {
+		node.next().backward = node
+	} else {
+		list.tail = node
+	}
+	list.length++
+}
+
+// delete removes the node with the provided value and score. It returns false if there is no such node.
+func (list *skipList) delete(value Value, score Score) bool {
+	var update [skipListMaxLevel]*skipListNode
+
+	node := list.header
+	for i := list.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && node.levels[i].forward.less(score, value) {
+			node = node.levels[i].forward
+		}
+		update[i] = node
+	}
+
+	node = node.next()
+	if node == nil || node.score != score || node.value != value {
+		return false
+	}
+
+	for i := 0; i < list.level; i++ {
+		if update[i].levels[i].forward == node {
+			update[i].levels[i].span += node.levels[i].span - 1
+			update[i].levels[i].forward = node.levels[i].forward
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	if node.next() != nil {
+		node.next().backward = node.backward
+	} else {
+		list.tail = node.backward
+	}
+	for list.level > 1 && list.header.levels[list.level-1].forward == nil {
+		list.level--
+	}
+	list.length--
+	return true
+}
+
+// rank returns the 0-based rank of the node with the provided value and score, or -1 if there is no such node.
+func (list *skipList) rank(value Value, score Score) int {
+	rank := 0
+	node := list.header
+	for i := list.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && (node.levels[i].forward.less(score, value) ||
+			(node.levels[i].forward.score == score && node.levels[i].forward.value == value)) {
+			rank += node.levels[i].span
+			node = node.levels[i].forward
+		}
+		if node != list.header && node.value == value && node.score == score {
+			return rank - 1
+		}
+	}
+	return -1
+}
+
+// countWhile returns the number of nodes at the start of the list for which before returns true.
+// before must be monotonic over the list order: once it returns false for a node, it must return false
+// for all the nodes that follow.
+func (list *skipList) countWhile(before func(node *skipListNode) bool) int {
+	rank := 0
+	node := list.header
+	for i := list.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && before(node.levels[i].forward) {
+			rank += node.levels[i].span
+			node = node.levels[i].forward
+		}
+	}
+	return rank
+}
+
+// nodeAt returns the node at the provided 0-based rank, or nil if the rank is out of range.
+func (list *skipList) nodeAt(rank int) *skipListNode {
+	if rank < 0 || rank >= list.length {
+		return nil
+	}
+	traversed := 0
+	node := list.header
+	for i := list.level - 1; i >= 0; i-- {
+		for node.levels[i].forward != nil && traversed+node.levels[i].span <= rank+1 {
+			traversed += node.levels[i].span
+			node = node.levels[i].forward
+		}
+		if traversed == rank+1 {
+			return node
+		}
+	}
+	return nil
+}
+
+func (list *skipList) first() *skipListNode {
+	return list.header.next()
+}
+
+func (list *skipList) getMem() int64 {
+	size := int64(unsafe.Sizeof(*list))
+	size += int64(unsafe.Sizeof(*list.header)) + int64(len(list.header.levels))*int64(unsafe.Sizeof(skipListLevel{}))
+	for node := list.first(); node != nil; node = node.next() {
+		// The value shares its bytes with the map key, so only the node and its levels are counted.
+		size += int64(unsafe.Sizeof(*node)) + int64(len(node.levels))*int64(unsafe.Sizeof(skipListLevel{}))
+	}
+	return size
+}
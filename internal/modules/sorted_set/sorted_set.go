@@ -16,6 +16,7 @@ package sorted_set
 
 import (
 	"cmp"
+	"encoding/binary"
 	"errors"
 	"math"
 	"math/rand"
@@ -46,14 +47,18 @@ type MemberParam struct {
 
 type SortedSet struct {
 	members map[Value]MemberObject
+	// index keeps the members ordered by score and value for rank and range queries.
+	index *skipList
+	// scanIndex keeps the members ordered by their scan position for ZSCAN.
+	scanIndex *internal.ScanIndex[Value]
 }
 
-func (s *SortedSet) GetMem() int64 {
+func (set *SortedSet) GetMem() int64 {
 	var size int64
 	// map header
-	size += int64(unsafe.Sizeof(s))
+	size += int64(unsafe.Sizeof(set))
 	// map contents
-	for k, v := range s.members {
+	for k, v := range set.members {
 		// string header
 		size += int64(unsafe.Sizeof(k))
 		// string
@@ -64,6 +69,9 @@ func (s *SortedSet) GetMem() int64 {
 		size += int64(unsafe.Sizeof(v.Value))
 		size += int64(len(v.Value))
 	}
+	// ordered indexes
+	size += set.index.getMem()
+	size += set.scanIndex.GetMem()
 
 	return size
 }
@@ -71,20 +79,63 @@ func (s *SortedSet) GetMem() int64 {
 // compile time interface check
 var _ constants.CompositeType = (*SortedSet)(nil)
 
+// MarshalBinary encodes the members of the sorted set ordered by value, so equal sets always encode to the same bytes.
+func (set *SortedSet) MarshalBinary() ([]byte, error) {
+	members := set.GetAll()
+	slices.SortFunc(members, func(a, b MemberParam) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+	b := binary.AppendUvarint(nil, uint64(len(members)))
+	for _, member := range members {
+		b = internal.AppendBinaryString(b, string(member.Value))
+		b = internal.AppendBinaryFloat(b, float64(member.Score))
+	}
+	return b, nil
+}
+
+func (set *SortedSet) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	n := r.Count()
+	members := make([]MemberParam, 0, n)
+	for i := 0; i < n; i++ {
+		members = append(members, MemberParam{Value: Value(r.String()), Score: Score(r.Float())})
+	}
+	if r.Err() != nil {
+		return r.Err()
+	}
+	*set = *NewSortedSet(members)
+	return nil
+}
+
 func NewSortedSet(members []MemberParam) *SortedSet {
 	s := &SortedSet{
-		members: make(map[Value]MemberObject),
+		members:   make(map[Value]MemberObject),
+		index:     newSkipList(),
+		scanIndex: internal.NewScanIndex[Value](),
 	}
 	for _, m := range members {
-		s.members[m.Value] = MemberObject{
-			Value:  m.Value,
-			Score:  m.Score,
-			Exists: true,
-		}
+		s.put(m.Value, m.Score)
 	}
 	return s
 }
 
+// put adds the member to the sorted set or updates its score, keeping the ordered indexes in sync with the map.
+func (set *SortedSet) put(v Value, score Score) {
+	if member, ok := set.members[v]; ok {
+		if member.Score == score {
+			return
+		}
+		set.index.delete(v, member.Score)
+	}
+	set.members[v] = MemberObject{
+		Value:  v,
+		Score:  score,
+		Exists: true,
+	}
+	set.index.insert(v, score)
+	set.scanIndex.Insert(v)
+}
+
 func (set *SortedSet) Contains(m Value) bool {
 	return set.members[m].Exists
 }
@@ -129,19 +180,94 @@ func (set *SortedSet) GetRandom(count int) []MemberParam {
 	return res
 }
 
+// GetAll returns all the members of the sorted set ordered by score, and then by value.
 func (set *SortedSet) GetAll() []MemberParam {
 	var res []MemberParam
-	for k, v := range set.members {
+	for node := set.index.first(); node != nil; node = node.next() {
 		res = append(res, MemberParam{
-			Value: k,
-			Score: v.Score,
+			Value: node.value,
+			Score: node.score,
 		})
 	}
 	return res
 }
 
+// Rank returns the 0-based rank of the member when the members are ordered by score, and then by value.
+// It returns false if the member is not in the sorted set.
+func (set *SortedSet) Rank(v Value) (int, bool) {
+	member, ok := set.members[v]
+	if !ok {
+		return 0, false
+	}
+	return set.index.rank(v, member.Score), true
+}
+
+// RangeByRank returns the members with ranks from start to stop inclusive. When reverse is true, the members
+// are returned starting from stop. The ranks are clamped to the bounds of the sorted set.
+func (set *SortedSet) RangeByRank(start, stop int, reverse bool) []MemberParam {
+	start = max(start, 0)
+	stop = min(stop, set.Cardinality()-1)
+	if start > stop {
+		return []MemberParam{}
+	}
+
+	res := make([]MemberParam, 0, stop-start+1)
+	if reverse {
+		for node := set.index.nodeAt(stop); node != nil && len(res) <= stop-start; node = node.backward {
+			res = append(res, MemberParam{Value: node.value, Score: node.score})
+		}
+		return res
+	}
+	for node := set.index.nodeAt(start); node != nil && len(res) <= stop-start; node = node.next() {
+		res = append(res, MemberParam{Value: node.value, Score: node.score})
+	}
+	return res
+}
+
+// ScoreRange returns the ranks of the members with scores between minimum and maximum inclusive,
+// as the rank of the first member and the rank after the last member.
+func (set *SortedSet) ScoreRange(minimum, maximum Score) (int, int) {
+	start := set.index.countWhile(func(node *skipListNode) bool {
+		return node.score < minimum
+	})
+	end := set.index.countWhile(func(node *skipListNode) bool {
+		return node.score <= maximum
+	})
+	return start, max(start, end)
+}
+
+// LexRange returns the ranks of the members with values between minimum and maximum inclusive, as the rank of
+// the first member and the rank after the last member. Like Redis, it assumes all the members have the same score.
+func (set *SortedSet) LexRange(minimum, maximum string) (int, int) {
+	start := set.index.countWhile(func(node *skipListNode) bool {
+		return string(node.value) < minimum
+	})
+	end := set.index.countWhile(func(node *skipListNode) bool {
+		return string(node.value) <= maximum
+	})
+	return start, max(start, end)
+}
+
+// SingleScore reports whether all the members of the sorted set have the same score.
+func (set *SortedSet) SingleScore() bool {
+	if set.Cardinality() == 0 {
+		return true
+	}
+	return set.index.first().score == set.index.tail.score
+}
+
+// Scan returns up to count members starting from cursor, and the cursor to continue the iteration from.
+func (set *SortedSet) Scan(cursor uint64, count int) (uint64, []MemberParam) {
+	next, values := set.scanIndex.Scan(cursor, count)
+	members := make([]MemberParam, len(values))
+	for i, value := range values {
+		members[i] = MemberParam{Value: value, Score: set.members[value].Score}
+	}
+	return next, members
+}
+
 func (set *SortedSet) Cardinality() int {
-	return len(set.GetAll())
+	return len(set.members)
 }
 
 func (set *SortedSet) AddOrUpdate(
@@ -176,11 +302,7 @@ func (set *SortedSet) AddOrUpdate(
 		for _, m := range members {
 			if !set.Contains(m.Value) {
 				// If the member is not contained, add it with the increment as its Score
-				set.members[m.Value] = MemberObject{
-					Value:  m.Value,
-					Score:  m.Score,
-					Exists: true,
-				}
+				set.put(m.Value, m.Score)
 				// Always add count because this is the addition of a new element
 				count += 1
 				return count, err
@@ -188,11 +310,7 @@ func (set *SortedSet) AddOrUpdate(
 			if slices.Contains([]Score{Score(math.Inf(-1)), Score(math.Inf(1))}, set.members[m.Value].Score) {
 				return count, errors.New("cannot increment -inf or +inf")
 			}
-			set.members[m.Value] = MemberObject{
-				Value:  m.Value,
-				Score:  set.members[m.Value].Score + m.Score,
-				Exists: true,
-			}
+			set.put(m.Value, set.members[m.Value].Score+m.Score)
 			if strings.EqualFold(ch, "ch") {
 				count += 1
 			}
@@ -204,11 +322,7 @@ func (set *SortedSet) AddOrUpdate(
 		if strings.EqualFold(policy, "xx") {
 			// Only update existing elements, do not add new elements
 			if set.Contains(m.Value) {
-				set.members[m.Value] = MemberObject{
-					Value:  m.Value,
-					Score:  compareScores(set.members[m.Value].Score, m.Score, comp),
-					Exists: true,
-				}
+				set.put(m.Value, compareScores(set.members[m.Value].Score, m.Score, comp))
 				if strings.EqualFold(ch, "ch") {
 					count += 1
 				}
@@ -218,11 +332,7 @@ func (set *SortedSet) AddOrUpdate(
 		if strings.EqualFold(policy, "nx") {
 			// Only add new elements, do not update existing elements
 			if !set.Contains(m.Value) {
-				set.members[m.Value] = MemberObject{
-					Value:  m.Value,
-					Score:  m.Score,
-					Exists: true,
-				}
+				set.put(m.Value, m.Score)
 				count += 1
 			}
 			continue
@@ -231,17 +341,15 @@ func (set *SortedSet) AddOrUpdate(
 		if set.members[m.Value].Score != m.Score || !set.members[m.Value].Exists {
 			count += 1
 		}
-		set.members[m.Value] = MemberObject{
-			Value:  m.Value,
-			Score:  compareScores(set.members[m.Value].Score, m.Score, comp),
-			Exists: true,
-		}
+		set.put(m.Value, compareScores(set.members[m.Value].Score, m.Score, comp))
 	}
 	return count, nil
 }
 
 func (set *SortedSet) Remove(v Value) bool {
 	if set.Contains(v) {
+		set.index.delete(v, set.members[v].Score)
+		set.scanIndex.Delete(v)
 		delete(set.members, v)
 		return true
 	}
@@ -260,14 +368,12 @@ func (set *SortedSet) Pop(count int, policy string) (*SortedSet, error) {
 		return popped, nil
 	}
 
-	members := set.GetAll()
-
-	slices.SortFunc(members, func(a, b MemberParam) int {
-		if strings.EqualFold(policy, "min") {
-			return cmp.Compare(a.Score, b.Score)
-		}
-		return cmp.Compare(b.Score, a.Score)
-	})
+	var members []MemberParam
+	if strings.EqualFold(policy, "min") {
+		members = set.RangeByRank(0, count-1, false)
+	} else {
+		members = set.RangeByRank(set.Cardinality()-count, set.Cardinality()-1, true)
+	}
 
 	for i := 0; i < count; i++ {
 		if i >= len(members) {
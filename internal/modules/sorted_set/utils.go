@@ -16,6 +16,8 @@ package sorted_set
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"slices"
 	"strconv"
 	"strings"
@@ -167,3 +169,117 @@ func compareScores(old Score, new Score, comp string) Score {
 		return old
 	}
 }
+
+// parseScoreBound parses the min or max argument of ZRANGEBYSCORE and ZREVRANGEBYSCORE. A bound prefixed with
+// "(" is exclusive, so it is moved to the next representable score inside the range.
+func parseScoreBound(bound string, lower bool) (Score, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	score, err := strconv.ParseFloat(strings.TrimPrefix(bound, "("), 64)
+	if err != nil {
+		return 0, errors.New("min or max is not a float")
+	}
+	if exclusive {
+		if lower {
+			score = math.Nextafter(score, math.Inf(1))
+		} else {
+			score = math.Nextafter(score, math.Inf(-1))
+		}
+	}
+	return Score(score), nil
+}
+
+// lexRange returns the ranks of the members within the ZRANGEBYLEX bounds minimum and maximum, as the rank of the
+// first member and the rank after the last member. Bounds are "-", "+", or a value prefixed with "[" (inclusive)
+// or "(" (exclusive).
+func lexRange(set *SortedSet, minimum, maximum string) (int, int, error) {
+	start, err := lexBoundRank(set, minimum, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := lexBoundRank(set, maximum, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, max(start, end), nil
+}
+
+// lexBoundRank returns the number of members that sort before the bound. For a lower bound, these are the
+// members outside the range. For an upper bound, these are the members up to and including the bound.
+func lexBoundRank(set *SortedSet, bound string, lower bool) (int, error) {
+	switch {
+	case bound == "-":
+		return 0, nil
+	case bound == "+":
+		return set.Cardinality(), nil
+	case strings.HasPrefix(bound, "["), strings.HasPrefix(bound, "("):
+		value, inclusive := bound[1:], bound[0] == '['
+		return set.index.countWhile(func(node *skipListNode) bool {
+			if lower == inclusive {
+				return string(node.value) < value
+			}
+			return string(node.value) <= value
+		}), nil
+	default:
+		return 0, errors.New("min or max not valid string range item")
+	}
+}
+
+// parseRangeByOptions parses the [WITHSCORES] [LIMIT offset count] options of the ZRANGEBYSCORE family.
+// A negative count returns all the members after the offset.
+func parseRangeByOptions(args []string, allowWithScores bool) (withscores bool, offset int, count int, err error) {
+	count = -1
+	for i := 0; i < len(args); i++ {
+		switch {
+		case allowWithScores && strings.EqualFold(args[i], "withscores"):
+			withscores = true
+		case strings.EqualFold(args[i], "limit"):
+			if i+2 >= len(args) {
+				return false, 0, 0, errors.New("limit should contain offset and count as integers")
+			}
+			if offset, err = strconv.Atoi(args[i+1]); err != nil {
+				return false, 0, 0, errors.New("limit offset must be integer")
+			}
+			if count, err = strconv.Atoi(args[i+2]); err != nil {
+				return false, 0, 0, errors.New("limit count must be integer")
+			}
+			i += 2
+		default:
+			return false, 0, 0, fmt.Errorf("unknown option %s", strings.ToUpper(args[i]))
+		}
+	}
+	return withscores, offset, count, nil
+}
+
+// limitRange returns up to count members with ranks from start to end exclusive, skipping the first offset
+// members. When reverse is true, the members are taken from the end of the range in descending order.
+func limitRange(set *SortedSet, start, end, offset, count int, reverse bool) []MemberParam {
+	n := end - start - offset
+	if offset < 0 || n <= 0 {
+		return []MemberParam{}
+	}
+	if count >= 0 {
+		n = min(n, count)
+	}
+	if reverse {
+		return set.RangeByRank(end-offset-n, end-offset-1, true)
+	}
+	return set.RangeByRank(start+offset, start+offset+n-1, false)
+}
+
+// encodeMemberArray returns the members as a flat RESP array, with each member followed by its score when
+// withscores is true.
+func encodeMemberArray(members []MemberParam, withscores bool) []byte {
+	length := len(members)
+	if withscores {
+		length *= 2
+	}
+	res := fmt.Sprintf("*%d\r\n", length)
+	for _, m := range members {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(m.Value), m.Value)
+		if withscores {
+			score := strconv.FormatFloat(float64(m.Score), 'f', -1, 64)
+			res += fmt.Sprintf("$%d\r\n%s\r\n", len(score), score)
+		}
+	}
+	return []byte(res)
+}
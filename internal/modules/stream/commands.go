@@ -0,0 +1,897 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+func handleXAdd(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xaddKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	key := keys.WriteKeys[0]
+
+	noMkStream := false
+	maxLen := -1
+	i := 2
+options:
+	for ; i < len(params.Command); i++ {
+		switch strings.ToLower(params.Command[i]) {
+		default:
+			break options
+		case "nomkstream":
+			noMkStream = true
+		case "maxlen":
+			i++
+			if i < len(params.Command) && slices.Contains([]string{"=", "~"}, params.Command[i]) {
+				i++
+			}
+			if i >= len(params.Command) {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			if maxLen, err = strconv.Atoi(params.Command[i]); err != nil || maxLen < 0 {
+				return nil, errors.New("MAXLEN must be a non-negative integer")
+			}
+		}
+	}
+
+	fields := params.Command[min(i+1, len(params.Command)):]
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	stream, err := getStream(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil {
+		if noMkStream {
+			return []byte("$-1\r\n"), nil
+		}
+		stream = NewStream()
+	}
+
+	id, err := stream.NextID(params.GetClock().Now(), params.Command[i])
+	if err != nil {
+		return nil, err
+	}
+	// Log the generated ID so that replaying the command adds the entry with the same ID.
+	if params.Command[i] != id.String() {
+		cmd := slices.Clone(params.Command)
+		cmd[i] = id.String()
+		internal.PropagateCommand(params.Context, cmd)
+	}
+	stream.Add(id, slices.Clone(fields))
+	if maxLen >= 0 {
+		stream.Trim(maxLen)
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: stream}); err != nil {
+		return nil, err
+	}
+
+	return []byte(encodeBulkString(id.String())), nil
+}
+
+func handleXLen(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xlenKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := getStream(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil {
+		return []byte(":0\r\n"), nil
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", stream.Len())), nil
+}
+
+func parseRangeArgs(cmd []string, reverse bool) (start ID, end ID, count int, err error) {
+	startArg, endArg := cmd[2], cmd[3]
+	if reverse {
+		startArg, endArg = endArg, startArg
+	}
+	if start, err = parseRangeID(startArg, false); err != nil {
+		return
+	}
+	if end, err = parseRangeID(endArg, true); err != nil {
+		return
+	}
+	if len(cmd) == 6 {
+		if !strings.EqualFold(cmd[4], "count") {
+			err = fmt.Errorf("unknown option %s", cmd[4])
+			return
+		}
+		count, err = parseCount(cmd[5])
+	}
+	return
+}
+
+func handleXRange(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xrangeKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, count, err := parseRangeArgs(params.Command, false)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := getStream(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil || (len(params.Command) == 6 && count == 0) {
+		return []byte("*0\r\n"), nil
+	}
+
+	return []byte(encodeEntries(stream.Range(start, end, count))), nil
+}
+
+func handleXRevRange(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xrevrangeKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, count, err := parseRangeArgs(params.Command, true)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := getStream(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil || (len(params.Command) == 6 && count == 0) {
+		return []byte("*0\r\n"), nil
+	}
+
+	return []byte(encodeEntries(stream.RevRange(end, start, count))), nil
+}
+
+type readOptions struct {
+	count   int
+	block   bool
+	timeout time.Duration
+	noAck   bool
+}
+
+// parseReadOptions parses the options of XREAD and XREADGROUP that come before the STREAMS keyword.
+func parseReadOptions(args []string, allowNoAck bool) (readOptions, error) {
+	options := readOptions{}
+	for i := 0; i < len(args); i++ {
+		switch strings.ToLower(args[i]) {
+		case "count":
+			if i+1 >= len(args) {
+				return options, errors.New(constants.WrongArgsResponse)
+			}
+			count, err := parseCount(args[i+1])
+			if err != nil {
+				return options, err
+			}
+			options.count = count
+			i++
+		case "block":
+			if i+1 >= len(args) {
+				return options, errors.New(constants.WrongArgsResponse)
+			}
+			ms, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || ms < 0 {
+				return options, errors.New("timeout is not an integer or out of range")
+			}
+			options.block = true
+			options.timeout = time.Duration(ms) * time.Millisecond
+			i++
+		case "noack":
+			if !allowNoAck {
+				return options, fmt.Errorf("unknown option %s", args[i])
+			}
+			options.noAck = true
+		default:
+			return options, fmt.Errorf("unknown option %s", args[i])
+		}
+	}
+	return options, nil
+}
+
+// streamsIndex returns the index of the STREAMS keyword in the command.
+func streamsIndex(cmd []string) int {
+	return slices.IndexFunc(cmd, func(arg string) bool {
+		return strings.EqualFold(arg, "streams")
+	})
+}
+
+// blockTimeout returns the channel that receives when a blocking read times out.
+// A nil channel is returned when the command blocks indefinitely.
+func blockTimeout(params internal.HandlerFuncParams, options readOptions) <-chan time.Time {
+	if !options.block || options.timeout == 0 {
+		return nil
+	}
+	return params.GetClock().After(options.timeout)
+}
+
+func handleXRead(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xreadKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	index := streamsIndex(params.Command)
+	options, err := parseReadOptions(params.Command[1:index], false)
+	if err != nil {
+		return nil, err
+	}
+	rawIDs := params.Command[index+1+len(keys.ReadKeys):]
+
+	// Resolve the IDs before blocking so that "$" refers to the last ID at the time the command was received.
+	ids := make(map[string]ID, len(keys.ReadKeys))
+	for i, key := range keys.ReadKeys {
+		if rawIDs[i] != "$" {
+			if ids[key], err = ParseID(rawIDs[i], 0); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		stream, err := getStream(params, key)
+		if err != nil {
+			return nil, err
+		}
+		ids[key] = MinID
+		if stream != nil {
+			ids[key] = stream.LastID()
+		}
+	}
+
+	timeout := blockTimeout(params, options)
+	for {
+		wait, cancel := func(timeout <-chan time.Time) bool { return false }, func() {}
+		if options.block {
+			// Register the wait before reading so that entries added after the read wake the client.
+			wait, cancel = params.AwaitKeys(params.Context, keys.ReadKeys)
+		}
+
+		entries := make(map[string][]Entry, len(keys.ReadKeys))
+		found := false
+		for _, key := range keys.ReadKeys {
+			stream, err := getStream(params, key)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			if stream == nil {
+				continue
+			}
+			entries[key] = stream.After(ids[key], options.count)
+			found = found || len(entries[key]) > 0
+		}
+
+		if found {
+			cancel()
+			return encodeStreams(keys.ReadKeys, entries, false), nil
+		}
+		if !wait(timeout) {
+			return []byte("*-1\r\n"), nil
+		}
+	}
+}
+
+func handleXReadGroup(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xreadgroupKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(params.Command[1], "group") {
+		return nil, errors.New("missing GROUP option")
+	}
+	group, consumer := params.Command[2], params.Command[3]
+
+	index := streamsIndex(params.Command)
+	if index < 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	options, err := parseReadOptions(params.Command[4:index], true)
+	if err != nil {
+		return nil, err
+	}
+	rawIDs := params.Command[index+1+len(keys.WriteKeys):]
+
+	// Only block when reading new entries. Reading the consumer's pending entries never blocks.
+	history := slices.ContainsFunc(rawIDs, func(id string) bool { return id != ">" })
+	if history {
+		options.block = false
+	}
+
+	timeout := blockTimeout(params, options)
+	for {
+		wait, cancel := func(timeout <-chan time.Time) bool { return false }, func() {}
+		if options.block {
+			wait, cancel = params.AwaitKeys(params.Context, keys.WriteKeys)
+		}
+
+		entries := make(map[string][]Entry, len(keys.WriteKeys))
+		found := false
+		for i, key := range keys.WriteKeys {
+			stream, err := getStream(params, key)
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			if stream == nil || !stream.HasGroup(group) {
+				cancel()
+				return nil, fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", key, group)
+			}
+			entries[key], err = stream.ReadGroup(group, consumer, rawIDs[i], options.count, options.noAck, params.GetClock().Now())
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			found = found || len(entries[key]) > 0
+		}
+
+		if found || history {
+			cancel()
+			return encodeStreams(keys.WriteKeys, entries, history), nil
+		}
+		if !wait(timeout) {
+			return []byte("*-1\r\n"), nil
+		}
+	}
+}
+
+// getGroupStream returns the stream at the key for the XGROUP subcommands. The key must exist.
+func getGroupStream(params internal.HandlerFuncParams, key string) (*Stream, error) {
+	stream, err := getStream(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil {
+		return nil, errors.New("the XGROUP subcommand requires the key to exist, use the MKSTREAM option to create the stream automatically")
+	}
+	return stream, nil
+}
+
+// parseGroupID parses the ID of XGROUP CREATE and XGROUP SETID. "$" is the last ID of the stream.
+func parseGroupID(stream *Stream, id string) (ID, error) {
+	if id == "$" {
+		return stream.LastID(), nil
+	}
+	return ParseID(id, 0)
+}
+
+func handleXGroupCreate(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xgroupCreateKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	key := keys.WriteKeys[0]
+
+	mkStream := false
+	if len(params.Command) == 6 {
+		if !strings.EqualFold(params.Command[5], "mkstream") {
+			return nil, fmt.Errorf("unknown option %s", params.Command[5])
+		}
+		mkStream = true
+	}
+
+	stream, err := getStream(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil {
+		if !mkStream {
+			return nil, errors.New("the XGROUP subcommand requires the key to exist, use the MKSTREAM option to create the stream automatically")
+		}
+		stream = NewStream()
+	}
+
+	id, err := parseGroupID(stream, params.Command[4])
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.CreateGroup(params.Command[3], id); err != nil {
+		return nil, err
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{key: stream}); err != nil {
+		return nil, err
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleXGroupDestroy(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xgroupDestroyKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := getGroupStream(params, keys.WriteKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if stream.DestroyGroup(params.Command[3]) {
+		return []byte(":1\r\n"), nil
+	}
+	return []byte(":0\r\n"), nil
+}
+
+func handleXGroupCreateConsumer(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xgroupConsumerKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := getGroupStream(params, keys.WriteKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := stream.CreateConsumer(params.Command[3], params.Command[4], params.GetClock().Now())
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		return []byte(":1\r\n"), nil
+	}
+	return []byte(":0\r\n"), nil
+}
+
+func handleXGroupDelConsumer(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xgroupConsumerKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := getGroupStream(params, keys.WriteKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := stream.DeleteConsumer(params.Command[3], params.Command[4])
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", pending)), nil
+}
+
+func handleXGroupSetID(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xgroupSetIDKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := getGroupStream(params, keys.WriteKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := parseGroupID(stream, params.Command[4])
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.SetGroupID(params.Command[3], id); err != nil {
+		return nil, err
+	}
+
+	return []byte(constants.OkResponse), nil
+}
+
+func handleXAck(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xackKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]ID, len(params.Command[3:]))
+	for i, rawID := range params.Command[3:] {
+		if ids[i], err = ParseID(rawID, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	stream, err := getStream(params, keys.WriteKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil || !stream.HasGroup(params.Command[2]) {
+		return []byte(":0\r\n"), nil
+	}
+
+	count, err := stream.Ack(params.Command[2], ids)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+}
+
+func handleXPending(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xpendingKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	key, group := keys.ReadKeys[0], params.Command[2]
+
+	stream, err := getStream(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil || !stream.HasGroup(group) {
+		return nil, fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", key, group)
+	}
+	now := params.GetClock().Now()
+
+	// Summary form
+	if len(params.Command) == 3 {
+		pending, err := stream.Pending(group, MinID, MaxID, 0, "", 0, now)
+		if err != nil {
+			return nil, err
+		}
+		if len(pending) == 0 {
+			return []byte("*4\r\n:0\r\n$-1\r\n$-1\r\n*-1\r\n"), nil
+		}
+
+		consumers := make([]string, 0)
+		counts := make(map[string]int)
+		for _, entry := range pending {
+			if counts[entry.Consumer] == 0 {
+				consumers = append(consumers, entry.Consumer)
+			}
+			counts[entry.Consumer]++
+		}
+		slices.Sort(consumers)
+
+		res := fmt.Sprintf("*4\r\n:%d\r\n%s%s*%d\r\n", len(pending),
+			encodeBulkString(pending[0].ID.String()), encodeBulkString(pending[len(pending)-1].ID.String()), len(consumers))
+		for _, consumer := range consumers {
+			res += "*2\r\n" + encodeBulkString(consumer) + encodeBulkString(strconv.Itoa(counts[consumer]))
+		}
+		return []byte(res), nil
+	}
+
+	// Extended form
+	args := params.Command[3:]
+	var minIdle time.Duration
+	if strings.EqualFold(args[0], "idle") {
+		if len(args) < 2 {
+			return nil, errors.New(constants.WrongArgsResponse)
+		}
+		ms, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || ms < 0 {
+			return nil, errors.New("IDLE must be a non-negative integer")
+		}
+		minIdle = time.Duration(ms) * time.Millisecond
+		args = args[2:]
+	}
+	if len(args) < 3 || len(args) > 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	start, err := parseRangeID(args[0], false)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseRangeID(args[1], true)
+	if err != nil {
+		return nil, err
+	}
+	count, err := parseCount(args[2])
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return []byte("*0\r\n"), nil
+	}
+	consumer := ""
+	if len(args) == 4 {
+		consumer = args[3]
+	}
+
+	pending, err := stream.Pending(group, start, end, count, consumer, minIdle, now)
+	if err != nil {
+		return nil, err
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(pending))
+	for _, entry := range pending {
+		res += fmt.Sprintf("*4\r\n%s%s:%d\r\n:%d\r\n",
+			encodeBulkString(entry.ID.String()), encodeBulkString(entry.Consumer),
+			now.Sub(entry.DeliveredAt).Milliseconds(), entry.DeliveryCount)
+	}
+	return []byte(res), nil
+}
+
+func handleXClaim(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := xclaimKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	key, group, consumer := keys.WriteKeys[0], params.Command[2], params.Command[3]
+
+	ms, err := strconv.ParseInt(params.Command[4], 10, 64)
+	if err != nil || ms < 0 {
+		return nil, errors.New("min-idle-time must be a non-negative integer")
+	}
+	minIdle := time.Duration(ms) * time.Millisecond
+	now := params.GetClock().Now()
+
+	// The IDs are followed by the options.
+	ids := make([]ID, 0)
+	i := 5
+	for ; i < len(params.Command); i++ {
+		id, err := ParseID(params.Command[i], 0)
+		if err != nil {
+			break
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	options := ClaimOptions{}
+	for ; i < len(params.Command); i++ {
+		switch strings.ToLower(params.Command[i]) {
+		default:
+			return nil, fmt.Errorf("unknown option %s", params.Command[i])
+		case "force":
+			options.Force = true
+		case "justid":
+			options.JustID = true
+		case "idle", "time", "retrycount":
+			if i+1 >= len(params.Command) {
+				return nil, errors.New(constants.WrongArgsResponse)
+			}
+			n, err := strconv.ParseInt(params.Command[i+1], 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s must be a non-negative integer", strings.ToUpper(params.Command[i]))
+			}
+			switch strings.ToLower(params.Command[i]) {
+			case "idle":
+				deliveredAt := now.Add(-time.Duration(n) * time.Millisecond)
+				options.DeliveredAt = &deliveredAt
+			case "time":
+				deliveredAt := time.UnixMilli(n)
+				options.DeliveredAt = &deliveredAt
+			case "retrycount":
+				count := int(n)
+				options.DeliveryCount = &count
+			}
+			i++
+		}
+	}
+
+	stream, err := getStream(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if stream == nil || !stream.HasGroup(group) {
+		return nil, fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", key, group)
+	}
+
+	claimed, err := stream.Claim(group, consumer, minIdle, ids, now, options)
+	if err != nil {
+		return nil, err
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(claimed))
+	for _, id := range claimed {
+		if options.JustID {
+			res += encodeBulkString(id.String())
+			continue
+		}
+		entry, _ := stream.Get(id)
+		res += encodeEntry(entry)
+	}
+	return []byte(res), nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "xadd",
+			Module:     constants.StreamModule,
+			Categories: []string{constants.StreamCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(XADD key [NOMKSTREAM] [MAXLEN [= | ~] threshold] <* | id> field value [field value ...])
+Appends an entry to the stream at key and returns the entry's ID. The stream is created if it does not exist
+unless NOMKSTREAM is provided. With "*", the ID is generated from the server time.
+MAXLEN trims the stream to the given number of entries after the entry is added.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xaddKeyFunc,
+			HandlerFunc:       handleXAdd,
+		},
+		{
+			Command:           "xlen",
+			Module:            constants.StreamModule,
+			Categories:        []string{constants.StreamCategory, constants.ReadCategory, constants.FastCategory},
+			Description:       "(XLEN key) Returns the number of entries in the stream at key.",
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xlenKeyFunc,
+			HandlerFunc:       handleXLen,
+		},
+		{
+			Command:    "xrange",
+			Module:     constants.StreamModule,
+			Categories: []string{constants.StreamCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(XRANGE key start end [COUNT count]) Returns the entries of the stream with IDs between start and end.
+"-" and "+" are the smallest and greatest possible IDs. Prefix an ID with "(" to make it exclusive.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xrangeKeyFunc,
+			HandlerFunc:       handleXRange,
+		},
+		{
+			Command:    "xrevrange",
+			Module:     constants.StreamModule,
+			Categories: []string{constants.StreamCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(XREVRANGE key end start [COUNT count]) Returns the entries of the stream with IDs between end and start
+in reverse order.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xrevrangeKeyFunc,
+			HandlerFunc:       handleXRevRange,
+		},
+		{
+			Command: "xread",
+			Module:  constants.StreamModule,
+			Categories: []string{
+				constants.StreamCategory, constants.ReadCategory, constants.SlowCategory, constants.BlockingCategory,
+			},
+			Description: `(XREAD [COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...])
+Returns the entries with IDs greater than the given ID from each stream. "$" is the last ID of the stream.
+With BLOCK, waits for new entries for the given number of milliseconds if there are none. 0 blocks indefinitely.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xreadKeyFunc,
+			HandlerFunc:       handleXRead,
+		},
+		{
+			Command: "xreadgroup",
+			Module:  constants.StreamModule,
+			Categories: []string{
+				constants.StreamCategory, constants.WriteCategory, constants.SlowCategory, constants.BlockingCategory,
+			},
+			Description: `(XREADGROUP GROUP group consumer [COUNT count] [BLOCK milliseconds] [NOACK] STREAMS key [key ...] id [id ...])
+Reads entries from the streams on behalf of a consumer of the group. With ">", the entries that were never
+delivered to the group are returned and added to the group's pending entries list unless NOACK is provided.
+With any other ID, the consumer's pending entries with greater IDs are returned.
+BLOCK only applies when all the IDs are ">".`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xreadgroupKeyFunc,
+			HandlerFunc:       handleXReadGroup,
+		},
+		{
+			Command:     "xgroup",
+			Module:      constants.StreamModule,
+			Categories:  []string{},
+			Description: "Stream consumer group commands",
+			Sync:        false,
+			Type:        "BUILT_IN",
+			KeyExtractionFunc: func(cmd []string) (internal.KeyExtractionFuncResult, error) {
+				return internal.KeyExtractionFuncResult{
+					Channels:  make([]string, 0),
+					ReadKeys:  make([]string, 0),
+					WriteKeys: make([]string, 0),
+				}, nil
+			},
+			SubCommands: []internal.SubCommand{
+				{
+					Command:    "create",
+					Module:     constants.StreamModule,
+					Categories: []string{constants.StreamCategory, constants.WriteCategory, constants.SlowCategory},
+					Description: `(XGROUP CREATE key group <id | $> [MKSTREAM]) Creates a consumer group that delivers the entries
+with IDs greater than id. MKSTREAM creates the stream if it does not exist.`,
+					Sync:              true,
+					KeyExtractionFunc: xgroupCreateKeyFunc,
+					HandlerFunc:       handleXGroupCreate,
+				},
+				{
+					Command:           "destroy",
+					Module:            constants.StreamModule,
+					Categories:        []string{constants.StreamCategory, constants.WriteCategory, constants.SlowCategory},
+					Description:       "(XGROUP DESTROY key group) Deletes the consumer group. Returns 1 if the group was deleted and 0 otherwise.",
+					Sync:              true,
+					KeyExtractionFunc: xgroupDestroyKeyFunc,
+					HandlerFunc:       handleXGroupDestroy,
+				},
+				{
+					Command:    "createconsumer",
+					Module:     constants.StreamModule,
+					Categories: []string{constants.StreamCategory, constants.WriteCategory, constants.SlowCategory},
+					Description: `(XGROUP CREATECONSUMER key group consumer) Creates a consumer in the group.
+Returns 1 if the consumer was created and 0 if it already exists.`,
+					Sync:              true,
+					KeyExtractionFunc: xgroupConsumerKeyFunc,
+					HandlerFunc:       handleXGroupCreateConsumer,
+				},
+				{
+					Command:    "delconsumer",
+					Module:     constants.StreamModule,
+					Categories: []string{constants.StreamCategory, constants.WriteCategory, constants.SlowCategory},
+					Description: `(XGROUP DELCONSUMER key group consumer) Deletes the consumer from the group along with its
+pending entries. Returns the number of pending entries the consumer had.`,
+					Sync:              true,
+					KeyExtractionFunc: xgroupConsumerKeyFunc,
+					HandlerFunc:       handleXGroupDelConsumer,
+				},
+				{
+					Command:           "setid",
+					Module:            constants.StreamModule,
+					Categories:        []string{constants.StreamCategory, constants.WriteCategory, constants.SlowCategory},
+					Description:       "(XGROUP SETID key group <id | $>) Sets the last delivered ID of the consumer group.",
+					Sync:              true,
+					KeyExtractionFunc: xgroupSetIDKeyFunc,
+					HandlerFunc:       handleXGroupSetID,
+				},
+			},
+		},
+		{
+			Command:    "xack",
+			Module:     constants.StreamModule,
+			Categories: []string{constants.StreamCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(XACK key group id [id ...]) Removes the entries from the pending entries list of the group.
+Returns the number of acknowledged entries.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xackKeyFunc,
+			HandlerFunc:       handleXAck,
+		},
+		{
+			Command:    "xpending",
+			Module:     constants.StreamModule,
+			Categories: []string{constants.StreamCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(XPENDING key group [[IDLE min-idle-time] start end count [consumer]])
+Without a range, returns a summary of the group's pending entries list: the number of pending entries, the smallest
+and greatest pending IDs and the number of pending entries of each consumer.
+With a range, returns the ID, consumer, idle time and delivery count of each pending entry in the range.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xpendingKeyFunc,
+			HandlerFunc:       handleXPending,
+		},
+		{
+			Command:    "xclaim",
+			Module:     constants.StreamModule,
+			Categories: []string{constants.StreamCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(XCLAIM key group consumer min-idle-time id [id ...] [IDLE ms] [TIME unix-time-milliseconds]
+[RETRYCOUNT count] [FORCE] [JUSTID]) Changes the ownership of the pending entries that have been idle for at least
+min-idle-time to the consumer and returns the claimed entries.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: xclaimKeyFunc,
+			HandlerFunc:       handleXClaim,
+		},
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream_test
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+// The mock clock's time in milliseconds. IDs generated with "*" start at this timestamp.
+const now = "1136189045000"
+
+type step struct {
+	command          []string
+	expectedResponse string // Expected response formatted by format.
+	expectedError    string // Expected substring of the error response.
+}
+
+// format returns the string representation of a response. Arrays are formatted as "[a b c]" and nulls as "<nil>".
+func format(v resp.Value) string {
+	if v.IsNull() {
+		return "<nil>"
+	}
+	if v.Type() != resp.Array {
+		return v.String()
+	}
+	elems := make([]string, len(v.Array()))
+	for i, elem := range v.Array() {
+		elems[i] = format(elem)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elems, " "))
+}
+
+func send(client *resp.Conn, command []string) (resp.Value, error) {
+	values := make([]resp.Value, len(command))
+	for i, c := range command {
+		values[i] = resp.StringValue(c)
+	}
+	if err := client.WriteArray(values); err != nil {
+		return resp.Value{}, err
+	}
+	res, _, err := client.ReadValue()
+	return res, err
+}
+
+func runSteps(t *testing.T, client *resp.Conn, steps []step) {
+	for i, s := range steps {
+		res, err := send(client, s.command)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if s.expectedError != "" {
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), s.expectedError) {
+				t.Errorf("step %d %v: expected error \"%s\", got \"%s\"", i, s.command, s.expectedError, format(res))
+			}
+			continue
+		}
+		if got := format(res); got != s.expectedResponse {
+			t.Errorf("step %d %v: expected response \"%s\", got \"%s\"", i, s.command, s.expectedResponse, got)
+		}
+	}
+}
+
+func connect(t *testing.T, port int) (net.Conn, *resp.Conn) {
+	conn, err := internal.GetConnection("localhost", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, resp.NewConn(conn)
+}
+
+func Test_Stream(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "1. XADD generates IDs from the server clock",
+			steps: []step{
+				{command: []string{"XADD", "XAddKey1", "*", "field1", "value1"}, expectedResponse: now + "-0"},
+				{command: []string{"XADD", "XAddKey1", "*", "field2", "value2"}, expectedResponse: now + "-1"},
+				{command: []string{"XADD", "XAddKey1", "5-*", "field3", "value3"}, expectedError: "equal or smaller"},
+				{command: []string{"XLEN", "XAddKey1"}, expectedResponse: "2"},
+			},
+		},
+		{
+			name: "2. XADD with explicit IDs, NOMKSTREAM and MAXLEN",
+			steps: []step{
+				{command: []string{"XADD", "XAddKey2", "NOMKSTREAM", "*", "f", "v"}, expectedResponse: "<nil>"},
+				{command: []string{"XADD", "XAddKey2", "0-0", "f", "v"}, expectedError: "must be greater than 0-0"},
+				{command: []string{"XADD", "XAddKey2", "1-1", "f", "v"}, expectedResponse: "1-1"},
+				{command: []string{"XADD", "XAddKey2", "1-1", "f", "v"}, expectedError: "equal or smaller"},
+				{command: []string{"XADD", "XAddKey2", "1-*", "f", "v"}, expectedResponse: "1-2"},
+				{command: []string{"XADD", "XAddKey2", "2-*", "f", "v"}, expectedResponse: "2-0"},
+				{command: []string{"XADD", "XAddKey2", "MAXLEN", "~", "2", "3", "f", "v"}, expectedResponse: "3-0"},
+				{command: []string{"XRANGE", "XAddKey2", "-", "+"}, expectedResponse: "[[2-0 [f v]] [3-0 [f v]]]"},
+				{command: []string{"XADD", "XAddKey2", "4", "f"}, expectedError: constants.WrongArgsResponse},
+				{command: []string{"SET", "XAddKey3", "value"}, expectedResponse: "OK"},
+				{command: []string{"XADD", "XAddKey3", "*", "f", "v"}, expectedError: "value at key XAddKey3 is not a stream"},
+				{command: []string{"XLEN", "XAddKey4"}, expectedResponse: "0"},
+			},
+		},
+		{
+			name: "3. XRANGE and XREVRANGE",
+			steps: []step{
+				{command: []string{"XADD", "XRangeKey1", "1-0", "a", "1"}, expectedResponse: "1-0"},
+				{command: []string{"XADD", "XRangeKey1", "1-1", "b", "2"}, expectedResponse: "1-1"},
+				{command: []string{"XADD", "XRangeKey1", "2-0", "c", "3", "d", "4"}, expectedResponse: "2-0"},
+				{command: []string{"XRANGE", "XRangeKey1", "1", "1"}, expectedResponse: "[[1-0 [a 1]] [1-1 [b 2]]]"},
+				{command: []string{"XRANGE", "XRangeKey1", "(1-0", "+", "COUNT", "1"}, expectedResponse: "[[1-1 [b 2]]]"},
+				{command: []string{"XRANGE", "XRangeKey1", "3", "+"}, expectedResponse: "[]"},
+				{command: []string{"XREVRANGE", "XRangeKey1", "+", "-", "COUNT", "2"}, expectedResponse: "[[2-0 [c 3 d 4]] [1-1 [b 2]]]"},
+				{command: []string{"XREVRANGE", "XRangeKey1", "1-1", "-"}, expectedResponse: "[[1-1 [b 2]] [1-0 [a 1]]]"},
+				{command: []string{"XRANGE", "XRangeKey1", "x", "+"}, expectedError: "invalid stream ID"},
+				{command: []string{"XRANGE", "XRangeKey2", "-", "+"}, expectedResponse: "[]"},
+			},
+		},
+		{
+			name: "4. XREAD returns entries after the given IDs",
+			steps: []step{
+				{command: []string{"XADD", "XReadKey1", "1-0", "a", "1"}, expectedResponse: "1-0"},
+				{command: []string{"XADD", "XReadKey1", "2-0", "b", "2"}, expectedResponse: "2-0"},
+				{command: []string{"XADD", "XReadKey2", "1-0", "c", "3"}, expectedResponse: "1-0"},
+				{
+					command:          []string{"XREAD", "COUNT", "1", "STREAMS", "XReadKey1", "XReadKey2", "0", "0"},
+					expectedResponse: "[[XReadKey1 [[1-0 [a 1]]]] [XReadKey2 [[1-0 [c 3]]]]]",
+				},
+				{
+					command:          []string{"XREAD", "STREAMS", "XReadKey1", "XReadKey2", "1-0", "1-0"},
+					expectedResponse: "[[XReadKey1 [[2-0 [b 2]]]]]",
+				},
+				{command: []string{"XREAD", "STREAMS", "XReadKey1", "$"}, expectedResponse: "<nil>"},
+				{command: []string{"XREAD", "BLOCK", "10", "STREAMS", "XReadKey1", "$"}, expectedResponse: "<nil>"},
+				{command: []string{"XREAD", "STREAMS", "XReadKey1", "XReadKey2", "0"}, expectedError: "unbalanced"},
+			},
+		},
+		{
+			name: "5. Consumer groups deliver entries and track them in the pending entries list",
+			steps: []step{
+				{command: []string{"XGROUP", "CREATE", "XGroupKey1", "group", "$"}, expectedError: "requires the key to exist"},
+				{command: []string{"XGROUP", "CREATE", "XGroupKey1", "group", "$", "MKSTREAM"}, expectedResponse: "OK"},
+				{command: []string{"XGROUP", "CREATE", "XGroupKey1", "group", "$"}, expectedError: "BUSYGROUP"},
+				{command: []string{"XADD", "XGroupKey1", "1-0", "a", "1"}, expectedResponse: "1-0"},
+				{command: []string{"XADD", "XGroupKey1", "2-0", "b", "2"}, expectedResponse: "2-0"},
+				{command: []string{"XADD", "XGroupKey1", "3-0", "c", "3"}, expectedResponse: "3-0"},
+				{
+					command:          []string{"XREADGROUP", "GROUP", "group", "alice", "COUNT", "2", "STREAMS", "XGroupKey1", ">"},
+					expectedResponse: "[[XGroupKey1 [[1-0 [a 1]] [2-0 [b 2]]]]]",
+				},
+				{
+					command:          []string{"XREADGROUP", "GROUP", "group", "bob", "STREAMS", "XGroupKey1", ">"},
+					expectedResponse: "[[XGroupKey1 [[3-0 [c 3]]]]]",
+				},
+				{command: []string{"XREADGROUP", "GROUP", "group", "bob", "STREAMS", "XGroupKey1", ">"}, expectedResponse: "<nil>"},
+				{
+					command:          []string{"XREADGROUP", "GROUP", "group", "alice", "STREAMS", "XGroupKey1", "0"},
+					expectedResponse: "[[XGroupKey1 [[1-0 [a 1]] [2-0 [b 2]]]]]",
+				},
+				{command: []string{"XPENDING", "XGroupKey1", "group"}, expectedResponse: "[3 1-0 3-0 [[alice 2] [bob 1]]]"},
+				{
+					command:          []string{"XPENDING", "XGroupKey1", "group", "-", "+", "10", "alice"},
+					expectedResponse: "[[1-0 alice 0 1] [2-0 alice 0 1]]",
+				},
+				{command: []string{"XACK", "XGroupKey1", "group", "1-0", "5-0"}, expectedResponse: "1"},
+				{command: []string{"XCLAIM", "XGroupKey1", "group", "bob", "0", "2-0"}, expectedResponse: "[[2-0 [b 2]]]"},
+				{
+					command:          []string{"XPENDING", "XGroupKey1", "group", "-", "+", "10"},
+					expectedResponse: "[[2-0 bob 0 2] [3-0 bob 0 1]]",
+				},
+				{command: []string{"XCLAIM", "XGroupKey1", "group", "alice", "10000", "2-0"}, expectedResponse: "[]"},
+				{
+					command:          []string{"XCLAIM", "XGroupKey1", "group", "alice", "0", "3-0", "RETRYCOUNT", "5", "JUSTID"},
+					expectedResponse: "[3-0]",
+				},
+				{command: []string{"XGROUP", "DELCONSUMER", "XGroupKey1", "group", "bob"}, expectedResponse: "1"},
+				{command: []string{"XPENDING", "XGroupKey1", "group"}, expectedResponse: "[1 3-0 3-0 [[alice 1]]]"},
+				{command: []string{"XGROUP", "CREATECONSUMER", "XGroupKey1", "group", "carol"}, expectedResponse: "1"},
+				{command: []string{"XGROUP", "CREATECONSUMER", "XGroupKey1", "group", "carol"}, expectedResponse: "0"},
+				{command: []string{"XGROUP", "SETID", "XGroupKey1", "group", "0"}, expectedResponse: "OK"},
+				{
+					command:          []string{"XREADGROUP", "GROUP", "group", "carol", "COUNT", "1", "NOACK", "STREAMS", "XGroupKey1", ">"},
+					expectedResponse: "[[XGroupKey1 [[1-0 [a 1]]]]]",
+				},
+				{command: []string{"XPENDING", "XGroupKey1", "group"}, expectedResponse: "[1 3-0 3-0 [[alice 1]]]"},
+				{command: []string{"XGROUP", "DESTROY", "XGroupKey1", "group"}, expectedResponse: "1"},
+				{command: []string{"XGROUP", "DESTROY", "XGroupKey1", "group"}, expectedResponse: "0"},
+				{command: []string{"XPENDING", "XGroupKey1", "group"}, expectedError: "NOGROUP"},
+				{command: []string{"XREADGROUP", "GROUP", "group", "alice", "STREAMS", "XGroupKey1", ">"}, expectedError: "NOGROUP"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, client := connect(t, port)
+			defer func() {
+				_ = conn.Close()
+			}()
+			runSteps(t, client, test.steps)
+		})
+	}
+
+	t.Run("Test_HandleXREAD_Block", func(t *testing.T) {
+		conn, client := connect(t, port)
+		defer func() {
+			_ = conn.Close()
+		}()
+		writerConn, writer := connect(t, port)
+		defer func() {
+			_ = writerConn.Close()
+		}()
+
+		runSteps(t, writer, []step{
+			{command: []string{"XADD", "XReadBlockKey1", "1-0", "a", "1"}, expectedResponse: "1-0"},
+		})
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			runSteps(t, writer, []step{
+				{command: []string{"XADD", "XReadBlockKey1", "2-0", "b", "2"}, expectedResponse: "2-0"},
+			})
+		}()
+
+		// The "$" ID is resolved when the command is received, so only the entry added while blocked is returned.
+		runSteps(t, client, []step{
+			{
+				command:          []string{"XREAD", "BLOCK", "0", "STREAMS", "XReadBlockKey1", "$"},
+				expectedResponse: "[[XReadBlockKey1 [[2-0 [b 2]]]]]",
+			},
+		})
+	})
+
+	t.Run("Test_HandleXREADGROUP_Block", func(t *testing.T) {
+		conn, client := connect(t, port)
+		defer func() {
+			_ = conn.Close()
+		}()
+		writerConn, writer := connect(t, port)
+		defer func() {
+			_ = writerConn.Close()
+		}()
+
+		runSteps(t, writer, []step{
+			{command: []string{"XGROUP", "CREATE", "XReadGroupBlockKey1", "group", "$", "MKSTREAM"}, expectedResponse: "OK"},
+		})
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			runSteps(t, writer, []step{
+				{command: []string{"XADD", "XReadGroupBlockKey1", "1-0", "a", "1"}, expectedResponse: "1-0"},
+			})
+		}()
+
+		runSteps(t, client, []step{
+			{
+				command:          []string{"XREADGROUP", "GROUP", "group", "alice", "BLOCK", "5000", "STREAMS", "XReadGroupBlockKey1", ">"},
+				expectedResponse: "[[XReadGroupBlockKey1 [[1-0 [a 1]]]]]",
+			},
+			{command: []string{"XPENDING", "XReadGroupBlockKey1", "group"}, expectedResponse: "[1 1-0 1-0 [[alice 1]]]"},
+		})
+	})
+
+	t.Run("Test_HandleXREAD_BlockInTransaction", func(t *testing.T) {
+		conn, client := connect(t, port)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		// Blocking commands do not block inside a transaction.
+		runSteps(t, client, []step{
+			{command: []string{"MULTI"}, expectedResponse: "OK"},
+			{command: []string{"XREAD", "BLOCK", "0", "STREAMS", "XReadBlockKey2", "$"}, expectedResponse: "QUEUED"},
+			{command: []string{"EXEC"}, expectedResponse: "[<nil>]"},
+		})
+	})
+}
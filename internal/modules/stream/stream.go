@@ -0,0 +1,628 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// ID is the ID of a stream entry. It's made up of a millisecond timestamp and a sequence number.
+type ID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+var (
+	MinID = ID{Ms: 0, Seq: 0}
+	MaxID = ID{Ms: math.MaxUint64, Seq: math.MaxUint64}
+)
+
+func (id ID) String() string {
+	return fmt.Sprintf("%d-%d", id.Ms, id.Seq)
+}
+
+// Compare returns -1 if id is less than other, 0 if they're equal and 1 if id is greater than other.
+func (id ID) Compare(other ID) int {
+	switch {
+	case id.Ms < other.Ms:
+		return -1
+	case id.Ms > other.Ms:
+		return 1
+	case id.Seq < other.Seq:
+		return -1
+	case id.Seq > other.Seq:
+		return 1
+	}
+	return 0
+}
+
+// Next returns the smallest ID that is greater than id.
+func (id ID) Next() ID {
+	if id.Seq == math.MaxUint64 {
+		if id.Ms == math.MaxUint64 {
+			return id
+		}
+		return ID{Ms: id.Ms + 1, Seq: 0}
+	}
+	return ID{Ms: id.Ms, Seq: id.Seq + 1}
+}
+
+// Prev returns the greatest ID that is less than id.
+func (id ID) Prev() ID {
+	if id.Seq == 0 {
+		if id.Ms == 0 {
+			return id
+		}
+		return ID{Ms: id.Ms - 1, Seq: math.MaxUint64}
+	}
+	return ID{Ms: id.Ms, Seq: id.Seq - 1}
+}
+
+// ParseID parses an ID in the "<ms>-<seq>" format. If the sequence number is omitted, defaultSeq is used.
+func ParseID(s string, defaultSeq uint64) (ID, error) {
+	invalid := errors.New("invalid stream ID specified as stream command argument")
+	msPart, seqPart, hasSeq := strings.Cut(s, "-")
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return ID{}, invalid
+	}
+	if !hasSeq {
+		return ID{Ms: ms, Seq: defaultSeq}, nil
+	}
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return ID{}, invalid
+	}
+	return ID{Ms: ms, Seq: seq}, nil
+}
+
+// Entry is a single entry in the stream.
+type Entry struct {
+	ID     ID
+	Fields []string // The field names and values of the entry, in the order field1, value1, field2, value2...
+}
+
+// PendingEntry is an entry that has been delivered to a consumer of a group but not acknowledged yet.
+type PendingEntry struct {
+	ID            ID
+	Consumer      string
+	DeliveredAt   time.Time
+	DeliveryCount int
+}
+
+// Group is a consumer group of the stream.
+type Group struct {
+	lastDeliveredID ID
+	consumers       map[string]time.Time // The consumers of the group and the last time each one was seen.
+	pending         map[ID]*PendingEntry // The pending entries list of the group.
+}
+
+type Stream struct {
+	entries []Entry // The entries of the stream ordered by ID.
+	lastID  ID      // The greatest ID ever added to the stream.
+	groups  map[string]*Group
+}
+
+func (s *Stream) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*s))
+	for _, entry := range s.entries {
+		size += int64(unsafe.Sizeof(entry))
+		for _, field := range entry.Fields {
+			size += int64(unsafe.Sizeof(field))
+			size += int64(len(field))
+		}
+	}
+	for name, group := range s.groups {
+		size += int64(unsafe.Sizeof(name))
+		size += int64(len(name))
+		size += int64(unsafe.Sizeof(*group))
+		for consumer, seenAt := range group.consumers {
+			size += int64(unsafe.Sizeof(consumer))
+			size += int64(len(consumer))
+			size += int64(unsafe.Sizeof(seenAt))
+		}
+		for id, pending := range group.pending {
+			size += int64(unsafe.Sizeof(id))
+			size += int64(unsafe.Sizeof(*pending))
+		}
+	}
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*Stream)(nil)
+
+func appendID(b []byte, id ID) []byte {
+	b = binary.AppendUvarint(b, id.Ms)
+	return binary.AppendUvarint(b, id.Seq)
+}
+
+func readID(r *internal.BinaryReader) ID {
+	return ID{Ms: r.Uvarint(), Seq: r.Uvarint()}
+}
+
+// MarshalBinary encodes the entries of the stream together with its consumer groups. Groups, consumers and
+// pending entries are written in sorted order, so equal streams always encode to the same bytes.
+func (s *Stream) MarshalBinary() ([]byte, error) {
+	b := appendID(nil, s.lastID)
+	b = binary.AppendUvarint(b, uint64(len(s.entries)))
+	for _, entry := range s.entries {
+		b = appendID(b, entry.ID)
+		b = binary.AppendUvarint(b, uint64(len(entry.Fields)))
+		for _, field := range entry.Fields {
+			b = internal.AppendBinaryString(b, field)
+		}
+	}
+
+	names := make([]string, 0, len(s.groups))
+	for name := range s.groups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	b = binary.AppendUvarint(b, uint64(len(names)))
+	for _, name := range names {
+		group := s.groups[name]
+		b = internal.AppendBinaryString(b, name)
+		b = appendID(b, group.lastDeliveredID)
+
+		consumers := make([]string, 0, len(group.consumers))
+		for consumer := range group.consumers {
+			consumers = append(consumers, consumer)
+		}
+		slices.Sort(consumers)
+		b = binary.AppendUvarint(b, uint64(len(consumers)))
+		for _, consumer := range consumers {
+			b = internal.AppendBinaryString(b, consumer)
+			b = internal.AppendBinaryTime(b, group.consumers[consumer])
+		}
+
+		pending := group.sortedPending()
+		b = binary.AppendUvarint(b, uint64(len(pending)))
+		for _, entry := range pending {
+			b = appendID(b, entry.ID)
+			b = internal.AppendBinaryString(b, entry.Consumer)
+			b = internal.AppendBinaryTime(b, entry.DeliveredAt)
+			b = binary.AppendUvarint(b, uint64(entry.DeliveryCount))
+		}
+	}
+	return b, nil
+}
+
+func (s *Stream) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	stream := NewStream()
+	stream.lastID = readID(r)
+
+	n := r.Count()
+	for i := 0; i < n && r.Err() == nil; i++ {
+		entry := Entry{ID: readID(r), Fields: make([]string, r.Count())}
+		for j := range entry.Fields {
+			entry.Fields[j] = r.String()
+		}
+		stream.entries = append(stream.entries, entry)
+	}
+
+	n = r.Count()
+	for i := 0; i < n && r.Err() == nil; i++ {
+		name := r.String()
+		group := &Group{
+			lastDeliveredID: readID(r),
+			consumers:       make(map[string]time.Time),
+			pending:         make(map[ID]*PendingEntry),
+		}
+		consumers := r.Count()
+		for j := 0; j < consumers; j++ {
+			consumer := r.String()
+			group.consumers[consumer] = r.Time()
+		}
+		pending := r.Count()
+		for j := 0; j < pending; j++ {
+			entry := &PendingEntry{
+				ID:            readID(r),
+				Consumer:      r.String(),
+				DeliveredAt:   r.Time(),
+				DeliveryCount: int(r.Uvarint()),
+			}
+			group.pending[entry.ID] = entry
+		}
+		stream.groups[name] = group
+	}
+
+	if r.Err() != nil {
+		return r.Err()
+	}
+	*s = *stream
+	return nil
+}
+
+func NewStream() *Stream {
+	return &Stream{
+		entries: make([]Entry, 0),
+		lastID:  MinID,
+		groups:  make(map[string]*Group),
+	}
+}
+
+func (s *Stream) Len() int {
+	return len(s.entries)
+}
+
+func (s *Stream) LastID() ID {
+	return s.lastID
+}
+
+// NextID returns the ID for a new entry. id is either "*" to generate the ID from the current time,
+// "<ms>-*" to generate the sequence number for the given time, or an explicit "<ms>-<seq>" ID.
+// The returned ID is always greater than the last ID in the stream.
+func (s *Stream) NextID(now time.Time, id string) (ID, error) {
+	if id == "*" {
+		ms := uint64(now.UnixMilli())
+		if ms > s.lastID.Ms {
+			return ID{Ms: ms, Seq: 0}, nil
+		}
+		if s.lastID.Seq == math.MaxUint64 {
+			return ID{Ms: s.lastID.Ms + 1, Seq: 0}, nil
+		}
+		return ID{Ms: s.lastID.Ms, Seq: s.lastID.Seq + 1}, nil
+	}
+
+	if msPart, found := strings.CutSuffix(id, "-*"); found {
+		ms, err := strconv.ParseUint(msPart, 10, 64)
+		if err != nil {
+			return ID{}, errors.New("invalid stream ID specified as stream command argument")
+		}
+		switch {
+		case ms < s.lastID.Ms || (ms == s.lastID.Ms && s.lastID.Seq == math.MaxUint64):
+			return ID{}, errors.New("the ID specified in XADD is equal or smaller than the target stream top item")
+		case ms == s.lastID.Ms && (s.Len() > 0 || s.lastID != MinID):
+			return ID{Ms: ms, Seq: s.lastID.Seq + 1}, nil
+		case ms == 0:
+			return ID{Ms: 0, Seq: 1}, nil
+		}
+		return ID{Ms: ms, Seq: 0}, nil
+	}
+
+	next, err := ParseID(id, 0)
+	if err != nil {
+		return ID{}, err
+	}
+	if next == MinID {
+		return ID{}, errors.New("the ID specified in XADD must be greater than 0-0")
+	}
+	if next.Compare(s.lastID) <= 0 {
+		return ID{}, errors.New("the ID specified in XADD is equal or smaller than the target stream top item")
+	}
+	return next, nil
+}
+
+// Add appends the entry to the stream. The ID must be greater than the last ID of the stream.
+func (s *Stream) Add(id ID, fields []string) {
+	s.entries = append(s.entries, Entry{ID: id, Fields: fields})
+	s.lastID = id
+}
+
+// Trim removes the oldest entries so that at most maxLen entries remain. Returns the number of removed entries.
+func (s *Stream) Trim(maxLen int) int {
+	if len(s.entries) <= maxLen {
+		return 0
+	}
+	removed := len(s.entries) - maxLen
+	s.entries = slices.Clone(s.entries[removed:])
+	return removed
+}
+
+// search returns the index of the first entry whose ID is greater than or equal to id.
+func (s *Stream) search(id ID) int {
+	return sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].ID.Compare(id) >= 0
+	})
+}
+
+// Get returns the entry with the given ID.
+func (s *Stream) Get(id ID) (Entry, bool) {
+	i := s.search(id)
+	if i < len(s.entries) && s.entries[i].ID == id {
+		return s.entries[i], true
+	}
+	return Entry{}, false
+}
+
+// Range returns the entries with IDs between start and end inclusive, in ascending order.
+// If count is greater than 0, at most count entries are returned.
+func (s *Stream) Range(start, end ID, count int) []Entry {
+	entries := make([]Entry, 0)
+	for i := s.search(start); i < len(s.entries) && s.entries[i].ID.Compare(end) <= 0; i++ {
+		if count > 0 && len(entries) == count {
+			break
+		}
+		entries = append(entries, s.entries[i])
+	}
+	return entries
+}
+
+// RevRange returns the entries with IDs between start and end inclusive, in descending order.
+// If count is greater than 0, at most count entries are returned.
+func (s *Stream) RevRange(end, start ID, count int) []Entry {
+	entries := make([]Entry, 0)
+	for i := s.search(end.Next()) - 1; i >= 0 && s.entries[i].ID.Compare(start) >= 0; i-- {
+		if end == MaxID && s.entries[i].ID.Compare(end) > 0 {
+			continue
+		}
+		if count > 0 && len(entries) == count {
+			break
+		}
+		entries = append(entries, s.entries[i])
+	}
+	return entries
+}
+
+// After returns the entries with IDs greater than id.
+// If count is greater than 0, at most count entries are returned.
+func (s *Stream) After(id ID, count int) []Entry {
+	if id == MaxID {
+		return make([]Entry, 0)
+	}
+	return s.Range(id.Next(), MaxID, count)
+}
+
+func (s *Stream) getGroup(name string) (*Group, error) {
+	group, ok := s.groups[name]
+	if !ok {
+		return nil, fmt.Errorf("NOGROUP no consumer group '%s'", name)
+	}
+	return group, nil
+}
+
+func (s *Stream) HasGroup(name string) bool {
+	_, ok := s.groups[name]
+	return ok
+}
+
+// CreateGroup creates a consumer group that starts delivering entries after the given ID.
+func (s *Stream) CreateGroup(name string, id ID) error {
+	if s.HasGroup(name) {
+		return errors.New("BUSYGROUP consumer group name already exists")
+	}
+	s.groups[name] = &Group{
+		lastDeliveredID: id,
+		consumers:       make(map[string]time.Time),
+		pending:         make(map[ID]*PendingEntry),
+	}
+	return nil
+}
+
+// DestroyGroup deletes the consumer group. Returns true if the group existed.
+func (s *Stream) DestroyGroup(name string) bool {
+	if !s.HasGroup(name) {
+		return false
+	}
+	delete(s.groups, name)
+	return true
+}
+
+// SetGroupID sets the last delivered ID of the consumer group.
+func (s *Stream) SetGroupID(name string, id ID) error {
+	group, err := s.getGroup(name)
+	if err != nil {
+		return err
+	}
+	group.lastDeliveredID = id
+	return nil
+}
+
+// CreateConsumer creates a consumer in the group. Returns true if the consumer was created and false if
+// it already existed.
+func (s *Stream) CreateConsumer(group, consumer string, now time.Time) (bool, error) {
+	g, err := s.getGroup(group)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := g.consumers[consumer]; ok {
+		return false, nil
+	}
+	g.consumers[consumer] = now
+	return true, nil
+}
+
+// DeleteConsumer deletes the consumer from the group along with its pending entries.
+// Returns the number of pending entries the consumer had.
+func (s *Stream) DeleteConsumer(group, consumer string) (int, error) {
+	g, err := s.getGroup(group)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := g.consumers[consumer]; !ok {
+		return 0, nil
+	}
+	deleted := 0
+	for id, pending := range g.pending {
+		if pending.Consumer == consumer {
+			delete(g.pending, id)
+			deleted++
+		}
+	}
+	delete(g.consumers, consumer)
+	return deleted, nil
+}
+
+// ReadGroup reads entries from the stream on behalf of a consumer of the group.
+// If id is ">", the entries that have not been delivered to any consumer of the group are returned and added
+// to the group's pending entries list, unless noAck is true.
+// Otherwise, the consumer's pending entries with IDs greater than id are returned. Pending entries that are no
+// longer in the stream are returned with nil fields.
+func (s *Stream) ReadGroup(group, consumer, id string, count int, noAck bool, now time.Time) ([]Entry, error) {
+	g, err := s.getGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	g.consumers[consumer] = now
+
+	if id == ">" {
+		entries := s.After(g.lastDeliveredID, count)
+		for _, entry := range entries {
+			g.lastDeliveredID = entry.ID
+			if noAck {
+				continue
+			}
+			g.pending[entry.ID] = &PendingEntry{
+				ID:            entry.ID,
+				Consumer:      consumer,
+				DeliveredAt:   now,
+				DeliveryCount: 1,
+			}
+		}
+		return entries, nil
+	}
+
+	start, err := ParseID(id, 0)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0)
+	for _, pending := range g.sortedPending() {
+		if pending.Consumer != consumer || pending.ID.Compare(start) <= 0 {
+			continue
+		}
+		if count > 0 && len(entries) == count {
+			break
+		}
+		entry, ok := s.Get(pending.ID)
+		if !ok {
+			entry = Entry{ID: pending.ID, Fields: nil}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Ack removes the entries from the group's pending entries list. Returns the number of acknowledged entries.
+func (s *Stream) Ack(group string, ids []ID) (int, error) {
+	g, err := s.getGroup(group)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, id := range ids {
+		if _, ok := g.pending[id]; ok {
+			delete(g.pending, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (g *Group) sortedPending() []PendingEntry {
+	pending := make([]PendingEntry, 0, len(g.pending))
+	for _, entry := range g.pending {
+		pending = append(pending, *entry)
+	}
+	slices.SortFunc(pending, func(a, b PendingEntry) int {
+		return a.ID.Compare(b.ID)
+	})
+	return pending
+}
+
+// Pending returns the pending entries of the group with IDs between start and end inclusive, in ascending order.
+// If consumer is not empty, only the consumer's entries are returned. Entries that have been idle for less than
+// minIdle are skipped. If count is greater than 0, at most count entries are returned.
+func (s *Stream) Pending(group string, start, end ID, count int, consumer string, minIdle time.Duration, now time.Time) ([]PendingEntry, error) {
+	g, err := s.getGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]PendingEntry, 0)
+	for _, entry := range g.sortedPending() {
+		if entry.ID.Compare(start) < 0 || entry.ID.Compare(end) > 0 {
+			continue
+		}
+		if consumer != "" && entry.Consumer != consumer {
+			continue
+		}
+		if now.Sub(entry.DeliveredAt) < minIdle {
+			continue
+		}
+		if count > 0 && len(pending) == count {
+			break
+		}
+		pending = append(pending, entry)
+	}
+	return pending, nil
+}
+
+// ClaimOptions modifies the behaviour of Claim.
+type ClaimOptions struct {
+	DeliveredAt   *time.Time // Sets the delivery time of the claimed entries. Defaults to now.
+	DeliveryCount *int       // Sets the delivery count of the claimed entries. By default, it is incremented.
+	Force         bool       // Creates pending entries for IDs that are in the stream but not in the pending entries list.
+	JustID        bool       // Does not increment the delivery count.
+}
+
+// Claim changes the ownership of the pending entries that have been idle for at least minIdle to the consumer.
+// Pending entries that are no longer in the stream are removed from the pending entries list.
+// Returns the IDs of the claimed entries.
+func (s *Stream) Claim(group, consumer string, minIdle time.Duration, ids []ID, now time.Time, options ClaimOptions) ([]ID, error) {
+	g, err := s.getGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	g.consumers[consumer] = now
+
+	claimed := make([]ID, 0)
+	for _, id := range ids {
+		pending, ok := g.pending[id]
+		if !ok {
+			if _, exists := s.Get(id); !options.Force || !exists {
+				continue
+			}
+			pending = &PendingEntry{ID: id, Consumer: consumer, DeliveredAt: time.Time{}}
+			g.pending[id] = pending
+		}
+		if _, exists := s.Get(id); !exists {
+			delete(g.pending, id)
+			continue
+		}
+		if minIdle > 0 && now.Sub(pending.DeliveredAt) < minIdle {
+			continue
+		}
+
+		pending.Consumer = consumer
+		pending.DeliveredAt = now
+		if options.DeliveredAt != nil {
+			pending.DeliveredAt = *options.DeliveredAt
+		}
+		switch {
+		case options.DeliveryCount != nil:
+			pending.DeliveryCount = *options.DeliveryCount
+		case !options.JustID:
+			pending.DeliveryCount++
+		}
+
+		claimed = append(claimed, id)
+	}
+	return claimed, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// getStream returns the stream at the key. Returns nil if the key does not exist.
+func getStream(params internal.HandlerFuncParams, key string) (*Stream, error) {
+	if !params.KeysExist(params.Context, []string{key})[key] {
+		return nil, nil
+	}
+	stream, ok := params.GetValues(params.Context, []string{key})[key].(*Stream)
+	if !ok {
+		return nil, fmt.Errorf("value at key %s is not a stream", key)
+	}
+	return stream, nil
+}
+
+// parseRangeID parses the start or end ID of a range. "-" and "+" are the smallest and greatest possible IDs.
+// A "(" prefix makes the ID exclusive. An incomplete start ID defaults to sequence 0 and an incomplete
+// end ID defaults to the greatest sequence number.
+func parseRangeID(s string, isEnd bool) (ID, error) {
+	switch s {
+	case "-":
+		return MinID, nil
+	case "+":
+		return MaxID, nil
+	}
+
+	exclusive := strings.HasPrefix(s, "(")
+	s = strings.TrimPrefix(s, "(")
+
+	var defaultSeq uint64
+	if isEnd {
+		defaultSeq = MaxID.Seq
+	}
+	id, err := ParseID(s, defaultSeq)
+	if err != nil {
+		return ID{}, err
+	}
+
+	if !exclusive {
+		return id, nil
+	}
+	if isEnd {
+		if id == MinID {
+			return ID{}, fmt.Errorf("invalid end ID for the interval")
+		}
+		return id.Prev(), nil
+	}
+	if id == MaxID {
+		return ID{}, fmt.Errorf("invalid start ID for the interval")
+	}
+	return id.Next(), nil
+}
+
+func parseCount(s string) (int, error) {
+	count, err := strconv.Atoi(s)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("count must be a non-negative integer")
+	}
+	return count, nil
+}
+
+func encodeBulkString(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func encodeEntry(entry Entry) string {
+	res := "*2\r\n" + encodeBulkString(entry.ID.String())
+	if entry.Fields == nil {
+		return res + "*-1\r\n"
+	}
+	res += fmt.Sprintf("*%d\r\n", len(entry.Fields))
+	for _, field := range entry.Fields {
+		res += encodeBulkString(field)
+	}
+	return res
+}
+
+func encodeEntries(entries []Entry) string {
+	res := fmt.Sprintf("*%d\r\n", len(entries))
+	for _, entry := range entries {
+		res += encodeEntry(entry)
+	}
+	return res
+}
+
+// encodeStreams encodes the result of XREAD and XREADGROUP.
+// The streams are returned in the order of keys. Streams without entries are omitted unless includeEmpty is true.
+func encodeStreams(keys []string, entries map[string][]Entry, includeEmpty bool) []byte {
+	count := 0
+	res := ""
+	for _, key := range keys {
+		if len(entries[key]) == 0 && !includeEmpty {
+			continue
+		}
+		count++
+		res += "*2\r\n" + encodeBulkString(key) + encodeEntries(entries[key])
+	}
+	if count == 0 {
+		return []byte("*-1\r\n")
+	}
+	return []byte(fmt.Sprintf("*%d\r\n%s", count, res))
+}
@@ -0,0 +1,311 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package str
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// maxBitOffset is the largest bit offset that can be set, limiting bitmaps to 512MB.
+const maxBitOffset = 1<<32 - 1
+
+const (
+	overflowWrap = "wrap"
+	overflowSat  = "sat"
+	overflowFail = "fail"
+)
+
+// bitfieldType is an integer type of a BITFIELD operation, e.g. i8 or u16.
+type bitfieldType struct {
+	signed bool
+	bits   uint64
+}
+
+// getBitmap converts the value of a key to the bytes of its string representation.
+func getBitmap(key string, value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return []byte{}, nil
+	case string:
+		return []byte(v), nil
+	case int, int64, float64:
+		return []byte(fmt.Sprintf("%v", v)), nil
+	default:
+		return nil, fmt.Errorf("value at key %s is not a string", key)
+	}
+}
+
+// parseBitOffset parses the offset of SETBIT and GETBIT.
+func parseBitOffset(offset string) (uint64, error) {
+	o, err := strconv.ParseUint(offset, 10, 64)
+	if err != nil || o > maxBitOffset {
+		return 0, errors.New("bit offset is not an integer or out of range")
+	}
+	return o, nil
+}
+
+// getBit returns the bit at the offset. Bits beyond the end of the bitmap are 0.
+func getBit(bitmap []byte, offset uint64) int {
+	if offset>>3 >= uint64(len(bitmap)) {
+		return 0
+	}
+	return int(bitmap[offset>>3]>>(7-offset&7)) & 1
+}
+
+// setBit sets the bit at the offset, growing the bitmap with zero bytes if required.
+func setBit(bitmap []byte, offset uint64, bit int) []byte {
+	if offset>>3 >= uint64(len(bitmap)) {
+		bitmap = append(bitmap, make([]byte, offset>>3-uint64(len(bitmap))+1)...)
+	}
+	if bit == 1 {
+		bitmap[offset>>3] |= 1 << (7 - offset&7)
+	} else {
+		bitmap[offset>>3] &^= 1 << (7 - offset&7)
+	}
+	return bitmap
+}
+
+// bitRange normalizes the inclusive range of BITCOUNT and BITPOS for a sequence of the given length.
+// Negative indices count from the end. ok is false when the range is empty.
+func bitRange(start, end, length int64) (int64, int64, bool) {
+	if start < 0 {
+		start = length + start
+	}
+	if end < 0 {
+		end = length + end
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 {
+		end = 0
+	}
+	if end >= length {
+		end = length - 1
+	}
+	return start, end, length > 0 && start <= end
+}
+
+// countBits returns the number of set bits between the inclusive bit offsets.
+func countBits(bitmap []byte, start, end uint64) int {
+	count := 0
+	for offset := start; offset <= end; {
+		// Count whole bytes at once when the byte is fully within the range.
+		if offset&7 == 0 && offset+7 <= end {
+			count += bits.OnesCount8(bitmap[offset>>3])
+			offset += 8
+			continue
+		}
+		count += getBit(bitmap, offset)
+		offset++
+	}
+	return count
+}
+
+// parseBitfieldType parses a BITFIELD type. Signed integers can have up to 64 bits and unsigned integers up to 63 bits.
+func parseBitfieldType(t string) (bitfieldType, error) {
+	err := errors.New("invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is")
+	if len(t) < 2 {
+		return bitfieldType{}, err
+	}
+	var signed bool
+	switch t[0] {
+	case 'i', 'I':
+		signed = true
+	case 'u', 'U':
+		signed = false
+	default:
+		return bitfieldType{}, err
+	}
+	n, parseErr := strconv.ParseUint(t[1:], 10, 64)
+	if parseErr != nil || n < 1 || (signed && n > 64) || (!signed && n > 63) {
+		return bitfieldType{}, err
+	}
+	return bitfieldType{signed: signed, bits: n}, nil
+}
+
+// parseBitfieldOffset parses a BITFIELD offset. Offsets prefixed with # are multiplied by the width of the type.
+func parseBitfieldOffset(offset string, t bitfieldType) (uint64, error) {
+	multiply := strings.HasPrefix(offset, "#")
+	o, err := strconv.ParseUint(strings.TrimPrefix(offset, "#"), 10, 64)
+	if err != nil {
+		return 0, errors.New("bit offset is not an integer or out of range")
+	}
+	if multiply {
+		o *= t.bits
+	}
+	if o > maxBitOffset || o+t.bits-1 > maxBitOffset {
+		return 0, errors.New("bit offset is not an integer or out of range")
+	}
+	return o, nil
+}
+
+// getUnsignedBits returns the bits at the offset as an unsigned integer.
+func getUnsignedBits(bitmap []byte, offset uint64, n uint64) uint64 {
+	var value uint64
+	for i := uint64(0); i < n; i++ {
+		value = value<<1 | uint64(getBit(bitmap, offset+i))
+	}
+	return value
+}
+
+// setUnsignedBits writes the n least significant bits of the value at the offset.
+func setUnsignedBits(bitmap []byte, offset uint64, n uint64, value uint64) []byte {
+	for i := uint64(0); i < n; i++ {
+		bitmap = setBit(bitmap, offset+i, int(value>>(n-1-i))&1)
+	}
+	return bitmap
+}
+
+// getBitfield returns the integer of the type at the offset.
+func getBitfield(bitmap []byte, offset uint64, t bitfieldType) int64 {
+	value := getUnsignedBits(bitmap, offset, t.bits)
+	if t.signed && t.bits < 64 && value&(1<<(t.bits-1)) != 0 {
+		// Extend the sign of negative numbers.
+		value |= math.MaxUint64 << t.bits
+	}
+	return int64(value)
+}
+
+// applyBitfieldOverflow adds the increment to the value of the type and handles overflows with the overflow mode.
+// SET operations use an increment of 0 with the new value. ok is false when the value overflows in FAIL mode.
+func applyBitfieldOverflow(value int64, incr int64, t bitfieldType, overflow string) (int64, bool) {
+	if !t.signed {
+		max := uint64(1)<<t.bits - 1
+		v := uint64(value)
+		switch {
+		case v > max || (incr > 0 && uint64(incr) > max-v):
+			if overflow == overflowSat {
+				return int64(max), true
+			}
+		case incr < 0 && incr < -int64(v):
+			if overflow == overflowSat {
+				return 0, true
+			}
+		default:
+			return int64(v + uint64(incr)), true
+		}
+		if overflow == overflowFail {
+			return 0, false
+		}
+		return int64((v + uint64(incr)) & max), true
+	}
+
+	max := int64(math.MaxInt64)
+	if t.bits < 64 {
+		max = int64(1)<<(t.bits-1) - 1
+	}
+	min := -max - 1
+	maxIncr := max - value
+	minIncr := min - value
+	switch {
+	case value > max || (t.bits != 64 && incr > maxIncr) || (value >= 0 && incr > 0 && incr > maxIncr):
+		if overflow == overflowSat {
+			return max, true
+		}
+	case value < min || (t.bits != 64 && incr < minIncr) || (value < 0 && incr < 0 && incr < minIncr):
+		if overflow == overflowSat {
+			return min, true
+		}
+	default:
+		return value + incr, true
+	}
+	if overflow == overflowFail {
+		return 0, false
+	}
+	// Wrap the result around by truncating it to the width of the type and extending its sign.
+	result := uint64(value) + uint64(incr)
+	if t.bits < 64 {
+		result &= uint64(1)<<t.bits - 1
+		if result&(1<<(t.bits-1)) != 0 {
+			result |= math.MaxUint64 << t.bits
+		}
+	}
+	return int64(result), true
+}
+
+// bitfieldOperation is a GET, SET or INCRBY operation of the BITFIELD command.
+type bitfieldOperation struct {
+	name         string // get, set or incrby.
+	bitfieldType bitfieldType
+	offset       uint64
+	value        int64  // The new value of SET or the increment of INCRBY.
+	overflow     string // The overflow mode in effect for the operation.
+}
+
+// getBitfieldOperations parses the operations of the BITFIELD command.
+// An OVERFLOW option applies to all the SET and INCRBY operations that follow it.
+func getBitfieldOperations(cmd []string) ([]bitfieldOperation, error) {
+	operations := make([]bitfieldOperation, 0)
+	overflow := overflowWrap
+	for i := 0; i < len(cmd); {
+		name := strings.ToLower(cmd[i])
+		switch name {
+		case "overflow":
+			if i+1 >= len(cmd) {
+				return nil, errors.New("syntax error")
+			}
+			overflow = strings.ToLower(cmd[i+1])
+			if overflow != overflowWrap && overflow != overflowSat && overflow != overflowFail {
+				return nil, errors.New("invalid OVERFLOW type specified")
+			}
+			i += 2
+		case "get", "set", "incrby":
+			argCount := 3
+			if name == "get" {
+				argCount = 2
+			}
+			if i+argCount >= len(cmd) {
+				return nil, errors.New("syntax error")
+			}
+			t, err := parseBitfieldType(cmd[i+1])
+			if err != nil {
+				return nil, err
+			}
+			offset, err := parseBitfieldOffset(cmd[i+2], t)
+			if err != nil {
+				return nil, err
+			}
+			operation := bitfieldOperation{name: name, bitfieldType: t, offset: offset, overflow: overflow}
+			if name != "get" {
+				if operation.value, err = strconv.ParseInt(cmd[i+3], 10, 64); err != nil {
+					return nil, errors.New("value is not an integer or out of range")
+				}
+			}
+			operations = append(operations, operation)
+			i += argCount + 1
+		default:
+			return nil, errors.New("syntax error")
+		}
+	}
+	return operations, nil
+}
+
+// parseBitUnit parses the BYTE or BIT option of BITCOUNT and BITPOS. It returns true if the unit is BIT.
+func parseBitUnit(unit string) (bool, error) {
+	switch strings.ToLower(unit) {
+	case "byte":
+		return false, nil
+	case "bit":
+		return true, nil
+	default:
+		return false, errors.New("syntax error")
+	}
+}
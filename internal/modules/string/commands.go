@@ -17,6 +17,9 @@ package str
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/constants"
@@ -197,6 +200,331 @@ func handleAppend(params internal.HandlerFuncParams) ([]byte, error) {
 	return []byte(fmt.Sprintf(":%d\r\n", len(newValue))), nil
 }
 
+func handleSetBit(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := setBitKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+
+	offset, err := parseBitOffset(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	bit, err := strconv.Atoi(params.Command[3])
+	if err != nil || (bit != 0 && bit != 1) {
+		return nil, errors.New("bit is not an integer or out of range")
+	}
+
+	bitmap, err := getBitmap(key, params.GetValues(params.Context, []string{key})[key])
+	if err != nil {
+		return nil, err
+	}
+
+	original := getBit(bitmap, offset)
+	if err = params.SetValues(params.Context, map[string]interface{}{
+		key: string(setBit(bitmap, offset, bit)),
+	}); err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", original)), nil
+}
+
+func handleGetBit(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := getBitKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	offset, err := parseBitOffset(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if !keyExists {
+		return []byte(":0\r\n"), nil
+	}
+
+	bitmap, err := getBitmap(key, params.GetValues(params.Context, []string{key})[key])
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", getBit(bitmap, offset))), nil
+}
+
+func handleBitCount(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := bitCountKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	var start, end int64 = 0, -1
+	bitUnit := false
+	if len(params.Command) >= 4 {
+		start, err = strconv.ParseInt(params.Command[2], 10, 64)
+		if err != nil {
+			return nil, errors.New("start and end indices must be integers")
+		}
+		end, err = strconv.ParseInt(params.Command[3], 10, 64)
+		if err != nil {
+			return nil, errors.New("start and end indices must be integers")
+		}
+	}
+	if len(params.Command) == 5 {
+		if bitUnit, err = parseBitUnit(params.Command[4]); err != nil {
+			return nil, err
+		}
+	}
+
+	if !keyExists {
+		return []byte(":0\r\n"), nil
+	}
+
+	bitmap, err := getBitmap(key, params.GetValues(params.Context, []string{key})[key])
+	if err != nil {
+		return nil, err
+	}
+
+	length := int64(len(bitmap))
+	if bitUnit {
+		length *= 8
+	}
+	start, end, ok := bitRange(start, end, length)
+	if !ok {
+		return []byte(":0\r\n"), nil
+	}
+	if !bitUnit {
+		start, end = start*8, end*8+7
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", countBits(bitmap, uint64(start), uint64(end)))), nil
+}
+
+func handleBitPos(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := bitPosKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.ReadKeys[0]
+	keyExists := params.KeysExist(params.Context, keys.ReadKeys)[key]
+
+	bit, err := strconv.Atoi(params.Command[2])
+	if err != nil || (bit != 0 && bit != 1) {
+		return nil, errors.New("the bit argument must be 1 or 0")
+	}
+
+	var start, end int64 = 0, -1
+	endProvided := len(params.Command) >= 5
+	bitUnit := false
+	if len(params.Command) >= 4 {
+		if start, err = strconv.ParseInt(params.Command[3], 10, 64); err != nil {
+			return nil, errors.New("start and end indices must be integers")
+		}
+	}
+	if endProvided {
+		if end, err = strconv.ParseInt(params.Command[4], 10, 64); err != nil {
+			return nil, errors.New("start and end indices must be integers")
+		}
+	}
+	if len(params.Command) == 6 {
+		if bitUnit, err = parseBitUnit(params.Command[5]); err != nil {
+			return nil, err
+		}
+	}
+
+	if !keyExists {
+		// A missing key is an empty string, so the first clear bit is at position 0.
+		if bit == 0 {
+			return []byte(":0\r\n"), nil
+		}
+		return []byte(":-1\r\n"), nil
+	}
+
+	bitmap, err := getBitmap(key, params.GetValues(params.Context, []string{key})[key])
+	if err != nil {
+		return nil, err
+	}
+
+	length := int64(len(bitmap))
+	if bitUnit {
+		length *= 8
+	}
+	start, end, ok := bitRange(start, end, length)
+	if !ok {
+		return []byte(":-1\r\n"), nil
+	}
+	if !bitUnit {
+		start, end = start*8, end*8+7
+	}
+
+	for offset := start; offset <= end; offset++ {
+		if getBit(bitmap, uint64(offset)) == bit {
+			return []byte(fmt.Sprintf(":%d\r\n", offset)), nil
+		}
+	}
+
+	// When looking for a clear bit without an explicit end, the string is considered to be padded with zeros,
+	// so the first clear bit is the one right after the range.
+	if bit == 0 && !endProvided {
+		return []byte(fmt.Sprintf(":%d\r\n", end+1)), nil
+	}
+	return []byte(":-1\r\n"), nil
+}
+
+func handleBitOp(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := bitOpKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := strings.ToLower(params.Command[1])
+	if !slices.Contains([]string{"and", "or", "xor", "not"}, operation) {
+		return nil, fmt.Errorf("invalid operation %s", strings.ToUpper(params.Command[1]))
+	}
+	if operation == "not" && len(keys.ReadKeys) != 1 {
+		return nil, errors.New("BITOP NOT must be called with a single source key")
+	}
+
+	destination := keys.WriteKeys[0]
+	values := params.GetValues(params.Context, keys.ReadKeys)
+
+	bitmaps := make([][]byte, len(keys.ReadKeys))
+	length := 0
+	for i, key := range keys.ReadKeys {
+		if bitmaps[i], err = getBitmap(key, values[key]); err != nil {
+			return nil, err
+		}
+		length = max(length, len(bitmaps[i]))
+	}
+
+	if length == 0 {
+		// The result is an empty string, so the destination key is deleted.
+		if params.KeysExist(params.Context, keys.WriteKeys)[destination] {
+			if err = params.DeleteKey(params.Context, destination); err != nil {
+				return nil, err
+			}
+		}
+		return []byte(":0\r\n"), nil
+	}
+
+	// Shorter strings are considered to be padded with zero bytes.
+	byteAt := func(bitmap []byte, i int) byte {
+		if i < len(bitmap) {
+			return bitmap[i]
+		}
+		return 0
+	}
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = byteAt(bitmaps[0], i)
+		if operation == "not" {
+			result[i] = ^result[i]
+			continue
+		}
+		for _, bitmap := range bitmaps[1:] {
+			switch operation {
+			case "and":
+				result[i] &= byteAt(bitmap, i)
+			case "or":
+				result[i] |= byteAt(bitmap, i)
+			case "xor":
+				result[i] ^= byteAt(bitmap, i)
+			}
+		}
+	}
+
+	if err = params.SetValues(params.Context, map[string]interface{}{destination: string(result)}); err != nil {
+		return nil, err
+	}
+
+	return []byte(fmt.Sprintf(":%d\r\n", length)), nil
+}
+
+func handleBitField(params internal.HandlerFuncParams) ([]byte, error) {
+	readOnly := strings.EqualFold(params.Command[0], "bitfield_ro")
+
+	var keys internal.KeyExtractionFuncResult
+	var err error
+	if readOnly {
+		keys, err = bitFieldROKeyFunc(params.Command)
+	} else {
+		keys, err = bitFieldKeyFunc(params.Command)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := params.Command[1]
+
+	operations, err := getBitfieldOperations(params.Command[2:])
+	if err != nil {
+		return nil, err
+	}
+	if readOnly && slices.ContainsFunc(operations, func(operation bitfieldOperation) bool {
+		return operation.name != "get"
+	}) {
+		return nil, errors.New("BITFIELD_RO only supports the GET subcommand")
+	}
+
+	bitmap := []byte{}
+	if params.KeysExist(params.Context, []string{key})[key] {
+		if bitmap, err = getBitmap(key, params.GetValues(params.Context, []string{key})[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(operations))
+	modified := false
+	for _, operation := range operations {
+		value := getBitfield(bitmap, operation.offset, operation.bitfieldType)
+		if operation.name == "get" {
+			res += fmt.Sprintf(":%d\r\n", value)
+			continue
+		}
+
+		var ok bool
+		var newValue int64
+		if operation.name == "set" {
+			newValue, ok = applyBitfieldOverflow(operation.value, 0, operation.bitfieldType, operation.overflow)
+		} else {
+			newValue, ok = applyBitfieldOverflow(value, operation.value, operation.bitfieldType, operation.overflow)
+		}
+		if !ok {
+			// The operation overflowed in FAIL mode and is not performed.
+			res += "$-1\r\n"
+			continue
+		}
+
+		bitmap = setUnsignedBits(bitmap, operation.offset, operation.bitfieldType.bits, uint64(newValue))
+		modified = true
+
+		// SET returns the old value while INCRBY returns the new value.
+		if operation.name == "set" {
+			res += fmt.Sprintf(":%d\r\n", value)
+		} else {
+			res += fmt.Sprintf(":%d\r\n", newValue)
+		}
+	}
+
+	if modified {
+		if err = params.SetValues(params.Context, map[string]interface{}{keys.WriteKeys[0]: string(bitmap)}); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(res), nil
+}
+
 func Commands() []internal.Command {
 	return []internal.Command{
 		{
@@ -250,5 +578,89 @@ Overwrites part of a string value with another by offset. Creates the key if it
 			KeyExtractionFunc: appendKeyFunc,
 			HandlerFunc:       handleAppend,
 		},
+		{
+			Command:    "setbit",
+			Module:     constants.StringModule,
+			Categories: []string{constants.BitmapCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(SETBIT key offset value)
+Sets or clears the bit at offset in the string value at key. The string is grown with zero bytes if offset
+is beyond its end. Creates the key if it doesn't exist. Returns the original bit value at offset.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: setBitKeyFunc,
+			HandlerFunc:       handleSetBit,
+		},
+		{
+			Command:    "getbit",
+			Module:     constants.StringModule,
+			Categories: []string{constants.BitmapCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(GETBIT key offset) Returns the bit value at offset in the string value at key.
+Bits beyond the end of the string and bits of non-existent keys are 0.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: getBitKeyFunc,
+			HandlerFunc:       handleGetBit,
+		},
+		{
+			Command:    "bitcount",
+			Module:     constants.StringModule,
+			Categories: []string{constants.BitmapCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(BITCOUNT key [start end [BYTE | BIT]])
+Counts the number of set bits in the string value at key. The optional start and end indices are inclusive
+and can be negative to count from the end of the string. They are byte indices unless BIT is specified.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: bitCountKeyFunc,
+			HandlerFunc:       handleBitCount,
+		},
+		{
+			Command:    "bitpos",
+			Module:     constants.StringModule,
+			Categories: []string{constants.BitmapCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(BITPOS key bit [start [end [BYTE | BIT]]])
+Returns the position of the first bit set to 1 or 0 in the string value at key. The optional start and end indices
+are inclusive byte indices unless BIT is specified. Returns -1 if the bit is not found.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: bitPosKeyFunc,
+			HandlerFunc:       handleBitPos,
+		},
+		{
+			Command:    "bitop",
+			Module:     constants.StringModule,
+			Categories: []string{constants.BitmapCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(BITOP AND | OR | XOR | NOT destkey key [key ...])
+Performs a bitwise operation between the string values of the keys and stores the result in destkey.
+Shorter strings are padded with zero bytes. NOT takes a single key. Returns the length of the result.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: bitOpKeyFunc,
+			HandlerFunc:       handleBitOp,
+		},
+		{
+			Command:    "bitfield",
+			Module:     constants.StringModule,
+			Categories: []string{constants.BitmapCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(BITFIELD key [GET encoding offset | [OVERFLOW WRAP | SAT | FAIL] SET encoding offset value |
+INCRBY encoding offset increment] ...)
+Treats the string value at key as an array of signed (e.g. i8) or unsigned (e.g. u16) integers of arbitrary width.
+Offsets prefixed with # are multiplied by the width of the encoding. OVERFLOW controls how the following SET
+and INCRBY operations handle overflows. Returns the result of each operation.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: bitFieldKeyFunc,
+			HandlerFunc:       handleBitField,
+		},
+		{
+			Command:    "bitfield_ro",
+			Module:     constants.StringModule,
+			Categories: []string{constants.BitmapCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(BITFIELD_RO key [GET encoding offset ...])
+Read-only variant of BITFIELD that only supports the GET operation.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: bitFieldROKeyFunc,
+			HandlerFunc:       handleBitField,
+		},
 	}
 }
@@ -553,4 +553,337 @@ func Test_String(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Test_HandleBitmap", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name             string
+			presetCommands   [][]string
+			command          []string
+			expectedResponse int
+			expectedError    error
+		}{
+			{
+				name:             "1. SETBIT on non-existent key creates the key and returns 0",
+				command:          []string{"SETBIT", "BitmapKey1", "7", "1"},
+				expectedResponse: 0,
+			},
+			{
+				name:             "2. SETBIT returns the original bit",
+				presetCommands:   [][]string{{"SETBIT", "BitmapKey2", "100", "1"}},
+				command:          []string{"SETBIT", "BitmapKey2", "100", "0"},
+				expectedResponse: 1,
+			},
+			{
+				name:             "3. GETBIT returns the bit at the offset",
+				presetCommands:   [][]string{{"SETBIT", "BitmapKey3", "13", "1"}},
+				command:          []string{"GETBIT", "BitmapKey3", "13"},
+				expectedResponse: 1,
+			},
+			{
+				name:             "4. GETBIT beyond the end of the string returns 0",
+				presetCommands:   [][]string{{"SET", "BitmapKey4", "a"}},
+				command:          []string{"GETBIT", "BitmapKey4", "1000"},
+				expectedResponse: 0,
+			},
+			{
+				name:             "5. GETBIT reads the bits of an existing string",
+				presetCommands:   [][]string{{"SET", "BitmapKey5", "a"}},
+				command:          []string{"GETBIT", "BitmapKey5", "1"},
+				expectedResponse: 1,
+			},
+			{
+				name:             "6. BITCOUNT counts all the set bits",
+				presetCommands:   [][]string{{"SET", "BitmapKey6", "foobar"}},
+				command:          []string{"BITCOUNT", "BitmapKey6"},
+				expectedResponse: 26,
+			},
+			{
+				name:             "7. BITCOUNT with a byte range",
+				presetCommands:   [][]string{{"SET", "BitmapKey7", "foobar"}},
+				command:          []string{"BITCOUNT", "BitmapKey7", "1", "1"},
+				expectedResponse: 6,
+			},
+			{
+				name:             "8. BITCOUNT with a negative bit range",
+				presetCommands:   [][]string{{"SET", "BitmapKey8", "foobar"}},
+				command:          []string{"BITCOUNT", "BitmapKey8", "5", "30", "BIT"},
+				expectedResponse: 17,
+			},
+			{
+				name:             "9. BITCOUNT on non-existent key returns 0",
+				command:          []string{"BITCOUNT", "BitmapKey9"},
+				expectedResponse: 0,
+			},
+			{
+				name:             "10. BITPOS finds the first set bit",
+				presetCommands:   [][]string{{"SETBIT", "BitmapKey10", "21", "1"}},
+				command:          []string{"BITPOS", "BitmapKey10", "1"},
+				expectedResponse: 21,
+			},
+			{
+				name: "11. BITPOS finds the first clear bit after the string when no end is provided",
+				presetCommands: [][]string{
+					{"SETBIT", "BitmapKey11", "0", "1"}, {"SETBIT", "BitmapKey11", "1", "1"},
+					{"SETBIT", "BitmapKey11", "2", "1"}, {"SETBIT", "BitmapKey11", "3", "1"},
+					{"SETBIT", "BitmapKey11", "4", "1"}, {"SETBIT", "BitmapKey11", "5", "1"},
+					{"SETBIT", "BitmapKey11", "6", "1"}, {"SETBIT", "BitmapKey11", "7", "1"},
+				},
+				command:          []string{"BITPOS", "BitmapKey11", "0"},
+				expectedResponse: 8,
+			},
+			{
+				name:             "12. BITPOS returns -1 when the bit is not in the range",
+				presetCommands:   [][]string{{"SETBIT", "BitmapKey12", "3", "1"}},
+				command:          []string{"BITPOS", "BitmapKey12", "1", "4", "7", "BIT"},
+				expectedResponse: -1,
+			},
+			{
+				name:             "13. BITPOS on non-existent key looking for 1 returns -1",
+				command:          []string{"BITPOS", "BitmapKey13", "1"},
+				expectedResponse: -1,
+			},
+			{
+				name: "14. BITOP AND stores the result in the destination key",
+				presetCommands: [][]string{
+					{"SET", "BitmapKey14a", "abc"}, {"SET", "BitmapKey14b", "a"},
+					{"BITOP", "AND", "BitmapKey14", "BitmapKey14a", "BitmapKey14b"},
+				},
+				command:          []string{"BITCOUNT", "BitmapKey14"},
+				expectedResponse: 3,
+			},
+			{
+				name:             "15. BITOP returns the length of the longest string",
+				presetCommands:   [][]string{{"SET", "BitmapKey15a", "abc"}, {"SET", "BitmapKey15b", "a"}},
+				command:          []string{"BITOP", "OR", "BitmapKey15", "BitmapKey15a", "BitmapKey15b"},
+				expectedResponse: 3,
+			},
+			{
+				name: "16. BITOP NOT inverts the bits",
+				presetCommands: [][]string{
+					{"SETBIT", "BitmapKey16a", "0", "1"},
+					{"BITOP", "NOT", "BitmapKey16", "BitmapKey16a"},
+				},
+				command:          []string{"BITCOUNT", "BitmapKey16"},
+				expectedResponse: 7,
+			},
+			{
+				name:          "17. BITOP NOT with more than one key",
+				command:       []string{"BITOP", "NOT", "BitmapKey17", "BitmapKey17a", "BitmapKey17b"},
+				expectedError: errors.New("BITOP NOT must be called with a single source key"),
+			},
+			{
+				name:          "18. SETBIT with invalid bit",
+				command:       []string{"SETBIT", "BitmapKey18", "0", "2"},
+				expectedError: errors.New("bit is not an integer or out of range"),
+			},
+			{
+				name:          "19. SETBIT with offset out of range",
+				command:       []string{"SETBIT", "BitmapKey19", "4294967296", "1"},
+				expectedError: errors.New("bit offset is not an integer or out of range"),
+			},
+			{
+				name:           "20. GETBIT on a value that is not a string",
+				presetCommands: [][]string{{"LPUSH", "BitmapKey20", "a"}},
+				command:        []string{"GETBIT", "BitmapKey20", "0"},
+				expectedError:  errors.New("value at key BitmapKey20 is not a string"),
+			},
+			{
+				name:          "21. BITCOUNT with invalid unit",
+				command:       []string{"BITCOUNT", "BitmapKey21", "0", "1", "WORD"},
+				expectedError: errors.New("syntax error"),
+			},
+			{
+				name:          "22. Command too short",
+				command:       []string{"GETBIT", "BitmapKey22"},
+				expectedError: errors.New(constants.WrongArgsResponse),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for _, preset := range test.presetCommands {
+					command := make([]resp.Value, len(preset))
+					for i, c := range preset {
+						command[i] = resp.StringValue(c)
+					}
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					if _, _, err = client.ReadValue(); err != nil {
+						t.Error(err)
+					}
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.expectedError != nil {
+					if res.Error() == nil || !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%+v\"", test.expectedError.Error(), res)
+					}
+					return
+				}
+
+				if res.Integer() != test.expectedResponse {
+					t.Errorf("expected response \"%d\", got \"%d\"", test.expectedResponse, res.Integer())
+				}
+			})
+		}
+	})
+
+	t.Run("Test_HandleBitField", func(t *testing.T) {
+		t.Parallel()
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		tests := []struct {
+			name             string
+			presetCommands   [][]string
+			command          []string
+			expectedResponse []interface{}
+			expectedError    error
+		}{
+			{
+				name:             "1. SET and GET unsigned and signed integers",
+				command:          []string{"BITFIELD", "BitFieldKey1", "SET", "u8", "0", "255", "GET", "u8", "0", "GET", "i8", "0"},
+				expectedResponse: []interface{}{0, 255, -1},
+			},
+			{
+				name:             "2. Offsets prefixed with # are multiplied by the width",
+				presetCommands:   [][]string{{"BITFIELD", "BitFieldKey2", "SET", "u4", "#1", "9"}},
+				command:          []string{"BITFIELD", "BitFieldKey2", "GET", "u8", "0", "GET", "u4", "4"},
+				expectedResponse: []interface{}{9, 9},
+			},
+			{
+				name:             "3. INCRBY wraps around by default",
+				presetCommands:   [][]string{{"BITFIELD", "BitFieldKey3", "SET", "i8", "0", "127"}},
+				command:          []string{"BITFIELD", "BitFieldKey3", "INCRBY", "i8", "0", "1", "INCRBY", "u2", "100", "5"},
+				expectedResponse: []interface{}{-128, 1},
+			},
+			{
+				name:             "4. OVERFLOW SAT saturates at the limits of the type",
+				presetCommands:   [][]string{{"BITFIELD", "BitFieldKey4", "SET", "u8", "0", "250"}},
+				command:          []string{"BITFIELD", "BitFieldKey4", "OVERFLOW", "SAT", "INCRBY", "u8", "0", "10", "INCRBY", "i4", "8", "-100"},
+				expectedResponse: []interface{}{255, -8},
+			},
+			{
+				name:             "5. OVERFLOW FAIL does not perform the operation",
+				presetCommands:   [][]string{{"BITFIELD", "BitFieldKey5", "SET", "u8", "0", "250"}},
+				command:          []string{"BITFIELD", "BitFieldKey5", "OVERFLOW", "FAIL", "INCRBY", "u8", "0", "10", "GET", "u8", "0"},
+				expectedResponse: []interface{}{nil, 250},
+			},
+			{
+				name:             "6. SET with a value that does not fit in the type wraps around",
+				command:          []string{"BITFIELD", "BitFieldKey6", "SET", "u8", "0", "-1", "GET", "u8", "0", "SET", "i64", "8", "-5", "GET", "i64", "8"},
+				expectedResponse: []interface{}{0, 255, 0, -5},
+			},
+			{
+				name:             "7. BITFIELD_RO reads the integers",
+				presetCommands:   [][]string{{"SET", "BitFieldKey7", "a"}},
+				command:          []string{"BITFIELD_RO", "BitFieldKey7", "GET", "u8", "0", "GET", "u4", "4"},
+				expectedResponse: []interface{}{97, 1},
+			},
+			{
+				name:          "8. BITFIELD_RO does not support SET",
+				command:       []string{"BITFIELD_RO", "BitFieldKey8", "SET", "u8", "0", "1"},
+				expectedError: errors.New("BITFIELD_RO only supports the GET subcommand"),
+			},
+			{
+				name:          "9. u64 is not supported",
+				command:       []string{"BITFIELD", "BitFieldKey9", "GET", "u64", "0"},
+				expectedError: errors.New("invalid bitfield type"),
+			},
+			{
+				name:          "10. Invalid overflow type",
+				command:       []string{"BITFIELD", "BitFieldKey10", "OVERFLOW", "NONE", "GET", "u8", "0"},
+				expectedError: errors.New("invalid OVERFLOW type specified"),
+			},
+			{
+				name:          "11. Missing operation arguments",
+				command:       []string{"BITFIELD", "BitFieldKey11", "SET", "u8", "0"},
+				expectedError: errors.New("syntax error"),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for _, preset := range test.presetCommands {
+					command := make([]resp.Value, len(preset))
+					for i, c := range preset {
+						command[i] = resp.StringValue(c)
+					}
+					if err = client.WriteArray(command); err != nil {
+						t.Error(err)
+					}
+					if _, _, err = client.ReadValue(); err != nil {
+						t.Error(err)
+					}
+				}
+
+				command := make([]resp.Value, len(test.command))
+				for i, c := range test.command {
+					command[i] = resp.StringValue(c)
+				}
+
+				if err = client.WriteArray(command); err != nil {
+					t.Error(err)
+				}
+				res, _, err := client.ReadValue()
+				if err != nil {
+					t.Error(err)
+				}
+
+				if test.expectedError != nil {
+					if res.Error() == nil || !strings.Contains(res.Error().Error(), test.expectedError.Error()) {
+						t.Errorf("expected error \"%s\", got \"%+v\"", test.expectedError.Error(), res)
+					}
+					return
+				}
+
+				if len(res.Array()) != len(test.expectedResponse) {
+					t.Errorf("expected response of length %d, got %d", len(test.expectedResponse), len(res.Array()))
+					return
+				}
+				for i, value := range res.Array() {
+					if test.expectedResponse[i] == nil {
+						if !value.IsNull() {
+							t.Errorf("expected nil at index %d, got %s", i, value.String())
+						}
+						continue
+					}
+					if value.Integer() != test.expectedResponse[i] {
+						t.Errorf("expected %d at index %d, got %d", test.expectedResponse[i], i, value.Integer())
+					}
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,963 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// getSeries returns the series at key. It returns false if the key doesn't exist, and an error if the value
+// is not a series.
+func getSeries(params internal.HandlerFuncParams, key string) (*TimeSeries, bool, error) {
+	if !params.KeysExist(params.Context, []string{key})[key] {
+		return nil, false, nil
+	}
+	ts, ok := params.GetValues(params.Context, []string{key})[key].(*TimeSeries)
+	if !ok {
+		return nil, false, fmt.Errorf("value at key %s is not a time series", key)
+	}
+	return ts, true, nil
+}
+
+// getExistingSeries returns the series at key, or an error if the key doesn't exist.
+func getExistingSeries(params internal.HandlerFuncParams, key string) (*TimeSeries, error) {
+	ts, exists, err := getSeries(params, key)
+	if err == nil && !exists {
+		err = fmt.Errorf("key %s does not exist", key)
+	}
+	return ts, err
+}
+
+func now(params internal.HandlerFuncParams) int64 {
+	return params.GetClock().Now().UnixMilli()
+}
+
+// seriesOptions holds the options of TS.CREATE, TS.ALTER and TS.ADD.
+type seriesOptions struct {
+	retention       int64
+	hasRetention    bool
+	duplicatePolicy string
+	onDuplicate     string
+	labels          []Label
+	hasLabels       bool
+}
+
+// parseSeriesOptions parses RETENTION, DUPLICATE_POLICY and LABELS, and ON_DUPLICATE if it's allowed.
+// LABELS takes the rest of the arguments.
+func parseSeriesOptions(args []string, allowOnDuplicate bool) (seriesOptions, error) {
+	var options seriesOptions
+	for i := 0; i < len(args); i++ {
+		switch option := strings.ToLower(args[i]); {
+		case option == "retention" && i+1 < len(args):
+			retention, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || retention < 0 {
+				return options, errors.New("retention must be a non-negative integer")
+			}
+			options.retention, options.hasRetention = retention, true
+			i++
+		case (option == "duplicate_policy" || (option == "on_duplicate" && allowOnDuplicate)) && i+1 < len(args):
+			policy := strings.ToLower(args[i+1])
+			if !IsDuplicatePolicy(policy) {
+				return options, fmt.Errorf("unknown duplicate policy %s", args[i+1])
+			}
+			if option == "duplicate_policy" {
+				options.duplicatePolicy = policy
+			} else {
+				options.onDuplicate = policy
+			}
+			i++
+		case option == "labels":
+			labels, err := ParseLabels(args[i+1:])
+			if err != nil {
+				return options, err
+			}
+			options.labels, options.hasLabels = labels, true
+			i = len(args)
+		default:
+			return options, fmt.Errorf("unknown option %s", args[i])
+		}
+	}
+	return options, nil
+}
+
+func parseValue(arg string) (float64, error) {
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errors.New("value must be a finite number")
+	}
+	return value, nil
+}
+
+// parseAddTimestamp parses the timestamp of a new sample, where * is the current time.
+func parseAddTimestamp(arg string, now int64) (int64, error) {
+	if arg == "*" {
+		return now, nil
+	}
+	timestamp, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || timestamp < 0 {
+		return 0, errors.New("timestamp must be a non-negative integer or *")
+	}
+	return timestamp, nil
+}
+
+// parseRangeTimestamp parses the bound of a range, where - is the earliest and + is the latest timestamp.
+func parseRangeTimestamp(arg string) (int64, error) {
+	switch arg {
+	case "-":
+		return 0, nil
+	case "+":
+		return math.MaxInt64, nil
+	}
+	timestamp, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, errors.New("range bounds must be integers, - or +")
+	}
+	return timestamp, nil
+}
+
+// addSample adds the sample to the series and the samples produced by its compaction rules to their destination
+// series. The changed series are added to values.
+func addSample(
+	params internal.HandlerFuncParams,
+	key string,
+	ts *TimeSeries,
+	sample Sample,
+	policy string,
+	values map[string]interface{},
+) error {
+	compactions, err := ts.Add(sample, policy, now(params))
+	if err != nil {
+		return err
+	}
+	values[key] = ts
+	for _, compaction := range compactions {
+		// Destinations that were deleted or replaced since the rule was created are skipped.
+		dest, ok := values[compaction.DestKey].(*TimeSeries)
+		if !ok {
+			if dest, _, _ = getSeries(params, compaction.DestKey); dest == nil {
+				continue
+			}
+		}
+		if _, err = dest.Add(compaction.Sample, DuplicateLast, now(params)); err == nil {
+			values[compaction.DestKey] = dest
+		}
+	}
+	return nil
+}
+
+func handleCREATE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	options, err := parseSeriesOptions(params.Command[2:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	if params.KeysExist(params.Context, []string{key})[key] {
+		return nil, fmt.Errorf("key %s already exists", key)
+	}
+
+	ts := NewTimeSeries(options.retention, options.duplicatePolicy, options.labels)
+	if err = params.SetValues(params.Context, map[string]interface{}{key: ts}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleALTER(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	options, err := parseSeriesOptions(params.Command[2:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	ts, err := getExistingSeries(params, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.hasRetention {
+		ts.SetRetention(options.retention)
+	}
+	if options.duplicatePolicy != "" {
+		ts.SetDuplicatePolicy(options.duplicatePolicy)
+	}
+	if options.hasLabels {
+		ts.SetLabels(options.labels)
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{key: ts}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleADD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := parseAddTimestamp(params.Command[2], now(params))
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseValue(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+	options, err := parseSeriesOptions(params.Command[4:], true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The options other than ON_DUPLICATE are only used when the series is created.
+	key := keys.WriteKeys[0]
+	ts, exists, err := getSeries(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		ts = NewTimeSeries(options.retention, options.duplicatePolicy, options.labels)
+	}
+
+	values := make(map[string]interface{})
+	if err = addSample(params, key, ts, Sample{Timestamp: timestamp, Value: value}, options.onDuplicate, values); err != nil {
+		return nil, err
+	}
+	if err = params.SetValues(params.Context, values); err != nil {
+		return nil, err
+	}
+	// Log the resolved timestamp so that replaying the command adds the sample at the same time.
+	if params.Command[2] == "*" {
+		cmd := slices.Clone(params.Command)
+		cmd[2] = strconv.FormatInt(timestamp, 10)
+		internal.PropagateCommand(params.Context, cmd)
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", timestamp)), nil
+}
+
+// handleMADD adds samples to existing series. Each sample replies with its timestamp, or with an error if it
+// couldn't be added.
+func handleMADD(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := maddKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+
+	// Resolve * timestamps up front and log the resolved command, so that replaying it adds the samples
+	// at the same time.
+	cmd := slices.Clone(params.Command)
+	for i := 2; i < len(cmd); i += 3 {
+		if cmd[i] == "*" {
+			cmd[i] = strconv.FormatInt(now(params), 10)
+		}
+	}
+	if !slices.Equal(cmd, params.Command) {
+		internal.PropagateCommand(params.Context, cmd)
+	}
+
+	values := make(map[string]interface{})
+	res := fmt.Sprintf("*%d\r\n", (len(cmd)-1)/3)
+	for i := 1; i < len(cmd); i += 3 {
+		timestamp, err := addArgs(params, values, cmd[i:i+3])
+		if err != nil {
+			res += fmt.Sprintf("-%s\r\n", err.Error())
+			continue
+		}
+		res += fmt.Sprintf(":%d\r\n", timestamp)
+	}
+
+	if len(values) > 0 {
+		if err := params.SetValues(params.Context, values); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(res), nil
+}
+
+// addArgs adds the sample described by a key, timestamp and value to the series at the key. Series that were
+// already changed are taken from values, so that they aren't read back from the store.
+func addArgs(params internal.HandlerFuncParams, values map[string]interface{}, args []string) (int64, error) {
+	timestamp, err := parseAddTimestamp(args[1], now(params))
+	if err != nil {
+		return 0, err
+	}
+	value, err := parseValue(args[2])
+	if err != nil {
+		return 0, err
+	}
+	ts, ok := values[args[0]].(*TimeSeries)
+	if !ok {
+		if ts, err = getExistingSeries(params, args[0]); err != nil {
+			return 0, err
+		}
+	}
+	return timestamp, addSample(params, args[0], ts, Sample{Timestamp: timestamp, Value: value}, "", values)
+}
+
+func handleDEL(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	from, err := parseRangeTimestamp(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseRangeTimestamp(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	ts, err := getExistingSeries(params, key)
+	if err != nil {
+		return nil, err
+	}
+	count := ts.Delete(from, to)
+	if count > 0 {
+		if err = params.SetValues(params.Context, map[string]interface{}{key: ts}); err != nil {
+			return nil, err
+		}
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", count)), nil
+}
+
+func handleGET(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	ts, err := getExistingSeries(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	return []byte(encodeLast(ts, now(params))), nil
+}
+
+// rangeOptions holds the options that filter and aggregate the samples of a range.
+type rangeOptions struct {
+	timestamps     []int64
+	filterByValue  bool
+	minValue       float64
+	maxValue       float64
+	count          int
+	aggregation    string
+	bucketDuration int64
+	withLabels     bool
+	filters        []Filter
+}
+
+// parseRangeOptions parses the options of the range commands. WITHLABELS and FILTER are only allowed by the
+// multi-series commands, and FILTER takes the rest of the arguments.
+func parseRangeOptions(args []string, multi bool) (rangeOptions, error) {
+	var options rangeOptions
+	for i := 0; i < len(args); i++ {
+		switch option := strings.ToLower(args[i]); {
+		case option == "filter_by_ts":
+			for i+1 < len(args) {
+				timestamp, err := strconv.ParseInt(args[i+1], 10, 64)
+				if err != nil {
+					break
+				}
+				options.timestamps = append(options.timestamps, timestamp)
+				i++
+			}
+			if len(options.timestamps) == 0 {
+				return options, errors.New("FILTER_BY_TS requires at least one timestamp")
+			}
+		case option == "filter_by_value" && i+2 < len(args):
+			minValue, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil {
+				return options, errors.New("FILTER_BY_VALUE bounds must be numbers")
+			}
+			maxValue, err := strconv.ParseFloat(args[i+2], 64)
+			if err != nil {
+				return options, errors.New("FILTER_BY_VALUE bounds must be numbers")
+			}
+			options.filterByValue, options.minValue, options.maxValue = true, minValue, maxValue
+			i += 2
+		case option == "count" && i+1 < len(args):
+			count, err := strconv.Atoi(args[i+1])
+			if err != nil || count <= 0 {
+				return options, errors.New("count must be a positive integer")
+			}
+			options.count = count
+			i++
+		case option == "aggregation" && i+2 < len(args):
+			aggregation := strings.ToLower(args[i+1])
+			if !IsAggregation(aggregation) {
+				return options, fmt.Errorf("unknown aggregation %s", args[i+1])
+			}
+			duration, err := strconv.ParseInt(args[i+2], 10, 64)
+			if err != nil || duration <= 0 {
+				return options, errors.New("bucket duration must be a positive integer")
+			}
+			options.aggregation, options.bucketDuration = aggregation, duration
+			i += 2
+		case option == "withlabels" && multi:
+			options.withLabels = true
+		case option == "filter" && multi:
+			filters, err := ParseFilters(args[i+1:])
+			if err != nil {
+				return options, err
+			}
+			options.filters = filters
+			i = len(args)
+		default:
+			return options, fmt.Errorf("unknown option %s", args[i])
+		}
+	}
+	if multi && options.filters == nil {
+		return options, errors.New("FILTER is required")
+	}
+	return options, nil
+}
+
+// apply filters the samples, aggregates them into buckets and limits the result to count samples. If reverse
+// is true, the result is ordered from the latest sample.
+func (options rangeOptions) apply(samples []Sample, reverse bool) []Sample {
+	samples = slices.DeleteFunc(samples, func(s Sample) bool {
+		if options.timestamps != nil && !slices.Contains(options.timestamps, s.Timestamp) {
+			return true
+		}
+		return options.filterByValue && (s.Value < options.minValue || s.Value > options.maxValue)
+	})
+	if options.aggregation != "" {
+		samples = Aggregate(samples, options.aggregation, options.bucketDuration)
+	}
+	if reverse {
+		slices.Reverse(samples)
+	}
+	if options.count > 0 && len(samples) > options.count {
+		samples = samples[:options.count]
+	}
+	return samples
+}
+
+// handleRANGE handles TS.RANGE and TS.REVRANGE.
+func handleRANGE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseRangeTimestamp(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseRangeTimestamp(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+	options, err := parseRangeOptions(params.Command[4:], false)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, err := getExistingSeries(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	reverse := strings.EqualFold(params.Command[0], "ts.revrange")
+	return []byte(encodeSamples(options.apply(ts.Range(from, to, now(params)), reverse))), nil
+}
+
+// findSeries returns the keys of the series that match the filters, in order, and the series at each key.
+func findSeries(params internal.HandlerFuncParams, filters []Filter) ([]string, map[string]*TimeSeries) {
+	var keys []string
+	var cursor uint64
+	for {
+		var found []string
+		cursor, found = params.ScanKeys(params.Context, cursor, 0, func(key string, value interface{}) bool {
+			ts, ok := value.(*TimeSeries)
+			return ok && ts.Matches(filters)
+		})
+		keys = append(keys, found...)
+		if cursor == 0 {
+			break
+		}
+	}
+	slices.Sort(keys)
+
+	series := make(map[string]*TimeSeries, len(keys))
+	for key, value := range params.GetValues(params.Context, keys) {
+		if ts, ok := value.(*TimeSeries); ok {
+			series[key] = ts
+		}
+	}
+	// Keys that were deleted or replaced after they were scanned are skipped.
+	keys = slices.DeleteFunc(keys, func(key string) bool {
+		return series[key] == nil
+	})
+	return keys, series
+}
+
+// handleMRANGE handles TS.MRANGE and TS.MREVRANGE. Each matching series replies with its key, its labels if
+// WITHLABELS is set, and its samples.
+func handleMRANGE(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := queryKeyFunc(5)(params.Command); err != nil {
+		return nil, err
+	}
+	from, err := parseRangeTimestamp(params.Command[1])
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseRangeTimestamp(params.Command[2])
+	if err != nil {
+		return nil, err
+	}
+	options, err := parseRangeOptions(params.Command[3:], true)
+	if err != nil {
+		return nil, err
+	}
+
+	reverse := strings.EqualFold(params.Command[0], "ts.mrevrange")
+	keys, series := findSeries(params, options.filters)
+	res := fmt.Sprintf("*%d\r\n", len(keys))
+	for _, key := range keys {
+		ts := series[key]
+		res += fmt.Sprintf("*3\r\n$%d\r\n%s\r\n", len(key), key)
+		res += encodeLabels(ts, options.withLabels)
+		res += encodeSamples(options.apply(ts.Range(from, to, now(params)), reverse))
+	}
+	return []byte(res), nil
+}
+
+// handleMGET replies with the key, the labels if WITHLABELS is set, and the latest sample of each matching series.
+func handleMGET(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := queryKeyFunc(3)(params.Command); err != nil {
+		return nil, err
+	}
+	args := params.Command[1:]
+	withLabels := strings.EqualFold(args[0], "withlabels")
+	if withLabels {
+		args = args[1:]
+	}
+	if len(args) < 2 || !strings.EqualFold(args[0], "filter") {
+		return nil, errors.New("FILTER is required")
+	}
+	filters, err := ParseFilters(args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	keys, series := findSeries(params, filters)
+	res := fmt.Sprintf("*%d\r\n", len(keys))
+	for _, key := range keys {
+		ts := series[key]
+		res += fmt.Sprintf("*3\r\n$%d\r\n%s\r\n", len(key), key)
+		res += encodeLabels(ts, withLabels)
+		res += encodeLast(ts, now(params))
+	}
+	return []byte(res), nil
+}
+
+func handleQUERYINDEX(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := queryKeyFunc(2)(params.Command); err != nil {
+		return nil, err
+	}
+	filters, err := ParseFilters(params.Command[1:])
+	if err != nil {
+		return nil, err
+	}
+	keys, _ := findSeries(params, filters)
+	res := fmt.Sprintf("*%d\r\n", len(keys))
+	for _, key := range keys {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(key), key)
+	}
+	return []byte(res), nil
+}
+
+func handleCREATERULE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := ruleKeyFunc(6)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 6 || !strings.EqualFold(params.Command[3], "aggregation") {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	aggregation := strings.ToLower(params.Command[4])
+	if !IsAggregation(aggregation) {
+		return nil, fmt.Errorf("unknown aggregation %s", params.Command[4])
+	}
+	duration, err := strconv.ParseInt(params.Command[5], 10, 64)
+	if err != nil || duration <= 0 {
+		return nil, errors.New("bucket duration must be a positive integer")
+	}
+
+	sourceKey, destKey := keys.WriteKeys[0], keys.WriteKeys[1]
+	if sourceKey == destKey {
+		return nil, errors.New("the source and destination keys must be different")
+	}
+	source, err := getExistingSeries(params, sourceKey)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := getExistingSeries(params, destKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compactions can't be chained, so the source must not be a destination and the destination must not
+	// be a source.
+	if isDestination(params, sourceKey, source) {
+		return nil, fmt.Errorf("key %s is the destination of a compaction rule", sourceKey)
+	}
+	if len(dest.Rules()) > 0 {
+		return nil, fmt.Errorf("key %s is the source of a compaction rule", destKey)
+	}
+	if source.Rule(destKey) != nil {
+		return nil, fmt.Errorf("a compaction rule from %s to %s already exists", sourceKey, destKey)
+	}
+	if isDestination(params, destKey, dest) {
+		return nil, fmt.Errorf("key %s is already the destination of a compaction rule", destKey)
+	}
+
+	source.AddRule(destKey, aggregation, duration)
+	dest.SetSourceKey(sourceKey)
+	if err = params.SetValues(params.Context, map[string]interface{}{sourceKey: source, destKey: dest}); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+// isDestination returns true if the series at key is the destination of a compaction rule. The source key of a
+// series is left behind when its source is deleted, so the rule is looked up in the source.
+func isDestination(params internal.HandlerFuncParams, key string, ts *TimeSeries) bool {
+	if ts.SourceKey() == "" {
+		return false
+	}
+	source, _, _ := getSeries(params, ts.SourceKey())
+	return source != nil && source.Rule(key) != nil
+}
+
+func handleDELETERULE(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := ruleKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	sourceKey, destKey := keys.WriteKeys[0], keys.WriteKeys[1]
+	source, err := getExistingSeries(params, sourceKey)
+	if err != nil {
+		return nil, err
+	}
+	if !source.DeleteRule(destKey) {
+		return nil, fmt.Errorf("there is no compaction rule from %s to %s", sourceKey, destKey)
+	}
+	values := map[string]interface{}{sourceKey: source}
+	if dest, _, _ := getSeries(params, destKey); dest != nil && dest.SourceKey() == sourceKey {
+		dest.SetSourceKey("")
+		values[destKey] = dest
+	}
+	if err = params.SetValues(params.Context, values); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+// handleINFO replies with the field names and values that describe the series.
+func handleINFO(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	ts, err := getExistingSeries(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+
+	samples := ts.Range(0, math.MaxInt64, now(params))
+	var first, last int64
+	if len(samples) > 0 {
+		first, last = samples[0].Timestamp, samples[len(samples)-1].Timestamp
+	}
+	res := "*18\r\n"
+	res += fmt.Sprintf("$12\r\ntotalSamples\r\n:%d\r\n", len(samples))
+	res += fmt.Sprintf("$11\r\nmemoryUsage\r\n:%d\r\n", ts.GetMem())
+	res += fmt.Sprintf("$14\r\nfirstTimestamp\r\n:%d\r\n", first)
+	res += fmt.Sprintf("$13\r\nlastTimestamp\r\n:%d\r\n", last)
+	res += fmt.Sprintf("$13\r\nretentionTime\r\n:%d\r\n", ts.Retention())
+	res += fmt.Sprintf("$15\r\nduplicatePolicy\r\n$%d\r\n%s\r\n", len(ts.DuplicatePolicy()), ts.DuplicatePolicy())
+	res += "$6\r\nlabels\r\n" + encodeLabels(ts, true)
+	if ts.SourceKey() == "" {
+		res += "$9\r\nsourceKey\r\n$-1\r\n"
+	} else {
+		res += fmt.Sprintf("$9\r\nsourceKey\r\n$%d\r\n%s\r\n", len(ts.SourceKey()), ts.SourceKey())
+	}
+	res += fmt.Sprintf("$5\r\nrules\r\n*%d\r\n", len(ts.Rules()))
+	for _, rule := range ts.Rules() {
+		res += fmt.Sprintf("*3\r\n$%d\r\n%s\r\n:%d\r\n$%d\r\n%s\r\n",
+			len(rule.DestKey), rule.DestKey, rule.BucketDuration, len(rule.Aggregation), rule.Aggregation)
+	}
+	return []byte(res), nil
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func encodeSample(sample Sample) string {
+	value := formatValue(sample.Value)
+	return fmt.Sprintf("*2\r\n:%d\r\n$%d\r\n%s\r\n", sample.Timestamp, len(value), value)
+}
+
+func encodeSamples(samples []Sample) string {
+	res := fmt.Sprintf("*%d\r\n", len(samples))
+	for _, sample := range samples {
+		res += encodeSample(sample)
+	}
+	return res
+}
+
+// encodeLast encodes the latest sample of the series, or an empty array if it has none.
+func encodeLast(ts *TimeSeries, now int64) string {
+	sample, ok := ts.Last(now)
+	if !ok {
+		return "*0\r\n"
+	}
+	return encodeSample(sample)
+}
+
+// encodeLabels encodes the labels of the series as name/value pairs, or an empty array if withLabels is false.
+func encodeLabels(ts *TimeSeries, withLabels bool) string {
+	if !withLabels {
+		return "*0\r\n"
+	}
+	res := fmt.Sprintf("*%d\r\n", len(ts.Labels()))
+	for _, label := range ts.Labels() {
+		res += fmt.Sprintf("*2\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(label.Name), label.Name, len(label.Value), label.Value)
+	}
+	return res
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "ts.create",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(TS.CREATE key [RETENTION retention] [DUPLICATE_POLICY policy] [LABELS label value [label value ...]])
+Creates a time series at key. RETENTION is the maximum age of samples in milliseconds, relative to the server clock.
+The default retention of 0 keeps samples forever. DUPLICATE_POLICY decides how a sample with the timestamp
+of an existing sample is handled, and is one of BLOCK, FIRST, LAST, MIN, MAX or SUM. The default is BLOCK.
+LABELS sets the labels used to select series in the multi-series commands.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(2),
+			HandlerFunc:       handleCREATE,
+		},
+		{
+			Command:    "ts.alter",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(TS.ALTER key [RETENTION retention] [DUPLICATE_POLICY policy] [LABELS [label value ...]])
+Changes the retention, duplicate policy or labels of the time series at key.
+LABELS replaces all the labels of the series.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(2),
+			HandlerFunc:       handleALTER,
+		},
+		{
+			Command:    "ts.add",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(TS.ADD key timestamp value [RETENTION retention] [DUPLICATE_POLICY policy] [ON_DUPLICATE policy]
+[LABELS label value [label value ...]])
+Adds a sample to the time series at key. A timestamp of * uses the server clock.
+If the key doesn't exist, the series is created with the given options.
+ON_DUPLICATE overrides the duplicate policy of the series for this sample.
+Returns the timestamp of the sample.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleADD,
+		},
+		{
+			Command:    "ts.madd",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(TS.MADD key timestamp value [key timestamp value ...])
+Adds samples to existing time series. Returns an array with the timestamp of each sample,
+or an error for each sample that couldn't be added.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: maddKeyFunc,
+			HandlerFunc:       handleMADD,
+		},
+		{
+			Command:    "ts.del",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(TS.DEL key from to)
+Deletes the samples with timestamps between from and to, inclusive, from the time series at key.
+Returns the number of samples deleted.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleDEL,
+		},
+		{
+			Command:    "ts.get",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(TS.GET key)
+Returns the latest sample of the time series at key as a timestamp and value, or an empty array if it has none.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleGET,
+		},
+		{
+			Command:    "ts.mget",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TS.MGET [WITHLABELS] FILTER filter [filter ...])
+Returns the latest sample of each time series that matches the filters, ordered by key.
+Each series replies with its key, its labels if WITHLABELS is set, and its latest sample.
+Filters are of the form label=value, label!=value, label=(value1,value2,...) or label!=(value1,value2,...).`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: queryKeyFunc(3),
+			HandlerFunc:       handleMGET,
+		},
+		{
+			Command:    "ts.range",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TS.RANGE key from to [FILTER_BY_TS timestamp [timestamp ...]] [FILTER_BY_VALUE min max] [COUNT count]
+[AGGREGATION aggregator bucketDuration])
+Returns the samples of the time series at key with timestamps between from and to, inclusive.
+- is the earliest and + is the latest timestamp. AGGREGATION groups the samples into buckets of bucketDuration
+milliseconds and replies with one sample per bucket, where the aggregator is one of AVG, SUM, MIN, MAX, RANGE,
+COUNT, FIRST or LAST. COUNT limits the number of samples returned.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(4),
+			HandlerFunc:       handleRANGE,
+		},
+		{
+			Command:    "ts.revrange",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TS.REVRANGE key from to [FILTER_BY_TS timestamp [timestamp ...]] [FILTER_BY_VALUE min max] [COUNT count]
+[AGGREGATION aggregator bucketDuration])
+Like TS.RANGE, but returns the samples from the latest to the earliest.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(4),
+			HandlerFunc:       handleRANGE,
+		},
+		{
+			Command:    "ts.mrange",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TS.MRANGE from to [FILTER_BY_TS timestamp [timestamp ...]] [FILTER_BY_VALUE min max] [COUNT count]
+[AGGREGATION aggregator bucketDuration] [WITHLABELS] FILTER filter [filter ...])
+Returns the samples between from and to of each time series that matches the filters, ordered by key.
+Each series replies with its key, its labels if WITHLABELS is set, and its samples. The options are the same
+as TS.RANGE and the filters are the same as TS.MGET.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: queryKeyFunc(5),
+			HandlerFunc:       handleMRANGE,
+		},
+		{
+			Command:    "ts.mrevrange",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TS.MREVRANGE from to [FILTER_BY_TS timestamp [timestamp ...]] [FILTER_BY_VALUE min max] [COUNT count]
+[AGGREGATION aggregator bucketDuration] [WITHLABELS] FILTER filter [filter ...])
+Like TS.MRANGE, but returns the samples of each series from the latest to the earliest.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: queryKeyFunc(5),
+			HandlerFunc:       handleMRANGE,
+		},
+		{
+			Command:    "ts.queryindex",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TS.QUERYINDEX filter [filter ...])
+Returns the keys of the time series that match the filters, ordered by key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: queryKeyFunc(2),
+			HandlerFunc:       handleQUERYINDEX,
+		},
+		{
+			Command:    "ts.createrule",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(TS.CREATERULE sourceKey destKey AGGREGATION aggregator bucketDuration)
+Creates a compaction rule that downsamples the time series at sourceKey into the series at destKey.
+When a sample is added to the source after a bucket, the aggregate of the bucket is added to the destination.
+Both series must exist, and a destination can't be the source of another rule.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: ruleKeyFunc(6),
+			HandlerFunc:       handleCREATERULE,
+		},
+		{
+			Command:    "ts.deleterule",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(TS.DELETERULE sourceKey destKey)
+Deletes the compaction rule from sourceKey to destKey. The samples in the destination are kept.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: ruleKeyFunc(3),
+			HandlerFunc:       handleDELETERULE,
+		},
+		{
+			Command:    "ts.info",
+			Module:     constants.TimeSeriesModule,
+			Categories: []string{constants.TimeSeriesCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(TS.INFO key)
+Returns the number of samples, memory usage, first and last timestamps, retention, duplicate policy, labels,
+source key and compaction rules of the time series at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleINFO,
+		},
+	}
+}
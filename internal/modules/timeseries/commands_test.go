@@ -0,0 +1,526 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/clock"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+func Test_TimeSeries(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	// The server uses the mock clock in tests, so timestamps are relative to its fixed time.
+	now := clock.NewClock().Now().UnixMilli()
+	ts := func(offset int64) string {
+		return strconv.FormatInt(now+offset, 10)
+	}
+
+	// command is a command and its expected response. Array responses are flattened and compared element by
+	// element as strings, where nil elements are empty strings and "*" matches any element.
+	type command struct {
+		command          []string
+		expectedResponse interface{}
+		expectedError    error
+	}
+
+	var flatten func(value resp.Value) []string
+	flatten = func(value resp.Value) []string {
+		if value.Type() != resp.Array {
+			return []string{value.String()}
+		}
+		var values []string
+		for _, v := range value.Array() {
+			values = append(values, flatten(v)...)
+		}
+		return values
+	}
+
+	runCommands := func(t *testing.T, client *resp.Conn, commands []command) {
+		for _, c := range commands {
+			cmd := make([]resp.Value, len(c.command))
+			for i, arg := range c.command {
+				cmd[i] = resp.StringValue(arg)
+			}
+			if err := client.WriteArray(cmd); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if c.expectedError != nil {
+				if res.Error() == nil || !strings.Contains(res.Error().Error(), c.expectedError.Error()) {
+					t.Errorf("%v: expected error \"%s\", got \"%v\"", c.command, c.expectedError.Error(), res)
+				}
+				continue
+			}
+			if res.Error() != nil {
+				t.Errorf("%v: unexpected error \"%v\"", c.command, res.Error())
+				continue
+			}
+			switch expected := c.expectedResponse.(type) {
+			case int:
+				if res.Integer() != expected {
+					t.Errorf("%v: expected response %d, got \"%v\"", c.command, expected, res)
+				}
+			case string:
+				if res.String() != expected {
+					t.Errorf("%v: expected response \"%s\", got \"%s\"", c.command, expected, res.String())
+				}
+			case []string:
+				got := flatten(res)
+				matches := len(got) == len(expected)
+				for i := 0; matches && i < len(got); i++ {
+					matches = expected[i] == "*" || got[i] == expected[i]
+				}
+				if !matches {
+					t.Errorf("%v: expected response %v, got %v", c.command, expected, got)
+				}
+			}
+		}
+	}
+
+	runTests := func(t *testing.T, tests []struct {
+		name     string
+		commands []command
+	}) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		for _, test := range tests {
+			t.Log(test.name)
+			runCommands(t, client, test.commands)
+		}
+	}
+
+	t.Run("Test_HandleCREATEAndADD", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. TS.CREATE creates a series and TS.ADD adds samples within the retention period",
+				commands: []command{
+					{
+						command:          []string{"TS.CREATE", "CreateKey1", "RETENTION", "10000", "LABELS", "sensor", "1", "area", "north"},
+						expectedResponse: "OK",
+					},
+					{command: []string{"TS.CREATE", "CreateKey1"}, expectedError: errors.New("key CreateKey1 already exists")},
+					{command: []string{"TS.ADD", "CreateKey1", "*", "1.5"}, expectedResponse: int(now)},
+					{command: []string{"TS.ADD", "CreateKey1", ts(-5000), "2"}, expectedResponse: int(now - 5000)},
+					{
+						command:       []string{"TS.ADD", "CreateKey1", ts(-20000), "2"},
+						expectedError: errors.New("timestamp is older than the retention period"),
+					},
+					{command: []string{"TS.GET", "CreateKey1"}, expectedResponse: []string{ts(0), "1.5"}},
+					{command: []string{"TS.RANGE", "CreateKey1", "-", "+"}, expectedResponse: []string{ts(-5000), "2", ts(0), "1.5"}},
+				},
+			},
+			{
+				name: "2. Duplicate samples follow the duplicate policy of the series unless ON_DUPLICATE is set",
+				commands: []command{
+					{
+						command:       []string{"TS.ADD", "CreateKey1", ts(-5000), "3"},
+						expectedError: errors.New("a sample already exists at the timestamp and the duplicate policy is block"),
+					},
+					{command: []string{"TS.ADD", "CreateKey1", ts(-5000), "3", "ON_DUPLICATE", "SUM"}, expectedResponse: int(now - 5000)},
+					{command: []string{"TS.ALTER", "CreateKey1", "DUPLICATE_POLICY", "MAX"}, expectedResponse: "OK"},
+					{command: []string{"TS.ADD", "CreateKey1", ts(0), "1"}, expectedResponse: int(now)},
+					{command: []string{"TS.ADD", "CreateKey1", ts(-5000), "7"}, expectedResponse: int(now - 5000)},
+					{command: []string{"TS.RANGE", "CreateKey1", "-", "+"}, expectedResponse: []string{ts(-5000), "7", ts(0), "1.5"}},
+					{
+						command:       []string{"TS.ALTER", "CreateKey1", "DUPLICATE_POLICY", "NEWEST"},
+						expectedError: errors.New("unknown duplicate policy NEWEST"),
+					},
+				},
+			},
+			{
+				name: "3. TS.ADD creates a missing series with the given options",
+				commands: []command{
+					{
+						command:          []string{"TS.ADD", "CreateKey2", "100", "-2.25", "DUPLICATE_POLICY", "LAST", "LABELS", "sensor", "2"},
+						expectedResponse: 100,
+					},
+					{command: []string{"TS.ADD", "CreateKey2", "100", "4"}, expectedResponse: 100},
+					{command: []string{"TS.GET", "CreateKey2"}, expectedResponse: []string{"100", "4"}},
+					{
+						command: []string{"TS.INFO", "CreateKey2"},
+						expectedResponse: []string{
+							"totalSamples", "1", "memoryUsage", "*", "firstTimestamp", "100", "lastTimestamp", "100",
+							"retentionTime", "0", "duplicatePolicy", "last", "labels", "sensor", "2", "sourceKey", "", "rules",
+						},
+					},
+				},
+			},
+			{
+				name: "4. Return errors for invalid arguments, missing keys and values of other types",
+				commands: []command{
+					{command: []string{"SET", "CreateStringKey", "value"}, expectedResponse: "OK"},
+					{command: []string{"TS.ADD", "CreateStringKey", "1", "1"}, expectedError: errors.New("value at key CreateStringKey is not a time series")},
+					{command: []string{"TS.GET", "CreateMissingKey"}, expectedError: errors.New("key CreateMissingKey does not exist")},
+					{command: []string{"TS.ALTER", "CreateMissingKey", "RETENTION", "1"}, expectedError: errors.New("key CreateMissingKey does not exist")},
+					{command: []string{"TS.ADD", "CreateKey3", "1", "nan"}, expectedError: errors.New("value must be a finite number")},
+					{command: []string{"TS.ADD", "CreateKey3", "-1", "1"}, expectedError: errors.New("timestamp must be a non-negative integer or *")},
+					{command: []string{"TS.CREATE", "CreateKey3", "RETENTION", "-1"}, expectedError: errors.New("retention must be a non-negative integer")},
+					{command: []string{"TS.CREATE", "CreateKey3", "LABELS", "sensor"}, expectedError: errors.New("labels must be name/value pairs")},
+					{command: []string{"TS.CREATE", "CreateKey3", "CHUNK_SIZE", "128"}, expectedError: errors.New("unknown option CHUNK_SIZE")},
+					{command: []string{"TS.ADD", "CreateKey3", "1"}, expectedError: errors.New(constants.WrongArgsResponse)},
+					{command: []string{"TS.GET", "CreateKey3", "LATEST"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleRANGE", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. TS.RANGE and TS.REVRANGE return the samples between the bounds",
+				commands: []command{
+					{
+						command: []string{
+							"TS.MADD", "RangeKey1", "1000", "1", "RangeKey1", "1500", "3", "RangeKey1", "2000", "5",
+							"RangeKey1", "2500", "2", "RangeKey1", "3100", "4", "RangeKey1", "4000", "10",
+						},
+						expectedResponse: []string{
+							"key RangeKey1 does not exist", "key RangeKey1 does not exist", "key RangeKey1 does not exist",
+							"key RangeKey1 does not exist", "key RangeKey1 does not exist", "key RangeKey1 does not exist",
+						},
+					},
+					{command: []string{"TS.CREATE", "RangeKey1"}, expectedResponse: "OK"},
+					{
+						command: []string{
+							"TS.MADD", "RangeKey1", "1000", "1", "RangeKey1", "1500", "3", "RangeKey1", "2000", "5",
+							"RangeKey1", "2500", "2", "RangeKey1", "3100", "4", "RangeKey1", "4000", "10",
+						},
+						expectedResponse: []string{"1000", "1500", "2000", "2500", "3100", "4000"},
+					},
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "1500", "3100"},
+						expectedResponse: []string{"1500", "3", "2000", "5", "2500", "2", "3100", "4"},
+					},
+					{
+						command:          []string{"TS.REVRANGE", "RangeKey1", "-", "+", "COUNT", "2"},
+						expectedResponse: []string{"4000", "10", "3100", "4"},
+					},
+					{command: []string{"TS.RANGE", "RangeKey1", "5000", "+"}, expectedResponse: []string{}},
+				},
+			},
+			{
+				name: "2. AGGREGATION returns one sample per bucket",
+				commands: []command{
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "AGGREGATION", "AVG", "1000"},
+						expectedResponse: []string{"1000", "2", "2000", "3.5", "3000", "4", "4000", "10"},
+					},
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "AGGREGATION", "MAX", "2000"},
+						expectedResponse: []string{"0", "3", "2000", "5", "4000", "10"},
+					},
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "AGGREGATION", "COUNT", "2000"},
+						expectedResponse: []string{"0", "2", "2000", "3", "4000", "1"},
+					},
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "AGGREGATION", "SUM", "1000"},
+						expectedResponse: []string{"1000", "4", "2000", "7", "3000", "4", "4000", "10"},
+					},
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "AGGREGATION", "MIN", "1000"},
+						expectedResponse: []string{"1000", "1", "2000", "2", "3000", "4", "4000", "10"},
+					},
+					{
+						command:          []string{"TS.REVRANGE", "RangeKey1", "-", "+", "AGGREGATION", "SUM", "2000", "COUNT", "1"},
+						expectedResponse: []string{"4000", "10"},
+					},
+					{
+						command:       []string{"TS.RANGE", "RangeKey1", "-", "+", "AGGREGATION", "MEDIAN", "1000"},
+						expectedError: errors.New("unknown aggregation MEDIAN"),
+					},
+					{
+						command:       []string{"TS.RANGE", "RangeKey1", "-", "+", "AGGREGATION", "AVG", "0"},
+						expectedError: errors.New("bucket duration must be a positive integer"),
+					},
+				},
+			},
+			{
+				name: "3. FILTER_BY_TS and FILTER_BY_VALUE filter the samples before aggregation",
+				commands: []command{
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "FILTER_BY_VALUE", "2", "5"},
+						expectedResponse: []string{"1500", "3", "2000", "5", "2500", "2", "3100", "4"},
+					},
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "FILTER_BY_TS", "1000", "2500", "9999"},
+						expectedResponse: []string{"1000", "1", "2500", "2"},
+					},
+					{
+						command:          []string{"TS.RANGE", "RangeKey1", "-", "+", "FILTER_BY_VALUE", "2", "5", "AGGREGATION", "MAX", "2000"},
+						expectedResponse: []string{"0", "3", "2000", "5"},
+					},
+				},
+			},
+			{
+				name: "4. TS.DEL deletes the samples between the bounds",
+				commands: []command{
+					{command: []string{"TS.DEL", "RangeKey1", "1500", "2500"}, expectedResponse: 3},
+					{command: []string{"TS.DEL", "RangeKey1", "1500", "2500"}, expectedResponse: 0},
+					{command: []string{"TS.RANGE", "RangeKey1", "-", "+"}, expectedResponse: []string{"1000", "1", "3100", "4", "4000", "10"}},
+					{command: []string{"TS.RANGE", "RangeKey1", "start", "+"}, expectedError: errors.New("range bounds must be integers, - or +")},
+				},
+			},
+			{
+				name: "5. Samples older than the retention period are removed",
+				commands: []command{
+					{command: []string{"TS.CREATE", "RangeKey2", "RETENTION", "1000"}, expectedResponse: "OK"},
+					{command: []string{"TS.ADD", "RangeKey2", ts(-500), "1"}, expectedResponse: int(now - 500)},
+					{
+						command:       []string{"TS.ADD", "RangeKey2", ts(-1500), "1"},
+						expectedError: errors.New("timestamp is older than the retention period"),
+					},
+					{command: []string{"TS.RANGE", "RangeKey2", "-", "+"}, expectedResponse: []string{ts(-500), "1"}},
+					{command: []string{"TS.ALTER", "RangeKey2", "RETENTION", "100"}, expectedResponse: "OK"},
+					{command: []string{"TS.RANGE", "RangeKey2", "-", "+"}, expectedResponse: []string{}},
+					{command: []string{"TS.GET", "RangeKey2"}, expectedResponse: []string{}},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleMultiSeries", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. TS.QUERYINDEX returns the keys of the series that match the filters",
+				commands: []command{
+					{command: []string{"TS.CREATE", "MultiKey1", "LABELS", "group", "multi", "sensor", "a", "area", "north"}, expectedResponse: "OK"},
+					{command: []string{"TS.CREATE", "MultiKey2", "LABELS", "group", "multi", "sensor", "b", "area", "north"}, expectedResponse: "OK"},
+					{command: []string{"TS.CREATE", "MultiKey3", "LABELS", "group", "multi", "sensor", "c", "area", "south"}, expectedResponse: "OK"},
+					{command: []string{"TS.QUERYINDEX", "group=multi"}, expectedResponse: []string{"MultiKey1", "MultiKey2", "MultiKey3"}},
+					{command: []string{"TS.QUERYINDEX", "group=multi", "area!=north"}, expectedResponse: []string{"MultiKey3"}},
+					{command: []string{"TS.QUERYINDEX", "group=multi", "area=(south,east)"}, expectedResponse: []string{"MultiKey3"}},
+					{command: []string{"TS.QUERYINDEX", "group=multi", "sensor!=(a,c)"}, expectedResponse: []string{"MultiKey2"}},
+					{command: []string{"TS.QUERYINDEX", "group=multi", "zone="}, expectedResponse: []string{"MultiKey1", "MultiKey2", "MultiKey3"}},
+					{command: []string{"TS.QUERYINDEX", "group=multi", "area!="}, expectedResponse: []string{"MultiKey1", "MultiKey2", "MultiKey3"}},
+					{
+						command:       []string{"TS.QUERYINDEX", "area!=north"},
+						expectedError: errors.New("at least one filter must be of the form label=value or label=(value1,value2,...)"),
+					},
+					{command: []string{"TS.QUERYINDEX", "area"}, expectedError: errors.New("invalid filter area")},
+				},
+			},
+			{
+				name: "2. TS.MADD replies with the timestamp or error of each sample",
+				commands: []command{
+					{
+						command: []string{
+							"TS.MADD", "MultiKey1", "1000", "1", "MultiKey2", "1000", "2", "MultiKey1", "2000", "3",
+							"MultiMissingKey", "1000", "1", "MultiKey3", "1000", "5",
+						},
+						expectedResponse: []string{"1000", "1000", "2000", "key MultiMissingKey does not exist", "1000"},
+					},
+					{command: []string{"TS.MADD", "MultiKey1", "1000"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+			{
+				name: "3. TS.MGET returns the latest sample of each matching series",
+				commands: []command{
+					{
+						command:          []string{"TS.MGET", "FILTER", "group=multi"},
+						expectedResponse: []string{"MultiKey1", "2000", "3", "MultiKey2", "1000", "2", "MultiKey3", "1000", "5"},
+					},
+					{
+						command:          []string{"TS.MGET", "WITHLABELS", "FILTER", "group=multi", "area=south"},
+						expectedResponse: []string{"MultiKey3", "area", "south", "group", "multi", "sensor", "c", "1000", "5"},
+					},
+					{command: []string{"TS.MGET", "WITHLABELS", "group=multi"}, expectedError: errors.New("FILTER is required")},
+				},
+			},
+			{
+				name: "4. TS.MRANGE and TS.MREVRANGE return the samples of each matching series",
+				commands: []command{
+					{
+						command:          []string{"TS.MRANGE", "-", "+", "FILTER", "group=multi", "area=north"},
+						expectedResponse: []string{"MultiKey1", "1000", "1", "2000", "3", "MultiKey2", "1000", "2"},
+					},
+					{
+						command:          []string{"TS.MREVRANGE", "-", "+", "COUNT", "1", "FILTER", "group=multi", "area=north"},
+						expectedResponse: []string{"MultiKey1", "2000", "3", "MultiKey2", "1000", "2"},
+					},
+					{
+						command: []string{
+							"TS.MRANGE", "-", "+", "AGGREGATION", "SUM", "5000", "WITHLABELS", "FILTER", "group=multi", "sensor=(a,b)",
+						},
+						expectedResponse: []string{
+							"MultiKey1", "area", "north", "group", "multi", "sensor", "a", "0", "4",
+							"MultiKey2", "area", "north", "group", "multi", "sensor", "b", "0", "2",
+						},
+					},
+					{command: []string{"TS.MRANGE", "-", "+", "COUNT", "1"}, expectedError: errors.New("FILTER is required")},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleRules", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. Compaction rules add the aggregate of each closed bucket to the destination",
+				commands: []command{
+					{command: []string{"TS.CREATE", "RuleSourceKey1"}, expectedResponse: "OK"},
+					{command: []string{"TS.CREATE", "RuleDestKey1"}, expectedResponse: "OK"},
+					{command: []string{"TS.CREATE", "RuleDestKey2"}, expectedResponse: "OK"},
+					{command: []string{"TS.CREATERULE", "RuleSourceKey1", "RuleDestKey1", "AGGREGATION", "AVG", "1000"}, expectedResponse: "OK"},
+					{command: []string{"TS.CREATERULE", "RuleSourceKey1", "RuleDestKey2", "AGGREGATION", "MAX", "2000"}, expectedResponse: "OK"},
+					{
+						command: []string{
+							"TS.MADD", "RuleSourceKey1", "1000", "1", "RuleSourceKey1", "1500", "3", "RuleSourceKey1", "2000", "5",
+							"RuleSourceKey1", "3500", "7", "RuleSourceKey1", "4000", "2",
+						},
+						expectedResponse: []string{"1000", "1500", "2000", "3500", "4000"},
+					},
+					{command: []string{"TS.RANGE", "RuleDestKey1", "-", "+"}, expectedResponse: []string{"1000", "2", "2000", "5", "3000", "7"}},
+					{command: []string{"TS.RANGE", "RuleDestKey2", "-", "+"}, expectedResponse: []string{"0", "3", "2000", "7"}},
+					// Samples that belong to a closed bucket are not compacted.
+					{command: []string{"TS.ADD", "RuleSourceKey1", "500", "9"}, expectedResponse: 500},
+					{command: []string{"TS.RANGE", "RuleDestKey1", "-", "+"}, expectedResponse: []string{"1000", "2", "2000", "5", "3000", "7"}},
+				},
+			},
+			{
+				name: "2. TS.INFO returns the rules of the source and the source key of the destination",
+				commands: []command{
+					{
+						command: []string{"TS.INFO", "RuleSourceKey1"},
+						expectedResponse: []string{
+							"totalSamples", "6", "memoryUsage", "*", "firstTimestamp", "500", "lastTimestamp", "4000",
+							"retentionTime", "0", "duplicatePolicy", "block", "labels", "sourceKey", "",
+							"rules", "RuleDestKey1", "1000", "avg", "RuleDestKey2", "2000", "max",
+						},
+					},
+					{
+						command: []string{"TS.INFO", "RuleDestKey1"},
+						expectedResponse: []string{
+							"totalSamples", "3", "memoryUsage", "*", "firstTimestamp", "1000", "lastTimestamp", "3000",
+							"retentionTime", "0", "duplicatePolicy", "block", "labels", "sourceKey", "RuleSourceKey1", "rules",
+						},
+					},
+				},
+			},
+			{
+				name: "3. Rules can't be duplicated or chained",
+				commands: []command{
+					{
+						command:       []string{"TS.CREATERULE", "RuleSourceKey1", "RuleDestKey1", "AGGREGATION", "SUM", "10"},
+						expectedError: errors.New("a compaction rule from RuleSourceKey1 to RuleDestKey1 already exists"),
+					},
+					{
+						command:       []string{"TS.CREATERULE", "RuleDestKey1", "RuleDestKey2", "AGGREGATION", "SUM", "10"},
+						expectedError: errors.New("key RuleDestKey1 is the destination of a compaction rule"),
+					},
+					{command: []string{"TS.CREATE", "RuleSourceKey2"}, expectedResponse: "OK"},
+					{
+						command:       []string{"TS.CREATERULE", "RuleSourceKey2", "RuleSourceKey1", "AGGREGATION", "SUM", "10"},
+						expectedError: errors.New("key RuleSourceKey1 is the source of a compaction rule"),
+					},
+					{
+						command:       []string{"TS.CREATERULE", "RuleSourceKey2", "RuleDestKey1", "AGGREGATION", "SUM", "10"},
+						expectedError: errors.New("key RuleDestKey1 is already the destination of a compaction rule"),
+					},
+					{
+						command:       []string{"TS.CREATERULE", "RuleSourceKey2", "RuleSourceKey2", "AGGREGATION", "SUM", "10"},
+						expectedError: errors.New("the source and destination keys must be different"),
+					},
+					{
+						command:       []string{"TS.CREATERULE", "RuleSourceKey2", "RuleMissingKey", "AGGREGATION", "SUM", "10"},
+						expectedError: errors.New("key RuleMissingKey does not exist"),
+					},
+					{
+						command:       []string{"TS.CREATERULE", "RuleSourceKey2", "RuleDestKey2", "SUM", "10"},
+						expectedError: errors.New(constants.WrongArgsResponse),
+					},
+				},
+			},
+			{
+				name: "4. TS.DELETERULE stops the compaction and frees the destination",
+				commands: []command{
+					{command: []string{"TS.DELETERULE", "RuleSourceKey1", "RuleDestKey1"}, expectedResponse: "OK"},
+					{
+						command:       []string{"TS.DELETERULE", "RuleSourceKey1", "RuleDestKey1"},
+						expectedError: errors.New("there is no compaction rule from RuleSourceKey1 to RuleDestKey1"),
+					},
+					{command: []string{"TS.ADD", "RuleSourceKey1", "6000", "1"}, expectedResponse: 6000},
+					{command: []string{"TS.RANGE", "RuleDestKey1", "-", "+"}, expectedResponse: []string{"1000", "2", "2000", "5", "3000", "7"}},
+					{command: []string{"TS.RANGE", "RuleDestKey2", "-", "+"}, expectedResponse: []string{"0", "3", "2000", "7", "4000", "2"}},
+					{command: []string{"TS.CREATERULE", "RuleSourceKey2", "RuleDestKey1", "AGGREGATION", "COUNT", "1000"}, expectedResponse: "OK"},
+				},
+			},
+		})
+	})
+}
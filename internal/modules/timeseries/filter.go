@@ -0,0 +1,77 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Filter selects series by a label. A series without the label is treated as having the label with an
+// empty value, so "label=" matches series without the label and "label!=" matches series with it.
+type Filter struct {
+	label  string
+	values []string
+	negate bool
+}
+
+// ParseFilters parses label filters of the forms label=value, label!=value, label=(value1,value2,...) and
+// label!=(value1,value2,...). At least one filter must match series by value, so that a query can't select
+// every series that lacks a label.
+func ParseFilters(args []string) ([]Filter, error) {
+	if len(args) == 0 {
+		return nil, errors.New("at least one filter is required")
+	}
+	filters := make([]Filter, len(args))
+	positive := false
+	for i, arg := range args {
+		index := strings.Index(arg, "=")
+		if index <= 0 {
+			return nil, errors.New("invalid filter " + arg)
+		}
+		filter := Filter{label: arg[:index]}
+		if strings.HasSuffix(filter.label, "!") {
+			filter.label, filter.negate = strings.TrimSuffix(filter.label, "!"), true
+		}
+		if filter.label == "" {
+			return nil, errors.New("invalid filter " + arg)
+		}
+		value := arg[index+1:]
+		if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			filter.values = strings.Split(value[1:len(value)-1], ",")
+		} else {
+			filter.values = []string{value}
+		}
+		if !filter.negate && slices.ContainsFunc(filter.values, func(v string) bool { return v != "" }) {
+			positive = true
+		}
+		filters[i] = filter
+	}
+	if !positive {
+		return nil, errors.New("at least one filter must be of the form label=value or label=(value1,value2,...)")
+	}
+	return filters, nil
+}
+
+// Matches returns true if the series matches all the filters.
+func (ts *TimeSeries) Matches(filters []Filter) bool {
+	for _, filter := range filters {
+		if slices.Contains(filter.values, ts.Label(filter.label)) == filter.negate {
+			return false
+		}
+	}
+	return true
+}
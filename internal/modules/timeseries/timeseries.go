@@ -0,0 +1,493 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// The policies for a sample added at the timestamp of an existing sample.
+const (
+	DuplicateBlock = "block" // Reject the sample.
+	DuplicateFirst = "first" // Keep the existing value.
+	DuplicateLast  = "last"  // Replace the existing value.
+	DuplicateMin   = "min"   // Keep the lower value.
+	DuplicateMax   = "max"   // Keep the higher value.
+	DuplicateSum   = "sum"   // Add the value to the existing value.
+)
+
+var duplicatePolicies = []string{DuplicateBlock, DuplicateFirst, DuplicateLast, DuplicateMin, DuplicateMax, DuplicateSum}
+
+// Aggregations are the aggregation functions supported by ranges and compaction rules.
+var Aggregations = []string{"avg", "sum", "min", "max", "range", "count", "first", "last"}
+
+// Sample is a value of a series at a timestamp in milliseconds.
+type Sample struct {
+	Timestamp int64
+	Value     float64
+}
+
+// Label is a name/value pair that describes a series. Series are selected by their labels in multi-series queries.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Rule is a compaction rule that aggregates the samples added to a series into buckets of BucketDuration
+// milliseconds. The aggregate of a bucket is added to the series at DestKey once a sample of a later bucket
+// is added, since the bucket can still change until then.
+type Rule struct {
+	DestKey        string
+	Aggregation    string
+	BucketDuration int64
+	open           bool
+	bucketStart    int64
+	aggregator     aggregator
+}
+
+// Compaction is a sample produced by a compaction rule, to be added to the series at DestKey.
+type Compaction struct {
+	DestKey string
+	Sample  Sample
+}
+
+// TimeSeries holds samples ordered by timestamp. Samples older than the retention period, measured from the
+// current time, are removed as new samples are added and are skipped by reads.
+type TimeSeries struct {
+	retention       int64 // In milliseconds. 0 keeps samples forever.
+	duplicatePolicy string
+	labels          []Label
+	sourceKey       string // The key of the series that compacts into this one, if any.
+	rules           []*Rule
+	samples         []Sample
+}
+
+// NewTimeSeries returns an empty series. The duplicate policy defaults to block, and the labels are sorted by name.
+func NewTimeSeries(retention int64, duplicatePolicy string, labels []Label) *TimeSeries {
+	ts := &TimeSeries{retention: retention, duplicatePolicy: DuplicateBlock}
+	if duplicatePolicy != "" {
+		ts.duplicatePolicy = duplicatePolicy
+	}
+	ts.SetLabels(labels)
+	return ts
+}
+
+func (ts *TimeSeries) GetMem() int64 {
+	var size int64
+	size += int64(unsafe.Sizeof(*ts))
+	size += int64(len(ts.duplicatePolicy) + len(ts.sourceKey))
+	for _, label := range ts.labels {
+		size += int64(unsafe.Sizeof(label)) + int64(len(label.Name)+len(label.Value))
+	}
+	for _, rule := range ts.rules {
+		size += int64(unsafe.Sizeof(*rule)) + int64(len(rule.DestKey)+len(rule.Aggregation))
+	}
+	size += int64(cap(ts.samples)) * int64(unsafe.Sizeof(Sample{}))
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*TimeSeries)(nil)
+
+// IsDuplicatePolicy returns true if the policy is one of the supported duplicate policies.
+func IsDuplicatePolicy(policy string) bool {
+	return slices.Contains(duplicatePolicies, policy)
+}
+
+// IsAggregation returns true if the aggregation is one of the supported aggregations.
+func IsAggregation(aggregation string) bool {
+	return slices.Contains(Aggregations, aggregation)
+}
+
+func (ts *TimeSeries) Retention() int64 {
+	return ts.retention
+}
+
+func (ts *TimeSeries) SetRetention(retention int64) {
+	ts.retention = retention
+}
+
+func (ts *TimeSeries) DuplicatePolicy() string {
+	return ts.duplicatePolicy
+}
+
+func (ts *TimeSeries) SetDuplicatePolicy(policy string) {
+	ts.duplicatePolicy = policy
+}
+
+// Labels returns the labels of the series, sorted by name.
+func (ts *TimeSeries) Labels() []Label {
+	return ts.labels
+}
+
+// SetLabels replaces the labels of the series. Labels with an empty value are dropped, as a label with an empty
+// value can't be told apart from a missing label by filters.
+func (ts *TimeSeries) SetLabels(labels []Label) {
+	ts.labels = make([]Label, 0, len(labels))
+	for _, label := range labels {
+		if label.Value == "" {
+			continue
+		}
+		if i := slices.IndexFunc(ts.labels, func(l Label) bool { return l.Name == label.Name }); i >= 0 {
+			ts.labels[i] = label
+			continue
+		}
+		ts.labels = append(ts.labels, label)
+	}
+	sort.Slice(ts.labels, func(i, j int) bool {
+		return ts.labels[i].Name < ts.labels[j].Name
+	})
+}
+
+// Label returns the value of the label, or an empty string if the series doesn't have it.
+func (ts *TimeSeries) Label(name string) string {
+	for _, label := range ts.labels {
+		if label.Name == name {
+			return label.Value
+		}
+	}
+	return ""
+}
+
+func (ts *TimeSeries) SourceKey() string {
+	return ts.sourceKey
+}
+
+func (ts *TimeSeries) SetSourceKey(key string) {
+	ts.sourceKey = key
+}
+
+func (ts *TimeSeries) Rules() []*Rule {
+	return ts.rules
+}
+
+// Rule returns the compaction rule into the destination key, or nil if there isn't one.
+func (ts *TimeSeries) Rule(destKey string) *Rule {
+	for _, rule := range ts.rules {
+		if rule.DestKey == destKey {
+			return rule
+		}
+	}
+	return nil
+}
+
+// AddRule adds a compaction rule into the destination key. Only samples added after the rule is created
+// are compacted.
+func (ts *TimeSeries) AddRule(destKey string, aggregation string, bucketDuration int64) {
+	ts.rules = append(ts.rules, &Rule{DestKey: destKey, Aggregation: aggregation, BucketDuration: bucketDuration})
+}
+
+// DeleteRule deletes the compaction rule into the destination key. Returns false if there isn't one.
+func (ts *TimeSeries) DeleteRule(destKey string) bool {
+	i := slices.IndexFunc(ts.rules, func(rule *Rule) bool { return rule.DestKey == destKey })
+	if i < 0 {
+		return false
+	}
+	ts.rules = slices.Delete(ts.rules, i, i+1)
+	return true
+}
+
+// cutoff returns the timestamp of the oldest sample within the retention period.
+func (ts *TimeSeries) cutoff(now int64) int64 {
+	if ts.retention == 0 || now-ts.retention < 0 {
+		return 0
+	}
+	return now - ts.retention
+}
+
+// Add adds the sample to the series and removes the samples that are older than the retention period.
+// If a sample exists at the timestamp, the policy decides the value, and defaults to the duplicate policy
+// of the series. Returns the samples produced by the compaction rules of the series.
+func (ts *TimeSeries) Add(sample Sample, policy string, now int64) ([]Compaction, error) {
+	if sample.Timestamp < ts.cutoff(now) {
+		return nil, errors.New("timestamp is older than the retention period")
+	}
+	if policy == "" {
+		policy = ts.duplicatePolicy
+	}
+
+	i, found := slices.BinarySearchFunc(ts.samples, sample.Timestamp, func(s Sample, t int64) int {
+		return cmpInt64(s.Timestamp, t)
+	})
+	if found {
+		existing := &ts.samples[i]
+		switch policy {
+		case DuplicateBlock:
+			return nil, errors.New("a sample already exists at the timestamp and the duplicate policy is block")
+		case DuplicateFirst:
+		case DuplicateLast:
+			existing.Value = sample.Value
+		case DuplicateMin:
+			existing.Value = math.Min(existing.Value, sample.Value)
+		case DuplicateMax:
+			existing.Value = math.Max(existing.Value, sample.Value)
+		case DuplicateSum:
+			existing.Value += sample.Value
+		}
+		return nil, nil
+	}
+
+	ts.samples = slices.Insert(ts.samples, i, sample)
+	ts.trim(now)
+	return ts.compact(sample), nil
+}
+
+// trim removes the samples that are older than the retention period.
+func (ts *TimeSeries) trim(now int64) {
+	i, _ := slices.BinarySearchFunc(ts.samples, ts.cutoff(now), func(s Sample, t int64) int {
+		return cmpInt64(s.Timestamp, t)
+	})
+	if i > 0 {
+		ts.samples = slices.Delete(ts.samples, 0, i)
+	}
+}
+
+// compact adds the sample to the open bucket of each rule. When the sample belongs to a later bucket, the open
+// bucket is closed and its aggregate is returned. Samples that belong to a closed bucket are ignored.
+func (ts *TimeSeries) compact(sample Sample) []Compaction {
+	var compactions []Compaction
+	for _, rule := range ts.rules {
+		start := BucketStart(sample.Timestamp, rule.BucketDuration)
+		switch {
+		case !rule.open:
+			rule.open, rule.bucketStart, rule.aggregator = true, start, aggregator{}
+		case start < rule.bucketStart:
+			continue
+		case start > rule.bucketStart:
+			compactions = append(compactions, Compaction{
+				DestKey: rule.DestKey,
+				Sample:  Sample{Timestamp: rule.bucketStart, Value: rule.aggregator.result(rule.Aggregation)},
+			})
+			rule.bucketStart, rule.aggregator = start, aggregator{}
+		}
+		rule.aggregator.add(sample.Value)
+	}
+	return compactions
+}
+
+// Len returns the number of samples in the series, including samples that have expired but not been removed.
+func (ts *TimeSeries) Len() int {
+	return len(ts.samples)
+}
+
+// Last returns the latest sample within the retention period.
+func (ts *TimeSeries) Last(now int64) (Sample, bool) {
+	if len(ts.samples) == 0 || ts.samples[len(ts.samples)-1].Timestamp < ts.cutoff(now) {
+		return Sample{}, false
+	}
+	return ts.samples[len(ts.samples)-1], true
+}
+
+// Range returns the samples within the retention period with timestamps from from to to, inclusive.
+func (ts *TimeSeries) Range(from int64, to int64, now int64) []Sample {
+	from = max(from, ts.cutoff(now))
+	start, _ := slices.BinarySearchFunc(ts.samples, from, func(s Sample, t int64) int {
+		return cmpInt64(s.Timestamp, t)
+	})
+	end := start
+	for end < len(ts.samples) && ts.samples[end].Timestamp <= to {
+		end++
+	}
+	return slices.Clone(ts.samples[start:end])
+}
+
+// Delete deletes the samples with timestamps from from to to, inclusive, and returns the number deleted.
+func (ts *TimeSeries) Delete(from int64, to int64) int {
+	count := len(ts.samples)
+	ts.samples = slices.DeleteFunc(ts.samples, func(s Sample) bool {
+		return s.Timestamp >= from && s.Timestamp <= to
+	})
+	return count - len(ts.samples)
+}
+
+// BucketStart returns the start of the bucket of the given duration that the timestamp belongs to.
+func BucketStart(timestamp int64, duration int64) int64 {
+	return timestamp - timestamp%duration
+}
+
+// Aggregate aggregates the samples, which must be ordered by timestamp, into buckets of the given duration.
+// Each bucket is returned as a sample at the start of the bucket. Empty buckets are skipped.
+func Aggregate(samples []Sample, aggregation string, duration int64) []Sample {
+	var result []Sample
+	var agg aggregator
+	for i, sample := range samples {
+		start := BucketStart(sample.Timestamp, duration)
+		if i > 0 && start != result[len(result)-1].Timestamp {
+			result[len(result)-1].Value = agg.result(aggregation)
+			agg = aggregator{}
+		}
+		if i == 0 || start != result[len(result)-1].Timestamp {
+			result = append(result, Sample{Timestamp: start})
+		}
+		agg.add(sample.Value)
+	}
+	if len(result) > 0 {
+		result[len(result)-1].Value = agg.result(aggregation)
+	}
+	return result
+}
+
+type aggregator struct {
+	count int64
+	sum   float64
+	min   float64
+	max   float64
+	first float64
+	last  float64
+}
+
+func (a *aggregator) add(value float64) {
+	if a.count == 0 {
+		a.min, a.max, a.first = value, value, value
+	}
+	a.count++
+	a.sum += value
+	a.min = math.Min(a.min, value)
+	a.max = math.Max(a.max, value)
+	a.last = value
+}
+
+func (a *aggregator) result(aggregation string) float64 {
+	switch aggregation {
+	case "avg":
+		return a.sum / float64(a.count)
+	case "sum":
+		return a.sum
+	case "min":
+		return a.min
+	case "max":
+		return a.max
+	case "range":
+		return a.max - a.min
+	case "count":
+		return float64(a.count)
+	case "first":
+		return a.first
+	default:
+		return a.last
+	}
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (ts *TimeSeries) MarshalBinary() ([]byte, error) {
+	b := binary.AppendVarint(nil, ts.retention)
+	b = internal.AppendBinaryString(b, ts.duplicatePolicy)
+	b = internal.AppendBinaryString(b, ts.sourceKey)
+	b = binary.AppendUvarint(b, uint64(len(ts.labels)))
+	for _, label := range ts.labels {
+		b = internal.AppendBinaryString(b, label.Name)
+		b = internal.AppendBinaryString(b, label.Value)
+	}
+	b = binary.AppendUvarint(b, uint64(len(ts.rules)))
+	for _, rule := range ts.rules {
+		b = internal.AppendBinaryString(b, rule.DestKey)
+		b = internal.AppendBinaryString(b, rule.Aggregation)
+		b = binary.AppendVarint(b, rule.BucketDuration)
+		if !rule.open {
+			b = append(b, 0)
+			continue
+		}
+		b = append(b, 1)
+		b = binary.AppendVarint(b, rule.bucketStart)
+		b = binary.AppendVarint(b, rule.aggregator.count)
+		for _, f := range []float64{rule.aggregator.sum, rule.aggregator.min, rule.aggregator.max,
+			rule.aggregator.first, rule.aggregator.last} {
+			b = internal.AppendBinaryFloat(b, f)
+		}
+	}
+	// Timestamps are written as the difference from the previous timestamp, which is usually small.
+	b = binary.AppendUvarint(b, uint64(len(ts.samples)))
+	var previous int64
+	for _, sample := range ts.samples {
+		b = binary.AppendVarint(b, sample.Timestamp-previous)
+		b = internal.AppendBinaryFloat(b, sample.Value)
+		previous = sample.Timestamp
+	}
+	return b, nil
+}
+
+func (ts *TimeSeries) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	series := TimeSeries{
+		retention:       r.Varint(),
+		duplicatePolicy: r.String(),
+		sourceKey:       r.String(),
+	}
+	series.labels = make([]Label, r.Count())
+	for i := range series.labels {
+		series.labels[i] = Label{Name: r.String(), Value: r.String()}
+	}
+	series.rules = make([]*Rule, r.Count())
+	for i := range series.rules {
+		rule := &Rule{DestKey: r.String(), Aggregation: r.String(), BucketDuration: r.Varint()}
+		if rule.open = r.Byte() == 1; rule.open {
+			rule.bucketStart = r.Varint()
+			rule.aggregator = aggregator{count: r.Varint(), sum: r.Float(), min: r.Float(), max: r.Float(),
+				first: r.Float(), last: r.Float()}
+		}
+		if rule.BucketDuration <= 0 && r.Err() == nil {
+			return errors.New("invalid compaction rule")
+		}
+		series.rules[i] = rule
+	}
+	series.samples = make([]Sample, r.Count())
+	var previous int64
+	for i := range series.samples {
+		previous += r.Varint()
+		series.samples[i] = Sample{Timestamp: previous, Value: r.Float()}
+	}
+	if r.Err() != nil {
+		return r.Err()
+	}
+	if !IsDuplicatePolicy(series.duplicatePolicy) {
+		return errors.New("invalid time series")
+	}
+	*ts = series
+	return nil
+}
+
+// ParseLabels parses label name/value pairs. Names are case-sensitive.
+func ParseLabels(args []string) ([]Label, error) {
+	if len(args)%2 != 0 {
+		return nil, errors.New("labels must be name/value pairs")
+	}
+	labels := make([]Label, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		if args[i] == "" || strings.ContainsAny(args[i], "=!(),") {
+			return nil, errors.New("invalid label name " + args[i])
+		}
+		labels = append(labels, Label{Name: args[i], Value: args[i+1]})
+	}
+	return labels, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transaction
+
+import (
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+func handleMulti(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := multiKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+	if err := params.StartTransaction(params.Connection); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleExec(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := execKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+	return params.ExecTransaction(params.Context, params.Connection)
+}
+
+func handleDiscard(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := discardKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+	if err := params.DiscardTransaction(params.Connection); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleWatch(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := watchKeyFunc(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if err = params.WatchKeys(params.Context, params.Connection, keys.ReadKeys); err != nil {
+		return nil, err
+	}
+	return []byte(constants.OkResponse), nil
+}
+
+func handleUnwatch(params internal.HandlerFuncParams) ([]byte, error) {
+	if _, err := unwatchKeyFunc(params.Command); err != nil {
+		return nil, err
+	}
+	params.UnwatchKeys(params.Connection)
+	return []byte(constants.OkResponse), nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "multi",
+			Module:     constants.TransactionModule,
+			Categories: []string{constants.TransactionCategory, constants.FastCategory},
+			Description: `(MULTI) Mark the start of a transaction block.
+All subsequent commands are queued and executed atomically when EXEC is called.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: multiKeyFunc,
+			HandlerFunc:       handleMulti,
+		},
+		{
+			Command:    "exec",
+			Module:     constants.TransactionModule,
+			Categories: []string{constants.TransactionCategory, constants.SlowCategory},
+			Description: `(EXEC) Execute all the commands queued since MULTI atomically.
+Returns an array with the response of each command, or a null array if any of the watched keys was modified.`,
+			// EXEC is not synced itself. The queued commands are replicated as a single entry when required.
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: execKeyFunc,
+			HandlerFunc:       handleExec,
+		},
+		{
+			Command:           "discard",
+			Module:            constants.TransactionModule,
+			Categories:        []string{constants.TransactionCategory, constants.FastCategory},
+			Description:       `(DISCARD) Discard all the commands queued since MULTI.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: discardKeyFunc,
+			HandlerFunc:       handleDiscard,
+		},
+		{
+			Command:    "watch",
+			Module:     constants.TransactionModule,
+			Categories: []string{constants.TransactionCategory, constants.FastCategory},
+			Description: `(WATCH key [key ...]) Watch the keys for modifications.
+If any of the keys is modified before EXEC is called, the transaction is aborted.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: watchKeyFunc,
+			HandlerFunc:       handleWatch,
+		},
+		{
+			Command:           "unwatch",
+			Module:            constants.TransactionModule,
+			Categories:        []string{constants.TransactionCategory, constants.FastCategory},
+			Description:       `(UNWATCH) Forget all the keys watched by the connection.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: unwatchKeyFunc,
+			HandlerFunc:       handleUnwatch,
+		},
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transaction_test
+
+import (
+	"net"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+type step struct {
+	command          []string
+	expectedResponse string   // Expected simple or bulk string response.
+	expectedArray    []string // Expected array response. Each element is compared using its string representation.
+	expectedNull     bool     // True if the response is expected to be a null array.
+	expectedError    string   // Expected substring of the error response.
+}
+
+func runSteps(t *testing.T, client *resp.Conn, steps []step) {
+	for i, s := range steps {
+		command := make([]resp.Value, len(s.command))
+		for j, c := range s.command {
+			command[j] = resp.StringValue(c)
+		}
+		if err := client.WriteArray(command); err != nil {
+			t.Error(err)
+			return
+		}
+		res, _, err := client.ReadValue()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		switch {
+		case s.expectedError != "":
+			if res.Error() == nil || !strings.Contains(res.Error().Error(), s.expectedError) {
+				t.Errorf("step %d %v: expected error \"%s\", got \"%s\"", i, s.command, s.expectedError, res.String())
+			}
+		case s.expectedNull:
+			if !res.IsNull() {
+				t.Errorf("step %d %v: expected null response, got \"%s\"", i, s.command, res.String())
+			}
+		case s.expectedArray != nil:
+			arr := make([]string, len(res.Array()))
+			for j, v := range res.Array() {
+				arr[j] = v.String()
+			}
+			if !slices.Equal(arr, s.expectedArray) {
+				t.Errorf("step %d %v: expected response %v, got %v", i, s.command, s.expectedArray, arr)
+			}
+		default:
+			if res.String() != s.expectedResponse {
+				t.Errorf("step %d %v: expected response \"%s\", got \"%s\"", i, s.command, s.expectedResponse, res.String())
+			}
+		}
+	}
+}
+
+func connect(t *testing.T, port int) (net.Conn, *resp.Conn) {
+	conn, err := internal.GetConnection("localhost", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, resp.NewConn(conn)
+}
+
+func Test_Transaction(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	t.Run("Test_HandleMultiExec", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name  string
+			steps []step
+		}{
+			{
+				name: "1. Execute queued commands and return their responses",
+				steps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"SET", "MultiExecKey1", "value1"}, expectedResponse: "QUEUED"},
+					{command: []string{"GET", "MultiExecKey1"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedArray: []string{"OK", "value1"}},
+					{command: []string{"GET", "MultiExecKey1"}, expectedResponse: "value1"},
+				},
+			},
+			{
+				name: "2. Return errors of failed commands without aborting the other commands",
+				steps: []step{
+					{command: []string{"SET", "MultiExecKey2", "value2"}, expectedResponse: "OK"},
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"LPUSH", "MultiExecKey2", "element"}, expectedResponse: "QUEUED"},
+					{command: []string{"SET", "MultiExecKey3", "value3"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedArray: []string{
+						"Error LPUSH command on non-list item", "OK",
+					}},
+					{command: []string{"GET", "MultiExecKey3"}, expectedResponse: "value3"},
+				},
+			},
+			{
+				name: "3. Discard the transaction when a command fails to queue",
+				steps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"SET", "MultiExecKey4", "value4"}, expectedResponse: "QUEUED"},
+					{command: []string{"GET"}, expectedError: constants.WrongArgsResponse},
+					{command: []string{"UNKNOWN"}, expectedError: "command UNKNOWN not supported"},
+					{command: []string{"EXEC"}, expectedError: "EXECABORT"},
+					{command: []string{"GET", "MultiExecKey4"}, expectedNull: true},
+				},
+			},
+			{
+				name: "4. Return an empty array when no commands are queued",
+				steps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"EXEC"}, expectedArray: []string{}},
+				},
+			},
+			{
+				name: "5. Return error when EXEC is called without MULTI",
+				steps: []step{
+					{command: []string{"EXEC"}, expectedError: "EXEC without MULTI"},
+				},
+			},
+			{
+				name: "6. Return error when MULTI calls are nested",
+				steps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"MULTI"}, expectedError: "MULTI calls can not be nested"},
+					{command: []string{"SET", "MultiExecKey5", "value5"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedArray: []string{"OK"}},
+				},
+			},
+			{
+				name: "7. Use the database selected within the transaction for subsequent commands",
+				steps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"SELECT", "1"}, expectedResponse: "QUEUED"},
+					{command: []string{"SET", "MultiExecKey6", "value6"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedArray: []string{"OK", "OK"}},
+					{command: []string{"GET", "MultiExecKey6"}, expectedResponse: "value6"},
+					{command: []string{"SELECT", "0"}, expectedResponse: "OK"},
+					{command: []string{"GET", "MultiExecKey6"}, expectedNull: true},
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				conn, client := connect(t, port)
+				defer func() {
+					_ = conn.Close()
+				}()
+				runSteps(t, client, test.steps)
+			})
+		}
+	})
+
+	t.Run("Test_HandleDiscard", func(t *testing.T) {
+		t.Parallel()
+		conn, client := connect(t, port)
+		defer func() {
+			_ = conn.Close()
+		}()
+		runSteps(t, client, []step{
+			{command: []string{"DISCARD"}, expectedError: "DISCARD without MULTI"},
+			{command: []string{"MULTI"}, expectedResponse: "OK"},
+			{command: []string{"SET", "DiscardKey1", "value1"}, expectedResponse: "QUEUED"},
+			{command: []string{"DISCARD"}, expectedResponse: "OK"},
+			{command: []string{"GET", "DiscardKey1"}, expectedNull: true},
+			{command: []string{"EXEC"}, expectedError: "EXEC without MULTI"},
+		})
+	})
+
+	t.Run("Test_HandleWatch", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			steps       []step
+			otherClient []step // Steps executed by another client after the first len(steps)-2 steps.
+			finalSteps  []step
+		}{
+			{
+				name: "1. Abort the transaction when a watched key is modified by another client",
+				steps: []step{
+					{command: []string{"SET", "WatchKey1", "value1"}, expectedResponse: "OK"},
+					{command: []string{"WATCH", "WatchKey1"}, expectedResponse: "OK"},
+				},
+				otherClient: []step{
+					{command: []string{"SET", "WatchKey1", "other"}, expectedResponse: "OK"},
+				},
+				finalSteps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"SET", "WatchKey1", "value2"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedNull: true},
+					{command: []string{"GET", "WatchKey1"}, expectedResponse: "other"},
+				},
+			},
+			{
+				name: "2. Abort the transaction when a watched key is deleted by another client",
+				steps: []step{
+					{command: []string{"SET", "WatchKey2", "value1"}, expectedResponse: "OK"},
+					{command: []string{"WATCH", "WatchKey2"}, expectedResponse: "OK"},
+				},
+				otherClient: []step{
+					{command: []string{"DEL", "WatchKey2"}, expectedResponse: "1"},
+				},
+				finalSteps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"SET", "WatchKey2", "value2"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedNull: true},
+				},
+			},
+			{
+				name: "3. Execute the transaction when watched keys are not modified",
+				steps: []step{
+					{command: []string{"WATCH", "WatchKey3", "WatchKey4"}, expectedResponse: "OK"},
+				},
+				otherClient: []step{
+					{command: []string{"SET", "WatchKey5", "value"}, expectedResponse: "OK"},
+				},
+				finalSteps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"SET", "WatchKey3", "value3"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedArray: []string{"OK"}},
+				},
+			},
+			{
+				name: "4. Execute the transaction when the keys are unwatched before modification",
+				steps: []step{
+					{command: []string{"WATCH", "WatchKey6"}, expectedResponse: "OK"},
+					{command: []string{"UNWATCH"}, expectedResponse: "OK"},
+				},
+				otherClient: []step{
+					{command: []string{"SET", "WatchKey6", "other"}, expectedResponse: "OK"},
+				},
+				finalSteps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"SET", "WatchKey6", "value6"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedArray: []string{"OK"}},
+					{command: []string{"GET", "WatchKey6"}, expectedResponse: "value6"},
+				},
+			},
+			{
+				name: "5. Abort the transaction when the watched key's database is flushed",
+				steps: []step{
+					{command: []string{"SELECT", "2"}, expectedResponse: "OK"},
+					{command: []string{"SET", "WatchKey7", "value7"}, expectedResponse: "OK"},
+					{command: []string{"WATCH", "WatchKey7"}, expectedResponse: "OK"},
+				},
+				otherClient: []step{
+					{command: []string{"SELECT", "2"}, expectedResponse: "OK"},
+					{command: []string{"FLUSHDB"}, expectedResponse: "OK"},
+				},
+				finalSteps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"GET", "WatchKey7"}, expectedResponse: "QUEUED"},
+					{command: []string{"EXEC"}, expectedNull: true},
+				},
+			},
+			{
+				name: "6. Return error when WATCH is called inside MULTI",
+				steps: []step{
+					{command: []string{"MULTI"}, expectedResponse: "OK"},
+					{command: []string{"WATCH", "WatchKey8"}, expectedError: "WATCH inside MULTI is not allowed"},
+					{command: []string{"DISCARD"}, expectedResponse: "OK"},
+				},
+			},
+			{
+				name: "7. Command too short",
+				steps: []step{
+					{command: []string{"WATCH"}, expectedError: constants.WrongArgsResponse},
+				},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				conn, client := connect(t, port)
+				defer func() {
+					_ = conn.Close()
+				}()
+				otherConn, otherClient := connect(t, port)
+				defer func() {
+					_ = otherConn.Close()
+				}()
+				runSteps(t, client, test.steps)
+				runSteps(t, otherClient, test.otherClient)
+				runSteps(t, client, test.finalSteps)
+			})
+		}
+	})
+}
@@ -0,0 +1,581 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_set
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+)
+
+// getVectorSet returns the vector set at key. It returns false if the key doesn't exist, and an error if the
+// value is not a vector set.
+func getVectorSet(params internal.HandlerFuncParams, key string) (*VectorSet, bool, error) {
+	if !params.KeysExist(params.Context, []string{key})[key] {
+		return nil, false, nil
+	}
+	vs, ok := params.GetValues(params.Context, []string{key})[key].(*VectorSet)
+	if !ok {
+		return nil, false, fmt.Errorf("value at key %s is not a vector set", key)
+	}
+	return vs, true, nil
+}
+
+// parseVector parses a vector given as FP32 blob or VALUES num value [value ...], and returns the number of
+// arguments it used.
+func parseVector(args []string) ([]float32, int, error) {
+	if len(args) < 2 {
+		return nil, 0, errors.New(constants.WrongArgsResponse)
+	}
+	var vector []float32
+	var n int
+	switch strings.ToLower(args[0]) {
+	case "fp32":
+		v, err := DecodeVector([]byte(args[1]))
+		if err != nil {
+			return nil, 0, err
+		}
+		vector, n = v, 2
+	case "values":
+		count, err := strconv.Atoi(args[1])
+		if err != nil || count < 1 {
+			return nil, 0, errors.New("the number of values must be a positive integer")
+		}
+		if len(args) < 2+count {
+			return nil, 0, errors.New(constants.WrongArgsResponse)
+		}
+		vector = make([]float32, count)
+		for i := range vector {
+			f, err := strconv.ParseFloat(args[2+i], 32)
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid vector value %s", args[2+i])
+			}
+			vector[i] = float32(f)
+		}
+		n = 2 + count
+	default:
+		return nil, 0, errors.New("the vector must be given as FP32 blob or VALUES num value [value ...]")
+	}
+	if err := ValidateVector(vector); err != nil {
+		return nil, 0, err
+	}
+	return vector, n, nil
+}
+
+func parsePositiveInt(arg string, name string, maxValue int) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > maxValue {
+		return 0, fmt.Errorf("%s must be an integer between 1 and %d", name, maxValue)
+	}
+	return n, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func encodeBulkString(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func handleVADD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(5)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	vector, n, err := parseVector(params.Command[2:])
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) < 3+n {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	element := params.Command[2+n]
+
+	var attributes *jsondoc.Document
+	var setAttributes bool
+	var metric, index string
+	var m, ef int
+	args := params.Command[3+n:]
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("unknown option %s", args[i])
+		}
+		switch option := strings.ToLower(args[i]); option {
+		case "setattr":
+			if attributes, err = ParseAttributes(args[i+1]); err != nil {
+				return nil, err
+			}
+			setAttributes = true
+		case "m":
+			if m, err = parsePositiveInt(args[i+1], "M", MaxM); err != nil {
+				return nil, err
+			}
+		case "ef":
+			if ef, err = parsePositiveInt(args[i+1], "EF", 1<<20); err != nil {
+				return nil, err
+			}
+		case "metric":
+			if metric = strings.ToLower(args[i+1]); !IsMetric(metric) {
+				return nil, fmt.Errorf("unknown metric %s", args[i+1])
+			}
+		case "index":
+			if index = strings.ToLower(args[i+1]); !IsIndex(index) {
+				return nil, fmt.Errorf("unknown index %s", args[i+1])
+			}
+		default:
+			return nil, fmt.Errorf("unknown option %s", args[i])
+		}
+		i++
+	}
+
+	key := keys.WriteKeys[0]
+	vs, exists, err := getVectorSet(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		vs = NewVectorSet(len(vector), metric, index, m, ef)
+	} else if (metric != "" && metric != vs.Metric()) || (index != "" && index != vs.Index()) {
+		return nil, fmt.Errorf("the vector set at key %s uses the %s metric and %s index", key, vs.Metric(), vs.Index())
+	}
+
+	added, err := vs.Add(element, vector, attributes, setAttributes)
+	if err != nil {
+		return nil, err
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{key: vs}); err != nil {
+		return nil, err
+	}
+	if added {
+		return []byte(":1\r\n"), nil
+	}
+	return []byte(":0\r\n"), nil
+}
+
+func handleVSIM(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	var query []float32
+	var element string
+	var n int
+	if strings.EqualFold(params.Command[2], "ele") {
+		element, n = params.Command[3], 2
+	} else if query, n, err = parseVector(params.Command[2:]); err != nil {
+		return nil, err
+	}
+
+	options := SearchOptions{Count: 10}
+	var withScores, withAttributes bool
+	args := params.Command[2+n:]
+	for i := 0; i < len(args); i++ {
+		option := strings.ToLower(args[i])
+		switch option {
+		case "withscores":
+			withScores = true
+			continue
+		case "withattribs":
+			withAttributes = true
+			continue
+		case "truth":
+			options.Exact = true
+			continue
+		}
+		if i+1 >= len(args) {
+			return nil, fmt.Errorf("unknown option %s", args[i])
+		}
+		switch option {
+		case "count":
+			if options.Count, err = parsePositiveInt(args[i+1], "COUNT", 1<<20); err != nil {
+				return nil, err
+			}
+		case "ef":
+			if options.EF, err = parsePositiveInt(args[i+1], "EF", 1<<20); err != nil {
+				return nil, err
+			}
+		case "filter":
+			if options.Filter, err = ParseFilter(args[i+1]); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown option %s", args[i])
+		}
+		i++
+	}
+
+	vs, exists, err := getVectorSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return []byte("*0\r\n"), nil
+	}
+	var results []Result
+	if query == nil {
+		results, err = vs.SearchElement(element, options)
+	} else {
+		results, err = vs.Search(query, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	width := 1
+	if withScores {
+		width++
+	}
+	if withAttributes {
+		width++
+	}
+	res := fmt.Sprintf("*%d\r\n", len(results)*width)
+	for _, result := range results {
+		res += encodeBulkString(result.Name)
+		if withScores {
+			res += encodeBulkString(formatFloat(result.Score))
+		}
+		if withAttributes {
+			if result.Attributes == "" {
+				res += "$-1\r\n"
+			} else {
+				res += encodeBulkString(result.Attributes)
+			}
+		}
+	}
+	return []byte(res), nil
+}
+
+func handleVREM(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+
+	key := keys.WriteKeys[0]
+	vs, exists, err := getVectorSet(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || !vs.Remove(params.Command[2]) {
+		return []byte(":0\r\n"), nil
+	}
+	// The key is deleted with its last element.
+	if vs.Card() == 0 {
+		err = params.DeleteKey(params.Context, key)
+	} else {
+		err = params.SetValues(params.Context, map[string]interface{}{key: vs})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(":1\r\n"), nil
+}
+
+func handleVCARD(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	vs, exists, err := getVectorSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return []byte(":0\r\n"), nil
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", vs.Card())), nil
+}
+
+func handleVDIM(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	key := keys.ReadKeys[0]
+	vs, exists, err := getVectorSet(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+	return []byte(fmt.Sprintf(":%d\r\n", vs.Dimension())), nil
+}
+
+func handleVEMB(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	vs, exists, err := getVectorSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if !exists || !vs.Contains(params.Command[2]) {
+		return []byte("$-1\r\n"), nil
+	}
+	vector := vs.Vector(params.Command[2])
+	res := fmt.Sprintf("*%d\r\n", len(vector))
+	for _, v := range vector {
+		res += encodeBulkString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	}
+	return []byte(res), nil
+}
+
+func handleVGETATTR(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	vs, exists, err := getVectorSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return []byte("$-1\r\n"), nil
+	}
+	attributes, ok := vs.Attributes(params.Command[2])
+	if !ok || attributes == "" {
+		return []byte("$-1\r\n"), nil
+	}
+	return []byte(encodeBulkString(attributes)), nil
+}
+
+func handleVSETATTR(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.WriteKeyFunc(4)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 4 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	attributes, err := ParseAttributes(params.Command[3])
+	if err != nil {
+		return nil, err
+	}
+
+	key := keys.WriteKeys[0]
+	vs, exists, err := getVectorSet(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || !vs.SetAttributes(params.Command[2], attributes) {
+		return []byte(":0\r\n"), nil
+	}
+	if err = params.SetValues(params.Context, map[string]interface{}{key: vs}); err != nil {
+		return nil, err
+	}
+	return []byte(":1\r\n"), nil
+}
+
+func handleVISMEMBER(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(3)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 3 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	vs, exists, err := getVectorSet(params, keys.ReadKeys[0])
+	if err != nil {
+		return nil, err
+	}
+	if !exists || !vs.Contains(params.Command[2]) {
+		return []byte(":0\r\n"), nil
+	}
+	return []byte(":1\r\n"), nil
+}
+
+// handleVINFO replies with the field names and values that describe the vector set.
+func handleVINFO(params internal.HandlerFuncParams) ([]byte, error) {
+	keys, err := internal.ReadKeyFunc(2)(params.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Command) != 2 {
+		return nil, errors.New(constants.WrongArgsResponse)
+	}
+	key := keys.ReadKeys[0]
+	vs, exists, err := getVectorSet(params, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("key %s does not exist", key)
+	}
+
+	res := "*16\r\n"
+	res += encodeBulkString("metric") + encodeBulkString(vs.Metric())
+	res += encodeBulkString("index") + encodeBulkString(vs.Index())
+	res += encodeBulkString("vector-dim") + fmt.Sprintf(":%d\r\n", vs.Dimension())
+	res += encodeBulkString("size") + fmt.Sprintf(":%d\r\n", vs.Card())
+	res += encodeBulkString("max-level") + fmt.Sprintf(":%d\r\n", vs.MaxLevel())
+	res += encodeBulkString("hnsw-m") + fmt.Sprintf(":%d\r\n", vs.M())
+	res += encodeBulkString("hnsw-ef") + fmt.Sprintf(":%d\r\n", vs.EFConstruction())
+	res += encodeBulkString("memory") + fmt.Sprintf(":%d\r\n", vs.GetMem())
+	return []byte(res), nil
+}
+
+func Commands() []internal.Command {
+	return []internal.Command{
+		{
+			Command:    "vadd",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(VADD key (FP32 vector | VALUES num value [value ...]) element [SETATTR attributes] [M numlinks] [EF ef]
+[METRIC COSINE | L2 | IP] [INDEX HNSW | FLAT])
+Adds the element with the vector to the vector set at key, or replaces its vector if it exists.
+FP32 takes the vector as a blob of little-endian float32 values. SETATTR sets the JSON object attributes of the element.
+If the key doesn't exist, the set is created with the dimension of the vector. METRIC is the similarity metric,
+COSINE by default. INDEX is HNSW for approximate search, the default, or FLAT for exact search only.
+M and EF are the number of links per node and the candidate list size used to build the HNSW graph.
+Returns 1 if the element was added and 0 if it was updated.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(5),
+			HandlerFunc:       handleVADD,
+		},
+		{
+			Command:    "vsim",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.ReadCategory, constants.SlowCategory},
+			Description: `(VSIM key (ELE element | FP32 vector | VALUES num value [value ...]) [WITHSCORES] [WITHATTRIBS]
+[COUNT count] [EF ef] [FILTER expression] [TRUTH])
+Returns the elements of the vector set at key most similar to the vector or element, from the most similar.
+COUNT is the maximum number of elements, 10 by default. EF is the candidate list size of the HNSW search.
+TRUTH scans every element for exact results. FILTER selects elements by their attributes, such as
+'.year >= 2000 and .genre == "drama"'. WITHSCORES adds the score of each element, which is the cosine similarity,
+the Euclidean distance or the inner product. WITHATTRIBS adds the attributes of each element.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(4),
+			HandlerFunc:       handleVSIM,
+		},
+		{
+			Command:    "vrem",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.WriteCategory, constants.SlowCategory},
+			Description: `(VREM key element)
+Removes the element from the vector set at key. The key is deleted with its last element.
+Returns 1 if the element was removed and 0 if it doesn't exist.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(3),
+			HandlerFunc:       handleVREM,
+		},
+		{
+			Command:    "vcard",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(VCARD key)
+Returns the number of elements in the vector set at key, or 0 if the key doesn't exist.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleVCARD,
+		},
+		{
+			Command:    "vdim",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(VDIM key)
+Returns the dimension of the vectors in the vector set at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleVDIM,
+		},
+		{
+			Command:    "vemb",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(VEMB key element)
+Returns the vector of the element in the vector set at key, or nil if it doesn't exist.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleVEMB,
+		},
+		{
+			Command:    "vgetattr",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(VGETATTR key element)
+Returns the JSON attributes of the element in the vector set at key,
+or nil if the element doesn't exist or has no attributes.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleVGETATTR,
+		},
+		{
+			Command:    "vsetattr",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.WriteCategory, constants.FastCategory},
+			Description: `(VSETATTR key element attributes)
+Replaces the JSON object attributes of the element in the vector set at key. An empty string removes them.
+Returns 1 if the attributes were set and 0 if the element doesn't exist.`,
+			Sync:              true,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.WriteKeyFunc(4),
+			HandlerFunc:       handleVSETATTR,
+		},
+		{
+			Command:    "vismember",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(VISMEMBER key element)
+Returns 1 if the element is in the vector set at key, otherwise 0.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(3),
+			HandlerFunc:       handleVISMEMBER,
+		},
+		{
+			Command:    "vinfo",
+			Module:     constants.VectorSetModule,
+			Categories: []string{constants.VectorSetCategory, constants.ReadCategory, constants.FastCategory},
+			Description: `(VINFO key)
+Returns the metric, index, dimension, size, highest HNSW level, HNSW parameters and memory usage
+of the vector set at key.`,
+			Sync:              false,
+			Type:              "BUILT_IN",
+			KeyExtractionFunc: internal.ReadKeyFunc(2),
+			HandlerFunc:       handleVINFO,
+		},
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_set_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+	"github.com/echovault/sugardb/internal/modules/vector_set"
+	"github.com/echovault/sugardb/sugardb"
+	"github.com/tidwall/resp"
+)
+
+func Test_VectorSet(t *testing.T) {
+	port, err := internal.GetFreePort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mockServer, err := sugardb.NewSugarDB(
+		sugardb.WithConfig(config.Config{
+			BindAddr:       "localhost",
+			Port:           uint16(port),
+			DataDir:        "",
+			EvictionPolicy: constants.NoEviction,
+		}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	go func() {
+		mockServer.Start()
+	}()
+
+	t.Cleanup(func() {
+		mockServer.ShutDown()
+	})
+
+	// command is a command and its expected response. Array responses are flattened and compared element by
+	// element as strings, where nil elements are empty strings and "*" matches any element.
+	type command struct {
+		command          []string
+		expectedResponse interface{}
+		expectedError    error
+	}
+
+	var flatten func(value resp.Value) []string
+	flatten = func(value resp.Value) []string {
+		if value.Type() != resp.Array {
+			return []string{value.String()}
+		}
+		var values []string
+		for _, v := range value.Array() {
+			values = append(values, flatten(v)...)
+		}
+		return values
+	}
+
+	runCommands := func(t *testing.T, client *resp.Conn, commands []command) {
+		for _, c := range commands {
+			cmd := make([]resp.Value, len(c.command))
+			for i, arg := range c.command {
+				cmd[i] = resp.StringValue(arg)
+			}
+			if err := client.WriteArray(cmd); err != nil {
+				t.Error(err)
+				return
+			}
+			res, _, err := client.ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if c.expectedError != nil {
+				if res.Error() == nil || !strings.Contains(res.Error().Error(), c.expectedError.Error()) {
+					t.Errorf("%v: expected error \"%s\", got \"%v\"", c.command, c.expectedError.Error(), res)
+				}
+				continue
+			}
+			if res.Error() != nil {
+				t.Errorf("%v: unexpected error \"%v\"", c.command, res.Error())
+				continue
+			}
+			switch expected := c.expectedResponse.(type) {
+			case int:
+				if res.Integer() != expected {
+					t.Errorf("%v: expected response %d, got \"%v\"", c.command, expected, res)
+				}
+			case string:
+				if res.String() != expected {
+					t.Errorf("%v: expected response \"%s\", got \"%s\"", c.command, expected, res.String())
+				}
+			case []string:
+				got := flatten(res)
+				matches := len(got) == len(expected)
+				for i := 0; matches && i < len(got); i++ {
+					matches = expected[i] == "*" || got[i] == expected[i]
+				}
+				if !matches {
+					t.Errorf("%v: expected response %v, got %v", c.command, expected, got)
+				}
+			}
+		}
+	}
+
+	runTests := func(t *testing.T, tests []struct {
+		name     string
+		commands []command
+	}) {
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+		client := resp.NewConn(conn)
+
+		for _, test := range tests {
+			t.Log(test.name)
+			runCommands(t, client, test.commands)
+		}
+	}
+
+	t.Run("Test_HandleVADD", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. VADD creates the vector set with the dimension of the first vector",
+				commands: []command{
+					{command: []string{"VADD", "AddKey1", "VALUES", "2", "1", "0", "a"}, expectedResponse: 1},
+					{command: []string{"VADD", "AddKey1", "VALUES", "2", "0", "1", "b", "SETATTR", `{"year":1999}`}, expectedResponse: 1},
+					{command: []string{"VADD", "AddKey1", "FP32", string(vector_set.EncodeVector([]float32{0.5, 0.25})), "c"}, expectedResponse: 1},
+					{command: []string{"VADD", "AddKey1", "VALUES", "2", "1", "0", "a"}, expectedResponse: 0},
+					{command: []string{"VCARD", "AddKey1"}, expectedResponse: 3},
+					{command: []string{"VDIM", "AddKey1"}, expectedResponse: 2},
+					{command: []string{"VEMB", "AddKey1", "c"}, expectedResponse: []string{"0.5", "0.25"}},
+					{command: []string{"VEMB", "AddKey1", "z"}, expectedResponse: []string{""}},
+					{command: []string{"VISMEMBER", "AddKey1", "b"}, expectedResponse: 1},
+					{command: []string{"VISMEMBER", "AddKey1", "z"}, expectedResponse: 0},
+					{command: []string{"VCARD", "AddMissingKey"}, expectedResponse: 0},
+					{
+						command: []string{"VINFO", "AddKey1"},
+						expectedResponse: []string{
+							"metric", "cosine", "index", "hnsw", "vector-dim", "2", "size", "3", "max-level", "*",
+							"hnsw-m", "16", "hnsw-ef", "200", "memory", "*",
+						},
+					},
+				},
+			},
+			{
+				name: "2. VADD replaces the vector of an existing element and keeps its attributes",
+				commands: []command{
+					{command: []string{"VADD", "AddKey1", "VALUES", "2", "0", "-1", "b"}, expectedResponse: 0},
+					{command: []string{"VEMB", "AddKey1", "b"}, expectedResponse: []string{"0", "-1"}},
+					{command: []string{"VGETATTR", "AddKey1", "b"}, expectedResponse: `{"year":1999}`},
+					{command: []string{"VADD", "AddKey1", "VALUES", "2", "0", "-1", "b", "SETATTR", ""}, expectedResponse: 0},
+					{command: []string{"VGETATTR", "AddKey1", "b"}, expectedResponse: ""},
+				},
+			},
+			{
+				name: "3. Return errors for invalid vectors, options and values of other types",
+				commands: []command{
+					{
+						command:       []string{"VADD", "AddKey1", "VALUES", "3", "1", "0", "0", "d"},
+						expectedError: errors.New("vector has 3 dimensions, but the vector set has 2"),
+					},
+					{command: []string{"VADD", "AddKey1", "FP32", "abc", "d"}, expectedError: errors.New("FP32 blob length must be a multiple of 4")},
+					{command: []string{"VADD", "AddKey1", "VALUES", "2", "1", "x", "d"}, expectedError: errors.New("invalid vector value x")},
+					{command: []string{"VADD", "AddKey1", "VALUES", "2", "1", "nan", "d"}, expectedError: errors.New("vector values must be finite numbers")},
+					{command: []string{"VADD", "AddKey1", "VALUES", "3", "1", "0"}, expectedError: errors.New(constants.WrongArgsResponse)},
+					{command: []string{"VADD", "AddKey1", "1", "0", "d"}, expectedError: errors.New("the vector must be given as FP32 blob or VALUES")},
+					{
+						command:       []string{"VADD", "AddKey1", "VALUES", "2", "1", "0", "d", "METRIC", "L2"},
+						expectedError: errors.New("the vector set at key AddKey1 uses the cosine metric and hnsw index"),
+					},
+					{command: []string{"VADD", "AddKey2", "VALUES", "1", "1", "d", "METRIC", "HAMMING"}, expectedError: errors.New("unknown metric HAMMING")},
+					{command: []string{"VADD", "AddKey2", "VALUES", "1", "1", "d", "M", "0"}, expectedError: errors.New("M must be an integer between 1 and 512")},
+					{command: []string{"VADD", "AddKey2", "VALUES", "1", "1", "d", "SETATTR", "[1]"}, expectedError: errors.New("attributes must be a JSON object")},
+					{command: []string{"VADD", "AddKey2", "VALUES", "1", "1", "d", "NOQUANT"}, expectedError: errors.New("unknown option NOQUANT")},
+					{command: []string{"SET", "AddStringKey", "value"}, expectedResponse: "OK"},
+					{
+						command:       []string{"VADD", "AddStringKey", "VALUES", "1", "1", "d"},
+						expectedError: errors.New("value at key AddStringKey is not a vector set"),
+					},
+					{command: []string{"VDIM", "AddMissingKey"}, expectedError: errors.New("key AddMissingKey does not exist")},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleVSIM", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. VSIM returns the most similar elements by cosine similarity",
+				commands: []command{
+					{command: []string{"VADD", "SimKey1", "VALUES", "2", "1", "0", "a"}, expectedResponse: 1},
+					{command: []string{"VADD", "SimKey1", "VALUES", "2", "0", "1", "b", "SETATTR", `{"year":1999,"genre":"drama"}`}, expectedResponse: 1},
+					{command: []string{"VADD", "SimKey1", "VALUES", "2", "1", "1", "c", "SETATTR", `{"year":2005,"genre":"comedy"}`}, expectedResponse: 1},
+					{
+						command:          []string{"VADD", "SimKey1", "VALUES", "2", "-1", "0", "d", "SETATTR", `{"year":2010,"genre":"drama","tags":["x"]}`},
+						expectedResponse: 1,
+					},
+					{command: []string{"VADD", "SimKey1", "VALUES", "2", "2", "1", "e"}, expectedResponse: 1},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "COUNT", "3"}, expectedResponse: []string{"a", "e", "c"}},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "WITHSCORES", "COUNT", "1"}, expectedResponse: []string{"a", "1"}},
+					{command: []string{"VSIM", "SimKey1", "ELE", "b", "COUNT", "2"}, expectedResponse: []string{"b", "c"}},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "TRUTH"}, expectedResponse: []string{"a", "e", "c", "b", "d"}},
+					{command: []string{"VSIM", "SimMissingKey", "VALUES", "2", "1", "0"}, expectedResponse: []string{}},
+				},
+			},
+			{
+				name: "2. FILTER selects the elements by their attributes",
+				commands: []command{
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", `.genre == "drama"`}, expectedResponse: []string{"b", "d"}},
+					{
+						command:          []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "WITHATTRIBS", "FILTER", ".year > 2000"},
+						expectedResponse: []string{"c", `{"year":2005,"genre":"comedy"}`, "d", `{"year":2010,"genre":"drama","tags":["x"]}`},
+					},
+					{
+						command:          []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", `.genre == 'drama' && .year >= 2000`},
+						expectedResponse: []string{"d"},
+					},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", `"x" in .tags`}, expectedResponse: []string{"d"}},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", `.genre in ["comedy", "horror"]`}, expectedResponse: []string{"c"}},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", "not .year"}, expectedResponse: []string{"a", "e"}},
+					{
+						command:          []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", "(.year < 2000 or .year > 2008) and !(.genre == 'comedy')"},
+						expectedResponse: []string{"b", "d"},
+					},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", ".year >"}, expectedError: errors.New("unexpected end of filter")},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "FILTER", ".year = 1"}, expectedError: errors.New("unknown operator = in filter")},
+				},
+			},
+			{
+				name: "3. VSIM supports the L2 and inner product metrics and flat indexes",
+				commands: []command{
+					{command: []string{"VADD", "SimKey2", "VALUES", "2", "0", "0", "o", "METRIC", "L2", "INDEX", "FLAT"}, expectedResponse: 1},
+					{command: []string{"VADD", "SimKey2", "VALUES", "2", "3", "4", "p"}, expectedResponse: 1},
+					{command: []string{"VADD", "SimKey2", "VALUES", "2", "1", "0", "q"}, expectedResponse: 1},
+					{command: []string{"VSIM", "SimKey2", "VALUES", "2", "0", "0", "WITHSCORES"}, expectedResponse: []string{"o", "0", "q", "1", "p", "5"}},
+					{
+						command: []string{"VINFO", "SimKey2"},
+						expectedResponse: []string{
+							"metric", "l2", "index", "flat", "vector-dim", "2", "size", "3", "max-level", "-1",
+							"hnsw-m", "16", "hnsw-ef", "200", "memory", "*",
+						},
+					},
+					{command: []string{"VADD", "SimKey3", "VALUES", "2", "1", "2", "x", "METRIC", "IP"}, expectedResponse: 1},
+					{command: []string{"VADD", "SimKey3", "VALUES", "2", "3", "1", "y"}, expectedResponse: 1},
+					{command: []string{"VSIM", "SimKey3", "VALUES", "2", "1", "1", "WITHSCORES"}, expectedResponse: []string{"y", "4", "x", "3"}},
+				},
+			},
+			{
+				name: "4. Return errors for invalid queries",
+				commands: []command{
+					{command: []string{"VSIM", "SimKey1", "ELE", "z"}, expectedError: errors.New("element z does not exist")},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "1", "1"}, expectedError: errors.New("query has 1 dimensions, but the vector set has 2")},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "COUNT", "0"}, expectedError: errors.New("COUNT must be an integer between")},
+					{command: []string{"VSIM", "SimKey1", "VALUES", "2", "1", "0", "LIMIT", "1"}, expectedError: errors.New("unknown option LIMIT")},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleHNSW", func(t *testing.T) {
+		t.Parallel()
+
+		// A 15x15 grid of points, added in an order that isn't sorted by position.
+		var commands []command
+		for i := 0; i < 225; i++ {
+			n := (i * 97) % 225
+			x, y := n/15, n%15
+			commands = append(commands, command{
+				command: []string{
+					"VADD", "HNSWKey1", "VALUES", "2", fmt.Sprint(x), fmt.Sprint(y), fmt.Sprintf("%d:%d", x, y),
+					"SETATTR", fmt.Sprintf(`{"x":%d,"y":%d}`, x, y), "METRIC", "L2", "M", "4", "EF", "32",
+				},
+				expectedResponse: 1,
+			})
+		}
+		// Removing the points in order of name repeatedly removes the entry of the graph.
+		var removals []command
+		for x := 0; x <= 12; x++ {
+			for y := 0; y < 15; y++ {
+				removed := 1
+				if (x == 7 || x == 8) && y == 7 {
+					removed = 0
+				}
+				removals = append(removals, command{command: []string{"VREM", "HNSWKey1", fmt.Sprintf("%d:%d", x, y)}, expectedResponse: removed})
+			}
+		}
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name:     "1. Add the points of a grid",
+				commands: commands,
+			},
+			{
+				name: "2. HNSW search finds the nearest points",
+				commands: []command{
+					{command: []string{"VSIM", "HNSWKey1", "VALUES", "2", "7.2", "7.1", "COUNT", "3"}, expectedResponse: []string{"7:7", "8:7", "7:8"}},
+					{command: []string{"VSIM", "HNSWKey1", "ELE", "0:0", "COUNT", "3", "EF", "10"}, expectedResponse: []string{"0:0", "0:1", "1:0"}},
+					{
+						command:          []string{"VSIM", "HNSWKey1", "VALUES", "2", "7.2", "7.1", "COUNT", "2", "FILTER", ".x < 3"},
+						expectedResponse: []string{"2:7", "2:8"},
+					},
+				},
+			},
+			{
+				name: "3. HNSW search still finds the nearest points after elements are removed",
+				commands: []command{
+					{command: []string{"VREM", "HNSWKey1", "7:7"}, expectedResponse: 1},
+					{command: []string{"VREM", "HNSWKey1", "7:7"}, expectedResponse: 0},
+					{command: []string{"VREM", "HNSWKey1", "8:7"}, expectedResponse: 1},
+					{command: []string{"VSIM", "HNSWKey1", "VALUES", "2", "7.2", "7.1", "COUNT", "2"}, expectedResponse: []string{"7:8", "7:6"}},
+					{command: []string{"VCARD", "HNSWKey1"}, expectedResponse: 223},
+				},
+			},
+			{
+				name:     "4. Remove most of the points",
+				commands: removals,
+			},
+			{
+				name: "5. HNSW search finds the nearest remaining points",
+				commands: []command{
+					{command: []string{"VSIM", "HNSWKey1", "VALUES", "2", "7.2", "7.1", "COUNT", "2"}, expectedResponse: []string{"13:7", "13:8"}},
+					{command: []string{"VSIM", "HNSWKey1", "ELE", "14:14", "COUNT", "3"}, expectedResponse: []string{"14:14", "13:14", "14:13"}},
+					{command: []string{"VCARD", "HNSWKey1"}, expectedResponse: 30},
+				},
+			},
+		})
+	})
+
+	t.Run("Test_HandleAttributesAndVREM", func(t *testing.T) {
+		t.Parallel()
+		runTests(t, []struct {
+			name     string
+			commands []command
+		}{
+			{
+				name: "1. VSETATTR and VGETATTR replace and return the attributes of an element",
+				commands: []command{
+					{command: []string{"VADD", "AttrKey1", "VALUES", "1", "1", "a", "SETATTR", `{"n":1}`}, expectedResponse: 1},
+					{command: []string{"VGETATTR", "AttrKey1", "a"}, expectedResponse: `{"n":1}`},
+					{command: []string{"VSETATTR", "AttrKey1", "a", `{"n": 2, "s": "x"}`}, expectedResponse: 1},
+					{command: []string{"VGETATTR", "AttrKey1", "a"}, expectedResponse: `{"n":2,"s":"x"}`},
+					{command: []string{"VSETATTR", "AttrKey1", "a", ""}, expectedResponse: 1},
+					{command: []string{"VGETATTR", "AttrKey1", "a"}, expectedResponse: ""},
+					{command: []string{"VSETATTR", "AttrKey1", "z", `{}`}, expectedResponse: 0},
+					{command: []string{"VSETATTR", "AttrKey1", "a", `{"n":`}, expectedError: errors.New("invalid attributes")},
+					{command: []string{"VGETATTR", "AttrMissingKey", "a"}, expectedResponse: ""},
+				},
+			},
+			{
+				name: "2. VREM deletes the key with its last element",
+				commands: []command{
+					{command: []string{"VADD", "AttrKey1", "VALUES", "1", "2", "b"}, expectedResponse: 1},
+					{command: []string{"VREM", "AttrKey1", "a"}, expectedResponse: 1},
+					{command: []string{"EXISTS", "AttrKey1"}, expectedResponse: 1},
+					{command: []string{"VREM", "AttrKey1", "b"}, expectedResponse: 1},
+					{command: []string{"EXISTS", "AttrKey1"}, expectedResponse: 0},
+					{command: []string{"VREM", "AttrKey1", "b"}, expectedResponse: 0},
+					{command: []string{"VREM", "AttrKey1"}, expectedError: errors.New(constants.WrongArgsResponse)},
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,476 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_set
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+)
+
+// Filter is a boolean expression over the attributes of an element. Expressions compare the fields of the
+// attributes, written as .field or .field.subfield, with numbers, strings, true, false and null, or with
+// other fields. The operators are ==, !=, >, >=, <, <=, in, and, or and not, with && || and ! as aliases,
+// and parentheses group expressions. The in operator matches a value in a list such as [1, 2] or a substring
+// of a string. Comparisons with a missing field are false.
+type Filter struct {
+	expression string
+	root       expr
+}
+
+// missing is the value of a field that the attributes don't have.
+type missing struct{}
+
+type expr interface {
+	eval(attributes interface{}) interface{}
+}
+
+type literal struct {
+	value interface{}
+}
+
+type field struct {
+	path []string
+}
+
+type list struct {
+	elements []expr
+}
+
+type unaryOp struct {
+	operand expr
+}
+
+type binaryOp struct {
+	op          string
+	left, right expr
+}
+
+func (e literal) eval(interface{}) interface{} {
+	return e.value
+}
+
+func (e field) eval(attributes interface{}) interface{} {
+	value := attributes
+	for _, name := range e.path {
+		object, ok := value.(*jsondoc.Object)
+		if !ok {
+			return missing{}
+		}
+		if value, ok = object.Get(name); !ok {
+			return missing{}
+		}
+	}
+	return normalize(value)
+}
+
+// normalize converts the integers of attributes to floats and arrays to slices, so that they compare with
+// the values of expressions.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return float64(v)
+	case *jsondoc.Array:
+		values := make([]interface{}, v.Len())
+		for i, element := range v.Elements() {
+			values[i] = normalize(element)
+		}
+		return values
+	}
+	return value
+}
+
+func (e list) eval(attributes interface{}) interface{} {
+	values := make([]interface{}, len(e.elements))
+	for i, element := range e.elements {
+		values[i] = element.eval(attributes)
+	}
+	return values
+}
+
+func (e unaryOp) eval(attributes interface{}) interface{} {
+	return !truthy(e.operand.eval(attributes))
+}
+
+func (e binaryOp) eval(attributes interface{}) interface{} {
+	switch e.op {
+	case "and":
+		return truthy(e.left.eval(attributes)) && truthy(e.right.eval(attributes))
+	case "or":
+		return truthy(e.left.eval(attributes)) || truthy(e.right.eval(attributes))
+	}
+
+	left, right := e.left.eval(attributes), e.right.eval(attributes)
+	if _, ok := left.(missing); ok {
+		return false
+	}
+	if _, ok := right.(missing); ok {
+		return false
+	}
+	switch e.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "in":
+		switch r := right.(type) {
+		case []interface{}:
+			for _, value := range r {
+				if equal(left, value) {
+					return true
+				}
+			}
+		case string:
+			l, ok := left.(string)
+			return ok && strings.Contains(r, l)
+		}
+		return false
+	}
+
+	var c int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false
+		}
+		c = compareFloats(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false
+		}
+		c = strings.Compare(l, r)
+	default:
+		return false
+	}
+	switch e.op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func equal(a, b interface{}) bool {
+	switch a.(type) {
+	case float64, string, bool, nil:
+		return a == b
+	}
+	return false
+}
+
+// truthy returns false for false, 0, empty strings, null and missing fields.
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case nil, missing:
+		return false
+	}
+	return true
+}
+
+// ParseFilter parses a filter expression.
+func ParseFilter(expression string) (*Filter, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s in filter", p.tokens[p.pos].text)
+	}
+	return &Filter{expression: expression, root: root}, nil
+}
+
+func (f *Filter) String() string {
+	return f.expression
+}
+
+// Matches returns true if the attributes match the filter. Elements without attributes only match
+// filters that don't need a field, such as not .field.
+func (f *Filter) Matches(attributes *jsondoc.Document) bool {
+	var root interface{}
+	if attributes != nil {
+		root = attributes.Root()
+	}
+	return truthy(f.root.eval(root))
+}
+
+type tokenKind int
+
+const (
+	tokenOperator tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenField
+	tokenWord
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value interface{}
+}
+
+var operatorAliases = map[string]string{"&&": "and", "||": "or", "!": "not"}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("()[],", r):
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r)})
+			i++
+		case strings.ContainsRune("=!<>&|", r):
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune("=&|", runes[j]) {
+				j++
+			}
+			text := string(runes[i:j])
+			switch text {
+			case "==", "!=", ">", ">=", "<", "<=", "&&", "||", "!":
+			default:
+				return nil, fmt.Errorf("unknown operator %s in filter", text)
+			}
+			if alias, ok := operatorAliases[text]; ok {
+				text = alias
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: text})
+			i = j
+		case r == '"' || r == '\'':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i : j+1]), value: b.String()})
+			i = j + 1
+		case r == '.':
+			j := i
+			var path []string
+			for j < len(runes) && runes[j] == '.' {
+				k := j + 1
+				for k < len(runes) && (unicode.IsLetter(runes[k]) || unicode.IsDigit(runes[k]) || runes[k] == '_') {
+					k++
+				}
+				if k == j+1 {
+					return nil, fmt.Errorf("invalid field in filter at position %d", j)
+				}
+				path = append(path, string(runes[j+1:k]))
+				j = k
+			}
+			tokens = append(tokens, token{kind: tokenField, text: string(runes[i:j]), value: path})
+			i = j
+		case unicode.IsDigit(r) || r == '-' || r == '+':
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || strings.ContainsRune(".eE+-", runes[j])) {
+				j++
+			}
+			n, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %s in filter", string(runes[i:j]))
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[i:j]), value: n})
+			i = j
+		case unicode.IsLetter(r):
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: strings.ToLower(string(runes[i:j]))})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %c in filter", r)
+		}
+	}
+	return tokens, nil
+}
+
+// parser parses the tokens of a filter. From the lowest precedence, the operators are or, and, not and the
+// comparison operators.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// accept consumes the next token if it's one of the operators or words and returns its text.
+func (p *parser) accept(texts ...string) (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	t := p.tokens[p.pos]
+	if t.kind != tokenOperator && t.kind != tokenWord {
+		return "", false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryOp{op: "or", left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryOp{op: "and", left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if _, ok := p.accept("not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryOp{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", ">", ">=", "<", "<=", "in")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return binaryOp{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of filter")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokenNumber, tokenString:
+		return literal{value: t.value}, nil
+	case tokenField:
+		return field{path: t.value.([]string)}, nil
+	case tokenWord:
+		switch t.text {
+		case "true":
+			return literal{value: true}, nil
+		case "false":
+			return literal{value: false}, nil
+		case "null":
+			return literal{value: nil}, nil
+		}
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			e, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("missing ) in filter")
+			}
+			return e, nil
+		case "[":
+			var elements []expr
+			if _, ok := p.accept("]"); ok {
+				return list{}, nil
+			}
+			for {
+				element, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+				if _, ok := p.accept("]"); ok {
+					return list{elements: elements}, nil
+				}
+				if _, ok := p.accept(","); !ok {
+					return nil, fmt.Errorf("missing ] in filter")
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s in filter", t.text)
+}
@@ -0,0 +1,269 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector_set
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// maxLevel bounds the level of a node in the HNSW graph.
+const maxLevel = 16
+
+type candidate struct {
+	node     *node
+	distance float64
+}
+
+// compareCandidates orders candidates from the closest, breaking ties by name so that results are stable.
+func compareCandidates(a, b candidate) int {
+	if c := cmp.Compare(a.distance, b.distance); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.node.name, b.node.name)
+}
+
+// candidateHeap is a heap of candidates ordered from the closest, or from the furthest if furthest is true.
+type candidateHeap struct {
+	candidates []candidate
+	furthest   bool
+}
+
+func (h *candidateHeap) Len() int {
+	return len(h.candidates)
+}
+
+func (h *candidateHeap) Less(i, j int) bool {
+	if h.furthest {
+		return compareCandidates(h.candidates[i], h.candidates[j]) > 0
+	}
+	return compareCandidates(h.candidates[i], h.candidates[j]) < 0
+}
+
+func (h *candidateHeap) Swap(i, j int) {
+	h.candidates[i], h.candidates[j] = h.candidates[j], h.candidates[i]
+}
+
+func (h *candidateHeap) Push(x any) {
+	h.candidates = append(h.candidates, x.(candidate))
+}
+
+func (h *candidateHeap) Pop() any {
+	c := h.candidates[len(h.candidates)-1]
+	h.candidates = h.candidates[:len(h.candidates)-1]
+	return c
+}
+
+func (h *candidateHeap) peek() candidate {
+	return h.candidates[0]
+}
+
+// randomLevel returns the level of a new node, which decays exponentially with a factor of 1/ln(m).
+func randomLevel(m int) int {
+	level := int(-math.Log(1-rand.Float64()) / math.Log(float64(m)))
+	return min(level, maxLevel)
+}
+
+// maxLinks returns the maximum number of neighbours of a node at the level. The bottom level is denser.
+func (vs *VectorSet) maxLinks(level int) int {
+	if level == 0 {
+		return vs.m * 2
+	}
+	return vs.m
+}
+
+// insert links the node into the HNSW graph at every level up to the given level.
+func (vs *VectorSet) insert(n *node, level int) {
+	n.links = make([][]*node, level+1)
+	n.linkedBy = make([]map[*node]struct{}, level+1)
+	if vs.entry == nil {
+		vs.entry, vs.maxLevel = n, level
+		return
+	}
+
+	entry := candidate{node: vs.entry, distance: vs.distance(n, vs.entry)}
+	for l := vs.maxLevel; l > level; l-- {
+		entry = vs.greedy(n, entry, l)
+	}
+	entries := []candidate{entry}
+	for l := min(level, vs.maxLevel); l >= 0; l-- {
+		candidates, _ := vs.searchLayer(n, entries, vs.efConstruction, l)
+		neighbours := candidates[:min(len(candidates), vs.m)]
+		n.links[l] = make([]*node, 0, len(neighbours))
+		for _, c := range neighbours {
+			addLink(n, c.node, l)
+			vs.link(c.node, n, l)
+		}
+		entries = candidates
+	}
+
+	if level > vs.maxLevel {
+		vs.entry, vs.maxLevel = n, level
+	}
+}
+
+// link adds a link from the node to the neighbour at the level. If the node has too many links, the
+// furthest is dropped.
+func (vs *VectorSet) link(n *node, neighbour *node, level int) {
+	if slices.Contains(n.links[level], neighbour) {
+		return
+	}
+	addLink(n, neighbour, level)
+	if len(n.links[level]) <= vs.maxLinks(level) {
+		return
+	}
+	candidates := make([]candidate, len(n.links[level]))
+	for i, link := range n.links[level] {
+		candidates[i] = candidate{node: link, distance: vs.distance(n, link)}
+	}
+	slices.SortFunc(candidates, compareCandidates)
+	n.links[level] = n.links[level][:0]
+	for i, c := range candidates {
+		if i < vs.maxLinks(level) {
+			n.links[level] = append(n.links[level], c.node)
+		} else {
+			delete(c.node.linkedBy[level], n)
+		}
+	}
+}
+
+// addLink appends a link from the node to the neighbour at the level, and records it in the neighbour's
+// reverse links.
+func addLink(n *node, neighbour *node, level int) {
+	n.links[level] = append(n.links[level], neighbour)
+	if neighbour.linkedBy[level] == nil {
+		neighbour.linkedBy[level] = make(map[*node]struct{})
+	}
+	neighbour.linkedBy[level][n] = struct{}{}
+}
+
+// greedy moves from the entry to the closest neighbour at the level until no neighbour is closer.
+func (vs *VectorSet) greedy(q *node, entry candidate, level int) candidate {
+	for changed := true; changed; {
+		changed = false
+		for _, neighbour := range entry.node.links[level] {
+			if d := vs.distance(q, neighbour); d < entry.distance {
+				entry, changed = candidate{node: neighbour, distance: d}, true
+			}
+		}
+	}
+	return entry
+}
+
+// searchLayer returns up to ef of the closest nodes to the query at the level, from the closest, and the
+// number of nodes visited.
+func (vs *VectorSet) searchLayer(q *node, entries []candidate, ef int, level int) ([]candidate, int) {
+	visited := make(map[*node]bool, ef*2)
+	pending := &candidateHeap{}
+	results := &candidateHeap{furthest: true}
+	for _, entry := range entries {
+		if visited[entry.node] {
+			continue
+		}
+		visited[entry.node] = true
+		heap.Push(pending, entry)
+		heap.Push(results, entry)
+		if results.Len() > ef {
+			heap.Pop(results)
+		}
+	}
+
+	for pending.Len() > 0 {
+		current := heap.Pop(pending).(candidate)
+		if results.Len() >= ef && current.distance > results.peek().distance {
+			break
+		}
+		for _, neighbour := range current.node.links[level] {
+			if visited[neighbour] {
+				continue
+			}
+			visited[neighbour] = true
+			c := candidate{node: neighbour, distance: vs.distance(q, neighbour)}
+			if results.Len() < ef || c.distance < results.peek().distance {
+				heap.Push(pending, c)
+				heap.Push(results, c)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(results.candidates, compareCandidates)
+	return results.candidates, len(visited)
+}
+
+// searchGraph returns up to ef of the closest nodes to the query, from the closest, and the number of nodes
+// visited at the bottom level.
+func (vs *VectorSet) searchGraph(q *node, ef int) ([]candidate, int) {
+	entry := candidate{node: vs.entry, distance: vs.distance(q, vs.entry)}
+	for l := vs.maxLevel; l > 0; l-- {
+		entry = vs.greedy(q, entry, l)
+	}
+	return vs.searchLayer(q, []candidate{entry}, ef, 0)
+}
+
+// unlink removes the node from the HNSW graph. Nodes that linked to it are linked to its closest neighbours
+// instead, so that the graph stays connected. Only the node's neighbours and the nodes that link to it are
+// visited.
+func (vs *VectorSet) unlink(n *node) {
+	for l := range n.links {
+		for _, neighbour := range n.links[l] {
+			delete(neighbour.linkedBy[l], n)
+		}
+		// Visit the nodes in order of name, so that the graph doesn't depend on the order of the map.
+		others := make([]*node, 0, len(n.linkedBy[l]))
+		for other := range n.linkedBy[l] {
+			others = append(others, other)
+		}
+		slices.SortFunc(others, func(a, b *node) int {
+			return cmp.Compare(a.name, b.name)
+		})
+		for _, other := range others {
+			i := slices.Index(other.links[l], n)
+			other.links[l] = slices.Delete(other.links[l], i, i+1)
+			for _, neighbour := range n.links[l] {
+				if neighbour != other && neighbour != n {
+					vs.link(other, neighbour, l)
+				}
+			}
+		}
+	}
+
+	if vs.entry != n {
+		return
+	}
+	// The new entry is the neighbour with the highest level. The node only has no neighbours when it was the
+	// last one, or when every other node was left without links to it, in which case every node is scanned.
+	vs.entry, vs.maxLevel = nil, 0
+	candidates := slices.Concat(n.links...)
+	if len(candidates) == 0 {
+		for _, other := range vs.nodes {
+			candidates = append(candidates, other)
+		}
+	}
+	for _, other := range candidates {
+		if other == n {
+			continue
+		}
+		if vs.entry == nil || len(other.links)-1 > vs.maxLevel ||
+			(len(other.links)-1 == vs.maxLevel && other.name < vs.entry.name) {
+			vs.entry, vs.maxLevel = other, len(other.links)-1
+		}
+	}
+}
@@ -0,0 +1,514 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vector_set implements the vector set type. A vector set holds named float32 vectors of the same
+// dimension with optional JSON attributes, and finds the elements most similar to a query vector, either
+// exactly or approximately with an HNSW graph.
+package vector_set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+)
+
+const (
+	MetricCosine = "cosine"
+	MetricL2     = "l2"
+	MetricIP     = "ip"
+)
+
+const (
+	IndexHNSW = "hnsw"
+	IndexFlat = "flat"
+)
+
+const (
+	DefaultM              = 16
+	DefaultEFConstruction = 200
+	DefaultEFSearch       = 100
+	MaxM                  = 512
+)
+
+func IsMetric(metric string) bool {
+	return metric == MetricCosine || metric == MetricL2 || metric == MetricIP
+}
+
+func IsIndex(index string) bool {
+	return index == IndexHNSW || index == IndexFlat
+}
+
+// node is an element of the set. links holds the neighbours of the node at each level of the HNSW graph,
+// and linkedBy the nodes that link to it, so that removing the node doesn't scan the set. Both are nil in a
+// flat index.
+type node struct {
+	name       string
+	vector     []float32
+	norm       float32
+	attributes *jsondoc.Document
+	links      [][]*node
+	linkedBy   []map[*node]struct{}
+}
+
+type VectorSet struct {
+	dimension      int
+	metric         string
+	index          string
+	m              int
+	efConstruction int
+	nodes          map[string]*node
+	entry          *node
+	maxLevel       int
+}
+
+// NewVectorSet creates an empty vector set. The metric and index default to cosine and HNSW, and m and
+// efConstruction default to DefaultM and DefaultEFConstruction when they're 0.
+func NewVectorSet(dimension int, metric string, index string, m int, efConstruction int) *VectorSet {
+	if metric == "" {
+		metric = MetricCosine
+	}
+	if index == "" {
+		index = IndexHNSW
+	}
+	if m == 0 {
+		m = DefaultM
+	}
+	if efConstruction == 0 {
+		efConstruction = DefaultEFConstruction
+	}
+	return &VectorSet{
+		dimension:      dimension,
+		metric:         metric,
+		index:          index,
+		m:              m,
+		efConstruction: efConstruction,
+		nodes:          make(map[string]*node),
+	}
+}
+
+func (vs *VectorSet) GetMem() int64 {
+	size := int64(unsafe.Sizeof(*vs))
+	for name, n := range vs.nodes {
+		// The name is held by both the map and the node.
+		size += int64(unsafe.Sizeof(name)) + int64(len(name)) + int64(unsafe.Sizeof(n))
+		size += int64(unsafe.Sizeof(*n)) + int64(cap(n.vector))*4
+		if n.attributes != nil {
+			size += n.attributes.GetMem()
+		}
+		for _, links := range n.links {
+			size += int64(unsafe.Sizeof(links)) + int64(cap(links))*int64(unsafe.Sizeof(n))
+		}
+		for _, linkedBy := range n.linkedBy {
+			// Each map entry is estimated as the key and one more pointer of overhead.
+			size += int64(unsafe.Sizeof(linkedBy)) + int64(len(linkedBy))*2*int64(unsafe.Sizeof(n))
+		}
+	}
+	return size
+}
+
+// compile time interface check
+var _ constants.CompositeType = (*VectorSet)(nil)
+
+func (vs *VectorSet) Dimension() int {
+	return vs.dimension
+}
+
+func (vs *VectorSet) Metric() string {
+	return vs.metric
+}
+
+func (vs *VectorSet) Index() string {
+	return vs.index
+}
+
+func (vs *VectorSet) M() int {
+	return vs.m
+}
+
+func (vs *VectorSet) EFConstruction() int {
+	return vs.efConstruction
+}
+
+// MaxLevel returns the highest level of the HNSW graph, or -1 if the set is empty or has a flat index.
+func (vs *VectorSet) MaxLevel() int {
+	if vs.entry == nil {
+		return -1
+	}
+	return vs.maxLevel
+}
+
+func (vs *VectorSet) Card() int {
+	return len(vs.nodes)
+}
+
+func (vs *VectorSet) Contains(name string) bool {
+	_, ok := vs.nodes[name]
+	return ok
+}
+
+// Vector returns a copy of the vector of the element, or nil if it doesn't exist.
+func (vs *VectorSet) Vector(name string) []float32 {
+	n, ok := vs.nodes[name]
+	if !ok {
+		return nil
+	}
+	return slices.Clone(n.vector)
+}
+
+// Attributes returns the JSON attributes of the element, and false if the element doesn't exist.
+// Elements without attributes return an empty string.
+func (vs *VectorSet) Attributes(name string) (string, bool) {
+	n, ok := vs.nodes[name]
+	if !ok {
+		return "", false
+	}
+	if n.attributes == nil {
+		return "", true
+	}
+	return n.attributes.String(), true
+}
+
+// ParseAttributes parses the JSON attributes of an element, which must be an object. An empty string
+// returns nil, which removes the attributes.
+func ParseAttributes(text string) (*jsondoc.Document, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	root, err := jsondoc.Parse([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid attributes: %w", err)
+	}
+	if _, ok := root.(*jsondoc.Object); !ok {
+		return nil, errors.New("attributes must be a JSON object")
+	}
+	return jsondoc.NewDocument(root), nil
+}
+
+// SetAttributes replaces the attributes of the element. Returns false if the element doesn't exist.
+func (vs *VectorSet) SetAttributes(name string, attributes *jsondoc.Document) bool {
+	n, ok := vs.nodes[name]
+	if ok {
+		n.attributes = attributes
+	}
+	return ok
+}
+
+// Add adds the element, or replaces its vector if it exists. The attributes are only replaced if
+// setAttributes is true. Returns true if the element was added.
+func (vs *VectorSet) Add(name string, vector []float32, attributes *jsondoc.Document, setAttributes bool) (bool, error) {
+	if len(vector) != vs.dimension {
+		return false, fmt.Errorf("vector has %d dimensions, but the vector set has %d", len(vector), vs.dimension)
+	}
+
+	existing, exists := vs.nodes[name]
+	if exists {
+		if !setAttributes {
+			attributes = existing.attributes
+		}
+		if slices.Equal(existing.vector, vector) {
+			existing.attributes = attributes
+			return false, nil
+		}
+		vs.Remove(name)
+	}
+
+	n := &node{name: name, vector: slices.Clone(vector), norm: norm(vector), attributes: attributes}
+	vs.nodes[name] = n
+	if vs.index == IndexHNSW {
+		vs.insert(n, randomLevel(vs.m))
+	}
+	return !exists, nil
+}
+
+// Remove removes the element. Returns false if it doesn't exist.
+func (vs *VectorSet) Remove(name string) bool {
+	n, ok := vs.nodes[name]
+	if !ok {
+		return false
+	}
+	delete(vs.nodes, name)
+	if vs.index == IndexHNSW {
+		vs.unlink(n)
+	}
+	return true
+}
+
+// Result is an element found by a similarity search.
+type Result struct {
+	Name       string
+	Score      float64
+	Attributes string
+}
+
+// SearchOptions modifies a similarity search.
+//
+// Count is the maximum number of results. EF is the size of the candidate list of an HNSW search, and
+// defaults to DefaultEFSearch. Exact scans every element instead of searching the HNSW graph.
+// Filter selects the elements by their attributes.
+type SearchOptions struct {
+	Count  int
+	EF     int
+	Exact  bool
+	Filter *Filter
+}
+
+// Search returns the elements most similar to the query, from the most similar. The score of each result
+// is the cosine similarity, the Euclidean distance or the inner product, depending on the metric.
+func (vs *VectorSet) Search(query []float32, options SearchOptions) ([]Result, error) {
+	if len(query) != vs.dimension {
+		return nil, fmt.Errorf("query has %d dimensions, but the vector set has %d", len(query), vs.dimension)
+	}
+	q := &node{vector: query, norm: norm(query)}
+	matches := func(n *node) bool {
+		return options.Filter == nil || options.Filter.Matches(n.attributes)
+	}
+
+	var candidates []candidate
+	if options.Exact || vs.index == IndexFlat || vs.entry == nil {
+		candidates = make([]candidate, 0, len(vs.nodes))
+		for _, n := range vs.nodes {
+			if matches(n) {
+				candidates = append(candidates, candidate{node: n, distance: vs.distance(q, n)})
+			}
+		}
+		slices.SortFunc(candidates, compareCandidates)
+	} else {
+		ef := max(options.EF, options.Count)
+		if options.EF == 0 {
+			ef = max(DefaultEFSearch, options.Count)
+		}
+		// With a filter, the search is widened until it finds enough matches or has visited the whole graph.
+		for {
+			found, visited := vs.searchGraph(q, ef)
+			candidates = slices.DeleteFunc(found, func(c candidate) bool { return !matches(c.node) })
+			if len(candidates) >= options.Count || visited >= len(vs.nodes) || ef >= len(vs.nodes) {
+				break
+			}
+			ef *= 2
+		}
+	}
+
+	if len(candidates) > options.Count {
+		candidates = candidates[:options.Count]
+	}
+	results := make([]Result, len(candidates))
+	for i, c := range candidates {
+		results[i] = Result{Name: c.node.name, Score: vs.score(q, c.node, c.distance)}
+		if c.node.attributes != nil {
+			results[i].Attributes = c.node.attributes.String()
+		}
+	}
+	return results, nil
+}
+
+// SearchElement is like Search, but uses the vector of the element as the query.
+func (vs *VectorSet) SearchElement(name string, options SearchOptions) ([]Result, error) {
+	n, ok := vs.nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("element %s does not exist", name)
+	}
+	return vs.Search(n.vector, options)
+}
+
+// distance returns the distance between the nodes, where a lower distance is more similar.
+func (vs *VectorSet) distance(a, b *node) float64 {
+	switch vs.metric {
+	case MetricL2:
+		var sum float64
+		for i := range a.vector {
+			d := float64(a.vector[i]) - float64(b.vector[i])
+			sum += d * d
+		}
+		return sum
+	case MetricIP:
+		return -dot(a.vector, b.vector)
+	default:
+		if a.norm == 0 || b.norm == 0 {
+			return 1
+		}
+		return 1 - dot(a.vector, b.vector)/(float64(a.norm)*float64(b.norm))
+	}
+}
+
+// score converts the distance into the score returned to the client.
+func (vs *VectorSet) score(a, b *node, distance float64) float64 {
+	switch vs.metric {
+	case MetricL2:
+		return math.Sqrt(distance)
+	case MetricIP:
+		return -distance
+	default:
+		return 1 - distance
+	}
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+func norm(vector []float32) float32 {
+	return float32(math.Sqrt(dot(vector, vector)))
+}
+
+// ValidateVector returns an error if the vector is empty or has a value that is not a finite number.
+func ValidateVector(vector []float32) error {
+	if len(vector) == 0 {
+		return errors.New("vector must have at least one dimension")
+	}
+	for _, v := range vector {
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return errors.New("vector values must be finite numbers")
+		}
+	}
+	return nil
+}
+
+// EncodeVector encodes the vector as little-endian float32 values.
+func EncodeVector(vector []float32) []byte {
+	b := make([]byte, 0, len(vector)*4)
+	for _, v := range vector {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	}
+	return b
+}
+
+// DecodeVector decodes little-endian float32 values.
+func DecodeVector(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, errors.New("FP32 blob length must be a multiple of 4")
+	}
+	vector := make([]float32, len(b)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return vector, nil
+}
+
+// MarshalBinary encodes the elements and the links of the HNSW graph, so that the graph is restored as it was
+// rather than rebuilt. Nodes are written in order of name and links refer to that order.
+func (vs *VectorSet) MarshalBinary() ([]byte, error) {
+	b := binary.AppendUvarint(nil, uint64(vs.dimension))
+	b = internal.AppendBinaryString(b, vs.metric)
+	b = internal.AppendBinaryString(b, vs.index)
+	b = binary.AppendUvarint(b, uint64(vs.m))
+	b = binary.AppendUvarint(b, uint64(vs.efConstruction))
+
+	names := make([]string, 0, len(vs.nodes))
+	for name := range vs.nodes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	ids := make(map[*node]uint64, len(names))
+	for i, name := range names {
+		ids[vs.nodes[name]] = uint64(i)
+	}
+
+	b = binary.AppendUvarint(b, uint64(len(names)))
+	for _, name := range names {
+		n := vs.nodes[name]
+		b = internal.AppendBinaryString(b, n.name)
+		attributes, _ := vs.Attributes(name)
+		b = internal.AppendBinaryString(b, attributes)
+		b = append(b, EncodeVector(n.vector)...)
+		b = binary.AppendUvarint(b, uint64(len(n.links)))
+		for _, links := range n.links {
+			b = binary.AppendUvarint(b, uint64(len(links)))
+			for _, link := range links {
+				b = binary.AppendUvarint(b, ids[link])
+			}
+		}
+	}
+	if vs.entry == nil {
+		b = binary.AppendUvarint(b, 0)
+	} else {
+		b = binary.AppendUvarint(b, ids[vs.entry]+1)
+	}
+	return b, nil
+}
+
+func (vs *VectorSet) UnmarshalBinary(data []byte) error {
+	r := internal.NewBinaryReader(data)
+	set := NewVectorSet(int(r.Uvarint()), r.String(), r.String(), int(r.Uvarint()), int(r.Uvarint()))
+	if r.Err() == nil && (!IsMetric(set.metric) || !IsIndex(set.index) || set.dimension <= 0) {
+		return errors.New("invalid vector set")
+	}
+
+	invalid := errors.New("invalid vector set")
+	nodes := make([]*node, r.Count())
+	links := make([][][]uint64, len(nodes))
+	for i := range nodes {
+		n := &node{name: r.String()}
+		attributes, err := ParseAttributes(r.String())
+		if err != nil {
+			return err
+		}
+		n.attributes = attributes
+		if n.vector, err = DecodeVector(r.Bytes(set.dimension * 4)); err != nil || r.Err() != nil {
+			return errors.Join(invalid, r.Err())
+		}
+		n.norm = norm(n.vector)
+		links[i] = make([][]uint64, r.Count())
+		for level := range links[i] {
+			links[i][level] = make([]uint64, r.Count())
+			for j := range links[i][level] {
+				links[i][level][j] = r.Uvarint()
+			}
+		}
+		nodes[i] = n
+		set.nodes[n.name] = n
+	}
+	entry := r.Uvarint()
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	for i, n := range nodes {
+		if len(links[i]) > 0 {
+			n.links = make([][]*node, len(links[i]))
+			n.linkedBy = make([]map[*node]struct{}, len(links[i]))
+		}
+	}
+	for i, n := range nodes {
+		for level, ids := range links[i] {
+			n.links[level] = make([]*node, 0, len(ids))
+			for _, id := range ids {
+				if id >= uint64(len(nodes)) || level >= len(links[id]) {
+					return invalid
+				}
+				addLink(n, nodes[id], level)
+			}
+		}
+	}
+	if entry > uint64(len(nodes)) || (set.index == IndexHNSW && (entry == 0) != (len(nodes) == 0)) {
+		return invalid
+	}
+	if entry > 0 {
+		set.entry = nodes[entry-1]
+		set.maxLevel = len(set.entry.links) - 1
+	}
+	*vs = *set
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyspaceEvents is the set of keyspace notification classes enabled with the notify-keyspace-events option.
+type KeyspaceEvents int
+
+const (
+	KeyspaceEventsKeyspace  KeyspaceEvents = 1 << iota // K: Publish to the __keyspace@<db>__:<key> channels.
+	KeyspaceEventsKeyevent                             // E: Publish to the __keyevent@<db>__:<event> channels.
+	KeyspaceEventsGeneric                              // g: Type independent events such as del.
+	KeyspaceEventsString                               // $: String events.
+	KeyspaceEventsList                                 // l: List events.
+	KeyspaceEventsSet                                  // s: Set events.
+	KeyspaceEventsHash                                 // h: Hash events.
+	KeyspaceEventsSortedSet                            // z: Sorted set events.
+	KeyspaceEventsStream                               // t: Stream events.
+	KeyspaceEventsExpired                              // x: Keys deleted because their TTL expired.
+	KeyspaceEventsEvicted                              // e: Keys evicted because max-memory was reached.
+	KeyspaceEventsNew                                  // n: New keys. Not included in A.
+
+	// KeyspaceEventsAll is the A alias.
+	KeyspaceEventsAll = KeyspaceEventsGeneric | KeyspaceEventsString | KeyspaceEventsList | KeyspaceEventsSet |
+		KeyspaceEventsHash | KeyspaceEventsSortedSet | KeyspaceEventsStream | KeyspaceEventsExpired |
+		KeyspaceEventsEvicted
+)
+
+var keyspaceEventFlags = []struct {
+	flag   byte
+	events KeyspaceEvents
+}{
+	{'K', KeyspaceEventsKeyspace},
+	{'E', KeyspaceEventsKeyevent},
+	{'g', KeyspaceEventsGeneric},
+	{'$', KeyspaceEventsString},
+	{'l', KeyspaceEventsList},
+	{'s', KeyspaceEventsSet},
+	{'h', KeyspaceEventsHash},
+	{'z', KeyspaceEventsSortedSet},
+	{'t', KeyspaceEventsStream},
+	{'x', KeyspaceEventsExpired},
+	{'e', KeyspaceEventsEvicted},
+	{'n', KeyspaceEventsNew},
+}
+
+// ParseKeyspaceEvents parses the flags of the notify-keyspace-events option (e.g. "KEA" or "Elx").
+func ParseKeyspaceEvents(flags string) (KeyspaceEvents, error) {
+	var events KeyspaceEvents
+	for i := 0; i < len(flags); i++ {
+		if flags[i] == 'A' {
+			events |= KeyspaceEventsAll
+			continue
+		}
+		found := false
+		for _, f := range keyspaceEventFlags {
+			if f.flag == flags[i] {
+				events |= f.events
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid keyspace events flag '%c'", flags[i])
+		}
+	}
+	return events, nil
+}
+
+// String returns the flags of the enabled classes, using the A alias when all of its classes are enabled.
+func (events KeyspaceEvents) String() string {
+	var flags strings.Builder
+	for _, f := range keyspaceEventFlags {
+		if f.events == KeyspaceEventsGeneric && events&KeyspaceEventsAll == KeyspaceEventsAll {
+			flags.WriteByte('A')
+		}
+		if events&f.events != 0 && (f.events&KeyspaceEventsAll == 0 || events&KeyspaceEventsAll != KeyspaceEventsAll) {
+			flags.WriteByte(f.flag)
+		}
+	}
+	return flags.String()
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/codec"
 	"github.com/echovault/sugardb/internal/config"
 	"github.com/hashicorp/raft"
 	"io"
@@ -39,6 +40,8 @@ type FSMOpts struct {
 	FinishSnapshot        func()
 	SetLatestSnapshotTime func(msec int64)
 	GetHandlerFuncParams  func(ctx context.Context, cmd []string, conn *net.Conn) internal.HandlerFuncParams
+	RunTransaction        func(ctx context.Context, commands [][]string, watched map[int]map[string]uint64) ([]byte, error)
+	TouchWriteKeys        func(ctx context.Context, command internal.Command, subCommand internal.SubCommand, cmd []string)
 }
 
 type FSM struct {
@@ -70,6 +73,12 @@ func (fsm *FSM) Apply(log *raft.Log) interface{} {
 		ctx = context.WithValue(ctx, internal.ContextConnID("ConnectionID"), request.ConnectionID)
 		ctx = context.WithValue(ctx, "Protocol", request.Protocol)
 		ctx = context.WithValue(ctx, "Database", request.Database)
+		// Commands applied from the raft log must not block.
+		ctx = context.WithValue(ctx, internal.ContextNonBlocking("NonBlocking"), true)
+		// Commands are executed at the time they were proposed so that every node produces the same result.
+		if request.Time != 0 {
+			ctx = context.WithValue(ctx, internal.ContextTime("Time"), time.Unix(0, request.Time))
+		}
 
 		switch strings.ToLower(request.Type) {
 		default:
@@ -90,6 +99,20 @@ func (fsm *FSM) Apply(log *raft.Log) interface{} {
 				Response: []byte("OK"),
 			}
 
+		case "transaction":
+			// Execute all the commands of the transaction atomically, unless one of the watched keys has been modified.
+			res, err := fsm.options.RunTransaction(ctx, request.Transaction, request.Watched)
+			if err != nil {
+				return internal.ApplyResponse{
+					Error:    err,
+					Response: nil,
+				}
+			}
+			return internal.ApplyResponse{
+				Error:    nil,
+				Response: res,
+			}
+
 		case "command":
 			// Handle command
 			command, err := fsm.options.GetCommand(request.CMD[0])
@@ -114,16 +137,27 @@ func (fsm *FSM) Apply(log *raft.Log) interface{} {
 				handler = subCommand.HandlerFunc
 			}
 
+			blocked := &internal.BlockedCommand{}
+			ctx = context.WithValue(ctx, internal.ContextBlockedCommand("BlockedCommand"), blocked)
+
 			if res, err := handler(fsm.options.GetHandlerFuncParams(ctx, request.CMD, nil)); err != nil {
 				return internal.ApplyResponse{
 					Error:    err,
 					Response: nil,
 				}
 			} else {
-				return internal.ApplyResponse{
+				if internal.IsWriteCommand(command, subCommand) {
+					// Invalidate watching transactions and wake up blocked clients on every node.
+					fsm.options.TouchWriteKeys(ctx, command, subCommand, request.CMD)
+				}
+				response := internal.ApplyResponse{
 					Error:    nil,
 					Response: res,
 				}
+				if blocked.Blocked {
+					response.Blocked = blocked
+				}
+				return response
 			}
 		}
 	}
@@ -151,12 +185,8 @@ func (fsm *FSM) Restore(snapshot io.ReadCloser) error {
 		return err
 	}
 
-	data := internal.SnapshotObject{
-		State:                      make(map[int]map[string]internal.KeyData),
-		LatestSnapshotMilliseconds: 0,
-	}
-
-	if err = json.Unmarshal(b, &data); err != nil {
+	data, err := codec.DecodeSnapshot(b)
+	if err != nil {
 		log.Fatal(err)
 		return err
 	}
@@ -15,8 +15,8 @@
 package raft
 
 import (
-	"encoding/json"
 	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/codec"
 	"github.com/echovault/sugardb/internal/config"
 	"github.com/hashicorp/raft"
 	"strconv"
@@ -57,7 +57,7 @@ func (s *Snapshot) Persist(sink raft.SnapshotSink) error {
 		LatestSnapshotMilliseconds: int64(msec),
 	}
 
-	o, err := json.Marshal(snapshotObject)
+	o, err := codec.EncodeSnapshot(snapshotObject)
 
 	if err != nil {
 		_ = sink.Cancel()
@@ -43,6 +43,8 @@ type Opts struct {
 	FinishSnapshot        func()
 	SetLatestSnapshotTime func(msec int64)
 	GetHandlerFuncParams  func(ctx context.Context, cmd []string, conn *net.Conn) internal.HandlerFuncParams
+	RunTransaction        func(ctx context.Context, commands [][]string, watched map[int]map[string]uint64) ([]byte, error)
+	TouchWriteKeys        func(ctx context.Context, command internal.Command, subCommand internal.SubCommand, cmd []string)
 }
 
 type Raft struct {
@@ -127,6 +129,8 @@ func (r *Raft) RaftInit(ctx context.Context) {
 			FinishSnapshot:        r.options.FinishSnapshot,
 			SetLatestSnapshotTime: r.options.SetLatestSnapshotTime,
 			GetHandlerFuncParams:  r.options.GetHandlerFuncParams,
+			RunTransaction:        r.options.RunTransaction,
+			TouchWriteKeys:        r.options.TouchWriteKeys,
 		}),
 		logStore,
 		stableStore,
@@ -215,6 +219,17 @@ func (r *Raft) RemoveServer(meta memberlist.NodeMeta) error {
 	return nil
 }
 
+// Stats returns the statistics of the raft node, such as its state, term and log indexes.
+func (r *Raft) Stats() map[string]string {
+	return r.raft.Stats()
+}
+
+// Leader returns the address and ID of the current cluster leader. Both are empty if there's no leader.
+func (r *Raft) Leader() (string, string) {
+	addr, id := r.raft.LeaderWithID()
+	return string(addr), string(id)
+}
+
 func (r *Raft) TakeSnapshot() error {
 	return r.raft.Snapshot().Error()
 }
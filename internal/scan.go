@@ -0,0 +1,330 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// DefaultScanCount is the number of members visited by a SCAN family command when COUNT is not provided.
+const DefaultScanCount = 10
+
+// ScanPosition returns the position of a member in the cursor space of the SCAN family of commands.
+func ScanPosition(member string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	return h.Sum64()
+}
+
+const (
+	scanIndexMaxLevel    = 32
+	scanIndexProbability = 0.25
+)
+
+type scanIndexNode[K ~string] struct {
+	position uint64
+	member   K
+	forward  []*scanIndexNode[K]
+}
+
+// less reports whether the node is ordered before the provided position and member.
+// Nodes are ordered by position, and then by member for the members that share a position.
+func (node *scanIndexNode[K]) less(position uint64, member K) bool {
+	return node.position < position || (node.position == position && node.member < member)
+}
+
+// ScanIndex keeps the members of a collection ordered by their ScanPosition, so the SCAN family of commands can
+// resume from a cursor without visiting the members before it. The index is a skip list, which makes inserts and
+// deletes cost O(log n) and a scan of count members cost O(log n + count).
+//
+// The owner of the collection is responsible for keeping the index in sync with the members of the collection.
+type ScanIndex[K ~string] struct {
+	header *scanIndexNode[K]
+	length int
+	level  int
+}
+
+func NewScanIndex[K ~string]() *ScanIndex[K] {
+	return &ScanIndex[K]{
+		header: &scanIndexNode[K]{forward: make([]*scanIndexNode[K], scanIndexMaxLevel)},
+		level:  1,
+	}
+}
+
+// Len returns the number of members in the index.
+func (index *ScanIndex[K]) Len() int {
+	return index.length
+}
+
+// GetMem returns the approximate memory used by the index, excluding the strings that it shares with its owner.
+func (index *ScanIndex[K]) GetMem() int64 {
+	size := int64(unsafe.Sizeof(*index))
+	for node := index.header; node != nil; node = node.forward[0] {
+		size += int64(unsafe.Sizeof(*node))
+		size += int64(len(node.forward)) * int64(unsafe.Sizeof(node))
+	}
+	return size
+}
+
+// seek returns, for each level, the last node that is ordered before the provided position and member.
+func (index *ScanIndex[K]) seek(position uint64, member K) [scanIndexMaxLevel]*scanIndexNode[K] {
+	var update [scanIndexMaxLevel]*scanIndexNode[K]
+	node := index.header
+	for i := index.level - 1; i >= 0; i-- {
+		for node.forward[i] != nil && node.forward[i].less(position, member) {
+			node = node.forward[i]
+		}
+		update[i] = node
+	}
+	return update
+}
+
+// Insert adds the member to the index. Inserting a member that is already in the index is a no-op.
+func (index *ScanIndex[K]) Insert(member K) {
+	position := ScanPosition(string(member))
+	update := index.seek(position, member)
+	if next := update[0].forward[0]; next != nil && next.position == position && next.member == member {
+		return
+	}
+
+	level := 1
+	for level < scanIndexMaxLevel && rand.Float64() < scanIndexProbability {
+		level++
+	}
+	if level > index.level {
+		for i := index.level; i < level; i++ {
+			update[i] = index.header
+		}
+		index.level = level
+	}
+
+	node := &scanIndexNode[K]{position: position, member: member, forward: make([]*scanIndexNode[K], level)}
+	for i := 0; i < level; i++ {
+		node.forward[i] = update[i].forward[i]
+		update[i].forward[i] = node
+	}
+	index.length++
+}
+
+// Delete removes the member from the index. Deleting a member that is not in the index is a no-op.
+func (index *ScanIndex[K]) Delete(member K) {
+	position := ScanPosition(string(member))
+	update := index.seek(position, member)
+	node := update[0].forward[0]
+	if node == nil || node.position != position || node.member != member {
+		return
+	}
+	for i := 0; i < index.level && update[i].forward[i] == node; i++ {
+		update[i].forward[i] = node.forward[i]
+	}
+	for index.level > 1 && index.header.forward[index.level-1] == nil {
+		index.level--
+	}
+	index.length--
+}
+
+// Clear removes all the members from the index.
+func (index *ScanIndex[K]) Clear() {
+	*index = *NewScanIndex[K]()
+}
+
+// Scan visits up to count members of the index, starting from cursor, and returns the members along with
+// the cursor to resume the iteration from. A returned cursor of 0 means the iteration is complete.
+//
+// Members are visited in the order of their ScanPosition rather than insertion order, so a cursor remains valid
+// while the collection is modified between calls: every member that is present for the whole iteration is returned
+// exactly once, while members that are added or removed during the iteration may or may not be returned.
+// Members that share a position are always returned by the same call, so a call can return more than count members
+// when two members hash to the same position.
+func (index *ScanIndex[K]) Scan(cursor uint64, count int) (uint64, []K) {
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+
+	node := index.seek(cursor, "")[0].forward[0]
+	members := make([]K, 0, min(count, index.length))
+	var boundary uint64
+	for ; node != nil && len(members) < count; node = node.forward[0] {
+		members = append(members, node.member)
+		boundary = node.position
+	}
+	if len(members) == 0 {
+		return 0, members
+	}
+
+	for ; node != nil && node.position == boundary; node = node.forward[0] {
+		members = append(members, node.member)
+	}
+
+	if node == nil || boundary == math.MaxUint64 {
+		return 0, members
+	}
+	return boundary + 1, members
+}
+
+// ParseScanCursor parses the cursor argument of the SCAN family of commands.
+func ParseScanCursor(cursor string) (uint64, error) {
+	c, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+	return c, nil
+}
+
+// ParseScanCount parses the COUNT argument of the SCAN family of commands.
+func ParseScanCount(count string) (int, error) {
+	c, err := strconv.Atoi(count)
+	if err != nil || c < 1 {
+		return 0, errors.New("count must be a positive integer")
+	}
+	return c, nil
+}
+
+// ParseScanOptions parses the MATCH and COUNT options of the SCAN family of commands.
+func ParseScanOptions(args []string) (string, int, error) {
+	if len(args)%2 != 0 {
+		return "", 0, errors.New(constants.WrongArgsResponse)
+	}
+	match := ""
+	count := DefaultScanCount
+	for i := 0; i < len(args); i += 2 {
+		var err error
+		switch strings.ToLower(args[i]) {
+		case "match":
+			match = args[i+1]
+		case "count":
+			if count, err = ParseScanCount(args[i+1]); err != nil {
+				return "", 0, err
+			}
+		default:
+			return "", 0, fmt.Errorf("unknown option %s", args[i])
+		}
+	}
+	return match, count, nil
+}
+
+// EncodeScanResponse encodes the reply of the SCAN family of commands: the next cursor followed by the elements.
+func EncodeScanResponse(cursor uint64, elements []string) []byte {
+	c := strconv.FormatUint(cursor, 10)
+	res := fmt.Sprintf("*2\r\n$%d\r\n%s\r\n*%d\r\n", len(c), c, len(elements))
+	for _, e := range elements {
+		res += fmt.Sprintf("$%d\r\n%s\r\n", len(e), e)
+	}
+	return []byte(res)
+}
+
+// MatchPattern reports whether the string matches the Redis-style glob pattern used by KEYS and the SCAN family.
+func MatchPattern(pattern string, key string) bool {
+	/*
+		Implementation of Redis-style pattern matching
+		https://redis.io/docs/latest/commands/keys/
+	*/
+	patternLen := len(pattern)
+	keyLen := len(key) // length of the key to match
+	patternPos := 0    // position in the pattern
+	keyPos := 0        // position in the key
+
+	for patternPos < patternLen {
+		switch pattern[patternPos] {
+		case '\\': // Match characters verbatum after slash
+			if patternPos+1 < patternLen {
+				patternPos++
+				if keyPos >= keyLen || pattern[patternPos] != key[keyPos] {
+					return false
+				}
+				keyPos++
+			}
+		case '?': // Match any single character (skip key position)
+			// key position is at the end, return false
+			if keyPos >= keyLen {
+				return false
+			}
+			keyPos++
+		case '*': // Match any sequence of characters
+			// If pattern is at the end, return true
+			if patternPos+1 >= patternLen {
+				return true
+			}
+			// Use recursion to match the rest of the pattern at each position
+			for i := keyPos; i <= keyLen; i++ {
+				if MatchPattern(pattern[patternPos+1:], key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '[': // Match any character in the character class brackets []
+			// key position is at the end, return false
+			if keyPos >= keyLen {
+				return false
+			}
+			patternPos++ // skip the [ character
+			// check if character class is negated (^)
+			negate := false
+			if patternPos < patternLen && pattern[patternPos] == '^' {
+				negate = true
+				patternPos++
+			}
+
+			// look through all characters in the character class
+			matched := false
+			for patternPos < patternLen && pattern[patternPos] != ']' {
+				// if character is escaped, check the next character
+				if pattern[patternPos] == '\\' && patternPos+1 < patternLen {
+					patternPos++
+					if pattern[patternPos] == key[keyPos] {
+						matched = true
+					}
+					// if character is a range, check if the key position is within the range
+				} else if patternPos+2 < patternLen && pattern[patternPos+1] == '-' {
+					// Handle range
+					if key[keyPos] >= pattern[patternPos] && key[keyPos] <= pattern[patternPos+2] {
+						matched = true
+					}
+					patternPos += 2
+					// if character is a match, set matched to true
+				} else if pattern[patternPos] == key[keyPos] {
+					matched = true
+				}
+				patternPos++
+			}
+			// if pattern position is at the end, return false
+			if patternPos >= patternLen {
+				return false
+			}
+			// negate check: if matched is true and negate is true, return false
+			if matched == negate {
+				return false
+			}
+			keyPos++
+		default: // Match literal character (just like slash but on the current key position)
+			if keyPos >= keyLen || pattern[patternPos] != key[keyPos] {
+				return false
+			}
+			keyPos++
+		}
+		patternPos++
+	}
+
+	return keyPos == keyLen
+}
@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/clock"
+	"github.com/echovault/sugardb/internal/codec"
 	"io"
 	"io/fs"
 	"log"
 	"os"
 	"path"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -43,7 +45,9 @@ type Engine struct {
 	changeCount               atomic.Uint64
 	directory                 string
 	snapshotInterval          time.Duration
-	snapshotThreshold         uint64
+	snapshotThreshold         atomic.Uint64
+	intervalUpdates           chan time.Duration // Receives the new snapshot interval when it's changed at runtime.
+	intervalUpdatesMut        sync.Mutex
 	startSnapshotFunc         func()
 	finishSnapshotFunc        func()
 	getStateFunc              func() map[int]map[string]internal.KeyData
@@ -72,7 +76,7 @@ func WithInterval(interval time.Duration) func(engine *Engine) {
 
 func WithThreshold(threshold uint64) func(engine *Engine) {
 	return func(engine *Engine) {
-		engine.snapshotThreshold = threshold
+		engine.snapshotThreshold.Store(threshold)
 	}
 }
 
@@ -118,7 +122,7 @@ func NewSnapshotEngine(options ...func(engine *Engine)) *Engine {
 		changeCount:        atomic.Uint64{},
 		directory:          "",
 		snapshotInterval:   5 * time.Minute,
-		snapshotThreshold:  1000,
+		intervalUpdates:    make(chan time.Duration, 1),
 		startSnapshotFunc:  func() {},
 		finishSnapshotFunc: func() {},
 		getStateFunc: func() map[int]map[string]internal.KeyData {
@@ -131,30 +135,59 @@ func NewSnapshotEngine(options ...func(engine *Engine)) *Engine {
 		},
 	}
 
+	engine.snapshotThreshold.Store(1000)
+
 	for _, option := range options {
 		option(engine)
 	}
 
-	if engine.snapshotInterval != 0 {
-		go func() {
-			ticker := time.NewTicker(engine.snapshotInterval)
-			defer func() {
+	go func() {
+		// The ticker only runs while the snapshot interval is not 0.
+		ticker := time.NewTicker(time.Hour)
+		ticker.Stop()
+		if engine.snapshotInterval != 0 {
+			ticker.Reset(engine.snapshotInterval)
+		}
+		defer func() {
+			ticker.Stop()
+		}()
+		for {
+			select {
+			case interval := <-engine.intervalUpdates:
 				ticker.Stop()
-			}()
-			for {
-				<-ticker.C
-				if engine.changeCount.Load() == engine.snapshotThreshold {
+				if interval != 0 {
+					ticker.Reset(interval)
+				}
+			case <-ticker.C:
+				if engine.changeCount.Load() == engine.snapshotThreshold.Load() {
 					if err := engine.TakeSnapshot(); err != nil {
 						log.Println(err)
 					}
 				}
 			}
-		}()
-	}
+		}
+	}()
 
 	return engine
 }
 
+// SetInterval changes the interval between snapshots. An interval of 0 disables the periodic snapshots.
+func (engine *Engine) SetInterval(interval time.Duration) {
+	engine.intervalUpdatesMut.Lock()
+	defer engine.intervalUpdatesMut.Unlock()
+	// Discard a pending update that has not been picked up yet so that this call does not block.
+	select {
+	case <-engine.intervalUpdates:
+	default:
+	}
+	engine.intervalUpdates <- interval
+}
+
+// SetThreshold changes the number of changes that trigger a snapshot.
+func (engine *Engine) SetThreshold(threshold uint64) {
+	engine.snapshotThreshold.Store(threshold)
+}
+
 func (engine *Engine) TakeSnapshot() error {
 	engine.startSnapshotFunc()
 	defer engine.finishSnapshotFunc()
@@ -221,7 +254,7 @@ func (engine *Engine) TakeSnapshot() error {
 		State:                      internal.FilterExpiredKeys(engine.clock.Now(), engine.getStateFunc()),
 		LatestSnapshotMilliseconds: engine.getLatestSnapshotTimeFunc(),
 	}
-	out, err := json.Marshal(snapshotObject)
+	out, err := codec.EncodeSnapshot(snapshotObject)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -234,8 +267,8 @@ func (engine *Engine) TakeSnapshot() error {
 
 	// Update the snapshotObject
 	snapshotObject.LatestSnapshotMilliseconds = msec
-	// Marshal the updated snapshotObject
-	out, err = json.Marshal(snapshotObject)
+	// Encode the updated snapshotObject
+	out, err = codec.EncodeSnapshot(snapshotObject)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -356,8 +389,8 @@ func (engine *Engine) Restore() error {
 		return nil
 	}
 
-	snapshotObject := new(internal.SnapshotObject)
-	if err = json.Unmarshal(sd, snapshotObject); err != nil {
+	snapshotObject, err := codec.DecodeSnapshot(sd)
+	if err != nil {
 		return err
 	}
 
@@ -374,6 +407,11 @@ func (engine *Engine) Restore() error {
 	return nil
 }
 
+// ChangeCount returns the number of changes since the last snapshot.
+func (engine *Engine) ChangeCount() uint64 {
+	return engine.changeCount.Load()
+}
+
 func (engine *Engine) IncrementChangeCount() {
 	engine.changeCount.Add(1)
 }
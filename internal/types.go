@@ -71,20 +71,56 @@ func (k *KeyData) GetMem() (int64, error) {
 
 type ContextServerID string
 type ContextConnID string
+type ContextStoreLocked string
+type ContextNonBlocking string
+type ContextBlockedCommand string
+type ContextBlockingCommand string
+type ContextCommand string
+type ContextReadCommand string
+type ContextPropagatedCommand string
+type ContextTime string
+
+// BlockedCommand records that a blocking command applied from the raft log could not complete without blocking.
+// Timeout is the timeout channel the command's handler passed to the wait function of AwaitKeys.
+type BlockedCommand struct {
+	Blocked bool
+	Timeout <-chan time.Time
+}
+
+// PropagatedCommand holds the command that is logged to the AOF in place of the command that was executed.
+type PropagatedCommand struct {
+	Command []string
+}
+
+// PropagateCommand replaces the command that is logged to the AOF for the command executing with the context.
+// Commands with arguments that resolve differently each time they're executed, like the * ID of XADD, log the
+// command with the resolved arguments so that replaying the AOF produces the same result.
+func PropagateCommand(ctx context.Context, cmd []string) {
+	if propagated, ok := ctx.Value(ContextPropagatedCommand("PropagatedCommand")).(*PropagatedCommand); ok {
+		propagated.Command = cmd
+	}
+}
 
 type ApplyRequest struct {
-	Type         string   `json:"Type"` // command | delete-key
-	ServerID     string   `json:"ServerID"`
-	ConnectionID string   `json:"ConnectionID"`
-	Protocol     int      `json:"Protocol"`
-	Database     int      `json:"Database"`
-	CMD          []string `json:"CMD"`
-	Key          string   `json:"Key"` // Optional: Used with delete-key type to specify which key to delete.
+	Type         string     `json:"Type"` // command | delete-key | transaction
+	ServerID     string     `json:"ServerID"`
+	ConnectionID string     `json:"ConnectionID"`
+	Protocol     int        `json:"Protocol"`
+	Database     int        `json:"Database"`
+	CMD          []string   `json:"CMD"`
+	Key          string     `json:"Key"`         // Optional: Used with delete-key type to specify which key to delete.
+	Transaction  [][]string `json:"Transaction"` // Optional: Used with transaction type to specify the queued commands.
+	Time         int64      `json:"Time"`        // The time the request was proposed at in unix nanoseconds.
+	// Optional: Used with transaction type to specify the fingerprints of the watched keys in each database.
+	Watched map[int]map[string]uint64 `json:"Watched"`
 }
 
 type ApplyResponse struct {
 	Error    error
 	Response []byte
+	// Set when the command could not complete without blocking.
+	// The leader waits for the command's keys to be modified and applies the command again.
+	Blocked *BlockedCommand
 }
 
 type SnapshotObject struct {
@@ -104,12 +140,31 @@ type ServerInfo struct {
 	MaxMemory  uint64
 }
 
+// InfoSection is a section of the statistics reported by the INFO command.
+type InfoSection struct {
+	Name   string      // The lowercase name of the section, e.g. "server".
+	Fields []InfoField // The fields of the section, in the order they're reported.
+}
+
+type InfoField struct {
+	Name  string
+	Value string
+}
+
 // ConnectionInfo holds information about the connection
 type ConnectionInfo struct {
 	Id       uint64 // Connection id.
 	Name     string // Alias name for this connection.
 	Protocol int    // The RESP protocol used by the client. Can be either 2 or 3.
 	Database int    // Database index currently being used by the connection.
+
+	Addr            string    // The remote address of the client.
+	LocalAddr       string    // The local address the client is connected to.
+	User            string    // The ACL user the connection is authenticated as. Only set by ListClients.
+	CreatedAt       time.Time // The time the connection was established.
+	LastInteraction time.Time // The time of the connection's last command.
+	LastCommand     string    // The last command executed by the connection, e.g. "client|list".
+	NoEvict         bool      // Whether the client has been excluded from client eviction with CLIENT NO-EVICT.
 }
 
 // KeyExtractionFuncResult is the return type of the KeyExtractionFunc for the command/subcommand.
@@ -117,6 +172,9 @@ type KeyExtractionFuncResult struct {
 	Channels  []string // The pubsub channels the command accesses. For non pubsub commands, this should be an empty slice.
 	ReadKeys  []string // The keys the command reads from. If no keys are read, this should be an empty slice.
 	WriteKeys []string // The keys the command writes to. If no keys are written to, this should be an empty slice.
+	// True when the command reads keys that are only known once it executes, e.g. SORT with a BY or GET pattern.
+	// The ACL layer then requires read access to all keys.
+	AllReadKeys bool
 }
 
 // KeyExtractionFunc is included with every command/subcommand. This function returns a KeyExtractionFuncResult object.
@@ -140,12 +198,20 @@ type HandlerFuncParams struct {
 	KeysExist func(ctx context.Context, keys []string) map[string]bool
 	// GetKeys returns all the keys in the keyspace.
 	GetKeys func(ctx context.Context) []string
+	// ScanKeys visits up to count keys in the keyspace starting from cursor and returns the keys for which filter
+	// returns true, along with the cursor to resume from. A returned cursor of 0 means the iteration is complete.
+	// Expired keys are skipped. The filter is called with the store locked, so it must not modify the value.
+	ScanKeys func(ctx context.Context, cursor uint64, count int, filter func(key string, value interface{}) bool) (uint64, []string)
 	// GetExpiry returns the expiry time of a key.
 	GetExpiry func(ctx context.Context, key string) time.Time
 	// GetHashExpiry returns the expiry time of a field in a key whose value is a hash.
 	GetHashExpiry func(ctx context.Context, key string, field string) time.Time
 	// DeleteKey deletes the specified key. Returns an error if the deletion was unsuccessful.
 	DeleteKey func(ctx context.Context, key string) error
+	// ReplicateDeleteKey deletes the specified key if it still holds the value and expiry time encoded in dump by
+	// codec.EncodeDump. In cluster mode, the deletion is applied through raft so that the key is deleted on every
+	// node. Returns an error if the key was modified or if the node is not the cluster leader.
+	ReplicateDeleteKey func(ctx context.Context, key string, dump []byte) error
 	// GetValues retrieves the values from the specified keys.
 	// Non-existent keys will be nil.
 	GetValues func(ctx context.Context, keys []string) map[string]interface{}
@@ -169,6 +235,9 @@ type HandlerFuncParams struct {
 	// GetPubSub returns the SugarDB instance's PubSub engine.
 	// There's no need to use this outside of the pubsub package.
 	GetPubSub func() interface{}
+	// GetSearch returns the SugarDB instance's search engine, which holds the indexes of hashes.
+	// There's no need to use this outside of the search package.
+	GetSearch func() interface{}
 	// TakeSnapshot triggers a snapshot by the SugarDB instance.
 	TakeSnapshot func() error
 	// RewriteAOF triggers a compaction of the commands logs by the SugarDB instance.
@@ -187,8 +256,29 @@ type HandlerFuncParams struct {
 	SetConnectionInfo func(conn *net.Conn, clientname string, protocol int, database int)
 	// GetConnectionInfo returns information about the current connection.
 	GetConnectionInfo func(conn *net.Conn) ConnectionInfo
+	// ListClients returns the connection information of all the TCP clients, ordered by connection id.
+	ListClients func() []ConnectionInfo
+	// KillClients closes the connections of the TCP clients that match and returns the number of closed connections.
+	KillClients func(match func(info ConnectionInfo) bool) int
+	// SetClientName sets the name of the connection. An empty name clears the connection's name.
+	SetClientName func(conn *net.Conn, name string)
+	// SetClientNoEvict sets whether the connection is excluded from client eviction.
+	SetClientNoEvict func(conn *net.Conn, noEvict bool)
+	// PauseClients suspends the commands of all the TCP clients for the duration of the timeout.
+	// When all is false, only write commands are suspended.
+	PauseClients func(timeout time.Duration, all bool)
+	// UnpauseClients resumes the commands of the clients paused by PauseClients.
+	UnpauseClients func()
 	// GetServerInfo returns information about the server when requested by commands such as HELLO.
 	GetServerInfo func() ServerInfo
+	// GetInfo returns the sections of server statistics reported by the INFO command.
+	GetInfo func(ctx context.Context) []InfoSection
+	// GetConfig returns the values of the configuration parameters that match any of the glob patterns.
+	GetConfig func(ctx context.Context, patterns ...string) (map[string]string, error)
+	// SetConfig changes the configuration parameters at runtime. No parameter is changed if any of them is invalid.
+	SetConfig func(ctx context.Context, params map[string]string) error
+	// RewriteConfig persists the configuration parameters that can be changed at runtime to the config file.
+	RewriteConfig func(ctx context.Context) error
 	// SwapDBs swaps two databases,
 	// so that immediately all the clients connected to a given database will see the data of the other database,
 	// and the other way around.
@@ -209,12 +299,45 @@ type HandlerFuncParams struct {
 	// GetObjectIdleTime retrieves the time in seconds since the last access of a key.
 	// Can only be used with LRU type eviction policies.
 	GetObjectIdleTime func(ctx context.Context, keys string) (float64, error)
+	// SetObjectFrequency sets the access frequency count of a key. It's ignored unless the eviction policy is a type of LFU.
+	SetObjectFrequency func(ctx context.Context, key string, freq int)
+	// SetObjectIdleTime sets the time since the last access of a key.
+	// It's ignored unless the eviction policy is a type of LRU.
+	SetObjectIdleTime func(ctx context.Context, key string, idleTime time.Duration)
 	// AddScript adds a script to SugarDB that isn't associated with a command.
 	// This script is triggered using the EVAL or EVALSHA commands.
-	// engine defines the interpreter to be used. Possible values: "LUA"
+	// engine defines the interpreter to be used. Possible values: "LUA", "JS"
 	// scriptType is either "FILE" or "RAW".
 	// content contains the file path if scriptType is "FILE" and the raw script if scriptType is "RAW"
+	// The script is cached under the SHA1 digest of its source.
 	AddScript func(engine string, scriptType string, content string, args []string) error
+	// RunScript executes the cached script identified by the SHA1 digest with the provided keys and args.
+	// The script is executed atomically, no other command can modify the store while the script is running.
+	RunScript func(ctx context.Context, sha string, keys []string, args []string) ([]byte, error)
+	// ScriptExists checks whether a script with the given SHA1 digest is in the script cache.
+	ScriptExists func(sha string) bool
+	// FlushScripts removes all the scripts from the script cache.
+	FlushScripts func()
+	// StartTransaction marks the start of a transaction (MULTI) for the connection.
+	// All subsequent commands from the connection are queued until the transaction is executed or discarded.
+	StartTransaction func(conn *net.Conn) error
+	// ExecTransaction atomically executes all the commands queued by the connection since StartTransaction.
+	// Returns a null array if any of the keys watched by the connection were modified.
+	ExecTransaction func(ctx context.Context, conn *net.Conn) ([]byte, error)
+	// DiscardTransaction discards all the commands queued by the connection since StartTransaction.
+	DiscardTransaction func(conn *net.Conn) error
+	// WatchKeys marks the keys to be watched by the connection. If any of the watched keys is modified
+	// before the connection's transaction is executed, the transaction is aborted.
+	WatchKeys func(ctx context.Context, conn *net.Conn, keys []string) error
+	// UnwatchKeys removes all the keys watched by the connection.
+	UnwatchKeys func(conn *net.Conn)
+	// AwaitKeys is used by blocking commands to wait for any of the keys to be modified.
+	// Call AwaitKeys before checking the keys so that modifications made between the check and the wait are not missed.
+	// The returned wait function blocks until one of the keys is modified or the timeout channel receives.
+	// A nil timeout channel blocks indefinitely. Wait returns false if the timeout elapsed or if the command
+	// is not allowed to block, e.g. when it's executed in a transaction or script, or replayed from the AOF or raft log.
+	// The returned cancel function must be called if the wait function is not called.
+	AwaitKeys func(ctx context.Context, keys []string) (wait func(timeout <-chan time.Time) bool, cancel func())
 }
 
 // HandlerFunc is a functions described by a command where the bulk of the command handling is done.
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"math/big"
 	"net"
 	"reflect"
@@ -186,6 +187,19 @@ func ParseMemory(memory string) (uint64, error) {
 	return uint64(bytesInt), nil
 }
 
+// ParseBlockingTimeout parses the timeout of blocking commands such as BLPOP, which is given in seconds.
+// A timeout of 0 blocks indefinitely.
+func ParseBlockingTimeout(timeout string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(timeout, 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, errors.New("timeout is not a float or out of range")
+	}
+	if seconds < 0 {
+		return 0, errors.New("timeout is negative")
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
 // IsMaxMemoryExceeded checks whether we have exceeded the current maximum memory limit.
 func IsMaxMemoryExceeded(memUsed int64, maxMemory uint64) bool {
 	if maxMemory == 0 {
@@ -360,6 +374,30 @@ func ParseNestedStringArrayResponse(b []byte) ([][]string, error) {
 	return arr, nil
 }
 
+// ParseScanResponse parses the reply of the SCAN family of commands into the next cursor and the returned elements.
+func ParseScanResponse(b []byte) (uint64, []string, error) {
+	r := resp.NewReader(bytes.NewReader(b))
+	v, _, err := r.ReadValue()
+	if err != nil {
+		return 0, nil, err
+	}
+	if v.Type() == resp.Error {
+		return 0, nil, v.Error()
+	}
+	if len(v.Array()) != 2 {
+		return 0, nil, errors.New("malformed scan response")
+	}
+	cursor, err := strconv.ParseUint(v.Array()[0].String(), 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	elements := make([]string, len(v.Array()[1].Array()))
+	for i, e := range v.Array()[1].Array() {
+		elements[i] = e.String()
+	}
+	return cursor, elements, nil
+}
+
 func ParseIntegerArrayResponse(b []byte) ([]int, error) {
 	r := resp.NewReader(bytes.NewReader(b))
 	v, _, err := r.ReadValue()
@@ -519,3 +557,35 @@ func ParseInteger64ArrayResponse(b []byte) ([]int64, error) {
     }
     return arr, nil
 }
+
+// ParseAnyResponse parses a RESP response of any shape into native Go types.
+// Null responses are returned as nil, strings as string, integers as int, arrays as []interface{}
+// and error replies as error.
+func ParseAnyResponse(b []byte) (interface{}, error) {
+	r := resp.NewReader(bytes.NewReader(b))
+	v, _, err := r.ReadValue()
+	if err != nil {
+		return nil, err
+	}
+	return parseRESPValue(v), nil
+}
+
+func parseRESPValue(v resp.Value) interface{} {
+	if v.IsNull() {
+		return nil
+	}
+	switch v.Type() {
+	case resp.Integer:
+		return v.Integer()
+	case resp.Error:
+		return v.Error()
+	case resp.Array:
+		arr := make([]interface{}, len(v.Array()))
+		for i, e := range v.Array() {
+			arr[i] = parseRESPValue(e)
+		}
+		return arr
+	default:
+		return v.String()
+	}
+}
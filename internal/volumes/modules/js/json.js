@@ -0,0 +1,117 @@
+
+// The keyword to trigger the command
+var command = "JS.JSON"
+
+// The string array of categories this command belongs to.
+// This array can contain both built-in categories and new custom categories.
+var categories = ["json", "write", "fast"]
+
+// The description of the command.
+var description = "(JS.JSON key) This is an example of working with SugarDB JSON documents in js scripts."
+
+// Whether the command should be synced across the RAFT cluster.
+var sync = true
+
+/**
+ *  keyExtractionFunc is a function that extracts the keys from the command and returns them to SugarDB.keyExtractionFunc
+ *  The returned data from this function is used in the Access Control Layer to determine if the current connection is
+ *  authorized to execute this command. The function must return a table that specifies which keys in this command
+ *  are read keys and which ones are write keys.
+ *  Example return: {readKeys: ["key1", "key2"], writeKeys: ["key3", "key4", "key5"]}
+ *
+ *  1. "command" is a string array representing the command that triggered this key extraction function.
+ *
+ *  2. "args" is a string array of the modifier args that were passed when loading the module into SugarDB.
+ *  These args are passed to the key extraction function everytime it's invoked.
+ */
+function keyExtractionFunc(command, args) {
+  if (command.length !== 2) {
+    throw "wrong number of args, expected 1."
+  }
+  return {
+    "readKeys": [],
+    "writeKeys": [command[1]]
+  }
+}
+
+/**
+ * handlerFunc is the command's handler function. The function is passed some arguments that allow it to interact with
+ * SugarDB. The function must return a valid RESP response or throw an error.
+ * The handler function accepts the following args:
+ *
+ * 1. "context" is a table that contains some information about the environment this command has been executed in.
+ *     Example: {protocol: 2, database: 0}
+ *     This object contains the following properties:
+ *     i) protocol - the protocol version of the client that executed the command (either 2 or 3).
+ *     ii) database - the active database index of the client that executed the command.
+ *
+ * 2. "command" is the string array representing the command that triggered this handler function.
+ *
+ * 3. "keyExists" is a function that can be called to check if a list of keys exists in the SugarDB store database.
+ *     This function accepts a string array of keys to check and returns a table with each key having a corresponding
+ *     boolean value indicating whether it exists.
+ *     Examples:
+ *     i) Example invocation: keyExists(["key1", "key2", "key3"])
+ *     ii) Example return: {key1: true, key2: false, key3: true}
+ *
+ * 4. "getValues" is a function that can be called to retrieve values from the SugarDB store database.
+ *     The function accepts a string array of keys whose values we would like to fetch, and returns a table with each key
+ *     containing the corresponding value from the store.
+ *     The possible data types for the values are: number, string, nil, hash, set, zset, json
+ *     Examples:
+ *     i) Example invocation: getValues(["key1", "key2", "key3"])
+ *     ii) Example return: {key1: 3.142, key2: nil, key3: "Pi"}
+ *
+ * 5. "setValues" is a function that can be called to set values in the active database in the SugarDB store.
+ *     This function accepts a table with keys and the corresponding values to set for each key in the active database
+ *     in the store.
+ *     The accepted data types for the values are: number, string, nil, hash, set, zset, json.
+ *     The setValues function does not return anything.
+ *     Examples:
+ *     i) Example invocation: setValues({key1: 3.142, key2: nil, key3: "Pi"})
+ *
+ * 6. "args" is a string array of the modifier args passed to the module at load time. These args are passed to the
+ *    handler everytime it's invoked.
+ */
+function assert(condition, message) {
+  if (!condition) {
+    throw message
+  }
+}
+
+function handlerFunc(ctx, command, keysExist, getValues, setValues, args) {
+  // Initialize a new document from JSON text. Objects can also be passed, e.g. new JSONDoc({name: "sugardb"})
+  var doc = new JSONDoc('{"name":"sugardb","tags":["fast"],"stats":{"stars":1}}');
+
+  // Test get method. JSONPath queries return an array of matches, and legacy paths return the first match.
+  assert(doc.get("$.name")[0] === "sugardb", "get method failed for $.name");
+  assert(doc.get(".tags")[0] === "fast", "get method failed for .tags");
+  assert(doc.get(".missing") === undefined, "get method returned a value for a missing path");
+
+  // Test set method with nested values
+  assert(doc.set("$.tags", ["fast", "embedded"]) === 1, "set method failed for $.tags");
+  assert(doc.set("$.stats.forks", 2) === 1, "set method failed to add $.stats.forks");
+  assert(doc.set("$.meta", {license: "Apache-2.0"}) === 1, "set method failed to add $.meta");
+  assert(doc.get(".tags")[1] === "embedded", "set method did not set the array");
+  assert(doc.get(".meta.license") === "Apache-2.0", "set method did not set the object");
+
+  // Test type method
+  assert(doc.type(".stats.forks") === "integer", "type method failed for .stats.forks");
+  assert(doc.type("$.tags") === "array", "type method failed for $.tags");
+
+  // Test del method
+  assert(doc.del("$.stats.stars") === 1, "del method did not delete the correct number of values");
+
+  // Set the document in the store
+  var setVals = {}
+  setVals[command[1]] = doc
+  setValues(setVals);
+
+  // Check that the document was correctly set in the database
+  var stored = getValues([command[1]])[command[1]];
+  assert(stored.toString() === '{"name":"sugardb","tags":["fast","embedded"],"stats":{"forks":2},"meta":{"license":"Apache-2.0"}}',
+    "document not set correctly");
+
+  // Return RESP response
+  return "+OK\r\n"
+}
@@ -163,6 +163,14 @@ func TestSugarDB_ACL(t *testing.T) {
 					constants.HashCategory, constants.FastCategory, constants.KeyspaceCategory, constants.ListCategory,
 					constants.PubSubCategory, constants.ReadCategory, constants.WriteCategory, constants.SetCategory,
 					constants.SortedSetCategory, constants.SlowCategory, constants.StringCategory,
+					constants.ScriptingCategory, constants.TransactionCategory, constants.StreamCategory,
+					constants.BlockingCategory, constants.BitmapCategory, constants.HyperLogLogCategory,
+					constants.BloomCategory, constants.CuckooCategory, constants.CMSCategory, constants.TopKCategory,
+					constants.JSONCategory,
+					constants.TimeSeriesCategory,
+					constants.VectorSetCategory,
+					constants.SearchCategory,
+					constants.GeoCategory,
 				},
 				wantErr: false,
 			},
@@ -203,6 +203,111 @@ func (server *SugarDB) RewriteAOF() (string, error) {
 	return internal.ParseStringResponse(b)
 }
 
+// Info returns information and statistics about the server.
+//
+// Parameters:
+//
+// `sections` - ...string - The sections to return. The sections are server, clients, memory, persistence, stats,
+// replication, keyspace and commandstats. "default" returns all the sections except commandstats and "all" returns
+// every section. When no section is provided, the default sections are returned. Unknown sections are ignored.
+//
+// Returns: A map of the lowercase section names to the fields of each section.
+// For example, info["keyspace"]["db0"] is "keys=1,expires=0" if database 0 holds a single key without expiry.
+func (server *SugarDB) Info(sections ...string) (map[string]map[string]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append([]string{"INFO"}, sections...)), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseStringResponse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	info := make(map[string]map[string]string)
+	var section map[string]string
+	for _, line := range strings.Split(res, "\r\n") {
+		if strings.HasPrefix(line, "# ") {
+			section = make(map[string]string)
+			info[strings.ToLower(strings.TrimPrefix(line, "# "))] = section
+			continue
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok && section != nil {
+			section[name] = value
+		}
+	}
+	return info, nil
+}
+
+// ConfigGet returns the values of the configuration parameters.
+//
+// Parameters:
+//
+// `patterns` - ...string - The names of the parameters to return. Each name can be a glob pattern.
+// The parameter names are the same as the command line flags (e.g. max-memory, eviction-policy).
+//
+// Returns: A map of the matching parameter names to their values.
+func (server *SugarDB) ConfigGet(patterns ...string) (map[string]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append([]string{"CONFIG", "GET"}, patterns...)), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := internal.ParseStringArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]string, len(arr)/2)
+	for i := 0; i+1 < len(arr); i += 2 {
+		res[arr[i]] = arr[i+1]
+	}
+	return res, nil
+}
+
+// ConfigSet changes configuration parameters at runtime. The running eviction, AOF and snapshot engines
+// pick up the changes immediately.
+//
+// Parameters:
+//
+// `params` - map[string]string - The parameters to change, mapped to their new values.
+// Only max-memory, eviction-policy, eviction-sample, eviction-interval, aof-sync-strategy, snapshot-interval,
+// snapshot-threshold, require-pass, password and notify-keyspace-events can be changed.
+//
+// Returns: true if the parameters were changed.
+//
+// Errors:
+//
+// "unknown parameter <parameter>" - If the parameter does not exist.
+//
+// "parameter <parameter> cannot be changed at runtime" - If the parameter cannot be changed with ConfigSet.
+//
+// "invalid value for parameter <parameter>: <error>" - If the value is invalid. No parameter is changed in this case.
+func (server *SugarDB) ConfigSet(params map[string]string) (bool, error) {
+	cmd := []string{"CONFIG", "SET"}
+	for name, value := range params {
+		cmd = append(cmd, name, value)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	res, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(res, "ok"), err
+}
+
+// ConfigRewrite persists the configuration parameters that can be changed at runtime to the JSON or YAML
+// config file set with WithConfigFile or loaded with the --config flag. The other values in the file are preserved.
+//
+// Errors:
+//
+// "the server is running without a config file" - If there's no config file to write to.
+func (server *SugarDB) ConfigRewrite() (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CONFIG", "REWRITE"}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	res, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(res, "ok"), err
+}
+
 // AddCommand adds a new command to SugarDB. The added command can be executed using the ExecuteCommand method.
 //
 // Parameters:
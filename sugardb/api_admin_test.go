@@ -16,8 +16,10 @@ package sugardb
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/clock"
 	"github.com/echovault/sugardb/internal/constants"
 	"github.com/tidwall/resp"
@@ -465,6 +467,24 @@ The value passed must be an integer.`,
 				want:    "OK",
 				wantErr: nil,
 			},
+			{
+				name:    "14. Test LUA module that handles JSON documents",
+				path:    path.Join("..", "internal", "volumes", "modules", "lua", "json.lua"),
+				expect:  true,
+				args:    []string{},
+				cmd:     []string{"LUA.JSON", "LUA.JSON_KEY_1"},
+				want:    "OK",
+				wantErr: nil,
+			},
+			{
+				name:    "15. Test JS module that handles JSON documents",
+				path:    path.Join("..", "internal", "volumes", "modules", "js", "json.js"),
+				expect:  true,
+				args:    []string{},
+				cmd:     []string{"JS.JSON", "JS_JSON_KEY1"},
+				want:    "OK",
+				wantErr: nil,
+			},
 		}
 
 		for _, test := range tests {
@@ -573,7 +593,8 @@ The value passed must be an integer.`,
 				want: func() []string {
 					var commands []string
 					for _, command := range server.commands {
-						if strings.EqualFold(command.Module, constants.SortedSetModule) {
+						if strings.EqualFold(command.Module, constants.SortedSetModule) &&
+							strings.HasPrefix(strings.ToLower(command.Command), "z") {
 							commands = append(commands, strings.ToLower(command.Command))
 						}
 					}
@@ -733,4 +754,191 @@ The value passed must be an integer.`,
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_Info", func(t *testing.T) {
+		t.Parallel()
+
+		server := createSugarDB()
+		t.Cleanup(func() {
+			server.ShutDown()
+		})
+
+		if _, _, err := server.Set("key1", "value1", SETOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := server.HSet("key2", map[string]string{"field1": "value1"}); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := server.Expire("key2", 100); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := server.Get("key1"); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := server.Get("key3"); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name         string
+			sections     []string
+			wantSections []string
+			want         map[string]map[string]string
+		}{
+			{
+				name:         "1. Get the default sections",
+				sections:     []string{},
+				wantSections: []string{"server", "clients", "memory", "persistence", "stats", "replication", "keyspace"},
+				want: map[string]map[string]string{
+					"server":      {"sugardb_mode": "standalone", "uptime_in_seconds": "0"},
+					"stats":       {"keyspace_hits": "1", "keyspace_misses": "1"},
+					"replication": {"role": "master"},
+					"keyspace":    {"db0": "keys=2,expires=1"},
+				},
+			},
+			{
+				name:         "2. Get the commandstats section",
+				sections:     []string{"commandstats"},
+				wantSections: []string{"commandstats"},
+				want: map[string]map[string]string{
+					"commandstats": {"cmdstat_set": "calls=1", "cmdstat_get": "calls=2", "cmdstat_expire": "calls=1"},
+				},
+			},
+			{
+				name:         "3. Get all the sections",
+				sections:     []string{"all"},
+				wantSections: []string{"server", "keyspace", "commandstats"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := server.Info(tt.sections...)
+				if err != nil {
+					t.Errorf("Info() error = %v", err)
+					return
+				}
+				for _, section := range tt.wantSections {
+					if _, ok := got[section]; !ok {
+						t.Errorf("Info() expected section %s, got %v", section, got)
+					}
+				}
+				if len(tt.sections) > 0 && tt.sections[0] != "all" && len(got) != len(tt.wantSections) {
+					t.Errorf("Info() expected %d sections, got %d", len(tt.wantSections), len(got))
+				}
+				for section, fields := range tt.want {
+					for name, value := range fields {
+						if !strings.HasPrefix(got[section][name], value) {
+							t.Errorf("Info() expected %s field %s to start with %s, got %s", section, name, value, got[section][name])
+						}
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_Config", func(t *testing.T) {
+		t.Parallel()
+
+		configFile := path.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(configFile, []byte("Port: 7000\n"), 0644); err != nil {
+			t.Error(err)
+			return
+		}
+
+		server := createSugarDB()
+		WithConfigFile(configFile)(server)
+		t.Cleanup(func() {
+			server.ShutDown()
+		})
+
+		for i := 0; i < 10; i++ {
+			if err := presetValue(server, context.Background(), fmt.Sprintf("key%d", i), "value"); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		ok, err := server.ConfigSet(map[string]string{
+			"aof-sync-strategy":      "always",
+			"snapshot-threshold":     "50",
+			"notify-keyspace-events": "KEA",
+		})
+		if err != nil || !ok {
+			t.Errorf("ConfigSet() got = %v, error = %v", ok, err)
+			return
+		}
+		if got := server.aofEngine.Stats().SyncStrategy; got != "always" {
+			t.Errorf("expected AOF sync strategy to be always, got %s", got)
+		}
+		if got := internal.KeyspaceEvents(server.keyspaceEvents.Load()); got != internal.KeyspaceEventsKeyspace|
+			internal.KeyspaceEventsKeyevent|internal.KeyspaceEventsAll {
+			t.Errorf("expected keyspace events to be KEA, got %s", got)
+		}
+
+		// Lowering the memory limit evicts keys immediately.
+		if _, err = server.ConfigSet(map[string]string{"max-memory": "100", "eviction-policy": "allkeys-random"}); err != nil {
+			t.Error(err)
+			return
+		}
+		if size, _ := server.DBSize(); size >= 10 {
+			t.Errorf("expected keys to be evicted after lowering max-memory, got %d keys", size)
+		}
+
+		// Invalid values leave the config unchanged.
+		if _, err = server.ConfigSet(map[string]string{"eviction-sample": "not-a-number"}); err == nil {
+			t.Error("expected error for invalid eviction-sample")
+		}
+
+		got, err := server.ConfigGet("max-memory", "eviction-*", "snapshot-threshold")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := map[string]string{
+			"max-memory":         "100",
+			"eviction-policy":    "allkeys-random",
+			"eviction-sample":    "0",
+			"eviction-interval":  "0s",
+			"snapshot-threshold": "50",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ConfigGet() got = %v, want %v", got, want)
+		}
+
+		if ok, err = server.ConfigRewrite(); err != nil || !ok {
+			t.Errorf("ConfigRewrite() got = %v, error = %v", ok, err)
+			return
+		}
+		b, err := os.ReadFile(configFile)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, line := range []string{"Port: 7000", "MaxMemory: 100", "EvictionPolicy: allkeys-random", "AOFSyncStrategy: always"} {
+			if !strings.Contains(string(b), line) {
+				t.Errorf("expected config file to contain \"%s\", got:\n%s", line, string(b))
+			}
+		}
+
+		// The config can be changed while commands that read it are executing.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				_, _, _ = server.Set(fmt.Sprintf("key%d", i%10), "value", SETOptions{})
+			}
+		}()
+		for i := 0; i < 100; i++ {
+			if _, err = server.ConfigSet(map[string]string{"max-memory": strconv.Itoa(100000000 + i)}); err != nil {
+				t.Error(err)
+				break
+			}
+		}
+		<-done
+	})
 }
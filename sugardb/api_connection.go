@@ -17,8 +17,26 @@ package sugardb
 import (
 	"errors"
 	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
 )
 
+// ClientKillOptions selects the TCP clients closed by ClientKill. A client must match all the non-empty fields.
+//
+// ID - uint64 - The id of the client's connection.
+//
+// Addr - string - The remote address of the client in the ip:port format.
+//
+// User - string - The ACL user the client is authenticated as.
+type ClientKillOptions struct {
+	ID   uint64
+	Addr string
+	User string
+}
+
 // SetProtocol sets the RESP protocol that's expected from responses to embedded API calls.
 // This command does not affect the RESP protocol expected by any of the TCP clients.
 //
@@ -68,3 +86,97 @@ func (server *SugarDB) SelectDB(database int) error {
 
 	return nil
 }
+
+// ClientList returns information about the TCP clients connected to the server.
+//
+// Returns: A list of the fields of each client ordered by connection id. The fields are id, addr, laddr, name,
+// age, idle, flags, db, user, cmd and resp, e.g. client["cmd"] is the last command executed by the client.
+func (server *SugarDB) ClientList() ([]map[string]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CLIENT", "LIST"}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseStringResponse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make([]map[string]string, 0)
+	for _, line := range strings.Split(res, "\n") {
+		if line == "" {
+			continue
+		}
+		client := make(map[string]string)
+		for _, field := range strings.Fields(line) {
+			if name, value, ok := strings.Cut(field, "="); ok {
+				client[name] = value
+			}
+		}
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+// ClientKill closes the connections of the TCP clients that match all the options.
+// The zero value of ClientKillOptions closes every TCP client.
+//
+// Parameters:
+//
+// `options` - ClientKillOptions.
+//
+// Returns: The number of closed connections.
+func (server *SugarDB) ClientKill(options ClientKillOptions) (int, error) {
+	cmd := []string{"CLIENT", "KILL"}
+	if options.ID != 0 {
+		cmd = append(cmd, "ID", strconv.FormatUint(options.ID, 10))
+	}
+	if options.Addr != "" {
+		cmd = append(cmd, "ADDR", options.Addr)
+	}
+	if options.User != "" {
+		cmd = append(cmd, "USER", options.User)
+	}
+	cmd = append(cmd, "SKIPME", "no")
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// ClientPause suspends the commands of all the TCP clients for the duration of the timeout.
+// Embedded API calls are not suspended.
+//
+// Parameters:
+//
+// `timeout` - time.Duration - How long the clients are paused for. The timeout is rounded down to milliseconds.
+//
+// `writeOnly` - bool - When true, only write commands are suspended. Otherwise, every command is suspended.
+//
+// Returns: true if the clients are paused.
+func (server *SugarDB) ClientPause(timeout time.Duration, writeOnly bool) (bool, error) {
+	mode := "ALL"
+	if writeOnly {
+		mode = "WRITE"
+	}
+	cmd := []string{"CLIENT", "PAUSE", strconv.FormatInt(timeout.Milliseconds(), 10), mode}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// ClientUnpause resumes the commands of the TCP clients paused with ClientPause or CLIENT PAUSE.
+//
+// Returns: true if the clients are resumed.
+func (server *SugarDB) ClientUnpause() (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CLIENT", "UNPAUSE"}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
@@ -21,8 +21,11 @@ import (
 	"github.com/echovault/sugardb/internal/constants"
 	"github.com/echovault/sugardb/internal/modules/connection"
 	"github.com/tidwall/resp"
+	"net"
 	"reflect"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestSugarDB_Connection(t *testing.T) {
@@ -293,4 +296,93 @@ func TestSugarDB_Connection(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_Client", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		conf := DefaultConfig()
+		conf.Port = uint16(port)
+		conf.RequirePass = false
+
+		mockServer := createSugarDBWithConfig(conf)
+		go func() {
+			mockServer.Start()
+		}()
+		t.Cleanup(func() {
+			mockServer.ShutDown()
+		})
+
+		conn1, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn1.Close()
+		}()
+		conn2, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer func() {
+			_ = conn2.Close()
+		}()
+
+		// Wait for both connections to be registered.
+		var clients []map[string]string
+		for i := 0; i < 50; i++ {
+			if clients, err = mockServer.ClientList(); err != nil {
+				t.Error(err)
+				return
+			}
+			if len(clients) == 2 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if len(clients) != 2 {
+			t.Errorf("ClientList() got %d clients, want 2", len(clients))
+			return
+		}
+		for _, conn := range []net.Conn{conn1, conn2} {
+			if !slices.ContainsFunc(clients, func(client map[string]string) bool {
+				return client["addr"] == conn.LocalAddr().String() && client["user"] == "default"
+			}) {
+				t.Errorf("ClientList() got %+v, want client with addr %s", clients, conn.LocalAddr().String())
+			}
+		}
+
+		killed, err := mockServer.ClientKill(ClientKillOptions{Addr: conn2.LocalAddr().String()})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if killed != 1 {
+			t.Errorf("ClientKill() got %d, want 1", killed)
+		}
+		_ = conn2.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err = conn2.Read(make([]byte, 1)); err == nil {
+			t.Error("ClientKill() expected the client connection to be closed")
+		}
+
+		ok, err := mockServer.ClientPause(time.Minute, true)
+		if err != nil || !ok {
+			t.Errorf("ClientPause() got %v, %v, want true", ok, err)
+		}
+		// Embedded API calls are not paused.
+		if _, ok, err = mockServer.Set("key", "value", SETOptions{}); err != nil || !ok {
+			t.Errorf("Set() got %v, %v, want true", ok, err)
+		}
+		ok, err = mockServer.ClientUnpause()
+		if err != nil || !ok {
+			t.Errorf("ClientUnpause() got %v, %v, want true", ok, err)
+		}
+	})
 }
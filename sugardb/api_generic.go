@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/echovault/sugardb/internal"
 )
@@ -836,4 +837,281 @@ func (server *SugarDB) Exists(keys ...string) (int, error) {
 	}
 
 	return internal.ParseStringArrayResponse(b)
-}
\ No newline at end of file
+}
+
+// SCANOptions modifies the behaviour of the Scan function.
+//
+// Match - string - only return keys that match this glob pattern.
+//
+// Count - uint - the number of keys to visit in this call. Defaults to 10 when 0.
+//
+// Type - string - only return keys whose value is of this type, e.g. "string", "list", "hash", "set" or "zset".
+type SCANOptions struct {
+	Match string
+	Count uint
+	Type  string
+}
+
+// Scan incrementally iterates over the keys in the current database. Begin the iteration with a cursor of 0
+// and call Scan again with the returned cursor until it returns 0. Each call only visits a few keys,
+// so large databases can be walked without blocking other commands for long.
+//
+// Every key that exists for the whole iteration is returned exactly once, even if other keys are added or
+// removed between calls. Keys that are added or removed during the iteration may or may not be returned.
+//
+// Parameters:
+//
+// `cursor` - uint64 - the cursor returned by the previous call, or 0 to begin the iteration.
+//
+// `options` - SCANOptions.
+//
+// Returns: The cursor to pass to the next call and the keys visited in this call. The returned cursor is 0
+// when the iteration is complete. Match and Type are applied after the keys are visited, so a call can return
+// fewer keys than Count, including none at all.
+func (server *SugarDB) Scan(cursor uint64, options SCANOptions) (uint64, []string, error) {
+	cmd := append([]string{"SCAN", strconv.FormatUint(cursor, 10)}, scanOptions(options.Match, options.Count)...)
+	if options.Type != "" {
+		cmd = append(cmd, "TYPE", options.Type)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, nil, err
+	}
+	return internal.ParseScanResponse(b)
+}
+
+// scanOptions encodes the MATCH and COUNT options shared by the SCAN family of commands.
+func scanOptions(match string, count uint) []string {
+	var options []string
+	if match != "" {
+		options = append(options, "MATCH", match)
+	}
+	if count != 0 {
+		options = append(options, "COUNT", strconv.FormatUint(uint64(count), 10))
+	}
+	return options
+}
+
+// SORTOptions modifies the behaviour of the Sort, SortRO and SortStore functions.
+//
+// By - string - sort by the values of the keys matching this pattern instead of the elements. The first "*" is
+// replaced by the element, and a "->field" suffix refers to a hash field. A pattern without "*" skips sorting.
+//
+// Get - []string - return the values of the keys matching these patterns instead of the elements.
+// "#" returns the element itself.
+//
+// Offset - uint - the number of sorted elements to skip.
+//
+// Count - uint - the maximum number of sorted elements to return. All the elements after Offset are returned when 0.
+//
+// Desc - bool - sort from the largest to the smallest element.
+//
+// Alpha - bool - compare the elements as strings instead of numbers.
+type SORTOptions struct {
+	By     string
+	Get    []string
+	Offset uint
+	Count  uint
+	Desc   bool
+	Alpha  bool
+}
+
+func (options SORTOptions) args() []string {
+	var args []string
+	if options.By != "" {
+		args = append(args, "BY", options.By)
+	}
+	if options.Offset != 0 || options.Count != 0 {
+		count := "-1"
+		if options.Count != 0 {
+			count = strconv.FormatUint(uint64(options.Count), 10)
+		}
+		args = append(args, "LIMIT", strconv.FormatUint(uint64(options.Offset), 10), count)
+	}
+	for _, pattern := range options.Get {
+		args = append(args, "GET", pattern)
+	}
+	if options.Desc {
+		args = append(args, "DESC")
+	}
+	if options.Alpha {
+		args = append(args, "ALPHA")
+	}
+	return args
+}
+
+// Sort returns the elements of the list, set or sorted set at key in sorted order.
+//
+// Parameters:
+//
+// `key` - string - the key of the list, set or sorted set.
+//
+// `options` - SORTOptions.
+//
+// Returns: The sorted elements, or the values projected by the Get patterns. Values that don't exist are returned
+// as empty strings. Returns an error if the key holds another type or if the weights can't be parsed as numbers.
+func (server *SugarDB) Sort(key string, options SORTOptions) ([]string, error) {
+	cmd := append([]string{"SORT", key}, options.args()...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// SortRO is the read-only variant of Sort.
+func (server *SugarDB) SortRO(key string, options SORTOptions) ([]string, error) {
+	cmd := append([]string{"SORT_RO", key}, options.args()...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// SortStore sorts the elements of the list, set or sorted set at key and stores the result as a list at destination.
+//
+// Parameters:
+//
+// `key` - string - the key of the list, set or sorted set.
+//
+// `destination` - string - the key of the list to store the result in. It is deleted when the result is empty.
+//
+// `options` - SORTOptions.
+//
+// Returns: The number of elements in the stored list.
+func (server *SugarDB) SortStore(key, destination string, options SORTOptions) (int, error) {
+	cmd := append(append([]string{"SORT", key}, options.args()...), "STORE", destination)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// Dump serializes the value at key, together with its expiry time and the expiry times of hash fields.
+//
+// Parameters:
+//
+// `key` - string - the key to serialize.
+//
+// Returns: A versioned and checksummed payload that can be passed to Restore on this or another SugarDB instance.
+// Returns an empty string if the key does not exist.
+func (server *SugarDB) Dump(key string) (string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"DUMP", key}), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// RESTOREOptions modifies the behaviour of the Restore function.
+//
+// TTL - int - the TTL of the key in milliseconds. When 0, the key keeps the expiry time recorded in the payload.
+//
+// AbsTTL - bool - whether TTL is a unix time in milliseconds instead of a duration.
+//
+// Replace - bool - whether to overwrite the key if it already exists.
+//
+// IdleTime - uint - the number of seconds the key has been idle for. Only used with LRU eviction policies.
+//
+// Freq - uint - the access frequency of the key. Only used with LFU eviction policies.
+type RESTOREOptions struct {
+	TTL      int
+	AbsTTL   bool
+	Replace  bool
+	IdleTime uint
+	Freq     uint
+}
+
+// Restore creates the key from a payload returned by Dump.
+//
+// Parameters:
+//
+// `key` - string - the key to create.
+//
+// `payload` - string - the payload returned by Dump.
+//
+// `options` - RESTOREOptions.
+//
+// Returns: "OK" when the key is restored, or when its expiry time has already passed.
+// Returns an error if the key exists and Replace is false, or if the payload is corrupted.
+func (server *SugarDB) Restore(key, payload string, options RESTOREOptions) (string, error) {
+	cmd := []string{"RESTORE", key, strconv.Itoa(options.TTL), payload}
+	if options.Replace {
+		cmd = append(cmd, "REPLACE")
+	}
+	if options.AbsTTL {
+		cmd = append(cmd, "ABSTTL")
+	}
+	if options.IdleTime != 0 {
+		cmd = append(cmd, "IDLETIME", strconv.FormatUint(uint64(options.IdleTime), 10))
+	}
+	if options.Freq != 0 {
+		cmd = append(cmd, "FREQ", strconv.FormatUint(uint64(options.Freq), 10))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// MIGRATEOptions modifies the behaviour of the Migrate function.
+//
+// Database - int - the database on the target instance to restore the keys in.
+//
+// Timeout - time.Duration - the timeout of each request to the target instance. Defaults to 1 second when 0.
+//
+// Copy - bool - whether to keep the keys on this instance after they're migrated.
+//
+// Replace - bool - whether to overwrite keys that already exist on the target instance.
+//
+// Username - string - the username to authenticate with on the target instance. Requires Password.
+//
+// Password - string - the password to authenticate with on the target instance.
+type MIGRATEOptions struct {
+	Database int
+	Timeout  time.Duration
+	Copy     bool
+	Replace  bool
+	Username string
+	Password string
+}
+
+// Migrate moves keys to another SugarDB instance. Each key is restored on the target before it's deleted here,
+// so a key is never lost if the migration fails part of the way through.
+//
+// Parameters:
+//
+// `host` - string - the host of the target instance.
+//
+// `port` - int - the port of the target instance.
+//
+// `keys` - []string - the keys to migrate.
+//
+// `options` - MIGRATEOptions.
+//
+// Returns: "OK" if the keys were migrated, or "NOKEY" if none of the keys exist.
+// Returns an error if the target instance can't be reached or replies with an error.
+func (server *SugarDB) Migrate(host string, port int, keys []string, options MIGRATEOptions) (string, error) {
+	cmd := []string{"MIGRATE", host, strconv.Itoa(port), "", strconv.Itoa(options.Database),
+		strconv.FormatInt(options.Timeout.Milliseconds(), 10)}
+	if options.Copy {
+		cmd = append(cmd, "COPY")
+	}
+	if options.Replace {
+		cmd = append(cmd, "REPLACE")
+	}
+	if options.Username != "" {
+		cmd = append(cmd, "AUTH2", options.Username, options.Password)
+	} else if options.Password != "" {
+		cmd = append(cmd, "AUTH", options.Password)
+	}
+	cmd = append(append(cmd, "KEYS"), keys...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/echovault/sugardb/internal/constants"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -2244,4 +2245,267 @@ func TestSugarDB_Generic(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_SCAN", func(t *testing.T) {
+		// Use a dedicated server so that keys from the other tests are not returned.
+		server := createSugarDB()
+		t.Cleanup(func() {
+			server.ShutDown()
+		})
+
+		var want []string
+		for i := 0; i < 25; i++ {
+			key := "scan_key" + strconv.Itoa(i)
+			if err := presetValue(server, context.Background(), key, "value"); err != nil {
+				t.Error(err)
+				return
+			}
+			want = append(want, key)
+		}
+		if err := presetValue(server, context.Background(), "scan_list", []string{"value"}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		var got []string
+		var cursor uint64
+		for {
+			next, keys, err := server.Scan(cursor, SCANOptions{Match: "scan_key*", Count: 4})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got = append(got, keys...)
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+		slices.Sort(got)
+		slices.Sort(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("SCAN() got = %v, want %v", got, want)
+		}
+
+		_, keys, err := server.Scan(0, SCANOptions{Type: "list", Count: 100})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(keys, []string{"scan_list"}) {
+			t.Errorf("SCAN() with TYPE got = %v, want [scan_list]", keys)
+		}
+	})
+
+	t.Run("TestSugarDB_SORT", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.RPush("sort_list", "3", "1", "2"); err != nil {
+			t.Error(err)
+			return
+		}
+		for element, rank := range map[string]string{"1": "20", "2": "10", "3": "30"} {
+			if _, err := server.HSet("sort_weight_"+element, map[string]string{"rank": rank, "name": "n" + element}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		tests := []struct {
+			name    string
+			options SORTOptions
+			want    []string
+		}{
+			{
+				name:    "1. Sort the elements numerically",
+				options: SORTOptions{},
+				want:    []string{"1", "2", "3"},
+			},
+			{
+				name:    "2. Sort in descending order with a limit",
+				options: SORTOptions{Desc: true, Offset: 1, Count: 1},
+				want:    []string{"2"},
+			},
+			{
+				name:    "3. Sort by hash fields and project the element and another field",
+				options: SORTOptions{By: "sort_weight_*->rank", Get: []string{"#", "sort_weight_*->name"}},
+				want:    []string{"2", "n2", "1", "n1", "3", "n3"},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := server.Sort("sort_list", tt.options)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("SORT() got = %v, want %v", got, tt.want)
+				}
+				got, err = server.SortRO("sort_list", tt.options)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("SORT_RO() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+
+		t.Run("4. Store the sorted elements", func(t *testing.T) {
+			count, err := server.SortStore("sort_list", "sort_destination", SORTOptions{By: "sort_weight_*->rank"})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if count != 3 {
+				t.Errorf("SortStore() count = %d, want 3", count)
+			}
+			got, err := server.LRange("sort_destination", 0, -1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(got, []string{"2", "1", "3"}) {
+				t.Errorf("SortStore() stored %v, want [2 1 3]", got)
+			}
+		})
+	})
+
+	t.Run("TestSugarDB_DUMP_RESTORE", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.HSet("dump_hash", map[string]string{"field1": "value1", "field2": "value2"}); err != nil {
+			t.Error(err)
+			return
+		}
+		payload, err := server.Dump("dump_hash")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		ok, err := server.Restore("restore_hash", payload, RESTOREOptions{TTL: 2000})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if ok != "OK" {
+			t.Errorf("Restore() got = %s, want OK", ok)
+		}
+		got, err := server.HGetAll("restore_hash")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		slices.Sort(got)
+		if want := []string{"field1", "field2", "value1", "value2"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("HGetAll() got = %v, want %v", got, want)
+		}
+		if ttl, _ := server.PTTL("restore_hash"); ttl != 2000 {
+			t.Errorf("PTTL() got = %d, want 2000", ttl)
+		}
+
+		if _, err = server.Restore("restore_hash", payload, RESTOREOptions{}); err == nil {
+			t.Error("expected error restoring an existing key without Replace")
+		}
+		if missing, _ := server.Dump("dump_missing"); missing != "" {
+			t.Errorf("Dump() of a missing key got = %q, want empty string", missing)
+		}
+	})
+
+	t.Run("TestSugarDB_RESTORE_FREQ", func(t *testing.T) {
+		t.Parallel()
+
+		// Use a dedicated server with an LFU eviction policy so that the access frequency is tracked.
+		conf := DefaultConfig()
+		conf.DataDir = ""
+		conf.EvictionPolicy = constants.AllKeysLFU
+		lfuServer := createSugarDBWithConfig(conf)
+		t.Cleanup(func() {
+			lfuServer.ShutDown()
+		})
+
+		if _, _, err := lfuServer.Set("freq_key", "value", SETOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		payload, err := lfuServer.Dump("freq_key")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err = lfuServer.Restore("freq_key", payload, RESTOREOptions{Replace: true, Freq: 42}); err != nil {
+			t.Error(err)
+			return
+		}
+		freq, err := lfuServer.ObjectFreq("freq_key")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if freq != 42 {
+			t.Errorf("ObjectFreq() got = %d, want 42", freq)
+		}
+	})
+
+	t.Run("TestSugarDB_MIGRATE", func(t *testing.T) {
+		t.Parallel()
+
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conf := DefaultConfig()
+		conf.BindAddr = "localhost"
+		conf.Port = uint16(port)
+		conf.DataDir = ""
+		target := createSugarDBWithConfig(conf)
+		go func() {
+			target.Start()
+		}()
+		t.Cleanup(func() {
+			target.ShutDown()
+		})
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_ = conn.Close()
+
+		if _, err = server.RPush("migrate_list", "a", "b", "c"); err != nil {
+			t.Error(err)
+			return
+		}
+		res, err := server.Migrate("localhost", port, []string{"migrate_list", "migrate_missing"}, MIGRATEOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if res != "OK" {
+			t.Errorf("Migrate() got = %s, want OK", res)
+		}
+		if n, _ := server.Exists("migrate_list"); n != 0 {
+			t.Error("expected migrate_list to be deleted after the migration")
+		}
+		got, err := target.LRange("migrate_list", 0, -1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+			t.Errorf("LRange() on the target got = %v, want [a b c]", got)
+		}
+
+		res, err = server.Migrate("localhost", port, []string{"migrate_list"}, MIGRATEOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if res != "NOKEY" {
+			t.Errorf("Migrate() got = %s, want NOKEY", res)
+		}
+	})
 }
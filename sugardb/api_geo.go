@@ -0,0 +1,362 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// GeoCoordinates is the longitude and latitude of a point.
+type GeoCoordinates struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// GeoLocation is a member of a geospatial index with its coordinates.
+type GeoLocation struct {
+	Member string
+	GeoCoordinates
+}
+
+// GeoAddOptions modifies the behaviour of the GeoAdd command.
+//
+// NX only adds new members. NX is higher priority than XX.
+//
+// XX only updates the coordinates of existing members.
+//
+// CH modifies the result to return the number of members added or updated, instead of only the members added.
+type GeoAddOptions struct {
+	NX bool
+	XX bool
+	CH bool
+}
+
+// GeoBox is the width and height of the box searched by GeoSearch.
+type GeoBox struct {
+	Width  float64
+	Height float64
+}
+
+// GeoSearchOptions specifies the area searched by GeoSearch and modifies its result.
+//
+// FromMember is the member at the center of the search. If FromMember is empty, FromLonLat is the center.
+//
+// ByBox is the box to search in. If the box is empty, the members within the ByRadius radius are searched.
+//
+// Unit is the unit of ByRadius, ByBox and the returned distances. One of M, KM, FT or MI. Defaults to M.
+//
+// Asc and Desc sort the members by their distance from the center. Asc is higher priority than Desc.
+//
+// Count limits the result to the closest Count members. If Any is true, the first Count members found are
+// returned instead, which is faster but they may not be the closest ones.
+type GeoSearchOptions struct {
+	FromMember string
+	FromLonLat GeoCoordinates
+	ByRadius   float64
+	ByBox      GeoBox
+	Unit       string
+	Asc        bool
+	Desc       bool
+	Count      uint
+	Any        bool
+}
+
+// GeoSearchStoreOptions modifies the behaviour of GeoSearchStore. StoreDist stores the members with their
+// distance from the center instead of their geohash.
+type GeoSearchStoreOptions struct {
+	GeoSearchOptions
+	StoreDist bool
+}
+
+// GeoSearchResult is a member found by GeoSearch.
+//
+// Distance is the distance of the member from the center of the search, in the search unit.
+//
+// Hash is the geohash of the member, which is its score in the sorted set.
+type GeoSearchResult struct {
+	Member      string
+	Distance    float64
+	Hash        int
+	Coordinates GeoCoordinates
+}
+
+func buildGeoSearchCommand(cmd []string, options GeoSearchOptions) []string {
+	if options.FromMember != "" {
+		cmd = append(cmd, "FROMMEMBER", options.FromMember)
+	} else {
+		cmd = append(cmd, "FROMLONLAT",
+			strconv.FormatFloat(options.FromLonLat.Longitude, 'f', -1, 64),
+			strconv.FormatFloat(options.FromLonLat.Latitude, 'f', -1, 64))
+	}
+
+	unit := options.Unit
+	if unit == "" {
+		unit = "m"
+	}
+	if options.ByBox != (GeoBox{}) {
+		cmd = append(cmd, "BYBOX",
+			strconv.FormatFloat(options.ByBox.Width, 'f', -1, 64),
+			strconv.FormatFloat(options.ByBox.Height, 'f', -1, 64),
+			unit)
+	} else {
+		cmd = append(cmd, "BYRADIUS", strconv.FormatFloat(options.ByRadius, 'f', -1, 64), unit)
+	}
+
+	switch {
+	case options.Asc:
+		cmd = append(cmd, "ASC")
+	case options.Desc:
+		cmd = append(cmd, "DESC")
+	}
+
+	if options.Count > 0 {
+		cmd = append(cmd, "COUNT", strconv.Itoa(int(options.Count)))
+		if options.Any {
+			cmd = append(cmd, "ANY")
+		}
+	}
+
+	return cmd
+}
+
+func parseGeoCoordinates(value interface{}) (GeoCoordinates, error) {
+	arr, ok := value.([]interface{})
+	if !ok || len(arr) != 2 {
+		return GeoCoordinates{}, errors.New("invalid coordinates in response")
+	}
+	lon, _ := arr[0].(string)
+	lat, _ := arr[1].(string)
+	longitude, err := strconv.ParseFloat(lon, 64)
+	if err != nil {
+		return GeoCoordinates{}, err
+	}
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil {
+		return GeoCoordinates{}, err
+	}
+	return GeoCoordinates{Longitude: longitude, Latitude: latitude}, nil
+}
+
+// GeoAdd adds the locations to the geospatial index at the key. The index is a sorted set where each member's
+// score is the geohash of its coordinates. If the key does not exist, a new index is created.
+//
+// Parameters:
+//
+// `key` - string - the key to update.
+//
+// `options` - GeoAddOptions.
+//
+// `locations` - ...GeoLocation - the members to add with their coordinates.
+//
+// Returns: The number of members added, or the number of members added or updated if CH is true.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when the provided key exists but is not a sorted set.
+//
+// "invalid longitude,latitude pair <longitude>,<latitude>" - when the coordinates are out of range.
+func (server *SugarDB) GeoAdd(key string, options GeoAddOptions, locations ...GeoLocation) (int, error) {
+	cmd := []string{"GEOADD", key}
+
+	switch {
+	case options.NX:
+		cmd = append(cmd, "NX")
+	case options.XX:
+		cmd = append(cmd, "XX")
+	}
+
+	if options.CH {
+		cmd = append(cmd, "CH")
+	}
+
+	for _, location := range locations {
+		cmd = append(cmd,
+			strconv.FormatFloat(location.Longitude, 'f', -1, 64),
+			strconv.FormatFloat(location.Latitude, 'f', -1, 64),
+			location.Member)
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// GeoPos returns the coordinates of the members of the geospatial index at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the geospatial index.
+//
+// `members` - ...string - the members to get the coordinates of.
+//
+// Returns: The coordinates of each member, in the order of the members. The coordinates of the members that
+// don't exist are nil.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when the provided key exists but is not a sorted set.
+func (server *SugarDB) GeoPos(key string, members ...string) ([]*GeoCoordinates, error) {
+	cmd := append([]string{"GEOPOS", key}, members...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	arr, _ := res.([]interface{})
+
+	positions := make([]*GeoCoordinates, len(arr))
+	for i, value := range arr {
+		if value == nil {
+			continue
+		}
+		coords, err := parseGeoCoordinates(value)
+		if err != nil {
+			return nil, err
+		}
+		positions[i] = &coords
+	}
+	return positions, nil
+}
+
+// GeoDist returns the distance between two members of the geospatial index at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the geospatial index.
+//
+// `member1` - string - the first member.
+//
+// `member2` - string - the second member.
+//
+// `unit` - string - the unit of the distance. One of M, KM, FT or MI. Defaults to M when empty.
+//
+// Returns: The distance between the members, and false if one of the members does not exist.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when the provided key exists but is not a sorted set.
+//
+// "unsupported unit provided. please use M, KM, FT, MI" - when the unit is not supported.
+func (server *SugarDB) GeoDist(key, member1, member2, unit string) (float64, bool, error) {
+	cmd := []string{"GEODIST", key, member1, member2}
+	if unit != "" {
+		cmd = append(cmd, unit)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, false, err
+	}
+
+	isNil, err := internal.ParseNilResponse(b)
+	if err != nil || isNil {
+		return 0, false, err
+	}
+
+	dist, err := internal.ParseFloatResponse(b)
+	if err != nil {
+		return 0, false, err
+	}
+	return dist, true, nil
+}
+
+// GeoSearch returns the members of the geospatial index at the key that are within the area described
+// by the options.
+//
+// Parameters:
+//
+// `key` - string - the key of the geospatial index.
+//
+// `options` - GeoSearchOptions.
+//
+// Returns: The members found with their distance from the center, geohash and coordinates.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when the provided key exists but is not a sorted set.
+//
+// "could not find member <member>" - when the FromMember member does not exist.
+func (server *SugarDB) GeoSearch(key string, options GeoSearchOptions) ([]GeoSearchResult, error) {
+	cmd := buildGeoSearchCommand([]string{"GEOSEARCH", key}, options)
+	cmd = append(cmd, "WITHDIST", "WITHHASH", "WITHCOORD")
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	arr, _ := res.([]interface{})
+
+	results := make([]GeoSearchResult, len(arr))
+	for i, value := range arr {
+		fields, ok := value.([]interface{})
+		if !ok || len(fields) != 4 {
+			return nil, errors.New("invalid search result in response")
+		}
+		results[i].Member, _ = fields[0].(string)
+		dist, _ := fields[1].(string)
+		if results[i].Distance, err = strconv.ParseFloat(dist, 64); err != nil {
+			return nil, err
+		}
+		results[i].Hash, _ = fields[2].(int)
+		if results[i].Coordinates, err = parseGeoCoordinates(fields[3]); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// GeoSearchStore works like GeoSearch, but stores the members found in the destination key.
+// If no members are found, the destination key is deleted.
+//
+// Parameters:
+//
+// `destination` - string - the key to store the members found at.
+//
+// `source` - string - the key of the geospatial index to search.
+//
+// `options` - GeoSearchStoreOptions.
+//
+// Returns: The number of members stored.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when the source key exists but is not a sorted set.
+//
+// "could not find member <member>" - when the FromMember member does not exist.
+func (server *SugarDB) GeoSearchStore(destination, source string, options GeoSearchStoreOptions) (int, error) {
+	cmd := buildGeoSearchCommand([]string{"GEOSEARCHSTORE", destination, source}, options.GeoSearchOptions)
+	if options.StoreDist {
+		cmd = append(cmd, "STOREDIST")
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
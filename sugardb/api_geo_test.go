@@ -0,0 +1,311 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSugarDB_Geo(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	sicily := []GeoLocation{
+		{Member: "Palermo", GeoCoordinates: GeoCoordinates{Longitude: 13.361389, Latitude: 38.115556}},
+		{Member: "Catania", GeoCoordinates: GeoCoordinates{Longitude: 15.087269, Latitude: 37.502669}},
+		{Member: "edge1", GeoCoordinates: GeoCoordinates{Longitude: 12.758489, Latitude: 38.788135}},
+		{Member: "edge2", GeoCoordinates: GeoCoordinates{Longitude: 17.241510, Latitude: 38.788135}},
+	}
+
+	// The stored coordinates are approximations of the coordinates added.
+	closeTo := func(a, b GeoCoordinates) bool {
+		return math.Abs(a.Longitude-b.Longitude) < 1e-5 && math.Abs(a.Latitude-b.Latitude) < 1e-5
+	}
+
+	t.Run("TestSugarDB_GEOADD", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name      string
+			key       string
+			preset    []GeoLocation
+			options   GeoAddOptions
+			locations []GeoLocation
+			want      int
+			wantErr   bool
+		}{
+			{
+				name:      "1. Create geospatial index on a non-existent key",
+				key:       "geoadd_key1",
+				locations: sicily,
+				want:      4,
+			},
+			{
+				name:      "2. Count added and updated members with CH",
+				key:       "geoadd_key2",
+				preset:    sicily[:2],
+				options:   GeoAddOptions{CH: true},
+				locations: append([]GeoLocation{{Member: "Palermo", GeoCoordinates: GeoCoordinates{Longitude: 13, Latitude: 38}}}, sicily[2:]...),
+				want:      3,
+			},
+			{
+				name:      "3. Only add new members with NX",
+				key:       "geoadd_key3",
+				preset:    sicily[:2],
+				options:   GeoAddOptions{NX: true},
+				locations: sicily,
+				want:      2,
+			},
+			{
+				name:      "4. Return error on invalid coordinates",
+				key:       "geoadd_key4",
+				locations: []GeoLocation{{Member: "North Pole", GeoCoordinates: GeoCoordinates{Longitude: 0, Latitude: 90}}},
+				wantErr:   true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.preset != nil {
+					if _, err := server.GeoAdd(tt.key, GeoAddOptions{}, tt.preset...); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.GeoAdd(tt.key, tt.options, tt.locations...)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("GEOADD() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("GEOADD() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_GEOPOS", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.GeoAdd("geopos_key1", GeoAddOptions{}, sicily...); err != nil {
+			t.Error(err)
+			return
+		}
+
+		got, err := server.GeoPos("geopos_key1", "Palermo", "Rome", "Catania")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(got) != 3 || got[1] != nil {
+			t.Errorf("GEOPOS() got = %v, want 2 positions and nil for Rome", got)
+			return
+		}
+		if !closeTo(*got[0], sicily[0].GeoCoordinates) || !closeTo(*got[2], sicily[1].GeoCoordinates) {
+			t.Errorf("GEOPOS() got = %v, %v, want %v, %v", *got[0], *got[2], sicily[0], sicily[1])
+		}
+	})
+
+	t.Run("TestSugarDB_GEODIST", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.GeoAdd("geodist_key1", GeoAddOptions{}, sicily...); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name      string
+			member1   string
+			member2   string
+			unit      string
+			want      float64
+			wantFound bool
+			wantErr   bool
+		}{
+			{
+				name:      "1. Return the distance in meters by default",
+				member1:   "Palermo",
+				member2:   "Catania",
+				want:      166274.1516,
+				wantFound: true,
+			},
+			{
+				name:      "2. Return the distance in the given unit",
+				member1:   "Palermo",
+				member2:   "Catania",
+				unit:      "km",
+				want:      166.2742,
+				wantFound: true,
+			},
+			{
+				name:    "3. Return not found when a member does not exist",
+				member1: "Palermo",
+				member2: "Rome",
+			},
+			{
+				name:    "4. Return error on unsupported unit",
+				member1: "Palermo",
+				member2: "Catania",
+				unit:    "yd",
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, found, err := server.GeoDist("geodist_key1", tt.member1, tt.member2, tt.unit)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("GEODIST() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want || found != tt.wantFound {
+					t.Errorf("GEODIST() got = %v, %v, want %v, %v", got, found, tt.want, tt.wantFound)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_GEOSEARCH", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.GeoAdd("geosearch_key1", GeoAddOptions{}, sicily...); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name        string
+			options     GeoSearchOptions
+			wantMembers []string
+			wantErr     bool
+		}{
+			{
+				name: "1. Search by radius around coordinates",
+				options: GeoSearchOptions{
+					FromLonLat: GeoCoordinates{Longitude: 15, Latitude: 37},
+					ByRadius:   200,
+					Unit:       "km",
+					Asc:        true,
+				},
+				wantMembers: []string{"Catania", "Palermo"},
+			},
+			{
+				name: "2. Search by box around a member",
+				options: GeoSearchOptions{
+					FromMember: "Catania",
+					ByBox:      GeoBox{Width: 600, Height: 400},
+					Unit:       "km",
+					Desc:       true,
+				},
+				wantMembers: []string{"edge1", "edge2", "Palermo", "Catania"},
+			},
+			{
+				name: "3. Return the closest members with Count",
+				options: GeoSearchOptions{
+					FromMember: "Palermo",
+					ByRadius:   500,
+					Unit:       "km",
+					Count:      2,
+				},
+				wantMembers: []string{"Palermo", "edge1"},
+			},
+			{
+				name: "4. Return error when the member does not exist",
+				options: GeoSearchOptions{
+					FromMember: "Rome",
+					ByRadius:   500,
+				},
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := server.GeoSearch("geosearch_key1", tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("GEOSEARCH() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				members := make([]string, len(got))
+				for i, result := range got {
+					members[i] = result.Member
+				}
+				if !reflect.DeepEqual(members, tt.wantMembers) {
+					t.Errorf("GEOSEARCH() got = %v, want %v", members, tt.wantMembers)
+				}
+			})
+		}
+
+		t.Run("5. Return the distance, hash and coordinates of the members", func(t *testing.T) {
+			got, err := server.GeoSearch("geosearch_key1", GeoSearchOptions{
+				FromLonLat: GeoCoordinates{Longitude: 15, Latitude: 37},
+				ByRadius:   100,
+				Unit:       "km",
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(got) != 1 {
+				t.Errorf("GEOSEARCH() got %d results, want 1", len(got))
+				return
+			}
+			if got[0].Member != "Catania" || got[0].Distance != 56.4413 || got[0].Hash != 3479447370796909 ||
+				!closeTo(got[0].Coordinates, sicily[1].GeoCoordinates) {
+				t.Errorf("GEOSEARCH() got = %+v", got[0])
+			}
+		})
+	})
+
+	t.Run("TestSugarDB_GEOSEARCHSTORE", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.GeoAdd("geosearchstore_key1", GeoAddOptions{}, sicily...); err != nil {
+			t.Error(err)
+			return
+		}
+
+		got, err := server.GeoSearchStore("geosearchstore_dest1", "geosearchstore_key1", GeoSearchStoreOptions{
+			GeoSearchOptions: GeoSearchOptions{
+				FromLonLat: GeoCoordinates{Longitude: 15, Latitude: 37},
+				ByRadius:   200,
+				Unit:       "km",
+			},
+			StoreDist: true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got != 2 {
+			t.Errorf("GEOSEARCHSTORE() got = %v, want %v", got, 2)
+		}
+
+		score, err := server.ZScore("geosearchstore_dest1", "Catania")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if s, ok := score.(float64); !ok || math.Abs(s-56.4413) > 1e-4 {
+			t.Errorf("expected stored distance of Catania to be 56.4413, got %v", score)
+		}
+	})
+}
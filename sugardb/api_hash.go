@@ -510,3 +510,284 @@ func (server *SugarDB) HExpireTime(key string, fields ...string) ([]int64, error
 	}
 	return internal.ParseInteger64ArrayResponse(b)
 }
+
+// HPExpire sets the expiration for the provided field(s) in a hash map, in milliseconds.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `milliseconds` - int - number of milliseconds until expiration.
+//
+// `ExOpt` - ExpireOptions - One of NX, XX, GT, LT.
+//
+// `fields` - ...string - a list of fields to set expiration of.
+//
+// Returns: an integer array representing the outcome of the commmand for each field.
+//   - Integer reply: -2 if no such field exists in the provided hash key, or the provided key does not exist.
+//   - Integer reply: 0 if the specified NX | XX | GT | LT condition has not been met.
+//   - Integer reply: 1 if the expiration time was set/updated.
+//   - Integer reply: 2 when HPEXPIRE is called with 0 milliseconds.
+//
+// Errors:
+//
+// "value of key <key> is not a hash" - when the provided key is not a hash.
+func (server *SugarDB) HPExpire(key string, milliseconds int, ExOpt ExpireOptions, fields ...string) ([]int, error) {
+	cmd := []string{"HPEXPIRE", key, strconv.Itoa(milliseconds)}
+	if ExOpt != nil {
+		cmd = append(cmd, fmt.Sprintf("%v", ExOpt))
+	}
+	cmd = append(cmd, append([]string{"FIELDS", strconv.Itoa(len(fields))}, fields...)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// HPExpireAt sets the expiration for the provided field(s) in a hash map to a specific Unix time in milliseconds.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `unixMilliseconds` - int - Unix timestamp in milliseconds when the fields should expire.
+//
+// `ExOpt` - ExpireOptions - One of NX, XX, GT, LT.
+//
+// `fields` - ...string - a list of fields to set expiration of.
+//
+// Returns: an integer array representing the outcome of the commmand for each field, as described in HPExpire.
+//
+// Errors:
+//
+// "value of key <key> is not a hash" - when the provided key is not a hash.
+func (server *SugarDB) HPExpireAt(key string, unixMilliseconds int, ExOpt ExpireOptions, fields ...string) ([]int, error) {
+	cmd := []string{"HPEXPIREAT", key, strconv.Itoa(unixMilliseconds)}
+	if ExOpt != nil {
+		cmd = append(cmd, fmt.Sprintf("%v", ExOpt))
+	}
+	cmd = append(cmd, append([]string{"FIELDS", strconv.Itoa(len(fields))}, fields...)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// HPTTL gets the remaining time to live of the provided field(s) in a hash map, in milliseconds.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `fields` - ...string - a list of fields to get TTL for.
+//
+// Returns: an integer array representing the outcome of the commmand for each field.
+//   - Integer reply: the TTL in milliseconds.
+//   - Integer reply: -2 if no such field exists in the provided hash key, or the provided key does not exist.
+//   - Integer reply: -1 if the field exists but has no associated expiration set.
+//
+// Errors:
+//
+// "value of key <key> is not a hash" - when the provided key is not a hash.
+func (server *SugarDB) HPTTL(key string, fields ...string) ([]int, error) {
+	cmd := append([]string{"HPTTL", key, "FIELDS", strconv.Itoa(len(fields))}, fields...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// HPersist removes the expiration of the provided field(s) in a hash map.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `fields` - ...string - a list of fields to persist.
+//
+// Returns: an integer array representing the outcome of the commmand for each field.
+//   - Integer reply: 1 if the expiration was removed.
+//   - Integer reply: -1 if the field exists but has no associated expiration set.
+//   - Integer reply: -2 if no such field exists in the provided hash key, or the provided key does not exist.
+//
+// Errors:
+//
+// "value at <key> is not a hash" - when the provided key is not a hash.
+func (server *SugarDB) HPersist(key string, fields ...string) ([]int, error) {
+	cmd := append([]string{"HPERSIST", key, "FIELDS", strconv.Itoa(len(fields))}, fields...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// HGetDel retrieves the values of the provided fields and deletes the fields from the hash map.
+// The key is deleted when no fields are left.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `fields` - ...string - the list of fields to fetch and delete.
+//
+// Returns: A string slice of the values corresponding to the fields in the same order the fields were provided.
+// Non-existent fields have an empty string value.
+//
+// Errors:
+//
+// "value at <key> is not a hash" - when the provided key is not a hash.
+func (server *SugarDB) HGetDel(key string, fields ...string) ([]string, error) {
+	cmd := append([]string{"HGETDEL", key, "FIELDS", strconv.Itoa(len(fields))}, fields...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// HGetEx retrieves the values of the provided fields and optionally sets or removes their expiration.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `option` - GetExOption - one of EX, PX, EXAT, PXAT, PERSIST. Can be nil.
+//
+// `unixtime` - int - Number of seconds or milliseconds, or the Unix time, depending on the option.
+//
+// `fields` - ...string - the list of fields to fetch.
+//
+// Returns: A string slice of the values corresponding to the fields in the same order the fields were provided.
+// Non-existent fields have an empty string value.
+//
+// Errors:
+//
+// "value at <key> is not a hash" - when the provided key is not a hash.
+func (server *SugarDB) HGetEx(key string, option GetExOption, unixtime int, fields ...string) ([]string, error) {
+	cmd := []string{"HGETEX", key}
+	if option != nil {
+		cmd = append(cmd, fmt.Sprint(option))
+		if option != PERSIST {
+			cmd = append(cmd, strconv.Itoa(unixtime))
+		}
+	}
+	cmd = append(cmd, append([]string{"FIELDS", strconv.Itoa(len(fields))}, fields...)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// HSetExOptions modifies the behaviour of the HSetEx function.
+//
+// FNX - bool - only set the fields if none of them exist.
+//
+// FXX - bool - only set the fields if all of them already exist.
+//
+// ExpireOpt - SetExOption - One of SETEX, SETPX, SETEXAT, or SETPXAT.
+//
+// ExpireTime - int - Time in seconds or milliseconds depending on what ExpireOpt was provided.
+//
+// KeepTTL - bool - keep the current expiration of the fields. Ignored when ExpireOpt is provided.
+type HSetExOptions struct {
+	FNX        bool
+	FXX        bool
+	ExpireOpt  SetExOption
+	ExpireTime int
+	KeepTTL    bool
+}
+
+// HSetEx sets the values of the provided fields in a hash map and optionally their expiration.
+// If the hash map does not exist it will be created. Without ExpireOpt or KeepTTL, the expiration of the
+// fields is removed.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `fieldValuePairs` - map[string]string - the fields to set and their values.
+//
+// `options` - HSetExOptions.
+//
+// Returns: true if the fields were set, false if the FNX or FXX condition was not met.
+//
+// Errors:
+//
+// "value at <key> is not a hash" - when the provided key exists but is not a hash.
+func (server *SugarDB) HSetEx(key string, fieldValuePairs map[string]string, options HSetExOptions) (bool, error) {
+	cmd := []string{"HSETEX", key}
+
+	switch {
+	case options.FNX:
+		cmd = append(cmd, "FNX")
+	case options.FXX:
+		cmd = append(cmd, "FXX")
+	}
+
+	switch {
+	case options.ExpireOpt != nil:
+		cmd = append(cmd, fmt.Sprint(options.ExpireOpt), strconv.Itoa(options.ExpireTime))
+	case options.KeepTTL:
+		cmd = append(cmd, "KEEPTTL")
+	}
+
+	cmd = append(cmd, "FIELDS", strconv.Itoa(len(fieldValuePairs)))
+	for field, value := range fieldValuePairs {
+		cmd = append(cmd, field, value)
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// HSCANOptions modifies the behaviour of the HScan function.
+//
+// Match - string - only return fields that match this glob pattern.
+//
+// Count - uint - the number of fields to visit in this call. Defaults to 10 when 0.
+type HSCANOptions struct {
+	Match string
+	Count uint
+}
+
+// HScan incrementally iterates over the fields of a hash. Begin the iteration with a cursor of 0
+// and call HScan again with the returned cursor until it returns 0.
+//
+// Parameters:
+//
+// `key` - string - the key to the hash map.
+//
+// `cursor` - uint64 - the cursor returned by the previous call, or 0 to begin the iteration.
+//
+// `options` - HSCANOptions.
+//
+// Returns: The cursor to pass to the next call and a map of the fields visited in this call to their values.
+// The returned cursor is 0 when the iteration is complete.
+//
+// Errors:
+//
+// "value at <key> is not a hash" - when the provided key exists but is not a hash.
+func (server *SugarDB) HScan(key string, cursor uint64, options HSCANOptions) (uint64, map[string]string, error) {
+	cmd := append([]string{"HSCAN", key, strconv.FormatUint(cursor, 10)}, scanOptions(options.Match, options.Count)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, nil, err
+	}
+	next, elements, err := internal.ParseScanResponse(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	fields := make(map[string]string, len(elements)/2)
+	for i := 0; i+1 < len(elements); i += 2 {
+		fields[elements[i]] = elements[i+1]
+	}
+	return next, fields, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"reflect"
 	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -44,12 +45,12 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Return count of deleted fields in the specified hash",
 				key:  "hdel_key1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
 					"field7": {Value: "value7"},
-				},
+				}),
 				fields:  []string{"field1", "field2", "field3", "field4", "field5", "field6"},
 				want:    3,
 				wantErr: false,
@@ -57,11 +58,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "2. 0 response when passing delete fields that are non-existent on valid hash",
 				key:  "hdel_key2",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: "value2"},
 					"field3": {Value: "value3"},
-				},
+				}),
 				fields:  []string{"field4", "field5", "field6"},
 				want:    0,
 				wantErr: false,
@@ -118,11 +119,11 @@ func TestSugarDB_Hash(t *testing.T) {
 		}{
 			{
 				name: "1. Return 1 if the field exists in the hash",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				key:     "hexists_key1",
 				field:   "field1",
 				want:    true,
@@ -130,7 +131,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name:        "2. False response when trying to call HEXISTS on non-existent key",
-				presetValue: hash.Hash{},
+				presetValue: hash.NewHash(map[string]hash.HashValue{}),
 				key:         "hexists_key2",
 				field:       "field1",
 				want:        false,
@@ -180,18 +181,18 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Return an array containing all the fields and values of the hash",
 				key:  "hgetall_key1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				want:    []string{"field1", "value1", "field2", "123456789", "field3", "3.142"},
 				wantErr: false,
 			},
 			{
 				name:        "2. Empty array response when trying to call HGETALL on non-existent key",
 				key:         "hgetall_key2",
-				presetValue: hash.Hash{},
+				presetValue: hash.NewHash(map[string]hash.HashValue{}),
 				want:        []string{},
 				wantErr:     false,
 			},
@@ -273,7 +274,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name:          "3. Increment by integer on existing hash",
-				presetValue:   hash.Hash{"field1": {Value: 1}},
+				presetValue:   hash.NewHash(map[string]hash.HashValue{"field1": {Value: 1}}),
 				incr_type:     HINCRBY,
 				key:           "hincrby_key3",
 				field:         "field1",
@@ -283,7 +284,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name:            "4. Increment by float on an existing hash",
-				presetValue:     hash.Hash{"field1": {Value: 3.142}},
+				presetValue:     hash.NewHash(map[string]hash.HashValue{"field1": {Value: 3.142}}),
 				incr_type:       HINCRBYFLOAT,
 				key:             "hincrby_key4",
 				field:           "field1",
@@ -303,7 +304,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name:          "6. Error when trying to increment a hash field that is not a number",
-				presetValue:   hash.Hash{"field1": {Value: "value1"}},
+				presetValue:   hash.NewHash(map[string]hash.HashValue{"field1": {Value: "value1"}}),
 				incr_type:     HINCRBY,
 				key:           "hincrby_key10",
 				field:         "field1",
@@ -357,18 +358,18 @@ func TestSugarDB_Hash(t *testing.T) {
 		}{
 			{
 				name: "1. Return an array containing all the keys of the hash",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				key:     "hkeys_key1",
 				want:    []string{"field1", "field2", "field3"},
 				wantErr: false,
 			},
 			{
 				name:        "2. Empty array response when trying to call HKEYS on non-existent key",
-				presetValue: hash.Hash{},
+				presetValue: hash.NewHash(map[string]hash.HashValue{}),
 				key:         "hkeys_key2",
 				want:        []string{},
 				wantErr:     false,
@@ -420,11 +421,11 @@ func TestSugarDB_Hash(t *testing.T) {
 		}{
 			{
 				name: "1. Return the correct length of the hash",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				key:     "hlen_key1",
 				want:    3,
 				wantErr: false,
@@ -480,11 +481,11 @@ func TestSugarDB_Hash(t *testing.T) {
 		}{
 			{
 				name: "1. Get a random field",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				key:       "hrandfield_key1",
 				options:   HRandFieldOptions{Count: 1},
 				wantCount: 1,
@@ -493,11 +494,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name: "2. Get a random field with a value",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				key:       "hrandfield_key2",
 				options:   HRandFieldOptions{WithValues: true, Count: 1},
 				wantCount: 2,
@@ -506,13 +507,13 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name: "3. Get several random fields",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
 					"field4": {Value: "value4"},
 					"field5": {Value: "value6"},
-				},
+				}),
 				key:       "hrandfield_key3",
 				options:   HRandFieldOptions{Count: 3},
 				wantCount: 3,
@@ -521,13 +522,13 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name: "4. Get several random fields with their corresponding values",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
 					"field4": {Value: "value4"},
 					"field5": {Value: "value5"},
-				},
+				}),
 				key:       "hrandfield_key4",
 				options:   HRandFieldOptions{WithValues: true, Count: 3},
 				wantCount: 6,
@@ -539,13 +540,13 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name: "5. Get the entire hash",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
 					"field4": {Value: "value4"},
 					"field5": {Value: "value5"},
-				},
+				}),
 				key:       "hrandfield_key5",
 				options:   HRandFieldOptions{Count: 5},
 				wantCount: 5,
@@ -554,13 +555,13 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name: "6. Get the entire hash with values",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
 					"field4": {Value: "value4"},
 					"field5": {Value: "value5"},
-				},
+				}),
 				key:       "hrandfield_key6",
 				options:   HRandFieldOptions{WithValues: true, Count: 5},
 				wantCount: 10,
@@ -631,7 +632,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name:            "2. HSETNX set field on existing hash map",
 				key:             "hset_key2",
-				presetValue:     hash.Hash{"field1": {Value: "value1"}},
+				presetValue:     hash.NewHash(map[string]hash.HashValue{"field1": {Value: "value1"}}),
 				hsetFunc:        server.HSetNX,
 				fieldValuePairs: map[string]string{"field2": "value2"},
 				want:            1,
@@ -640,7 +641,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name:            "3. HSETNX skips operation when setting on existing field",
 				key:             "hset_key3",
-				presetValue:     hash.Hash{"field1": {Value: "value1"}},
+				presetValue:     hash.NewHash(map[string]hash.HashValue{"field1": {Value: "value1"}}),
 				hsetFunc:        server.HSetNX,
 				fieldValuePairs: map[string]string{"field1": "value1"},
 				want:            0,
@@ -658,7 +659,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name:            "5. Regular HSET update on existing hash map",
 				key:             "hset_key5",
-				presetValue:     hash.Hash{"field1": {Value: "value1"}, "field2": {Value: "value2"}},
+				presetValue:     hash.NewHash(map[string]hash.HashValue{"field1": {Value: "value1"}, "field2": {Value: "value2"}}),
 				fieldValuePairs: map[string]string{"field1": "value1-new", "field2": "value2-ne2", "field3": "value3"},
 				hsetFunc:        server.HSet,
 				want:            3,
@@ -711,11 +712,11 @@ func TestSugarDB_Hash(t *testing.T) {
 				// Return lengths of field values.
 				// If the key does not exist, its length should be 0.
 				name: "1. Return lengths of field values",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				key:     "hstrlen_key1",
 				fields:  []string{"field1", "field2", "field3", "field4"},
 				want:    []int{len("value1"), len("123456789"), len("3.142"), 0},
@@ -723,7 +724,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name:        "2. Response when trying to get HSTRLEN non-existent key",
-				presetValue: hash.Hash{},
+				presetValue: hash.NewHash(map[string]hash.HashValue{}),
 				key:         "hstrlen_key2",
 				fields:      []string{"field1"},
 				want:        []int{0},
@@ -732,7 +733,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name:        "3. Command too short",
 				key:         "hstrlen_key3",
-				presetValue: hash.Hash{},
+				presetValue: hash.NewHash(map[string]hash.HashValue{}),
 				fields:      []string{},
 				want:        nil,
 				wantErr:     true,
@@ -782,11 +783,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Return all the values from a hash",
 				key:  "hvals_key1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 123456789},
 					"field3": {Value: 3.142},
-				},
+				}),
 				want:    []string{"value1", "123456789", "3.142"},
 				wantErr: false,
 			},
@@ -846,11 +847,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Get values from existing hash.",
 				key:  "HgetKey1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:  []string{"field1", "field2", "field3", "field4"},
 				want:    []string{"value1", "365", "3.142", ""},
 				wantErr: false,
@@ -908,11 +909,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Get values from existing hash.",
 				key:  "HMgetKey1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:  []string{"field1", "field2", "field3", "field4"},
 				want:    []string{"value1", "365", "3.142", ""},
 				wantErr: false,
@@ -971,11 +972,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Set Expiration from existing hash.",
 				key:  "HExpireKey1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:  []string{"field1", "field2", "field3"},
 				want:    []int{1, 1, 1},
 				wantErr: false,
@@ -999,11 +1000,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "4. Set Expiration with option NX.",
 				key:  "HExpireKey4",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: NX,
 				want:         []int{1, 1, 1},
@@ -1012,11 +1013,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "5. Set Expiration with option XX.",
 				key:  "HExpireKey5",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: XX,
 				want:         []int{0, 0, 0},
@@ -1025,11 +1026,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "6. Set Expiration with option GT.",
 				key:  "HExpireKey6",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: GT,
 				want:         []int{0, 0, 0},
@@ -1038,11 +1039,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "7. Set Expiration with option LT.",
 				key:  "HExpireKey7",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: LT,
 				want:         []int{1, 1, 1},
@@ -1086,11 +1087,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Set Expiration from existing hash.",
 				key:  "HExpireAtKey1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:  []string{"field1", "field2", "field3"},
 				want:    []int{1, 1, 1},
 				wantErr: false,
@@ -1114,11 +1115,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "4. Set Expiration with option NX.",
 				key:  "HExpireAtKey4",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: NX,
 				want:         []int{1, 1, 1},
@@ -1127,11 +1128,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "5. Set Expiration with option XX.",
 				key:  "HExpireAtKey5",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: XX,
 				want:         []int{0, 0, 0},
@@ -1140,11 +1141,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "6. Set Expiration with option GT.",
 				key:  "HExpireAtKey6",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: GT,
 				want:         []int{0, 0, 0},
@@ -1153,11 +1154,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "7. Set Expiration with option LT.",
 				key:  "HExpireAtKey7",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:       []string{"field1", "field2", "field3"},
 				expireOption: LT,
 				want:         []int{1, 1, 1},
@@ -1200,20 +1201,20 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Get TTL for one field when expireTime is set.",
 				key:  "HTTL_Key1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1", ExpireAt: server.clock.Now().Add(time.Duration(500) * time.Second)},
-				},
+				}),
 				fields:  []string{"field1"},
 				want:    []int{500},
 				wantErr: false,
 			},
 			{
 				name: "2. Get TTL for multiple fields when expireTime is set.",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1", ExpireAt: server.clock.Now().Add(time.Duration(500) * time.Second)},
 					"field2": {Value: "value2", ExpireAt: server.clock.Now().Add(time.Duration(500) * time.Second)},
 					"field3": {Value: "value3", ExpireAt: server.clock.Now().Add(time.Duration(500) * time.Second)},
-				},
+				}),
 				key:     "HTTL_Key2",
 				fields:  []string{"field1", "field2", "field3"},
 				want:    []int{500, 500, 500},
@@ -1221,9 +1222,9 @@ func TestSugarDB_Hash(t *testing.T) {
 			},
 			{
 				name: "3. Get TTL for one field when expireTime is not set.",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
-				},
+				}),
 				key:     "HTTL_Key3",
 				fields:  []string{"field1"},
 				want:    []int{-1},
@@ -1232,11 +1233,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "4. Get TTL for multiple fields when expireTime is not set.",
 				key:  "HTTL_Key4",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": {Value: "value1"},
 					"field2": {Value: 365},
 					"field3": {Value: 3.142},
-				},
+				}),
 				fields:  []string{"field1", "field2", "field3"},
 				want:    []int{-1, -1, -1},
 				wantErr: false,
@@ -1298,11 +1299,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Get expiration time for one field",
 				key:  "HPExpireTime_Key1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "value1",
 					},
-				},
+				}),
 				fields:    []string{"field1"},
 				want:      []int64{fixedTimestamp},
 				wantErr:   false,
@@ -1311,7 +1312,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "2. Get expiration time for multiple fields",
 				key:  "HPExpireTime_Key2",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "value1",
 					},
@@ -1321,7 +1322,7 @@ func TestSugarDB_Hash(t *testing.T) {
 					"field3": hash.HashValue{
 						Value: "value3",
 					},
-				},
+				}),
 				fields:    []string{"field1", "field2", "field3"},
 				want:      []int64{fixedTimestamp, fixedTimestamp, fixedTimestamp},
 				wantErr:   false,
@@ -1330,14 +1331,14 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "3. Mix of existing and non-existing fields",
 				key:  "HPExpireTime_Key3",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "value1",
 					},
 					"field2": hash.HashValue{
 						Value: "value2",
 					},
-				},
+				}),
 				fields:    []string{"field1", "nonexistent", "field2"},
 				want:      []int64{fixedTimestamp, -2, fixedTimestamp},
 				wantErr:   false,
@@ -1346,10 +1347,10 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "4. Fields with no expiration set",
 				key:  "HPExpireTime_Key4",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{Value: "value1"},
 					"field2": hash.HashValue{Value: "value2"},
-				},
+				}),
 				fields:    []string{"field1", "field2"},
 				want:      []int64{-1, -1},
 				wantErr:   false,
@@ -1385,9 +1386,9 @@ func TestSugarDB_Hash(t *testing.T) {
 						return
 					}
 	
-					if hash, ok := tt.presetValue.(hash.Hash); ok && tt.setExpiry {
+					if hash, ok := tt.presetValue.(*hash.Hash); ok && tt.setExpiry {
 						for _, field := range tt.fields {
-							if hashValue, exists := hash[field]; exists && hashValue.Value != nil {
+							if hash.Get(field).Value != nil {
 								_, err := server.HExpire(tt.key, 500, noOption, field)
 								if err != nil {
 									t.Error(err)
@@ -1428,11 +1429,11 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "1. Get expiration time for one field",
 				key:  "HExpireTime_Key1",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "value1",
 					},
-				},
+				}),
 				fields:    []string{"field1"},
 				want:      []int64{fixedTimestamp},
 				wantErr:   false,
@@ -1441,7 +1442,7 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "2. Get expiration time for multiple fields",
 				key:  "HExpireTime_Key2",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "value1",
 					},
@@ -1451,7 +1452,7 @@ func TestSugarDB_Hash(t *testing.T) {
 					"field3": hash.HashValue{
 						Value: "value3",
 					},
-				},
+				}),
 				fields:    []string{"field1", "field2", "field3"},
 				want:      []int64{fixedTimestamp, fixedTimestamp, fixedTimestamp},
 				wantErr:   false,
@@ -1460,14 +1461,14 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "3. Mix of existing and non-existing fields",
 				key:  "HExpireTime_Key3",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{
 						Value: "value1",
 					},
 					"field2": hash.HashValue{
 						Value: "value2",
 					},
-				},
+				}),
 				fields:    []string{"field1", "nonexistent", "field2"},
 				want:      []int64{fixedTimestamp, -2, fixedTimestamp},
 				wantErr:   false,
@@ -1476,10 +1477,10 @@ func TestSugarDB_Hash(t *testing.T) {
 			{
 				name: "4. Fields with no expiration set",
 				key:  "HExpireTime_Key4",
-				presetValue: hash.Hash{
+				presetValue: hash.NewHash(map[string]hash.HashValue{
 					"field1": hash.HashValue{Value: "value1"},
 					"field2": hash.HashValue{Value: "value2"},
-				},
+				}),
 				fields:    []string{"field1", "field2"},
 				want:      []int64{-1, -1},
 				wantErr:   false,
@@ -1515,9 +1516,9 @@ func TestSugarDB_Hash(t *testing.T) {
 						return
 					}
 	
-					if hash, ok := tt.presetValue.(hash.Hash); ok && tt.setExpiry {
+					if hash, ok := tt.presetValue.(*hash.Hash); ok && tt.setExpiry {
 						for _, field := range tt.fields {
-							if hashValue, exists := hash[field]; exists && hashValue.Value != nil {
+							if hash.Get(field).Value != nil {
 								_, err := server.HExpire(tt.key, 500, noOption, field)
 								if err != nil {
 									t.Error(err)
@@ -1540,4 +1541,184 @@ func TestSugarDB_Hash(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_HSCAN", func(t *testing.T) {
+		t.Parallel()
+
+		want := make(map[string]string)
+		for i := 0; i < 20; i++ {
+			want["field"+strconv.Itoa(i)] = "value" + strconv.Itoa(i)
+		}
+		if _, err := server.HSet("hscan_key1", want); err != nil {
+			t.Error(err)
+			return
+		}
+
+		got := make(map[string]string)
+		var cursor uint64
+		for {
+			next, fields, err := server.HScan("hscan_key1", cursor, HSCANOptions{Count: 3})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for field, value := range fields {
+				got[field] = value
+			}
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("HSCAN() got = %v, want %v", got, want)
+		}
+
+		if _, _, err := server.HScan("hscan_key2", 0, HSCANOptions{}); err != nil {
+			t.Errorf("HSCAN() on non-existent key returned error %v", err)
+		}
+	})
+
+	t.Run("TestSugarDB_HashFieldExpiry", func(t *testing.T) {
+		t.Parallel()
+
+		key := "hash_field_expiry_key1"
+		if _, err := server.HSet(key, map[string]string{"field1": "value1", "field2": "value2"}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if got, err := server.HPExpire(key, 4000, nil, "field1", "field3"); err != nil || !reflect.DeepEqual(got, []int{1, -2}) {
+			t.Errorf("HPExpire() got = %v, err = %v, want [1 -2]", got, err)
+		}
+		if got, err := server.HPTTL(key, "field1", "field2", "field3"); err != nil || !reflect.DeepEqual(got, []int{4000, -1, -2}) {
+			t.Errorf("HPTTL() got = %v, err = %v, want [4000 -1 -2]", got, err)
+		}
+
+		expireAt := int(server.clock.Now().Add(8 * time.Second).UnixMilli())
+		if got, err := server.HPExpireAt(key, expireAt, GT, "field1"); err != nil || !reflect.DeepEqual(got, []int{1}) {
+			t.Errorf("HPExpireAt() got = %v, err = %v, want [1]", got, err)
+		}
+		if got, err := server.HTTL(key, "field1"); err != nil || !reflect.DeepEqual(got, []int{8}) {
+			t.Errorf("HTTL() got = %v, err = %v, want [8]", got, err)
+		}
+
+		if got, err := server.HPersist(key, "field1", "field2", "field3"); err != nil || !reflect.DeepEqual(got, []int{1, -1, -2}) {
+			t.Errorf("HPersist() got = %v, err = %v, want [1 -1 -2]", got, err)
+		}
+
+		if got, err := server.HGetEx(key, EX, 30, "field1", "field3"); err != nil || !reflect.DeepEqual(got, []string{"value1", ""}) {
+			t.Errorf("HGetEx() got = %v, err = %v, want [value1 ]", got, err)
+		}
+		if got, err := server.HTTL(key, "field1"); err != nil || !reflect.DeepEqual(got, []int{30}) {
+			t.Errorf("HTTL() got = %v, err = %v, want [30]", got, err)
+		}
+		if _, err := server.HGetEx(key, PERSIST, 0, "field1"); err != nil {
+			t.Error(err)
+		}
+		if got, err := server.HTTL(key, "field1"); err != nil || !reflect.DeepEqual(got, []int{-1}) {
+			t.Errorf("HTTL() got = %v, err = %v, want [-1]", got, err)
+		}
+
+		if got, err := server.HGetDel(key, "field1", "field2"); err != nil || !reflect.DeepEqual(got, []string{"value1", "value2"}) {
+			t.Errorf("HGetDel() got = %v, err = %v, want [value1 value2]", got, err)
+		}
+		if got, err := server.Exists(key); err != nil || got != 0 {
+			t.Errorf("expected HGetDel to delete the empty hash, Exists() got = %v, err = %v", got, err)
+		}
+	})
+
+	t.Run("TestSugarDB_HSETEX", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			key         string
+			fields      map[string]string
+			options     HSetExOptions
+			want        bool
+			wantTTL     []int
+			wantErr     bool
+		}{
+			{
+				name:    "1. Create a new hash with an expiry on its fields",
+				key:     "hsetex_key1",
+				fields:  map[string]string{"field1": "value1", "field2": "value2"},
+				options: HSetExOptions{ExpireOpt: SETEX, ExpireTime: 20},
+				want:    true,
+				wantTTL: []int{20, 20},
+			},
+			{
+				name: "2. FNX fails when one of the fields exists",
+				key:  "hsetex_key2",
+				presetValue: hash.NewHash(map[string]hash.HashValue{
+					"field1": {Value: "value1"},
+				}),
+				fields:  map[string]string{"field1": "new1", "field2": "new2"},
+				options: HSetExOptions{FNX: true, ExpireOpt: SETEX, ExpireTime: 20},
+				want:    false,
+				wantTTL: []int{-1, -2},
+			},
+			{
+				name: "3. FXX with KEEPTTL updates existing fields and keeps their expiry",
+				key:  "hsetex_key3",
+				presetValue: hash.NewHash(map[string]hash.HashValue{
+					"field1": {Value: "value1", ExpireAt: server.clock.Now().Add(50 * time.Second)},
+					"field2": {Value: "value2"},
+				}),
+				fields:  map[string]string{"field1": "new1", "field2": "new2"},
+				options: HSetExOptions{FXX: true, KeepTTL: true},
+				want:    true,
+				wantTTL: []int{50, -1},
+			},
+			{
+				name: "4. Without expiry options the expiry of the fields is removed",
+				key:  "hsetex_key4",
+				presetValue: hash.NewHash(map[string]hash.HashValue{
+					"field1": {Value: "value1", ExpireAt: server.clock.Now().Add(50 * time.Second)},
+				}),
+				fields:  map[string]string{"field1": "new1", "field2": "new2"},
+				want:    true,
+				wantTTL: []int{-1, -1},
+			},
+			{
+				name:        "5. Return error when the key is not a hash",
+				key:         "hsetex_key5",
+				presetValue: "not a hash",
+				fields:      map[string]string{"field1": "value1"},
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				if tt.presetValue != nil {
+					err := presetValue(server, context.Background(), tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.HSetEx(tt.key, tt.fields, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("HSetEx() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("HSetEx() got = %v, want %v", got, tt.want)
+				}
+				if tt.wantErr {
+					return
+				}
+				ttl, err := server.HTTL(tt.key, "field1", "field2")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !reflect.DeepEqual(ttl, tt.wantTTL) {
+					t.Errorf("HTTL() got = %v, want %v", ttl, tt.wantTTL)
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,88 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"github.com/echovault/sugardb/internal"
+	"strings"
+)
+
+// PFAdd adds the elements to the HyperLogLog at the key. If the key does not exist, a new HyperLogLog is created.
+//
+// Parameters:
+//
+// `key` - string - the key to update.
+//
+// `elements` - ...string - the elements to add to the HyperLogLog.
+//
+// Returns: true if the key was created or its estimated cardinality changed, otherwise false.
+//
+// Errors:
+//
+// "value at <key> is not a hyperloglog" - when the provided key exists but is not a HyperLogLog.
+func (server *SugarDB) PFAdd(key string, elements ...string) (bool, error) {
+	cmd := append([]string{"PFADD", key}, elements...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// PFCount returns the estimated number of distinct elements added to the HyperLogLog at the key.
+// When multiple keys are provided, the estimated cardinality of the union of the HyperLogLogs is returned.
+// The estimate has a standard error of 0.81%.
+//
+// Parameters:
+//
+// `keys` - ...string - the keys of the HyperLogLogs. Keys that don't exist are treated as empty HyperLogLogs.
+//
+// Returns: The estimated cardinality.
+//
+// Errors:
+//
+// "value at <key> is not a hyperloglog" - when one of the provided keys exists but is not a HyperLogLog.
+func (server *SugarDB) PFCount(keys ...string) (int, error) {
+	cmd := append([]string{"PFCOUNT"}, keys...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// PFMerge merges the HyperLogLogs at the source keys into the destination key. If the destination exists, it's
+// merged with the sources.
+//
+// Parameters:
+//
+// `destination` - string - the key to store the merged HyperLogLog at.
+//
+// `sources` - ...string - the keys of the HyperLogLogs to merge. Keys that don't exist are skipped.
+//
+// Returns: true if the merge was successful.
+//
+// Errors:
+//
+// "value at <key> is not a hyperloglog" - when one of the provided keys exists but is not a HyperLogLog.
+func (server *SugarDB) PFMerge(destination string, sources ...string) (bool, error) {
+	cmd := append([]string{"PFMERGE", destination}, sources...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/echovault/sugardb/internal/modules/hyperloglog"
+)
+
+func TestSugarDB_HyperLogLog(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	elements := func(offset, count int) []string {
+		res := make([]string, count)
+		for i := range res {
+			res[i] = fmt.Sprintf("element%d", offset+i)
+		}
+		return res
+	}
+
+	t.Run("TestSugarDB_PFADD", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			key         string
+			elements    []string
+			want        bool
+			wantErr     bool
+		}{
+			{
+				name:     "1. Create new HyperLogLog on a non-existent key",
+				key:      "pfadd_key1",
+				elements: []string{"a", "b", "c"},
+				want:     true,
+				wantErr:  false,
+			},
+			{
+				name: "2. Return false when the elements don't change the HyperLogLog",
+				presetValue: func() *hyperloglog.HyperLogLog {
+					hll := hyperloglog.NewHyperLogLog()
+					hll.Add([]string{"a", "b", "c"})
+					return hll
+				}(),
+				key:      "pfadd_key2",
+				elements: []string{"c", "a"},
+				want:     false,
+				wantErr:  false,
+			},
+			{
+				name:        "3. Throw error when the key does not hold a HyperLogLog",
+				presetValue: "Default value",
+				key:         "pfadd_key3",
+				elements:    []string{"a"},
+				want:        false,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.presetValue != nil {
+					err := presetValue(server, server.context, tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.PFAdd(tt.key, tt.elements...)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("PFADD() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("PFADD() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_PFCOUNT", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.PFAdd("pfcount_key1", elements(0, 5000)...); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := server.PFAdd("pfcount_key2", elements(2500, 5000)...); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := presetValue(server, server.context, "pfcount_key3", "Default value"); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name    string
+			keys    []string
+			want    int
+			wantErr bool
+		}{
+			{
+				name: "1. Return 0 for a non-existent key",
+				keys: []string{"pfcount_key4"},
+				want: 0,
+			},
+			{
+				name: "2. Estimate the cardinality of one HyperLogLog",
+				keys: []string{"pfcount_key1"},
+				want: 5000,
+			},
+			{
+				name: "3. Estimate the cardinality of the union of multiple HyperLogLogs",
+				keys: []string{"pfcount_key1", "pfcount_key2", "pfcount_key4"},
+				want: 7500,
+			},
+			{
+				name:    "4. Throw error when one of the keys does not hold a HyperLogLog",
+				keys:    []string{"pfcount_key1", "pfcount_key3"},
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := server.PFCount(tt.keys...)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("PFCOUNT() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				// Allow for 3 standard errors.
+				if margin := float64(tt.want) * 3 * 0.0081; float64(got) < float64(tt.want)-margin ||
+					float64(got) > float64(tt.want)+margin {
+					t.Errorf("PFCOUNT() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_PFMERGE", func(t *testing.T) {
+		t.Parallel()
+
+		for key, elems := range map[string][]string{
+			"pfmerge_key1":  {"a", "b", "c"},
+			"pfmerge_key2":  {"c", "d"},
+			"pfmerge_dest2": {"x"},
+		} {
+			if _, err := server.PFAdd(key, elems...); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if err := presetValue(server, server.context, "pfmerge_key3", "Default value"); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name        string
+			destination string
+			sources     []string
+			wantCount   int
+			wantErr     bool
+		}{
+			{
+				name:        "1. Merge the sources into a non-existent destination",
+				destination: "pfmerge_dest1",
+				sources:     []string{"pfmerge_key1", "pfmerge_key2", "pfmerge_key4"},
+				wantCount:   4,
+			},
+			{
+				name:        "2. Merge the sources into an existing destination",
+				destination: "pfmerge_dest2",
+				sources:     []string{"pfmerge_key1"},
+				wantCount:   4,
+			},
+			{
+				name:        "3. Throw error when a source does not hold a HyperLogLog",
+				destination: "pfmerge_dest3",
+				sources:     []string{"pfmerge_key1", "pfmerge_key3"},
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ok, err := server.PFMerge(tt.destination, tt.sources...)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("PFMERGE() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				if !ok {
+					t.Errorf("PFMERGE() got = %v, want %v", ok, true)
+				}
+				count, err := server.PFCount(tt.destination)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if count != tt.wantCount {
+					t.Errorf("PFCOUNT() got = %v, want %v", count, tt.wantCount)
+				}
+			})
+		}
+	})
+
+}
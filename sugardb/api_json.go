@@ -0,0 +1,581 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// JSONSetOptions modifies the behaviour of the JSONSet function.
+//
+// NX - bool - only set the value if the path doesn't exist.
+//
+// XX - bool - only set the value if the path exists.
+type JSONSetOptions struct {
+	NX bool
+	XX bool
+}
+
+// JSONGetOptions modifies the formatting of the document returned by the JSONGet function.
+//
+// Indent - string - the string used to indent each level of nesting.
+//
+// Newline - string - the string written at the end of each line.
+//
+// Space - string - the string written between a key and its value.
+type JSONGetOptions struct {
+	Indent  string
+	Newline string
+	Space   string
+}
+
+// parseNullableIntegerResponse parses a reply that is either an integer or an array of integers and nils.
+// A single integer is returned as a slice with one element, and nils are returned as nil pointers.
+func parseNullableIntegerResponse(b []byte) ([]*int, error) {
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := res.([]interface{})
+	if !ok {
+		values = []interface{}{res}
+	}
+	results := make([]*int, len(values))
+	for i, value := range values {
+		if n, ok := value.(int); ok {
+			results[i] = &n
+		}
+	}
+	return results, nil
+}
+
+// JSONSet sets the JSON value at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path to set. A new key must be set at the root path, "$" or ".".
+// If the path doesn't exist and its last element is an object key, the key is added to the parent object.
+//
+// `value` - string - the JSON value to set.
+//
+// `options` - JSONSetOptions.
+//
+// Returns: true if the value was set, or false if the NX or XX condition was not met or the path didn't match.
+//
+// Errors:
+//
+// "value at key <key> is not a JSON document" - when the key exists but is not a JSON document.
+//
+// "new objects must be created at the root" - when the key doesn't exist and the path is not the root.
+func (server *SugarDB) JSONSet(key, path, value string, options JSONSetOptions) (bool, error) {
+	cmd := []string{"JSON.SET", key, path, value}
+	switch {
+	case options.NX:
+		cmd = append(cmd, "NX")
+	case options.XX:
+		cmd = append(cmd, "XX")
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// JSONGet returns the JSON values at the paths in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `options` - JSONGetOptions.
+//
+// `paths` - ...string - the paths to read. Defaults to the root. JSONPath queries return an array of all
+// matches, and legacy paths return the first match. With multiple paths, returns an object keyed by path.
+//
+// Returns: the JSON text, or an empty string if the key doesn't exist.
+//
+// Errors:
+//
+// "value at key <key> is not a JSON document" - when the key exists but is not a JSON document.
+//
+// "path <path> does not exist" - when a legacy path doesn't match any value.
+func (server *SugarDB) JSONGet(key string, options JSONGetOptions, paths ...string) (string, error) {
+	cmd := []string{"JSON.GET", key}
+	if options.Indent != "" {
+		cmd = append(cmd, "INDENT", options.Indent)
+	}
+	if options.Newline != "" {
+		cmd = append(cmd, "NEWLINE", options.Newline)
+	}
+	if options.Space != "" {
+		cmd = append(cmd, "SPACE", options.Space)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append(cmd, paths...)), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// JSONMGet returns the JSON values at the path in the document at each key.
+//
+// Parameters:
+//
+// `path` - string - the path to read.
+//
+// `keys` - ...string - the keys of the documents.
+//
+// Returns: the JSON text for each key, in the order of the keys. Keys that don't exist or don't hold a
+// document return an empty string.
+func (server *SugarDB) JSONMGet(path string, keys ...string) ([]string, error) {
+	cmd := append(append([]string{"JSON.MGET"}, keys...), path)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// JSONDel deletes the values at the path in the document at the key. Deleting the root deletes the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path to delete.
+//
+// Returns: the number of values deleted.
+//
+// Errors:
+//
+// "value at key <key> is not a JSON document" - when the key exists but is not a JSON document.
+func (server *SugarDB) JSONDel(key, path string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.DEL", key, path}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// JSONType returns the types of the values at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path to read.
+//
+// Returns: the type of each value matched by the path. One of "object", "array", "string", "integer",
+// "number", "boolean" and "null".
+//
+// Errors:
+//
+// "value at key <key> is not a JSON document" - when the key exists but is not a JSON document.
+func (server *SugarDB) JSONType(key, path string) ([]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.TYPE", key, path}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// JSONNumIncrBy increments the numbers at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the numbers.
+//
+// `increment` - float64 - the amount to increment by.
+//
+// Returns: the new values as JSON text. JSONPath queries return an array with null for values that aren't numbers.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "expected number but found <type> at path <path>" - when a legacy path doesn't match a number.
+func (server *SugarDB) JSONNumIncrBy(key, path string, increment float64) (string, error) {
+	return server.jsonNumOp("JSON.NUMINCRBY", key, path, increment)
+}
+
+// JSONNumMultBy multiplies the numbers at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the numbers.
+//
+// `multiplier` - float64 - the amount to multiply by.
+//
+// Returns: the new values as JSON text. JSONPath queries return an array with null for values that aren't numbers.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "expected number but found <type> at path <path>" - when a legacy path doesn't match a number.
+func (server *SugarDB) JSONNumMultBy(key, path string, multiplier float64) (string, error) {
+	return server.jsonNumOp("JSON.NUMMULTBY", key, path, multiplier)
+}
+
+func (server *SugarDB) jsonNumOp(command, key, path string, operand float64) (string, error) {
+	cmd := []string{command, key, path, strconv.FormatFloat(operand, 'f', -1, 64)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// JSONStrAppend appends the JSON string value to the strings at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the strings.
+//
+// `value` - string - the JSON string to append, including its quotes.
+//
+// Returns: the new length of each string matched by the path, or nil for values that aren't strings.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "expected string but found <type> at path <path>" - when a legacy path doesn't match a string.
+func (server *SugarDB) JSONStrAppend(key, path, value string) ([]*int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.STRAPPEND", key, path, value}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONStrLen returns the length of the strings at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the strings.
+//
+// Returns: the length of each string matched by the path, or nil for values that aren't strings.
+//
+// Errors:
+//
+// "expected string but found <type> at path <path>" - when a legacy path doesn't match a string.
+func (server *SugarDB) JSONStrLen(key, path string) ([]*int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.STRLEN", key, path}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONArrAppend appends the JSON values to the arrays at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the arrays.
+//
+// `values` - ...string - the JSON values to append.
+//
+// Returns: the new length of each array matched by the path, or nil for values that aren't arrays.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "expected array but found <type> at path <path>" - when a legacy path doesn't match an array.
+func (server *SugarDB) JSONArrAppend(key, path string, values ...string) ([]*int, error) {
+	cmd := append([]string{"JSON.ARRAPPEND", key, path}, values...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONArrInsert inserts the JSON values before the index of the arrays at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the arrays.
+//
+// `index` - int - the index to insert at. A negative index counts from the end of the array.
+//
+// `values` - ...string - the JSON values to insert.
+//
+// Returns: the new length of each array matched by the path, or nil for values that aren't arrays.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "index out of bounds" - when the index is outside an array.
+func (server *SugarDB) JSONArrInsert(key, path string, index int, values ...string) ([]*int, error) {
+	cmd := append([]string{"JSON.ARRINSERT", key, path, strconv.Itoa(index)}, values...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONArrLen returns the length of the arrays at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the arrays.
+//
+// Returns: the length of each array matched by the path, or nil for values that aren't arrays.
+//
+// Errors:
+//
+// "expected array but found <type> at path <path>" - when a legacy path doesn't match an array.
+func (server *SugarDB) JSONArrLen(key, path string) ([]*int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.ARRLEN", key, path}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONArrPop removes and returns the element at the index of the arrays at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the arrays.
+//
+// `index` - int - the index of the element. -1 is the last element. The index is clamped to the array.
+//
+// Returns: the JSON text of each removed element, or an empty string for values that aren't arrays
+// and empty arrays.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "expected array but found <type> at path <path>" - when a legacy path doesn't match an array.
+func (server *SugarDB) JSONArrPop(key, path string, index int) ([]string, error) {
+	cmd := []string{"JSON.ARRPOP", key, path, strconv.Itoa(index)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// JSONArrIndex returns the index of the first occurrence of the JSON value in the arrays at the path in the
+// document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the arrays.
+//
+// `value` - string - the JSON value to search for.
+//
+// `start` - int - the index to start searching from.
+//
+// `stop` - int - the index to stop searching at, exclusive. 0 searches to the end of the array.
+//
+// Returns: the index in each array matched by the path, -1 if the value doesn't occur, or nil for values
+// that aren't arrays.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) JSONArrIndex(key, path, value string, start, stop int) ([]*int, error) {
+	cmd := []string{"JSON.ARRINDEX", key, path, value, strconv.Itoa(start), strconv.Itoa(stop)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONArrTrim trims the arrays at the path in the document at the key to the elements from start to stop,
+// inclusive.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the arrays.
+//
+// `start` - int - the index of the first element to keep.
+//
+// `stop` - int - the index of the last element to keep.
+//
+// Returns: the new length of each array matched by the path, or nil for values that aren't arrays.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) JSONArrTrim(key, path string, start, stop int) ([]*int, error) {
+	cmd := []string{"JSON.ARRTRIM", key, path, strconv.Itoa(start), strconv.Itoa(stop)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONObjKeys returns the keys of the objects at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the objects.
+//
+// Returns: the keys of each object matched by the path, in insertion order. Values that aren't objects
+// return nil.
+//
+// Errors:
+//
+// "expected object but found <type> at path <path>" - when a legacy path doesn't match an object.
+func (server *SugarDB) JSONObjKeys(key, path string) ([][]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.OBJKEYS", key, path}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, _ := res.([]interface{})
+	// A legacy path replies with the keys of a single object.
+	if len(values) > 0 {
+		if _, ok := values[0].(string); ok {
+			values = []interface{}{values}
+		}
+	}
+	keys := make([][]string, len(values))
+	for i, value := range values {
+		if arr, ok := value.([]interface{}); ok {
+			keys[i] = make([]string, len(arr))
+			for j, k := range arr {
+				keys[i][j] = k.(string)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// JSONObjLen returns the number of keys in the objects at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path of the objects.
+//
+// Returns: the number of keys in each object matched by the path, or nil for values that aren't objects.
+//
+// Errors:
+//
+// "expected object but found <type> at path <path>" - when a legacy path doesn't match an object.
+func (server *SugarDB) JSONObjLen(key, path string) ([]*int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.OBJLEN", key, path}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseNullableIntegerResponse(b)
+}
+
+// JSONClear empties the arrays and objects and sets the numbers at the path in the document at the key to 0.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - the path to clear.
+//
+// Returns: the number of values cleared.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) JSONClear(key, path string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.CLEAR", key, path}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// JSONToggle toggles the booleans at the path in the document at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the document.
+//
+// `path` - string - a JSONPath query for the booleans.
+//
+// Returns: the new value of each boolean matched by the path, or nil for values that aren't booleans.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) JSONToggle(key, path string) ([]*bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"JSON.TOGGLE", key, path}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := res.([]interface{})
+	if !ok {
+		values = []interface{}{res}
+	}
+	results := make([]*bool, len(values))
+	for i, value := range values {
+		var toggled bool
+		switch v := value.(type) {
+		case int:
+			toggled = v == 1
+		case string:
+			toggled = v == "true"
+		default:
+			continue
+		}
+		results[i] = &toggled
+	}
+	return results, nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intPointers(values ...int) []*int {
+	pointers := make([]*int, len(values))
+	for i, value := range values {
+		if value >= 0 {
+			v := value
+			pointers[i] = &v
+		}
+	}
+	return pointers
+}
+
+func TestSugarDB_JSON(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	t.Run("TestSugarDB_JSONSET", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			key         string
+			path        string
+			value       string
+			options     JSONSetOptions
+			want        bool
+			wantDoc     string
+			wantErr     bool
+		}{
+			{
+				name:    "1. Create a document at the root",
+				key:     "json_set_key1",
+				path:    "$",
+				value:   `{"a":1,"b":[true,null]}`,
+				want:    true,
+				wantDoc: `{"a":1,"b":[true,null]}`,
+				wantErr: false,
+			},
+			{
+				name:    "2. Return false when XX is set and the key doesn't exist",
+				key:     "json_set_key2",
+				path:    "$",
+				value:   `{}`,
+				options: JSONSetOptions{XX: true},
+				want:    false,
+				wantDoc: "",
+				wantErr: false,
+			},
+			{
+				name:    "3. Throw error when a new key is not set at the root",
+				key:     "json_set_key3",
+				path:    "$.a",
+				value:   `1`,
+				want:    false,
+				wantErr: true,
+			},
+			{
+				name:        "4. Throw error when the key does not hold a document",
+				presetValue: "Default value",
+				key:         "json_set_key4",
+				path:        "$",
+				value:       `1`,
+				want:        false,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.presetValue != nil {
+					err := presetValue(server, server.context, tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.JSONSet(tt.key, tt.path, tt.value, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("JSONSET() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("JSONSET() got = %v, want %v", got, tt.want)
+				}
+				if tt.wantErr {
+					return
+				}
+				doc, err := server.JSONGet(tt.key, JSONGetOptions{})
+				if err != nil || doc != tt.wantDoc {
+					t.Errorf("JSONGET() got = %v, want %v, error = %v", doc, tt.wantDoc, err)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_JSONDocument", func(t *testing.T) {
+		t.Parallel()
+
+		key := "json_doc_key1"
+		if ok, err := server.JSONSet(key, "$", `{"name":"sugar","tags":["a"],"stats":{"n":1,"on":false}}`, JSONSetOptions{}); !ok || err != nil {
+			t.Errorf("JSONSET() got = %v, error = %v", ok, err)
+			return
+		}
+
+		if got, err := server.JSONGet(key, JSONGetOptions{}, "$.name", ".stats.n"); err != nil || got != `{"$.name":["sugar"],".stats.n":1}` {
+			t.Errorf("JSONGET() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONType(key, "$.*"); err != nil || !reflect.DeepEqual(got, []string{"string", "array", "object"}) {
+			t.Errorf("JSONTYPE() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONNumIncrBy(key, "$.stats.n", 2); err != nil || got != "[3]" {
+			t.Errorf("JSONNUMINCRBY() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONNumMultBy(key, ".stats.n", 1.5); err != nil || got != "4.5" {
+			t.Errorf("JSONNUMMULTBY() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONStrAppend(key, "$.*", `"db"`); err != nil || !reflect.DeepEqual(got, intPointers(7, -1, -1)) {
+			t.Errorf("JSONSTRAPPEND() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONStrLen(key, ".name"); err != nil || !reflect.DeepEqual(got, intPointers(7)) {
+			t.Errorf("JSONSTRLEN() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONArrAppend(key, "$.tags", `"c"`, `"d"`); err != nil || !reflect.DeepEqual(got, intPointers(3)) {
+			t.Errorf("JSONARRAPPEND() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONArrInsert(key, "$.tags", 1, `"b"`); err != nil || !reflect.DeepEqual(got, intPointers(4)) {
+			t.Errorf("JSONARRINSERT() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONArrIndex(key, "$.tags", `"c"`, 0, 0); err != nil || !reflect.DeepEqual(got, intPointers(2)) {
+			t.Errorf("JSONARRINDEX() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONArrPop(key, "$.tags", -1); err != nil || !reflect.DeepEqual(got, []string{`"d"`}) {
+			t.Errorf("JSONARRPOP() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONArrTrim(key, "$.tags", 1, 1); err != nil || !reflect.DeepEqual(got, intPointers(1)) {
+			t.Errorf("JSONARRTRIM() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONArrLen(key, "$.*"); err != nil || !reflect.DeepEqual(got, intPointers(-1, 1, -1)) {
+			t.Errorf("JSONARRLEN() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONObjKeys(key, "$"); err != nil || !reflect.DeepEqual(got, [][]string{{"name", "tags", "stats"}}) {
+			t.Errorf("JSONOBJKEYS() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONObjKeys(key, ".stats"); err != nil || !reflect.DeepEqual(got, [][]string{{"n", "on"}}) {
+			t.Errorf("JSONOBJKEYS() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONObjLen(key, "$..stats"); err != nil || !reflect.DeepEqual(got, intPointers(2)) {
+			t.Errorf("JSONOBJLEN() got = %v, error = %v", got, err)
+		}
+		toggled := true
+		if got, err := server.JSONToggle(key, "$.stats.on"); err != nil || !reflect.DeepEqual(got, []*bool{&toggled}) {
+			t.Errorf("JSONTOGGLE() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONGet(key, JSONGetOptions{}); err != nil ||
+			got != `{"name":"sugardb","tags":["b"],"stats":{"n":4.5,"on":true}}` {
+			t.Errorf("JSONGET() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONClear(key, "$.*"); err != nil || got != 2 {
+			t.Errorf("JSONCLEAR() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONDel(key, "$.name"); err != nil || got != 1 {
+			t.Errorf("JSONDEL() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONMGet("$", key, "json_doc_key2"); err != nil ||
+			!reflect.DeepEqual(got, []string{`[{"tags":[],"stats":{}}]`, ""}) {
+			t.Errorf("JSONMGET() got = %v, error = %v", got, err)
+		}
+		if got, err := server.JSONDel(key, "$"); err != nil || got != 1 {
+			t.Errorf("JSONDEL() got = %v, error = %v", got, err)
+		}
+		if got, err := server.Exists(key); err != nil || got != 0 {
+			t.Errorf("EXISTS() got = %v, error = %v", got, err)
+		}
+	})
+
+}
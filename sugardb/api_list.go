@@ -18,8 +18,39 @@ import (
 	"github.com/echovault/sugardb/internal"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// LPosOptions allows you to modify the result of the LPos command.
+//
+// Rank skips the first Rank-1 matches. A negative Rank searches from the end of the list. A Rank of 0 is
+// treated as 1.
+//
+// Count specifies the maximum number of matches to return. When Count is 0, only the first match is returned.
+//
+// All returns all the matches and takes precedence over Count.
+//
+// MaxLen limits the number of elements that are compared. A MaxLen of 0 compares all the elements.
+type LPosOptions struct {
+	Rank   int
+	Count  uint
+	All    bool
+	MaxLen uint
+}
+
+// LMPopOptions allows you to modify the result of the LMPop command.
+//
+// Left instructs SugarDB to pop the elements from the beginning of the list. Left is higher priority than Right.
+//
+// Right instructs SugarDB to pop the elements from the end of the list.
+//
+// Count specifies the number of elements to pop.
+type LMPopOptions struct {
+	Left  bool
+	Right bool
+	Count uint
+}
+
 // LLen returns the length of the list.
 //
 // Parameters:
@@ -320,3 +351,272 @@ func (server *SugarDB) RPushX(key string, values ...string) (int, error) {
 	}
 	return internal.ParseIntegerResponse(b)
 }
+
+// BLPop removes and returns the first element of the first non-empty list. If all the lists are empty,
+// it blocks until an element is pushed to one of the lists or until the timeout elapses.
+//
+// Parameters:
+//
+// `timeout` - time.Duration - the maximum time to block for. A timeout of 0 blocks indefinitely.
+//
+// `keys` - ...string - the keys to the lists, checked in order.
+//
+// Returns: The key of the list the element was popped from and the element. Empty strings are returned
+// if the timeout elapses.
+//
+// Errors:
+//
+// "BLPOP command on non-list item" - when a key exists but is not a list.
+func (server *SugarDB) BLPop(timeout time.Duration, keys ...string) (string, string, error) {
+	return server.blockingPop("BLPOP", timeout, keys)
+}
+
+// BRPop works like BLPop but removes and returns the last element of the list.
+func (server *SugarDB) BRPop(timeout time.Duration, keys ...string) (string, string, error) {
+	return server.blockingPop("BRPOP", timeout, keys)
+}
+
+func (server *SugarDB) blockingPop(command string, timeout time.Duration, keys []string) (string, string, error) {
+	cmd := append(append([]string{command}, keys...), strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64))
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", "", err
+	}
+	res, err := internal.ParseStringArrayResponse(b)
+	if err != nil || len(res) != 2 {
+		return "", "", err
+	}
+	return res[0], res[1], nil
+}
+
+// BLMove moves an element from the source list to the destination list. The destination list is created if it
+// does not exist. If the source list is empty, it blocks until an element is pushed to it or until the timeout elapses.
+//
+// Parameters:
+//
+// `source` - string - the key to the source list.
+//
+// `destination` - string - the key to the destination list.
+//
+// `whereFrom` - string - either "LEFT" or "RIGHT". The side of the source list the element is removed from.
+//
+// `whereTo` - string - either "LEFT" or "RIGHT". The side of the destination list the element is added to.
+//
+// `timeout` - time.Duration - the maximum time to block for. A timeout of 0 blocks indefinitely.
+//
+// Returns: The moved element. An empty string is returned if the timeout elapses.
+//
+// Errors:
+//
+// "both source and destination must be lists" - when either source or destination exist but are not lists.
+//
+// "wherefrom and whereto arguments must be either LEFT or RIGHT" - if whereFrom or whereTo are not either "LEFT" or "RIGHT".
+func (server *SugarDB) BLMove(source, destination, whereFrom, whereTo string, timeout time.Duration) (string, error) {
+	cmd := []string{"BLMOVE", source, destination, whereFrom, whereTo, strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// LPushCap works like LPush, but caps the length of the list at maxLen after the push by dropping elements from
+// the end of the list. This allows the list to be used as a bounded buffer without a separate LTrim call.
+//
+// Parameters:
+//
+// `key` - string - the key to the list.
+//
+// `maxLen` - uint - the maximum length of the list after the push. Must be greater than 0.
+//
+// `values` - ...string - the list of elements to push to the beginning of the list.
+//
+// Returns: An integer with the length of the new list.
+//
+// Errors:
+//
+// "LPUSH command on non-list item" - when the provided key is not a list.
+//
+// "maxlen must be a positive integer" - when maxLen is 0.
+func (server *SugarDB) LPushCap(key string, maxLen uint, values ...string) (int, error) {
+	cmd := append([]string{"LPUSHCAP", key, strconv.Itoa(int(maxLen))}, values...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// RPushCap works like RPush, but caps the length of the list at maxLen after the push by dropping elements from
+// the beginning of the list. This allows the list to be used as a bounded buffer without a separate LTrim call.
+//
+// Parameters:
+//
+// `key` - string - the key to the list.
+//
+// `maxLen` - uint - the maximum length of the list after the push. Must be greater than 0.
+//
+// `values` - ...string - the list of elements to push to the end of the list.
+//
+// Returns: An integer with the length of the new list.
+//
+// Errors:
+//
+// "RPUSH command on non-list item" - when the provided key is not a list.
+//
+// "maxlen must be a positive integer" - when maxLen is 0.
+func (server *SugarDB) RPushCap(key string, maxLen uint, values ...string) (int, error) {
+	cmd := append([]string{"RPUSHCAP", key, strconv.Itoa(int(maxLen))}, values...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// LInsert inserts an element before or after the first occurrence of the pivot in the list.
+//
+// Parameters:
+//
+// `key` - string - the key to the list.
+//
+// `where` - string - either "BEFORE" or "AFTER" the pivot.
+//
+// `pivot` - string - the element to insert the new element next to.
+//
+// `element` - string - the element to insert.
+//
+// Returns: The length of the list after the insert. Returns -1 if the pivot was not found and 0 if the key
+// does not exist.
+//
+// Errors:
+//
+// "LINSERT command on non-list item" - when the provided key exists but is not a list.
+//
+// "where argument must be either BEFORE or AFTER" - when where is not either "BEFORE" or "AFTER".
+func (server *SugarDB) LInsert(key, where, pivot, element string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"LINSERT", key, where, pivot, element}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// LPos returns the indexes of the elements in the list that match the provided element.
+//
+// Parameters:
+//
+// `key` - string - the key to the list.
+//
+// `element` - string - the element to search for.
+//
+// `options` - LPosOptions.
+//
+// Returns: An integer slice with the indexes of the matches. The slice is empty if there are no matches or if
+// the key does not exist.
+//
+// Errors:
+//
+// "LPOS command on non-list item" - when the provided key exists but is not a list.
+func (server *SugarDB) LPos(key, element string, options LPosOptions) ([]int, error) {
+	cmd := []string{"LPOS", key, element}
+
+	if options.Rank != 0 {
+		cmd = append(cmd, "RANK", strconv.Itoa(options.Rank))
+	}
+
+	switch {
+	case options.All:
+		cmd = append(cmd, "COUNT", "0")
+	case options.Count > 0:
+		cmd = append(cmd, "COUNT", strconv.Itoa(int(options.Count)))
+	default:
+		cmd = append(cmd, "COUNT", "1")
+	}
+
+	if options.MaxLen > 0 {
+		cmd = append(cmd, "MAXLEN", strconv.Itoa(int(options.MaxLen)))
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// LMPop pops elements from the first non-empty list in the provided keys.
+//
+// Parameters:
+//
+// `keys` - []string - the keys to the lists, in the order they are checked.
+//
+// `options` - LMPopOptions.
+//
+// Returns: The key of the list the elements were popped from and a string slice with the popped elements.
+// When popping from the end of the list, the last element is returned first. Returns an empty key and an
+// empty slice if all the lists are empty.
+//
+// Errors:
+//
+// "LMPOP command on non-list item" - when one of the provided keys exists but is not a list.
+func (server *SugarDB) LMPop(keys []string, options LMPopOptions) (string, []string, error) {
+	cmd := append([]string{"LMPOP", strconv.Itoa(len(keys))}, keys...)
+
+	switch {
+	case options.Left:
+		cmd = append(cmd, "LEFT")
+	case options.Right:
+		cmd = append(cmd, "RIGHT")
+	default:
+		cmd = append(cmd, "LEFT")
+	}
+
+	if options.Count > 0 {
+		cmd = append(cmd, "COUNT", strconv.Itoa(int(options.Count)))
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return "", nil, err
+	}
+	arr, ok := res.([]interface{})
+	if !ok || len(arr) != 2 {
+		return "", []string{}, nil
+	}
+
+	elements := make([]string, 0)
+	if popped, ok := arr[1].([]interface{}); ok {
+		for _, element := range popped {
+			elements = append(elements, element.(string))
+		}
+	}
+	return arr[0].(string), elements, nil
+}
+
+// RPopLPush removes the last element of the source list and prepends it to the destination list.
+// The destination list is created if it does not exist.
+//
+// Parameters:
+//
+// `source` - string - the key to the source list.
+//
+// `destination` - string - the key to the destination list.
+//
+// Returns: The moved element. An empty string is returned if the source list is empty or does not exist.
+//
+// Errors:
+//
+// "both source and destination must be lists" - when either source or destination exist but are not lists.
+func (server *SugarDB) RPopLPush(source, destination string) (string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"RPOPLPUSH", source, destination}), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
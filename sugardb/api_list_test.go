@@ -18,6 +18,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestSugarDB_List(t *testing.T) {
@@ -952,4 +953,494 @@ func TestSugarDB_List(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_BLPOP", func(t *testing.T) {
+		t.Parallel()
+
+		// Returns immediately when the list is not empty.
+		if _, err := server.RPush("blpop_key1", "value1", "value2"); err != nil {
+			t.Error(err)
+			return
+		}
+		key, value, err := server.BLPop(time.Second, "blpop_empty1", "blpop_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if key != "blpop_key1" || value != "value1" {
+			t.Errorf("BLPOP() got = (%s, %s), want (blpop_key1, value1)", key, value)
+		}
+
+		// Returns empty strings when the timeout elapses.
+		key, value, err = server.BLPop(100*time.Millisecond, "blpop_empty2")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if key != "" || value != "" {
+			t.Errorf("BLPOP() got = (%s, %s), want empty strings", key, value)
+		}
+
+		// Unblocks when another client pushes to the list.
+		go func() {
+			<-time.After(100 * time.Millisecond)
+			_, _ = server.LPush("blpop_key3", "value3")
+		}()
+		key, value, err = server.BLPop(5*time.Second, "blpop_key3")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if key != "blpop_key3" || value != "value3" {
+			t.Errorf("BLPOP() got = (%s, %s), want (blpop_key3, value3)", key, value)
+		}
+	})
+
+	t.Run("TestSugarDB_BRPOP", func(t *testing.T) {
+		t.Parallel()
+
+		go func() {
+			<-time.After(100 * time.Millisecond)
+			_, _ = server.RPush("brpop_key1", "value1", "value2")
+		}()
+		key, value, err := server.BRPop(5*time.Second, "brpop_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if key != "brpop_key1" || value != "value2" {
+			t.Errorf("BRPOP() got = (%s, %s), want (brpop_key1, value2)", key, value)
+		}
+	})
+
+	t.Run("TestSugarDB_BLMOVE", func(t *testing.T) {
+		t.Parallel()
+
+		go func() {
+			<-time.After(100 * time.Millisecond)
+			_, _ = server.RPush("blmove_source1", "value1", "value2")
+		}()
+		value, err := server.BLMove("blmove_source1", "blmove_destination1", "RIGHT", "LEFT", 5*time.Second)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != "value2" {
+			t.Errorf("BLMOVE() got = %s, want value2", value)
+		}
+		got, err := server.LRange("blmove_destination1", 0, -1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(got, []string{"value2"}) {
+			t.Errorf("BLMOVE() destination got = %v, want [value2]", got)
+		}
+
+		value, err = server.BLMove("blmove_source2", "blmove_destination2", "LEFT", "LEFT", 100*time.Millisecond)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != "" {
+			t.Errorf("BLMOVE() got = %s, want empty string", value)
+		}
+	})
+
+	t.Run("TestSugarDB_PUSHCAP", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name      string
+			key       string
+			maxLen    uint
+			values    []string
+			pushFunc  func(key string, maxLen uint, values ...string) (int, error)
+			want      int
+			wantValue []string
+			wantErr   bool
+		}{
+			{
+				name:      "1. LPushCap drops the elements at the end of the list",
+				key:       "pushcap_key1",
+				maxLen:    3,
+				values:    []string{"1", "2", "3", "4"},
+				pushFunc:  server.LPushCap,
+				want:      3,
+				wantValue: []string{"1", "2", "3"},
+				wantErr:   false,
+			},
+			{
+				name:      "2. RPushCap drops the elements at the beginning of the list",
+				key:       "pushcap_key2",
+				maxLen:    3,
+				values:    []string{"1", "2", "3", "4"},
+				pushFunc:  server.RPushCap,
+				want:      3,
+				wantValue: []string{"2", "3", "4"},
+				wantErr:   false,
+			},
+			{
+				name:      "3. Return error when maxLen is 0",
+				key:       "pushcap_key3",
+				maxLen:    0,
+				values:    []string{"1", "2"},
+				pushFunc:  server.RPushCap,
+				want:      0,
+				wantValue: nil,
+				wantErr:   true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				got, err := tt.pushFunc(tt.key, tt.maxLen, tt.values...)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("PushCap() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				if got != tt.want {
+					t.Errorf("PushCap() got = %v, want %v", got, tt.want)
+				}
+				list, err := server.LRange(tt.key, 0, -1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !reflect.DeepEqual(list, tt.wantValue) {
+					t.Errorf("PushCap() list = %v, want %v", list, tt.wantValue)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_LINSERT", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			preset      bool
+			presetValue interface{}
+			key         string
+			where       string
+			pivot       string
+			element     string
+			want        int
+			wantErr     bool
+		}{
+			{
+				name:        "1. Insert element before the pivot",
+				preset:      true,
+				presetValue: []string{"1", "2", "3"},
+				key:         "linsert_key1",
+				where:       "BEFORE",
+				pivot:       "2",
+				element:     "value1",
+				want:        4,
+				wantErr:     false,
+			},
+			{
+				name:        "2. Return -1 when the pivot is not found",
+				preset:      true,
+				presetValue: []string{"1", "2", "3"},
+				key:         "linsert_key2",
+				where:       "AFTER",
+				pivot:       "4",
+				element:     "value1",
+				want:        -1,
+				wantErr:     false,
+			},
+			{
+				name:        "3. Return 0 when the key does not exist",
+				preset:      false,
+				presetValue: nil,
+				key:         "linsert_key3",
+				where:       "AFTER",
+				pivot:       "1",
+				element:     "value1",
+				want:        0,
+				wantErr:     false,
+			},
+			{
+				name:        "4. Return error when the value is not a list",
+				preset:      true,
+				presetValue: "Default value",
+				key:         "linsert_key4",
+				where:       "AFTER",
+				pivot:       "1",
+				element:     "value1",
+				want:        0,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				if tt.preset {
+					err := presetValue(server, context.Background(), tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.LInsert(tt.key, tt.where, tt.pivot, tt.element)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("LINSERT() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("LINSERT() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_LPOS", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			preset      bool
+			presetValue interface{}
+			key         string
+			element     string
+			options     LPosOptions
+			want        []int
+			wantErr     bool
+		}{
+			{
+				name:        "1. Return the first match by default",
+				preset:      true,
+				presetValue: []string{"a", "b", "c", "a", "a"},
+				key:         "lpos_key1",
+				element:     "a",
+				options:     LPosOptions{},
+				want:        []int{0},
+				wantErr:     false,
+			},
+			{
+				name:        "2. Return all the matches from the end of the list",
+				preset:      true,
+				presetValue: []string{"a", "b", "c", "a", "a"},
+				key:         "lpos_key2",
+				element:     "a",
+				options:     LPosOptions{Rank: -1, All: true},
+				want:        []int{4, 3, 0},
+				wantErr:     false,
+			},
+			{
+				name:        "3. Return count matches after skipping with rank",
+				preset:      true,
+				presetValue: []string{"a", "b", "c", "a", "a"},
+				key:         "lpos_key3",
+				element:     "a",
+				options:     LPosOptions{Rank: 2, Count: 1},
+				want:        []int{3},
+				wantErr:     false,
+			},
+			{
+				name:        "4. Return an empty slice when the key does not exist",
+				preset:      false,
+				presetValue: nil,
+				key:         "lpos_key4",
+				element:     "a",
+				options:     LPosOptions{},
+				want:        []int{},
+				wantErr:     false,
+			},
+			{
+				name:        "5. Return error when the value is not a list",
+				preset:      true,
+				presetValue: "Default value",
+				key:         "lpos_key5",
+				element:     "a",
+				options:     LPosOptions{},
+				want:        nil,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				if tt.preset {
+					err := presetValue(server, context.Background(), tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.LPos(tt.key, tt.element, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("LPOS() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("LPOS() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_LMPOP", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name         string
+			presetValues map[string]interface{}
+			keys         []string
+			options      LMPopOptions
+			wantKey      string
+			want         []string
+			wantErr      bool
+		}{
+			{
+				name: "1. Pop from the left of the first non-empty list",
+				presetValues: map[string]interface{}{
+					"lmpop_key2": []string{"1", "2", "3"},
+				},
+				keys:    []string{"lmpop_key1", "lmpop_key2"},
+				options: LMPopOptions{Left: true},
+				wantKey: "lmpop_key2",
+				want:    []string{"1"},
+				wantErr: false,
+			},
+			{
+				name: "2. Pop count elements from the right of the list",
+				presetValues: map[string]interface{}{
+					"lmpop_key3": []string{"1", "2", "3"},
+				},
+				keys:    []string{"lmpop_key3"},
+				options: LMPopOptions{Right: true, Count: 2},
+				wantKey: "lmpop_key3",
+				want:    []string{"3", "2"},
+				wantErr: false,
+			},
+			{
+				name:         "3. Return empty key and slice when none of the lists exist",
+				presetValues: nil,
+				keys:         []string{"lmpop_key4", "lmpop_key5"},
+				options:      LMPopOptions{Left: true},
+				wantKey:      "",
+				want:         []string{},
+				wantErr:      false,
+			},
+			{
+				name: "4. Return error when the value is not a list",
+				presetValues: map[string]interface{}{
+					"lmpop_key6": "Default value",
+				},
+				keys:    []string{"lmpop_key6"},
+				options: LMPopOptions{Left: true},
+				wantKey: "",
+				want:    nil,
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				for k, v := range tt.presetValues {
+					err := presetValue(server, context.Background(), k, v)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				key, got, err := server.LMPop(tt.keys, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("LMPOP() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if key != tt.wantKey {
+					t.Errorf("LMPOP() key = %v, want %v", key, tt.wantKey)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("LMPOP() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_RPOPLPUSH", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name         string
+			presetValues map[string]interface{}
+			source       string
+			destination  string
+			want         string
+			wantValues   map[string][]string
+			wantErr      bool
+		}{
+			{
+				name: "1. Move the last element of the source to the start of the destination",
+				presetValues: map[string]interface{}{
+					"rpoplpush_source1":      []string{"1", "2", "3"},
+					"rpoplpush_destination1": []string{"a", "b"},
+				},
+				source:      "rpoplpush_source1",
+				destination: "rpoplpush_destination1",
+				want:        "3",
+				wantValues: map[string][]string{
+					"rpoplpush_source1":      {"1", "2"},
+					"rpoplpush_destination1": {"3", "a", "b"},
+				},
+				wantErr: false,
+			},
+			{
+				name:         "2. Return empty string when the source does not exist",
+				presetValues: nil,
+				source:       "rpoplpush_source2",
+				destination:  "rpoplpush_destination2",
+				want:         "",
+				wantValues:   nil,
+				wantErr:      false,
+			},
+			{
+				name: "3. Return error when the source is not a list",
+				presetValues: map[string]interface{}{
+					"rpoplpush_source3": "Default value",
+				},
+				source:      "rpoplpush_source3",
+				destination: "rpoplpush_destination3",
+				want:        "",
+				wantValues:  nil,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				for k, v := range tt.presetValues {
+					err := presetValue(server, context.Background(), k, v)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.RPopLPush(tt.source, tt.destination)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("RPOPLPUSH() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("RPOPLPUSH() got = %v, want %v", got, tt.want)
+				}
+				for key, want := range tt.wantValues {
+					list, err := server.LRange(key, 0, -1)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if !reflect.DeepEqual(list, want) {
+						t.Errorf("RPOPLPUSH() list at %s = %v, want %v", key, list, want)
+					}
+				}
+			})
+		}
+	})
 }
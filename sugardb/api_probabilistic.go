@@ -0,0 +1,974 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/modules/probabilistic"
+)
+
+// BFReserveOptions modifies the behaviour of the BFReserve function.
+//
+// Expansion - uint - how many times larger each new sub-filter is than the previous one. Defaults to 2 when 0.
+//
+// NonScaling - bool - whether the filter should reject new items instead of growing when it's full.
+type BFReserveOptions struct {
+	Expansion  uint
+	NonScaling bool
+}
+
+// BFInsertOptions modifies the behaviour of the BFInsert function. The options are only used when the
+// filter is created.
+//
+// ErrorRate - float64 - the false positive rate of the filter. Defaults to 0.01 when 0.
+//
+// Capacity - uint - the number of items the filter holds before it grows. Defaults to 100 when 0.
+//
+// Expansion - uint - how many times larger each new sub-filter is than the previous one. Defaults to 2 when 0.
+//
+// NonScaling - bool - whether the filter should reject new items instead of growing when it's full.
+//
+// NoCreate - bool - return an error instead of creating the filter when the key doesn't exist.
+type BFInsertOptions struct {
+	ErrorRate  float64
+	Capacity   uint
+	Expansion  uint
+	NonScaling bool
+	NoCreate   bool
+}
+
+// BloomFilterInfo holds the information returned by BFInfo.
+//
+// Capacity - int - the number of items the filter holds before it grows, including all its sub-filters.
+//
+// Size - int - the memory used by the filter in bytes.
+//
+// Filters - int - the number of sub-filters.
+//
+// Items - int - the number of items added to the filter.
+//
+// Expansion - int - how many times larger each new sub-filter is than the previous one. 0 for non-scaling filters.
+type BloomFilterInfo struct {
+	Capacity  int
+	Size      int
+	Filters   int
+	Items     int
+	Expansion int
+}
+
+// CFReserveOptions modifies the behaviour of the CFReserve function.
+//
+// BucketSize - uint - the number of items each bucket holds. Defaults to 2 when 0.
+//
+// MaxIterations - uint - the number of times an item is moved to make room for a new one before the filter grows.
+// Defaults to 20 when 0.
+//
+// Expansion - uint - how many times larger each new sub-filter is than the previous one. Defaults to 1 when 0.
+//
+// NonScaling - bool - whether the filter should reject new items instead of growing when it's full.
+type CFReserveOptions struct {
+	BucketSize    uint
+	MaxIterations uint
+	Expansion     uint
+	NonScaling    bool
+}
+
+// CFInsertOptions modifies the behaviour of the CFInsert and CFInsertNX functions.
+//
+// Capacity - uint - the capacity of the filter if it's created. Defaults to 1024 when 0.
+//
+// NoCreate - bool - return an error instead of creating the filter when the key doesn't exist.
+type CFInsertOptions struct {
+	Capacity uint
+	NoCreate bool
+}
+
+// CuckooFilterInfo holds the information returned by CFInfo.
+//
+// Size - int - the memory used by the filter in bytes.
+//
+// Buckets - int - the number of buckets across all sub-filters.
+//
+// Filters - int - the number of sub-filters.
+//
+// Inserted - int - the number of items in the filter.
+//
+// Deleted - int - the number of items deleted from the filter.
+//
+// BucketSize, Expansion and MaxIterations - int - the options the filter was created with.
+type CuckooFilterInfo struct {
+	Size          int
+	Buckets       int
+	Filters       int
+	Inserted      int
+	Deleted       int
+	BucketSize    int
+	Expansion     int
+	MaxIterations int
+}
+
+// CountMinSketchInfo holds the information returned by CMSInfo.
+//
+// Width - int - the number of counters in each row.
+//
+// Depth - int - the number of rows.
+//
+// Count - int - the sum of all the increments.
+type CountMinSketchInfo struct {
+	Width int
+	Depth int
+	Count int
+}
+
+// TopKReserveOptions modifies the behaviour of the TopKReserve function.
+//
+// Width - uint - the number of counters in each row. Defaults to 8 when 0.
+//
+// Depth - uint - the number of rows. Defaults to 7 when 0.
+//
+// Decay - float64 - the probability of decrementing a counter held by another item, between 0 and 1.
+// Defaults to 0.9 when 0.
+type TopKReserveOptions struct {
+	Width uint
+	Depth uint
+	Decay float64
+}
+
+// TopKItem is an item of a top-k sketch and its estimated count.
+type TopKItem struct {
+	Item  string
+	Count int
+}
+
+// TopKSketchInfo holds the information returned by TopKInfo.
+type TopKSketchInfo struct {
+	K     int
+	Width int
+	Depth int
+	Decay float64
+}
+
+// parseInfoResponse parses the flat array of field names and values returned by the INFO commands of the
+// probabilistic types.
+func parseInfoResponse(b []byte) (map[string]interface{}, error) {
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := res.([]interface{})
+	if !ok || len(arr)%2 != 0 {
+		return nil, errors.New("malformed info response")
+	}
+	info := make(map[string]interface{}, len(arr)/2)
+	for i := 0; i < len(arr); i += 2 {
+		info[arr[i].(string)] = arr[i+1]
+	}
+	return info, nil
+}
+
+// parseBooleanArrayResponse parses an array of 1 and 0 replies, returning the first error element if any.
+func parseBooleanArrayResponse(b []byte) ([]bool, error) {
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	arr, _ := res.([]interface{})
+	values := make([]bool, len(arr))
+	for i, value := range arr {
+		if err, ok := value.(error); ok {
+			return nil, err
+		}
+		values[i] = value == 1
+	}
+	return values, nil
+}
+
+// BFReserve creates an empty Bloom filter at the key.
+//
+// Parameters:
+//
+// `key` - string - the key to create the filter at.
+//
+// `errorRate` - float64 - the false positive rate of the filter, between 0 and 1.
+//
+// `capacity` - uint - the number of items the filter holds before it grows.
+//
+// `options` - BFReserveOptions.
+//
+// Returns: true if the filter was created.
+//
+// Errors:
+//
+// "key <key> already exists" - when the key already exists.
+func (server *SugarDB) BFReserve(key string, errorRate float64, capacity uint, options BFReserveOptions) (bool, error) {
+	cmd := []string{"BF.RESERVE", key, strconv.FormatFloat(errorRate, 'f', -1, 64), strconv.FormatUint(uint64(capacity), 10)}
+	if options.Expansion != 0 {
+		cmd = append(cmd, "EXPANSION", strconv.FormatUint(uint64(options.Expansion), 10))
+	}
+	if options.NonScaling {
+		cmd = append(cmd, "NONSCALING")
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// BFAdd adds the item to the Bloom filter at the key. If the key does not exist, a filter is created with the
+// default options.
+//
+// Parameters:
+//
+// `key` - string - the key of the filter.
+//
+// `item` - string - the item to add.
+//
+// Returns: true if the item was added, or false if it may already exist.
+//
+// Errors:
+//
+// "value at key <key> is not a bloom filter" - when the key exists but is not a Bloom filter.
+//
+// "non scaling filter is full" - when the filter is non-scaling and has reached its capacity.
+func (server *SugarDB) BFAdd(key string, item string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"BF.ADD", key, item}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// BFMAdd adds the items to the Bloom filter at the key. If the key does not exist, a filter is created with the
+// default options.
+//
+// Parameters:
+//
+// `key` - string - the key of the filter.
+//
+// `items` - ...string - the items to add.
+//
+// Returns: A slice with true for each item that was added, and false for each item that may already exist.
+//
+// Errors:
+//
+// "value at key <key> is not a bloom filter" - when the key exists but is not a Bloom filter.
+//
+// "non scaling filter is full" - when the filter is non-scaling and has reached its capacity.
+func (server *SugarDB) BFMAdd(key string, items ...string) ([]bool, error) {
+	cmd := append([]string{"BF.MADD", key}, items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseBooleanArrayResponse(b)
+}
+
+// BFInsert adds the items to the Bloom filter at the key, creating the filter with the given options if it
+// doesn't exist.
+//
+// Parameters:
+//
+// `key` - string - the key of the filter.
+//
+// `options` - BFInsertOptions.
+//
+// `items` - ...string - the items to add.
+//
+// Returns: A slice with true for each item that was added, and false for each item that may already exist.
+//
+// Errors:
+//
+// "key <key> does not exist" - when NoCreate is true and the key does not exist.
+//
+// "value at key <key> is not a bloom filter" - when the key exists but is not a Bloom filter.
+func (server *SugarDB) BFInsert(key string, options BFInsertOptions, items ...string) ([]bool, error) {
+	cmd := []string{"BF.INSERT", key}
+	if options.ErrorRate != 0 {
+		cmd = append(cmd, "ERROR", strconv.FormatFloat(options.ErrorRate, 'f', -1, 64))
+	}
+	if options.Capacity != 0 {
+		cmd = append(cmd, "CAPACITY", strconv.FormatUint(uint64(options.Capacity), 10))
+	}
+	if options.Expansion != 0 {
+		cmd = append(cmd, "EXPANSION", strconv.FormatUint(uint64(options.Expansion), 10))
+	}
+	if options.NonScaling {
+		cmd = append(cmd, "NONSCALING")
+	}
+	if options.NoCreate {
+		cmd = append(cmd, "NOCREATE")
+	}
+	cmd = append(append(cmd, "ITEMS"), items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseBooleanArrayResponse(b)
+}
+
+// BFExists checks whether the item may exist in the Bloom filter at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the filter.
+//
+// `item` - string - the item to check.
+//
+// Returns: true if the item may exist, or false if it definitely doesn't or the key does not exist.
+//
+// Errors:
+//
+// "value at key <key> is not a bloom filter" - when the key exists but is not a Bloom filter.
+func (server *SugarDB) BFExists(key string, item string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"BF.EXISTS", key, item}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// BFMExists checks whether each of the items may exist in the Bloom filter at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the filter.
+//
+// `items` - ...string - the items to check.
+//
+// Returns: A slice with true for each item that may exist, and false for each item that definitely doesn't.
+//
+// Errors:
+//
+// "value at key <key> is not a bloom filter" - when the key exists but is not a Bloom filter.
+func (server *SugarDB) BFMExists(key string, items ...string) ([]bool, error) {
+	cmd := append([]string{"BF.MEXISTS", key}, items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseBooleanArrayResponse(b)
+}
+
+// BFCard returns the number of items added to the Bloom filter at the key, or 0 if the key does not exist.
+//
+// Errors:
+//
+// "value at key <key> is not a bloom filter" - when the key exists but is not a Bloom filter.
+func (server *SugarDB) BFCard(key string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"BF.CARD", key}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// BFInfo returns information about the Bloom filter at the key.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a bloom filter" - when the key exists but is not a Bloom filter.
+func (server *SugarDB) BFInfo(key string) (BloomFilterInfo, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"BF.INFO", key}), nil, false, true)
+	if err != nil {
+		return BloomFilterInfo{}, err
+	}
+	info, err := parseInfoResponse(b)
+	if err != nil {
+		return BloomFilterInfo{}, err
+	}
+	// The expansion rate is nil for non-scaling filters.
+	expansion, _ := info["Expansion rate"].(int)
+	return BloomFilterInfo{
+		Capacity:  info["Capacity"].(int),
+		Size:      info["Size"].(int),
+		Filters:   info["Number of filters"].(int),
+		Items:     info["Number of items inserted"].(int),
+		Expansion: expansion,
+	}, nil
+}
+
+// CFReserve creates an empty cuckoo filter at the key.
+//
+// Parameters:
+//
+// `key` - string - the key to create the filter at.
+//
+// `capacity` - uint - the number of items the filter holds before it grows.
+//
+// `options` - CFReserveOptions.
+//
+// Returns: true if the filter was created.
+//
+// Errors:
+//
+// "key <key> already exists" - when the key already exists.
+func (server *SugarDB) CFReserve(key string, capacity uint, options CFReserveOptions) (bool, error) {
+	cmd := []string{"CF.RESERVE", key, strconv.FormatUint(uint64(capacity), 10)}
+	if options.BucketSize != 0 {
+		cmd = append(cmd, "BUCKETSIZE", strconv.FormatUint(uint64(options.BucketSize), 10))
+	}
+	if options.MaxIterations != 0 {
+		cmd = append(cmd, "MAXITERATIONS", strconv.FormatUint(uint64(options.MaxIterations), 10))
+	}
+	if options.NonScaling {
+		cmd = append(cmd, "EXPANSION", "0")
+	} else if options.Expansion != 0 {
+		cmd = append(cmd, "EXPANSION", strconv.FormatUint(uint64(options.Expansion), 10))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// CFAdd adds the item to the cuckoo filter at the key, even if it was already added. If the key does not exist,
+// a filter is created with the default options.
+//
+// Returns: true if the item was added.
+//
+// Errors:
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+//
+// "filter is full" - when the filter is non-scaling and has no room for the item.
+func (server *SugarDB) CFAdd(key string, item string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CF.ADD", key, item}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// CFAddNX adds the item to the cuckoo filter at the key if it doesn't already exist in the filter.
+// If the key does not exist, a filter is created with the default options.
+//
+// Returns: true if the item was added, or false if it may already exist.
+//
+// Errors:
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+//
+// "filter is full" - when the filter is non-scaling and has no room for the item.
+func (server *SugarDB) CFAddNX(key string, item string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CF.ADDNX", key, item}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// CFInsert adds the items to the cuckoo filter at the key, creating the filter if it doesn't exist.
+//
+// Parameters:
+//
+// `key` - string - the key of the filter.
+//
+// `options` - CFInsertOptions.
+//
+// `items` - ...string - the items to add.
+//
+// Returns: A slice with 1 for each item that was added, or -1 for each item that didn't fit in the filter.
+//
+// Errors:
+//
+// "key <key> does not exist" - when NoCreate is true and the key does not exist.
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+func (server *SugarDB) CFInsert(key string, options CFInsertOptions, items ...string) ([]int, error) {
+	return server.cfInsert("CF.INSERT", key, options, items)
+}
+
+// CFInsertNX adds the items that don't already exist to the cuckoo filter at the key, creating the filter if it
+// doesn't exist.
+//
+// Parameters:
+//
+// `key` - string - the key of the filter.
+//
+// `options` - CFInsertOptions.
+//
+// `items` - ...string - the items to add.
+//
+// Returns: A slice with 1 for each item that was added, 0 for each item that may already exist, or -1 for each
+// item that didn't fit in the filter.
+//
+// Errors:
+//
+// "key <key> does not exist" - when NoCreate is true and the key does not exist.
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+func (server *SugarDB) CFInsertNX(key string, options CFInsertOptions, items ...string) ([]int, error) {
+	return server.cfInsert("CF.INSERTNX", key, options, items)
+}
+
+func (server *SugarDB) cfInsert(command string, key string, options CFInsertOptions, items []string) ([]int, error) {
+	cmd := []string{command, key}
+	if options.Capacity != 0 {
+		cmd = append(cmd, "CAPACITY", strconv.FormatUint(uint64(options.Capacity), 10))
+	}
+	if options.NoCreate {
+		cmd = append(cmd, "NOCREATE")
+	}
+	cmd = append(append(cmd, "ITEMS"), items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// CFExists checks whether the item may exist in the cuckoo filter at the key.
+//
+// Returns: true if the item may exist, or false if it definitely doesn't or the key does not exist.
+//
+// Errors:
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+func (server *SugarDB) CFExists(key string, item string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CF.EXISTS", key, item}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// CFMExists checks whether each of the items may exist in the cuckoo filter at the key.
+//
+// Returns: A slice with true for each item that may exist, and false for each item that definitely doesn't.
+//
+// Errors:
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+func (server *SugarDB) CFMExists(key string, items ...string) ([]bool, error) {
+	cmd := append([]string{"CF.MEXISTS", key}, items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseBooleanArrayResponse(b)
+}
+
+// CFCount returns an estimate of the number of times the item was added to the cuckoo filter at the key.
+//
+// Errors:
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+func (server *SugarDB) CFCount(key string, item string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CF.COUNT", key, item}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// CFDel deletes one occurrence of the item from the cuckoo filter at the key.
+//
+// Returns: true if the item was deleted, or false if it wasn't found.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+func (server *SugarDB) CFDel(key string, item string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CF.DEL", key, item}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// CFInfo returns information about the cuckoo filter at the key.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a cuckoo filter" - when the key exists but is not a cuckoo filter.
+func (server *SugarDB) CFInfo(key string) (CuckooFilterInfo, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CF.INFO", key}), nil, false, true)
+	if err != nil {
+		return CuckooFilterInfo{}, err
+	}
+	info, err := parseInfoResponse(b)
+	if err != nil {
+		return CuckooFilterInfo{}, err
+	}
+	return CuckooFilterInfo{
+		Size:          info["Size"].(int),
+		Buckets:       info["Number of buckets"].(int),
+		Filters:       info["Number of filters"].(int),
+		Inserted:      info["Number of items inserted"].(int),
+		Deleted:       info["Number of items deleted"].(int),
+		BucketSize:    info["Bucket size"].(int),
+		Expansion:     info["Expansion rate"].(int),
+		MaxIterations: info["Max iterations"].(int),
+	}, nil
+}
+
+// CMSInitByDim creates a count-min sketch at the key with the given dimensions.
+//
+// Parameters:
+//
+// `key` - string - the key to create the sketch at.
+//
+// `width` - uint - the number of counters in each row.
+//
+// `depth` - uint - the number of rows.
+//
+// Returns: true if the sketch was created.
+//
+// Errors:
+//
+// "key <key> already exists" - when the key already exists.
+func (server *SugarDB) CMSInitByDim(key string, width, depth uint) (bool, error) {
+	cmd := []string{"CMS.INITBYDIM", key, strconv.FormatUint(uint64(width), 10), strconv.FormatUint(uint64(depth), 10)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// CMSInitByProb creates a count-min sketch at the key, sized so that counts are overestimated by at most
+// errorRate times the total count, with the given probability of exceeding that bound.
+//
+// Parameters:
+//
+// `key` - string - the key to create the sketch at.
+//
+// `errorRate` - float64 - the overestimation as a fraction of the total count, between 0 and 1.
+//
+// `probability` - float64 - the probability of exceeding the overestimation, between 0 and 1.
+//
+// Returns: true if the sketch was created.
+//
+// Errors:
+//
+// "key <key> already exists" - when the key already exists.
+func (server *SugarDB) CMSInitByProb(key string, errorRate, probability float64) (bool, error) {
+	cmd := []string{"CMS.INITBYPROB", key,
+		strconv.FormatFloat(errorRate, 'f', -1, 64), strconv.FormatFloat(probability, 'f', -1, 64)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// CMSIncrBy increases the counts of the items in the count-min sketch at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the sketch.
+//
+// `increments` - map[string]uint - the items and the amounts to increase their counts by.
+//
+// Returns: A map of each item to its estimated count after the increment.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a count-min sketch" - when the key exists but is not a count-min sketch.
+func (server *SugarDB) CMSIncrBy(key string, increments map[string]uint) (map[string]int, error) {
+	items := sortedKeys(increments)
+	cmd := []string{"CMS.INCRBY", key}
+	for _, item := range items {
+		cmd = append(cmd, item, strconv.FormatUint(uint64(increments[item]), 10))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := internal.ParseIntegerArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]int, len(items))
+	for i, item := range items {
+		res[item] = counts[i]
+	}
+	return res, nil
+}
+
+// CMSQuery returns the estimated counts of the items in the count-min sketch at the key.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a count-min sketch" - when the key exists but is not a count-min sketch.
+func (server *SugarDB) CMSQuery(key string, items ...string) ([]int, error) {
+	cmd := append([]string{"CMS.QUERY", key}, items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// CMSMerge merges the count-min sketches at the source keys into the sketch at the destination key,
+// replacing its counts.
+//
+// Parameters:
+//
+// `destination` - string - the key of the sketch to store the result in. The sketch must already exist.
+//
+// `sources` - []string - the keys of the sketches to merge.
+//
+// `weights` - []uint - the weight each source's counts are multiplied by. When empty, every weight is 1.
+//
+// Returns: true if the merge was successful.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the destination or one of the sources does not exist.
+//
+// "width/depth is not equal" - when the sketches don't have the same dimensions.
+func (server *SugarDB) CMSMerge(destination string, sources []string, weights []uint) (bool, error) {
+	cmd := append([]string{"CMS.MERGE", destination, strconv.Itoa(len(sources))}, sources...)
+	if len(weights) > 0 {
+		cmd = append(cmd, "WEIGHTS")
+		for _, weight := range weights {
+			cmd = append(cmd, strconv.FormatUint(uint64(weight), 10))
+		}
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// CMSInfo returns the dimensions and total count of the count-min sketch at the key.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a count-min sketch" - when the key exists but is not a count-min sketch.
+func (server *SugarDB) CMSInfo(key string) (CountMinSketchInfo, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"CMS.INFO", key}), nil, false, true)
+	if err != nil {
+		return CountMinSketchInfo{}, err
+	}
+	info, err := parseInfoResponse(b)
+	if err != nil {
+		return CountMinSketchInfo{}, err
+	}
+	return CountMinSketchInfo{
+		Width: info["width"].(int),
+		Depth: info["depth"].(int),
+		Count: info["count"].(int),
+	}, nil
+}
+
+// TopKReserve creates a top-k sketch at the key that tracks the k most frequent items.
+//
+// Parameters:
+//
+// `key` - string - the key to create the sketch at.
+//
+// `k` - uint - the number of items to track.
+//
+// `options` - TopKReserveOptions.
+//
+// Returns: true if the sketch was created.
+//
+// Errors:
+//
+// "key <key> already exists" - when the key already exists.
+func (server *SugarDB) TopKReserve(key string, k uint, options TopKReserveOptions) (bool, error) {
+	cmd := []string{"TOPK.RESERVE", key, strconv.FormatUint(uint64(k), 10)}
+	if options != (TopKReserveOptions{}) {
+		width, depth, decay := uint(probabilistic.DefaultTopKWidth), uint(probabilistic.DefaultTopKDepth), probabilistic.DefaultTopKDecay
+		if options.Width != 0 {
+			width = options.Width
+		}
+		if options.Depth != 0 {
+			depth = options.Depth
+		}
+		if options.Decay != 0 {
+			decay = options.Decay
+		}
+		cmd = append(cmd, strconv.FormatUint(uint64(width), 10), strconv.FormatUint(uint64(depth), 10),
+			strconv.FormatFloat(decay, 'f', -1, 64))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// TopKAdd adds the items to the top-k sketch at the key.
+//
+// Returns: A slice with the item that dropped out of the top k because of each addition,
+// or an empty string if no item dropped out.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a top-k sketch" - when the key exists but is not a top-k sketch.
+func (server *SugarDB) TopKAdd(key string, items ...string) ([]string, error) {
+	cmd := append([]string{"TOPK.ADD", key}, items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// TopKIncrBy increases the counts of the items in the top-k sketch at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the sketch.
+//
+// `increments` - map[string]uint - the items and the amounts to increase their counts by.
+//
+// Returns: The items that dropped out of the top k because of the increments.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a top-k sketch" - when the key exists but is not a top-k sketch.
+func (server *SugarDB) TopKIncrBy(key string, increments map[string]uint) ([]string, error) {
+	cmd := []string{"TOPK.INCRBY", key}
+	for _, item := range sortedKeys(increments) {
+		cmd = append(cmd, item, strconv.FormatUint(uint64(increments[item]), 10))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseStringArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(res, func(item string) bool { return item == "" }), nil
+}
+
+// TopKQuery checks whether each of the items is in the top k of the sketch at the key.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a top-k sketch" - when the key exists but is not a top-k sketch.
+func (server *SugarDB) TopKQuery(key string, items ...string) ([]bool, error) {
+	cmd := append([]string{"TOPK.QUERY", key}, items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseBooleanArrayResponse(b)
+}
+
+// TopKCount returns the estimated counts of the items in the top-k sketch at the key.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a top-k sketch" - when the key exists but is not a top-k sketch.
+func (server *SugarDB) TopKCount(key string, items ...string) ([]int, error) {
+	cmd := append([]string{"TOPK.COUNT", key}, items...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseIntegerArrayResponse(b)
+}
+
+// TopKList returns the items in the top-k sketch at the key with their estimated counts,
+// ordered from the highest to the lowest count.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a top-k sketch" - when the key exists but is not a top-k sketch.
+func (server *SugarDB) TopKList(key string) ([]TopKItem, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"TOPK.LIST", key, "WITHCOUNT"}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	arr, _ := res.([]interface{})
+	items := make([]TopKItem, 0, len(arr)/2)
+	for i := 0; i+1 < len(arr); i += 2 {
+		items = append(items, TopKItem{Item: arr[i].(string), Count: arr[i+1].(int)})
+	}
+	return items, nil
+}
+
+// TopKInfo returns the k, width, depth and decay of the top-k sketch at the key.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key does not exist.
+//
+// "value at key <key> is not a top-k sketch" - when the key exists but is not a top-k sketch.
+func (server *SugarDB) TopKInfo(key string) (TopKSketchInfo, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"TOPK.INFO", key}), nil, false, true)
+	if err != nil {
+		return TopKSketchInfo{}, err
+	}
+	info, err := parseInfoResponse(b)
+	if err != nil {
+		return TopKSketchInfo{}, err
+	}
+	decay, err := strconv.ParseFloat(info["decay"].(string), 64)
+	if err != nil {
+		return TopKSketchInfo{}, err
+	}
+	return TopKSketchInfo{
+		K:     info["k"].(int),
+		Width: info["width"].(int),
+		Depth: info["depth"].(int),
+		Decay: decay,
+	}, nil
+}
+
+func sortedKeys(m map[string]uint) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
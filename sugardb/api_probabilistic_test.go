@@ -0,0 +1,324 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/echovault/sugardb/internal/modules/probabilistic"
+)
+
+func TestSugarDB_Probabilistic(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	t.Run("TestSugarDB_BFADD", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			key         string
+			item        string
+			want        bool
+			wantErr     bool
+		}{
+			{
+				name:    "1. Create a Bloom filter on a non-existent key",
+				key:     "bf_add_key1",
+				item:    "a",
+				want:    true,
+				wantErr: false,
+			},
+			{
+				name: "2. Return false when the item may already exist",
+				presetValue: func() *probabilistic.BloomFilter {
+					bf := probabilistic.NewBloomFilter(0.01, 100, 2)
+					_, _ = bf.Add("a")
+					return bf
+				}(),
+				key:     "bf_add_key2",
+				item:    "a",
+				want:    false,
+				wantErr: false,
+			},
+			{
+				name:        "3. Throw error when the key does not hold a Bloom filter",
+				presetValue: "Default value",
+				key:         "bf_add_key3",
+				item:        "a",
+				want:        false,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.presetValue != nil {
+					err := presetValue(server, server.context, tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.BFAdd(tt.key, tt.item)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("BFADD() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("BFADD() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_BloomFilter", func(t *testing.T) {
+		t.Parallel()
+
+		if ok, err := server.BFReserve("bf_key1", 0.01, 2, BFReserveOptions{NonScaling: true}); !ok || err != nil {
+			t.Errorf("BFRESERVE() got = %v, error = %v", ok, err)
+			return
+		}
+		if _, err := server.BFReserve("bf_key1", 0.01, 2, BFReserveOptions{}); err == nil {
+			t.Error("BFRESERVE() expected error on an existing key")
+		}
+
+		got, err := server.BFMAdd("bf_key1", "a", "b", "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []bool{true, true, false}; !reflect.DeepEqual(got, want) {
+			t.Errorf("BFMADD() got = %v, want %v", got, want)
+		}
+		if _, err = server.BFInsert("bf_key1", BFInsertOptions{}, "c"); err == nil {
+			t.Error("BFINSERT() expected error when the non-scaling filter is full")
+		}
+
+		got, err = server.BFMExists("bf_key1", "a", "b", "c")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []bool{true, true, false}; !reflect.DeepEqual(got, want) {
+			t.Errorf("BFMEXISTS() got = %v, want %v", got, want)
+		}
+
+		info, err := server.BFInfo("bf_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if info.Capacity != 2 || info.Items != 2 || info.Filters != 1 || info.Expansion != 0 || info.Size == 0 {
+			t.Errorf("BFINFO() got unexpected info %+v", info)
+		}
+
+		if _, err = server.BFInsert("bf_key2", BFInsertOptions{NoCreate: true}, "a"); err == nil {
+			t.Error("BFINSERT() expected error with NoCreate on a non-existent key")
+		}
+		if _, err = server.BFInsert("bf_key2", BFInsertOptions{Capacity: 1000, ErrorRate: 0.001}, "a"); err != nil {
+			t.Error(err)
+			return
+		}
+		card, err := server.BFCard("bf_key2")
+		if err != nil || card != 1 {
+			t.Errorf("BFCARD() got = %d, error = %v", card, err)
+		}
+		if exists, err := server.BFExists("bf_key2", "a"); !exists || err != nil {
+			t.Errorf("BFEXISTS() got = %v, error = %v", exists, err)
+		}
+	})
+
+	t.Run("TestSugarDB_CuckooFilter", func(t *testing.T) {
+		t.Parallel()
+
+		if ok, err := server.CFReserve("cf_key1", 2, CFReserveOptions{BucketSize: 2, NonScaling: true}); !ok || err != nil {
+			t.Errorf("CFRESERVE() got = %v, error = %v", ok, err)
+			return
+		}
+		if added, err := server.CFAdd("cf_key1", "a"); !added || err != nil {
+			t.Errorf("CFADD() got = %v, error = %v", added, err)
+		}
+		if added, err := server.CFAddNX("cf_key1", "a"); added || err != nil {
+			t.Errorf("CFADDNX() got = %v, error = %v", added, err)
+		}
+		got, err := server.CFInsert("cf_key1", CFInsertOptions{}, "b", "c")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []int{1, -1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CFINSERT() got = %v, want %v", got, want)
+		}
+
+		exists, err := server.CFMExists("cf_key1", "a", "b", "c")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []bool{true, true, false}; !reflect.DeepEqual(exists, want) {
+			t.Errorf("CFMEXISTS() got = %v, want %v", exists, want)
+		}
+
+		if deleted, err := server.CFDel("cf_key1", "a"); !deleted || err != nil {
+			t.Errorf("CFDEL() got = %v, error = %v", deleted, err)
+		}
+		if count, err := server.CFCount("cf_key1", "a"); count != 0 || err != nil {
+			t.Errorf("CFCOUNT() got = %d, error = %v", count, err)
+		}
+
+		info, err := server.CFInfo("cf_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := CuckooFilterInfo{Size: info.Size, Buckets: 1, Filters: 1, Inserted: 1, Deleted: 1,
+			BucketSize: 2, Expansion: 0, MaxIterations: 20}
+		if info != want {
+			t.Errorf("CFINFO() got = %+v, want %+v", info, want)
+		}
+
+		got, err = server.CFInsertNX("cf_key2", CFInsertOptions{Capacity: 100}, "a", "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []int{1, 0}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CFINSERTNX() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("TestSugarDB_CountMinSketch", func(t *testing.T) {
+		t.Parallel()
+
+		for _, key := range []string{"cms_key1", "cms_key2", "cms_key3"} {
+			if ok, err := server.CMSInitByDim(key, 1000, 5); !ok || err != nil {
+				t.Errorf("CMSINITBYDIM() got = %v, error = %v", ok, err)
+				return
+			}
+		}
+		if ok, err := server.CMSInitByProb("cms_key4", 0.01, 0.01); !ok || err != nil {
+			t.Errorf("CMSINITBYPROB() got = %v, error = %v", ok, err)
+			return
+		}
+
+		counts, err := server.CMSIncrBy("cms_key1", map[string]uint{"a": 3, "b": 1})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := map[string]int{"a": 3, "b": 1}; !reflect.DeepEqual(counts, want) {
+			t.Errorf("CMSINCRBY() got = %v, want %v", counts, want)
+		}
+		if _, err = server.CMSIncrBy("cms_key2", map[string]uint{"a": 1}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		if ok, err := server.CMSMerge("cms_key3", []string{"cms_key1", "cms_key2"}, []uint{2, 1}); !ok || err != nil {
+			t.Errorf("CMSMERGE() got = %v, error = %v", ok, err)
+			return
+		}
+		if _, err = server.CMSMerge("cms_key4", []string{"cms_key1"}, nil); err == nil {
+			t.Error("CMSMERGE() expected error when merging sketches of different dimensions")
+		}
+
+		got, err := server.CMSQuery("cms_key3", "a", "b", "c")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []int{7, 2, 0}; !reflect.DeepEqual(got, want) {
+			t.Errorf("CMSQUERY() got = %v, want %v", got, want)
+		}
+
+		info, err := server.CMSInfo("cms_key3")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := (CountMinSketchInfo{Width: 1000, Depth: 5, Count: 9}); info != want {
+			t.Errorf("CMSINFO() got = %+v, want %+v", info, want)
+		}
+	})
+
+	t.Run("TestSugarDB_TopK", func(t *testing.T) {
+		t.Parallel()
+
+		if ok, err := server.TopKReserve("topk_key1", 2, TopKReserveOptions{Width: 50}); !ok || err != nil {
+			t.Errorf("TOPKRESERVE() got = %v, error = %v", ok, err)
+			return
+		}
+		info, err := server.TopKInfo("topk_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := (TopKSketchInfo{K: 2, Width: 50, Depth: 7, Decay: 0.9}); info != want {
+			t.Errorf("TOPKINFO() got = %+v, want %+v", info, want)
+		}
+
+		expelled, err := server.TopKAdd("topk_key1", "a", "b", "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []string{"", "", ""}; !reflect.DeepEqual(expelled, want) {
+			t.Errorf("TOPKADD() got = %v, want %v", expelled, want)
+		}
+		expelled, err = server.TopKIncrBy("topk_key1", map[string]uint{"c": 5})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []string{"b"}; !reflect.DeepEqual(expelled, want) {
+			t.Errorf("TOPKINCRBY() got = %v, want %v", expelled, want)
+		}
+
+		inTopK, err := server.TopKQuery("topk_key1", "a", "b", "c")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []bool{true, false, true}; !reflect.DeepEqual(inTopK, want) {
+			t.Errorf("TOPKQUERY() got = %v, want %v", inTopK, want)
+		}
+		counts, err := server.TopKCount("topk_key1", "a", "b", "c")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []int{2, 1, 5}; !reflect.DeepEqual(counts, want) {
+			t.Errorf("TOPKCOUNT() got = %v, want %v", counts, want)
+		}
+		list, err := server.TopKList("topk_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if want := []TopKItem{{Item: "c", Count: 5}, {Item: "a", Count: 2}}; !reflect.DeepEqual(list, want) {
+			t.Errorf("TOPKLIST() got = %v, want %v", list, want)
+		}
+
+		if _, err = server.TopKAdd("topk_key2", "a"); err == nil {
+			t.Error("TOPKADD() expected error on a non-existent key")
+		}
+	})
+
+}
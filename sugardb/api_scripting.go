@@ -0,0 +1,124 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// Eval executes a Lua or JavaScript script atomically. The script is cached and can be executed
+// again with EvalSha using the SHA1 digest returned by ScriptLoad.
+// Scripts are executed with Lua unless the first line of the script is the "#!js" shebang.
+//
+// The keys are available to the script in the KEYS global and the args in the ARGV global.
+// The script can interact with the store using the keysExist, getValues and setValues functions.
+//
+// Parameters:
+//
+// `script` - string - The source of the script.
+//
+// `keys` - []string - The keys that the script accesses.
+//
+// `args` - []string - Additional arguments passed to the script.
+//
+// Returns: The value returned by the script. Nil is returned as nil, strings as string, numbers as int
+// and arrays as []interface{}.
+//
+// Errors:
+//
+// "unsupported engine <engine> in script shebang, expected lua or js" - when the shebang specifies an unknown engine.
+//
+// Any error raised by the script is also returned.
+func (server *SugarDB) Eval(script string, keys []string, args []string) (interface{}, error) {
+	cmd := append([]string{"EVAL", script, strconv.Itoa(len(keys))}, keys...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append(cmd, args...)), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseAnyResponse(b)
+}
+
+// EvalSha executes a cached script by its SHA1 digest.
+//
+// Parameters:
+//
+// `sha` - string - The SHA1 digest of the script.
+//
+// `keys` - []string - The keys that the script accesses.
+//
+// `args` - []string - Additional arguments passed to the script.
+//
+// Returns: The value returned by the script. See Eval.
+//
+// Errors:
+//
+// "NOSCRIPT No matching script. Please use EVAL" - when the script is not in the script cache.
+func (server *SugarDB) EvalSha(sha string, keys []string, args []string) (interface{}, error) {
+	cmd := append([]string{"EVALSHA", sha, strconv.Itoa(len(keys))}, keys...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append(cmd, args...)), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseAnyResponse(b)
+}
+
+// ScriptLoad loads a script into the script cache without executing it.
+//
+// Parameters:
+//
+// `script` - string - The source of the script.
+//
+// Returns: The SHA1 digest of the script.
+//
+// Errors:
+//
+// "could not compile lua script: <error>" - when the script is not valid Lua.
+func (server *SugarDB) ScriptLoad(script string) (string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"SCRIPT", "LOAD", script}), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// ScriptExists checks whether the scripts are in the script cache.
+//
+// Parameters:
+//
+// `shas` - ...string - The SHA1 digests of the scripts.
+//
+// Returns: A boolean slice where each element specifies whether the script at the corresponding index exists.
+func (server *SugarDB) ScriptExists(shas ...string) ([]bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append([]string{"SCRIPT", "EXISTS"}, shas...)), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseBooleanArrayResponse(b)
+}
+
+// ScriptFlush removes all the scripts from the script cache.
+//
+// Returns: true if the script cache was flushed.
+func (server *SugarDB) ScriptFlush() (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"SCRIPT", "FLUSH"}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	res, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(res, "ok"), err
+}
@@ -0,0 +1,263 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSugarDB_Scripting(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	luaIncrScript := `
+local values = getValues(KEYS)
+local current = values[KEYS[1]] or 0
+local entries = {}
+entries[KEYS[1]] = current + tonumber(ARGV[1])
+setValues(entries)
+return entries[KEYS[1]]`
+
+	jsIncrScript := `#!js
+var values = getValues(KEYS);
+var current = values[KEYS[0]] === null ? 0 : values[KEYS[0]];
+var entries = {};
+entries[KEYS[0]] = current + parseInt(ARGV[0]);
+setValues(entries);
+return entries[KEYS[0]];`
+
+	t.Run("TestSugarDB_Eval", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			script      string
+			keys        []string
+			args        []string
+			want        interface{}
+			wantValue   interface{}
+			wantErr     bool
+		}{
+			{
+				name:        "1. Lua script increments an existing integer",
+				presetValue: 10,
+				script:      luaIncrScript,
+				keys:        []string{"eval_key1"},
+				args:        []string{"5"},
+				want:        15,
+				wantValue:   15,
+				wantErr:     false,
+			},
+			{
+				name:        "2. Lua script sets a non-existent key",
+				presetValue: nil,
+				script:      luaIncrScript,
+				keys:        []string{"eval_key2"},
+				args:        []string{"7"},
+				want:        7,
+				wantValue:   7,
+				wantErr:     false,
+			},
+			{
+				name:        "3. JS script increments an existing integer",
+				presetValue: 20,
+				script:      jsIncrScript,
+				keys:        []string{"eval_key3"},
+				args:        []string{"5"},
+				want:        25,
+				wantValue:   float64(25), // JS numbers are stored as floats.
+				wantErr:     false,
+			},
+			{
+				name:   "4. Lua script returns nested array of strings, integers and nil",
+				script: `return {"one", 2, {"three", false}, ARGV[1]}`,
+				keys:   []string{},
+				args:   []string{"four"},
+				want:   []interface{}{"one", 2, []interface{}{"three", nil}, "four"},
+			},
+			{
+				name:   "5. JS script returns nested array of strings, integers and nil",
+				script: "#!js\nreturn [\"one\", 2, [\"three\", null], ARGV[0]];",
+				keys:   []string{},
+				args:   []string{"four"},
+				want:   []interface{}{"one", 2, []interface{}{"three", nil}, "four"},
+			},
+			{
+				name:    "6. Return error from Lua error table",
+				script:  `return {err = "custom script error"}`,
+				wantErr: true,
+			},
+			{
+				name:    "7. Return error when Lua script raises an error",
+				script:  `error("raised script error")`,
+				wantErr: true,
+			},
+			{
+				name:    "8. Return error when the script does not compile",
+				script:  `return {`,
+				wantErr: true,
+			},
+			{
+				name:    "9. Return error when the engine in the shebang is not supported",
+				script:  "#!python\nreturn 1",
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				if tt.presetValue != nil {
+					err := presetValue(server, context.Background(), tt.keys[0], tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.Eval(tt.script, tt.keys, tt.args)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Eval() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Eval() got = %v, want %v", got, tt.want)
+				}
+				if tt.wantValue != nil {
+					value, err := getValue(server, context.Background(), tt.keys[0], "0")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if value != tt.wantValue {
+						t.Errorf("Eval() stored value = %v, want %v", value, tt.wantValue)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_EvalSha", func(t *testing.T) {
+		t.Parallel()
+
+		script := `return KEYS[1] .. ":" .. ARGV[1]`
+		sha, err := server.ScriptLoad(script)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(sha) != 40 {
+			t.Errorf("ScriptLoad() expected 40 character SHA1 digest, got %s", sha)
+		}
+
+		got, err := server.EvalSha(sha, []string{"evalsha_key1"}, []string{"value"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got != "evalsha_key1:value" {
+			t.Errorf("EvalSha() got = %v, want %v", got, "evalsha_key1:value")
+		}
+
+		if _, err = server.EvalSha("0000000000000000000000000000000000000000", []string{}, []string{}); err == nil {
+			t.Errorf("EvalSha() expected NOSCRIPT error for unknown script")
+		}
+	})
+
+	t.Run("TestSugarDB_EvalConcurrentWithTransaction", func(t *testing.T) {
+		t.Parallel()
+
+		// EVAL and a transaction that executes the same script must not deadlock on the store and script locks.
+		for i, script := range []string{luaIncrScript, jsIncrScript} {
+			key := fmt.Sprintf("eval_concurrent_key%d", i+1)
+			done := make(chan error, 2)
+			go func() {
+				for j := 0; j < 2000; j++ {
+					if _, err := server.Eval(script, []string{key}, []string{"1"}); err != nil {
+						done <- err
+						return
+					}
+				}
+				done <- nil
+			}()
+			go func() {
+				for j := 0; j < 2000; j++ {
+					tx := server.NewTx()
+					if err := tx.Queue("EVAL", script, "1", key, "1"); err != nil {
+						done <- err
+						return
+					}
+					if _, err := tx.Exec(); err != nil {
+						done <- err
+						return
+					}
+				}
+				done <- nil
+			}()
+			for j := 0; j < 2; j++ {
+				select {
+				case err := <-done:
+					if err != nil {
+						t.Error(err)
+					}
+				case <-time.After(10 * time.Second):
+					t.Fatalf("expected EVAL and EXEC of script %d to complete, timed out", i+1)
+				}
+			}
+		}
+	})
+
+	t.Run("TestSugarDB_ScriptExistsAndFlush", func(t *testing.T) {
+		// This test flushes the script cache, so it must not run in parallel with the other tests.
+		sha, err := server.ScriptLoad(`return "exists"`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		exists, err := server.ScriptExists(sha, "0000000000000000000000000000000000000000")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(exists, []bool{true, false}) {
+			t.Errorf("ScriptExists() got = %v, want %v", exists, []bool{true, false})
+		}
+
+		ok, err := server.ScriptFlush()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !ok {
+			t.Errorf("ScriptFlush() expected true, got false")
+		}
+
+		exists, err = server.ScriptExists(sha)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(exists, []bool{false}) {
+			t.Errorf("ScriptExists() after flush got = %v, want %v", exists, []bool{false})
+		}
+	})
+}
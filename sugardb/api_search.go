@@ -0,0 +1,520 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// FTField is a hash field included in an index created with FTCreate.
+//
+// Name - string - the name of the hash field.
+//
+// Alias - string - the name of the field in queries. Defaults to Name.
+//
+// Type - string - one of "TEXT", "TAG" or "NUMERIC".
+//
+// Sortable - bool - accepted for compatibility, every field can be sorted by.
+//
+// Separator - string - the character that separates the tags of a TAG field. Defaults to ",".
+//
+// CaseSensitive - bool - match the tags of a TAG field case-sensitively.
+type FTField struct {
+	Name          string
+	Alias         string
+	Type          string
+	Sortable      bool
+	Separator     string
+	CaseSensitive bool
+}
+
+// FTCreateOptions modifies the behaviour of the FTCreate function.
+//
+// Prefixes - []string - only the hashes whose keys start with one of the prefixes are indexed.
+// When empty, every hash is indexed.
+type FTCreateOptions struct {
+	Prefixes []string
+}
+
+// FTIndexInfo describes an index.
+//
+// Name - string - the name of the index.
+//
+// Prefixes - []string - the prefixes of the keys of the indexed hashes.
+//
+// Fields - []FTField - the fields of the index.
+//
+// NumDocs - int - the number of hashes in the index.
+//
+// NumTerms - int - the number of distinct terms of the text fields.
+//
+// NumRecords - int - the number of entries of the inverted indexes.
+type FTIndexInfo struct {
+	Name       string
+	Prefixes   []string
+	Fields     []FTField
+	NumDocs    int
+	NumTerms   int
+	NumRecords int
+}
+
+// FTSearchOptions modifies the behaviour of the FTSearch function.
+//
+// NoContent - bool - only return the keys of the hashes.
+//
+// Return - []string - only return these fields of the hashes. Fields can be referred to by their alias.
+//
+// SortBy - string - the field the documents are sorted by. By default, they're sorted by score.
+//
+// SortDesc - bool - sort the documents by SortBy in descending order.
+//
+// Offset - int - the number of documents to skip.
+//
+// Limit - int - the maximum number of documents to return. Defaults to 10.
+type FTSearchOptions struct {
+	NoContent bool
+	Return    []string
+	SortBy    string
+	SortDesc  bool
+	Offset    int
+	Limit     int
+}
+
+// FTDocument is a hash returned by FTSearch.
+//
+// Key - string - the key of the hash.
+//
+// Score - float64 - the relevance of the hash to the text terms of the query.
+//
+// Fields - map[string]string - the fields of the hash. Nil when NoContent is set.
+type FTDocument struct {
+	Key    string
+	Score  float64
+	Fields map[string]string
+}
+
+// FTSearchResult is the result of FTSearch.
+//
+// Total - int - the number of hashes that match the query, including the ones outside the page.
+//
+// Documents - []FTDocument - the hashes in the page selected by Offset and Limit.
+type FTSearchResult struct {
+	Total     int
+	Documents []FTDocument
+}
+
+// FTReducer computes a property of each group of FTGroupBy.
+//
+// Function - string - one of "COUNT", "COUNT_DISTINCT", "SUM", "MIN", "MAX" or "AVG".
+//
+// Property - string - the property that's reduced. Empty for COUNT.
+//
+// Alias - string - the name of the computed property.
+type FTReducer struct {
+	Function string
+	Property string
+	Alias    string
+}
+
+// FTGroupBy groups the rows of FTAggregate by the values of the properties and reduces each group to a row.
+type FTGroupBy struct {
+	Properties []string
+	Reducers   []FTReducer
+}
+
+// FTSortKey is a property the rows of FTAggregate are sorted by.
+type FTSortKey struct {
+	Property   string
+	Descending bool
+}
+
+// FTAggregateOptions modifies the behaviour of the FTAggregate function. Properties are the aliases of the
+// fields of the index, the loaded fields, the properties computed by the reducers, and "__key".
+//
+// Load - []string - the hash fields returned in each row, before grouping. "*" loads every field.
+//
+// GroupBy - []FTGroupBy - the groupings applied to the rows, in order.
+//
+// SortBy - []FTSortKey - sorts the rows after they're grouped.
+//
+// Offset - int - the number of rows to skip.
+//
+// Limit - int - the maximum number of rows to return. All the rows are returned when 0.
+type FTAggregateOptions struct {
+	Load    []string
+	GroupBy []FTGroupBy
+	SortBy  []FTSortKey
+	Offset  int
+	Limit   int
+}
+
+// FTAggregateResult is the result of FTAggregate.
+//
+// Total - int - the number of rows, including the ones outside the page.
+//
+// Rows - []map[string]string - the rows in the page selected by Offset and Limit.
+type FTAggregateResult struct {
+	Total int
+	Rows  []map[string]string
+}
+
+func parseFTFields(value interface{}) map[string]string {
+	values, _ := value.([]interface{})
+	fields := make(map[string]string, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		field, _ := values[i].(string)
+		v, _ := values[i+1].(string)
+		fields[field] = v
+	}
+	return fields
+}
+
+// FTCreate creates an index of hashes. The hashes that already exist are added to the index, and the index is
+// kept up to date as hashes are written, deleted and expired.
+//
+// Parameters:
+//
+// `index` - string - the name of the index.
+//
+// `schema` - []FTField - the fields of the hashes that are indexed.
+//
+// `options` - FTCreateOptions.
+//
+// Returns: true if the index was created.
+//
+// Errors:
+//
+// "index <index> already exists" - when an index with the name exists in the database.
+//
+// "unknown field type <type>" - when the type of a field is not TEXT, TAG or NUMERIC.
+//
+// "duplicate field <alias>" - when two fields have the same alias.
+func (server *SugarDB) FTCreate(index string, schema []FTField, options FTCreateOptions) (bool, error) {
+	cmd := []string{"FT.CREATE", index, "ON", "HASH"}
+	if len(options.Prefixes) > 0 {
+		cmd = append(cmd, "PREFIX", strconv.Itoa(len(options.Prefixes)))
+		cmd = append(cmd, options.Prefixes...)
+	}
+	cmd = append(cmd, "SCHEMA")
+	for _, field := range schema {
+		cmd = append(cmd, field.Name)
+		if field.Alias != "" {
+			cmd = append(cmd, "AS", field.Alias)
+		}
+		cmd = append(cmd, field.Type)
+		if field.Separator != "" {
+			cmd = append(cmd, "SEPARATOR", field.Separator)
+		}
+		if field.CaseSensitive {
+			cmd = append(cmd, "CASESENSITIVE")
+		}
+		if field.Sortable {
+			cmd = append(cmd, "SORTABLE")
+		}
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return s == "OK", err
+}
+
+// FTDropIndex deletes an index.
+//
+// Parameters:
+//
+// `index` - string - the name of the index.
+//
+// `deleteDocuments` - bool - also delete the hashes in the index.
+//
+// Returns: true if the index was deleted.
+//
+// Errors:
+//
+// "unknown index <index>" - when the index doesn't exist.
+func (server *SugarDB) FTDropIndex(index string, deleteDocuments bool) (bool, error) {
+	cmd := []string{"FT.DROPINDEX", index}
+	if deleteDocuments {
+		cmd = append(cmd, "DD")
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return s == "OK", err
+}
+
+// FTList returns the names of the indexes of the database in order.
+func (server *SugarDB) FTList() ([]string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"FT._LIST"}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// FTInfo returns the definition of an index and the number of documents it holds.
+//
+// Parameters:
+//
+// `index` - string - the name of the index.
+//
+// Returns: an FTIndexInfo describing the index.
+//
+// Errors:
+//
+// "unknown index <index>" - when the index doesn't exist.
+func (server *SugarDB) FTInfo(index string) (FTIndexInfo, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"FT.INFO", index}), nil, false, true)
+	if err != nil {
+		return FTIndexInfo{}, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return FTIndexInfo{}, err
+	}
+	values, _ := res.([]interface{})
+	var info FTIndexInfo
+	for i := 0; i+1 < len(values); i += 2 {
+		field, _ := values[i].(string)
+		n, _ := values[i+1].(int)
+		switch field {
+		case "index_name":
+			info.Name, _ = values[i+1].(string)
+		case "index_definition":
+			definition, _ := values[i+1].([]interface{})
+			for j := 0; j+1 < len(definition); j += 2 {
+				if name, _ := definition[j].(string); name != "prefixes" {
+					continue
+				}
+				prefixes, _ := definition[j+1].([]interface{})
+				for _, prefix := range prefixes {
+					s, _ := prefix.(string)
+					info.Prefixes = append(info.Prefixes, s)
+				}
+			}
+		case "attributes":
+			attributes, _ := values[i+1].([]interface{})
+			for _, a := range attributes {
+				attribute, _ := a.([]interface{})
+				var f FTField
+				for j := 0; j < len(attribute); j++ {
+					s, _ := attribute[j].(string)
+					var next string
+					if j+1 < len(attribute) {
+						next, _ = attribute[j+1].(string)
+					}
+					switch s {
+					case "identifier":
+						f.Name = next
+						j++
+					case "attribute":
+						f.Alias = next
+						j++
+					case "type":
+						f.Type = next
+						j++
+					case "SEPARATOR":
+						f.Separator = next
+						j++
+					case "CASESENSITIVE":
+						f.CaseSensitive = true
+					case "SORTABLE":
+						f.Sortable = true
+					}
+				}
+				info.Fields = append(info.Fields, f)
+			}
+		case "num_docs":
+			info.NumDocs = n
+		case "num_terms":
+			info.NumTerms = n
+		case "num_records":
+			info.NumRecords = n
+		}
+	}
+	return info, nil
+}
+
+// FTSearch returns the hashes in an index that match the query.
+//
+// Terms separated by spaces must all match, | separates alternatives, - negates an expression and parentheses
+// group expressions. Fields are queried with @field:term for TEXT fields, @field:{tag | tag} for TAG fields and
+// @field:[min max] for NUMERIC fields, where a bound preceded by ( is exclusive. Terms that are not preceded by a
+// field are searched in all the TEXT fields, and terms ending with * match by prefix. "*" matches every hash.
+//
+// Parameters:
+//
+// `index` - string - the name of the index.
+//
+// `query` - string - the query, e.g. `@category:{shoes} @price:[50 100] running`.
+//
+// `options` - FTSearchOptions.
+//
+// Returns: an FTSearchResult with the number of matching hashes and the hashes in the selected page.
+//
+// Errors:
+//
+// "unknown index <index>" - when the index doesn't exist.
+//
+// "unknown field <field>" - when the query or SortBy refers to a field that's not in the index.
+func (server *SugarDB) FTSearch(index, query string, options FTSearchOptions) (FTSearchResult, error) {
+	cmd := []string{"FT.SEARCH", index, query, "WITHSCORES"}
+	if options.NoContent {
+		cmd = append(cmd, "NOCONTENT")
+	} else if len(options.Return) > 0 {
+		cmd = append(cmd, "RETURN", strconv.Itoa(len(options.Return)))
+		cmd = append(cmd, options.Return...)
+	}
+	if options.SortBy != "" {
+		cmd = append(cmd, "SORTBY", options.SortBy)
+		if options.SortDesc {
+			cmd = append(cmd, "DESC")
+		}
+	}
+	if options.Offset > 0 || options.Limit > 0 {
+		limit := options.Limit
+		if limit <= 0 {
+			limit = 10
+		}
+		cmd = append(cmd, "LIMIT", strconv.Itoa(options.Offset), strconv.Itoa(limit))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return FTSearchResult{}, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return FTSearchResult{}, err
+	}
+	values, _ := res.([]interface{})
+	if len(values) == 0 {
+		return FTSearchResult{}, nil
+	}
+
+	result := FTSearchResult{}
+	result.Total, _ = values[0].(int)
+	width := 3
+	if options.NoContent {
+		width = 2
+	}
+	for i := 1; i+width-1 < len(values); i += width {
+		var document FTDocument
+		document.Key, _ = values[i].(string)
+		s, _ := values[i+1].(string)
+		if document.Score, err = strconv.ParseFloat(s, 64); err != nil {
+			return FTSearchResult{}, err
+		}
+		if !options.NoContent {
+			document.Fields = parseFTFields(values[i+2])
+		}
+		result.Documents = append(result.Documents, document)
+	}
+	return result, nil
+}
+
+// FTAggregate groups and sorts the hashes in an index that match the query.
+//
+// Parameters:
+//
+// `index` - string - the name of the index.
+//
+// `query` - string - the query that selects the hashes. See FTSearch.
+//
+// `options` - FTAggregateOptions.
+//
+// Returns: an FTAggregateResult with the number of rows and the rows in the selected page.
+//
+// Errors:
+//
+// "unknown index <index>" - when the index doesn't exist.
+//
+// "unknown reducer <function>" - when a reducer function is not supported.
+func (server *SugarDB) FTAggregate(index, query string, options FTAggregateOptions) (FTAggregateResult, error) {
+	property := func(name string) string {
+		return "@" + strings.TrimPrefix(name, "@")
+	}
+
+	cmd := []string{"FT.AGGREGATE", index, query}
+	if len(options.Load) == 1 && options.Load[0] == "*" {
+		cmd = append(cmd, "LOAD", "*")
+	} else if len(options.Load) > 0 {
+		cmd = append(cmd, "LOAD", strconv.Itoa(len(options.Load)))
+		for _, field := range options.Load {
+			cmd = append(cmd, property(field))
+		}
+	}
+	for _, group := range options.GroupBy {
+		cmd = append(cmd, "GROUPBY", strconv.Itoa(len(group.Properties)))
+		for _, p := range group.Properties {
+			cmd = append(cmd, property(p))
+		}
+		for _, reducer := range group.Reducers {
+			cmd = append(cmd, "REDUCE", reducer.Function)
+			if reducer.Property == "" {
+				cmd = append(cmd, "0")
+			} else {
+				cmd = append(cmd, "1", property(reducer.Property))
+			}
+			if reducer.Alias != "" {
+				cmd = append(cmd, "AS", reducer.Alias)
+			}
+		}
+	}
+	if len(options.SortBy) > 0 {
+		args := make([]string, 0, len(options.SortBy)*2)
+		for _, key := range options.SortBy {
+			args = append(args, property(key.Property))
+			if key.Descending {
+				args = append(args, "DESC")
+			}
+		}
+		cmd = append(cmd, "SORTBY", strconv.Itoa(len(args)))
+		cmd = append(cmd, args...)
+	}
+	if options.Offset > 0 || options.Limit > 0 {
+		limit := strconv.Itoa(options.Limit)
+		if options.Limit <= 0 {
+			// LIMIT requires a number of rows, so the offset is applied to all the rows.
+			limit = strconv.Itoa(int(^uint32(0) >> 1))
+		}
+		cmd = append(cmd, "LIMIT", strconv.Itoa(options.Offset), limit)
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return FTAggregateResult{}, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return FTAggregateResult{}, err
+	}
+	values, _ := res.([]interface{})
+	if len(values) == 0 {
+		return FTAggregateResult{}, nil
+	}
+
+	result := FTAggregateResult{Rows: make([]map[string]string, 0, len(values)-1)}
+	result.Total, _ = values[0].(int)
+	for _, row := range values[1:] {
+		result.Rows = append(result.Rows, parseFTFields(row))
+	}
+	return result, nil
+}
@@ -0,0 +1,267 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSugarDB_Search(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	products := map[string]map[string]string{
+		"product:1": {"name": "Red running shoes", "category": "shoes,sport", "price": "80"},
+		"product:2": {"name": "Blue running shorts", "category": "clothing,sport", "price": "30"},
+		"product:3": {"name": "Red dress shoes", "category": "shoes,formal", "price": "120"},
+		"product:4": {"name": "Green socks", "category": "clothing", "price": "5"},
+	}
+	schema := []FTField{
+		{Name: "name", Type: "TEXT"},
+		{Name: "category", Alias: "tags", Type: "TAG"},
+		{Name: "price", Type: "NUMERIC", Sortable: true},
+	}
+
+	t.Run("TestSugarDB_FTCreate", func(t *testing.T) {
+		mockServer := createSugarDB()
+		defer mockServer.ShutDown()
+
+		if _, err := mockServer.HSet("item:1", map[string]string{"name": "Red hat", "price": "10"}); err != nil {
+			t.Error(err)
+			return
+		}
+		ok, err := mockServer.FTCreate("idx", schema, FTCreateOptions{Prefixes: []string{"item:"}})
+		if err != nil || !ok {
+			t.Errorf("FTCREATE() expected true, got %v, error = %v", ok, err)
+			return
+		}
+		if _, err = mockServer.FTCreate("idx", schema, FTCreateOptions{}); err == nil {
+			t.Error("FTCREATE() expected error for an existing index")
+		}
+		if _, err = mockServer.FTCreate("invalid", []FTField{{Name: "location", Type: "GEO"}}, FTCreateOptions{}); err == nil {
+			t.Error("FTCREATE() expected error for an unknown field type")
+		}
+
+		want := FTIndexInfo{
+			Name:     "idx",
+			Prefixes: []string{"item:"},
+			Fields: []FTField{
+				{Name: "name", Alias: "name", Type: "TEXT"},
+				{Name: "category", Alias: "tags", Type: "TAG", Separator: ","},
+				{Name: "price", Alias: "price", Type: "NUMERIC", Sortable: true},
+			},
+			NumDocs:    1,
+			NumTerms:   2,
+			NumRecords: 3,
+		}
+		if info, err := mockServer.FTInfo("idx"); err != nil || !reflect.DeepEqual(info, want) {
+			t.Errorf("FTINFO() expected %+v, got %+v, error = %v", want, info, err)
+		}
+		if names, err := mockServer.FTList(); err != nil || !reflect.DeepEqual(names, []string{"idx"}) {
+			t.Errorf("FTLIST() expected [idx], got %v, error = %v", names, err)
+		}
+
+		if ok, err = mockServer.FTDropIndex("idx", true); err != nil || !ok {
+			t.Errorf("FTDROPINDEX() expected true, got %v, error = %v", ok, err)
+		}
+		if n, err := mockServer.Exists("item:1"); err != nil || n != 0 {
+			t.Errorf("expected the hash to be deleted with the index, got %d, error = %v", n, err)
+		}
+		if _, err = mockServer.FTInfo("idx"); err == nil {
+			t.Error("FTINFO() expected error for a dropped index")
+		}
+	})
+
+	t.Run("TestSugarDB_FTSearch", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.FTCreate("search_idx", schema, FTCreateOptions{Prefixes: []string{"product:"}}); err != nil {
+			t.Error(err)
+			return
+		}
+		for key, fields := range products {
+			if _, err := server.HSet(key, fields); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		tests := []struct {
+			name      string
+			query     string
+			options   FTSearchOptions
+			wantTotal int
+			wantKeys  []string
+			wantErr   bool
+		}{
+			{
+				name:      "1. Match a numeric range",
+				query:     "@price:[30 100]",
+				options:   FTSearchOptions{NoContent: true, SortBy: "price"},
+				wantTotal: 2,
+				wantKeys:  []string{"product:2", "product:1"},
+			},
+			{
+				name:      "2. Match tags by alias",
+				query:     "@tags:{formal | clothing}",
+				options:   FTSearchOptions{NoContent: true, SortBy: "price", SortDesc: true},
+				wantTotal: 3,
+				wantKeys:  []string{"product:3", "product:2", "product:4"},
+			},
+			{
+				name:      "3. Match text terms and exclude tags",
+				query:     "red -@tags:{formal}",
+				options:   FTSearchOptions{NoContent: true},
+				wantTotal: 1,
+				wantKeys:  []string{"product:1"},
+			},
+			{
+				name:      "4. Select a page of the results",
+				query:     "*",
+				options:   FTSearchOptions{NoContent: true, SortBy: "price", Offset: 1, Limit: 2},
+				wantTotal: 4,
+				wantKeys:  []string{"product:2", "product:1"},
+			},
+			{
+				name:    "5. Throw error when the query is not valid",
+				query:   "@price:[1 2",
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := server.FTSearch("search_idx", tt.query, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("FTSEARCH() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				keys := make([]string, len(got.Documents))
+				for i, document := range got.Documents {
+					keys[i] = document.Key
+				}
+				if got.Total != tt.wantTotal || !reflect.DeepEqual(keys, tt.wantKeys) {
+					t.Errorf("FTSEARCH() got total %d and keys %v, want %d and %v", got.Total, keys, tt.wantTotal, tt.wantKeys)
+				}
+			})
+		}
+
+		t.Run("6. Return the fields of the hashes", func(t *testing.T) {
+			got, err := server.FTSearch("search_idx", "socks", FTSearchOptions{})
+			if err != nil || len(got.Documents) != 1 {
+				t.Errorf("FTSEARCH() expected 1 document, got %+v, error = %v", got, err)
+				return
+			}
+			if !reflect.DeepEqual(got.Documents[0].Fields, products["product:4"]) || got.Documents[0].Score <= 0 {
+				t.Errorf("FTSEARCH() expected the fields %v with a positive score, got %+v", products["product:4"], got.Documents[0])
+			}
+			got, err = server.FTSearch("search_idx", "socks", FTSearchOptions{Return: []string{"tags"}})
+			if err != nil || len(got.Documents) != 1 || !reflect.DeepEqual(got.Documents[0].Fields, map[string]string{"tags": "clothing"}) {
+				t.Errorf("FTSEARCH() expected the returned field, got %+v, error = %v", got, err)
+			}
+		})
+	})
+
+	t.Run("TestSugarDB_FTAggregate", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.FTCreate("aggregate_idx", schema, FTCreateOptions{Prefixes: []string{"aggregate:"}}); err != nil {
+			t.Error(err)
+			return
+		}
+		for key, fields := range products {
+			if _, err := server.HSet("aggregate:"+key, fields); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		got, err := server.FTAggregate("aggregate_idx", "*", FTAggregateOptions{
+			GroupBy: []FTGroupBy{{
+				Properties: []string{"tags"},
+				Reducers: []FTReducer{
+					{Function: "COUNT", Alias: "count"},
+					{Function: "MIN", Property: "price", Alias: "min_price"},
+				},
+			}},
+			SortBy: []FTSortKey{{Property: "count", Descending: true}, {Property: "tags"}},
+			Limit:  2,
+		})
+		want := FTAggregateResult{
+			Total: 4,
+			Rows: []map[string]string{
+				{"tags": "clothing", "count": "1", "min_price": "5"},
+				{"tags": "clothing,sport", "count": "1", "min_price": "30"},
+			},
+		}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("FTAGGREGATE() expected %+v, got %+v, error = %v", want, got, err)
+		}
+
+		got, err = server.FTAggregate("aggregate_idx", "@price:[100 +inf]", FTAggregateOptions{Load: []string{"*"}})
+		want = FTAggregateResult{Total: 1, Rows: []map[string]string{products["product:3"]}}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("FTAGGREGATE() expected %+v, got %+v, error = %v", want, got, err)
+		}
+	})
+
+	t.Run("TestSugarDB_SearchIndexUpdates", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.FTCreate("update_idx", schema, FTCreateOptions{Prefixes: []string{"update:"}}); err != nil {
+			t.Error(err)
+			return
+		}
+		count := func(query string) int {
+			res, err := server.FTSearch("update_idx", query, FTSearchOptions{NoContent: true})
+			if err != nil {
+				t.Error(err)
+			}
+			return res.Total
+		}
+
+		if _, err := server.HSet("update:1", map[string]string{"name": "Yellow hat", "price": "50"}); err != nil {
+			t.Error(err)
+			return
+		}
+		if n := count("yellow"); n != 1 {
+			t.Errorf("expected the new hash to be indexed, got %d documents", n)
+		}
+		if _, err := server.HSet("update:1", map[string]string{"name": "Orange hat"}); err != nil {
+			t.Error(err)
+			return
+		}
+		if n := count("yellow"); n != 0 {
+			t.Errorf("expected the old value to be removed from the index, got %d documents", n)
+		}
+		if n := count("@price:[50 50]"); n != 1 {
+			t.Errorf("expected the other fields to stay indexed, got %d documents", n)
+		}
+		if _, err := server.Del("update:1"); err != nil {
+			t.Error(err)
+			return
+		}
+		if n := count("*"); n != 0 {
+			t.Errorf("expected the deleted hash to be removed from the index, got %d documents", n)
+		}
+	})
+
+}
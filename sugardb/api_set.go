@@ -346,3 +346,39 @@ func (server *SugarDB) SUnionStore(destination string, keys ...string) (int, err
 	}
 	return internal.ParseIntegerResponse(b)
 }
+
+// SSCANOptions modifies the behaviour of the SScan function.
+//
+// Match - string - only return members that match this glob pattern.
+//
+// Count - uint - the number of members to visit in this call. Defaults to 10 when 0.
+type SSCANOptions struct {
+	Match string
+	Count uint
+}
+
+// SScan incrementally iterates over the members of a set. Begin the iteration with a cursor of 0
+// and call SScan again with the returned cursor until it returns 0.
+//
+// Parameters:
+//
+// `key` - string - The key of the set.
+//
+// `cursor` - uint64 - the cursor returned by the previous call, or 0 to begin the iteration.
+//
+// `options` - SSCANOptions.
+//
+// Returns: The cursor to pass to the next call and the members visited in this call.
+// The returned cursor is 0 when the iteration is complete.
+//
+// Errors:
+//
+// "value at <key> is not a set" - when the provided key exists but is not a set.
+func (server *SugarDB) SScan(key string, cursor uint64, options SSCANOptions) (uint64, []string, error) {
+	cmd := append([]string{"SSCAN", key, strconv.FormatUint(cursor, 10)}, scanOptions(options.Match, options.Count)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, nil, err
+	}
+	return internal.ParseScanResponse(b)
+}
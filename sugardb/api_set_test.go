@@ -1216,4 +1216,31 @@ func TestSugarDB_Set(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_SSCAN", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.SAdd("sscan_key1", "one", "two", "three", "four", "five", "six"); err != nil {
+			t.Error(err)
+			return
+		}
+
+		var got []string
+		var cursor uint64
+		for {
+			next, members, err := server.SScan("sscan_key1", cursor, SSCANOptions{Match: "t*", Count: 2})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got = append(got, members...)
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+		slices.Sort(got)
+		if !reflect.DeepEqual(got, []string{"three", "two"}) {
+			t.Errorf("SSCAN() got = %v, want [three two]", got)
+		}
+	})
 }
@@ -17,6 +17,7 @@ package sugardb
 import (
 	"github.com/echovault/sugardb/internal"
 	"strconv"
+	"time"
 )
 
 // ZAddOptions allows you to modify the effects of the ZAdd command.
@@ -645,6 +646,43 @@ func (server *SugarDB) ZPopMin(key string, count uint) ([][]string, error) {
 	return internal.ParseNestedStringArrayResponse(b)
 }
 
+// BZPopMin removes and returns the member with the lowest score from the first non-empty sorted set.
+// If all the sorted sets are empty, it blocks until a member is added to one of them or until the timeout elapses.
+//
+// Parameters:
+//
+// `timeout` - time.Duration - the maximum time to block for. A timeout of 0 blocks indefinitely.
+//
+// `keys` - ...string - the keys to the sorted sets, checked in order.
+//
+// Returns: The key of the sorted set the member was popped from, and a slice containing the member and its score
+// at the 0 and 1 indices respectively. An empty key and a nil slice are returned if the timeout elapses.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when a key exists but is not a sorted set.
+func (server *SugarDB) BZPopMin(timeout time.Duration, keys ...string) (string, []string, error) {
+	return server.blockingZPop("BZPOPMIN", timeout, keys)
+}
+
+// BZPopMax works like BZPopMin but removes and returns the member with the highest score.
+func (server *SugarDB) BZPopMax(timeout time.Duration, keys ...string) (string, []string, error) {
+	return server.blockingZPop("BZPOPMAX", timeout, keys)
+}
+
+func (server *SugarDB) blockingZPop(command string, timeout time.Duration, keys []string) (string, []string, error) {
+	cmd := append(append([]string{command}, keys...), strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64))
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", nil, err
+	}
+	res, err := internal.ParseStringArrayResponse(b)
+	if err != nil || len(res) != 3 {
+		return "", nil, err
+	}
+	return res[0], res[1:], nil
+}
+
 // ZRandMember Returns a list of length equivalent to 'count' containing random members of the sorted set.
 // If count is negative, repeated elements are allowed. If count is positive, the returned elements will be distinct.
 // The default count is 1. If a count of 0 is passed, it will be ignored.
@@ -1019,3 +1057,239 @@ func (server *SugarDB) ZRangeStore(destination, source, start, stop string, opti
 
 	return internal.ParseIntegerResponse(b)
 }
+
+// ZRangeByOptions modifies the behaviour of the ZRangeByScore, ZRevRangeByScore and ZRangeByLex functions.
+//
+// WithScores specifies whether to return the associated scores. It is ignored by ZRangeByLex.
+//
+// Offset specifies the number of members to skip.
+//
+// Count specifies the maximum number of members to return. A Count of 0 returns all the members after the offset.
+type ZRangeByOptions struct {
+	WithScores bool
+	Offset     uint
+	Count      uint
+}
+
+func (options ZRangeByOptions) args(withscores bool) []string {
+	var args []string
+	if withscores && options.WithScores {
+		args = append(args, "WITHSCORES")
+	}
+	if options.Offset != 0 || options.Count != 0 {
+		count := "-1"
+		if options.Count != 0 {
+			count = strconv.Itoa(int(options.Count))
+		}
+		args = append(args, "LIMIT", strconv.Itoa(int(options.Offset)), count)
+	}
+	return args
+}
+
+// buildMemberSlices groups a flat list of members, each optionally followed by its score, into one slice per member.
+func buildMemberSlices(arr []string, withscores bool) [][]string {
+	step := 1
+	if withscores {
+		step = 2
+	}
+	res := make([][]string, 0, len(arr)/step)
+	for i := 0; i+step <= len(arr); i += step {
+		res = append(res, arr[i:i+step])
+	}
+	return res
+}
+
+// ZRangeByScore returns the members of the sorted set with scores between min and max, ordered from the lowest score.
+//
+// Parameters:
+//
+// `key` - string - The key to the sorted set.
+//
+// `min` - string - The minimum score. Prefix with "(" for an exclusive bound. Can be "-inf".
+//
+// `max` - string - The maximum score. Prefix with "(" for an exclusive bound. Can be "+inf".
+//
+// `options` - ZRangeByOptions
+//
+// Returns: A 2-dimensional slice where each slice contains a member, and its score when WithScores is true.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when a key exists but is not a sorted set.
+func (server *SugarDB) ZRangeByScore(key, min, max string, options ZRangeByOptions) ([][]string, error) {
+	cmd := append([]string{"ZRANGEBYSCORE", key, min, max}, options.args(true)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := internal.ParseStringArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	return buildMemberSlices(arr, options.WithScores), nil
+}
+
+// ZRevRangeByScore returns the members of the sorted set with scores between max and min, ordered from the
+// highest score.
+//
+// Parameters:
+//
+// `key` - string - The key to the sorted set.
+//
+// `max` - string - The maximum score. Prefix with "(" for an exclusive bound. Can be "+inf".
+//
+// `min` - string - The minimum score. Prefix with "(" for an exclusive bound. Can be "-inf".
+//
+// `options` - ZRangeByOptions
+//
+// Returns: A 2-dimensional slice where each slice contains a member, and its score when WithScores is true.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when a key exists but is not a sorted set.
+func (server *SugarDB) ZRevRangeByScore(key, max, min string, options ZRangeByOptions) ([][]string, error) {
+	cmd := append([]string{"ZREVRANGEBYSCORE", key, max, min}, options.args(true)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := internal.ParseStringArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	return buildMemberSlices(arr, options.WithScores), nil
+}
+
+// ZRangeByLex returns the members of the sorted set between min and max in lexicographical order.
+// All the members must have the same score.
+//
+// Parameters:
+//
+// `key` - string - The key to the sorted set.
+//
+// `min` - string - The minimum boundary. "-" or a value prefixed with "[" (inclusive) or "(" (exclusive).
+//
+// `max` - string - The maximum boundary. "+" or a value prefixed with "[" (inclusive) or "(" (exclusive).
+//
+// `options` - ZRangeByOptions
+//
+// Returns: The members within the range. Returns an empty slice if the members don't all have the same score.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when a key exists but is not a sorted set.
+func (server *SugarDB) ZRangeByLex(key, min, max string, options ZRangeByOptions) ([]string, error) {
+	cmd := append([]string{"ZRANGEBYLEX", key, min, max}, options.args(false)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// ZRevRange returns the members of the sorted set with indices from start to stop, ordered from the highest score.
+//
+// Parameters:
+//
+// `key` - string - The key to the sorted set.
+//
+// `start` - int - The start index. Index 0 is the member with the highest score. Negative indices count from
+// the member with the lowest score.
+//
+// `stop` - int - The stop index, inclusive.
+//
+// `withscores` - bool - Whether to return the associated scores.
+//
+// Returns: A 2-dimensional slice where each slice contains a member, and its score when withscores is true.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when a key exists but is not a sorted set.
+func (server *SugarDB) ZRevRange(key string, start, stop int, withscores bool) ([][]string, error) {
+	cmd := []string{"ZREVRANGE", key, strconv.Itoa(start), strconv.Itoa(stop)}
+	if withscores {
+		cmd = append(cmd, "WITHSCORES")
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	arr, err := internal.ParseStringArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	return buildMemberSlices(arr, withscores), nil
+}
+
+// ZInterCard returns the cardinality of the intersection of the sorted sets.
+//
+// Parameters:
+//
+// `keys` - []string - The keys to the sorted sets.
+//
+// `limit` - uint - Stop counting when the cardinality reaches the limit. A limit of 0 means no limit.
+//
+// Returns: The number of members in the intersection. Non-existent keys are treated as empty sets.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when a key exists but is not a sorted set.
+func (server *SugarDB) ZInterCard(keys []string, limit uint) (int, error) {
+	cmd := append([]string{"ZINTERCARD", strconv.Itoa(len(keys))}, keys...)
+	if limit != 0 {
+		cmd = append(cmd, "LIMIT", strconv.Itoa(int(limit)))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// ZSCANOptions modifies the behaviour of the ZScan function.
+//
+// Match - string - only return members that match this glob pattern.
+//
+// Count - uint - the number of members to visit in this call. Defaults to 10 when 0.
+type ZSCANOptions struct {
+	Match string
+	Count uint
+}
+
+// ZScan incrementally iterates over the members of a sorted set. Begin the iteration with a cursor of 0
+// and call ZScan again with the returned cursor until it returns 0.
+//
+// Parameters:
+//
+// `key` - string - The key of the sorted set.
+//
+// `cursor` - uint64 - the cursor returned by the previous call, or 0 to begin the iteration.
+//
+// `options` - ZSCANOptions.
+//
+// Returns: The cursor to pass to the next call and a map of the members visited in this call to their scores.
+// The returned cursor is 0 when the iteration is complete.
+//
+// Errors:
+//
+// "value at <key> is not a sorted set" - when the provided key exists but is not a sorted set.
+func (server *SugarDB) ZScan(key string, cursor uint64, options ZSCANOptions) (uint64, map[string]float64, error) {
+	cmd := append([]string{"ZSCAN", key, strconv.FormatUint(cursor, 10)}, scanOptions(options.Match, options.Count)...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, nil, err
+	}
+	next, elements, err := internal.ParseScanResponse(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	members := make(map[string]float64, len(elements)/2)
+	for i := 0; i+1 < len(elements); i += 2 {
+		score, err := strconv.ParseFloat(elements[i+1], 64)
+		if err != nil {
+			return 0, nil, err
+		}
+		members[elements[i]] = score
+	}
+	return next, members, nil
+}
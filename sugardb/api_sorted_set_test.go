@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestSugarDB_SortedSet(t *testing.T) {
@@ -3590,4 +3591,166 @@ func TestSugarDB_SortedSet(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_BZPOPMIN", func(t *testing.T) {
+		t.Parallel()
+
+		go func() {
+			<-time.After(100 * time.Millisecond)
+			_, _ = server.ZAdd("bzpopmin_key1", map[string]float64{"one": 1, "two": 2}, ZAddOptions{})
+		}()
+		key, got, err := server.BZPopMin(5*time.Second, "bzpopmin_empty1", "bzpopmin_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if key != "bzpopmin_key1" || !reflect.DeepEqual(got, []string{"one", "1"}) {
+			t.Errorf("BZPOPMIN() got = (%s, %v), want (bzpopmin_key1, [one 1])", key, got)
+		}
+
+		key, got, err = server.BZPopMin(100*time.Millisecond, "bzpopmin_empty2")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if key != "" || got != nil {
+			t.Errorf("BZPOPMIN() got = (%s, %v), want empty result", key, got)
+		}
+	})
+
+	t.Run("TestSugarDB_BZPOPMAX", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := server.ZAdd("bzpopmax_key1", map[string]float64{"one": 1, "two": 2}, ZAddOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		key, got, err := server.BZPopMax(time.Second, "bzpopmax_key1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if key != "bzpopmax_key1" || !reflect.DeepEqual(got, []string{"two", "2"}) {
+			t.Errorf("BZPOPMAX() got = (%s, %v), want (bzpopmax_key1, [two 2])", key, got)
+		}
+	})
+
+	t.Run("TestSugarDB_ZSCAN", func(t *testing.T) {
+		t.Parallel()
+
+		want := map[string]float64{"one": 1, "two": 2, "three": 3.5, "four": 4, "five": 5}
+		if _, err := server.ZAdd("zscan_key1", want, ZAddOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		got := make(map[string]float64)
+		var cursor uint64
+		for {
+			next, members, err := server.ZScan("zscan_key1", cursor, ZSCANOptions{Count: 2})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for member, score := range members {
+				got[member] = score
+			}
+			if cursor = next; cursor == 0 {
+				break
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ZSCAN() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("TestSugarDB_ZRangeByCommands", func(t *testing.T) {
+		t.Parallel()
+
+		members := map[string]float64{"one": 1, "two": 2, "three": 3, "four": 4}
+		if _, err := server.ZAdd("zrangeby_key1", members, ZAddOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := server.ZAdd("zrangeby_key2", map[string]float64{"a": 0, "b": 0, "c": 0}, ZAddOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := server.ZAdd("zrangeby_key3", map[string]float64{"two": 1, "four": 1, "six": 1}, ZAddOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		tests := []struct {
+			name    string
+			call    func() (interface{}, error)
+			want    interface{}
+			wantErr bool
+		}{
+			{
+				name: "1. ZRangeByScore with scores and a limit",
+				call: func() (interface{}, error) {
+					return server.ZRangeByScore("zrangeby_key1", "(1", "+inf", ZRangeByOptions{WithScores: true, Count: 2})
+				},
+				want: [][]string{{"two", "2"}, {"three", "3"}},
+			},
+			{
+				name: "2. ZRevRangeByScore with an offset",
+				call: func() (interface{}, error) {
+					return server.ZRevRangeByScore("zrangeby_key1", "4", "1", ZRangeByOptions{Offset: 1})
+				},
+				want: [][]string{{"three"}, {"two"}, {"one"}},
+			},
+			{
+				name: "3. ZRangeByLex",
+				call: func() (interface{}, error) {
+					return server.ZRangeByLex("zrangeby_key2", "(a", "+", ZRangeByOptions{})
+				},
+				want: []string{"b", "c"},
+			},
+			{
+				name: "4. ZRevRange with scores",
+				call: func() (interface{}, error) {
+					return server.ZRevRange("zrangeby_key1", 0, 1, true)
+				},
+				want: [][]string{{"four", "4"}, {"three", "3"}},
+			},
+			{
+				name: "5. ZInterCard",
+				call: func() (interface{}, error) {
+					return server.ZInterCard([]string{"zrangeby_key1", "zrangeby_key3"}, 0)
+				},
+				want: 2,
+			},
+			{
+				name: "6. ZInterCard with a limit",
+				call: func() (interface{}, error) {
+					return server.ZInterCard([]string{"zrangeby_key1", "zrangeby_key3"}, 1)
+				},
+				want: 1,
+			},
+			{
+				name: "7. Return error when the key is not a sorted set",
+				call: func() (interface{}, error) {
+					if err := presetValue(server, context.Background(), "zrangeby_key4", "not a sorted set"); err != nil {
+						return nil, err
+					}
+					return server.ZRevRange("zrangeby_key4", 0, -1, false)
+				},
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := tt.call()
+				if (err != nil) != tt.wantErr {
+					t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,652 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// StreamEntry is an entry of a stream.
+//
+// ID is the entry's ID in the "<milliseconds>-<sequence>" format.
+//
+// Fields contains the field-value pairs of the entry. Fields is nil when the entry has been deleted from the stream
+// but is still in a consumer group's pending entries list.
+type StreamEntry struct {
+	ID     string
+	Fields map[string]string
+}
+
+// StreamPendingSummary is the summary of a consumer group's pending entries list returned by XPending.
+//
+// Count is the number of pending entries.
+//
+// MinID and MaxID are the smallest and greatest IDs in the pending entries list. They're empty when there are no
+// pending entries.
+//
+// Consumers maps each consumer that has pending entries to its number of pending entries.
+type StreamPendingSummary struct {
+	Count     int
+	MinID     string
+	MaxID     string
+	Consumers map[string]int
+}
+
+// StreamPendingEntry is an entry in a consumer group's pending entries list returned by XPendingRange.
+//
+// Idle is the time elapsed since the entry was last delivered.
+//
+// DeliveryCount is the number of times the entry has been delivered.
+type StreamPendingEntry struct {
+	ID            string
+	Consumer      string
+	Idle          time.Duration
+	DeliveryCount int
+}
+
+// XAddOptions allows you to modify the effects of the XAdd command.
+//
+// ID is the ID of the new entry. Defaults to "*", which generates the ID from the server time.
+// "<milliseconds>-*" generates the sequence number for the given time.
+//
+// NoMkStream prevents the stream from being created if it does not exist.
+//
+// MaxLen trims the stream to the given number of entries after the entry is added. The stream is not trimmed when
+// MaxLen is 0.
+type XAddOptions struct {
+	ID         string
+	NoMkStream bool
+	MaxLen     uint
+}
+
+// XReadOptions allows you to modify the effects of the XRead command.
+//
+// Count is the maximum number of entries to return from each stream. All the entries are returned when Count is 0.
+//
+// Block waits for new entries when there are none. The command blocks for Timeout, or indefinitely if Timeout is 0.
+type XReadOptions struct {
+	Count   uint
+	Block   bool
+	Timeout time.Duration
+}
+
+// XReadGroupOptions allows you to modify the effects of the XReadGroup command.
+//
+// Count, Block and Timeout behave the same way as in XReadOptions.
+//
+// NoAck delivers the entries without adding them to the group's pending entries list.
+type XReadGroupOptions struct {
+	Count   uint
+	Block   bool
+	Timeout time.Duration
+	NoAck   bool
+}
+
+// XGroupCreateOptions allows you to modify the effects of the XGroupCreate command.
+//
+// MkStream creates an empty stream if the stream does not exist.
+type XGroupCreateOptions struct {
+	MkStream bool
+}
+
+// XPendingOptions allows you to modify the result of the XPendingRange command.
+//
+// Idle only returns the entries that have been idle for at least the given duration.
+//
+// Consumer only returns the entries of the given consumer.
+type XPendingOptions struct {
+	Idle     time.Duration
+	Consumer string
+}
+
+// XClaimOptions allows you to modify the effects of the XClaim command.
+//
+// Idle sets the idle time of the claimed entries. Time sets the last delivery time of the claimed entries instead.
+//
+// RetryCount sets the delivery count of the claimed entries. By default, the delivery count is incremented.
+//
+// Force claims the IDs that exist in the stream even if they're not in the pending entries list.
+//
+// JustID returns only the IDs of the claimed entries and does not increment the delivery count.
+type XClaimOptions struct {
+	Idle       time.Duration
+	Time       time.Time
+	RetryCount uint
+	Force      bool
+	JustID     bool
+}
+
+func parseStreamEntry(value interface{}) (StreamEntry, error) {
+	arr, ok := value.([]interface{})
+	if !ok || len(arr) != 2 {
+		return StreamEntry{}, errors.New("invalid stream entry response")
+	}
+	entry := StreamEntry{ID: arr[0].(string)}
+	if arr[1] == nil {
+		return entry, nil
+	}
+	fields, ok := arr[1].([]interface{})
+	if !ok {
+		return StreamEntry{}, errors.New("invalid stream entry response")
+	}
+	entry.Fields = make(map[string]string, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		entry.Fields[fields[i].(string)] = fields[i+1].(string)
+	}
+	return entry, nil
+}
+
+func parseStreamEntries(value interface{}) ([]StreamEntry, error) {
+	arr, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid stream entries response")
+	}
+	entries := make([]StreamEntry, len(arr))
+	for i, v := range arr {
+		entry, err := parseStreamEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+// parseStreamsResponse parses the response of XREAD and XREADGROUP.
+func parseStreamsResponse(b []byte) (map[string][]StreamEntry, error) {
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	streams := make(map[string][]StreamEntry)
+	if res == nil {
+		return streams, nil
+	}
+	arr, ok := res.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid streams response")
+	}
+	for _, v := range arr {
+		stream, ok := v.([]interface{})
+		if !ok || len(stream) != 2 {
+			return nil, errors.New("invalid streams response")
+		}
+		entries, err := parseStreamEntries(stream[1])
+		if err != nil {
+			return nil, err
+		}
+		streams[stream[0].(string)] = entries
+	}
+	return streams, nil
+}
+
+// streamsArgs returns the STREAMS arguments of XREAD and XREADGROUP. The keys are sorted.
+func streamsArgs(streams map[string]string) []string {
+	keys := make([]string, 0, len(streams))
+	for key := range streams {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	args := append([]string{"STREAMS"}, keys...)
+	for _, key := range keys {
+		args = append(args, streams[key])
+	}
+	return args
+}
+
+// XAdd appends an entry to the stream.
+//
+// Parameters:
+//
+// `key` - string - the key to the stream.
+//
+// `values` - map[string]string - the field-value pairs of the entry. The fields are stored in sorted order.
+//
+// `options` - XAddOptions.
+//
+// Returns: The ID of the added entry. An empty string is returned if the stream does not exist and
+// NoMkStream is true.
+//
+// Errors:
+//
+// "value at key <key> is not a stream" - when the provided key exists but is not a stream.
+//
+// "the ID specified in XADD must be greater than 0-0" - when the provided ID is 0-0.
+//
+// "the ID specified in XADD is equal or smaller than the target stream top item" - when the provided ID is not
+// greater than the last ID of the stream.
+func (server *SugarDB) XAdd(key string, values map[string]string, options XAddOptions) (string, error) {
+	cmd := []string{"XADD", key}
+	if options.NoMkStream {
+		cmd = append(cmd, "NOMKSTREAM")
+	}
+	if options.MaxLen > 0 {
+		cmd = append(cmd, "MAXLEN", strconv.FormatUint(uint64(options.MaxLen), 10))
+	}
+	id := options.ID
+	if id == "" {
+		id = "*"
+	}
+	cmd = append(cmd, id)
+
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	for _, field := range fields {
+		cmd = append(cmd, field, values[field])
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// XLen returns the number of entries in the stream.
+//
+// Parameters:
+//
+// `key` - string - the key to the stream.
+//
+// Returns: The number of entries in the stream. 0 is returned if the stream does not exist.
+//
+// Errors:
+//
+// "value at key <key> is not a stream" - when the provided key exists but is not a stream.
+func (server *SugarDB) XLen(key string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"XLEN", key}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// XRange returns the entries of the stream with IDs between start and end inclusive, in ascending order.
+//
+// Parameters:
+//
+// `key` - string - the key to the stream.
+//
+// `start` - string - the start ID. "-" is the smallest possible ID. Prefix the ID with "(" to make it exclusive.
+//
+// `end` - string - the end ID. "+" is the greatest possible ID. Prefix the ID with "(" to make it exclusive.
+//
+// `count` - uint - the maximum number of entries to return. All the entries in the range are returned when count is 0.
+//
+// Returns: The entries in the range.
+//
+// Errors:
+//
+// "value at key <key> is not a stream" - when the provided key exists but is not a stream.
+func (server *SugarDB) XRange(key, start, end string, count uint) ([]StreamEntry, error) {
+	cmd := []string{"XRANGE", key, start, end}
+	if count > 0 {
+		cmd = append(cmd, "COUNT", strconv.FormatUint(uint64(count), 10))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	return parseStreamEntries(res)
+}
+
+// XRevRange works like XRange but returns the entries in descending order. The end ID comes before the start ID.
+func (server *SugarDB) XRevRange(key, end, start string, count uint) ([]StreamEntry, error) {
+	cmd := []string{"XREVRANGE", key, end, start}
+	if count > 0 {
+		cmd = append(cmd, "COUNT", strconv.FormatUint(uint64(count), 10))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	return parseStreamEntries(res)
+}
+
+// XRead returns the entries with IDs greater than the given IDs from each stream.
+//
+// Parameters:
+//
+// `streams` - map[string]string - maps each stream key to the ID after which to read. "$" is the last ID of the
+// stream at the time the command is executed.
+//
+// `options` - XReadOptions.
+//
+// Returns: A map of the stream keys to the entries read from them. Streams without new entries are omitted.
+// An empty map is returned if the command times out.
+//
+// Errors:
+//
+// "value at key <key> is not a stream" - when a provided key exists but is not a stream.
+func (server *SugarDB) XRead(streams map[string]string, options XReadOptions) (map[string][]StreamEntry, error) {
+	cmd := []string{"XREAD"}
+	if options.Count > 0 {
+		cmd = append(cmd, "COUNT", strconv.FormatUint(uint64(options.Count), 10))
+	}
+	if options.Block {
+		cmd = append(cmd, "BLOCK", strconv.FormatInt(options.Timeout.Milliseconds(), 10))
+	}
+	cmd = append(cmd, streamsArgs(streams)...)
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseStreamsResponse(b)
+}
+
+// XReadGroup reads entries from the streams on behalf of a consumer of the group.
+//
+// Parameters:
+//
+// `group` - string - the consumer group.
+//
+// `consumer` - string - the consumer. The consumer is created if it does not exist.
+//
+// `streams` - map[string]string - maps each stream key to an ID. ">" reads the entries that were never delivered to
+// the group and adds them to the group's pending entries list. Any other ID reads the consumer's pending entries with
+// greater IDs.
+//
+// `options` - XReadGroupOptions. The command only blocks when all the IDs are ">".
+//
+// Returns: A map of the stream keys to the entries read from them.
+//
+// Errors:
+//
+// "NOGROUP No such key '<key>' or consumer group '<group>'" - when a stream or the group does not exist.
+func (server *SugarDB) XReadGroup(group, consumer string, streams map[string]string, options XReadGroupOptions) (map[string][]StreamEntry, error) {
+	cmd := []string{"XREADGROUP", "GROUP", group, consumer}
+	if options.Count > 0 {
+		cmd = append(cmd, "COUNT", strconv.FormatUint(uint64(options.Count), 10))
+	}
+	if options.Block {
+		cmd = append(cmd, "BLOCK", strconv.FormatInt(options.Timeout.Milliseconds(), 10))
+	}
+	if options.NoAck {
+		cmd = append(cmd, "NOACK")
+	}
+	cmd = append(cmd, streamsArgs(streams)...)
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseStreamsResponse(b)
+}
+
+// XGroupCreate creates a consumer group.
+//
+// Parameters:
+//
+// `key` - string - the key to the stream.
+//
+// `group` - string - the name of the group.
+//
+// `id` - string - the group delivers the entries with IDs greater than id. "$" is the last ID of the stream.
+//
+// `options` - XGroupCreateOptions.
+//
+// Returns: true if the group is created.
+//
+// Errors:
+//
+// "BUSYGROUP consumer group name already exists" - when the group already exists.
+func (server *SugarDB) XGroupCreate(key, group, id string, options XGroupCreateOptions) (bool, error) {
+	cmd := []string{"XGROUP", "CREATE", key, group, id}
+	if options.MkStream {
+		cmd = append(cmd, "MKSTREAM")
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// XGroupDestroy deletes a consumer group.
+//
+// Returns: true if the group was deleted and false if it did not exist.
+func (server *SugarDB) XGroupDestroy(key, group string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"XGROUP", "DESTROY", key, group}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// XGroupCreateConsumer creates a consumer in the group.
+//
+// Returns: true if the consumer was created and false if it already exists.
+func (server *SugarDB) XGroupCreateConsumer(key, group, consumer string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"XGROUP", "CREATECONSUMER", key, group, consumer}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	return internal.ParseBooleanResponse(b)
+}
+
+// XGroupDelConsumer deletes a consumer from the group along with its pending entries.
+//
+// Returns: The number of pending entries the consumer had.
+func (server *SugarDB) XGroupDelConsumer(key, group, consumer string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"XGROUP", "DELCONSUMER", key, group, consumer}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// XGroupSetID sets the last delivered ID of the consumer group. "$" is the last ID of the stream.
+//
+// Returns: true if the ID is set.
+func (server *SugarDB) XGroupSetID(key, group, id string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"XGROUP", "SETID", key, group, id}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return strings.EqualFold(s, "ok"), err
+}
+
+// XAck removes entries from the group's pending entries list.
+//
+// Parameters:
+//
+// `key` - string - the key to the stream.
+//
+// `group` - string - the consumer group.
+//
+// `ids` - ...string - the IDs of the entries to acknowledge.
+//
+// Returns: The number of acknowledged entries.
+func (server *SugarDB) XAck(key, group string, ids ...string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(append([]string{"XACK", key, group}, ids...)), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// XPending returns a summary of the group's pending entries list.
+//
+// Errors:
+//
+// "NOGROUP No such key '<key>' or consumer group '<group>'" - when the stream or the group does not exist.
+func (server *SugarDB) XPending(key, group string) (StreamPendingSummary, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"XPENDING", key, group}), nil, false, true)
+	if err != nil {
+		return StreamPendingSummary{}, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return StreamPendingSummary{}, err
+	}
+	arr, ok := res.([]interface{})
+	if !ok || len(arr) != 4 {
+		return StreamPendingSummary{}, errors.New("invalid pending summary response")
+	}
+
+	summary := StreamPendingSummary{Count: arr[0].(int), Consumers: make(map[string]int)}
+	if summary.Count == 0 {
+		return summary, nil
+	}
+	summary.MinID, summary.MaxID = arr[1].(string), arr[2].(string)
+	for _, v := range arr[3].([]interface{}) {
+		consumer := v.([]interface{})
+		count, err := strconv.Atoi(consumer[1].(string))
+		if err != nil {
+			return StreamPendingSummary{}, err
+		}
+		summary.Consumers[consumer[0].(string)] = count
+	}
+	return summary, nil
+}
+
+// XPendingRange returns the entries in the group's pending entries list with IDs between start and end inclusive.
+//
+// Parameters:
+//
+// `key` - string - the key to the stream.
+//
+// `group` - string - the consumer group.
+//
+// `start` - string - the start ID. "-" is the smallest possible ID.
+//
+// `end` - string - the end ID. "+" is the greatest possible ID.
+//
+// `count` - uint - the maximum number of entries to return.
+//
+// `options` - XPendingOptions.
+//
+// Returns: The pending entries in the range.
+//
+// Errors:
+//
+// "NOGROUP No such key '<key>' or consumer group '<group>'" - when the stream or the group does not exist.
+func (server *SugarDB) XPendingRange(key, group, start, end string, count uint, options XPendingOptions) ([]StreamPendingEntry, error) {
+	cmd := []string{"XPENDING", key, group}
+	if options.Idle > 0 {
+		cmd = append(cmd, "IDLE", strconv.FormatInt(options.Idle.Milliseconds(), 10))
+	}
+	cmd = append(cmd, start, end, strconv.FormatUint(uint64(count), 10))
+	if options.Consumer != "" {
+		cmd = append(cmd, options.Consumer)
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := res.([]interface{})
+	if !ok {
+		return nil, errors.New("invalid pending entries response")
+	}
+
+	entries := make([]StreamPendingEntry, len(arr))
+	for i, v := range arr {
+		entry := v.([]interface{})
+		entries[i] = StreamPendingEntry{
+			ID:            entry[0].(string),
+			Consumer:      entry[1].(string),
+			Idle:          time.Duration(entry[2].(int)) * time.Millisecond,
+			DeliveryCount: entry[3].(int),
+		}
+	}
+	return entries, nil
+}
+
+// XClaim changes the ownership of pending entries that have been idle for at least minIdle to the consumer.
+//
+// Parameters:
+//
+// `key` - string - the key to the stream.
+//
+// `group` - string - the consumer group.
+//
+// `consumer` - string - the consumer that claims the entries.
+//
+// `minIdle` - time.Duration - only entries that have been idle for at least minIdle are claimed.
+//
+// `ids` - []string - the IDs of the entries to claim.
+//
+// `options` - XClaimOptions.
+//
+// Returns: The claimed entries. When JustID is true, only the ID of each entry is set.
+//
+// Errors:
+//
+// "NOGROUP No such key '<key>' or consumer group '<group>'" - when the stream or the group does not exist.
+func (server *SugarDB) XClaim(key, group, consumer string, minIdle time.Duration, ids []string, options XClaimOptions) ([]StreamEntry, error) {
+	cmd := append([]string{"XCLAIM", key, group, consumer, strconv.FormatInt(minIdle.Milliseconds(), 10)}, ids...)
+	switch {
+	case options.Idle > 0:
+		cmd = append(cmd, "IDLE", strconv.FormatInt(options.Idle.Milliseconds(), 10))
+	case !options.Time.IsZero():
+		cmd = append(cmd, "TIME", strconv.FormatInt(options.Time.UnixMilli(), 10))
+	}
+	if options.RetryCount > 0 {
+		cmd = append(cmd, "RETRYCOUNT", strconv.FormatUint(uint64(options.RetryCount), 10))
+	}
+	if options.Force {
+		cmd = append(cmd, "FORCE")
+	}
+	if options.JustID {
+		cmd = append(cmd, "JUSTID")
+	}
+
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	if !options.JustID {
+		res, err := internal.ParseAnyResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		return parseStreamEntries(res)
+	}
+
+	claimed, err := internal.ParseStringArrayResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]StreamEntry, len(claimed))
+	for i, id := range claimed {
+		entries[i] = StreamEntry{ID: id}
+	}
+	return entries, nil
+}
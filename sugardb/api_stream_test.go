@@ -0,0 +1,283 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSugarDB_Stream(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	t.Run("TestSugarDB_XAdd", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			key         string
+			values      map[string]string
+			options     XAddOptions
+			want        string
+			wantLen     int
+			wantErr     bool
+		}{
+			{
+				name:    "1. Generate the ID from the server clock",
+				key:     "xadd_key1",
+				values:  map[string]string{"field1": "value1"},
+				options: XAddOptions{},
+				want:    "1136189045000-0",
+				wantLen: 1,
+			},
+			{
+				name:    "2. Add an entry with an explicit ID",
+				key:     "xadd_key2",
+				values:  map[string]string{"field1": "value1", "field2": "value2"},
+				options: XAddOptions{ID: "5-1"},
+				want:    "5-1",
+				wantLen: 1,
+			},
+			{
+				name:    "3. Do not create the stream when NoMkStream is true",
+				key:     "xadd_key3",
+				values:  map[string]string{"field1": "value1"},
+				options: XAddOptions{NoMkStream: true},
+				want:    "",
+				wantLen: 0,
+			},
+			{
+				name:        "4. Return error when the key is not a stream",
+				presetValue: "value",
+				key:         "xadd_key4",
+				values:      map[string]string{"field1": "value1"},
+				wantErr:     true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.presetValue != nil {
+					if err := presetValue(server, context.Background(), tt.key, tt.presetValue); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.XAdd(tt.key, tt.values, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("XAdd() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				if got != tt.want {
+					t.Errorf("XAdd() got = %v, want %v", got, tt.want)
+				}
+				length, err := server.XLen(tt.key)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if length != tt.wantLen {
+					t.Errorf("XLen() got = %v, want %v", length, tt.wantLen)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_XRange", func(t *testing.T) {
+		t.Parallel()
+
+		key := "xrange_key1"
+		for _, id := range []string{"1-0", "2-0", "3-0"} {
+			if _, err := server.XAdd(key, map[string]string{"id": id}, XAddOptions{ID: id}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		got, err := server.XRange(key, "(1-0", "+", 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := []StreamEntry{
+			{ID: "2-0", Fields: map[string]string{"id": "2-0"}},
+			{ID: "3-0", Fields: map[string]string{"id": "3-0"}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("XRange() got = %v, want %v", got, want)
+		}
+
+		got, err = server.XRevRange(key, "+", "-", 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want = []StreamEntry{{ID: "3-0", Fields: map[string]string{"id": "3-0"}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("XRevRange() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("TestSugarDB_XRead", func(t *testing.T) {
+		t.Parallel()
+
+		key := "xread_key1"
+		if _, err := server.XAdd(key, map[string]string{"a": "1"}, XAddOptions{ID: "1-0"}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		got, err := server.XRead(map[string]string{key: "0", "xread_key2": "0"}, XReadOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := map[string][]StreamEntry{key: {{ID: "1-0", Fields: map[string]string{"a": "1"}}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("XRead() got = %v, want %v", got, want)
+		}
+
+		// Block until an entry is added.
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = server.XAdd(key, map[string]string{"b": "2"}, XAddOptions{ID: "2-0"})
+		}()
+		got, err = server.XRead(map[string]string{key: "$"}, XReadOptions{Block: true, Timeout: 5 * time.Second})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want = map[string][]StreamEntry{key: {{ID: "2-0", Fields: map[string]string{"b": "2"}}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("XRead() got = %v, want %v", got, want)
+		}
+
+		// Time out when no entry is added.
+		got, err = server.XRead(map[string]string{key: "$"}, XReadOptions{Block: true, Timeout: 10 * time.Millisecond})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(got) != 0 {
+			t.Errorf("XRead() expected empty result after timeout, got %v", got)
+		}
+	})
+
+	t.Run("TestSugarDB_XReadGroup", func(t *testing.T) {
+		t.Parallel()
+
+		key := "xreadgroup_key1"
+		if _, err := server.XGroupCreate(key, "group", "$", XGroupCreateOptions{}); err == nil {
+			t.Error("XGroupCreate() expected error when the stream does not exist")
+			return
+		}
+		if ok, err := server.XGroupCreate(key, "group", "$", XGroupCreateOptions{MkStream: true}); !ok || err != nil {
+			t.Errorf("XGroupCreate() got = %v, err = %v", ok, err)
+			return
+		}
+		for _, id := range []string{"1-0", "2-0"} {
+			if _, err := server.XAdd(key, map[string]string{"id": id}, XAddOptions{ID: id}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		got, err := server.XReadGroup("group", "alice", map[string]string{key: ">"}, XReadGroupOptions{Count: 1})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := map[string][]StreamEntry{key: {{ID: "1-0", Fields: map[string]string{"id": "1-0"}}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("XReadGroup() got = %v, want %v", got, want)
+		}
+		if _, err = server.XReadGroup("group", "bob", map[string]string{key: ">"}, XReadGroupOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		summary, err := server.XPending(key, "group")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		wantSummary := StreamPendingSummary{
+			Count:     2,
+			MinID:     "1-0",
+			MaxID:     "2-0",
+			Consumers: map[string]int{"alice": 1, "bob": 1},
+		}
+		if !reflect.DeepEqual(summary, wantSummary) {
+			t.Errorf("XPending() got = %v, want %v", summary, wantSummary)
+		}
+
+		claimed, err := server.XClaim(key, "group", "alice", 0, []string{"2-0"}, XClaimOptions{JustID: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(claimed, []StreamEntry{{ID: "2-0"}}) {
+			t.Errorf("XClaim() got = %v", claimed)
+		}
+
+		pending, err := server.XPendingRange(key, "group", "-", "+", 10, XPendingOptions{Consumer: "alice"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		wantPending := []StreamPendingEntry{
+			{ID: "1-0", Consumer: "alice", Idle: 0, DeliveryCount: 1},
+			{ID: "2-0", Consumer: "alice", Idle: 0, DeliveryCount: 1},
+		}
+		if !reflect.DeepEqual(pending, wantPending) {
+			t.Errorf("XPendingRange() got = %v, want %v", pending, wantPending)
+		}
+
+		acked, err := server.XAck(key, "group", "1-0", "2-0", "3-0")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if acked != 2 {
+			t.Errorf("XAck() got = %v, want 2", acked)
+		}
+
+		if created, err := server.XGroupCreateConsumer(key, "group", "carol"); !created || err != nil {
+			t.Errorf("XGroupCreateConsumer() got = %v, err = %v", created, err)
+		}
+		if deleted, err := server.XGroupDelConsumer(key, "group", "carol"); deleted != 0 || err != nil {
+			t.Errorf("XGroupDelConsumer() got = %v, err = %v", deleted, err)
+		}
+		if ok, err := server.XGroupSetID(key, "group", "0"); !ok || err != nil {
+			t.Errorf("XGroupSetID() got = %v, err = %v", ok, err)
+		}
+		if destroyed, err := server.XGroupDestroy(key, "group"); !destroyed || err != nil {
+			t.Errorf("XGroupDestroy() got = %v, err = %v", destroyed, err)
+		}
+		if _, err = server.XPending(key, "group"); err == nil {
+			t.Error("XPending() expected error after the group is destroyed")
+		}
+	})
+}
@@ -16,6 +16,7 @@ package sugardb
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/echovault/sugardb/internal"
 )
@@ -93,3 +94,237 @@ func (server *SugarDB) Append(key string, value string) (int, error) {
 	}
 	return internal.ParseIntegerResponse(b)
 }
+
+// BitCountOptions modifies the behaviour of the BitCount function.
+//
+// WithRange restricts the count to the inclusive range between Start and End.
+// Otherwise, the bits of the whole string are counted.
+//
+// Start and End are byte indices, or bit indices if Bit is true. Negative indices count from the end of the string.
+type BitCountOptions struct {
+	WithRange bool
+	Start     int
+	End       int
+	Bit       bool
+}
+
+// BitPosOptions modifies the behaviour of the BitPos function.
+//
+// Start is the index the search starts from.
+//
+// WithEnd restricts the search to the inclusive range between Start and End.
+//
+// Start and End are byte indices, or bit indices if Bit is true. Negative indices count from the end of the string.
+// Bit is only applied when WithEnd is true.
+type BitPosOptions struct {
+	Start   int
+	WithEnd bool
+	End     int
+	Bit     bool
+}
+
+// BitFieldOperation is a single operation of the BitField function.
+//
+// Operation is one of GET, SET or INCRBY.
+//
+// Encoding is the integer type of the operation, e.g. "u8" for an unsigned 8-bit integer or "i16" for a signed
+// 16-bit integer. Signed integers can have up to 64 bits and unsigned integers up to 63 bits.
+//
+// Offset is the bit offset of the integer. Offsets prefixed with # are multiplied by the width of the encoding.
+//
+// Value is the new value of SET or the increment of INCRBY.
+//
+// Overflow is one of WRAP, SAT or FAIL and controls how overflows of this and the following operations are handled.
+// When empty, the overflow mode of the previous operation is kept. The default is WRAP.
+type BitFieldOperation struct {
+	Operation string
+	Encoding  string
+	Offset    string
+	Value     int64
+	Overflow  string
+}
+
+// SetBit sets or clears the bit at the offset in the string value at the key.
+// The string is grown with zero bytes if the offset is beyond its end. The key is created if it doesn't exist.
+//
+// Parameters:
+//
+// `key` - string - The key to the string.
+//
+// `offset` - uint - The bit offset. The offset must be less than 2^32.
+//
+// `value` - int - The bit value, either 0 or 1.
+//
+// Returns: The original bit value at the offset.
+//
+// Errors:
+//
+// - "value at key <key> is not a string" - when the value at the key is not a string.
+func (server *SugarDB) SetBit(key string, offset uint, value int) (int, error) {
+	cmd := []string{"SETBIT", key, strconv.FormatUint(uint64(offset), 10), strconv.Itoa(value)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// GetBit returns the bit value at the offset in the string value at the key.
+//
+// Parameters:
+//
+// `key` - string - The key to the string.
+//
+// `offset` - uint - The bit offset.
+//
+// Returns: The bit value at the offset. Bits beyond the end of the string and bits of non-existent keys are 0.
+//
+// Errors:
+//
+// - "value at key <key> is not a string" - when the value at the key is not a string.
+func (server *SugarDB) GetBit(key string, offset uint) (int, error) {
+	cmd := []string{"GETBIT", key, strconv.FormatUint(uint64(offset), 10)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// BitCount counts the number of set bits in the string value at the key.
+//
+// Parameters:
+//
+// `key` - string - The key to the string.
+//
+// `options` - BitCountOptions.
+//
+// Returns: The number of set bits. Returns 0 if the key does not exist.
+//
+// Errors:
+//
+// - "value at key <key> is not a string" - when the value at the key is not a string.
+func (server *SugarDB) BitCount(key string, options BitCountOptions) (int, error) {
+	cmd := []string{"BITCOUNT", key}
+	if options.WithRange {
+		cmd = append(cmd, strconv.Itoa(options.Start), strconv.Itoa(options.End))
+		if options.Bit {
+			cmd = append(cmd, "BIT")
+		}
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// BitPos returns the position of the first bit set to the provided value in the string value at the key.
+//
+// Parameters:
+//
+// `key` - string - The key to the string.
+//
+// `bit` - int - The bit value to search for, either 0 or 1.
+//
+// `options` - BitPosOptions.
+//
+// Returns: The position of the first matching bit, or -1 if there is no such bit. When searching for a clear bit
+// without WithEnd, the string is considered to be padded with zeros so the position after the string can be returned.
+//
+// Errors:
+//
+// - "value at key <key> is not a string" - when the value at the key is not a string.
+func (server *SugarDB) BitPos(key string, bit int, options BitPosOptions) (int, error) {
+	cmd := []string{"BITPOS", key, strconv.Itoa(bit), strconv.Itoa(options.Start)}
+	if options.WithEnd {
+		cmd = append(cmd, strconv.Itoa(options.End))
+		if options.Bit {
+			cmd = append(cmd, "BIT")
+		}
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// BitOp performs a bitwise operation between the string values of the keys and stores the result in the destination.
+// Shorter strings are padded with zero bytes. If the result is empty, the destination key is deleted.
+//
+// Parameters:
+//
+// `operation` - string - One of AND, OR, XOR or NOT. NOT takes a single key.
+//
+// `destination` - string - The key to store the result in.
+//
+// `keys` - ...string - The keys to the source strings.
+//
+// Returns: The length of the resulting string.
+//
+// Errors:
+//
+// - "value at key <key> is not a string" - when the value at one of the keys is not a string.
+func (server *SugarDB) BitOp(operation string, destination string, keys ...string) (int, error) {
+	cmd := append([]string{"BITOP", operation, destination}, keys...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// BitField treats the string value at the key as an array of integers of arbitrary width and performs the operations.
+//
+// Parameters:
+//
+// `key` - string - The key to the string.
+//
+// `operations` - ...BitFieldOperation - The operations to perform in order.
+//
+// Returns: The result of each operation. GET and INCRBY return the current value and SET returns the old value.
+// The result is nil for operations that overflowed with the FAIL overflow mode.
+//
+// Errors:
+//
+// - "value at key <key> is not a string" - when the value at the key is not a string.
+func (server *SugarDB) BitField(key string, operations ...BitFieldOperation) ([]*int64, error) {
+	return server.bitField("BITFIELD", key, operations)
+}
+
+// BitFieldRO is the read-only variant of BitField that only supports GET operations.
+func (server *SugarDB) BitFieldRO(key string, operations ...BitFieldOperation) ([]*int64, error) {
+	return server.bitField("BITFIELD_RO", key, operations)
+}
+
+func (server *SugarDB) bitField(command string, key string, operations []BitFieldOperation) ([]*int64, error) {
+	cmd := []string{command, key}
+	for _, operation := range operations {
+		if operation.Overflow != "" {
+			cmd = append(cmd, "OVERFLOW", operation.Overflow)
+		}
+		cmd = append(cmd, operation.Operation, operation.Encoding, operation.Offset)
+		if !strings.EqualFold(operation.Operation, "get") {
+			cmd = append(cmd, strconv.FormatInt(operation.Value, 10))
+		}
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, _ := res.([]interface{})
+	results := make([]*int64, len(values))
+	for i, value := range values {
+		if n, ok := value.(int); ok {
+			result := int64(n)
+			results[i] = &result
+		}
+	}
+	return results, nil
+}
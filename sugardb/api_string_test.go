@@ -374,4 +374,59 @@ func TestSugarDB_String(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("TestSugarDB_Bitmap", func(t *testing.T) {
+		t.Parallel()
+
+		if bit, err := server.SetBit("bitmap_key1", 7, 1); err != nil || bit != 0 {
+			t.Errorf("SetBit() got %d, %v, want 0", bit, err)
+		}
+		if bit, err := server.SetBit("bitmap_key1", 7, 0); err != nil || bit != 1 {
+			t.Errorf("SetBit() got %d, %v, want 1", bit, err)
+		}
+		if _, err := server.SetBit("bitmap_key1", 9, 1); err != nil {
+			t.Error(err)
+		}
+		if bit, err := server.GetBit("bitmap_key1", 9); err != nil || bit != 1 {
+			t.Errorf("GetBit() got %d, %v, want 1", bit, err)
+		}
+
+		if _, _, err := server.Set("bitmap_key2", "foobar", SETOptions{}); err != nil {
+			t.Error(err)
+		}
+		if count, err := server.BitCount("bitmap_key2", BitCountOptions{}); err != nil || count != 26 {
+			t.Errorf("BitCount() got %d, %v, want 26", count, err)
+		}
+		if count, err := server.BitCount("bitmap_key2", BitCountOptions{WithRange: true, Start: 5, End: 30, Bit: true}); err != nil || count != 17 {
+			t.Errorf("BitCount() got %d, %v, want 17", count, err)
+		}
+		if pos, err := server.BitPos("bitmap_key1", 1, BitPosOptions{}); err != nil || pos != 9 {
+			t.Errorf("BitPos() got %d, %v, want 9", pos, err)
+		}
+		if pos, err := server.BitPos("bitmap_key1", 1, BitPosOptions{Start: 0, WithEnd: true, End: 0}); err != nil || pos != -1 {
+			t.Errorf("BitPos() got %d, %v, want -1", pos, err)
+		}
+
+		if length, err := server.BitOp("OR", "bitmap_key3", "bitmap_key1", "bitmap_key2"); err != nil || length != 6 {
+			t.Errorf("BitOp() got %d, %v, want 6", length, err)
+		}
+
+		results, err := server.BitField("bitmap_key4",
+			BitFieldOperation{Operation: "SET", Encoding: "u8", Offset: "0", Value: 200},
+			BitFieldOperation{Operation: "INCRBY", Encoding: "u8", Offset: "0", Value: 100, Overflow: "FAIL"},
+			BitFieldOperation{Operation: "INCRBY", Encoding: "i8", Offset: "#1", Value: -5},
+		)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(results) != 3 || results[0] == nil || *results[0] != 0 || results[1] != nil ||
+			results[2] == nil || *results[2] != -5 {
+			t.Errorf("BitField() got unexpected results %v", results)
+		}
+		results, err = server.BitFieldRO("bitmap_key4", BitFieldOperation{Operation: "GET", Encoding: "u8", Offset: "0"})
+		if err != nil || len(results) != 1 || results[0] == nil || *results[0] != 200 {
+			t.Errorf("BitFieldRO() got %v, %v, want [200]", results, err)
+		}
+	})
 }
@@ -0,0 +1,685 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// TSCreateOptions modifies the behaviour of the TSCreate function.
+//
+// Retention - int64 - the maximum age of samples in milliseconds, relative to the server clock.
+// The default of 0 keeps samples forever.
+//
+// DuplicatePolicy - string - how a sample with the timestamp of an existing sample is handled. One of "BLOCK",
+// "FIRST", "LAST", "MIN", "MAX" or "SUM". The default is "BLOCK".
+//
+// Labels - map[string]string - the labels used to select the series in the multi-series functions.
+type TSCreateOptions struct {
+	Retention       int64
+	DuplicatePolicy string
+	Labels          map[string]string
+}
+
+// TSAlterOptions modifies the behaviour of the TSAlter function. Fields that are not set are left unchanged.
+//
+// Retention - *int64 - the maximum age of samples in milliseconds. 0 keeps samples forever.
+//
+// DuplicatePolicy - string - the new duplicate policy.
+//
+// Labels - map[string]string - replaces all the labels of the series. An empty non-nil map removes them.
+type TSAlterOptions struct {
+	Retention       *int64
+	DuplicatePolicy string
+	Labels          map[string]string
+}
+
+// TSAddOptions modifies the behaviour of the TSAdd function.
+//
+// Retention, DuplicatePolicy, Labels - the options of the series if it's created by TSAdd. See TSCreateOptions.
+//
+// OnDuplicate - string - overrides the duplicate policy of the series for this sample.
+type TSAddOptions struct {
+	Retention       int64
+	DuplicatePolicy string
+	OnDuplicate     string
+	Labels          map[string]string
+}
+
+// TSRangeOptions modifies the samples returned by the range functions.
+//
+// FilterByTS - []int64 - only return the samples with these timestamps.
+//
+// FilterByValue - bool - only return the samples with values between MinValue and MaxValue, inclusive.
+//
+// MinValue, MaxValue - float64 - the bounds of FilterByValue.
+//
+// Count - int - the maximum number of samples to return. 0 returns all of them.
+//
+// Aggregation - string - groups the samples into buckets of BucketDuration milliseconds and returns one sample
+// per bucket. One of "AVG", "SUM", "MIN", "MAX", "RANGE", "COUNT", "FIRST" or "LAST".
+//
+// BucketDuration - int64 - the duration of each bucket in milliseconds.
+type TSRangeOptions struct {
+	FilterByTS     []int64
+	FilterByValue  bool
+	MinValue       float64
+	MaxValue       float64
+	Count          int
+	Aggregation    string
+	BucketDuration int64
+}
+
+// TSSample is a sample of a time series.
+//
+// Timestamp - int64 - the timestamp of the sample in milliseconds.
+//
+// Value - float64 - the value of the sample.
+type TSSample struct {
+	Timestamp int64
+	Value     float64
+}
+
+// TSKeySample is a sample to add to the time series at Key.
+type TSKeySample struct {
+	Key       string
+	Timestamp int64
+	Value     float64
+}
+
+// TSSeries is a time series returned by the multi-series functions.
+//
+// Key - string - the key of the series.
+//
+// Labels - map[string]string - the labels of the series.
+//
+// Samples - []TSSample - the samples of the series that were selected.
+type TSSeries struct {
+	Key     string
+	Labels  map[string]string
+	Samples []TSSample
+}
+
+// TSRule is a compaction rule of a time series.
+//
+// DestKey - string - the key of the series the samples are compacted into.
+//
+// BucketDuration - int64 - the duration of each bucket in milliseconds.
+//
+// Aggregation - string - the aggregation of each bucket.
+type TSRule struct {
+	DestKey        string
+	BucketDuration int64
+	Aggregation    string
+}
+
+// TSInfo describes a time series.
+//
+// TotalSamples - int - the number of samples within the retention period.
+//
+// MemoryUsage - int - the estimated memory used by the series in bytes.
+//
+// FirstTimestamp, LastTimestamp - int64 - the timestamps of the first and last samples, or 0 if there are none.
+//
+// Retention - int64 - the retention period in milliseconds.
+//
+// DuplicatePolicy - string - the duplicate policy of the series.
+//
+// Labels - map[string]string - the labels of the series.
+//
+// SourceKey - string - the key of the series compacted into this one, or an empty string if there isn't one.
+//
+// Rules - []TSRule - the compaction rules of the series.
+type TSInfo struct {
+	TotalSamples    int
+	MemoryUsage     int
+	FirstTimestamp  int64
+	LastTimestamp   int64
+	Retention       int64
+	DuplicatePolicy string
+	Labels          map[string]string
+	SourceKey       string
+	Rules           []TSRule
+}
+
+func appendTSLabels(cmd []string, labels map[string]string) []string {
+	cmd = append(cmd, "LABELS")
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd = append(cmd, name, labels[name])
+	}
+	return cmd
+}
+
+func appendTSRangeOptions(cmd []string, options TSRangeOptions) []string {
+	if len(options.FilterByTS) > 0 {
+		cmd = append(cmd, "FILTER_BY_TS")
+		for _, timestamp := range options.FilterByTS {
+			cmd = append(cmd, strconv.FormatInt(timestamp, 10))
+		}
+	}
+	if options.FilterByValue {
+		cmd = append(cmd, "FILTER_BY_VALUE",
+			strconv.FormatFloat(options.MinValue, 'f', -1, 64), strconv.FormatFloat(options.MaxValue, 'f', -1, 64))
+	}
+	if options.Count > 0 {
+		cmd = append(cmd, "COUNT", strconv.Itoa(options.Count))
+	}
+	if options.Aggregation != "" {
+		cmd = append(cmd, "AGGREGATION", options.Aggregation, strconv.FormatInt(options.BucketDuration, 10))
+	}
+	return cmd
+}
+
+// parseTSSample parses a sample reply, which is a timestamp and a value, or an empty array.
+func parseTSSample(value interface{}) (TSSample, bool) {
+	sample, ok := value.([]interface{})
+	if !ok || len(sample) != 2 {
+		return TSSample{}, false
+	}
+	timestamp, _ := sample[0].(int)
+	s, _ := sample[1].(string)
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return TSSample{}, false
+	}
+	return TSSample{Timestamp: int64(timestamp), Value: v}, true
+}
+
+func parseTSSamples(value interface{}) []TSSample {
+	values, _ := value.([]interface{})
+	samples := make([]TSSample, 0, len(values))
+	for _, v := range values {
+		if sample, ok := parseTSSample(v); ok {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+func parseTSLabels(value interface{}) map[string]string {
+	values, _ := value.([]interface{})
+	labels := make(map[string]string, len(values))
+	for _, v := range values {
+		if label, ok := v.([]interface{}); ok && len(label) == 2 {
+			name, _ := label[0].(string)
+			labels[name], _ = label[1].(string)
+		}
+	}
+	return labels
+}
+
+// parseTSSeries parses the reply of the multi-series commands, where each series is a key, labels and samples.
+func parseTSSeries(b []byte, single bool) ([]TSSeries, error) {
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := res.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response %v", res)
+	}
+	series := make([]TSSeries, 0, len(values))
+	for _, value := range values {
+		elements, ok := value.([]interface{})
+		if !ok || len(elements) != 3 {
+			return nil, fmt.Errorf("unexpected response %v", value)
+		}
+		key, _ := elements[0].(string)
+		s := TSSeries{Key: key, Labels: parseTSLabels(elements[1])}
+		if single {
+			if sample, ok := parseTSSample(elements[2]); ok {
+				s.Samples = []TSSample{sample}
+			}
+		} else {
+			s.Samples = parseTSSamples(elements[2])
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+// TSCreate creates a time series at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the series.
+//
+// `options` - TSCreateOptions.
+//
+// Returns: true if the series was created.
+//
+// Errors:
+//
+// "key <key> already exists" - when the key exists.
+//
+// "unknown duplicate policy <policy>" - when the duplicate policy is not valid.
+func (server *SugarDB) TSCreate(key string, options TSCreateOptions) (bool, error) {
+	cmd := []string{"TS.CREATE", key, "RETENTION", strconv.FormatInt(options.Retention, 10)}
+	if options.DuplicatePolicy != "" {
+		cmd = append(cmd, "DUPLICATE_POLICY", options.DuplicatePolicy)
+	}
+	if len(options.Labels) > 0 {
+		cmd = appendTSLabels(cmd, options.Labels)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return s == "OK", err
+}
+
+// TSAlter changes the retention, duplicate policy or labels of the time series at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the series.
+//
+// `options` - TSAlterOptions.
+//
+// Returns: true if the series was changed.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "value at key <key> is not a time series" - when the key exists but is not a time series.
+func (server *SugarDB) TSAlter(key string, options TSAlterOptions) (bool, error) {
+	cmd := []string{"TS.ALTER", key}
+	if options.Retention != nil {
+		cmd = append(cmd, "RETENTION", strconv.FormatInt(*options.Retention, 10))
+	}
+	if options.DuplicatePolicy != "" {
+		cmd = append(cmd, "DUPLICATE_POLICY", options.DuplicatePolicy)
+	}
+	if options.Labels != nil {
+		cmd = appendTSLabels(cmd, options.Labels)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return s == "OK", err
+}
+
+// TSAdd adds a sample to the time series at the key. If the key doesn't exist, the series is created with
+// the given options.
+//
+// Parameters:
+//
+// `key` - string - the key of the series.
+//
+// `timestamp` - int64 - the timestamp of the sample in milliseconds. A negative timestamp uses the server clock.
+//
+// `value` - float64 - the value of the sample.
+//
+// `options` - TSAddOptions.
+//
+// Returns: the timestamp of the sample.
+//
+// Errors:
+//
+// "value at key <key> is not a time series" - when the key exists but is not a time series.
+//
+// "timestamp is older than the retention period" - when the sample would be removed by the retention period.
+//
+// "a sample already exists at the timestamp and the duplicate policy is block" - when there is a sample at the
+// timestamp and the duplicate policy is "BLOCK".
+func (server *SugarDB) TSAdd(key string, timestamp int64, value float64, options TSAddOptions) (int64, error) {
+	ts := "*"
+	if timestamp >= 0 {
+		ts = strconv.FormatInt(timestamp, 10)
+	}
+	cmd := []string{"TS.ADD", key, ts, strconv.FormatFloat(value, 'f', -1, 64)}
+	if options.Retention > 0 {
+		cmd = append(cmd, "RETENTION", strconv.FormatInt(options.Retention, 10))
+	}
+	if options.DuplicatePolicy != "" {
+		cmd = append(cmd, "DUPLICATE_POLICY", options.DuplicatePolicy)
+	}
+	if options.OnDuplicate != "" {
+		cmd = append(cmd, "ON_DUPLICATE", options.OnDuplicate)
+	}
+	if len(options.Labels) > 0 {
+		cmd = appendTSLabels(cmd, options.Labels)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	res, err := internal.ParseIntegerResponse(b)
+	return int64(res), err
+}
+
+// TSMAdd adds samples to existing time series.
+//
+// Parameters:
+//
+// `samples` - ...TSKeySample - the samples to add. A negative timestamp uses the server clock.
+//
+// Returns: the timestamp of each sample, in the order of the samples. Samples that couldn't be added have a
+// timestamp of -1, and their errors are joined in the returned error.
+func (server *SugarDB) TSMAdd(samples ...TSKeySample) ([]int64, error) {
+	cmd := []string{"TS.MADD"}
+	for _, sample := range samples {
+		ts := "*"
+		if sample.Timestamp >= 0 {
+			ts = strconv.FormatInt(sample.Timestamp, 10)
+		}
+		cmd = append(cmd, sample.Key, ts, strconv.FormatFloat(sample.Value, 'f', -1, 64))
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, _ := res.([]interface{})
+	timestamps := make([]int64, len(values))
+	var errs []error
+	for i, value := range values {
+		switch v := value.(type) {
+		case int:
+			timestamps[i] = int64(v)
+		case error:
+			timestamps[i] = -1
+			errs = append(errs, fmt.Errorf("%s: %w", samples[i].Key, v))
+		}
+	}
+	return timestamps, errors.Join(errs...)
+}
+
+// TSDel deletes the samples with timestamps between from and to, inclusive, from the time series at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the series.
+//
+// `from` - int64 - the earliest timestamp to delete.
+//
+// `to` - int64 - the latest timestamp to delete.
+//
+// Returns: the number of samples deleted.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) TSDel(key string, from, to int64) (int, error) {
+	cmd := []string{"TS.DEL", key, strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// TSGet returns the latest sample of the time series at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the series.
+//
+// Returns: the latest sample, and false if the series has no samples within the retention period.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) TSGet(key string) (TSSample, bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"TS.GET", key}), nil, false, true)
+	if err != nil {
+		return TSSample{}, false, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return TSSample{}, false, err
+	}
+	sample, ok := parseTSSample(res)
+	return sample, ok, nil
+}
+
+// TSMGet returns the latest sample of each time series that matches the filters.
+//
+// Parameters:
+//
+// `filters` - ...string - the label filters. Each filter is of the form label=value, label!=value,
+// label=(value1,value2,...) or label!=(value1,value2,...). At least one filter must match values.
+//
+// Returns: the matching series ordered by key, with their labels and latest sample, if they have one.
+func (server *SugarDB) TSMGet(filters ...string) ([]TSSeries, error) {
+	cmd := append([]string{"TS.MGET", "WITHLABELS", "FILTER"}, filters...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseTSSeries(b, true)
+}
+
+func (server *SugarDB) tsRange(command, key string, from, to int64, options TSRangeOptions) ([]TSSample, error) {
+	cmd := appendTSRangeOptions([]string{command, key, strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)}, options)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	return parseTSSamples(res), nil
+}
+
+// TSRange returns the samples of the time series at the key with timestamps between from and to, inclusive.
+//
+// Parameters:
+//
+// `key` - string - the key of the series.
+//
+// `from` - int64 - the earliest timestamp. Use 0 for the earliest sample.
+//
+// `to` - int64 - the latest timestamp. Use math.MaxInt64 for the latest sample.
+//
+// `options` - TSRangeOptions.
+//
+// Returns: the samples ordered by timestamp.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+//
+// "unknown aggregation <aggregation>" - when the aggregation is not valid.
+func (server *SugarDB) TSRange(key string, from, to int64, options TSRangeOptions) ([]TSSample, error) {
+	return server.tsRange("TS.RANGE", key, from, to, options)
+}
+
+// TSRevRange is like TSRange, but returns the samples from the latest to the earliest.
+func (server *SugarDB) TSRevRange(key string, from, to int64, options TSRangeOptions) ([]TSSample, error) {
+	return server.tsRange("TS.REVRANGE", key, from, to, options)
+}
+
+func (server *SugarDB) tsMRange(command string, from, to int64, options TSRangeOptions, filters []string) ([]TSSeries, error) {
+	cmd := appendTSRangeOptions([]string{command, strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)}, options)
+	cmd = append(append(cmd, "WITHLABELS", "FILTER"), filters...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseTSSeries(b, false)
+}
+
+// TSMRange returns the samples between from and to of each time series that matches the filters.
+//
+// Parameters:
+//
+// `from` - int64 - the earliest timestamp.
+//
+// `to` - int64 - the latest timestamp.
+//
+// `options` - TSRangeOptions.
+//
+// `filters` - ...string - the label filters. See TSMGet.
+//
+// Returns: the matching series ordered by key, with their labels and samples.
+func (server *SugarDB) TSMRange(from, to int64, options TSRangeOptions, filters ...string) ([]TSSeries, error) {
+	return server.tsMRange("TS.MRANGE", from, to, options, filters)
+}
+
+// TSMRevRange is like TSMRange, but returns the samples of each series from the latest to the earliest.
+func (server *SugarDB) TSMRevRange(from, to int64, options TSRangeOptions, filters ...string) ([]TSSeries, error) {
+	return server.tsMRange("TS.MREVRANGE", from, to, options, filters)
+}
+
+// TSQueryIndex returns the keys of the time series that match the filters.
+//
+// Parameters:
+//
+// `filters` - ...string - the label filters. See TSMGet.
+//
+// Returns: the matching keys in order.
+func (server *SugarDB) TSQueryIndex(filters ...string) ([]string, error) {
+	cmd := append([]string{"TS.QUERYINDEX"}, filters...)
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	return internal.ParseStringArrayResponse(b)
+}
+
+// TSCreateRule creates a compaction rule that downsamples the time series at the source key into the series
+// at the destination key. When a sample is added to the source after a bucket, the aggregate of the bucket
+// is added to the destination.
+//
+// Parameters:
+//
+// `sourceKey` - string - the key of the source series.
+//
+// `destKey` - string - the key of the destination series.
+//
+// `aggregation` - string - the aggregation of each bucket. See TSRangeOptions.
+//
+// `bucketDuration` - int64 - the duration of each bucket in milliseconds.
+//
+// Returns: true if the rule was created.
+//
+// Errors:
+//
+// "key <key> does not exist" - when either key doesn't exist.
+//
+// "key <key> is the destination of a compaction rule" - when the source is the destination of another rule.
+//
+// "key <key> is the source of a compaction rule" - when the destination has rules of its own.
+func (server *SugarDB) TSCreateRule(sourceKey, destKey, aggregation string, bucketDuration int64) (bool, error) {
+	cmd := []string{"TS.CREATERULE", sourceKey, destKey, "AGGREGATION", aggregation, strconv.FormatInt(bucketDuration, 10)}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return s == "OK", err
+}
+
+// TSDeleteRule deletes the compaction rule from the source key to the destination key.
+// The samples in the destination are kept.
+//
+// Parameters:
+//
+// `sourceKey` - string - the key of the source series.
+//
+// `destKey` - string - the key of the destination series.
+//
+// Returns: true if the rule was deleted.
+//
+// Errors:
+//
+// "there is no compaction rule from <sourceKey> to <destKey>" - when the rule doesn't exist.
+func (server *SugarDB) TSDeleteRule(sourceKey, destKey string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"TS.DELETERULE", sourceKey, destKey}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	s, err := internal.ParseStringResponse(b)
+	return s == "OK", err
+}
+
+// TSInfo returns information about the time series at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the series.
+//
+// Returns: a TSInfo describing the series.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) TSInfo(key string) (TSInfo, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"TS.INFO", key}), nil, false, true)
+	if err != nil {
+		return TSInfo{}, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return TSInfo{}, err
+	}
+	values, _ := res.([]interface{})
+	var info TSInfo
+	for i := 0; i+1 < len(values); i += 2 {
+		field, _ := values[i].(string)
+		n, _ := values[i+1].(int)
+		s, _ := values[i+1].(string)
+		switch field {
+		case "totalSamples":
+			info.TotalSamples = n
+		case "memoryUsage":
+			info.MemoryUsage = n
+		case "firstTimestamp":
+			info.FirstTimestamp = int64(n)
+		case "lastTimestamp":
+			info.LastTimestamp = int64(n)
+		case "retentionTime":
+			info.Retention = int64(n)
+		case "duplicatePolicy":
+			info.DuplicatePolicy = s
+		case "labels":
+			info.Labels = parseTSLabels(values[i+1])
+		case "sourceKey":
+			info.SourceKey = s
+		case "rules":
+			rules, _ := values[i+1].([]interface{})
+			for _, r := range rules {
+				rule, ok := r.([]interface{})
+				if !ok || len(rule) != 3 {
+					continue
+				}
+				destKey, _ := rule[0].(string)
+				duration, _ := rule[1].(int)
+				aggregation, _ := rule[2].(string)
+				info.Rules = append(info.Rules, TSRule{DestKey: destKey, BucketDuration: int64(duration), Aggregation: aggregation})
+			}
+		}
+	}
+	return info, nil
+}
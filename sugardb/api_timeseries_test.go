@@ -0,0 +1,254 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/echovault/sugardb/internal/clock"
+)
+
+func TestSugarDB_TimeSeries(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	t.Run("TestSugarDB_TSADD", func(t *testing.T) {
+		t.Parallel()
+
+		now := clock.NewClock().Now().UnixMilli()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			key         string
+			timestamp   int64
+			value       float64
+			options     TSAddOptions
+			want        int64
+			wantSamples []TSSample
+			wantErr     bool
+		}{
+			{
+				name:        "1. Create a series when the key doesn't exist",
+				key:         "ts_add_key1",
+				timestamp:   1000,
+				value:       1.5,
+				want:        1000,
+				wantSamples: []TSSample{{Timestamp: 1000, Value: 1.5}},
+				wantErr:     false,
+			},
+			{
+				name:        "2. Use the server clock when the timestamp is negative",
+				key:         "ts_add_key2",
+				timestamp:   -1,
+				value:       2,
+				options:     TSAddOptions{Retention: 60000},
+				want:        now,
+				wantSamples: []TSSample{{Timestamp: now, Value: 2}},
+				wantErr:     false,
+			},
+			{
+				name:      "3. Throw error when the sample is older than the retention period",
+				key:       "ts_add_key3",
+				timestamp: now - 2000,
+				value:     1,
+				options:   TSAddOptions{Retention: 1000},
+				want:      0,
+				wantErr:   true,
+			},
+			{
+				name:        "4. Throw error when the key does not hold a time series",
+				presetValue: "Default value",
+				key:         "ts_add_key4",
+				timestamp:   1000,
+				value:       1,
+				want:        0,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.presetValue != nil {
+					err := presetValue(server, server.context, tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.TSAdd(tt.key, tt.timestamp, tt.value, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("TSADD() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("TSADD() got = %v, want %v", got, tt.want)
+				}
+				if tt.wantErr {
+					return
+				}
+				samples, err := server.TSRange(tt.key, 0, math.MaxInt64, TSRangeOptions{})
+				if err != nil || !reflect.DeepEqual(samples, tt.wantSamples) {
+					t.Errorf("TSRANGE() got = %v, want %v, error = %v", samples, tt.wantSamples, err)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_TimeSeriesQueries", func(t *testing.T) {
+		t.Parallel()
+
+		for _, key := range []string{"ts_query_key1", "ts_query_key2"} {
+			if ok, err := server.TSCreate(key, TSCreateOptions{
+				DuplicatePolicy: "LAST",
+				Labels:          map[string]string{"group": "ts_query", "sensor": key},
+			}); !ok || err != nil {
+				t.Errorf("TSCREATE() got = %v, error = %v", ok, err)
+				return
+			}
+		}
+		if ok, err := server.TSCreate("ts_query_key1", TSCreateOptions{}); ok || err == nil {
+			t.Errorf("TSCREATE() expected error for existing key, got = %v", ok)
+		}
+
+		got, err := server.TSMAdd(
+			TSKeySample{Key: "ts_query_key1", Timestamp: 1000, Value: 1},
+			TSKeySample{Key: "ts_query_key1", Timestamp: 1500, Value: 3},
+			TSKeySample{Key: "ts_query_key2", Timestamp: 1000, Value: 10},
+			TSKeySample{Key: "ts_query_missing", Timestamp: 1000, Value: 1},
+			TSKeySample{Key: "ts_query_key1", Timestamp: 2500, Value: 5},
+		)
+		if err == nil || !reflect.DeepEqual(got, []int64{1000, 1500, 1000, -1, 2500}) {
+			t.Errorf("TSMADD() got = %v, error = %v", got, err)
+		}
+
+		if got, ok, err := server.TSGet("ts_query_key1"); err != nil || !ok || got != (TSSample{Timestamp: 2500, Value: 5}) {
+			t.Errorf("TSGET() got = %v, %v, error = %v", got, ok, err)
+		}
+		if got, err := server.TSRange("ts_query_key1", 0, math.MaxInt64, TSRangeOptions{
+			Aggregation:    "AVG",
+			BucketDuration: 2000,
+		}); err != nil || !reflect.DeepEqual(got, []TSSample{{Timestamp: 0, Value: 2}, {Timestamp: 2000, Value: 5}}) {
+			t.Errorf("TSRANGE() got = %v, error = %v", got, err)
+		}
+		if got, err := server.TSRevRange("ts_query_key1", 0, 2000, TSRangeOptions{
+			FilterByValue: true,
+			MinValue:      2,
+			MaxValue:      10,
+		}); err != nil || !reflect.DeepEqual(got, []TSSample{{Timestamp: 1500, Value: 3}}) {
+			t.Errorf("TSREVRANGE() got = %v, error = %v", got, err)
+		}
+
+		if got, err := server.TSQueryIndex("group=ts_query", "sensor!=ts_query_key1"); err != nil ||
+			!reflect.DeepEqual(got, []string{"ts_query_key2"}) {
+			t.Errorf("TSQUERYINDEX() got = %v, error = %v", got, err)
+		}
+		wantLabels := func(key string) map[string]string {
+			return map[string]string{"group": "ts_query", "sensor": key}
+		}
+		if got, err := server.TSMGet("group=ts_query"); err != nil || !reflect.DeepEqual(got, []TSSeries{
+			{Key: "ts_query_key1", Labels: wantLabels("ts_query_key1"), Samples: []TSSample{{Timestamp: 2500, Value: 5}}},
+			{Key: "ts_query_key2", Labels: wantLabels("ts_query_key2"), Samples: []TSSample{{Timestamp: 1000, Value: 10}}},
+		}) {
+			t.Errorf("TSMGET() got = %v, error = %v", got, err)
+		}
+		if got, err := server.TSMRevRange(0, math.MaxInt64, TSRangeOptions{Count: 1}, "group=ts_query"); err != nil ||
+			!reflect.DeepEqual(got, []TSSeries{
+				{Key: "ts_query_key1", Labels: wantLabels("ts_query_key1"), Samples: []TSSample{{Timestamp: 2500, Value: 5}}},
+				{Key: "ts_query_key2", Labels: wantLabels("ts_query_key2"), Samples: []TSSample{{Timestamp: 1000, Value: 10}}},
+			}) {
+			t.Errorf("TSMREVRANGE() got = %v, error = %v", got, err)
+		}
+		if got, err := server.TSMRange(1000, 1000, TSRangeOptions{}, "group=ts_query"); err != nil ||
+			!reflect.DeepEqual(got, []TSSeries{
+				{Key: "ts_query_key1", Labels: wantLabels("ts_query_key1"), Samples: []TSSample{{Timestamp: 1000, Value: 1}}},
+				{Key: "ts_query_key2", Labels: wantLabels("ts_query_key2"), Samples: []TSSample{{Timestamp: 1000, Value: 10}}},
+			}) {
+			t.Errorf("TSMRANGE() got = %v, error = %v", got, err)
+		}
+
+		if ok, err := server.TSAlter("ts_query_key2", TSAlterOptions{Labels: map[string]string{}}); !ok || err != nil {
+			t.Errorf("TSALTER() got = %v, error = %v", ok, err)
+		}
+		if got, err := server.TSQueryIndex("group=ts_query"); err != nil || !reflect.DeepEqual(got, []string{"ts_query_key1"}) {
+			t.Errorf("TSQUERYINDEX() got = %v, error = %v", got, err)
+		}
+		if got, err := server.TSDel("ts_query_key1", 1000, 1500); err != nil || got != 2 {
+			t.Errorf("TSDEL() got = %v, error = %v", got, err)
+		}
+	})
+
+	t.Run("TestSugarDB_TimeSeriesRules", func(t *testing.T) {
+		t.Parallel()
+
+		retention := int64(0)
+		for _, key := range []string{"ts_rule_source", "ts_rule_dest"} {
+			if ok, err := server.TSCreate(key, TSCreateOptions{Retention: 1000}); !ok || err != nil {
+				t.Errorf("TSCREATE() got = %v, error = %v", ok, err)
+				return
+			}
+			if ok, err := server.TSAlter(key, TSAlterOptions{Retention: &retention}); !ok || err != nil {
+				t.Errorf("TSALTER() got = %v, error = %v", ok, err)
+				return
+			}
+		}
+		if ok, err := server.TSCreateRule("ts_rule_source", "ts_rule_dest", "SUM", 1000); !ok || err != nil {
+			t.Errorf("TSCREATERULE() got = %v, error = %v", ok, err)
+			return
+		}
+		for i, timestamp := range []int64{100, 600, 1200, 2100} {
+			if _, err := server.TSAdd("ts_rule_source", timestamp, float64(i+1), TSAddOptions{}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if got, err := server.TSRange("ts_rule_dest", 0, math.MaxInt64, TSRangeOptions{}); err != nil ||
+			!reflect.DeepEqual(got, []TSSample{{Timestamp: 0, Value: 3}, {Timestamp: 1000, Value: 3}}) {
+			t.Errorf("TSRANGE() got = %v, error = %v", got, err)
+		}
+
+		info, err := server.TSInfo("ts_rule_source")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		info.MemoryUsage = 0
+		if want := (TSInfo{
+			TotalSamples:    4,
+			FirstTimestamp:  100,
+			LastTimestamp:   2100,
+			DuplicatePolicy: "block",
+			Labels:          map[string]string{},
+			Rules:           []TSRule{{DestKey: "ts_rule_dest", BucketDuration: 1000, Aggregation: "sum"}},
+		}); !reflect.DeepEqual(info, want) {
+			t.Errorf("TSINFO() got = %+v, want %+v", info, want)
+		}
+		if info, err := server.TSInfo("ts_rule_dest"); err != nil || info.SourceKey != "ts_rule_source" {
+			t.Errorf("TSINFO() got = %+v, error = %v", info, err)
+		}
+
+		if ok, err := server.TSDeleteRule("ts_rule_source", "ts_rule_dest"); !ok || err != nil {
+			t.Errorf("TSDELETERULE() got = %v, error = %v", ok, err)
+		}
+		if ok, err := server.TSDeleteRule("ts_rule_source", "ts_rule_dest"); ok || err == nil {
+			t.Errorf("TSDELETERULE() expected error for missing rule, got = %v", ok)
+		}
+	})
+
+}
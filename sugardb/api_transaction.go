@@ -0,0 +1,142 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"errors"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// Tx is a transaction for the embedded API. It is the embedded equivalent of the MULTI, EXEC, DISCARD,
+// WATCH and UNWATCH commands.
+//
+// Commands added with Queue are not executed until Exec is called. Exec executes all the queued commands
+// atomically, no other command can modify the store while the transaction is being executed.
+// If any of the keys marked with Watch is modified between the Watch call and the Exec call,
+// the transaction is aborted.
+//
+// A Tx cannot be reused after Exec or Discard is called. Create a new one with NewTx instead.
+type Tx struct {
+	server *SugarDB
+	tx     *transaction
+}
+
+// NewTx creates a new transaction on the embedded instance.
+// The transaction uses the logical database selected with SelectDB at the time each method is called.
+func (server *SugarDB) NewTx() *Tx {
+	return &Tx{
+		server: server,
+		tx:     &transaction{multi: true},
+	}
+}
+
+// Watch marks the keys to be watched. If any of the keys is modified before Exec is called, the transaction
+// is aborted and none of the queued commands are executed.
+//
+// Parameters:
+//
+// `keys` - ...string - The keys to watch.
+//
+// Errors:
+//
+// "transaction already executed or discarded" - when Exec or Discard has already been called.
+func (tx *Tx) Watch(keys ...string) error {
+	tx.tx.mut.Lock()
+	defer tx.tx.mut.Unlock()
+	if !tx.tx.multi {
+		return errors.New("transaction already executed or discarded")
+	}
+	tx.server.watch(tx.server.connectionContext(tx.server.context, nil, true), tx.tx, keys)
+	return nil
+}
+
+// Unwatch removes all the keys watched by the transaction.
+func (tx *Tx) Unwatch() {
+	tx.tx.mut.Lock()
+	defer tx.tx.mut.Unlock()
+	tx.server.unwatch(tx.tx)
+}
+
+// Queue adds a command to the transaction. The command is validated when it's queued but it's only
+// executed when Exec is called.
+//
+// Parameters:
+//
+// `command` - ...string - The command to queue (e.g. "SET", "key", "value").
+//
+// Errors:
+//
+// "transaction already executed or discarded" - when Exec or Discard has already been called.
+//
+// "command <command> not supported" - when the command does not exist.
+//
+// Any error returned by the command's validation is also returned. The command is not queued in this case.
+func (tx *Tx) Queue(command ...string) error {
+	if len(command) == 0 {
+		return errors.New("empty command")
+	}
+
+	tx.tx.mut.Lock()
+	defer tx.tx.mut.Unlock()
+	if !tx.tx.multi {
+		return errors.New("transaction already executed or discarded")
+	}
+
+	c, subCommand, _, err := tx.server.resolveCommand(command)
+	if err != nil {
+		return err
+	}
+	if err = tx.server.validateQueuedCommand(c, subCommand, command); err != nil {
+		return err
+	}
+
+	tx.tx.commands = append(tx.tx.commands, command)
+	return nil
+}
+
+// Exec atomically executes all the commands queued in the transaction.
+//
+// Returns: A slice containing the result of each queued command in the order they were queued.
+// Strings are returned as string, integers as int, arrays as []interface{} and nil as nil.
+// If a command fails, its error is returned at the command's index in the slice.
+//
+// Errors:
+//
+// "transaction aborted, watched keys were modified" - when a watched key was modified after Watch was called.
+//
+// "EXEC without MULTI" - when Exec or Discard has already been called.
+func (tx *Tx) Exec() ([]interface{}, error) {
+	ctx := tx.server.connectionContext(tx.server.context, nil, true)
+	b, err := tx.server.exec(ctx, tx.tx, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, errors.New("transaction aborted, watched keys were modified")
+	}
+	return res.([]interface{}), nil
+}
+
+// Discard discards all the commands queued in the transaction and removes the watched keys.
+func (tx *Tx) Discard() {
+	tx.tx.mut.Lock()
+	defer tx.tx.mut.Unlock()
+	tx.server.resetTransaction(tx.tx)
+}
@@ -0,0 +1,262 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSugarDB_Transaction(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	t.Run("TestSugarDB_TxExec", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name         string
+			presetValues map[string]interface{}
+			commands     [][]string
+			want         []interface{}
+			wantQueueErr bool
+			wantValues   map[string]interface{}
+		}{
+			{
+				name: "1. Execute queued commands in order",
+				commands: [][]string{
+					{"SET", "tx_key1", "value1"},
+					{"GET", "tx_key1"},
+					{"LPUSH", "tx_key2", "a", "b"},
+				},
+				want:       []interface{}{"OK", "value1", 2},
+				wantValues: map[string]interface{}{"tx_key1": "value1"},
+			},
+			{
+				name:         "2. Return the error of a failed command without aborting the others",
+				presetValues: map[string]interface{}{"tx_key3": "value3"},
+				commands: [][]string{
+					{"LPUSH", "tx_key3", "a"},
+					{"SET", "tx_key4", "value4"},
+				},
+				want: []interface{}{
+					errors.New("Error LPUSH command on non-list item"),
+					"OK",
+				},
+				wantValues: map[string]interface{}{"tx_key3": "value3", "tx_key4": "value4"},
+			},
+			{
+				name:         "3. Return error when queueing an unknown command",
+				commands:     [][]string{{"UNKNOWN", "tx_key5"}},
+				wantQueueErr: true,
+			},
+			{
+				name:         "4. Return error when queueing a command with the wrong number of arguments",
+				commands:     [][]string{{"GET"}},
+				wantQueueErr: true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				for k, v := range tt.presetValues {
+					if err := presetValue(server, context.Background(), k, v); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				tx := server.NewTx()
+				for _, command := range tt.commands {
+					err := tx.Queue(command...)
+					if (err != nil) != tt.wantQueueErr {
+						t.Errorf("Queue() error = %v, wantQueueErr %v", err, tt.wantQueueErr)
+						return
+					}
+				}
+				if tt.wantQueueErr {
+					return
+				}
+				got, err := tx.Exec()
+				if err != nil {
+					t.Errorf("Exec() error = %v", err)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Exec() got = %v, want %v", got, tt.want)
+				}
+				for k, want := range tt.wantValues {
+					value, err := getValue(server, context.Background(), k, "0")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if !reflect.DeepEqual(value, want) {
+						t.Errorf("expected value %v for key %s, got %v", want, k, value)
+					}
+				}
+				// The transaction cannot be reused after Exec.
+				if err = tx.Queue("GET", "tx_key1"); err == nil {
+					t.Error("expected error when queueing a command after Exec")
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_TxWatch", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name      string
+			key       string
+			modify    bool
+			wantErr   bool
+			wantValue interface{}
+		}{
+			{
+				name:      "1. Abort the transaction when a watched key is modified",
+				key:       "tx_watch_key1",
+				modify:    true,
+				wantErr:   true,
+				wantValue: "modified",
+			},
+			{
+				name:      "2. Execute the transaction when the watched key is not modified",
+				key:       "tx_watch_key2",
+				modify:    false,
+				wantErr:   false,
+				wantValue: "from_tx",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tx := server.NewTx()
+				if err := tx.Watch(tt.key); err != nil {
+					t.Error(err)
+					return
+				}
+				if err := tx.Queue("SET", tt.key, "from_tx"); err != nil {
+					t.Error(err)
+					return
+				}
+				if tt.modify {
+					if _, _, err := server.Set(tt.key, "modified", SETOptions{}); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				_, err := tx.Exec()
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Exec() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				value, err := getValue(server, context.Background(), tt.key, "0")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if value != tt.wantValue {
+					t.Errorf("expected value %v, got %v", tt.wantValue, value)
+				}
+			})
+		}
+	})
+
+	t.Run("TestSugarDB_TxDiscard", func(t *testing.T) {
+		t.Parallel()
+		tx := server.NewTx()
+		if err := tx.Queue("SET", "tx_discard_key", "value"); err != nil {
+			t.Error(err)
+			return
+		}
+		tx.Discard()
+		if _, err := tx.Exec(); err == nil {
+			t.Error("expected error when executing a discarded transaction")
+		}
+		value, err := getValue(server, context.Background(), "tx_discard_key", "0")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != nil {
+			t.Errorf("expected nil value after discard, got %v", value)
+		}
+	})
+
+	t.Run("TestSugarDB_TxScriptCommandConcurrently", func(t *testing.T) {
+		t.Parallel()
+
+		mockServer := createSugarDB()
+		defer mockServer.ShutDown()
+
+		// A transaction and a direct call of the same script command must not deadlock on the store and
+		// script locks.
+		for _, module := range []struct {
+			path    string
+			command string
+		}{
+			{path: path.Join("..", "internal", "volumes", "modules", "lua", "hash.lua"), command: "LUA.HASH"},
+			{path: path.Join("..", "internal", "volumes", "modules", "js", "hash.js"), command: "JS.HASH"},
+		} {
+			if err := mockServer.LoadModule(module.path); err != nil {
+				t.Error(err)
+				return
+			}
+			key := fmt.Sprintf("tx_script_key_%s", module.command)
+			done := make(chan error, 2)
+			go func() {
+				for i := 0; i < 2000; i++ {
+					if _, err := mockServer.ExecuteCommand(module.command, key); err != nil {
+						done <- err
+						return
+					}
+				}
+				done <- nil
+			}()
+			go func() {
+				for i := 0; i < 2000; i++ {
+					tx := mockServer.NewTx()
+					if err := tx.Queue(module.command, key); err != nil {
+						done <- err
+						return
+					}
+					if _, err := tx.Exec(); err != nil {
+						done <- err
+						return
+					}
+				}
+				done <- nil
+			}()
+			for i := 0; i < 2; i++ {
+				select {
+				case err := <-done:
+					if err != nil {
+						t.Error(err)
+					}
+				case <-time.After(10 * time.Second):
+					t.Fatalf("expected %s and EXEC to complete, timed out", module.command)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,429 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"strconv"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/modules/vector_set"
+)
+
+// VAddOptions modifies the behaviour of the VAdd function.
+//
+// Attributes - string - a JSON object stored with the element and matched by the Filter of VSimOptions.
+// When empty, the attributes of an existing element are left unchanged.
+//
+// SetAttributes - bool - replace the attributes of the element even when Attributes is empty, which removes
+// the attributes of an existing element.
+//
+// Metric - string - the similarity metric of the vector set if it's created by VAdd. One of "cosine", "l2" or
+// "ip". The default is "cosine".
+//
+// Index - string - the index of the vector set if it's created by VAdd. One of "hnsw" or "flat".
+// The default is "hnsw".
+//
+// M - int - the number of links of each node of the HNSW index. The default is 16.
+//
+// EF - int - the number of candidates considered when inserting into the HNSW index. The default is 200.
+type VAddOptions struct {
+	Attributes    string
+	SetAttributes bool
+	Metric        string
+	Index         string
+	M             int
+	EF            int
+}
+
+// VSimOptions modifies the behaviour of the VSim and VSimElement functions.
+//
+// Count - int - the maximum number of elements to return. The default is 10.
+//
+// EF - int - the number of candidates considered when searching the HNSW index. Larger values are slower but
+// more accurate.
+//
+// Filter - string - an expression on the attributes of the elements, e.g. `.year > 2000 and .genre == "drama"`.
+// Only the elements that match it are returned.
+//
+// Exact - bool - compare the query with every element instead of searching the index.
+type VSimOptions struct {
+	Count  int
+	EF     int
+	Filter string
+	Exact  bool
+}
+
+// VSimResult is an element returned by the VSim and VSimElement functions.
+//
+// Element - string - the name of the element.
+//
+// Score - float64 - the similarity of the element to the query. For the cosine metric it's the cosine
+// similarity, for the l2 metric the euclidean distance and for the ip metric the inner product.
+//
+// Attributes - string - the attributes of the element, or an empty string if it has none.
+type VSimResult struct {
+	Element    string
+	Score      float64
+	Attributes string
+}
+
+// VSetInfo describes a vector set.
+//
+// Metric - string - the similarity metric of the set.
+//
+// Index - string - the index of the set.
+//
+// Dimension - int - the number of dimensions of the vectors.
+//
+// Size - int - the number of elements.
+//
+// MaxLevel - int - the highest level of the HNSW index, or -1 if the set has no index.
+//
+// M, EF - int - the parameters of the HNSW index.
+//
+// MemoryUsage - int - the estimated memory used by the set in bytes.
+type VSetInfo struct {
+	Metric      string
+	Index       string
+	Dimension   int
+	Size        int
+	MaxLevel    int
+	M           int
+	EF          int
+	MemoryUsage int
+}
+
+func (server *SugarDB) vSim(cmd []string, options VSimOptions) ([]VSimResult, error) {
+	cmd = append(cmd, "WITHSCORES", "WITHATTRIBS")
+	if options.Count > 0 {
+		cmd = append(cmd, "COUNT", strconv.Itoa(options.Count))
+	}
+	if options.EF > 0 {
+		cmd = append(cmd, "EF", strconv.Itoa(options.EF))
+	}
+	if options.Filter != "" {
+		cmd = append(cmd, "FILTER", options.Filter)
+	}
+	if options.Exact {
+		cmd = append(cmd, "TRUTH")
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, _ := res.([]interface{})
+	results := make([]VSimResult, 0, len(values)/3)
+	for i := 0; i+2 < len(values); i += 3 {
+		element, _ := values[i].(string)
+		s, _ := values[i+1].(string)
+		score, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		attributes, _ := values[i+2].(string)
+		results = append(results, VSimResult{Element: element, Score: score, Attributes: attributes})
+	}
+	return results, nil
+}
+
+// VAdd adds an element with the given vector to the vector set at the key. If the key doesn't exist, the set
+// is created with the dimension of the vector and the given options. If the element exists, its vector is replaced.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `element` - string - the name of the element.
+//
+// `vector` - []float32 - the vector of the element.
+//
+// `options` - VAddOptions.
+//
+// Returns: true if the element was added, or false if it already existed.
+//
+// Errors:
+//
+// "value at key <key> is not a vector set" - when the key exists but is not a vector set.
+//
+// "vector has <n> dimensions, but the vector set has <dim>" - when the vector has the wrong dimension.
+//
+// "the vector set at key <key> uses the <metric> metric and <index> index" - when the options name a different
+// metric or index than the existing set.
+//
+// "invalid attributes: <reason>" - when the attributes are not a JSON object.
+func (server *SugarDB) VAdd(key, element string, vector []float32, options VAddOptions) (bool, error) {
+	cmd := []string{"VADD", key, "FP32", string(vector_set.EncodeVector(vector)), element}
+	if options.SetAttributes || options.Attributes != "" {
+		cmd = append(cmd, "SETATTR", options.Attributes)
+	}
+	if options.M > 0 {
+		cmd = append(cmd, "M", strconv.Itoa(options.M))
+	}
+	if options.EF > 0 {
+		cmd = append(cmd, "EF", strconv.Itoa(options.EF))
+	}
+	if options.Metric != "" {
+		cmd = append(cmd, "METRIC", options.Metric)
+	}
+	if options.Index != "" {
+		cmd = append(cmd, "INDEX", options.Index)
+	}
+	b, err := server.handleCommand(server.context, internal.EncodeCommand(cmd), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	added, err := internal.ParseIntegerResponse(b)
+	return added == 1, err
+}
+
+// VSim returns the elements of the vector set at the key that are most similar to the vector, from the most
+// similar to the least similar.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `vector` - []float32 - the query vector.
+//
+// `options` - VSimOptions.
+//
+// Returns: the most similar elements with their scores and attributes. An empty slice if the key doesn't exist.
+//
+// Errors:
+//
+// "value at key <key> is not a vector set" - when the key exists but is not a vector set.
+//
+// "query has <n> dimensions, but the vector set has <dim>" - when the vector has the wrong dimension.
+func (server *SugarDB) VSim(key string, vector []float32, options VSimOptions) ([]VSimResult, error) {
+	return server.vSim([]string{"VSIM", key, "FP32", string(vector_set.EncodeVector(vector))}, options)
+}
+
+// VSimElement returns the elements of the vector set at the key that are most similar to the vector of the
+// given element. The element itself is included in the results.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `element` - string - the element whose vector is the query.
+//
+// `options` - VSimOptions.
+//
+// Returns: the most similar elements with their scores and attributes. An empty slice if the key doesn't exist.
+//
+// Errors:
+//
+// "element <element> does not exist" - when the element is not in the set.
+func (server *SugarDB) VSimElement(key, element string, options VSimOptions) ([]VSimResult, error) {
+	return server.vSim([]string{"VSIM", key, "ELE", element}, options)
+}
+
+// VRem removes an element from the vector set at the key. The key is deleted with its last element.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `element` - string - the element to remove.
+//
+// Returns: true if the element was removed.
+func (server *SugarDB) VRem(key, element string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VREM", key, element}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	removed, err := internal.ParseIntegerResponse(b)
+	return removed == 1, err
+}
+
+// VCard returns the number of elements in the vector set at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// Returns: the number of elements, or 0 if the key doesn't exist.
+func (server *SugarDB) VCard(key string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VCARD", key}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// VDim returns the number of dimensions of the vectors in the vector set at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// Returns: the number of dimensions.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) VDim(key string) (int, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VDIM", key}), nil, false, true)
+	if err != nil {
+		return 0, err
+	}
+	return internal.ParseIntegerResponse(b)
+}
+
+// VEmb returns the vector of an element of the vector set at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `element` - string - the name of the element.
+//
+// Returns: the vector of the element, or nil if the key or element doesn't exist.
+func (server *SugarDB) VEmb(key, element string) ([]float32, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VEMB", key, element}), nil, false, true)
+	if err != nil {
+		return nil, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := res.([]interface{})
+	if !ok {
+		return nil, nil
+	}
+	vector := make([]float32, len(values))
+	for i, value := range values {
+		s, _ := value.(string)
+		v, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return nil, err
+		}
+		vector[i] = float32(v)
+	}
+	return vector, nil
+}
+
+// VGetAttr returns the attributes of an element of the vector set at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `element` - string - the name of the element.
+//
+// Returns: the attributes as a JSON object, or an empty string if the key or element doesn't exist or the
+// element has no attributes.
+func (server *SugarDB) VGetAttr(key, element string) (string, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VGETATTR", key, element}), nil, false, true)
+	if err != nil {
+		return "", err
+	}
+	return internal.ParseStringResponse(b)
+}
+
+// VSetAttr replaces the attributes of an element of the vector set at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `element` - string - the name of the element.
+//
+// `attributes` - string - a JSON object. An empty string removes the attributes.
+//
+// Returns: true if the attributes were set, or false if the key or element doesn't exist.
+//
+// Errors:
+//
+// "invalid attributes: <reason>" - when the attributes are not a JSON object.
+func (server *SugarDB) VSetAttr(key, element, attributes string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VSETATTR", key, element, attributes}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	ok, err := internal.ParseIntegerResponse(b)
+	return ok == 1, err
+}
+
+// VIsMember checks whether an element is in the vector set at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// `element` - string - the name of the element.
+//
+// Returns: true if the element is in the set.
+func (server *SugarDB) VIsMember(key, element string) (bool, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VISMEMBER", key, element}), nil, false, true)
+	if err != nil {
+		return false, err
+	}
+	ok, err := internal.ParseIntegerResponse(b)
+	return ok == 1, err
+}
+
+// VInfo returns information about the vector set at the key.
+//
+// Parameters:
+//
+// `key` - string - the key of the vector set.
+//
+// Returns: a VSetInfo describing the set.
+//
+// Errors:
+//
+// "key <key> does not exist" - when the key doesn't exist.
+func (server *SugarDB) VInfo(key string) (VSetInfo, error) {
+	b, err := server.handleCommand(server.context, internal.EncodeCommand([]string{"VINFO", key}), nil, false, true)
+	if err != nil {
+		return VSetInfo{}, err
+	}
+	res, err := internal.ParseAnyResponse(b)
+	if err != nil {
+		return VSetInfo{}, err
+	}
+	values, _ := res.([]interface{})
+	var info VSetInfo
+	for i := 0; i+1 < len(values); i += 2 {
+		field, _ := values[i].(string)
+		n, _ := values[i+1].(int)
+		s, _ := values[i+1].(string)
+		switch field {
+		case "metric":
+			info.Metric = s
+		case "index":
+			info.Index = s
+		case "vector-dim":
+			info.Dimension = n
+		case "size":
+			info.Size = n
+		case "max-level":
+			info.MaxLevel = n
+		case "hnsw-m":
+			info.M = n
+		case "hnsw-ef":
+			info.EF = n
+		case "memory":
+			info.MemoryUsage = n
+		}
+	}
+	return info, nil
+}
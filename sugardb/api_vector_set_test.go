@@ -0,0 +1,324 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSugarDB_VectorSet(t *testing.T) {
+	server := createSugarDB()
+
+	t.Cleanup(func() {
+		server.ShutDown()
+	})
+
+	t.Run("TestSugarDB_VADD", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name        string
+			presetValue interface{}
+			key         string
+			element     string
+			vector      []float32
+			options     VAddOptions
+			want        bool
+			wantErr     bool
+		}{
+			{
+				name:    "1. Create a vector set when the key doesn't exist",
+				key:     "vadd_key1",
+				element: "a",
+				vector:  []float32{1, 0, 0},
+				want:    true,
+				wantErr: false,
+			},
+			{
+				name:    "2. Create a flat vector set with the l2 metric",
+				key:     "vadd_key2",
+				element: "a",
+				vector:  []float32{1, 2},
+				options: VAddOptions{Metric: "l2", Index: "flat"},
+				want:    true,
+				wantErr: false,
+			},
+			{
+				name:    "3. Throw error when the options are not valid",
+				key:     "vadd_key3",
+				element: "a",
+				vector:  []float32{1, 2},
+				options: VAddOptions{Metric: "manhattan"},
+				want:    false,
+				wantErr: true,
+			},
+			{
+				name:    "4. Throw error when the attributes are not a JSON object",
+				key:     "vadd_key4",
+				element: "a",
+				vector:  []float32{1, 2},
+				options: VAddOptions{Attributes: "[1, 2]"},
+				want:    false,
+				wantErr: true,
+			},
+			{
+				name:        "5. Throw error when the key does not hold a vector set",
+				presetValue: "Default value",
+				key:         "vadd_key5",
+				element:     "a",
+				vector:      []float32{1, 2},
+				want:        false,
+				wantErr:     true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.presetValue != nil {
+					err := presetValue(server, server.context, tt.key, tt.presetValue)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				got, err := server.VAdd(tt.key, tt.element, tt.vector, tt.options)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("VADD() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if got != tt.want {
+					t.Errorf("VADD() got = %v, want %v", got, tt.want)
+				}
+				if tt.wantErr {
+					return
+				}
+				vector, err := server.VEmb(tt.key, tt.element)
+				if err != nil || !reflect.DeepEqual(vector, tt.vector) {
+					t.Errorf("VEMB() got = %v, want %v, error = %v", vector, tt.vector, err)
+				}
+			})
+		}
+
+		t.Run("6. Throw error when the vector has the wrong dimension", func(t *testing.T) {
+			if _, err := server.VAdd("vadd_key1", "b", []float32{1, 0}, VAddOptions{}); err == nil {
+				t.Error("expected error for a vector with the wrong dimension")
+			}
+		})
+	})
+
+	t.Run("TestSugarDB_VSIM", func(t *testing.T) {
+		t.Parallel()
+
+		elements := map[string][]float32{
+			"north": {0, 1},
+			"east":  {1, 0},
+			"south": {0, -1},
+			"west":  {-1, 0},
+			"ne":    {1, 1},
+		}
+		attributes := map[string]string{
+			"north": `{"axis":"y","length":1}`,
+			"south": `{"axis":"y","length":1}`,
+			"ne":    `{"length":1.41}`,
+		}
+		for _, index := range []string{"hnsw", "flat"} {
+			key := "vsim_" + index
+			for element, vector := range elements {
+				if _, err := server.VAdd(key, element, vector, VAddOptions{Attributes: attributes[element], Index: index}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}
+
+		tests := []struct {
+			name    string
+			key     string
+			vector  []float32
+			element string
+			options VSimOptions
+			want    []string
+			wantErr bool
+		}{
+			{
+				name:    "1. Return the most similar elements of an HNSW index",
+				key:     "vsim_hnsw",
+				vector:  []float32{1, 0.1},
+				options: VSimOptions{Count: 3},
+				want:    []string{"east", "ne", "north"},
+			},
+			{
+				name:    "2. Return the most similar elements of a flat index",
+				key:     "vsim_flat",
+				vector:  []float32{1, 0.1},
+				options: VSimOptions{Count: 3},
+				want:    []string{"east", "ne", "north"},
+			},
+			{
+				name:    "3. Filter the elements by their attributes",
+				key:     "vsim_hnsw",
+				vector:  []float32{1, 0.1},
+				options: VSimOptions{Filter: `.axis == "y"`},
+				want:    []string{"north", "south"},
+			},
+			{
+				name:    "4. Search by the vector of an element",
+				key:     "vsim_hnsw",
+				element: "west",
+				options: VSimOptions{Count: 2, Exact: true},
+				want:    []string{"west", "north"},
+			},
+			{
+				name:   "5. Return an empty result when the key doesn't exist",
+				key:    "vsim_missing",
+				vector: []float32{1, 0},
+				want:   []string{},
+			},
+			{
+				name:    "6. Throw error when the query has the wrong dimension",
+				key:     "vsim_hnsw",
+				vector:  []float32{1, 0, 0},
+				wantErr: true,
+			},
+			{
+				name:    "7. Throw error when the filter is not valid",
+				key:     "vsim_hnsw",
+				vector:  []float32{1, 0},
+				options: VSimOptions{Filter: ".axis =="},
+				wantErr: true,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var results []VSimResult
+				var err error
+				if tt.element != "" {
+					results, err = server.VSimElement(tt.key, tt.element, tt.options)
+				} else {
+					results, err = server.VSim(tt.key, tt.vector, tt.options)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Errorf("VSIM() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					return
+				}
+				got := make([]string, len(results))
+				for i, result := range results {
+					got[i] = result.Element
+					if result.Attributes != attributes[result.Element] {
+						t.Errorf("VSIM() expected attributes %q for %s, got %q",
+							attributes[result.Element], result.Element, result.Attributes)
+					}
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("VSIM() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+
+		t.Run("8. Score the elements with the metric of the set", func(t *testing.T) {
+			results, err := server.VSim("vsim_flat", []float32{0, 2}, VSimOptions{Count: 1})
+			if err != nil || len(results) != 1 || results[0].Element != "north" || results[0].Score != 1 {
+				t.Errorf("expected north with a cosine similarity of 1, got %v, error = %v", results, err)
+			}
+		})
+	})
+
+	t.Run("TestSugarDB_VectorSetElements", func(t *testing.T) {
+		t.Parallel()
+
+		key := "vset_elements_key"
+		for element, vector := range map[string][]float32{"a": {1, 2, 3}, "b": {3, 2, 1}} {
+			if _, err := server.VAdd(key, element, vector, VAddOptions{M: 4, EF: 50}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+
+		if ok, err := server.VSetAttr(key, "a", `{"color": "red"}`); err != nil || !ok {
+			t.Errorf("VSETATTR() expected true, got %v, error = %v", ok, err)
+		}
+		if attributes, err := server.VGetAttr(key, "a"); err != nil || attributes != `{"color":"red"}` {
+			t.Errorf("VGETATTR() expected attributes, got %q, error = %v", attributes, err)
+		}
+		if ok, err := server.VSetAttr(key, "missing", `{}`); err != nil || ok {
+			t.Errorf("VSETATTR() expected false for a missing element, got %v, error = %v", ok, err)
+		}
+		if _, err := server.VSetAttr(key, "a", `{"color":`); err == nil {
+			t.Error("VSETATTR() expected error for invalid attributes")
+		}
+
+		if dim, err := server.VDim(key); err != nil || dim != 3 {
+			t.Errorf("VDIM() expected 3, got %d, error = %v", dim, err)
+		}
+		info, err := server.VInfo(key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if info.Metric != "cosine" || info.Index != "hnsw" || info.Dimension != 3 || info.Size != 2 ||
+			info.M != 4 || info.EF != 50 || info.MemoryUsage <= 0 {
+			t.Errorf("VINFO() got unexpected info %+v", info)
+		}
+
+		if ok, err := server.VRem(key, "a"); err != nil || !ok {
+			t.Errorf("VREM() expected true, got %v, error = %v", ok, err)
+		}
+		if ok, err := server.VIsMember(key, "a"); err != nil || ok {
+			t.Errorf("VISMEMBER() expected false, got %v, error = %v", ok, err)
+		}
+		if card, err := server.VCard(key); err != nil || card != 1 {
+			t.Errorf("VCARD() expected 1, got %d, error = %v", card, err)
+		}
+		if vector, err := server.VEmb(key, "a"); err != nil || vector != nil {
+			t.Errorf("VEMB() expected nil for a removed element, got %v, error = %v", vector, err)
+		}
+
+		// The key is deleted with its last element.
+		if ok, err := server.VRem(key, "b"); err != nil || !ok {
+			t.Errorf("VREM() expected true, got %v, error = %v", ok, err)
+		}
+		if _, err := server.VDim(key); err == nil {
+			t.Error("VDIM() expected error for a deleted key")
+		}
+	})
+
+	t.Run("TestSugarDB_VectorSetMemory", func(t *testing.T) {
+		t.Parallel()
+
+		conf := DefaultConfig()
+		conf.DataDir = ""
+		conf.MaxMemory = 16 * 1024
+		mockServer := createSugarDBWithConfig(conf)
+		defer mockServer.ShutDown()
+
+		before := mockServer.GetServerInfo().MemoryUsed
+		vector := make([]float32, 128)
+		var err error
+		for i := 0; i < 100 && err == nil; i++ {
+			vector[i] = 1
+			_, err = mockServer.VAdd("vset_memory_key", string(rune('a'+i%26))+string(rune('a'+i/26)), vector, VAddOptions{})
+		}
+		if err == nil {
+			t.Error("expected the vector set to exceed the max memory")
+		}
+		if used := mockServer.GetServerInfo().MemoryUsed; used <= before {
+			t.Errorf("expected the memory used to grow from %d, got %d", before, used)
+		}
+	})
+
+}
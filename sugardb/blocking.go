@@ -0,0 +1,163 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// nonBlocking reports whether the command executed with the context is not allowed to block.
+// This is the case for commands replayed from the AOF or applied from the raft log.
+func nonBlocking(ctx context.Context) bool {
+	flag, _ := ctx.Value(internal.ContextNonBlocking("NonBlocking")).(bool)
+	return flag
+}
+
+// blockingCommand reports whether the store lock in the context is held by a blocking command rather than by a
+// transaction or script. Blocking commands hold the store lock while they execute so that clients woken up by the
+// same write do not read and modify the same value concurrently. The lock is released while the command waits.
+func blockingCommand(ctx context.Context) bool {
+	flag, _ := ctx.Value(internal.ContextBlockingCommand("BlockingCommand")).(bool)
+	return flag && storeLocked(ctx)
+}
+
+// lockBlockingCommand acquires the store lock for the execution of a blocking command.
+func (server *SugarDB) lockBlockingCommand(ctx context.Context) context.Context {
+	return context.WithValue(server.lockStore(ctx), internal.ContextBlockingCommand("BlockingCommand"), true)
+}
+
+// awaitKeys registers a blocked client's interest in the keys. The returned wait function blocks until one of the
+// keys is modified after awaitKeys was called or until the timeout channel receives.
+func (server *SugarDB) awaitKeys(ctx context.Context, keys []string) (func(timeout <-chan time.Time) bool, func()) {
+	// Commands executed within an atomic block (transaction or script) or replayed from a log cannot block.
+	if (storeLocked(ctx) && !blockingCommand(ctx)) || nonBlocking(ctx) {
+		return func(timeout <-chan time.Time) bool {
+			// When the command is applied from the raft log, record that it would have blocked so that the
+			// leader can wait for the keys and apply the command again.
+			if blocked, ok := ctx.Value(internal.ContextBlockedCommand("BlockedCommand")).(*internal.BlockedCommand); ok && !storeLocked(ctx) {
+				blocked.Blocked = true
+				blocked.Timeout = timeout
+			}
+			return false
+		}, func() {}
+	}
+
+	database := ctx.Value("Database").(int)
+	ch := make(chan struct{}, 1)
+
+	server.blockedClients.mut.Lock()
+	if server.blockedClients.waiters[database] == nil {
+		server.blockedClients.waiters[database] = make(map[string][]chan struct{})
+	}
+	for _, key := range keys {
+		server.blockedClients.waiters[database][key] = append(server.blockedClients.waiters[database][key], ch)
+	}
+	server.blockedClients.mut.Unlock()
+
+	once := sync.Once{}
+	cancel := func() {
+		once.Do(func() {
+			server.blockedClients.mut.Lock()
+			defer server.blockedClients.mut.Unlock()
+			for _, key := range keys {
+				waiters := slices.DeleteFunc(server.blockedClients.waiters[database][key], func(c chan struct{}) bool {
+					return c == ch
+				})
+				if len(waiters) == 0 {
+					delete(server.blockedClients.waiters[database], key)
+					continue
+				}
+				server.blockedClients.waiters[database][key] = waiters
+			}
+		})
+	}
+
+	wait := func(timeout <-chan time.Time) bool {
+		defer cancel()
+
+		// Release the store lock held by the blocking command while it waits.
+		if blockingCommand(ctx) {
+			server.storeLock.Unlock()
+			defer server.storeLock.Lock()
+		}
+
+		// Release the state mutation flag while blocked so that snapshots and AOF rewrites are not held up.
+		mutating := server.stateMutationInProgress.Load()
+		if mutating {
+			server.stateMutationInProgress.Store(false)
+			defer func() {
+				for {
+					if !server.stateCopyInProgress.Load() {
+						server.stateMutationInProgress.Store(true)
+						break
+					}
+				}
+			}()
+		}
+
+		select {
+		case <-ch:
+			return true
+		case <-timeout:
+			return false
+		}
+	}
+
+	return wait, cancel
+}
+
+// signalKeys wakes up the blocked clients waiting for any of the keys in the database.
+func (server *SugarDB) signalKeys(database int, keys ...string) {
+	server.blockedClients.mut.Lock()
+	defer server.blockedClients.mut.Unlock()
+
+	for _, key := range keys {
+		for _, ch := range server.blockedClients.waiters[database][key] {
+			select {
+			case ch <- struct{}{}:
+			default:
+				// The client has already been signalled.
+			}
+		}
+	}
+}
+
+// keysModified invalidates the transactions watching the keys and wakes up the clients blocked on them.
+func (server *SugarDB) keysModified(database int, keys ...string) {
+	server.touchWatchedKeys(database, keys...)
+	server.signalKeys(database, keys...)
+}
+
+// touchWriteKeys calls keysModified with the keys written by the command.
+// Some handlers modify composite values in place without calling SetValues, so this is done after every
+// successful write command. Blocked clients are only woken up here, once the command has completed and has been
+// logged, so that the commands of the woken clients are always logged after it.
+func (server *SugarDB) touchWriteKeys(ctx context.Context, command internal.Command, subCommand internal.SubCommand, cmd []string) {
+	keyFunc := keyExtractionFunc(command, subCommand)
+	if keyFunc == nil {
+		return
+	}
+	keys, err := keyFunc(cmd)
+	if err != nil || len(keys.WriteKeys) == 0 {
+		return
+	}
+	database, _ := ctx.Value("Database").(int)
+	server.keysModified(database, keys.WriteKeys...)
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"cmp"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+)
+
+// clientPause holds the state of the client pause started with CLIENT PAUSE.
+type clientPause struct {
+	mut        sync.Mutex
+	paused     bool          // Whether the clients are currently paused.
+	all        bool          // Whether all commands are paused. When false, only write commands are paused.
+	until      time.Time     // The time at which the pause ends.
+	generation uint64        // Incremented whenever the pause is extended or ended, so stale timers are ignored.
+	done       chan struct{} // Closed when the pause ends, either at the timeout or with CLIENT UNPAUSE.
+}
+
+// commandName returns the lowercase name of the command, e.g. "client|list" for subcommands.
+func commandName(command internal.Command, subCommand internal.SubCommand) string {
+	name := strings.ToLower(command.Command)
+	if subCommand.Command != "" {
+		name = fmt.Sprintf("%s|%s", name, strings.ToLower(subCommand.Command))
+	}
+	return name
+}
+
+// recordClientCommand records the command as the last command executed by the TCP connection.
+func (server *SugarDB) recordClientCommand(conn *net.Conn, command internal.Command, subCommand internal.SubCommand) {
+	server.connInfo.mut.Lock()
+	defer server.connInfo.mut.Unlock()
+	info, ok := server.connInfo.tcpClients[conn]
+	if !ok {
+		return
+	}
+	info.LastInteraction = server.clock.Now()
+	info.LastCommand = commandName(command, subCommand)
+	server.connInfo.tcpClients[conn] = info
+}
+
+// clientUser returns the name of the ACL user the connection is authenticated as.
+func (server *SugarDB) clientUser(conn *net.Conn) string {
+	if server.acl == nil {
+		return "default"
+	}
+	server.acl.RLockUsers()
+	defer server.acl.RUnlockUsers()
+	if connection, ok := server.acl.Connections[conn]; ok && connection.User != nil {
+		return connection.User.Username
+	}
+	return "default"
+}
+
+// tcpClients returns a copy of the connection information of the TCP clients with the user field set.
+func (server *SugarDB) tcpClients() map[*net.Conn]internal.ConnectionInfo {
+	server.connInfo.mut.RLock()
+	clients := make(map[*net.Conn]internal.ConnectionInfo, len(server.connInfo.tcpClients))
+	for conn, info := range server.connInfo.tcpClients {
+		clients[conn] = info
+	}
+	server.connInfo.mut.RUnlock()
+
+	// The ACL lock is acquired after the connection info lock is released.
+	for conn, info := range clients {
+		info.User = server.clientUser(conn)
+		clients[conn] = info
+	}
+	return clients
+}
+
+func (server *SugarDB) listClients() []internal.ConnectionInfo {
+	clients := server.tcpClients()
+	list := make([]internal.ConnectionInfo, 0, len(clients))
+	for _, info := range clients {
+		list = append(list, info)
+	}
+	slices.SortFunc(list, func(a, b internal.ConnectionInfo) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return list
+}
+
+// killClients closes the connections of the TCP clients that match.
+// The connection's read loop in handleConnection exits once the connection is closed.
+func (server *SugarDB) killClients(match func(info internal.ConnectionInfo) bool) int {
+	killed := 0
+	for conn, info := range server.tcpClients() {
+		if !match(info) {
+			continue
+		}
+		_ = (*conn).Close()
+		killed++
+	}
+	return killed
+}
+
+func (server *SugarDB) setClientName(conn *net.Conn, name string) {
+	server.connInfo.mut.Lock()
+	defer server.connInfo.mut.Unlock()
+	if info, ok := server.connInfo.tcpClients[conn]; ok {
+		info.Name = name
+		server.connInfo.tcpClients[conn] = info
+	}
+}
+
+func (server *SugarDB) setClientNoEvict(conn *net.Conn, noEvict bool) {
+	server.connInfo.mut.Lock()
+	defer server.connInfo.mut.Unlock()
+	if info, ok := server.connInfo.tcpClients[conn]; ok {
+		info.NoEvict = noEvict
+		server.connInfo.tcpClients[conn] = info
+	}
+}
+
+// pauseClients pauses the TCP clients until the timeout elapses.
+// If the clients are already paused, the pause is only extended: the later end time and the stricter mode are kept.
+func (server *SugarDB) pauseClients(timeout time.Duration, all bool) {
+	server.clientPause.mut.Lock()
+	defer server.clientPause.mut.Unlock()
+
+	until := server.clock.Now().Add(timeout)
+	if server.clientPause.paused {
+		server.clientPause.all = server.clientPause.all || all
+		if !until.After(server.clientPause.until) {
+			return
+		}
+	} else {
+		server.clientPause.paused = true
+		server.clientPause.all = all
+		server.clientPause.done = make(chan struct{})
+	}
+	server.clientPause.until = until
+	server.clientPause.generation++
+
+	// End the pause when the timeout elapses, unless it has been extended or ended in the meantime.
+	generation := server.clientPause.generation
+	go func() {
+		<-server.clock.After(timeout)
+		server.clientPause.mut.Lock()
+		defer server.clientPause.mut.Unlock()
+		if server.clientPause.paused && server.clientPause.generation == generation {
+			server.endClientPause()
+		}
+	}()
+}
+
+// unpauseClients ends the current client pause.
+func (server *SugarDB) unpauseClients() {
+	server.clientPause.mut.Lock()
+	defer server.clientPause.mut.Unlock()
+	if server.clientPause.paused {
+		server.endClientPause()
+	}
+}
+
+// endClientPause releases the clients waiting on the pause. The caller must hold the pause lock.
+func (server *SugarDB) endClientPause() {
+	server.clientPause.paused = false
+	server.clientPause.until = time.Time{}
+	server.clientPause.generation++
+	close(server.clientPause.done)
+}
+
+// waitForClientPause blocks while the clients are paused and the message is a command affected by the pause.
+// The CLIENT commands are never paused so that the clients can still be inspected, killed and unpaused.
+func (server *SugarDB) waitForClientPause(message []byte) {
+	server.clientPause.mut.Lock()
+	paused := server.clientPause.paused
+	server.clientPause.mut.Unlock()
+	if !paused {
+		return
+	}
+
+	cmd, err := internal.Decode(message)
+	if err != nil || len(cmd) == 0 {
+		return
+	}
+	command, subCommand, _, err := server.resolveCommand(cmd)
+	if err != nil || strings.EqualFold(command.Command, "client") {
+		return
+	}
+
+	for {
+		server.clientPause.mut.Lock()
+		paused, all, done := server.clientPause.paused, server.clientPause.all, server.clientPause.done
+		server.clientPause.mut.Unlock()
+
+		if !paused || (!all && !internal.IsWriteCommand(command, subCommand)) {
+			return
+		}
+		<-done
+	}
+}
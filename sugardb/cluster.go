@@ -23,7 +23,8 @@ import (
 )
 
 func (server *SugarDB) isInCluster() bool {
-	return server.config.BootstrapCluster || server.config.JoinAddr != ""
+	conf := server.currentConfig()
+	return conf.BootstrapCluster || conf.JoinAddr != ""
 }
 
 func (server *SugarDB) raftApplyDeleteKey(ctx context.Context, key string) error {
@@ -65,6 +66,14 @@ func (server *SugarDB) raftApplyDeleteKey(ctx context.Context, key string) error
 }
 
 func (server *SugarDB) raftApplyCommand(ctx context.Context, cmd []string) ([]byte, error) {
+	r, err := server.raftApply(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return r.Response, nil
+}
+
+func (server *SugarDB) raftApply(ctx context.Context, cmd []string) (internal.ApplyResponse, error) {
 	serverId, _ := ctx.Value(internal.ContextServerID("ServerID")).(string)
 	connectionId, _ := ctx.Value(internal.ContextConnID("ConnectionID")).(string)
 	protocol, _ := ctx.Value("Protocol").(int)
@@ -77,11 +86,81 @@ func (server *SugarDB) raftApplyCommand(ctx context.Context, cmd []string) ([]by
 		Protocol:     protocol,
 		Database:     database,
 		CMD:          cmd,
+		Time:         server.clock.Now().UnixNano(),
+	}
+
+	b, err := json.Marshal(applyRequest)
+	if err != nil {
+		return internal.ApplyResponse{}, fmt.Errorf("could not parse command request for commad: %+v", cmd)
+	}
+
+	applyFuture := server.raft.Apply(b, 500*time.Millisecond)
+
+	if err = applyFuture.Error(); err != nil {
+		return internal.ApplyResponse{}, err
+	}
+
+	r, ok := applyFuture.Response().(internal.ApplyResponse)
+
+	if !ok {
+		return internal.ApplyResponse{}, fmt.Errorf("unprocessable entity %v", r)
+	}
+
+	if r.Error != nil {
+		return internal.ApplyResponse{}, r.Error
+	}
+
+	return r, nil
+}
+
+// raftApplyBlockingCommand applies the command to the raft log. Commands applied from the raft log cannot block,
+// so if the command would have blocked, the leader waits for the command's keys to be modified and applies the
+// command again until it completes or times out. The timeout starts when the command is first applied.
+func (server *SugarDB) raftApplyBlockingCommand(ctx context.Context, command internal.Command, subCommand internal.SubCommand, cmd []string) ([]byte, error) {
+	var keys []string
+	if keyFunc := keyExtractionFunc(command, subCommand); keyFunc != nil {
+		if k, err := keyFunc(cmd); err == nil {
+			keys = append(append(keys, k.ReadKeys...), k.WriteKeys...)
+		}
+	}
+
+	var timeout <-chan time.Time
+	for attempt := 0; ; attempt++ {
+		wait, cancel := server.awaitKeys(ctx, keys)
+		r, err := server.raftApply(ctx, cmd)
+		if err != nil || r.Blocked == nil {
+			cancel()
+			return r.Response, err
+		}
+		if attempt == 0 {
+			timeout = r.Blocked.Timeout
+		}
+		if !wait(timeout) {
+			return r.Response, nil
+		}
+	}
+}
+
+func (server *SugarDB) raftApplyTransaction(ctx context.Context, commands [][]string, watched map[int]map[string]uint64) ([]byte, error) {
+	serverId, _ := ctx.Value(internal.ContextServerID("ServerID")).(string)
+	connectionId, _ := ctx.Value(internal.ContextConnID("ConnectionID")).(string)
+	protocol, _ := ctx.Value("Protocol").(int)
+	database, _ := ctx.Value("Database").(int)
+
+	applyRequest := internal.ApplyRequest{
+		Type:         "transaction",
+		ServerID:     serverId,
+		ConnectionID: connectionId,
+		Protocol:     protocol,
+		Database:     database,
+		Transaction:  commands,
+		Time:         server.clock.Now().UnixNano(),
+		Watched:      watched,
 	}
 
 	b, err := json.Marshal(applyRequest)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse command request for commad: %+v", cmd)
+		return nil, fmt.Errorf("could not parse transaction request for commands: %+v", commands)
 	}
 
 	applyFuture := server.raft.Apply(b, 500*time.Millisecond)
@@ -16,6 +16,8 @@ package sugardb
 
 import (
 	"context"
+	"errors"
+	"log"
 	"time"
 
 	"github.com/echovault/sugardb/internal"
@@ -29,11 +31,19 @@ func DefaultConfig() config.Config {
 	return config.DefaultConfig()
 }
 
+// currentConfig returns a copy of the configuration that can be read while it's changed with CONFIG SET.
+func (server *SugarDB) currentConfig() config.Config {
+	server.configLock.RLock()
+	defer server.configLock.RUnlock()
+	return server.config
+}
+
 func (server *SugarDB) GetServerInfo() internal.ServerInfo {
+	conf := server.currentConfig()
 	return internal.ServerInfo{
 		Server:  "sugardb",
 		Version: constants.Version,
-		Id:      server.config.ServerID,
+		Id:      conf.ServerID,
 		Mode: func() string {
 			if server.isInCluster() {
 				return "cluster"
@@ -51,8 +61,103 @@ func (server *SugarDB) GetServerInfo() internal.ServerInfo {
 		}(),
 		Modules:    server.ListModules(),
 		MemoryUsed: server.memUsed,
-		MaxMemory:  server.config.MaxMemory,
+		MaxMemory:  conf.MaxMemory,
+	}
+}
+
+// getConfig returns the values of the configuration parameters that match any of the glob patterns.
+func (server *SugarDB) getConfig(ctx context.Context, patterns ...string) (map[string]string, error) {
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
+	return config.GetParameters(server.currentConfig(), patterns...)
+}
+
+// setConfig changes the configuration parameters at runtime and applies the changes to the running engines.
+// The parameters are applied together, so none of them is changed if any of them is invalid.
+func (server *SugarDB) setConfig(ctx context.Context, params map[string]string) error {
+	// The store lock guards the config against the keyspace functions that read it.
+	if !storeLocked(ctx) {
+		ctx = server.lockStore(ctx)
+		defer server.storeLock.Unlock()
+	}
+
+	conf := server.currentConfig()
+	for name, value := range params {
+		if err := config.SetParameter(&conf, name, value); err != nil {
+			return err
+		}
+	}
+	if conf.RequirePass && conf.Password == "" {
+		return errors.New("password cannot be empty if require-pass is true")
+	}
+
+	server.configLock.Lock()
+	previous := server.config
+	server.config = conf
+	server.configLock.Unlock()
+
+	if conf.EvictionPolicy != previous.EvictionPolicy || conf.EvictionInterval != previous.EvictionInterval {
+		interval := conf.EvictionInterval
+		if conf.EvictionPolicy == constants.NoEviction {
+			interval = 0
+		}
+		// Discard a pending update that has not been picked up by the TTL sampling goroutine yet.
+		select {
+		case <-server.evictionIntervalUpdates:
+		default:
+		}
+		server.evictionIntervalUpdates <- interval
+	}
+
+	if conf.EvictionPolicy != previous.EvictionPolicy || conf.MaxMemory != previous.MaxMemory {
+		// The eviction caches are only maintained for the configured policy while there's a memory limit,
+		// so they're rebuilt from the current keys. This also evicts keys if the new limit is exceeded.
+		server.initialiseCaches()
+		for database, store := range server.store {
+			keys := make([]string, 0, len(store))
+			for key := range store {
+				keys = append(keys, key)
+			}
+			if _, err := server.updateKeysInCache(context.WithValue(ctx, "Database", database), keys); err != nil {
+				log.Printf("set config: %v\n", err)
+			}
+		}
+	}
+
+	if !server.isInCluster() {
+		if conf.AOFSyncStrategy != previous.AOFSyncStrategy {
+			server.aofEngine.SetStrategy(conf.AOFSyncStrategy)
+		}
+		if conf.SnapshotInterval != previous.SnapshotInterval {
+			server.snapshotEngine.SetInterval(conf.SnapshotInterval)
+		}
+		if conf.SnapShotThreshold != previous.SnapShotThreshold {
+			server.snapshotEngine.SetThreshold(conf.SnapShotThreshold)
+		}
+	}
+
+	if conf.RequirePass != previous.RequirePass || conf.Password != previous.Password {
+		server.acl.SetRequirePass(conf.RequirePass, conf.Password)
+	}
+
+	if conf.NotifyKeyspaceEvents != previous.NotifyKeyspaceEvents {
+		// The flags were validated by config.SetParameter.
+		keyspaceEvents, _ := internal.ParseKeyspaceEvents(conf.NotifyKeyspaceEvents)
+		server.keyspaceEvents.Store(int64(keyspaceEvents))
+	}
+
+	return nil
+}
+
+// rewriteConfig persists the configuration parameters that can be changed at runtime to the config file.
+func (server *SugarDB) rewriteConfig(ctx context.Context) error {
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
 	}
+	return config.Rewrite(server.currentConfig())
 }
 
 // WithTLS is an option to the NewSugarDB function that allows you to pass a
@@ -378,3 +483,29 @@ func WithRaftBindPort(raftBindPort uint16) func(sugardb *SugarDB) {
 		sugardb.config.RaftBindPort = raftBindPort
 	}
 }
+
+// WithNotifyKeyspaceEvents is an option to the NewSugarDB function that allows you to pass a
+// custom NotifyKeyspaceEvents to SugarDB.
+// If not specified, SugarDB will use the default configuration from config.DefaultConfig().
+func WithNotifyKeyspaceEvents(notifyKeyspaceEvents string) func(sugardb *SugarDB) {
+	return func(sugardb *SugarDB) {
+		sugardb.config.NotifyKeyspaceEvents = notifyKeyspaceEvents
+	}
+}
+
+// WithKeyspaceEventHandler is an option to the NewSugarDB function that registers a handler for keyspace events.
+// The handler receives the events of the classes enabled with NotifyKeyspaceEvents, whether or not the K and E
+// flags are set. Events are delivered in order on a separate goroutine, so the handler can call SugarDB methods.
+func WithKeyspaceEventHandler(handler func(event KeyspaceEvent)) func(sugardb *SugarDB) {
+	return func(sugardb *SugarDB) {
+		sugardb.keyspaceEventHandler = handler
+	}
+}
+
+// WithConfigFile is an option to the NewSugarDB function that sets the JSON or YAML file that
+// CONFIG REWRITE persists the configuration to.
+func WithConfigFile(configFile string) func(sugardb *SugarDB) {
+	return func(sugardb *SugarDB) {
+		sugardb.config.ConfigFile = configFile
+	}
+}
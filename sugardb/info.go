@@ -0,0 +1,291 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"context"
+	"fmt"
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/constants"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// serverStats holds the counters reported by the INFO command.
+type serverStats struct {
+	startTime              time.Time
+	totalCommandsProcessed atomic.Int64
+	expiredKeys            atomic.Int64
+	evictedKeys            atomic.Int64
+	keyspaceHits           atomic.Int64
+	keyspaceMisses         atomic.Int64
+	// The statistics of each command. This map's shape is map[string]*commandStats with the string key being the
+	// lowercase command name, or "command|subcommand" for subcommands.
+	commands sync.Map
+}
+
+type commandStats struct {
+	calls         atomic.Int64
+	usec          atomic.Int64
+	rejectedCalls atomic.Int64
+	failedCalls   atomic.Int64
+}
+
+// recordCommandStats updates the statistics of the command after it has been executed.
+// rejected is true when the command was not executed because the connection is not authorized to execute it.
+func (server *SugarDB) recordCommandStats(command internal.Command, subCommand internal.SubCommand,
+	start time.Time, err error, rejected bool) {
+	s, _ := server.stats.commands.LoadOrStore(commandName(command, subCommand), &commandStats{})
+	stats := s.(*commandStats)
+
+	if rejected {
+		stats.rejectedCalls.Add(1)
+		return
+	}
+
+	server.stats.totalCommandsProcessed.Add(1)
+	stats.calls.Add(1)
+	stats.usec.Add(time.Since(start).Microseconds())
+	if err != nil {
+		stats.failedCalls.Add(1)
+	}
+}
+
+// countKeyspaceLookup counts a key lookup as a keyspace hit or miss if it's made by a read-only command.
+func (server *SugarDB) countKeyspaceLookup(ctx context.Context, hit bool) {
+	if read, _ := ctx.Value(internal.ContextReadCommand("ReadCommand")).(bool); !read {
+		return
+	}
+	if hit {
+		server.stats.keyspaceHits.Add(1)
+	} else {
+		server.stats.keyspaceMisses.Add(1)
+	}
+}
+
+// getInfo returns all the sections of the INFO command in the order they're reported.
+func (server *SugarDB) getInfo(ctx context.Context) []internal.InfoSection {
+	return []internal.InfoSection{
+		{Name: "server", Fields: server.getServerInfoFields()},
+		{Name: "clients", Fields: server.getClientsInfoFields()},
+		{Name: "memory", Fields: server.getMemoryInfoFields()},
+		{Name: "persistence", Fields: server.getPersistenceInfoFields()},
+		{Name: "stats", Fields: server.getStatsInfoFields()},
+		{Name: "replication", Fields: server.getReplicationInfoFields()},
+		{Name: "keyspace", Fields: server.getKeyspaceInfoFields(ctx)},
+		{Name: "commandstats", Fields: server.getCommandStatsInfoFields()},
+	}
+}
+
+func (server *SugarDB) getServerInfoFields() []internal.InfoField {
+	serverInfo := server.GetServerInfo()
+	uptime := int64(server.clock.Now().Sub(server.stats.startTime).Seconds())
+	return []internal.InfoField{
+		{Name: "sugardb_version", Value: constants.Version},
+		{Name: "sugardb_mode", Value: serverInfo.Mode},
+		{Name: "server_id", Value: serverInfo.Id},
+		{Name: "os", Value: fmt.Sprintf("%s %s", runtime.GOOS, runtime.GOARCH)},
+		{Name: "go_version", Value: runtime.Version()},
+		{Name: "process_id", Value: strconv.Itoa(os.Getpid())},
+		{Name: "tcp_port", Value: strconv.Itoa(int(server.currentConfig().Port))},
+		{Name: "uptime_in_seconds", Value: strconv.FormatInt(uptime, 10)},
+		{Name: "uptime_in_days", Value: strconv.FormatInt(uptime/86400, 10)},
+	}
+}
+
+func (server *SugarDB) getClientsInfoFields() []internal.InfoField {
+	server.connInfo.mut.RLock()
+	connectedClients := len(server.connInfo.tcpClients)
+	server.connInfo.mut.RUnlock()
+
+	// Each blocked client waits on a single channel, which may be registered against multiple keys.
+	blocked := make(map[chan struct{}]struct{})
+	server.blockedClients.mut.Lock()
+	for _, database := range server.blockedClients.waiters {
+		for _, waiters := range database {
+			for _, ch := range waiters {
+				blocked[ch] = struct{}{}
+			}
+		}
+	}
+	server.blockedClients.mut.Unlock()
+
+	return []internal.InfoField{
+		{Name: "connected_clients", Value: strconv.Itoa(connectedClients)},
+		{Name: "blocked_clients", Value: strconv.Itoa(len(blocked))},
+	}
+}
+
+func (server *SugarDB) getMemoryInfoFields() []internal.InfoField {
+	memUsed := server.GetServerInfo().MemoryUsed
+	conf := server.currentConfig()
+	return []internal.InfoField{
+		{Name: "used_memory", Value: strconv.FormatInt(memUsed, 10)},
+		{Name: "used_memory_human", Value: humanReadableBytes(uint64(max(memUsed, 0)))},
+		{Name: "maxmemory", Value: strconv.FormatUint(conf.MaxMemory, 10)},
+		{Name: "maxmemory_human", Value: humanReadableBytes(conf.MaxMemory)},
+		{Name: "maxmemory_policy", Value: conf.EvictionPolicy},
+	}
+}
+
+func (server *SugarDB) getPersistenceInfoFields() []internal.InfoField {
+	var changes uint64
+	aofEnabled := false
+	aofLastRewriteTime := int64(-1)
+	aofLastRewriteStatus := "ok"
+	if !server.isInCluster() {
+		changes = server.snapshotEngine.ChangeCount()
+		aofEnabled = true
+		stats := server.aofEngine.Stats()
+		if stats.LastRewriteTime != 0 {
+			aofLastRewriteTime = stats.LastRewriteTime
+		}
+		if stats.LastRewriteFailed {
+			aofLastRewriteStatus = "err"
+		}
+	}
+
+	return []internal.InfoField{
+		{Name: "rdb_changes_since_last_save", Value: strconv.FormatUint(changes, 10)},
+		{Name: "rdb_bgsave_in_progress", Value: boolInfoValue(server.snapshotInProgress.Load())},
+		{Name: "rdb_last_save_time", Value: strconv.FormatInt(server.getLatestSnapshotTime()/1000, 10)},
+		{Name: "aof_enabled", Value: boolInfoValue(aofEnabled)},
+		{Name: "aof_rewrite_in_progress", Value: boolInfoValue(server.rewriteAOFInProgress.Load())},
+		{Name: "aof_last_rewrite_time", Value: strconv.FormatInt(aofLastRewriteTime, 10)},
+		{Name: "aof_last_bgrewrite_status", Value: aofLastRewriteStatus},
+	}
+}
+
+func (server *SugarDB) getStatsInfoFields() []internal.InfoField {
+	var pubSubChannels int
+	for _, channel := range server.pubSub.GetAllChannels() {
+		if channel.Pattern() == nil && channel.IsActive() {
+			pubSubChannels += 1
+		}
+	}
+
+	return []internal.InfoField{
+		{Name: "total_connections_received", Value: strconv.FormatUint(server.connId.Load(), 10)},
+		{Name: "total_commands_processed", Value: strconv.FormatInt(server.stats.totalCommandsProcessed.Load(), 10)},
+		{Name: "expired_keys", Value: strconv.FormatInt(server.stats.expiredKeys.Load(), 10)},
+		{Name: "evicted_keys", Value: strconv.FormatInt(server.stats.evictedKeys.Load(), 10)},
+		{Name: "keyspace_hits", Value: strconv.FormatInt(server.stats.keyspaceHits.Load(), 10)},
+		{Name: "keyspace_misses", Value: strconv.FormatInt(server.stats.keyspaceMisses.Load(), 10)},
+		{Name: "pubsub_channels", Value: strconv.Itoa(pubSubChannels)},
+		{Name: "pubsub_patterns", Value: strconv.Itoa(server.pubSub.NumPat())},
+	}
+}
+
+func (server *SugarDB) getReplicationInfoFields() []internal.InfoField {
+	fields := []internal.InfoField{
+		{Name: "role", Value: server.GetServerInfo().Role},
+	}
+	if !server.isInCluster() {
+		return fields
+	}
+
+	stats := server.raft.Stats()
+	leaderAddr, leaderId := server.raft.Leader()
+	return append(fields, []internal.InfoField{
+		{Name: "raft_state", Value: strings.ToLower(stats["state"])},
+		{Name: "raft_term", Value: stats["term"]},
+		{Name: "raft_last_log_index", Value: stats["last_log_index"]},
+		{Name: "raft_commit_index", Value: stats["commit_index"]},
+		{Name: "raft_applied_index", Value: stats["applied_index"]},
+		{Name: "raft_num_peers", Value: stats["num_peers"]},
+		{Name: "leader_id", Value: leaderId},
+		{Name: "leader_addr", Value: leaderAddr},
+	}...)
+}
+
+func (server *SugarDB) getKeyspaceInfoFields(ctx context.Context) []internal.InfoField {
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
+
+	var databases []int
+	for database, keys := range server.store {
+		if len(keys) > 0 {
+			databases = append(databases, database)
+		}
+	}
+	slices.Sort(databases)
+
+	fields := make([]internal.InfoField, len(databases))
+	for i, database := range databases {
+		var expires int
+		for _, data := range server.store[database] {
+			if data.ExpireAt != (time.Time{}) {
+				expires += 1
+			}
+		}
+		fields[i] = internal.InfoField{
+			Name:  fmt.Sprintf("db%d", database),
+			Value: fmt.Sprintf("keys=%d,expires=%d", len(server.store[database]), expires),
+		}
+	}
+	return fields
+}
+
+func (server *SugarDB) getCommandStatsInfoFields() []internal.InfoField {
+	var fields []internal.InfoField
+	server.stats.commands.Range(func(key, value any) bool {
+		stats := value.(*commandStats)
+		calls, usec := stats.calls.Load(), stats.usec.Load()
+		usecPerCall := float64(0)
+		if calls > 0 {
+			usecPerCall = float64(usec) / float64(calls)
+		}
+		fields = append(fields, internal.InfoField{
+			Name: fmt.Sprintf("cmdstat_%s", key.(string)),
+			Value: fmt.Sprintf("calls=%d,usec=%d,usec_per_call=%.2f,rejected_calls=%d,failed_calls=%d",
+				calls, usec, usecPerCall, stats.rejectedCalls.Load(), stats.failedCalls.Load()),
+		})
+		return true
+	})
+	slices.SortFunc(fields, func(a, b internal.InfoField) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return fields
+}
+
+func boolInfoValue(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// humanReadableBytes formats the number of bytes the way the INFO command reports memory, e.g. 1.50M.
+func humanReadableBytes(n uint64) string {
+	units := []string{"B", "K", "M", "G", "T", "P"}
+	value := float64(n)
+	i := 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%dB", n)
+	}
+	return fmt.Sprintf("%.2f%s", value, units[i])
+}
@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
-	"reflect"
 	"runtime"
 	"slices"
 	"strings"
@@ -33,6 +32,7 @@ import (
 	"github.com/echovault/sugardb/internal/constants"
 	"github.com/echovault/sugardb/internal/eviction"
 	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/list"
 )
 
 // SwapDBs swaps every TCP client connection from database1 over to database2.
@@ -40,19 +40,27 @@ import (
 // This only affects TCP connections, it does not swap the logical database currently
 // being used by the embedded API.
 func (server *SugarDB) SwapDBs(database1, database2 int) {
+	server.swapDBs(server.context, database1, database2)
+}
+
+func (server *SugarDB) swapDBs(ctx context.Context, database1, database2 int) {
 	// If the databases are the same, skip the swap.
 	if database1 == database2 {
 		return
 	}
 
 	// If any of the databases does not exist, create them.
-	server.storeLock.Lock()
+	if !storeLocked(ctx) {
+		server.storeLock.Lock()
+	}
 	for _, database := range []int{database1, database2} {
 		if server.store[database] == nil {
 			server.createDatabase(database)
 		}
 	}
-	server.storeLock.Unlock()
+	if !storeLocked(ctx) {
+		server.storeLock.Unlock()
+	}
 
 	// Swap the connections for each database.
 	server.connInfo.mut.Lock()
@@ -80,8 +88,20 @@ func (server *SugarDB) SwapDBs(database1, database2 int) {
 // Flush flushes all the data from the database at the specified index.
 // When -1 is passed, all the logical databases are cleared.
 func (server *SugarDB) Flush(database int) {
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
+	server.flush(server.context, database)
+}
+
+func (server *SugarDB) flush(ctx context.Context, database int) {
+	if !storeLocked(ctx) {
+		server.storeLock.Lock()
+		defer server.storeLock.Unlock()
+	}
+
+	// Invalidate the transactions watching keys in the flushed databases.
+	server.touchWatchedDatabase(database)
+
+	// Remove the documents of the flushed databases from the search indexes.
+	server.search.Flush(database)
 
 	server.keysWithExpiry.rwMutex.Lock()
 	defer server.keysWithExpiry.rwMutex.Unlock()
@@ -90,6 +110,7 @@ func (server *SugarDB) Flush(database int) {
 		for db, _ := range server.store {
 			// Clear db store.
 			clear(server.store[db])
+			server.scanIndex[db].Clear()
 			// Clear db volatile key tracker.
 			clear(server.keysWithExpiry.keys[db])
 			// Clear db LFU cache.
@@ -106,6 +127,7 @@ func (server *SugarDB) Flush(database int) {
 
 	// Clear db store.
 	clear(server.store[database])
+	server.scanIndex[database].Clear()
 	// Clear db volatile key tracker.
 	clear(server.keysWithExpiry.keys[database])
 	// Clear db LFU cache.
@@ -119,8 +141,10 @@ func (server *SugarDB) Flush(database int) {
 }
 
 func (server *SugarDB) keysExist(ctx context.Context, keys []string) map[string]bool {
-	server.storeLock.RLock()
-	defer server.storeLock.RUnlock()
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
@@ -129,14 +153,20 @@ func (server *SugarDB) keysExist(ctx context.Context, keys []string) map[string]
 	for _, key := range keys {
 		_, ok := server.store[database][key]
 		exists[key] = ok
+		// Only misses are counted here as the handler reads existing keys with getValues.
+		if !ok {
+			server.countKeyspaceLookup(ctx, false)
+		}
 	}
 
 	return exists
 }
 
 func (server *SugarDB) getKeys(ctx context.Context) []string {
-	server.storeLock.RLock()
-	defer server.storeLock.RUnlock()
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
@@ -150,9 +180,46 @@ func (server *SugarDB) getKeys(ctx context.Context) []string {
 	return keys
 }
 
+func (server *SugarDB) scanKeys(
+	ctx context.Context,
+	cursor uint64,
+	count int,
+	filter func(key string, value interface{}) bool,
+) (uint64, []string) {
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
+
+	database := ctx.Value("Database").(int)
+	store := server.store[database]
+	if store == nil {
+		return 0, []string{}
+	}
+
+	next, visited := server.scanIndex[database].Scan(cursor, count)
+
+	now := server.clock.Now()
+	keys := make([]string, 0, len(visited))
+	for _, key := range visited {
+		entry := store[key]
+		if entry.ExpireAt != (time.Time{}) && entry.ExpireAt.Before(now) {
+			continue
+		}
+		if filter != nil && !filter(key, entry.Value) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return next, keys
+}
+
 func (server *SugarDB) getExpiry(ctx context.Context, key string) time.Time {
-	server.storeLock.RLock()
-	defer server.storeLock.RUnlock()
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
@@ -165,8 +232,10 @@ func (server *SugarDB) getExpiry(ctx context.Context, key string) time.Time {
 }
 
 func (server *SugarDB) getHashExpiry(ctx context.Context, key string, field string) time.Time {
-	server.storeLock.RLock()
-	defer server.storeLock.RUnlock()
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
@@ -175,14 +244,16 @@ func (server *SugarDB) getHashExpiry(ctx context.Context, key string, field stri
 		return time.Time{}
 	}
 
-	hash := entry.Value.(hash.Hash)
+	hash := entry.Value.(*hash.Hash)
 
-	return hash[field].ExpireAt
+	return hash.Get(field).ExpireAt
 }
 
 func (server *SugarDB) getValues(ctx context.Context, keys []string) map[string]interface{} {
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
+	if !storeLocked(ctx) {
+		server.storeLock.Lock()
+		defer server.storeLock.Unlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
@@ -191,24 +262,21 @@ func (server *SugarDB) getValues(ctx context.Context, keys []string) map[string]
 	for _, key := range keys {
 		entry, ok := server.store[database][key]
 		if !ok {
+			server.countKeyspaceLookup(ctx, false)
 			values[key] = nil
 			continue
 		}
 
 		if entry.ExpireAt != (time.Time{}) && entry.ExpireAt.Before(server.clock.Now()) {
-			if !server.isInCluster() {
+			server.countKeyspaceLookup(ctx, false)
+			if !server.isInCluster() || server.raft.IsRaftLeader() {
 				// If in standalone mode, delete the key directly.
-				err := server.deleteKey(ctx, key)
-				if err != nil {
-					log.Printf("keyExists: %+v\n", err)
-				}
-			} else if server.isInCluster() && server.raft.IsRaftLeader() {
 				// If we're in a raft cluster, and we're the leader, send command to delete the key in the cluster.
-				err := server.raftApplyDeleteKey(ctx, key)
+				err := server.expireKey(ctx, key)
 				if err != nil {
 					log.Printf("keyExists: %+v\n", err)
 				}
-			} else if server.isInCluster() && !server.raft.IsRaftLeader() {
+			} else {
 				// Forward message to leader to initiate key deletion.
 				// This is always called regardless of ForwardCommand config value
 				// because we always want to remove expired keys.
@@ -218,6 +286,7 @@ func (server *SugarDB) getValues(ctx context.Context, keys []string) map[string]
 			continue
 		}
 
+		server.countKeyspaceLookup(ctx, true)
 		values[key] = entry.Value
 	}
 
@@ -226,16 +295,18 @@ func (server *SugarDB) getValues(ctx context.Context, keys []string) map[string]
 		if _, err := server.updateKeysInCache(ctx, keys); err != nil {
 			log.Printf("getValues error: %+v\n", err)
 		}
-	}(ctx, keys)
+	}(releaseStoreLock(ctx), keys)
 
 	return values
 }
 
 func (server *SugarDB) setValues(ctx context.Context, entries map[string]interface{}) error {
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
+	if !storeLocked(ctx) {
+		server.storeLock.Lock()
+		defer server.storeLock.Unlock()
+	}
 
-	if internal.IsMaxMemoryExceeded(server.memUsed, server.config.MaxMemory) && server.config.EvictionPolicy == constants.NoEviction {
+	if conf := server.currentConfig(); internal.IsMaxMemoryExceeded(server.memUsed, conf.MaxMemory) && conf.EvictionPolicy == constants.NoEviction {
 
 		return errors.New("max memory reached, key value not set")
 	}
@@ -247,10 +318,22 @@ func (server *SugarDB) setValues(ctx context.Context, entries map[string]interfa
 		server.createDatabase(database)
 	}
 
+	event, notify := commandEvent(ctx)
+
 	for key, value := range entries {
+		server.touchWatchedKeys(database, key)
+
+		// Lists are stored as *list.List. Plain string slices written by modules and scripts are converted here.
+		if elements, ok := value.([]string); ok {
+			value = list.NewList(elements...)
+		}
+
 		expireAt := time.Time{}
-		if _, ok := server.store[database][key]; ok {
+		_, exists := server.store[database][key]
+		if exists {
 			expireAt = server.store[database][key].ExpireAt
+		} else {
+			server.scanIndex[database].Insert(key)
 		}
 		server.store[database][key] = internal.KeyData{
 			Value:    value,
@@ -265,9 +348,19 @@ func (server *SugarDB) setValues(ctx context.Context, entries map[string]interfa
 		server.memUsed += int64(unsafe.Sizeof(key))
 		server.memUsed += int64(len(key))
 
+		// Update the search indexes that include the key.
+		server.search.Update(database, key, value)
+
 		if !server.isInCluster() {
 			server.snapshotEngine.IncrementChangeCount()
 		}
+
+		if notify {
+			if !exists {
+				server.notifyKeyspaceEvent(ctx, internal.KeyspaceEventsNew, "new", key)
+			}
+			server.notifyKeyspaceEvent(ctx, keyspaceEventClass(value), event, key)
+		}
 	}
 
 	// Asynchronously update the keys in the cache.
@@ -278,17 +371,21 @@ func (server *SugarDB) setValues(ctx context.Context, entries map[string]interfa
 				log.Printf("setValues error: %+v\n", err)
 			}
 		}
-	}(ctx, entries)
+	}(releaseStoreLock(ctx), entries)
 
 	return nil
 }
 
 func (server *SugarDB) setExpiry(ctx context.Context, key string, expireAt time.Time, touch bool) {
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
+	if !storeLocked(ctx) {
+		server.storeLock.Lock()
+		defer server.storeLock.Unlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
+	server.touchWatchedKeys(database, key)
+
 	server.store[database][key] = internal.KeyData{
 		Value:    server.store[database][key].Value,
 		ExpireAt: expireAt,
@@ -308,24 +405,28 @@ func (server *SugarDB) setExpiry(ctx context.Context, key string, expireAt time.
 			if err != nil {
 				log.Printf("setExpiry error: %+v\n", err)
 			}
-		}(ctx, key)
+		}(releaseStoreLock(ctx), key)
 	}
 }
 
 func (server *SugarDB) setHashExpiry(ctx context.Context, key string, field string, expireAt time.Time) error {
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
+	if !storeLocked(ctx) {
+		server.storeLock.Lock()
+		defer server.storeLock.Unlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
-	hashmap, ok := server.store[database][key].Value.(hash.Hash)
+	hashmap, ok := server.store[database][key].Value.(*hash.Hash)
 	if !ok {
 		return fmt.Errorf("setHashExpiry can only be used on keys whose value is a Hash")
 	}
-	hashmap[field] = hash.HashValue{
-		Value:    hashmap[field].Value,
+	server.touchWatchedKeys(database, key)
+
+	hashmap.Set(field, hash.HashValue{
+		Value:    hashmap.Get(field).Value,
 		ExpireAt: expireAt,
-	}
+	})
 
 	server.keysWithExpiry.rwMutex.Lock()
 	if !slices.Contains(server.keysWithExpiry.keys[database], key) {
@@ -337,6 +438,77 @@ func (server *SugarDB) setHashExpiry(ctx context.Context, key string, field stri
 }
 
 func (server *SugarDB) deleteKey(ctx context.Context, key string) error {
+	if err := server.removeKey(ctx, key); err != nil {
+		return err
+	}
+	server.notifyKeyspaceEvent(ctx, internal.KeyspaceEventsGeneric, "del", key)
+	return nil
+}
+
+// replicateDeleteKey deletes the key if it still matches the dump, so that a write made since the dump was taken
+// is not lost. The key is deleted in standalone mode, or through raft when the node is the cluster leader, where
+// the deletion is applied as a transaction that watches the key. Followers cannot delete keys on the other nodes,
+// so they return an error.
+func (server *SugarDB) replicateDeleteKey(ctx context.Context, key string, dump []byte) error {
+	database := ctx.Value("Database").(int)
+	modified := fmt.Errorf("key %s was modified since it was read, it was not deleted", key)
+
+	if !server.isInCluster() {
+		if !storeLocked(ctx) {
+			server.storeLock.Lock()
+			defer server.storeLock.Unlock()
+		}
+		if server.keyFingerprint(database, key) != dumpFingerprint(dump) {
+			return modified
+		}
+		return server.deleteKey(ctx, key)
+	}
+	if !server.raft.IsRaftLeader() {
+		return errors.New("not cluster leader, cannot carry out command")
+	}
+	watched := map[int]map[string]uint64{database: {key: dumpFingerprint(dump)}}
+	res, err := server.raftApplyTransaction(ctx, [][]string{{"DEL", key}}, watched)
+	if err != nil {
+		return err
+	}
+	if string(res) == "*-1\r\n" {
+		return modified
+	}
+	return nil
+}
+
+// expireKey deletes a key whose TTL has expired, or asks the cluster to delete it.
+// If the key is deleted by this node, the expired keyspace event is published.
+func (server *SugarDB) expireKey(ctx context.Context, key string) error {
+	return server.evictKey(ctx, key, internal.KeyspaceEventsExpired, "expired")
+}
+
+// evictKey deletes the key in standalone mode, or through raft when the node is the cluster leader, and publishes
+// the keyspace event. Followers don't delete the key.
+func (server *SugarDB) evictKey(ctx context.Context, key string, class internal.KeyspaceEvents, event string) error {
+	if !server.isInCluster() {
+		if err := server.removeKey(ctx, key); err != nil {
+			return err
+		}
+	} else if server.raft.IsRaftLeader() {
+		if err := server.raftApplyDeleteKey(ctx, key); err != nil {
+			return err
+		}
+	} else {
+		return nil
+	}
+	switch event {
+	case "expired":
+		server.stats.expiredKeys.Add(1)
+	case "evicted":
+		server.stats.evictedKeys.Add(1)
+	}
+	server.notifyKeyspaceEvent(ctx, class, event, key)
+	return nil
+}
+
+// removeKey deletes the key without publishing a keyspace event.
+func (server *SugarDB) removeKey(ctx context.Context, key string) error {
 	database := ctx.Value("Database").(int)
 
 	// Deduct memory usage in tracker.
@@ -351,6 +523,13 @@ func (server *SugarDB) deleteKey(ctx context.Context, key string) error {
 
 	// Delete the key from keyLocks and store.
 	delete(server.store[database], key)
+	server.scanIndex[database].Delete(key)
+
+	// Remove the key from the search indexes.
+	server.search.Remove(database, key)
+
+	// Invalidate the transactions watching the key.
+	server.touchWatchedKeys(database, key)
 
 	// Remove key from slice of keys associated with expiry.
 	server.keysWithExpiry.rwMutex.Lock()
@@ -361,9 +540,9 @@ func (server *SugarDB) deleteKey(ctx context.Context, key string) error {
 
 	// Remove the key from the cache associated with the database.
 	switch {
-	case slices.Contains([]string{constants.AllKeysLFU, constants.VolatileLFU}, server.config.EvictionPolicy):
+	case slices.Contains([]string{constants.AllKeysLFU, constants.VolatileLFU}, server.currentConfig().EvictionPolicy):
 		server.lfuCache.cache[database].Delete(key)
-	case slices.Contains([]string{constants.AllKeysLRU, constants.VolatileLRU}, server.config.EvictionPolicy):
+	case slices.Contains([]string{constants.AllKeysLRU, constants.VolatileLRU}, server.currentConfig().EvictionPolicy):
 		server.lruCache.cache[database].Delete(key)
 	}
 
@@ -375,6 +554,7 @@ func (server *SugarDB) deleteKey(ctx context.Context, key string) error {
 func (server *SugarDB) createDatabase(database int) {
 	// Create database store.
 	server.store[database] = make(map[string]internal.KeyData)
+	server.scanIndex[database] = internal.NewScanIndex[string]()
 
 	// Set volatile keys tracker for database.
 	server.keysWithExpiry.rwMutex.Lock()
@@ -422,12 +602,14 @@ func (server *SugarDB) updateKeysInCache(ctx context.Context, keys []string) (in
 		return touchCounter, nil
 	}
 	// If max memory is 0, there's no max so no need to update caches.
-	if server.config.MaxMemory == 0 {
+	if server.currentConfig().MaxMemory == 0 {
 		return touchCounter, nil
 	}
 
-	server.storeLock.Lock()
-	defer server.storeLock.Unlock()
+	if !storeLocked(ctx) {
+		server.storeLock.Lock()
+		defer server.storeLock.Unlock()
+	}
 
 	for _, key := range keys {
 		// Verify key exists
@@ -437,7 +619,7 @@ func (server *SugarDB) updateKeysInCache(ctx context.Context, keys []string) (in
 
 		touchCounter++
 
-		switch strings.ToLower(server.config.EvictionPolicy) {
+		switch strings.ToLower(server.currentConfig().EvictionPolicy) {
 		case constants.AllKeysLFU:
 			server.lfuCache.cache[database].Mutex.Lock()
 			server.lfuCache.cache[database].Update(key)
@@ -492,8 +674,10 @@ func (server *SugarDB) updateKeysInCache(ctx context.Context, keys []string) (in
 
 // adjustMemoryUsage should only be called from standalone echovault or from raft cluster leader.
 func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
+	conf := server.currentConfig()
+
 	// If max memory is 0, there's no need to adjust memory usage.
-	if server.config.MaxMemory == 0 {
+	if conf.MaxMemory == 0 {
 		return nil
 	}
 
@@ -502,12 +686,12 @@ func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
 	// Check if memory usage is above max-memory.
 	// If it is, pop items from the cache until we get under the limit.
 	// If we're using less memory than the max-memory, there's no need to evict.
-	if uint64(server.memUsed) < server.config.MaxMemory {
+	if uint64(server.memUsed) < conf.MaxMemory {
 		return nil
 	}
 	// Force a garbage collection first before we start evicting keys.
 	runtime.GC()
-	if uint64(server.memUsed) < server.config.MaxMemory {
+	if uint64(server.memUsed) < conf.MaxMemory {
 		return nil
 	}
 
@@ -517,7 +701,7 @@ func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
 
 	log.Printf("Memory used: %v, Max Memory: %v", server.GetServerInfo().MemoryUsed, server.GetServerInfo().MaxMemory)
 	switch {
-	case slices.Contains([]string{constants.AllKeysLFU, constants.VolatileLFU}, strings.ToLower(server.config.EvictionPolicy)):
+	case slices.Contains([]string{constants.AllKeysLFU, constants.VolatileLFU}, strings.ToLower(conf.EvictionPolicy)):
 		// Remove keys from LFU cache until we're below the max memory limit or
 		// until the LFU cache is empty.
 		server.lfuCache.cache[database].Mutex.Lock()
@@ -529,28 +713,19 @@ func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
 			}
 
 			key := heap.Pop(server.lfuCache.cache[database]).(string)
-			if !server.isInCluster() {
-				// If in standalone mode, directly delete the key
-				if err := server.deleteKey(ctx, key); err != nil {
-
-					log.Printf("Evicting key %v from database %v \n", key, database)
-					return fmt.Errorf("adjustMemoryUsage -> LFU cache eviction: %+v", err)
-				}
-			} else if server.isInCluster() && server.raft.IsRaftLeader() {
-				// If in raft cluster, send command to delete key from cluster
-				if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-
-					return fmt.Errorf("adjustMemoryUsage -> LFU cache eviction: %+v", err)
-				}
+			// In standalone mode, directly delete the key.
+			// If in raft cluster and the node is the leader, send command to delete the key from the cluster.
+			if err := server.evictKey(ctx, key, internal.KeyspaceEventsEvicted, "evicted"); err != nil {
+				return fmt.Errorf("adjustMemoryUsage -> LFU cache eviction: %+v", err)
 			}
 			// Run garbage collection
 			runtime.GC()
 			// Return if we're below max memory
-			if uint64(server.memUsed) < server.config.MaxMemory {
+			if uint64(server.memUsed) < conf.MaxMemory {
 				return nil
 			}
 		}
-	case slices.Contains([]string{constants.AllKeysLRU, constants.VolatileLRU}, strings.ToLower(server.config.EvictionPolicy)):
+	case slices.Contains([]string{constants.AllKeysLRU, constants.VolatileLRU}, strings.ToLower(conf.EvictionPolicy)):
 		// Remove keys from th LRU cache until we're below the max memory limit or
 		// until the LRU cache is empty.
 		server.lruCache.cache[database].Mutex.Lock()
@@ -562,28 +737,20 @@ func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
 			}
 
 			key := heap.Pop(server.lruCache.cache[database]).(string)
-			if !server.isInCluster() {
-				// If in standalone mode, directly delete the key.
-				if err := server.deleteKey(ctx, key); err != nil {
-					log.Printf("Evicting key %v from database %v \n", key, database)
-					return fmt.Errorf("adjustMemoryUsage -> LRU cache eviction: %+v", err)
-				}
-			} else if server.isInCluster() && server.raft.IsRaftLeader() {
-				// If in cluster mode and the node is a cluster leader,
-				// send command to delete the key from the cluster.
-				if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-					return fmt.Errorf("adjustMemoryUsage -> LRU cache eviction: %+v", err)
-				}
+			// In standalone mode, directly delete the key.
+			// If in raft cluster and the node is the leader, send command to delete the key from the cluster.
+			if err := server.evictKey(ctx, key, internal.KeyspaceEventsEvicted, "evicted"); err != nil {
+				return fmt.Errorf("adjustMemoryUsage -> LRU cache eviction: %+v", err)
 			}
 
 			// Run garbage collection
 			runtime.GC()
 			// Return if we're below max memory
-			if uint64(server.memUsed) < server.config.MaxMemory {
+			if uint64(server.memUsed) < conf.MaxMemory {
 				return nil
 			}
 		}
-	case slices.Contains([]string{constants.AllKeysRandom}, strings.ToLower(server.config.EvictionPolicy)):
+	case slices.Contains([]string{constants.AllKeysRandom}, strings.ToLower(conf.EvictionPolicy)):
 		// Remove random keys until we're below the max memory limit
 		// or there are no more keys remaining.
 		for {
@@ -598,23 +765,15 @@ func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
 				if db == database {
 					for key, _ := range data {
 						if idx == 0 {
-							if !server.isInCluster() {
-								// If in standalone mode, directly delete the key
-								if err := server.deleteKey(ctx, key); err != nil {
-									log.Printf("Evicting key %v from database %v \n", key, db)
-
-									return fmt.Errorf("adjustMemoryUsage -> all keys random: %+v", err)
-								}
-							} else if server.isInCluster() && server.raft.IsRaftLeader() {
-								if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-
-									return fmt.Errorf("adjustMemoryUsage -> all keys random: %+v", err)
-								}
+							// In standalone mode, directly delete the key.
+							// If in raft cluster and the node is the leader, send command to delete the key from the cluster.
+							if err := server.evictKey(ctx, key, internal.KeyspaceEventsEvicted, "evicted"); err != nil {
+								return fmt.Errorf("adjustMemoryUsage -> all keys random: %+v", err)
 							}
 							// Run garbage collection
 							runtime.GC()
 							// Return if we're below max memory
-							if uint64(server.memUsed) < server.config.MaxMemory {
+							if uint64(server.memUsed) < conf.MaxMemory {
 								return nil
 							}
 						}
@@ -623,7 +782,7 @@ func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
 				}
 			}
 		}
-	case slices.Contains([]string{constants.VolatileRandom}, strings.ToLower(server.config.EvictionPolicy)):
+	case slices.Contains([]string{constants.VolatileRandom}, strings.ToLower(conf.EvictionPolicy)):
 		// Remove random keys with an associated expiry time until we're below the max memory limit
 		// or there are no more keys with expiry time.
 		for {
@@ -633,24 +792,16 @@ func (server *SugarDB) adjustMemoryUsage(ctx context.Context) error {
 			key := server.keysWithExpiry.keys[database][idx]
 			server.keysWithExpiry.rwMutex.RUnlock()
 
-			if !server.isInCluster() {
-				// If in standalone mode, directly delete the key
-				if err := server.deleteKey(ctx, key); err != nil {
-					log.Printf("Evicting key %v from database %v \n", key, database)
-
-					return fmt.Errorf("adjustMemoryUsage -> volatile keys random: %+v", err)
-				}
-			} else if server.isInCluster() && server.raft.IsRaftLeader() {
-				if err := server.raftApplyDeleteKey(ctx, key); err != nil {
-
-					return fmt.Errorf("adjustMemoryUsage -> volatile keys randome: %+v", err)
-				}
+			// In standalone mode, directly delete the key.
+			// If in raft cluster and the node is the leader, send command to delete the key from the cluster.
+			if err := server.evictKey(ctx, key, internal.KeyspaceEventsEvicted, "evicted"); err != nil {
+				return fmt.Errorf("adjustMemoryUsage -> volatile keys random: %+v", err)
 			}
 
 			// Run garbage collection
 			runtime.GC()
 			// Return if we're below max memory
-			if uint64(server.memUsed) < server.config.MaxMemory {
+			if uint64(server.memUsed) < conf.MaxMemory {
 				return nil
 			}
 		}
@@ -676,9 +827,9 @@ func (server *SugarDB) evictKeysWithExpiredTTL(ctx context.Context) error {
 
 	// Sample size should be the configured sample size, or the size of the keys with expiry,
 	// whichever one is smaller.
-	sampleSize := int(server.config.EvictionSample)
+	sampleSize := int(server.currentConfig().EvictionSample)
 	if len(server.keysWithExpiry.keys[database]) < sampleSize {
-		sampleSize = len(server.keysWithExpiry.keys)
+		sampleSize = len(server.keysWithExpiry.keys[database])
 	}
 	keys := make([]string, sampleSize)
 
@@ -690,7 +841,7 @@ func (server *SugarDB) evictKeysWithExpiredTTL(ctx context.Context) error {
 	for i := 0; i < len(keys); i++ {
 		for {
 			// Retry retrieval of a random key until we find a key that is not already in the list of sampled keys.
-			idx = rand.Intn(len(server.keysWithExpiry.keys))
+			idx = rand.Intn(len(server.keysWithExpiry.keys[database]))
 			key = server.keysWithExpiry.keys[database][idx]
 			if !slices.Contains(keys, key) {
 				keys[i] = key
@@ -706,39 +857,32 @@ func (server *SugarDB) evictKeysWithExpiredTTL(ctx context.Context) error {
 	for _, k := range keys {
 
 		// handle keys within a hash type value
-		value := server.store[database][k].Value
-		t := reflect.TypeOf(value)
-		if t.Kind() == reflect.Map {
-
-			hashkey, ok := server.store[database][k].Value.(hash.Hash)
-			if !ok {
-				return fmt.Errorf("Hash value should contain type HashValue, but type %s was found.", t.Elem().Name())
-			}
+		if hashkey, ok := server.store[database][k].Value.(*hash.Hash); ok {
 
-			for k, v := range hashkey {
-				if v.ExpireAt.Before(time.Now()) {
-					delete(hashkey, k)
+			expiredFields := 0
+			for field, v := range hashkey.All() {
+				if v.ExpireAt != (time.Time{}) && v.ExpireAt.Before(time.Now()) {
+					hashkey.Delete(field)
+					expiredFields++
 				}
 			}
+			if expiredFields > 0 {
+				server.search.Update(database, k, hashkey)
+				server.notifyKeyspaceEvent(ctx, internal.KeyspaceEventsHash, "hexpired", k)
+			}
 
 		}
 
 		// Check if key is expired, move on if it's not
 		ExpireTime := server.store[database][k].ExpireAt
-		if ExpireTime.Before(time.Now()) {
+		if ExpireTime == (time.Time{}) || !ExpireTime.Before(time.Now()) {
 			continue
 		}
 
 		// Delete the expired key
 		deletedCount += 1
-		if !server.isInCluster() {
-			if err := server.deleteKey(ctx, k); err != nil {
-				return fmt.Errorf("evictKeysWithExpiredTTL -> standalone delete: %+v", err)
-			}
-		} else if server.isInCluster() && server.raft.IsRaftLeader() {
-			if err := server.raftApplyDeleteKey(ctx, k); err != nil {
-				return fmt.Errorf("evictKeysWithExpiredTTL -> cluster delete: %+v", err)
-			}
+		if err := server.expireKey(ctx, k); err != nil {
+			return fmt.Errorf("evictKeysWithExpiredTTL -> delete: %+v", err)
 		}
 	}
 
@@ -760,8 +904,10 @@ func (server *SugarDB) evictKeysWithExpiredTTL(ctx context.Context) error {
 }
 
 func (server *SugarDB) randomKey(ctx context.Context) string {
-	server.storeLock.RLock()
-	defer server.storeLock.RUnlock()
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
 
 	database := ctx.Value("Database").(int)
 
@@ -788,8 +934,10 @@ func (server *SugarDB) randomKey(ctx context.Context) string {
 }
 
 func (server *SugarDB) dbSize(ctx context.Context) int {
-	server.storeLock.RLock()
-	defer server.storeLock.RUnlock()
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
 
 	database := ctx.Value("Database").(int)
 	return len(server.store[database])
@@ -837,3 +985,54 @@ func (server *SugarDB) getObjectIdleTime(ctx context.Context, key string) (float
 
 	return secs, nil
 }
+
+// setObjectFreq sets the access frequency of the key in the LFU cache.
+// It's ignored when the eviction policy is not a type of LFU.
+func (server *SugarDB) setObjectFreq(ctx context.Context, key string, freq int) {
+	database := ctx.Value("Database").(int)
+
+	switch strings.ToLower(server.currentConfig().EvictionPolicy) {
+	case constants.AllKeysLFU, constants.VolatileLFU:
+		if cache, ok := server.lfuCache.cache[database]; ok {
+			cache.Mutex.Lock()
+			cache.SetCount(key, freq)
+			cache.Mutex.Unlock()
+		}
+	}
+}
+
+// setObjectIdleTime sets the last access time of the key in the LRU cache so that the key has been idle for the
+// given duration. It's ignored when the eviction policy is not a type of LRU.
+func (server *SugarDB) setObjectIdleTime(ctx context.Context, key string, idleTime time.Duration) {
+	database := ctx.Value("Database").(int)
+
+	switch strings.ToLower(server.currentConfig().EvictionPolicy) {
+	case constants.AllKeysLRU, constants.VolatileLRU:
+		if cache, ok := server.lruCache.cache[database]; ok {
+			cache.Mutex.Lock()
+			cache.SetTime(key, time.Now().Add(-idleTime).UnixMilli())
+			cache.Mutex.Unlock()
+		}
+	}
+}
+
+// storeLocked reports whether the store lock is already held for the duration of an
+// atomic execution block (e.g. a script) that the context belongs to.
+// Keyspace functions called within such a block must not try to acquire the lock again.
+func storeLocked(ctx context.Context) bool {
+	locked, _ := ctx.Value(internal.ContextStoreLocked("StoreLocked")).(bool)
+	return locked
+}
+
+// lockStore acquires the store lock for an atomic execution block and returns the context
+// that should be passed to the keyspace functions called within the block.
+func (server *SugarDB) lockStore(ctx context.Context) context.Context {
+	server.storeLock.Lock()
+	return context.WithValue(ctx, internal.ContextStoreLocked("StoreLocked"), true)
+}
+
+// releaseStoreLock returns a context that does not carry the store lock of the atomic
+// block it was derived from. Use this for goroutines that may outlive the block.
+func releaseStoreLock(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internal.ContextStoreLocked("StoreLocked"), false)
+}
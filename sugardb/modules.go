@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/clock"
@@ -39,12 +41,15 @@ func (server *SugarDB) getCommand(cmd string) (internal.Command, error) {
 }
 
 func (server *SugarDB) getHandlerFuncParams(ctx context.Context, cmd []string, conn *net.Conn) internal.HandlerFuncParams {
+	// The command name is used as the keyspace event name for the keys the command writes.
+	ctx = context.WithValue(ctx, internal.ContextCommand("Command"), strings.ToLower(cmd[0]))
 	return internal.HandlerFuncParams{
 		Context:               ctx,
 		Command:               cmd,
 		Connection:            conn,
 		KeysExist:             server.keysExist,
 		GetKeys:               server.getKeys,
+		ScanKeys:              server.scanKeys,
 		GetExpiry:             server.getExpiry,
 		GetHashExpiry:         server.getHashExpiry,
 		GetValues:             server.getValues,
@@ -59,28 +64,69 @@ func (server *SugarDB) getHandlerFuncParams(ctx context.Context, cmd []string, c
 		ListModules:           server.ListModules,
 		GetPubSub:             server.getPubSub,
 		GetACL:                server.getACL,
+		GetSearch:             server.getSearch,
 		GetAllCommands:        server.getCommands,
-		GetClock:              server.getClock,
-		Flush:                 server.Flush,
+		GetClock:              server.getClock(ctx),
 		RandomKey:             server.randomKey,
 		DBSize:                server.dbSize,
 		TouchKey:              server.updateKeysInCache,
 		GetObjectFrequency:    server.getObjectFreq,
 		GetObjectIdleTime:     server.getObjectIdleTime,
-		SwapDBs:               server.SwapDBs,
+		SetObjectFrequency:    server.setObjectFreq,
+		SetObjectIdleTime:     server.setObjectIdleTime,
 		GetServerInfo:         server.GetServerInfo,
+		GetInfo:               server.getInfo,
+		GetConfig:             server.getConfig,
+		SetConfig:             server.setConfig,
+		RewriteConfig:         server.rewriteConfig,
 		AddScript:             server.AddScript,
+		RunScript:             server.runScript,
+		ScriptExists:          server.scriptExists,
+		FlushScripts:          server.flushScripts,
+		StartTransaction:      server.startTransaction,
+		ExecTransaction:       server.execTransaction,
+		DiscardTransaction:    server.discardTransaction,
+		WatchKeys:             server.watchKeys,
+		UnwatchKeys:           server.unwatchKeys,
+		AwaitKeys:             server.awaitKeys,
+		Flush: func(database int) {
+			server.flush(ctx, database)
+		},
+		SwapDBs: func(database1, database2 int) {
+			server.swapDBs(ctx, database1, database2)
+		},
 		DeleteKey: func(ctx context.Context, key string) error {
-			server.storeLock.Lock()
-			defer server.storeLock.Unlock()
+			if !storeLocked(ctx) {
+				server.storeLock.Lock()
+				defer server.storeLock.Unlock()
+			}
 			return server.deleteKey(ctx, key)
 		},
+		ReplicateDeleteKey: server.replicateDeleteKey,
 		GetConnectionInfo: func(conn *net.Conn) internal.ConnectionInfo {
 			server.connInfo.mut.RLock()
 			defer server.connInfo.mut.RUnlock()
 			return server.connInfo.tcpClients[conn]
 		},
+		ListClients:      server.listClients,
+		KillClients:      server.killClients,
+		SetClientName:    server.setClientName,
+		SetClientNoEvict: server.setClientNoEvict,
+		PauseClients:     server.pauseClients,
+		UnpauseClients:   server.unpauseClients,
 		SetConnectionInfo: func(conn *net.Conn, clientname string, protocol int, database int) {
+			// If the database index does not exist, create the new database.
+			// The store lock is released before the connection info lock is acquired.
+			if !storeLocked(ctx) {
+				server.storeLock.Lock()
+			}
+			if server.store[database] == nil {
+				server.createDatabase(database)
+			}
+			if !storeLocked(ctx) {
+				server.storeLock.Unlock()
+			}
+
 			server.connInfo.mut.Lock()
 			defer server.connInfo.mut.Unlock()
 
@@ -94,13 +140,6 @@ func (server *SugarDB) getHandlerFuncParams(ctx context.Context, cmd []string, c
 				info.Name = clientname
 			}
 
-			// If the database index does not exist, create the new database.
-			server.storeLock.Lock()
-			if server.store[database] == nil {
-				server.createDatabase(database)
-			}
-			server.storeLock.Unlock()
-
 			// Set database index for the current connection.
 			info.Database = database
 
@@ -109,23 +148,63 @@ func (server *SugarDB) getHandlerFuncParams(ctx context.Context, cmd []string, c
 	}
 }
 
-func (server *SugarDB) handleCommand(ctx context.Context, message []byte, conn *net.Conn, replay bool, embedded bool) ([]byte, error) {
-	// Prepare context before processing the command.
+// connectionContext adds the connection's name, protocol and database to the context.
+// When embedded is true, the embedded connection's info is used instead of the TCP connection's info.
+func (server *SugarDB) connectionContext(ctx context.Context, conn *net.Conn, embedded bool) context.Context {
 	server.connInfo.mut.RLock()
-	if embedded && !replay {
+	defer server.connInfo.mut.RUnlock()
+	if embedded {
 		// The call is triggered via the embedded API.
 		// Add embedded connection info to the context of the request.
 		ctx = context.WithValue(ctx, "ConnectionName", server.connInfo.embedded.Name)
 		ctx = context.WithValue(ctx, "Protocol", server.connInfo.embedded.Protocol)
 		ctx = context.WithValue(ctx, "Database", server.connInfo.embedded.Database)
-	} else {
-		// The call is triggered by a TCP connection.
-		// Add TCP connection info to the context of the request.
-		ctx = context.WithValue(ctx, "ConnectionName", server.connInfo.tcpClients[conn].Name)
-		ctx = context.WithValue(ctx, "Protocol", server.connInfo.tcpClients[conn].Protocol)
-		ctx = context.WithValue(ctx, "Database", server.connInfo.tcpClients[conn].Database)
+		return ctx
+	}
+	// The call is triggered by a TCP connection.
+	// Add TCP connection info to the context of the request.
+	ctx = context.WithValue(ctx, "ConnectionName", server.connInfo.tcpClients[conn].Name)
+	ctx = context.WithValue(ctx, "Protocol", server.connInfo.tcpClients[conn].Protocol)
+	ctx = context.WithValue(ctx, "Database", server.connInfo.tcpClients[conn].Database)
+	return ctx
+}
+
+// resolveCommand returns the command, the subcommand (if any) and the handler that should process cmd.
+func (server *SugarDB) resolveCommand(cmd []string) (internal.Command, internal.SubCommand, internal.HandlerFunc, error) {
+	command, err := server.getCommand(cmd[0])
+	if err != nil {
+		return internal.Command{}, internal.SubCommand{}, nil, err
+	}
+	handler := command.HandlerFunc
+
+	sc, err := internal.GetSubCommand(command, cmd)
+	if err != nil {
+		return internal.Command{}, internal.SubCommand{}, nil, err
+	}
+	subCommand, ok := sc.(internal.SubCommand)
+	if ok {
+		handler = subCommand.HandlerFunc
+	}
+
+	return command, subCommand, handler, nil
+}
+
+// keyExtractionFunc returns the key extraction function of the subcommand if there is one,
+// otherwise it returns the command's key extraction function.
+func keyExtractionFunc(command internal.Command, subCommand internal.SubCommand) internal.KeyExtractionFunc {
+	if subCommand.KeyExtractionFunc != nil {
+		return subCommand.KeyExtractionFunc
+	}
+	return command.KeyExtractionFunc
+}
+
+func (server *SugarDB) handleCommand(ctx context.Context, message []byte, conn *net.Conn, replay bool, embedded bool) (res []byte, err error) {
+	// Prepare context before processing the command.
+	ctx = server.connectionContext(ctx, conn, embedded && !replay)
+	if replay {
+		// Commands replayed from the AOF must not block.
+		ctx = context.WithValue(ctx, internal.ContextNonBlocking("NonBlocking"), true)
 	}
-	server.connInfo.mut.RUnlock()
 
 	cmd, err := internal.Decode(message)
 	if err != nil {
@@ -141,32 +220,51 @@ func (server *SugarDB) handleCommand(ctx context.Context, message []byte, conn *
 		return nil, io.EOF
 	}
 
-	command, err := server.getCommand(cmd[0])
+	command, subCommand, handler, err := server.resolveCommand(cmd)
 	if err != nil {
+		// If the connection has an open transaction, the transaction can no longer be executed.
+		server.abortTransaction(conn)
 		return nil, err
 	}
 
-	synchronize := command.Sync
-	handler := command.HandlerFunc
-
-	sc, err := internal.GetSubCommand(command, cmd)
-	if err != nil {
-		return nil, err
+	if conn != nil && !embedded && !replay {
+		server.recordClientCommand(conn, command, subCommand)
 	}
-	subCommand, ok := sc.(internal.SubCommand)
-	if ok {
+
+	synchronize := command.Sync
+	if subCommand.Command != "" {
 		synchronize = subCommand.Sync
-		handler = subCommand.HandlerFunc
 	}
 
 	if conn != nil && server.acl != nil && !embedded {
 		// Authorize connection if it's provided and if ACL module is present and the embedded parameter is false.
 		// Skip the authorization if the command is being executed from embedded mode.
 		if err = server.acl.AuthorizeConnection(conn, cmd, command, subCommand); err != nil {
+			server.abortTransaction(conn)
+			server.recordCommandStats(command, subCommand, time.Now(), err, true)
 			return nil, err
 		}
 	}
 
+	// If the connection has an open transaction, queue the command instead of executing it.
+	if !embedded && !replay {
+		queued, err := server.queueCommand(conn, command, subCommand, cmd)
+		if err != nil {
+			return nil, err
+		}
+		if queued {
+			return []byte("+QUEUED\r\n"), nil
+		}
+	}
+
+	// Commands replayed from the AOF are not included in the command statistics.
+	if !replay {
+		start := time.Now()
+		defer func() {
+			server.recordCommandStats(command, subCommand, start, err, false)
+		}()
+	}
+
 	// If the command is a write command, wait for state copy to finish.
 	if internal.IsWriteCommand(command, subCommand) {
 		for {
@@ -178,17 +276,34 @@ func (server *SugarDB) handleCommand(ctx context.Context, message []byte, conn *
 	}
 
 	if !server.isInCluster() || !synchronize {
-		res, err := handler(server.getHandlerFuncParams(ctx, cmd, conn))
+		propagated := &internal.PropagatedCommand{}
+		ctx = context.WithValue(ctx, internal.ContextPropagatedCommand("PropagatedCommand"), propagated)
+		res, err := server.runHandler(ctx, command, subCommand, handler, cmd, conn)
 		if err != nil {
+			// A command that fails part way logs the writes it propagated before the failure.
+			if propagated.Command != nil && internal.IsWriteCommand(command, subCommand) && !replay && !server.isInCluster() {
+				server.connInfo.mut.RLock()
+				server.aofEngine.LogCommand(server.connInfo.tcpClients[conn].Database, internal.EncodeCommand(propagated.Command))
+				server.connInfo.mut.RUnlock()
+			}
 			return nil, err
 		}
 
-		if internal.IsWriteCommand(command, subCommand) && !replay {
+		// In cluster mode, the writes of commands that are not synced are replicated by the command itself.
+		if internal.IsWriteCommand(command, subCommand) && !replay && !server.isInCluster() {
+			if propagated.Command != nil {
+				message = internal.EncodeCommand(propagated.Command)
+			}
 			server.connInfo.mut.RLock()
 			server.aofEngine.LogCommand(server.connInfo.tcpClients[conn].Database, message)
 			server.connInfo.mut.RUnlock()
 		}
 
+		// Wake up blocked clients after the command is logged so that they're logged after it.
+		if internal.IsWriteCommand(command, subCommand) {
+			server.touchWriteKeys(ctx, command, subCommand, cmd)
+		}
+
 		server.stateMutationInProgress.Store(false)
 
 		return res, err
@@ -196,16 +311,11 @@ func (server *SugarDB) handleCommand(ctx context.Context, message []byte, conn *
 
 	// Handle other commands that need to be synced across the cluster
 	if server.raft.IsRaftLeader() {
-		var res []byte
-		res, err = server.raftApplyCommand(ctx, cmd)
-		if err != nil {
-			return nil, err
-		}
-		return res, err
+		return server.raftApplyBlockingCommand(ctx, command, subCommand, cmd)
 	}
 
 	// Forward message to leader and return immediate OK response
-	if server.config.ForwardCommand {
+	if server.currentConfig().ForwardCommand {
 		server.memberList.ForwardDataMutation(ctx, message)
 		return []byte(constants.OkResponse), nil
 	}
@@ -213,6 +323,24 @@ func (server *SugarDB) handleCommand(ctx context.Context, message []byte, conn *
 	return nil, errors.New("not cluster leader, cannot carry out command")
 }
 
+// runHandler executes the command's handler. Blocking commands are executed while holding the store lock.
+func (server *SugarDB) runHandler(ctx context.Context, command internal.Command, subCommand internal.SubCommand,
+	handler internal.HandlerFunc, cmd []string, conn *net.Conn) ([]byte, error) {
+	blocking := slices.Contains(command.Categories, constants.BlockingCategory) ||
+		slices.Contains(subCommand.Categories, constants.BlockingCategory)
+	if blocking && !storeLocked(ctx) {
+		ctx = server.lockBlockingCommand(ctx)
+		defer server.storeLock.Unlock()
+	}
+	return handler(server.getHandlerFuncParams(readCommandContext(ctx, command, subCommand), cmd, conn))
+}
+
+// readCommandContext marks the context of read-only commands so that their key lookups are counted
+// in the keyspace hits and misses reported by the INFO command.
+func readCommandContext(ctx context.Context, command internal.Command, subCommand internal.SubCommand) context.Context {
+	return context.WithValue(ctx, internal.ContextReadCommand("ReadCommand"), !internal.IsWriteCommand(command, subCommand))
+}
+
 func (server *SugarDB) getCommands() []internal.Command {
 	return server.commands
 }
@@ -225,6 +353,18 @@ func (server *SugarDB) getPubSub() interface{} {
 	return server.pubSub
 }
 
-func (server *SugarDB) getClock() clock.Clock {
-	return server.clock
+func (server *SugarDB) getSearch() interface{} {
+	return server.search
+}
+
+// getClock returns the function that gets the clock of the command executing with the context.
+// Commands applied from the raft log use a clock fixed at the time the command was proposed,
+// so that every node produces the same result.
+func (server *SugarDB) getClock(ctx context.Context) func() clock.Clock {
+	return func() clock.Clock {
+		if t, ok := ctx.Value(internal.ContextTime("Time")).(time.Time); ok {
+			return clock.FixedClock{Clock: server.clock, Time: t}
+		}
+		return server.clock
+	}
 }
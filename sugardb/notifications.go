@@ -0,0 +1,139 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/hyperloglog"
+	"github.com/echovault/sugardb/internal/modules/list"
+	"github.com/echovault/sugardb/internal/modules/set"
+	"github.com/echovault/sugardb/internal/modules/sorted_set"
+	"github.com/echovault/sugardb/internal/modules/stream"
+)
+
+// KeyspaceEvent is a change to the keyspace passed to the handler registered with WithKeyspaceEventHandler.
+type KeyspaceEvent struct {
+	Database int    // The database of the key.
+	Key      string // The key that changed.
+	Event    string // The event, e.g. "set", "lpush", "del", "expired" or "evicted".
+}
+
+// keyspaceEventQueue delivers keyspace events to the embedded handler in the order they happened.
+// Events are queued because they're raised while the store lock is held, and the handler must be free to
+// call back into SugarDB.
+type keyspaceEventQueue struct {
+	handler func(event KeyspaceEvent)
+	mut     sync.Mutex
+	cond    *sync.Cond
+	events  []KeyspaceEvent
+	closed  bool
+}
+
+func newKeyspaceEventQueue(handler func(event KeyspaceEvent)) *keyspaceEventQueue {
+	queue := &keyspaceEventQueue{handler: handler}
+	queue.cond = sync.NewCond(&queue.mut)
+	go queue.run()
+	return queue
+}
+
+func (queue *keyspaceEventQueue) push(event KeyspaceEvent) {
+	queue.mut.Lock()
+	defer queue.mut.Unlock()
+	if queue.closed {
+		return
+	}
+	queue.events = append(queue.events, event)
+	queue.cond.Signal()
+}
+
+func (queue *keyspaceEventQueue) close() {
+	queue.mut.Lock()
+	defer queue.mut.Unlock()
+	queue.closed = true
+	queue.cond.Signal()
+}
+
+func (queue *keyspaceEventQueue) run() {
+	for {
+		queue.mut.Lock()
+		for len(queue.events) == 0 && !queue.closed {
+			queue.cond.Wait()
+		}
+		if queue.closed {
+			queue.mut.Unlock()
+			return
+		}
+		events := queue.events
+		queue.events = nil
+		queue.mut.Unlock()
+
+		for _, event := range events {
+			queue.handler(event)
+		}
+	}
+}
+
+// keyspaceEventClass returns the notification class of the events raised when the value is written.
+func keyspaceEventClass(value interface{}) internal.KeyspaceEvents {
+	switch value.(type) {
+	case string, int, int64, float64, *hyperloglog.HyperLogLog:
+		return internal.KeyspaceEventsString
+	case *list.List:
+		return internal.KeyspaceEventsList
+	case *hash.Hash:
+		return internal.KeyspaceEventsHash
+	case *set.Set:
+		return internal.KeyspaceEventsSet
+	case *sorted_set.SortedSet:
+		return internal.KeyspaceEventsSortedSet
+	case *stream.Stream:
+		return internal.KeyspaceEventsStream
+	}
+	return internal.KeyspaceEventsGeneric
+}
+
+// commandEvent returns the name of the command executed with the context, which is used as the event name for
+// the keys it writes. It returns false when the write doesn't come from a command, e.g. when restoring a snapshot.
+func commandEvent(ctx context.Context) (string, bool) {
+	command, ok := ctx.Value(internal.ContextCommand("Command")).(string)
+	return command, ok
+}
+
+// notifyKeyspaceEvent publishes the event to the keyspace and keyevent channels and passes it to the embedded
+// handler, if its class is enabled with the notify-keyspace-events option.
+func (server *SugarDB) notifyKeyspaceEvent(ctx context.Context, class internal.KeyspaceEvents, event string, key string) {
+	events := internal.KeyspaceEvents(server.keyspaceEvents.Load())
+	if events&class == 0 {
+		return
+	}
+
+	database := ctx.Value("Database").(int)
+
+	if events&internal.KeyspaceEventsKeyspace != 0 {
+		server.pubSub.Publish(event, fmt.Sprintf("__keyspace@%d__:%s", database, key))
+	}
+	if events&internal.KeyspaceEventsKeyevent != 0 {
+		server.pubSub.Publish(key, fmt.Sprintf("__keyevent@%d__:%s", database, event))
+	}
+
+	if server.keyspaceEventQueue != nil {
+		server.keyspaceEventQueue.push(KeyspaceEvent{Database: database, Key: key, Event: event})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/clock"
+	"github.com/echovault/sugardb/internal/config"
+	"github.com/echovault/sugardb/internal/constants"
+)
+
+// readMessages reads n messages from the subscription, waiting up to a second for them to be published.
+func readMessages(t *testing.T, reader *MessageReader, n int) [][]string {
+	t.Helper()
+	var messages [][]string
+	deadline := time.Now().Add(time.Second)
+	for len(messages) < n && time.Now().Before(deadline) {
+		p := make([]byte, 1024)
+		if _, err := reader.Read(p); err != nil {
+			time.Sleep(5 * time.Millisecond)
+			continue
+		}
+		var message []string
+		if err := json.Unmarshal(bytes.TrimRight(p, "\x00"), &message); err != nil {
+			t.Fatalf("json unmarshal error: %v", err)
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) != n {
+		t.Fatalf("expected %d messages, got %d: %v", n, len(messages), messages)
+	}
+	return messages
+}
+
+// eventRecorder collects the events passed to the embedded keyspace event handler.
+type eventRecorder struct {
+	mut    sync.Mutex
+	events []KeyspaceEvent
+}
+
+func (recorder *eventRecorder) handle(event KeyspaceEvent) {
+	recorder.mut.Lock()
+	defer recorder.mut.Unlock()
+	recorder.events = append(recorder.events, event)
+}
+
+func (recorder *eventRecorder) wait(t *testing.T, want []KeyspaceEvent) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		recorder.mut.Lock()
+		got := append([]KeyspaceEvent{}, recorder.events...)
+		recorder.mut.Unlock()
+		if len(got) >= len(want) || time.Now().After(deadline) {
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected events %+v, got %+v", want, got)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSugarDB_KeyspaceNotifications(t *testing.T) {
+	t.Run("Test keyspace and keyevent channels", func(t *testing.T) {
+		recorder := &eventRecorder{}
+		server, err := NewSugarDB(
+			WithConfig(config.Config{
+				DataDir:              "",
+				EvictionPolicy:       constants.NoEviction,
+				NotifyKeyspaceEvents: "KEA",
+			}),
+			WithKeyspaceEventHandler(recorder.handle),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			server.ShutDown()
+		})
+
+		keyspace, err := server.Subscribe("keyspace_notifications_keyspace", "__keyspace@0__:key1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyevent, err := server.Subscribe("keyspace_notifications_keyevent",
+			"__keyevent@0__:lpush", "__keyevent@0__:expired")
+		if err != nil {
+			t.Fatal(err)
+		}
+		readMessages(t, keyspace, 1)
+		readMessages(t, keyevent, 2)
+
+		if _, _, err = server.Set("key1", "value1", SETOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = server.LPush("list1", "a", "b"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = server.Del("key1"); err != nil {
+			t.Fatal(err)
+		}
+		// Keys written without a command (e.g. restored from a snapshot) don't raise events.
+		presetKeyData(server, context.Background(), "expiring", internal.KeyData{
+			Value:    "value",
+			ExpireAt: clock.NewClock().Now().Add(-1 * time.Second),
+		})
+		if _, err = server.Get("expiring"); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := readMessages(t, keyspace, 2); !reflect.DeepEqual(got, [][]string{
+			{"message", "__keyspace@0__:key1", "set"},
+			{"message", "__keyspace@0__:key1", "del"},
+		}) {
+			t.Errorf("unexpected keyspace messages %v", got)
+		}
+
+		got := readMessages(t, keyevent, 2)
+		for _, want := range [][]string{
+			{"message", "__keyevent@0__:lpush", "list1"},
+			{"message", "__keyevent@0__:expired", "expiring"},
+		} {
+			if !reflect.DeepEqual(got[0], want) && !reflect.DeepEqual(got[1], want) {
+				t.Errorf("expected keyevent message %v, got %v", want, got)
+			}
+		}
+
+		recorder.wait(t, []KeyspaceEvent{
+			{Database: 0, Key: "key1", Event: "set"},
+			{Database: 0, Key: "list1", Event: "lpush"},
+			{Database: 0, Key: "key1", Event: "del"},
+			{Database: 0, Key: "expiring", Event: "expired"},
+		})
+	})
+
+	t.Run("Test event classes", func(t *testing.T) {
+		recorder := &eventRecorder{}
+		server, err := NewSugarDB(
+			WithConfig(config.Config{
+				DataDir:              "",
+				EvictionPolicy:       constants.NoEviction,
+				NotifyKeyspaceEvents: "Kln",
+			}),
+			WithKeyspaceEventHandler(recorder.handle),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			server.ShutDown()
+		})
+
+		if _, _, err = server.Set("key1", "value1", SETOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = server.LPush("list1", "a"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = server.LPush("list1", "b"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = server.Del("key1", "list1"); err != nil {
+			t.Fatal(err)
+		}
+
+		recorder.wait(t, []KeyspaceEvent{
+			{Database: 0, Key: "key1", Event: "new"},
+			{Database: 0, Key: "list1", Event: "new"},
+			{Database: 0, Key: "list1", Event: "lpush"},
+			{Database: 0, Key: "list1", Event: "lpush"},
+		})
+	})
+
+	t.Run("Test invalid flags", func(t *testing.T) {
+		_, err := NewSugarDB(
+			WithConfig(config.Config{DataDir: "", EvictionPolicy: constants.NoEviction}),
+			WithNotifyKeyspaceEvents("KEq"),
+		)
+		if err == nil {
+			t.Error("expected error for invalid notify-keyspace-events flags")
+		}
+	})
+}
@@ -16,9 +16,11 @@ package sugardb
 
 import (
 	"context"
+	"crypto/sha1"
 	"errors"
 	"fmt"
 	"github.com/echovault/sugardb/internal"
+	lua "github.com/yuin/gopher-lua"
 	"io/fs"
 	"os"
 	"plugin"
@@ -27,10 +29,169 @@ import (
 	"sync"
 )
 
+// adHocScript is a script added with AddScript. It is not associated with a command and
+// is executed using the EVAL or EVALSHA commands.
+type adHocScript struct {
+	engine string      // The interpreter used to execute the script, "lua" or "js".
+	vm     any         // The script's VM, *lua.LState or *otto.Otto.
+	chunk  any         // The compiled script, *lua.LFunction or *otto.Script.
+	lock   *sync.Mutex // Mutex that is locked while the script executes as the VM is not thread safe.
+}
+
+// AddScript adds an ad-hoc script to the script cache. The script is identified by the SHA1
+// digest of its source. Adding a script that is already in the cache is a no-op.
+//
+// Parameters:
+//
+// `engine` - string - The interpreter used to execute the script. Either "LUA" or "JS".
+//
+// `scriptType` - string - Either "FILE" or "RAW".
+//
+// `content` - string - The path to the script file if scriptType is "FILE" and the raw script if scriptType is "RAW".
+//
+// `args` - []string - Not used by ad-hoc scripts. The scripts get their arguments from the ARGV global instead.
 func (server *SugarDB) AddScript(engine string, scriptType string, content string, args []string) error {
+	var source string
+	switch strings.ToUpper(scriptType) {
+	default:
+		return fmt.Errorf("script type %s not supported, expected FILE or RAW", scriptType)
+	case "RAW":
+		source = content
+	case "FILE":
+		b, err := os.ReadFile(content)
+		if err != nil {
+			return fmt.Errorf("could not load script file %s: %v", content, err)
+		}
+		source = string(b)
+	}
+
+	sha := scriptSHA(source)
+	if _, ok := server.scripts.Load(sha); ok {
+		return nil
+	}
+
+	script := &adHocScript{
+		engine: strings.ToLower(engine),
+		lock:   &sync.Mutex{},
+	}
+
+	var err error
+	switch script.engine {
+	default:
+		return fmt.Errorf("engine %s not supported, only [lua js] engines are supported", engine)
+	case "lua":
+		script.vm, script.chunk, err = compileLuaScript(stripShebang(source))
+	case "js":
+		script.vm, script.chunk, err = compileJSScript(stripShebang(source))
+	}
+	if err != nil {
+		return err
+	}
+
+	server.scripts.Store(sha, script)
 	return nil
 }
 
+// runScript executes the script with the given SHA1 digest.
+// The store lock is held for the entire execution so that the script is atomic.
+// The script can only access the keys passed in keys.
+func (server *SugarDB) runScript(ctx context.Context, sha string, keys []string, args []string) (res []byte, err error) {
+	v, ok := server.scripts.Load(strings.ToLower(sha))
+	if !ok {
+		return nil, errors.New("NOSCRIPT No matching script. Please use EVAL")
+	}
+	script := v.(*adHocScript)
+
+	// The store lock is always acquired before the script lock, as a transaction that executes the script
+	// already holds the store lock. The store may already be locked if the script is executed within a
+	// transaction.
+	if !storeLocked(ctx) {
+		ctx = server.lockStore(ctx)
+		defer server.storeLock.Unlock()
+	}
+
+	script.lock.Lock()
+	defer script.lock.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			res, err = nil, fmt.Errorf("script failed: %v", r)
+		}
+		if err != nil {
+			err = scriptError(err)
+		}
+	}()
+
+	if keys == nil {
+		keys = []string{}
+	}
+
+	switch script.engine {
+	default:
+		return nil, fmt.Errorf("engine %s not supported", script.engine)
+	case "lua":
+		return server.luaRunScript(ctx, script, keys, args)
+	case "js":
+		return server.jsRunScript(ctx, script, keys, args)
+	}
+}
+
+// scriptError reduces a script error to its first line. Runtime errors carry a multi-line
+// stack traceback which cannot be written in an error reply.
+func scriptError(err error) error {
+	line, _, _ := strings.Cut(err.Error(), "\n")
+	return errors.New(strings.TrimRight(line, "\r"))
+}
+
+// checkDeclaredKeys returns an error if any of the keys is not one of the declared keys.
+// A nil declared slice allows all keys.
+func checkDeclaredKeys(declared []string, keys []string) error {
+	if declared == nil {
+		return nil
+	}
+	for _, key := range keys {
+		if !slices.Contains(declared, key) {
+			return fmt.Errorf("script accessed key %s that was not declared in KEYS", key)
+		}
+	}
+	return nil
+}
+
+func (server *SugarDB) scriptExists(sha string) bool {
+	_, ok := server.scripts.Load(strings.ToLower(sha))
+	return ok
+}
+
+// flushScripts removes all the scripts from the script cache and closes their VMs.
+func (server *SugarDB) flushScripts() {
+	server.scripts.Range(func(key, value any) bool {
+		server.scripts.Delete(key)
+		script := value.(*adHocScript)
+		script.lock.Lock()
+		if script.engine == "lua" {
+			script.vm.(*lua.LState).Close()
+		}
+		script.lock.Unlock()
+		return true
+	})
+}
+
+// scriptSHA returns the hex encoded SHA1 digest of the script source.
+func scriptSHA(source string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(source)))
+}
+
+// stripShebang removes the "#!<engine>" line from the beginning of the script source if it is present.
+func stripShebang(source string) string {
+	if !strings.HasPrefix(source, "#!") {
+		return source
+	}
+	if i := strings.Index(source, "\n"); i != -1 {
+		return source[i+1:]
+	}
+	return ""
+}
+
 func (server *SugarDB) AddScriptCommand(
 	path string,
 	args []string,
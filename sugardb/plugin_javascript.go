@@ -15,10 +15,13 @@
 package sugardb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/modules/hash"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+	"github.com/echovault/sugardb/internal/modules/list"
 	"github.com/echovault/sugardb/internal/modules/set"
 	"github.com/echovault/sugardb/internal/modules/sorted_set"
 	"github.com/robertkrimen/otto"
@@ -64,17 +67,81 @@ func generateJSCommandInfo(path string) (*otto.Otto, string, []string, string, b
 		return nil, "", nil, "", false, "", fmt.Errorf("could not run javascript script file %s: %v", path, err)
 	}
 
+	registerJSTypes(vm)
+
+	// Get the command name
+	v, err := vm.Get("command")
+	if err != nil {
+		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command %s: %v", path, err)
+	}
+	command, err := v.ToString()
+	if err != nil || len(command) <= 0 {
+		return nil, "", nil, "", false, "", fmt.Errorf("javascript command not found %s: %v", path, err)
+	}
+
+	// Get the categories
+	v, err = vm.Get("categories")
+	if err != nil {
+		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command categories %s: %v", path, err)
+	}
+	isArray, _ := vm.Run(`Array.isArray(categories)`)
+	if ok, _ := isArray.ToBoolean(); !ok {
+		return nil, "", nil, "", false, "", fmt.Errorf("javascript command categories is not an array %s: %v", path, err)
+	}
+	c, _ := v.Export()
+	categories := c.([]string)
+
+	// Get the description
+	v, err = vm.Get("description")
+	if err != nil {
+		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command description %s: %v", path, err)
+	}
+	description, err := v.ToString()
+	if err != nil || len(description) <= 0 {
+		return nil, "", nil, "", false, "", fmt.Errorf("javascript command description not found %s: %v", path, err)
+	}
+
+	// Get the sync policy
+	v, err = vm.Get("sync")
+	if err != nil {
+		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command sync policy %s: %v", path, err)
+	}
+	if !v.IsBoolean() {
+		return nil, "", nil, "", false, "", fmt.Errorf("javascript command sync policy is not a boolean %s: %v", path, err)
+	}
+	synchronize, _ := v.ToBoolean()
+
+	// Set command type
+	commandType := "JS_SCRIPT"
+
+	return vm, strings.ToLower(command), categories, description, synchronize, commandType, nil
+}
+
+// compileJSScript creates a new JS VM with the SugarDB data types registered and compiles
+// the ad-hoc script in it. The script is wrapped in a function so that it can return a value.
+func compileJSScript(source string) (*otto.Otto, *otto.Script, error) {
+	vm := otto.New()
+	registerJSTypes(vm)
+	script, err := vm.Compile("", fmt.Sprintf("(function() {\n%s\n})()", source))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not compile javascript script: %v", err)
+	}
+	return vm, script, nil
+}
+
+// registerJSTypes registers the Hash, Set, ZMember and ZSet data types in the JS VM.
+func registerJSTypes(vm *otto.Otto) {
 	// Register hash data type
 	_ = vm.Set("Hash", func(call otto.FunctionCall) otto.Value {
 		// Initialize hash
-		h := hash.Hash{}
+		h := hash.NewHash(nil)
 		// If an object is passed then initialize the default values of the hash
 		if len(call.ArgumentList) > 0 {
 			args := call.Argument(0).Object()
 			for _, key := range args.Keys() {
 				value, _ := args.Get(key)
 				v, _ := value.ToString()
-				h[key] = hash.HashValue{Value: v}
+				h.Set(key, hash.HashValue{Value: v})
 			}
 		}
 
@@ -104,6 +171,22 @@ func generateJSCommandInfo(path string) (*otto.Otto, string, []string, string, b
 		return obj.Value()
 	})
 
+	// Register JSON document data type
+	_ = vm.Set("JSONDoc", func(call otto.FunctionCall) otto.Value {
+		// Parse the JSON text or convert the value if one is passed, otherwise create an empty object.
+		var root interface{} = jsondoc.NewObject()
+		if len(call.ArgumentList) > 0 {
+			var err error
+			if root, err = jsValueToJSONValue(call, call.Argument(0), true); err != nil {
+				panicWithFunctionCall(call, err.Error())
+			}
+		}
+
+		obj, _ := call.Otto.Object(`({})`)
+		buildJSONObject(obj, jsondoc.NewDocument(root))
+		return obj.Value()
+	})
+
 	// Register sorted set member data type
 	_ = vm.Set("ZMember", func(call otto.FunctionCall) otto.Value {
 		obj, _ := call.Otto.Object(`({})`)
@@ -114,7 +197,7 @@ func generateJSCommandInfo(path string) (*otto.Otto, string, []string, string, b
 		}
 		arg := call.Argument(0).Object()
 		// Validate the object
-		if err = validateMemberParamObject(arg); err != nil {
+		if err := validateMemberParamObject(arg); err != nil {
 			panicWithFunctionCall(call, err.Error())
 		}
 		// Get the value
@@ -154,53 +237,6 @@ func generateJSCommandInfo(path string) (*otto.Otto, string, []string, string, b
 		buildSortedSetObject(obj, ss)
 		return obj.Value()
 	})
-
-	// Get the command name
-	v, err := vm.Get("command")
-	if err != nil {
-		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command %s: %v", path, err)
-	}
-	command, err := v.ToString()
-	if err != nil || len(command) <= 0 {
-		return nil, "", nil, "", false, "", fmt.Errorf("javascript command not found %s: %v", path, err)
-	}
-
-	// Get the categories
-	v, err = vm.Get("categories")
-	if err != nil {
-		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command categories %s: %v", path, err)
-	}
-	isArray, _ := vm.Run(`Array.isArray(categories)`)
-	if ok, _ := isArray.ToBoolean(); !ok {
-		return nil, "", nil, "", false, "", fmt.Errorf("javascript command categories is not an array %s: %v", path, err)
-	}
-	c, _ := v.Export()
-	categories := c.([]string)
-
-	// Get the description
-	v, err = vm.Get("description")
-	if err != nil {
-		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command description %s: %v", path, err)
-	}
-	description, err := v.ToString()
-	if err != nil || len(description) <= 0 {
-		return nil, "", nil, "", false, "", fmt.Errorf("javascript command description not found %s: %v", path, err)
-	}
-
-	// Get the sync policy
-	v, err = vm.Get("sync")
-	if err != nil {
-		return nil, "", nil, "", false, "", fmt.Errorf("could not get javascript command sync policy %s: %v", path, err)
-	}
-	if !v.IsBoolean() {
-		return nil, "", nil, "", false, "", fmt.Errorf("javascript command sync policy is not a boolean %s: %v", path, err)
-	}
-	synchronize, _ := v.ToBoolean()
-
-	// Set command type
-	commandType := "JS_SCRIPT"
-
-	return vm, strings.ToLower(command), categories, description, synchronize, commandType, nil
 }
 
 // jsKeyExtractionFunc executes the extraction function defined in the script and returns the result or error.
@@ -261,6 +297,13 @@ func (server *SugarDB) jsKeyExtractionFunc(cmd []string, args []string) (interna
 
 // jsHandlerFunc executes the extraction function defined in the script nad returns the RESP response or error.
 func (server *SugarDB) jsHandlerFunc(command string, args []string, params internal.HandlerFuncParams) ([]byte, error) {
+	// The store lock is acquired before the script's lock, in the same order as a transaction that executes
+	// the command. The store may already be locked if the command is executed within a transaction.
+	if !storeLocked(params.Context) {
+		params.Context = server.lockStore(params.Context)
+		defer server.storeLock.Unlock()
+	}
+
 	// Lock the script before executing the key extraction function.
 	script, ok := server.scriptVMs.Load(strings.ToLower(command))
 	if !ok {
@@ -293,112 +336,223 @@ func (server *SugarDB) jsHandlerFunc(command string, args []string, params inter
 		// Command
 		params.Command,
 
-		// Build keysExist function
-		func(keys []string) otto.Value {
-			obj, _ := vm.Object(`({})`)
-			exists := server.keysExist(params.Context, keys)
-			for key, value := range exists {
+		// Functions to check if keys exist, get values from keys and set values on keys
+		server.jsKeysExistFunc(vm, params.Context, nil),
+		server.jsGetValuesFunc(vm, params.Context, nil),
+		server.jsSetValuesFunc(params.Context, command, nil),
+
+		// Args
+		args,
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := v.ToString()
+
+	clearObjectRegistry()
+
+	return []byte(res), err
+}
+
+// jsKeysExistFunc returns the function that checks if keys exist in the store.
+// If declared is not nil, the function throws when a key is not in declared.
+func (server *SugarDB) jsKeysExistFunc(vm *otto.Otto, ctx context.Context, declared []string) func(keys []string) otto.Value {
+	return func(keys []string) otto.Value {
+		if err := checkDeclaredKeys(declared, keys); err != nil {
+			panicInHandler(err.Error())
+		}
+		obj, _ := vm.Object(`({})`)
+		exists := server.keysExist(ctx, keys)
+		for key, value := range exists {
+			_ = obj.Set(key, value)
+		}
+		return obj.Value()
+	}
+}
+
+// jsGetValuesFunc returns the function that gets values from keys.
+// If declared is not nil, the function throws when a key is not in declared.
+func (server *SugarDB) jsGetValuesFunc(vm *otto.Otto, ctx context.Context, declared []string) func(keys []string) otto.Value {
+	return func(keys []string) otto.Value {
+		if err := checkDeclaredKeys(declared, keys); err != nil {
+			panicInHandler(err.Error())
+		}
+		obj, _ := vm.Object(`({})`)
+		values := server.getValues(ctx, keys)
+		for key, value := range values {
+			switch value.(type) {
+			default:
+				// Types that have no JS representation are returned as null.
+				_ = obj.Set(key, otto.NullValue())
+			case string, int, int64, float32, float64:
 				_ = obj.Set(key, value)
+			case *list.List:
+				l, _ := vm.Object(`([])`)
+				for i, elem := range value.(*list.List).Elements() {
+					_ = l.Set(fmt.Sprintf("%d", i), elem)
+				}
+				_ = obj.Set(key, l.Value())
+			case *hash.Hash:
+				h, _ := vm.Object(`({})`)
+				buildHashObject(h, value.(*hash.Hash))
+				_ = obj.Set(key, h.Value())
+			case *set.Set:
+				s, _ := vm.Object(`({})`)
+				buildSetObject(s, value.(*set.Set))
+				_ = obj.Set(key, s.Value())
+			case *sorted_set.SortedSet:
+				ss, _ := vm.Object(`({})`)
+				buildSortedSetObject(ss, value.(*sorted_set.SortedSet))
+				_ = obj.Set(key, ss.Value())
+			case *jsondoc.Document:
+				doc, _ := vm.Object(`({})`)
+				buildJSONObject(doc, value.(*jsondoc.Document))
+				_ = obj.Set(key, doc.Value())
 			}
-			return obj.Value()
-		},
+		}
+		return obj.Value()
+	}
+}
 
-		// Build getValues function
-		func(keys []string) otto.Value {
-			obj, _ := vm.Object(`({})`)
-			values := server.getValues(params.Context, keys)
-			for key, value := range values {
-				switch value.(type) {
+// jsSetValuesFunc returns the function that sets values on keys.
+// If declared is not nil, the function throws when a key is not in declared.
+func (server *SugarDB) jsSetValuesFunc(ctx context.Context, command string, declared []string) func(entries map[string]interface{}) {
+	return func(entries map[string]interface{}) {
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		if err := checkDeclaredKeys(declared, keys); err != nil {
+			panicInHandler(err.Error())
+		}
+		values := make(map[string]interface{})
+		for key, entry := range entries {
+			switch entry.(type) {
+			default:
+				panicInHandler(fmt.Sprintf("unknown type %s on key %s", reflect.TypeOf(entry).String(), key))
+			case nil:
+				values[key] = nil
+			case string:
+				values[key] = internal.AdaptType(entry.(string))
+			case int64:
+				values[key] = int(entry.(int64))
+			case float64:
+				values[key] = entry.(float64)
+			case []string:
+				values[key] = list.NewList(entry.([]string)...)
+			case map[string]interface{}:
+				value, ok := entry.(map[string]interface{})
+				if !ok || value["__id"] == nil {
+					panicInHandler(fmt.Sprintf("unknown object on key %s", key))
+				}
+				obj, exists := getObjectById(value["__id"].(string))
+				if !exists {
+					panicInHandler(
+						fmt.Sprintf(
+							"could not find object of id %s in the object registry on key %s",
+							value["__id"].(string),
+							key,
+						),
+					)
+				}
+				switch obj.(type) {
 				default:
-					_ = obj.Set(key, value)
-				case nil:
-					_ = obj.Set(key, otto.NullValue())
-				case []string:
-					l, _ := vm.Object(`([])`)
-					for i, elem := range value.([]string) {
-						_ = l.Set(fmt.Sprintf("%d", i), elem)
-					}
-					_ = obj.Set(key, l.Value())
-				case hash.Hash:
-					h, _ := vm.Object(`({})`)
-					buildHashObject(h, value.(hash.Hash))
-					_ = obj.Set(key, h.Value())
+					panicInHandler(fmt.Sprintf("unknown type on key %s for command %s\n", key, command))
+				case *hash.Hash:
+					values[key] = obj.(*hash.Hash)
 				case *set.Set:
-					s, _ := vm.Object(`({})`)
-					buildSetObject(s, value.(*set.Set))
-					_ = obj.Set(key, s.Value())
+					values[key] = obj.(*set.Set)
 				case *sorted_set.SortedSet:
-					ss, _ := vm.Object(`({})`)
-					buildSortedSetObject(ss, value.(*sorted_set.SortedSet))
-					_ = obj.Set(key, ss.Value())
+					values[key] = obj.(*sorted_set.SortedSet)
+				case *jsondoc.Document:
+					values[key] = obj.(*jsondoc.Document)
 				}
 			}
-			return obj.Value()
-		},
+		}
+		if err := server.setValues(ctx, values); err != nil {
+			panicInHandler(err.Error())
+		}
+	}
+}
 
-		// Build setValues function
-		func(entries map[string]interface{}) {
-			values := make(map[string]interface{})
-			for key, entry := range entries {
-				switch entry.(type) {
-				default:
-					panicInHandler(fmt.Sprintf("unknown type %s on key %s", reflect.TypeOf(entry).String(), key))
-				case nil:
-					values[key] = nil
-				case string:
-					values[key] = internal.AdaptType(entry.(string))
-				case int64:
-					values[key] = int(entry.(int64))
-				case float64:
-					values[key] = entry.(float64)
-				case []string:
-					values[key] = entry.([]string)
-				case map[string]interface{}:
-					value, ok := entry.(map[string]interface{})
-					if !ok || value["__id"] == nil {
-						panicInHandler(fmt.Sprintf("unknown object on key %s", key))
-					}
-					obj, exists := getObjectById(value["__id"].(string))
-					if !exists {
-						panicInHandler(
-							fmt.Sprintf(
-								"could not find object of id %s in the object registry on key %s",
-								value["__id"].(string),
-								key,
-							),
-						)
-					}
-					switch obj.(type) {
-					default:
-						panicInHandler(fmt.Sprintf("unknown type on key %s for command %s\n", key, command))
-					case hash.Hash:
-						values[key] = obj.(hash.Hash)
-					case *set.Set:
-						values[key] = obj.(*set.Set)
-					case *sorted_set.SortedSet:
-						values[key] = obj.(*sorted_set.SortedSet)
-					}
-				}
-			}
-			if err := server.setValues(params.Context, values); err != nil {
-				panicInHandler(err.Error())
-			}
-		},
+// jsRunScript executes an ad-hoc JS script added with AddScript.
+// The keys and args are exposed to the script as the KEYS and ARGV global arrays.
+// The script's return value is converted to a RESP response.
+func (server *SugarDB) jsRunScript(ctx context.Context, script *adHocScript, keys []string, args []string) ([]byte, error) {
+	vm := script.vm.(*otto.Otto)
 
-		// Args
-		args,
-	)
+	if keys == nil {
+		keys = []string{}
+	}
+	if args == nil {
+		args = []string{}
+	}
+	_ = vm.Set("KEYS", keys)
+	_ = vm.Set("ARGV", args)
+
+	// Functions to interact with the store
+	// Functions to interact with the store. They can only access the keys declared in KEYS.
+	_ = vm.Set("keysExist", server.jsKeysExistFunc(vm, ctx, keys))
+	_ = vm.Set("getValues", server.jsGetValuesFunc(vm, ctx, keys))
+	_ = vm.Set("setValues", server.jsSetValuesFunc(ctx, "EVAL", keys))
+
+	defer clearObjectRegistry()
+
+	v, err := vm.Run(script.chunk.(*otto.Script))
 	if err != nil {
 		return nil, err
 	}
-	res, err := v.ToString()
 
-	clearObjectRegistry()
+	return jsValueToRESP(v)
+}
 
-	return []byte(res), err
+// jsValueToRESP converts the value returned by an ad-hoc JS script into a RESP response.
+// Numbers are truncated to integers and false is returned as a nil reply.
+// An object with an "err" property is returned as an error and an object with an "ok" property as a simple string.
+func jsValueToRESP(value otto.Value) ([]byte, error) {
+	switch {
+	case value.IsUndefined(), value.IsNull():
+		return []byte("$-1\r\n"), nil
+	case value.IsBoolean():
+		if b, _ := value.ToBoolean(); b {
+			return []byte(":1\r\n"), nil
+		}
+		return []byte("$-1\r\n"), nil
+	case value.IsNumber():
+		n, _ := value.ToFloat()
+		return []byte(fmt.Sprintf(":%d\r\n", int64(n))), nil
+	case value.IsString():
+		str, _ := value.ToString()
+		return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(str), str)), nil
+	case value.IsObject():
+		obj := value.Object()
+		if obj.Class() == "Array" {
+			l, _ := obj.Get("length")
+			length, _ := l.ToInteger()
+			res := []byte(fmt.Sprintf("*%d\r\n", length))
+			for i := int64(0); i < length; i++ {
+				elem, _ := obj.Get(fmt.Sprintf("%d", i))
+				b, err := jsValueToRESP(elem)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, b...)
+			}
+			return res, nil
+		}
+		if e, _ := obj.Get("err"); e.IsDefined() {
+			return nil, errors.New(e.String())
+		}
+		if ok, _ := obj.Get("ok"); ok.IsDefined() {
+			return []byte(fmt.Sprintf("+%s\r\n", ok.String())), nil
+		}
+		return nil, fmt.Errorf("cannot convert javascript %s to a response", obj.Class())
+	default:
+		return nil, fmt.Errorf("cannot convert javascript value %s to a response", value.String())
+	}
 }
 
-func buildHashObject(obj *otto.Object, h hash.Hash) {
+func buildHashObject(obj *otto.Object, h *hash.Hash) {
 	_ = obj.Set("__type", "hash")
 	_ = obj.Set("__id", registerObject(h))
 	_ = obj.Set("set", func(call otto.FunctionCall) otto.Value {
@@ -406,7 +560,7 @@ func buildHashObject(obj *otto.Object, h hash.Hash) {
 		for _, key := range args.Keys() {
 			value, _ := args.Get(key)
 			v, _ := value.ToString()
-			h[key] = hash.HashValue{Value: v}
+			h.Set(key, hash.HashValue{Value: v})
 		}
 		// Return changed count using the set data type
 		count, _ := otto.ToValue(set.NewSet(args.Keys()).Cardinality())
@@ -416,13 +570,13 @@ func buildHashObject(obj *otto.Object, h hash.Hash) {
 		count := 0
 		args := call.Argument(0).Object()
 		for _, key := range args.Keys() {
-			if _, exists := h[key]; exists {
+			if h.Contains(key) {
 				continue
 			}
 			count += 1
 			value, _ := args.Get(key)
 			v, _ := value.ToString()
-			h[key] = hash.HashValue{Value: v}
+			h.Set(key, hash.HashValue{Value: v})
 		}
 		c, _ := otto.ToValue(count)
 		return c
@@ -431,18 +585,18 @@ func buildHashObject(obj *otto.Object, h hash.Hash) {
 		result, _ := call.Otto.Object(`({})`)
 		for _, arg := range call.ArgumentList {
 			key, _ := arg.ToString()
-			value, _ := otto.ToValue(h[key].Value)
+			value, _ := otto.ToValue(h.Get(key).Value)
 			_ = result.Set(key, value)
 		}
 		return result.Value()
 	})
 	_ = obj.Set("len", func(call otto.FunctionCall) otto.Value {
-		length, _ := otto.ToValue(len(h))
+		length, _ := otto.ToValue(h.Len())
 		return length
 	})
 	_ = obj.Set("all", func(call otto.FunctionCall) otto.Value {
 		result, _ := call.Otto.Object(`({})`)
-		for key, value := range h {
+		for key, value := range h.All() {
 			v, _ := otto.ToValue(value.Value)
 			_ = result.Set(key, v)
 		}
@@ -452,8 +606,7 @@ func buildHashObject(obj *otto.Object, h hash.Hash) {
 		result, _ := call.Otto.Object(`({})`)
 		for _, arg := range call.ArgumentList {
 			key, _ := arg.ToString()
-			_, ok := h[key]
-			exists, _ := call.Otto.ToValue(ok)
+			exists, _ := call.Otto.ToValue(h.Contains(key))
 			_ = result.Set(key, exists)
 		}
 		return result.Value()
@@ -462,9 +615,8 @@ func buildHashObject(obj *otto.Object, h hash.Hash) {
 		count := 0
 		for _, arg := range call.ArgumentList {
 			key, _ := arg.ToString()
-			if _, exists := h[key]; exists {
+			if h.Delete(key) {
 				count += 1
-				delete(h, key)
 			}
 		}
 		result, _ := otto.ToValue(count)
@@ -897,6 +1049,97 @@ func buildSortedSetObject(obj *otto.Object, ss *sorted_set.SortedSet) {
 	})
 }
 
+func buildJSONObject(obj *otto.Object, doc *jsondoc.Document) {
+	_ = obj.Set("__type", "json")
+	_ = obj.Set("__id", registerObject(doc))
+	_ = obj.Set("get", func(call otto.FunctionCall) otto.Value {
+		// JSONPath queries return an array of all the matches, and legacy paths return the first match.
+		path := jsJSONPath(call, 0)
+		values := doc.Get(path)
+		if !path.IsLegacy() {
+			return jsonValueToJSValue(call, jsondoc.NewArray(values...))
+		}
+		if len(values) == 0 {
+			return otto.UndefinedValue()
+		}
+		return jsonValueToJSValue(call, values[0])
+	})
+	_ = obj.Set("set", func(call otto.FunctionCall) otto.Value {
+		path := jsJSONPath(call, 0)
+		value, err := jsValueToJSONValue(call, call.Argument(1), false)
+		if err != nil {
+			panicWithFunctionCall(call, err.Error())
+		}
+		count, _ := otto.ToValue(doc.Set(path, value))
+		return count
+	})
+	_ = obj.Set("del", func(call otto.FunctionCall) otto.Value {
+		count, _ := otto.ToValue(doc.Delete(jsJSONPath(call, 0)))
+		return count
+	})
+	_ = obj.Set("type", func(call otto.FunctionCall) otto.Value {
+		values := doc.Get(jsJSONPath(call, 0))
+		if len(values) == 0 {
+			return otto.UndefinedValue()
+		}
+		result, _ := otto.ToValue(jsondoc.TypeName(values[0]))
+		return result
+	})
+	_ = obj.Set("toString", func(call otto.FunctionCall) otto.Value {
+		result, _ := otto.ToValue(doc.String())
+		return result
+	})
+}
+
+// jsJSONPath parses the path passed as the argument at index i. The path defaults to the root.
+func jsJSONPath(call otto.FunctionCall, i int) jsondoc.Path {
+	raw := "$"
+	if arg := call.Argument(i); arg.IsDefined() {
+		raw, _ = arg.ToString()
+	}
+	path, err := jsondoc.ParsePath(raw)
+	if err != nil {
+		panicWithFunctionCall(call, err.Error())
+	}
+	return path
+}
+
+// jsValueToJSONValue converts a JS value to a JSON value through its JSON text. If parseStrings is true,
+// strings are parsed as JSON text instead of being converted to JSON strings.
+func jsValueToJSONValue(call otto.FunctionCall, value otto.Value, parseStrings bool) (interface{}, error) {
+	if value.IsString() && parseStrings {
+		text, _ := value.ToString()
+		return jsondoc.Parse([]byte(text))
+	}
+	if value.IsObject() {
+		// JSON documents are copied, so that the copy can be changed independently.
+		if id, _ := value.Object().Get("__id"); id.IsString() {
+			if doc, ok := getObjectById(id.String()); ok {
+				if doc, ok := doc.(*jsondoc.Document); ok {
+					return jsondoc.Parse([]byte(doc.String()))
+				}
+			}
+		}
+	}
+	if value.IsUndefined() {
+		return nil, errors.New("cannot convert undefined to JSON")
+	}
+	text, err := call.Otto.Call("JSON.stringify", nil, value)
+	if err != nil {
+		return nil, err
+	}
+	return jsondoc.Parse([]byte(text.String()))
+}
+
+// jsonValueToJSValue converts a JSON value to a JS value through its JSON text.
+func jsonValueToJSValue(call otto.FunctionCall, value interface{}) otto.Value {
+	result, err := call.Otto.Call("JSON.parse", nil, string(jsondoc.Marshal(value, jsondoc.Format{})))
+	if err != nil {
+		panicWithFunctionCall(call, err.Error())
+	}
+	return result
+}
+
 func panicWithFunctionCall(call otto.FunctionCall, message string) {
 	err, _ := call.Otto.ToValue(message)
 	panic(err)
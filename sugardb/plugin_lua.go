@@ -15,13 +15,18 @@
 package sugardb
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/echovault/sugardb/internal"
 	"github.com/echovault/sugardb/internal/modules/hash"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
+	"github.com/echovault/sugardb/internal/modules/list"
 	"github.com/echovault/sugardb/internal/modules/set"
 	"github.com/echovault/sugardb/internal/modules/sorted_set"
 	lua "github.com/yuin/gopher-lua"
+	"math"
 	"strings"
 	"sync"
 )
@@ -34,13 +39,61 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 		return nil, "", nil, "", false, "", fmt.Errorf("could not load lua script file %s: %v", path, err)
 	}
 
+	registerLuaTypes(L)
+
+	// Get the command name
+	cn := L.GetGlobal("command")
+	if _, ok := cn.(lua.LString); !ok {
+		return nil, "", nil, "", false, "", errors.New("command name does not exist or is not a string")
+	}
+
+	// Get the categories
+	c := L.GetGlobal("categories")
+	var categories []string
+	if _, ok := c.(*lua.LTable); !ok {
+		return nil, "", nil, "", false, "", errors.New("categories does not exist or is not an array")
+	}
+	for i := 0; i < c.(*lua.LTable).Len(); i++ {
+		categories = append(categories, c.(*lua.LTable).RawGetInt(i+1).String())
+	}
+
+	// Get the description
+	d := L.GetGlobal("description")
+	if _, ok := d.(lua.LString); !ok {
+		return nil, "", nil, "", false, "", errors.New("description does not exist or is not a string")
+	}
+
+	// Get the sync
+	synchronize := L.GetGlobal("sync") == lua.LTrue
+
+	// Set command type
+	commandType := "LUA_SCRIPT"
+
+	return L, strings.ToLower(cn.String()), categories, d.String(), synchronize, commandType, nil
+}
+
+// compileLuaScript creates a new Lua VM with the SugarDB data types registered and compiles
+// the ad-hoc script in it.
+func compileLuaScript(source string) (*lua.LState, *lua.LFunction, error) {
+	L := lua.NewState()
+	registerLuaTypes(L)
+	fn, err := L.LoadString(source)
+	if err != nil {
+		L.Close()
+		return nil, nil, fmt.Errorf("could not compile lua script: %v", err)
+	}
+	return L, fn, nil
+}
+
+// registerLuaTypes registers the hash, set, zmember and zset data types in the Lua VM.
+func registerLuaTypes(L *lua.LState) {
 	// Register hash data type
 	hashMetaTable := L.NewTypeMetatable("hash")
 	L.SetGlobal("hash", hashMetaTable)
 	// Static methods
 	L.SetField(hashMetaTable, "new", L.NewFunction(func(state *lua.LState) int {
 		ud := state.NewUserData()
-		ud.Value = hash.Hash{}
+		ud.Value = hash.NewHash(nil)
 		state.SetMetatable(ud, state.GetTypeMetatable("hash"))
 		state.Push(ud)
 		return 1
@@ -71,12 +124,12 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 						state.ArgError(2, err.Error())
 						return
 					}
-					if _, exists := h[field.String()]; !exists {
-						h[field.String()] = hash.HashValue{Value: v}
+					if !h.Contains(field.String()) {
+						h.Set(field.String(), hash.HashValue{Value: v})
 					} else {
-						hashValue := h[field.String()]
+						hashValue := h.Get(field.String())
 						hashValue.Value = v
-						h[field.String()] = hashValue
+						h.Set(field.String(), hashValue)
 					}
 					count += 1
 				})
@@ -107,8 +160,8 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 						return
 					}
 					// If the field does not exist, add it.
-					if _, exists := h[field.String()]; !exists {
-						h[field.String()] = hash.HashValue{Value: v}
+					if !h.Contains(field.String()) {
+						h.Set(field.String(), hash.HashValue{Value: v})
 						count += 1
 					}
 				})
@@ -131,8 +184,8 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 					return
 				}
 				var value lua.LValue
-				if _, exists := h[field.String()]; exists {
-					value = nativeTypeToLuaType(state, h[field.String()].Value)
+				if h.Contains(field.String()) {
+					value = nativeTypeToLuaType(state, h.Get(field.String()).Value)
 				} else {
 					value = lua.LNil
 				}
@@ -144,14 +197,14 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 		"len": func(state *lua.LState) int {
 			// Implement method len, returns the length of the hash
 			h := checkHash(state, 1)
-			state.Push(lua.LNumber(len(h)))
+			state.Push(lua.LNumber(h.Len()))
 			return 1
 		},
 		"all": func(state *lua.LState) int {
 			// Implement method all, returns all key/value pairs in the hash
 			h := checkHash(state, 1)
 			result := state.NewTable()
-			for field, hashValue := range h {
+			for field, hashValue := range h.All() {
 				result.RawSetString(field, lua.LString(hashValue.Value.(string)))
 			}
 			state.Push(result)
@@ -171,8 +224,7 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 					state.ArgError(2, "expected field to be a string")
 					return
 				}
-				_, exists := h[field.String()]
-				result.RawSet(field, lua.LBool(exists))
+				result.RawSet(field, lua.LBool(h.Contains(field.String())))
 			})
 			state.Push(result)
 			return 1
@@ -191,8 +243,7 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 					state.ArgError(2, "expected field value to be a string")
 					return
 				}
-				if _, exists := h[field.String()]; exists {
-					delete(h, field.String())
+				if h.Delete(field.String()) {
 					count += 1
 				}
 			})
@@ -607,35 +658,77 @@ func generateLuaCommandInfo(path string) (*lua.LState, string, []string, string,
 		},
 	}))
 
-	// Get the command name
-	cn := L.GetGlobal("command")
-	if _, ok := cn.(lua.LString); !ok {
-		return nil, "", nil, "", false, "", errors.New("command name does not exist or is not a string")
-	}
-
-	// Get the categories
-	c := L.GetGlobal("categories")
-	var categories []string
-	if _, ok := c.(*lua.LTable); !ok {
-		return nil, "", nil, "", false, "", errors.New("categories does not exist or is not an array")
-	}
-	for i := 0; i < c.(*lua.LTable).Len(); i++ {
-		categories = append(categories, c.(*lua.LTable).RawGetInt(i+1).String())
-	}
-
-	// Get the description
-	d := L.GetGlobal("description")
-	if _, ok := d.(lua.LString); !ok {
-		return nil, "", nil, "", false, "", errors.New("description does not exist or is not a string")
-	}
-
-	// Get the sync
-	synchronize := L.GetGlobal("sync") == lua.LTrue
-
-	// Set command type
-	commandType := "LUA_SCRIPT"
-
-	return L, strings.ToLower(cn.String()), categories, d.String(), synchronize, commandType, nil
+	// Register JSON document data type
+	jsonMetaTable := L.NewTypeMetatable("json")
+	L.SetGlobal("json", jsonMetaTable)
+	// Static methods
+	L.SetField(jsonMetaTable, "new", L.NewFunction(func(state *lua.LState) int {
+		// Parse the JSON text if it's passed, otherwise create an empty object.
+		var root interface{} = jsondoc.NewObject()
+		if state.GetTop() == 1 {
+			var err error
+			if root, err = jsondoc.Parse([]byte(state.CheckString(1))); err != nil {
+				state.ArgError(1, err.Error())
+			}
+		}
+		ud := state.NewUserData()
+		ud.Value = jsondoc.NewDocument(root)
+		state.SetMetatable(ud, state.GetTypeMetatable("json"))
+		state.Push(ud)
+		return 1
+	}))
+	// JSON document methods
+	L.SetField(jsonMetaTable, "__tostring", L.NewFunction(func(state *lua.LState) int {
+		state.Push(lua.LString(checkJSON(state, 1).String()))
+		return 1
+	}))
+	L.SetField(jsonMetaTable, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"get": func(state *lua.LState) int {
+			// JSONPath queries return a table of all the matches, and legacy paths return the first match.
+			doc := checkJSON(state, 1)
+			path := checkJSONPath(state, 2)
+			values := doc.Get(path)
+			if path.IsLegacy() {
+				if len(values) == 0 {
+					state.Push(lua.LNil)
+					return 1
+				}
+				state.Push(jsonValueToLuaType(state, values[0]))
+				return 1
+			}
+			state.Push(jsonValueToLuaType(state, jsondoc.NewArray(values...)))
+			return 1
+		},
+		"set": func(state *lua.LState) int {
+			doc := checkJSON(state, 1)
+			path := checkJSONPath(state, 2)
+			value, err := luaTypeToJSONValue(state.Get(3))
+			if err != nil {
+				state.ArgError(3, err.Error())
+			}
+			state.Push(lua.LNumber(doc.Set(path, value)))
+			return 1
+		},
+		"del": func(state *lua.LState) int {
+			doc := checkJSON(state, 1)
+			state.Push(lua.LNumber(doc.Delete(checkJSONPath(state, 2))))
+			return 1
+		},
+		"type": func(state *lua.LState) int {
+			doc := checkJSON(state, 1)
+			values := doc.Get(checkJSONPath(state, 2))
+			if len(values) == 0 {
+				state.Push(lua.LNil)
+				return 1
+			}
+			state.Push(lua.LString(jsondoc.TypeName(values[0])))
+			return 1
+		},
+		"tostring": func(state *lua.LState) int {
+			state.Push(lua.LString(checkJSON(state, 1).String()))
+			return 1
+		},
+	}))
 }
 
 // luaKeyExtractionFunc executes the extraction function defined in the script and returns the result or error.
@@ -711,6 +804,13 @@ func (server *SugarDB) luaKeyExtractionFunc(cmd []string, args []string) (intern
 
 // luaHandlerFunc executes the extraction function defined in the script nad returns the RESP response or error.
 func (server *SugarDB) luaHandlerFunc(command string, args []string, params internal.HandlerFuncParams) ([]byte, error) {
+	// The store lock is acquired before the script's lock, in the same order as a transaction that executes
+	// the command. The store may already be locked if the command is executed within a transaction.
+	if !storeLocked(params.Context) {
+		params.Context = server.lockStore(params.Context)
+		defer server.storeLock.Unlock()
+	}
+
 	// Lock this script's execution key before executing the handler.
 	script, ok := server.scriptVMs.Load(command)
 	if !ok {
@@ -733,8 +833,40 @@ func (server *SugarDB) luaHandlerFunc(command string, args []string, params inte
 	for i, s := range params.Command {
 		cmd.RawSetInt(i+1, lua.LString(s))
 	}
-	// Function that checks if keys exist
-	keysExist := L.NewFunction(func(state *lua.LState) int {
+	// Functions that check if keys exist, get values from keys and set values on keys
+	keysExist := server.luaKeysExistFunc(L, params.Context, nil)
+	getValues := server.luaGetValuesFunc(L, params.Context, nil)
+	setValues := server.luaSetValuesFunc(L, params.Context, nil)
+	// Args (Array)
+	funcArgs := L.NewTable()
+	for i, s := range args {
+		funcArgs.RawSetInt(i+1, lua.LString(s))
+	}
+
+	// Call the lua handler function
+	var err error
+	_ = L.CallByParam(lua.P{
+		Fn:      L.GetGlobal("handlerFunc"),
+		NRet:    1,
+		Protect: true,
+		Handler: L.NewFunction(func(state *lua.LState) int {
+			err = errors.New(state.Get(-1).String())
+			state.Pop(1)
+			return 0
+		}),
+	}, ctx, cmd, keysExist, getValues, setValues, funcArgs)
+	if err != nil {
+		return nil, err
+	}
+	// Get and pop the 2 values at the top of the stack, checking whether an error is returned.
+	defer L.Pop(1)
+	return []byte(L.Get(-1).String()), nil
+}
+
+// luaKeysExistFunc returns the Lua function that checks if keys exist in the store.
+// If declared is not nil, the function raises an error when a key is not in declared.
+func (server *SugarDB) luaKeysExistFunc(L *lua.LState, ctx context.Context, declared []string) *lua.LFunction {
+	return L.NewFunction(func(state *lua.LState) int {
 		// Get the keys array and pop it from the stack.
 		v := state.CheckTable(1)
 		state.Pop(1)
@@ -743,8 +875,11 @@ func (server *SugarDB) luaHandlerFunc(command string, args []string, params inte
 		for i := 1; i <= v.Len(); i++ {
 			keys = append(keys, v.RawGetInt(i).String())
 		}
+		if err := checkDeclaredKeys(declared, keys); err != nil {
+			state.RaiseError("%s", err.Error())
+		}
 		// Call the keysExist method to check if the key exists in the store.
-		exist := server.keysExist(params.Context, keys)
+		exist := server.keysExist(ctx, keys)
 		// Build the response table that specifies if each key exists.
 		res := state.NewTable()
 		for key, exists := range exist {
@@ -754,8 +889,12 @@ func (server *SugarDB) luaHandlerFunc(command string, args []string, params inte
 		state.Push(res)
 		return 1
 	})
-	// Function that gets values from keys
-	getValues := L.NewFunction(func(state *lua.LState) int {
+}
+
+// luaGetValuesFunc returns the Lua function that gets values from keys.
+// If declared is not nil, the function raises an error when a key is not in declared.
+func (server *SugarDB) luaGetValuesFunc(L *lua.LState, ctx context.Context, declared []string) *lua.LFunction {
+	return L.NewFunction(func(state *lua.LState) int {
 		// Get the keys array and pop it from the stack.
 		v := state.CheckTable(1)
 		state.Pop(1)
@@ -764,8 +903,11 @@ func (server *SugarDB) luaHandlerFunc(command string, args []string, params inte
 		for i := 1; i <= v.Len(); i++ {
 			keys = append(keys, v.RawGetInt(i).String())
 		}
+		if err := checkDeclaredKeys(declared, keys); err != nil {
+			state.RaiseError("%s", err.Error())
+		}
 		// Call the getValues method to get the values for each of the keys.
-		values := server.getValues(params.Context, keys)
+		values := server.getValues(ctx, keys)
 		// Build the response table that contains each key/value pair.
 		res := state.NewTable()
 		for key, value := range values {
@@ -776,8 +918,12 @@ func (server *SugarDB) luaHandlerFunc(command string, args []string, params inte
 		state.Push(res)
 		return 1
 	})
-	// Function that sets values on keys
-	setValues := L.NewFunction(func(state *lua.LState) int {
+}
+
+// luaSetValuesFunc returns the Lua function that sets values on keys.
+// If declared is not nil, the function raises an error when a key is not in declared.
+func (server *SugarDB) luaSetValuesFunc(L *lua.LState, ctx context.Context, declared []string) *lua.LFunction {
+	return L.NewFunction(func(state *lua.LState) int {
 		// Get the key/value table.
 		v := state.CheckTable(1)
 		// Get values passed from the Lua script and add.
@@ -790,42 +936,97 @@ func (server *SugarDB) luaHandlerFunc(command string, args []string, params inte
 				state.ArgError(1, err.Error())
 			}
 		})
-		if err = server.setValues(params.Context, values); err != nil {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		if err = checkDeclaredKeys(declared, keys); err != nil {
+			state.RaiseError("%s", err.Error())
+		}
+		if err = server.setValues(ctx, values); err != nil {
 			state.ArgError(1, err.Error())
 		}
 		// pop key/value table from the stack
 		state.Pop(1)
 		return 0
 	})
-	// Args (Array)
-	funcArgs := L.NewTable()
-	for i, s := range args {
-		funcArgs.RawSetInt(i+1, lua.LString(s))
+}
+
+// luaRunScript executes an ad-hoc Lua script added with AddScript.
+// The keys and args are exposed to the script as the KEYS and ARGV global tables.
+// The script's return value is converted to a RESP response.
+func (server *SugarDB) luaRunScript(ctx context.Context, script *adHocScript, keys []string, args []string) ([]byte, error) {
+	L := script.vm.(*lua.LState)
+
+	// Build KEYS and ARGV tables
+	keysTable := L.NewTable()
+	for i, key := range keys {
+		keysTable.RawSetInt(i+1, lua.LString(key))
 	}
+	argsTable := L.NewTable()
+	for i, arg := range args {
+		argsTable.RawSetInt(i+1, lua.LString(arg))
+	}
+	L.SetGlobal("KEYS", keysTable)
+	L.SetGlobal("ARGV", argsTable)
 
-	// Call the lua handler function
-	var err error
-	_ = L.CallByParam(lua.P{
-		Fn:      L.GetGlobal("handlerFunc"),
+	// Functions to interact with the store. They can only access the keys declared in KEYS.
+	L.SetGlobal("keysExist", server.luaKeysExistFunc(L, ctx, keys))
+	L.SetGlobal("getValues", server.luaGetValuesFunc(L, ctx, keys))
+	L.SetGlobal("setValues", server.luaSetValuesFunc(L, ctx, keys))
+
+	if err := L.CallByParam(lua.P{
+		Fn:      script.chunk.(*lua.LFunction),
 		NRet:    1,
 		Protect: true,
-		Handler: L.NewFunction(func(state *lua.LState) int {
-			err = errors.New(state.Get(-1).String())
-			state.Pop(1)
-			return 0
-		}),
-	}, ctx, cmd, keysExist, getValues, setValues, funcArgs)
-	if err != nil {
+	}); err != nil {
 		return nil, err
 	}
-	// Get and pop the 2 values at the top of the stack, checking whether an error is returned.
 	defer L.Pop(1)
-	return []byte(L.Get(-1).String()), nil
+
+	return luaValueToRESP(L.Get(-1))
 }
 
-func checkHash(L *lua.LState, n int) hash.Hash {
+// luaValueToRESP converts the value returned by an ad-hoc Lua script into a RESP response.
+// Numbers are truncated to integers and false is returned as a nil reply.
+// A table with an "err" field is returned as an error and a table with an "ok" field as a simple string.
+func luaValueToRESP(value lua.LValue) ([]byte, error) {
+	switch v := value.(type) {
+	case nil, *lua.LNilType:
+		return []byte("$-1\r\n"), nil
+	case lua.LBool:
+		if v {
+			return []byte(":1\r\n"), nil
+		}
+		return []byte("$-1\r\n"), nil
+	case lua.LNumber:
+		return []byte(fmt.Sprintf(":%d\r\n", int64(v))), nil
+	case lua.LString:
+		return []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)), nil
+	case *lua.LTable:
+		if e := v.RawGetString("err"); e != lua.LNil {
+			return nil, errors.New(e.String())
+		}
+		if ok := v.RawGetString("ok"); ok != lua.LNil {
+			return []byte(fmt.Sprintf("+%s\r\n", ok.String())), nil
+		}
+		res := []byte(fmt.Sprintf("*%d\r\n", v.Len()))
+		for i := 1; i <= v.Len(); i++ {
+			b, err := luaValueToRESP(v.RawGetInt(i))
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, b...)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("cannot convert lua %s to a response", value.Type())
+	}
+}
+
+func checkHash(L *lua.LState, n int) *hash.Hash {
 	ud := L.CheckUserData(n)
-	if v, ok := ud.Value.(hash.Hash); ok {
+	if v, ok := ud.Value.(*hash.Hash); ok {
 		return v
 	}
 	L.ArgError(n, "hash expected")
@@ -859,6 +1060,23 @@ func checkSortedSet(L *lua.LState, n int) *sorted_set.SortedSet {
 	return nil
 }
 
+func checkJSON(L *lua.LState, n int) *jsondoc.Document {
+	ud := L.CheckUserData(n)
+	if v, ok := ud.Value.(*jsondoc.Document); ok {
+		return v
+	}
+	L.ArgError(n, "json expected")
+	return nil
+}
+
+func checkJSONPath(L *lua.LState, n int) jsondoc.Path {
+	path, err := jsondoc.ParsePath(L.OptString(n, "$"))
+	if err != nil {
+		L.ArgError(n, err.Error())
+	}
+	return path
+}
+
 func checkArray(table *lua.LTable) ([]string, error) {
 	list := make([]string, table.Len())
 	var err error = nil
@@ -893,17 +1111,23 @@ func luaTypeToNativeType(value lua.LValue) (interface{}, error) {
 	case lua.LTNumber:
 		return internal.AdaptType(value.String()), nil
 	case lua.LTTable:
-		return checkArray(value.(*lua.LTable))
+		elements, err := checkArray(value.(*lua.LTable))
+		if err != nil {
+			return nil, err
+		}
+		return list.NewList(elements...), nil
 	case lua.LTUserData:
 		switch value.(*lua.LUserData).Value.(type) {
 		default:
 			return nil, errors.New("unknown user data")
-		case hash.Hash:
-			return value.(*lua.LUserData).Value.(hash.Hash), nil
+		case *hash.Hash:
+			return value.(*lua.LUserData).Value.(*hash.Hash), nil
 		case *set.Set:
 			return value.(*lua.LUserData).Value.(*set.Set), nil
 		case *sorted_set.SortedSet:
 			return value.(*lua.LUserData).Value.(*sorted_set.SortedSet), nil
+		case *jsondoc.Document:
+			return value.(*lua.LUserData).Value.(*jsondoc.Document), nil
 		}
 	default:
 		return nil, fmt.Errorf("unknown type %s", value.Type())
@@ -912,23 +1136,27 @@ func luaTypeToNativeType(value lua.LValue) (interface{}, error) {
 
 func nativeTypeToLuaType(L *lua.LState, value interface{}) lua.LValue {
 	switch value.(type) {
+	case nil:
+		return lua.LNil
 	case string:
 		return lua.LString(value.(string))
 	case float32:
 		return lua.LNumber(value.(float32))
 	case float64:
 		return lua.LNumber(value.(float64))
-	case int, int64:
+	case int:
 		return lua.LNumber(value.(int))
-	case []string:
+	case int64:
+		return lua.LNumber(value.(int64))
+	case *list.List:
 		tbl := L.NewTable()
-		for i, element := range value.([]string) {
+		for i, element := range value.(*list.List).Elements() {
 			tbl.RawSetInt(i+1, lua.LString(element))
 		}
 		return tbl
-	case hash.Hash:
+	case *hash.Hash:
 		ud := L.NewUserData()
-		ud.Value = value.(hash.Hash)
+		ud.Value = value.(*hash.Hash)
 		L.SetMetatable(ud, L.GetTypeMetatable("hash"))
 		return ud
 	case *set.Set:
@@ -941,6 +1169,99 @@ func nativeTypeToLuaType(L *lua.LState, value interface{}) lua.LValue {
 		ud.Value = value.(*sorted_set.SortedSet)
 		L.SetMetatable(ud, L.GetTypeMetatable("zset"))
 		return ud
+	case *jsondoc.Document:
+		ud := L.NewUserData()
+		ud.Value = value.(*jsondoc.Document)
+		L.SetMetatable(ud, L.GetTypeMetatable("json"))
+		return ud
+	}
+	// Types that have no Lua representation are returned as nil.
+	return lua.LNil
+}
+
+// luaTypeToJSONValue converts a Lua value to a JSON value. Tables with consecutive integer keys starting at 1
+// are converted to arrays, and other tables to objects. Integral numbers are converted to integers.
+func luaTypeToJSONValue(value lua.LValue) (interface{}, error) {
+	switch v := value.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LBool:
+		return bool(v), nil
+	case lua.LString:
+		return string(v), nil
+	case lua.LNumber:
+		if f := float64(v); f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return int64(f), nil
+		}
+		return float64(v), nil
+	case *lua.LTable:
+		if n := v.Len(); n > 0 {
+			elements := make([]interface{}, 0, n)
+			var err error
+			v.ForEach(func(key lua.LValue, value lua.LValue) {
+				if err != nil {
+					return
+				}
+				if _, ok := key.(lua.LNumber); !ok {
+					err = fmt.Errorf("expected array keys to be integers, got %s", key.Type())
+					return
+				}
+				var element interface{}
+				element, err = luaTypeToJSONValue(value)
+				elements = append(elements, element)
+			})
+			return jsondoc.NewArray(elements...), err
+		}
+		object := jsondoc.NewObject()
+		var err error
+		v.ForEach(func(key lua.LValue, value lua.LValue) {
+			if err != nil {
+				return
+			}
+			var element interface{}
+			element, err = luaTypeToJSONValue(value)
+			object.Set(key.String(), element)
+		})
+		return object, err
+	case *lua.LUserData:
+		if doc, ok := v.Value.(*jsondoc.Document); ok {
+			return jsondoc.Parse([]byte(doc.String()))
+		}
+		return nil, errors.New("unknown user data")
+	default:
+		return nil, fmt.Errorf("cannot convert lua %s to JSON", value.Type())
+	}
+}
+
+// jsonValueToLuaType converts a JSON value to a Lua value. Objects and arrays are converted to tables,
+// and null is converted to nil.
+func jsonValueToLuaType(L *lua.LState, value interface{}) lua.LValue {
+	switch v := value.(type) {
+	case bool:
+		return lua.LBool(v)
+	case string:
+		return lua.LString(v)
+	case int64:
+		return lua.LNumber(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return lua.LNumber(f)
+	case float64:
+		return lua.LNumber(v)
+	case *jsondoc.Array:
+		tbl := L.NewTable()
+		for i, element := range v.Elements() {
+			tbl.RawSetInt(i+1, jsonValueToLuaType(L, element))
+		}
+		return tbl
+	case *jsondoc.Object:
+		tbl := L.NewTable()
+		for _, key := range v.Keys() {
+			element, _ := v.Get(key)
+			tbl.RawSetString(key, jsonValueToLuaType(L, element))
+		}
+		return tbl
+	default:
+		return lua.LNil
 	}
-	return nil
 }
@@ -31,12 +31,22 @@ import (
 	"github.com/echovault/sugardb/internal/modules/admin"
 	"github.com/echovault/sugardb/internal/modules/connection"
 	"github.com/echovault/sugardb/internal/modules/generic"
+	"github.com/echovault/sugardb/internal/modules/geo"
 	"github.com/echovault/sugardb/internal/modules/hash"
+	"github.com/echovault/sugardb/internal/modules/hyperloglog"
+	jsondoc "github.com/echovault/sugardb/internal/modules/json"
 	"github.com/echovault/sugardb/internal/modules/list"
+	"github.com/echovault/sugardb/internal/modules/probabilistic"
 	"github.com/echovault/sugardb/internal/modules/pubsub"
+	"github.com/echovault/sugardb/internal/modules/scripting"
+	"github.com/echovault/sugardb/internal/modules/search"
 	"github.com/echovault/sugardb/internal/modules/set"
 	"github.com/echovault/sugardb/internal/modules/sorted_set"
+	"github.com/echovault/sugardb/internal/modules/stream"
 	str "github.com/echovault/sugardb/internal/modules/string"
+	"github.com/echovault/sugardb/internal/modules/timeseries"
+	tx "github.com/echovault/sugardb/internal/modules/transaction"
+	"github.com/echovault/sugardb/internal/modules/vector_set"
 	"github.com/echovault/sugardb/internal/raft"
 	"github.com/echovault/sugardb/internal/snapshot"
 	lua "github.com/yuin/gopher-lua"
@@ -55,7 +65,9 @@ type SugarDB struct {
 	clock clock.Clock
 
 	// config holds the SugarDB configuration variables.
-	config config.Config
+	// Once the server is running, read it with currentConfig as it can be changed with CONFIG SET.
+	config     config.Config
+	configLock sync.RWMutex // RWMutex that guards the config against changes made with CONFIG SET.
 
 	// The current index for the latest connection id.
 	// This number is incremented everytime there's a new connection and
@@ -70,6 +82,9 @@ type SugarDB struct {
 		embedded   internal.ConnectionInfo               // Information for the embedded connection.
 	}
 
+	// clientPause holds the state of the pause started with CLIENT PAUSE.
+	clientPause clientPause
+
 	// Global read-write mutex for entire store.
 	storeLock *sync.RWMutex
 
@@ -77,6 +92,8 @@ type SugarDB struct {
 	// The int key on the outer map represents the database index.
 	// Each database has a map that has a string key and the key data (value and expiry time).
 	store map[int]map[string]internal.KeyData
+	// scanIndex keeps the keys of each database ordered by their scan position for SCAN.
+	scanIndex map[int]*internal.ScanIndex[string]
 
 	// memUsed tracks the memory usage of the data in the store.
 	memUsed int64
@@ -110,6 +127,27 @@ type SugarDB struct {
 	// for each of the commands is not thread safe.
 	// This map's shape is map[string]struct{vm: any, lock: sync.Mutex} with the string key being the command name.
 	scriptVMs sync.Map
+	// Scripts added with AddScript (e.g. through EVAL or SCRIPT LOAD) that are not associated with a command.
+	// This map's shape is map[string]*adHocScript with the string key being the SHA1 digest of the script.
+	scripts sync.Map
+
+	// transactions holds the MULTI/EXEC state of TCP clients and the keys watched by all transactions.
+	transactions struct {
+		// Mutex for the clients and watched maps.
+		mut sync.Mutex
+		// The transaction state of each TCP client that has called MULTI or WATCH.
+		clients map[*net.Conn]*transaction
+		// The transactions watching each key. The int key on the outer map represents the database index.
+		watched map[int]map[string][]*transaction
+	}
+
+	// blockedClients holds the channels used to wake up clients blocked on keys (e.g. XREAD with BLOCK).
+	blockedClients struct {
+		// Mutex for the waiters map.
+		mut sync.Mutex
+		// The channels of the clients waiting for each key. The int key on the outer map represents the database index.
+		waiters map[int]map[string][]chan struct{}
+	}
 
 	raft       *raft.Raft             // The raft replication layer for SugarDB.
 	memberList *memberlist.MemberList // The memberlist layer for SugarDB.
@@ -118,6 +156,13 @@ type SugarDB struct {
 
 	acl    *acl.ACL
 	pubSub *pubsub.PubSub
+	search *search.Engine // The indexes of hashes, kept up to date by setValues and removeKey.
+
+	keyspaceEvents       atomic.Int64              // The keyspace notification classes enabled with notify-keyspace-events.
+	keyspaceEventHandler func(event KeyspaceEvent) // The embedded keyspace event handler.
+	keyspaceEventQueue   *keyspaceEventQueue       // Queue that delivers keyspace events to the embedded handler.
+
+	stats serverStats // Counters reported by the INFO command.
 
 	snapshotInProgress         atomic.Bool      // Atomic boolean that's true when actively taking a snapshot.
 	rewriteAOFInProgress       atomic.Bool      // Atomic boolean that's true when actively rewriting AOF file is in progress.
@@ -130,6 +175,9 @@ type SugarDB struct {
 	listener atomic.Value  // Holds the TCP listener.
 	quit     chan struct{} // Channel that signals the closing of all client connections.
 	stopTTL  chan struct{} // Channel that signals the TTL sampling goroutine to stop execution.
+	// Channel that passes the new sampling interval to the TTL sampling goroutine when the eviction
+	// configuration is changed at runtime. An interval of 0 pauses the sampling.
+	evictionIntervalUpdates chan time.Duration
 }
 
 // NewSugarDB creates a new SugarDB instance.
@@ -155,6 +203,7 @@ func NewSugarDB(options ...func(sugarDB *SugarDB)) (*SugarDB, error) {
 		},
 		storeLock: &sync.RWMutex{},
 		store:     make(map[int]map[string]internal.KeyData),
+		scanIndex: make(map[int]*internal.ScanIndex[string]),
 		memUsed:   0,
 		keysWithExpiry: struct {
 			rwMutex sync.RWMutex
@@ -170,22 +219,52 @@ func NewSugarDB(options ...func(sugarDB *SugarDB)) (*SugarDB, error) {
 			commands = append(commands, admin.Commands()...)
 			commands = append(commands, connection.Commands()...)
 			commands = append(commands, generic.Commands()...)
+			commands = append(commands, geo.Commands()...)
 			commands = append(commands, hash.Commands()...)
+			commands = append(commands, hyperloglog.Commands()...)
+			commands = append(commands, jsondoc.Commands()...)
 			commands = append(commands, list.Commands()...)
+			commands = append(commands, probabilistic.Commands()...)
 			commands = append(commands, pubsub.Commands()...)
+			commands = append(commands, scripting.Commands()...)
+			commands = append(commands, search.Commands()...)
 			commands = append(commands, set.Commands()...)
 			commands = append(commands, sorted_set.Commands()...)
+			commands = append(commands, stream.Commands()...)
 			commands = append(commands, str.Commands()...)
+			commands = append(commands, timeseries.Commands()...)
+			commands = append(commands, tx.Commands()...)
+			commands = append(commands, vector_set.Commands()...)
 			return commands
 		}(),
+		transactions: struct {
+			mut     sync.Mutex
+			clients map[*net.Conn]*transaction
+			watched map[int]map[string][]*transaction
+		}{
+			mut:     sync.Mutex{},
+			clients: make(map[*net.Conn]*transaction),
+			watched: make(map[int]map[string][]*transaction),
+		},
+		blockedClients: struct {
+			mut     sync.Mutex
+			waiters map[int]map[string][]chan struct{}
+		}{
+			mut:     sync.Mutex{},
+			waiters: make(map[int]map[string][]chan struct{}),
+		},
 		quit:    make(chan struct{}),
 		stopTTL: make(chan struct{}),
+
+		evictionIntervalUpdates: make(chan time.Duration, 1),
 	}
 
 	for _, option := range options {
 		option(sugarDB)
 	}
 
+	sugarDB.stats.startTime = sugarDB.clock.Now()
+
 	sugarDB.context = context.WithValue(
 		sugarDB.context, "ServerID",
 		internal.ContextServerID(sugarDB.config.ServerID),
@@ -206,6 +285,19 @@ func NewSugarDB(options ...func(sugarDB *SugarDB)) (*SugarDB, error) {
 	// Set up Pub/Sub module
 	sugarDB.pubSub = pubsub.NewPubSub(sugarDB.context)
 
+	// Set up search module
+	sugarDB.search = search.NewEngine()
+
+	// Set up keyspace notifications
+	keyspaceEvents, err := internal.ParseKeyspaceEvents(sugarDB.config.NotifyKeyspaceEvents)
+	if err != nil {
+		return nil, err
+	}
+	sugarDB.keyspaceEvents.Store(int64(keyspaceEvents))
+	if sugarDB.keyspaceEventHandler != nil {
+		sugarDB.keyspaceEventQueue = newKeyspaceEventQueue(sugarDB.keyspaceEventHandler)
+	}
+
 	if sugarDB.isInCluster() {
 		sugarDB.raft = raft.NewRaft(raft.Opts{
 			Config:                sugarDB.config,
@@ -216,10 +308,16 @@ func NewSugarDB(options ...func(sugarDB *SugarDB)) (*SugarDB, error) {
 			FinishSnapshot:        sugarDB.finishSnapshot,
 			SetLatestSnapshotTime: sugarDB.setLatestSnapshot,
 			GetHandlerFuncParams:  sugarDB.getHandlerFuncParams,
+			RunTransaction: func(ctx context.Context, commands [][]string, watched map[int]map[string]uint64) ([]byte, error) {
+				return sugarDB.runTransaction(ctx, commands, nil, &transaction{fingerprints: watched})
+			},
+			TouchWriteKeys: sugarDB.touchWriteKeys,
 			DeleteKey: func(ctx context.Context, key string) error {
 				sugarDB.storeLock.Lock()
 				defer sugarDB.storeLock.Unlock()
-				return sugarDB.deleteKey(ctx, key)
+				// Keys are deleted through raft when they expire or are evicted.
+				// The leader publishes the keyspace event when it applies the deletion.
+				return sugarDB.removeKey(ctx, key)
 			},
 			GetState: func() map[int]map[string]internal.KeyData {
 				state := make(map[int]map[string]internal.KeyData)
@@ -315,35 +413,47 @@ func NewSugarDB(options ...func(sugarDB *SugarDB)) (*SugarDB, error) {
 		sugarDB.aofEngine = aofEngine
 	}
 
-	// If eviction policy is not noeviction, start a goroutine to evict keys at the configured interval.
-	if sugarDB.config.EvictionPolicy != constants.NoEviction {
-		go func() {
-			ticker := time.NewTicker(sugarDB.config.EvictionInterval)
-			defer func() {
+	// Start a goroutine to evict keys at the configured interval while the eviction policy is not noeviction.
+	evictionInterval := sugarDB.config.EvictionInterval
+	if sugarDB.config.EvictionPolicy == constants.NoEviction {
+		evictionInterval = 0
+	}
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		ticker.Stop()
+		if evictionInterval > 0 {
+			ticker.Reset(evictionInterval)
+		}
+		defer func() {
+			ticker.Stop()
+		}()
+		for {
+			select {
+			case interval := <-sugarDB.evictionIntervalUpdates:
+				// The eviction interval or policy was changed with CONFIG SET.
 				ticker.Stop()
-			}()
-			for {
-				select {
-				case <-ticker.C:
-					// Run key eviction for each database that has volatile keys.
-					wg := sync.WaitGroup{}
-					for database, _ := range sugarDB.keysWithExpiry.keys {
-						wg.Add(1)
-						ctx := context.WithValue(context.Background(), "Database", database)
-						go func(ctx context.Context, wg *sync.WaitGroup) {
-							if err := sugarDB.evictKeysWithExpiredTTL(ctx); err != nil {
-								log.Printf("evict with ttl: %v\n", err)
-							}
-							wg.Done()
-						}(ctx, &wg)
-					}
-					wg.Wait()
-				case <-sugarDB.stopTTL:
-					break
+				if interval > 0 {
+					ticker.Reset(interval)
+				}
+			case <-ticker.C:
+				// Run key eviction for each database that has volatile keys.
+				wg := sync.WaitGroup{}
+				for database, _ := range sugarDB.keysWithExpiry.keys {
+					wg.Add(1)
+					ctx := context.WithValue(context.Background(), "Database", database)
+					go func(ctx context.Context, wg *sync.WaitGroup) {
+						if err := sugarDB.evictKeysWithExpiredTTL(ctx); err != nil {
+							log.Printf("evict with ttl: %v\n", err)
+						}
+						wg.Done()
+					}(ctx, &wg)
 				}
+				wg.Wait()
+			case <-sugarDB.stopTTL:
+				return
 			}
-		}()
-	}
+		}
+	}()
 
 	if sugarDB.config.TLS && len(sugarDB.config.CertKeyPairs) <= 0 {
 		return nil, errors.New("must provide certificate and key file paths for TLS mode")
@@ -380,7 +490,7 @@ func NewSugarDB(options ...func(sugarDB *SugarDB)) (*SugarDB, error) {
 }
 
 func (server *SugarDB) startTCP() {
-	conf := server.config
+	conf := server.currentConfig()
 
 	listenConfig := net.ListenConfig{
 		KeepAlive: 200 * time.Millisecond,
@@ -482,15 +592,26 @@ func (server *SugarDB) handleConnection(conn net.Conn) {
 	// Set the default connection information
 	server.connInfo.mut.Lock()
 	server.connInfo.tcpClients[&conn] = internal.ConnectionInfo{
-		Id:       cid,
-		Name:     "",
-		Protocol: 2,
-		Database: 0,
+		Id:              cid,
+		Name:            "",
+		Protocol:        2,
+		Database:        0,
+		Addr:            conn.RemoteAddr().String(),
+		LocalAddr:       conn.LocalAddr().String(),
+		CreatedAt:       server.clock.Now(),
+		LastInteraction: server.clock.Now(),
 	}
 	server.connInfo.mut.Unlock()
 
 	defer func() {
 		log.Printf("closing connection %d...", cid)
+		server.removeTransaction(&conn)
+		server.connInfo.mut.Lock()
+		delete(server.connInfo.tcpClients, &conn)
+		server.connInfo.mut.Unlock()
+		if server.acl != nil {
+			server.acl.UnregisterConnection(&conn)
+		}
 		if err := conn.Close(); err != nil {
 			log.Println(err)
 		}
@@ -510,6 +631,9 @@ func (server *SugarDB) handleConnection(conn net.Conn) {
 			break
 		}
 
+		// Hold the command while the clients are paused with CLIENT PAUSE.
+		server.waitForClientPause(message)
+
 		res, err := server.handleCommand(ctx, message, &conn, false, false)
 		if err != nil && errors.Is(err, io.EOF) {
 			break
@@ -657,6 +781,11 @@ func (server *SugarDB) ShutDown() {
 		}
 	}
 	server.commandsRWMut.Unlock()
+	server.flushScripts()
+
+	if server.keyspaceEventQueue != nil {
+		server.keyspaceEventQueue.close()
+	}
 
 	if !server.isInCluster() {
 		// Server is not in cluster, run standalone-only shutdown processes.
@@ -16,6 +16,7 @@ package sugardb
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -591,6 +592,154 @@ func Test_Cluster(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_BlockingPop", func(t *testing.T) {
+		// Block on the leader and push through a follower, which forwards the write to the leader.
+		if err := nodes[0].client.WriteArray([]resp.Value{
+			resp.StringValue("BLPOP"), resp.StringValue("blocking_key1"), resp.StringValue("5"),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+
+		<-time.After(200 * time.Millisecond)
+
+		if err := nodes[1].client.WriteArray([]resp.Value{
+			resp.StringValue("RPUSH"), resp.StringValue("blocking_key1"), resp.StringValue("value1"),
+		}); err != nil {
+			t.Error(err)
+			return
+		}
+		if _, _, err := nodes[1].client.ReadValue(); err != nil {
+			t.Error(err)
+			return
+		}
+
+		res, _, err := nodes[0].client.ReadValue()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		arr := res.Array()
+		if len(arr) != 2 || arr[0].String() != "blocking_key1" || arr[1].String() != "value1" {
+			t.Errorf("expected BLPOP response [blocking_key1 value1], got %v", arr)
+		}
+	})
+
+	t.Run("Test_TransactionWatch", func(t *testing.T) {
+		leader := nodes[0].server
+		key := "watch_key1"
+		if _, _, err := leader.Set(key, "value1", SETOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		watched := map[int]map[string]uint64{0: leader.keyFingerprints(leader.context, 0, []string{key})}
+
+		// A transaction whose watched key is modified before the entry is applied must be aborted on every node,
+		// even though the check made before proposing the transaction passed.
+		if _, _, err := leader.Set(key, "value2", SETOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		res, err := leader.raftApplyTransaction(leader.context, [][]string{{"SET", key, "from_tx"}}, watched)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if string(res) != "*-1\r\n" {
+			t.Errorf("expected aborted transaction response, got %q", string(res))
+		}
+
+		// The transaction is executed when the watched key still matches its fingerprint.
+		watched = map[int]map[string]uint64{0: leader.keyFingerprints(leader.context, 0, []string{key})}
+		res, err = leader.raftApplyTransaction(leader.context, [][]string{{"SET", key, "from_tx"}}, watched)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if string(res) != "*1\r\n+OK\r\n" {
+			t.Errorf("expected executed transaction response, got %q", string(res))
+		}
+
+		// Yield
+		<-time.After(200 * time.Millisecond)
+
+		for i, node := range nodes {
+			if err := node.client.WriteArray([]resp.Value{resp.StringValue("GET"), resp.StringValue(key)}); err != nil {
+				t.Errorf("could not write command to node %d: %v", i, err)
+				continue
+			}
+			rd, _, err := node.client.ReadValue()
+			if err != nil {
+				t.Errorf("could not read response from node %d: %v", i, err)
+				continue
+			}
+			if rd.String() != "from_tx" {
+				t.Errorf("expected value \"from_tx\" on node %d, got %q", i, rd.String())
+			}
+		}
+	})
+
+	t.Run("Test_Migrate", func(t *testing.T) {
+		port, err := internal.GetFreePort()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conf := DefaultConfig()
+		conf.BindAddr = "localhost"
+		conf.Port = uint16(port)
+		conf.DataDir = ""
+		target := createSugarDBWithConfig(conf)
+		go func() {
+			target.Start()
+		}()
+		t.Cleanup(func() {
+			target.ShutDown()
+		})
+		conn, err := internal.GetConnection("localhost", port)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_ = conn.Close()
+
+		key := "migrate_key1"
+		if _, _, err = nodes[0].server.Set(key, "value1", SETOptions{}); err != nil {
+			t.Error(err)
+			return
+		}
+		res, err := nodes[0].server.Migrate("localhost", port, []string{key}, MIGRATEOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if res != "OK" {
+			t.Errorf("Migrate() got = %s, want OK", res)
+		}
+
+		// Yield
+		<-time.After(200 * time.Millisecond)
+
+		// The migrated key must be deleted on every node, not only on the leader.
+		for i, node := range nodes {
+			if err := node.client.WriteArray([]resp.Value{resp.StringValue("GET"), resp.StringValue(key)}); err != nil {
+				t.Errorf("could not write command to node %d: %v", i, err)
+				continue
+			}
+			rd, _, err := node.client.ReadValue()
+			if err != nil {
+				t.Errorf("could not read response from node %d: %v", i, err)
+				continue
+			}
+			if !rd.IsNull() {
+				t.Errorf("expected key %s to be deleted on node %d, got %q", key, i, rd.String())
+			}
+		}
+		if value, err := target.Get(key); err != nil || value != "value1" {
+			t.Errorf("expected value \"value1\" on the target, got %q (%v)", value, err)
+		}
+	})
+
 	t.Run("Test_SnapshotRestore", func(t *testing.T) {
 		// TODO: Test snapshot creation and restoration on the cluster.
 	})
@@ -1169,6 +1318,66 @@ func Test_Standalone(t *testing.T) {
 		}
 	})
 
+	t.Run("Test_AOFResolvedArguments", func(t *testing.T) {
+		t.Parallel()
+
+		dataDir := path.Join(".", "testdata", "test_aof_resolved_arguments")
+		t.Cleanup(func() {
+			_ = os.RemoveAll(dataDir)
+		})
+
+		conf := DefaultConfig()
+		conf.DataDir = dataDir
+		conf.AOFSyncStrategy = "always"
+
+		mockServer, err := NewSugarDB(WithConfig(conf))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer mockServer.ShutDown()
+
+		// Commands that generate an ID or timestamp must be logged with the resolved value,
+		// so that replaying the AOF with a different clock produces the same result.
+		commands := [][]string{
+			{"XADD", "AOFStream", "*", "field", "value"},
+			{"XADD", "AOFStream", "*", "field", "value"},
+			{"TS.ADD", "AOFSeries", "*", "1", "DUPLICATE_POLICY", "LAST"},
+			{"TS.MADD", "AOFSeries", "*", "2"},
+		}
+		var resolved []string
+		for _, cmd := range commands {
+			res, err := mockServer.handleCommand(mockServer.context, internal.EncodeCommand(cmd), nil, false, true)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			v, _, err := resp.NewReader(bytes.NewReader(res)).ReadValue()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if v.Type() == resp.Array {
+				v = v.Array()[0]
+			}
+			resolved = append(resolved, v.String())
+		}
+
+		b, err := os.ReadFile(path.Join(dataDir, "aof", "log.aof"))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if strings.Contains(string(b), "$1\r\n*\r\n") {
+			t.Errorf("expected the AOF not to contain unresolved * arguments, got %q", string(b))
+		}
+		for _, value := range resolved {
+			if !strings.Contains(string(b), fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)) {
+				t.Errorf("expected the AOF to contain the resolved argument %s, got %q", value, string(b))
+			}
+		}
+	})
+
 	t.Run("Test_EvictExpiredTTL", func(t *testing.T) {
 		// TODO: Implement test for evicting expired keys in standalone mode.
 	})
@@ -0,0 +1,449 @@
+// Copyright 2024 Kelvin Clement Mwinuka
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sugardb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/echovault/sugardb/internal"
+	"github.com/echovault/sugardb/internal/codec"
+)
+
+// transaction holds the state of a MULTI/EXEC block.
+// TCP clients are assigned a transaction when they call MULTI or WATCH. The embedded API uses Tx.
+type transaction struct {
+	mut      sync.Mutex       // Mutex for the transaction's state.
+	multi    bool             // True between MULTI and EXEC/DISCARD.
+	aborted  bool             // True when a command failed to queue. EXEC discards an aborted transaction.
+	commands [][]string       // The commands queued since MULTI.
+	watched  map[int][]string // The keys watched by the transaction in each database.
+	dirty    atomic.Bool      // True when one of the watched keys was modified after it was watched.
+	// The fingerprints of the watched keys in each database when they were watched. Only recorded in cluster mode,
+	// where they're replicated with the transaction so that every node checks the watched keys when applying it.
+	fingerprints map[int]map[string]uint64
+}
+
+// getTransaction returns the transaction associated with the TCP connection.
+// If create is true, a new transaction is created for the connection if it does not have one yet.
+func (server *SugarDB) getTransaction(conn *net.Conn, create bool) *transaction {
+	server.transactions.mut.Lock()
+	defer server.transactions.mut.Unlock()
+	tx, ok := server.transactions.clients[conn]
+	if !ok && create {
+		tx = &transaction{}
+		server.transactions.clients[conn] = tx
+	}
+	return tx
+}
+
+// removeTransaction discards the transaction associated with the TCP connection when the connection is closed.
+func (server *SugarDB) removeTransaction(conn *net.Conn) {
+	tx := server.getTransaction(conn, false)
+	if tx == nil {
+		return
+	}
+	tx.mut.Lock()
+	server.unwatch(tx)
+	tx.mut.Unlock()
+
+	server.transactions.mut.Lock()
+	delete(server.transactions.clients, conn)
+	server.transactions.mut.Unlock()
+}
+
+func (server *SugarDB) startTransaction(conn *net.Conn) error {
+	if conn == nil {
+		return errors.New("MULTI is not supported in embedded mode, use NewTx instead")
+	}
+	tx := server.getTransaction(conn, true)
+	tx.mut.Lock()
+	defer tx.mut.Unlock()
+	if tx.multi {
+		return errors.New("MULTI calls can not be nested")
+	}
+	tx.multi = true
+	return nil
+}
+
+func (server *SugarDB) discardTransaction(conn *net.Conn) error {
+	tx := server.getTransaction(conn, false)
+	if tx == nil {
+		return errors.New("DISCARD without MULTI")
+	}
+	tx.mut.Lock()
+	defer tx.mut.Unlock()
+	if !tx.multi {
+		return errors.New("DISCARD without MULTI")
+	}
+	server.resetTransaction(tx)
+	return nil
+}
+
+func (server *SugarDB) execTransaction(ctx context.Context, conn *net.Conn) ([]byte, error) {
+	tx := server.getTransaction(conn, false)
+	if tx == nil {
+		return nil, errors.New("EXEC without MULTI")
+	}
+	return server.exec(ctx, tx, conn)
+}
+
+func (server *SugarDB) watchKeys(ctx context.Context, conn *net.Conn, keys []string) error {
+	if conn == nil {
+		return errors.New("WATCH is not supported in embedded mode, use NewTx instead")
+	}
+	tx := server.getTransaction(conn, true)
+	tx.mut.Lock()
+	defer tx.mut.Unlock()
+	if tx.multi {
+		return errors.New("WATCH inside MULTI is not allowed")
+	}
+	server.watch(ctx, tx, keys)
+	return nil
+}
+
+func (server *SugarDB) unwatchKeys(conn *net.Conn) {
+	tx := server.getTransaction(conn, false)
+	if tx == nil {
+		return
+	}
+	tx.mut.Lock()
+	defer tx.mut.Unlock()
+	server.unwatch(tx)
+}
+
+// abortTransaction marks the connection's open transaction as aborted.
+// This is called when a command sent after MULTI could not be queued.
+func (server *SugarDB) abortTransaction(conn *net.Conn) {
+	tx := server.getTransaction(conn, false)
+	if tx == nil {
+		return
+	}
+	tx.mut.Lock()
+	defer tx.mut.Unlock()
+	if tx.multi {
+		tx.aborted = true
+	}
+}
+
+// queueCommand queues the command if the connection has an open transaction.
+// Returns true if the command was consumed by the transaction. The transaction control commands
+// (MULTI, EXEC, DISCARD, WATCH) are never queued.
+func (server *SugarDB) queueCommand(conn *net.Conn, command internal.Command, subCommand internal.SubCommand, cmd []string) (bool, error) {
+	tx := server.getTransaction(conn, false)
+	if tx == nil {
+		return false, nil
+	}
+	tx.mut.Lock()
+	defer tx.mut.Unlock()
+	if !tx.multi || slices.Contains([]string{"multi", "exec", "discard", "watch"}, strings.ToLower(command.Command)) {
+		return false, nil
+	}
+	if err := server.validateQueuedCommand(command, subCommand, cmd); err != nil {
+		tx.aborted = true
+		return true, err
+	}
+	tx.commands = append(tx.commands, cmd)
+	return true, nil
+}
+
+// validateQueuedCommand checks the command's arguments using its key extraction function
+// so that malformed commands are rejected when they're queued rather than when EXEC is called.
+func (server *SugarDB) validateQueuedCommand(command internal.Command, subCommand internal.SubCommand, cmd []string) error {
+	keyFunc := keyExtractionFunc(command, subCommand)
+	if keyFunc == nil {
+		return nil
+	}
+	_, err := keyFunc(cmd)
+	return err
+}
+
+// exec executes the commands queued in the transaction and resets the transaction's state.
+// The caller must not hold the transaction's mutex.
+func (server *SugarDB) exec(ctx context.Context, tx *transaction, conn *net.Conn) ([]byte, error) {
+	tx.mut.Lock()
+	defer tx.mut.Unlock()
+
+	if !tx.multi {
+		return nil, errors.New("EXEC without MULTI")
+	}
+
+	commands, aborted := tx.commands, tx.aborted
+	defer server.resetTransaction(tx)
+
+	if aborted {
+		return nil, errors.New("EXECABORT Transaction discarded because of previous errors")
+	}
+
+	if server.isInCluster() {
+		synchronize := false
+		for _, cmd := range commands {
+			command, subCommand, _, err := server.resolveCommand(cmd)
+			if err != nil {
+				return nil, err
+			}
+			if (subCommand.Command == "" && command.Sync) || subCommand.Sync {
+				synchronize = true
+				break
+			}
+		}
+		if synchronize {
+			if !server.raft.IsRaftLeader() {
+				return nil, errors.New("not cluster leader, cannot carry out command")
+			}
+			// The whole transaction is replicated as a single log entry, and the watched keys are checked again
+			// when the entry is applied. This check only avoids proposing a transaction that is already aborted.
+			if tx.dirty.Load() {
+				return []byte("*-1\r\n"), nil
+			}
+			return server.raftApplyTransaction(ctx, commands, tx.fingerprints)
+		}
+	}
+
+	return server.runTransaction(ctx, commands, conn, tx)
+}
+
+// runTransaction executes the commands atomically while holding the store lock.
+// If tx is not nil and one of its watched keys has been modified, or no longer matches its fingerprint,
+// no command is executed and a null array is returned. The response is an array containing the response of each command.
+func (server *SugarDB) runTransaction(ctx context.Context, commands [][]string, conn *net.Conn, tx *transaction) ([]byte, error) {
+	write := false
+	for _, cmd := range commands {
+		command, subCommand, _, err := server.resolveCommand(cmd)
+		if err != nil {
+			return nil, err
+		}
+		if internal.IsWriteCommand(command, subCommand) {
+			write = true
+		}
+	}
+
+	// If the transaction contains a write command, wait for state copy to finish.
+	if write {
+		for {
+			if !server.stateCopyInProgress.Load() {
+				server.stateMutationInProgress.Store(true)
+				break
+			}
+		}
+		defer server.stateMutationInProgress.Store(false)
+	}
+
+	ctx = server.lockStore(ctx)
+	defer server.storeLock.Unlock()
+
+	// The store lock prevents any other modification, so the watched keys cannot change past this point.
+	if tx != nil && (tx.dirty.Load() || !server.fingerprintsMatch(tx.fingerprints)) {
+		return []byte("*-1\r\n"), nil
+	}
+
+	res := fmt.Sprintf("*%d\r\n", len(commands))
+	for _, cmd := range commands {
+		command, subCommand, handler, _ := server.resolveCommand(cmd)
+
+		// Refresh the connection details as a previous command in the transaction (e.g. SELECT) may have changed them.
+		if conn != nil {
+			ctx = server.connectionContext(ctx, conn, false)
+		}
+
+		propagated := &internal.PropagatedCommand{}
+		commandCtx := context.WithValue(ctx, internal.ContextPropagatedCommand("PropagatedCommand"), propagated)
+
+		start := time.Now()
+		r, err := handler(server.getHandlerFuncParams(readCommandContext(commandCtx, command, subCommand), cmd, conn))
+		server.recordCommandStats(command, subCommand, start, err, false)
+		if err != nil {
+			// A command that fails part way logs the writes it propagated before the failure.
+			if propagated.Command != nil && internal.IsWriteCommand(command, subCommand) && !server.isInCluster() {
+				server.aofEngine.LogCommand(ctx.Value("Database").(int), internal.EncodeCommand(propagated.Command))
+			}
+			res += fmt.Sprintf("-Error %s\r\n", err.Error())
+			continue
+		}
+		res += string(r)
+
+		if internal.IsWriteCommand(command, subCommand) {
+			if !server.isInCluster() {
+				logged := cmd
+				if propagated.Command != nil {
+					logged = propagated.Command
+				}
+				server.aofEngine.LogCommand(ctx.Value("Database").(int), internal.EncodeCommand(logged))
+			}
+			server.touchWriteKeys(ctx, command, subCommand, cmd)
+		}
+	}
+
+	return []byte(res), nil
+}
+
+// resetTransaction clears the queued commands and watched keys of the transaction.
+// The caller must hold the transaction's mutex.
+func (server *SugarDB) resetTransaction(tx *transaction) {
+	tx.multi = false
+	tx.aborted = false
+	tx.commands = nil
+	server.unwatch(tx)
+}
+
+// watch adds the keys to the transaction's watched keys in the context's database.
+// The caller must hold the transaction's mutex.
+func (server *SugarDB) watch(ctx context.Context, tx *transaction, keys []string) {
+	database := ctx.Value("Database").(int)
+
+	var fingerprints map[string]uint64
+	if server.isInCluster() {
+		fingerprints = server.keyFingerprints(ctx, database, keys)
+	}
+
+	server.transactions.mut.Lock()
+	defer server.transactions.mut.Unlock()
+
+	if tx.watched == nil {
+		tx.watched = make(map[int][]string)
+	}
+	if server.transactions.watched[database] == nil {
+		server.transactions.watched[database] = make(map[string][]*transaction)
+	}
+
+	for _, key := range keys {
+		if slices.Contains(tx.watched[database], key) {
+			continue
+		}
+		tx.watched[database] = append(tx.watched[database], key)
+		server.transactions.watched[database][key] = append(server.transactions.watched[database][key], tx)
+		if fingerprints != nil {
+			if tx.fingerprints == nil {
+				tx.fingerprints = make(map[int]map[string]uint64)
+			}
+			if tx.fingerprints[database] == nil {
+				tx.fingerprints[database] = make(map[string]uint64)
+			}
+			tx.fingerprints[database][key] = fingerprints[key]
+		}
+	}
+}
+
+// unwatch removes all the keys watched by the transaction.
+// The caller must hold the transaction's mutex.
+func (server *SugarDB) unwatch(tx *transaction) {
+	server.transactions.mut.Lock()
+	defer server.transactions.mut.Unlock()
+
+	for database, keys := range tx.watched {
+		for _, key := range keys {
+			watchers := slices.DeleteFunc(server.transactions.watched[database][key], func(t *transaction) bool {
+				return t == tx
+			})
+			if len(watchers) == 0 {
+				delete(server.transactions.watched[database], key)
+				continue
+			}
+			server.transactions.watched[database][key] = watchers
+		}
+	}
+
+	tx.watched = nil
+	tx.fingerprints = nil
+	tx.dirty.Store(false)
+}
+
+// keyFingerprints returns the fingerprint of each of the keys in the database.
+func (server *SugarDB) keyFingerprints(ctx context.Context, database int, keys []string) map[string]uint64 {
+	if !storeLocked(ctx) {
+		server.storeLock.RLock()
+		defer server.storeLock.RUnlock()
+	}
+	fingerprints := make(map[string]uint64, len(keys))
+	for _, key := range keys {
+		fingerprints[key] = server.keyFingerprint(database, key)
+	}
+	return fingerprints
+}
+
+// keyFingerprint returns a hash of the encoded value and expiry time of the key, or 0 if the key does not exist.
+// The encoding does not depend on the node, so every node computes the same fingerprint for the same state.
+// The caller must hold the store lock.
+func (server *SugarDB) keyFingerprint(database int, key string) uint64 {
+	data, ok := server.store[database][key]
+	if !ok {
+		return 0
+	}
+	b, err := codec.EncodeDump(data)
+	if err != nil {
+		// Values that cannot be encoded are only compared by their existence.
+		return 1
+	}
+	return dumpFingerprint(b)
+}
+
+// dumpFingerprint returns the fingerprint of a key encoded by codec.EncodeDump.
+func dumpFingerprint(dump []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(dump)
+	return h.Sum64()
+}
+
+// fingerprintsMatch returns true if all the keys still match the fingerprints recorded when they were watched.
+// The caller must hold the store lock.
+func (server *SugarDB) fingerprintsMatch(fingerprints map[int]map[string]uint64) bool {
+	for database, keys := range fingerprints {
+		for key, fingerprint := range keys {
+			if server.keyFingerprint(database, key) != fingerprint {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// touchWatchedKeys invalidates the transactions watching any of the keys in the database.
+// This is called whenever a key is modified.
+func (server *SugarDB) touchWatchedKeys(database int, keys ...string) {
+	server.transactions.mut.Lock()
+	defer server.transactions.mut.Unlock()
+
+	for _, key := range keys {
+		for _, tx := range server.transactions.watched[database][key] {
+			tx.dirty.Store(true)
+		}
+	}
+}
+
+// touchWatchedDatabase invalidates all the transactions watching keys in the database.
+// When -1 is passed, the transactions watching keys in any database are invalidated.
+func (server *SugarDB) touchWatchedDatabase(database int) {
+	server.transactions.mut.Lock()
+	defer server.transactions.mut.Unlock()
+
+	for db, keys := range server.transactions.watched {
+		if database != -1 && db != database {
+			continue
+		}
+		for _, watchers := range keys {
+			for _, tx := range watchers {
+				tx.dirty.Store(true)
+			}
+		}
+	}
+}